@@ -0,0 +1,114 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService wraps a influxdb.BucketSchemaService and authorizes actions
+// against it appropriately. Measurement schemas are authorized against the
+// bucket they belong to.
+type BucketSchemaService struct {
+	s influxdb.BucketSchemaService
+}
+
+// NewBucketSchemaService constructs an instance of an authorizing bucket schema service.
+func NewBucketSchemaService(s influxdb.BucketSchemaService) *BucketSchemaService {
+	return &BucketSchemaService{
+		s: s,
+	}
+}
+
+// FindMeasurementSchemaByID checks to see if the authorizer on context has read access to the bucket of the schema.
+func (s *BucketSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindMeasurementSchemas retrieves all measurement schemas that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ms, _, err := s.s.FindMeasurementSchemas(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	schemas := ms[:0]
+	for _, m := range ms {
+		err := authorizeReadBucket(ctx, m.OrgID, m.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		schemas = append(schemas, m)
+	}
+
+	return schemas, len(schemas), nil
+}
+
+// CreateMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateMeasurementSchema(ctx, m)
+}
+
+// UpdateMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+}
+
+// DeleteMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+func (s *BucketSchemaService) DeleteMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteMeasurementSchema(ctx, bucketID, id)
+}
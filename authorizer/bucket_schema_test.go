@@ -0,0 +1,174 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBucketSchemaService_FindMeasurementSchemas(t *testing.T) {
+	type fields struct {
+		BucketSchemaService influxdb.BucketSchemaService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err     error
+		schemas []*influxdb.MeasurementSchema
+	}
+
+	schemaService := &mock.BucketSchemaService{
+		FindMeasurementSchemasFn: func(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+			return []*influxdb.MeasurementSchema{
+				{ID: 1, OrgID: 10, BucketID: 1, Name: "cpu"},
+				{ID: 2, OrgID: 10, BucketID: 2, Name: "mem"},
+			}, 2, nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "authorized to see all schemas",
+			fields: fields{BucketSchemaService: schemaService},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				schemas: []*influxdb.MeasurementSchema{
+					{ID: 1, OrgID: 10, BucketID: 1, Name: "cpu"},
+					{ID: 2, OrgID: 10, BucketID: 2, Name: "mem"},
+				},
+			},
+		},
+		{
+			name:   "authorized to access a single bucket",
+			fields: fields{BucketSchemaService: schemaService},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(2),
+					},
+				},
+			},
+			wants: wants{
+				schemas: []*influxdb.MeasurementSchema{
+					{ID: 2, OrgID: 10, BucketID: 2, Name: "mem"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBucketSchemaService(tt.fields.BucketSchemaService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			schemas, _, err := s.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(schemas, tt.wants.schemas); diff != "" {
+				t.Errorf("measurement schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestBucketSchemaService_DeleteMeasurementSchema(t *testing.T) {
+	type fields struct {
+		BucketSchemaService influxdb.BucketSchemaService
+	}
+	type args struct {
+		permission influxdb.Permission
+		id         influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	schemaService := &mock.BucketSchemaService{
+		FindMeasurementSchemaByIDFn: func(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+			return &influxdb.MeasurementSchema{ID: id, OrgID: 10, BucketID: bucketID}, nil
+		},
+		DeleteMeasurementSchemaFn: func(ctx context.Context, bucketID, id influxdb.ID) error {
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "authorized to delete schema",
+			fields: fields{BucketSchemaService: schemaService},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				id: 5,
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name:   "unauthorized to delete schema",
+			fields: fields{BucketSchemaService: schemaService},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				id: 5,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBucketSchemaService(tt.fields.BucketSchemaService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			err := s.DeleteMeasurementSchema(ctx, 1, tt.args.id)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
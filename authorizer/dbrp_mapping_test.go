@@ -0,0 +1,123 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDBRPMappingService_FindMany(t *testing.T) {
+	dbrpService := mock.NewDBRPMappingService()
+	dbrpService.FindManyFn = func(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+		return []*influxdb.DBRPMapping{
+			{Database: "db0", OrganizationID: 10, BucketID: 20},
+			{Database: "db1", OrganizationID: 10, BucketID: 21},
+		}, 2, nil
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		mappings   []*influxdb.DBRPMapping
+	}{
+		{
+			name: "authorized to read all buckets",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			mappings: []*influxdb.DBRPMapping{
+				{Database: "db0", OrganizationID: 10, BucketID: 20},
+				{Database: "db1", OrganizationID: 10, BucketID: 21},
+			},
+		},
+		{
+			name: "authorized to read a bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(21),
+				},
+			},
+			mappings: []*influxdb.DBRPMapping{
+				{Database: "db1", OrganizationID: 10, BucketID: 21},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(dbrpService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			ms, _, err := s.FindMany(ctx, influxdb.DBRPMappingFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(ms, tt.mappings); diff != "" {
+				t.Errorf("mappings are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestDBRPMappingService_Create(t *testing.T) {
+	dbrpService := mock.NewDBRPMappingService()
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to write the bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(20),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write the bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(20),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(dbrpService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.Create(ctx, &influxdb.DBRPMapping{Database: "db0", OrganizationID: 10, BucketID: 20})
+			if got := influxdb.ErrorCode(err) == influxdb.EUnauthorized; got != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
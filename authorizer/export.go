@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.ExportService = (*ExportService)(nil)
+
+// ExportService wraps a influxdb.ExportService and authorizes actions
+// against it appropriately.
+type ExportService struct {
+	s influxdb.ExportService
+}
+
+// NewExportService constructs an instance of an authorizing export service.
+func NewExportService(s influxdb.ExportService) *ExportService {
+	return &ExportService{
+		s: s,
+	}
+}
+
+// ExportLineProtocol checks to see if the authorizer on context has read access to the bucket the points are exported from.
+func (s ExportService) ExportLineProtocol(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, req.OrgID, req.BucketID); err != nil {
+		return err
+	}
+	return s.s.ExportLineProtocol(ctx, req, w)
+}
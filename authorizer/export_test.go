@@ -0,0 +1,73 @@
+package authorizer_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestExportService_ExportLineProtocol(t *testing.T) {
+	exportService := mock.NewExportService()
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to read the bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to read another bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(2),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unauthorized to read a bucket of another org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewExportService(exportService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			req := influxdb.NewExportRequest(10, 1)
+			err := s.ExportLineProtocol(ctx, req, ioutil.Discard)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -396,6 +396,58 @@ func TestOrgService_UpdateOrganization(t *testing.T) {
 	}
 }
 
+func TestOrgService_UpdateOrganizationQueryQuotas(t *testing.T) {
+	orgService := &mock.OrganizationService{
+		UpdateOrganizationF: func(ctx context.Context, id influxdb.ID, upd influxdb.OrganizationUpdate) (*influxdb.Organization, error) {
+			return &influxdb.Organization{
+				ID:          1,
+				QueryQuotas: upd.QueryQuotas,
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to update quotas of all orgs",
+			permission: influxdb.Permission{
+				Action:   "write",
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType},
+			},
+		},
+		{
+			name: "unauthorized to update quotas of its own org",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewOrgService(orgService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.UpdateOrganization(ctx, 1, influxdb.OrganizationUpdate{
+				QueryQuotas: &influxdb.OrgQueryQuotas{ConcurrencyQuota: 100},
+			})
+			if got := influxdb.ErrorCode(err) == influxdb.EUnauthorized; got != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 func TestOrgService_DeleteOrganization(t *testing.T) {
 	type fields struct {
 		OrgService influxdb.OrganizationService
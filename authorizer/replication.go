@@ -0,0 +1,154 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.ReplicationService = (*ReplicationService)(nil)
+
+// ReplicationService wraps a influxdb.ReplicationService and authorizes actions
+// against it appropriately. Creating a replication also requires read access
+// to its local bucket, since the points written to it are sent to the remote.
+type ReplicationService struct {
+	s influxdb.ReplicationService
+}
+
+// NewReplicationService constructs an instance of an authorizing replication service.
+func NewReplicationService(s influxdb.ReplicationService) *ReplicationService {
+	return &ReplicationService{
+		s: s,
+	}
+}
+
+func newReplicationPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.ReplicationsResourceType, orgID)
+}
+
+func authorizeReadReplication(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newReplicationPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteReplication(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newReplicationPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindReplicationByID checks to see if the authorizer on context has read access to the id provided.
+func (s *ReplicationService) FindReplicationByID(ctx context.Context, id influxdb.ID) (*influxdb.Replication, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindReplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadReplication(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindReplications retrieves all replications that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *ReplicationService) FindReplications(ctx context.Context, filter influxdb.ReplicationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Replication, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rs, _, err := s.s.FindReplications(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	replications := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadReplication(ctx, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		replications = append(replications, r)
+	}
+
+	return replications, len(replications), nil
+}
+
+// CreateReplication checks to see if the authorizer on context has write access to replications
+// and read access to the local bucket of the replication.
+func (s *ReplicationService) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.ReplicationsResourceType, r.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if err := authorizeReadBucket(ctx, r.OrgID, r.LocalBucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateReplication(ctx, r)
+}
+
+// UpdateReplication checks to see if the authorizer on context has write access to the replication provided.
+func (s *ReplicationService) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindReplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteReplication(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateReplication(ctx, id, upd)
+}
+
+// DeleteReplication checks to see if the authorizer on context has write access to the replication provided.
+func (s *ReplicationService) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindReplicationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteReplication(ctx, r.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteReplication(ctx, id)
+}
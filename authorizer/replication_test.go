@@ -0,0 +1,131 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestReplicationService_FindReplications(t *testing.T) {
+	replicationService := &mock.ReplicationService{
+		FindReplicationsFn: func(ctx context.Context, filter influxdb.ReplicationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Replication, int, error) {
+			return []*influxdb.Replication{
+				{ID: 1, OrgID: 10, Name: "edge"},
+				{ID: 2, OrgID: 11, Name: "backup"},
+			}, 2, nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		permission   influxdb.Permission
+		replications []*influxdb.Replication
+	}{
+		{
+			name: "authorized to see all replications",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.ReplicationsResourceType},
+			},
+			replications: []*influxdb.Replication{
+				{ID: 1, OrgID: 10, Name: "edge"},
+				{ID: 2, OrgID: 11, Name: "backup"},
+			},
+		},
+		{
+			name: "authorized to see replications of an org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.ReplicationsResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			replications: []*influxdb.Replication{
+				{ID: 2, OrgID: 11, Name: "backup"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewReplicationService(replicationService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			rs, _, err := s.FindReplications(ctx, influxdb.ReplicationFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(rs, tt.replications); diff != "" {
+				t.Errorf("replications are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestReplicationService_CreateReplication(t *testing.T) {
+	replicationService := &mock.ReplicationService{
+		CreateReplicationFn: func(ctx context.Context, r *influxdb.Replication) error {
+			return nil
+		},
+	}
+
+	replicationsWrite := influxdb.Permission{
+		Action: influxdb.WriteAction,
+		Resource: influxdb.Resource{
+			Type:  influxdb.ReplicationsResourceType,
+			OrgID: influxdbtesting.IDPtr(10),
+		},
+	}
+	bucketRead := influxdb.Permission{
+		Action: influxdb.ReadAction,
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(10),
+			ID:    influxdbtesting.IDPtr(20),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name:        "authorized to create replications of the bucket",
+			permissions: []influxdb.Permission{replicationsWrite, bucketRead},
+		},
+		{
+			name:        "unauthorized to read the local bucket",
+			permissions: []influxdb.Permission{replicationsWrite},
+			wantErr:     true,
+		},
+		{
+			name:        "unauthorized to create replications",
+			permissions: []influxdb.Permission{bucketRead},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewReplicationService(replicationService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.CreateReplication(ctx, &influxdb.Replication{OrgID: 10, LocalBucketID: 20})
+			if got := influxdb.ErrorCode(err) == influxdb.EUnauthorized; got != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+
+// RestoreService wraps a influxdb.RestoreService and authorizes actions
+// against it appropriately.
+type RestoreService struct {
+	s influxdb.RestoreService
+}
+
+// NewRestoreService constructs an instance of an authorizing restore service.
+func NewRestoreService(s influxdb.RestoreService) *RestoreService {
+	return &RestoreService{
+		s: s,
+	}
+}
+
+// RestoreBucket checks to see if the authorizer on context has write access to the bucket the data is restored into.
+func (r RestoreService) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, req.OrgID, req.BucketID); err != nil {
+		return 0, err
+	}
+	return r.s.RestoreBucket(ctx, req, tsm, tombstone)
+}
+
+// RestoreEngine checks to see if the authorizer on context has write access to all data and meta data.
+func (r RestoreService) RestoreEngine(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := IsAllowedAll(ctx, influxdb.OperPermissions()); err != nil {
+		return err
+	}
+	return r.s.RestoreEngine(ctx, chain, files)
+}
+
+var _ influxdb.KVRestoreService = (*KVRestoreService)(nil)
+
+// KVRestoreService wraps a influxdb.KVRestoreService and authorizes actions
+// against it appropriately.
+type KVRestoreService struct {
+	s influxdb.KVRestoreService
+}
+
+// NewKVRestoreService constructs an instance of an authorizing meta data restore service.
+func NewKVRestoreService(s influxdb.KVRestoreService) *KVRestoreService {
+	return &KVRestoreService{
+		s: s,
+	}
+}
+
+// RestoreKVStore checks to see if the authorizer on context has write access to all data and meta data.
+func (r KVRestoreService) RestoreKVStore(ctx context.Context, chain []*influxdb.BackupManifest, kv io.Reader, changes []io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := IsAllowedAll(ctx, influxdb.OperPermissions()); err != nil {
+		return err
+	}
+	return r.s.RestoreKVStore(ctx, chain, kv, changes)
+}
@@ -0,0 +1,110 @@
+package authorizer_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRestoreService_RestoreBucket(t *testing.T) {
+	restoreService := mock.NewRestoreService()
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to write to the bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to restore with read access",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unauthorized to restore into a bucket of another org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRestoreService(restoreService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			req := influxdb.RestoreBucketRequest{SourceOrgID: 2, SourceBucketID: 3, OrgID: 10, BucketID: 1}
+			_, err := s.RestoreBucket(ctx, req, nil, nil)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRestoreService_RestoreEngine(t *testing.T) {
+	restoreService := mock.NewRestoreService()
+	kvRestoreService := mock.NewKVRestoreService()
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name:        "authorized with all permissions",
+			permissions: influxdb.OperPermissions(),
+		},
+		{
+			name:        "unauthorized with read all permissions",
+			permissions: influxdb.ReadAllPermissions(),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := authorizer.NewRestoreService(restoreService).RestoreEngine(ctx, nil, nil)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("unexpected engine restore error: %v", err)
+			}
+
+			err = authorizer.NewKVRestoreService(kvRestoreService).RestoreKVStore(ctx, nil, nil, []io.Reader{})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("unexpected meta data restore error: %v", err)
+			}
+		})
+	}
+}
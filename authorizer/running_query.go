@@ -0,0 +1,100 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+// RunningQueryService wraps a influxdb.RunningQueryService and authorizes actions
+// against it appropriately. Running queries are authorized by the organization
+// they were requested in.
+type RunningQueryService struct {
+	s influxdb.RunningQueryService
+}
+
+// NewRunningQueryService constructs an instance of an authorizing running query service.
+func NewRunningQueryService(s influxdb.RunningQueryService) *RunningQueryService {
+	return &RunningQueryService{
+		s: s,
+	}
+}
+
+func authorizeRunningQuery(ctx context.Context, a influxdb.Action, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermission(a, influxdb.QueriesResourceType, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRunningQueryByID checks to see if the authorizer on context has read access to the queries of the
+// organization of the query.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	q, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRunningQuery(ctx, influxdb.ReadAction, q.OrgID); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// FindRunningQueries retrieves all running queries that match the provided filter and then filters the list down to only the queries that are authorized.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	qs, err := s.s.FindRunningQueries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := qs[:0]
+	for _, q := range qs {
+		err := authorizeRunningQuery(ctx, influxdb.ReadAction, q.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		queries = append(queries, q)
+	}
+
+	return queries, nil
+}
+
+// CancelRunningQuery checks to see if the authorizer on context has write access to the queries of the
+// organization of the query.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	q, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRunningQuery(ctx, influxdb.WriteAction, q.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.CancelRunningQuery(ctx, id)
+}
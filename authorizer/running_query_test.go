@@ -0,0 +1,142 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRunningQueryService_FindRunningQueries(t *testing.T) {
+	runningQueryService := &mock.RunningQueryService{
+		FindRunningQueriesFn: func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+			return []*influxdb.RunningQuery{
+				{ID: 1, OrgID: 10},
+				{ID: 2, OrgID: 11},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		queries    []*influxdb.RunningQuery
+	}{
+		{
+			name: "authorized to see all queries",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.QueriesResourceType},
+			},
+			queries: []*influxdb.RunningQuery{
+				{ID: 1, OrgID: 10},
+				{ID: 2, OrgID: 11},
+			},
+		},
+		{
+			name: "authorized to see queries of an org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.QueriesResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			queries: []*influxdb.RunningQuery{
+				{ID: 2, OrgID: 11},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRunningQueryService(runningQueryService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			qs, err := s.FindRunningQueries(ctx, influxdb.RunningQueryFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(qs, tt.queries); diff != "" {
+				t.Errorf("queries are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestRunningQueryService_CancelRunningQuery(t *testing.T) {
+	var canceled bool
+	runningQueryService := &mock.RunningQueryService{
+		FindRunningQueryByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+			return &influxdb.RunningQuery{ID: id, OrgID: 10}, nil
+		},
+		CancelRunningQueryFn: func(ctx context.Context, id influxdb.ID) error {
+			canceled = true
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to cancel queries of the org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.QueriesResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "unauthorized to cancel queries of another org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.QueriesResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unauthorized to cancel queries it can read",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.QueriesResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canceled = false
+			s := authorizer.NewRunningQueryService(runningQueryService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CancelRunningQuery(ctx, 1)
+			if got := influxdb.ErrorCode(err) == influxdb.EUnauthorized; got != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if canceled == tt.wantErr {
+				t.Errorf("unexpected cancellation: %t", canceled)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.ScriptService = (*ScriptService)(nil)
+
+// ScriptService wraps a influxdb.ScriptService and authorizes actions
+// against it appropriately. Invoking a script only requires read access
+// to it, the query of the script is authorized as the invoker's query.
+type ScriptService struct {
+	s influxdb.ScriptService
+}
+
+// NewScriptService constructs an instance of an authorizing script service.
+func NewScriptService(s influxdb.ScriptService) *ScriptService {
+	return &ScriptService{
+		s: s,
+	}
+}
+
+func newScriptPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.ScriptsResourceType, orgID)
+}
+
+func authorizeReadScript(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newScriptPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteScript(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newScriptPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindScriptByID checks to see if the authorizer on context has read access to the id provided.
+func (s *ScriptService) FindScriptByID(ctx context.Context, id influxdb.ID) (*influxdb.Script, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sc, err := s.s.FindScriptByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadScript(ctx, sc.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// FindScripts retrieves all scripts that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *ScriptService) FindScripts(ctx context.Context, filter influxdb.ScriptFilter, opt ...influxdb.FindOptions) ([]*influxdb.Script, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ss, _, err := s.s.FindScripts(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	scripts := ss[:0]
+	for _, sc := range ss {
+		err := authorizeReadScript(ctx, sc.OrgID, sc.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		scripts = append(scripts, sc)
+	}
+
+	return scripts, len(scripts), nil
+}
+
+// CreateScript checks to see if the authorizer on context has write access to scripts in the org of the script.
+func (s *ScriptService) CreateScript(ctx context.Context, sc *influxdb.Script) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.ScriptsResourceType, sc.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateScript(ctx, sc)
+}
+
+// UpdateScript checks to see if the authorizer on context has write access to the script provided.
+func (s *ScriptService) UpdateScript(ctx context.Context, id influxdb.ID, upd influxdb.ScriptUpdate) (*influxdb.Script, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sc, err := s.s.FindScriptByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteScript(ctx, sc.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateScript(ctx, id, upd)
+}
+
+// DeleteScript checks to see if the authorizer on context has write access to the script provided.
+func (s *ScriptService) DeleteScript(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sc, err := s.s.FindScriptByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteScript(ctx, sc.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteScript(ctx, id)
+}
@@ -0,0 +1,138 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestScriptService_FindScripts(t *testing.T) {
+	scriptService := &mock.ScriptService{
+		FindScriptsFn: func(ctx context.Context, filter influxdb.ScriptFilter, opt ...influxdb.FindOptions) ([]*influxdb.Script, int, error) {
+			return []*influxdb.Script{
+				{ID: 1, OrgID: 10, Name: "cpu"},
+				{ID: 2, OrgID: 11, Name: "mem"},
+			}, 2, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		scripts    []*influxdb.Script
+	}{
+		{
+			name: "authorized to see all scripts",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.ScriptsResourceType},
+			},
+			scripts: []*influxdb.Script{
+				{ID: 1, OrgID: 10, Name: "cpu"},
+				{ID: 2, OrgID: 11, Name: "mem"},
+			},
+		},
+		{
+			name: "authorized to see a single script",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.ScriptsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+			scripts: []*influxdb.Script{
+				{ID: 1, OrgID: 10, Name: "cpu"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewScriptService(scriptService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			ss, _, err := s.FindScripts(ctx, influxdb.ScriptFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(ss, tt.scripts); diff != "" {
+				t.Errorf("scripts are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestScriptService_UpdateScript(t *testing.T) {
+	scriptService := &mock.ScriptService{
+		FindScriptByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Script, error) {
+			return &influxdb.Script{ID: id, OrgID: 10}, nil
+		},
+		UpdateScriptFn: func(ctx context.Context, id influxdb.ID, upd influxdb.ScriptUpdate) (*influxdb.Script, error) {
+			return &influxdb.Script{ID: id, OrgID: 10}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to update the script",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.ScriptsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to update the script with read access",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.ScriptsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unauthorized to update scripts of another org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.ScriptsResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewScriptService(scriptService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.UpdateScript(ctx, 1, influxdb.ScriptUpdate{})
+			if got := influxdb.ErrorCode(err) == influxdb.EUnauthorized; got != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
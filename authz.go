@@ -129,6 +129,12 @@ const (
 	NotificationEndpointResourceType = ResourceType("notificationEndpoints") // 15
 	// ChecksResourceType gives permission to one or more Checks.
 	ChecksResourceType = ResourceType("checks") // 16
+	// ReplicationsResourceType gives permission to one or more replications.
+	ReplicationsResourceType = ResourceType("replications") // 17
+	// QueriesResourceType gives permission to list and cancel running queries.
+	QueriesResourceType = ResourceType("queries") // 18
+	// ScriptsResourceType gives permission to one or more scripts.
+	ScriptsResourceType = ResourceType("scripts") // 19
 )
 
 // AllResourceTypes is the list of all known resource types.
@@ -150,6 +156,9 @@ var AllResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	ReplicationsResourceType,         // 17
+	QueriesResourceType,              // 18
+	ScriptsResourceType,              // 19
 	// NOTE: when modifying this list, please update the swagger for components.schemas.Permission resource enum.
 }
 
@@ -167,6 +176,9 @@ var OrgResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	ReplicationsResourceType,         // 17
+	QueriesResourceType,              // 18
+	ScriptsResourceType,              // 19
 }
 
 // Valid checks if the resource type is a member of the ResourceType enum.
@@ -194,6 +206,9 @@ func (t ResourceType) Valid() (err error) {
 	case NotificationRuleResourceType: // 14
 	case NotificationEndpointResourceType: // 15
 	case ChecksResourceType: // 16
+	case ReplicationsResourceType: // 17
+	case QueriesResourceType: // 18
+	case ScriptsResourceType: // 19
 	default:
 		err = ErrInvalidResourceType
 	}
@@ -3,6 +3,15 @@ package influxdb
 import (
 	"context"
 	"io"
+	"time"
+)
+
+const (
+	// BackupManifestFilename is the name of the manifest file of a backup.
+	BackupManifestFilename = "manifest.json"
+	// BackupKVChangesFilename is the name of the file with the meta data
+	// changes of an incremental backup.
+	BackupKVChangesFilename = "kv.changes"
 )
 
 // BackupService represents the data backup functions of InfluxDB.
@@ -20,4 +29,110 @@ type BackupService interface {
 type KVBackupService interface {
 	// Backup creates a live backup copy of the metadata database.
 	Backup(ctx context.Context, w io.Writer) error
+	// ChangeSequence returns the sequence of the last change made to the metadata.
+	ChangeSequence(ctx context.Context) (uint64, error)
+	// BackupChanges writes the changes made to the metadata after the change
+	// sequence since to w, and returns the sequence of the last change written.
+	BackupChanges(ctx context.Context, since uint64, w io.Writer) (uint64, error)
+	// TruncateChanges discards the changes up to the change sequence seq,
+	// once they are part of a full backup of the metadata.
+	TruncateChanges(ctx context.Context, seq uint64) error
+}
+
+// BackupManifest describes a backup, and chains an incremental backup to the
+// backup it was taken from.
+type BackupManifest struct {
+	ID ID `json:"id"`
+	// ParentID is the backup an incremental backup only carries the changes of.
+	// It is not set for a full backup.
+	ParentID *ID `json:"parentID,omitempty"`
+	// KVSequence is the sequence of the last change to the metadata included in the backup.
+	KVSequence uint64    `json:"kvSequence"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// Files are all of the TSM and tombstone files of the engine at the time
+	// of the backup, including the files that are part of the parent backups.
+	Files []BackupFile `json:"files"`
+	// KV is the meta data file of the backup: the bolt file of a full backup,
+	// or the meta data changes of an incremental backup.
+	KV *BackupFile `json:"kv,omitempty"`
+	// Credentials is the credentials file of the server at the time of the backup.
+	Credentials *BackupFile `json:"credentials,omitempty"`
+	// EngineVersion is the version of influxd which took the backup.
+	EngineVersion string `json:"engineVersion,omitempty"`
+	// Buckets are all of the buckets at the time of the backup.
+	Buckets []BackupBucket `json:"buckets,omitempty"`
+
+	// Compression and Encryption are how the files of the backup are encoded
+	// where the backup is stored. The manifest itself is never encoded, and
+	// the sizes and checksums of the files are those of the decoded files.
+	Compression BackupCompression `json:"compression,omitempty"`
+	Encryption  BackupEncryption  `json:"encryption,omitempty"`
+	// Checksum is the hex encoded SHA-256 checksum of the manifest, which
+	// detects a corrupt manifest but not one which was changed on purpose.
+	// See SetChecksum.
+	Checksum string `json:"checksum,omitempty"`
+	// Signature is the hex encoded HMAC-SHA256 of the manifest keyed with the
+	// encryption key of an encrypted backup. See Sign.
+	Signature string `json:"signature,omitempty"`
+}
+
+// Incremental returns true if the backup only carries the changes made
+// since its parent backup.
+func (m *BackupManifest) Incremental() bool {
+	return m.ParentID != nil
+}
+
+// BackupFile is a data file of the engine at the time of a backup.
+type BackupFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// Included is true if the file is part of this backup, rather than
+	// of a backup earlier in the chain.
+	Included bool `json:"included"`
+	// SHA256 is the hex encoded SHA-256 checksum of the file.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// BackupBucket is a bucket at the time of a backup, so that the data of the
+// bucket can be found in the backup after the bucket is renamed or deleted.
+type BackupBucket struct {
+	OrgID    ID     `json:"orgID"`
+	Org      string `json:"org"`
+	BucketID ID     `json:"bucketID"`
+	Bucket   string `json:"bucket"`
+}
+
+// BackupCompression is the compression of the files of a backup.
+type BackupCompression string
+
+// Compressions of the files of a backup.
+const (
+	NoBackupCompression   BackupCompression = ""
+	GzipBackupCompression BackupCompression = "gzip"
+	ZstdBackupCompression BackupCompression = "zstd"
+)
+
+// BackupEncryption is the encryption of the files of a backup.
+type BackupEncryption string
+
+// Encryptions of the files of a backup.
+const (
+	NoBackupEncryption     BackupEncryption = ""
+	AESGCMBackupEncryption BackupEncryption = "aes-gcm"
+)
+
+// ops for backup manifest and restore errors.
+var (
+	OpFindBackupManifestByID = "FindBackupManifestByID"
+	OpCreateBackupManifest   = "CreateBackupManifest"
+	OpRestoreKVStore         = "RestoreKVStore"
+)
+
+// BackupManifestService stores the manifests of backups, so that incremental
+// backups can be taken from them.
+type BackupManifestService interface {
+	// FindBackupManifestByID returns the manifest of the backup.
+	FindBackupManifestByID(ctx context.Context, id ID) (*BackupManifest, error)
+	// CreateBackupManifest stores the manifest of a backup and sets its ID.
+	CreateBackupManifest(ctx context.Context, m *BackupManifest) error
 }
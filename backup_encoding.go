@@ -0,0 +1,462 @@
+package influxdb
+
+import (
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/multierr"
+)
+
+// backupChunkSize is the size of the chunks the files of an encrypted backup
+// are sealed in.
+const backupChunkSize = 64 << 10
+
+// ParseBackupKey decodes the hex encoded encryption key of a backup, which is
+// a 16, 24 or 32 byte AES key.
+func ParseBackupKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "backup encryption key must be hex encoded",
+			Err:  err,
+		}
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("backup encryption key must be 16, 24 or 32 bytes, got %d bytes", len(key)),
+		}
+	}
+}
+
+// SetChecksum sets the checksum of the manifest.
+func (m *BackupManifest) SetChecksum() error {
+	sum, err := m.sum(sha256.New())
+	if err != nil {
+		return err
+	}
+	m.Checksum = sum
+	return nil
+}
+
+// VerifyChecksum returns an error unless the checksum of the manifest
+// matches its content.
+func (m *BackupManifest) VerifyChecksum() error {
+	if m.Checksum == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("backup %s has no checksum", m.ID),
+		}
+	}
+	sum, err := m.sum(sha256.New())
+	if err != nil {
+		return err
+	}
+	if sum != m.Checksum {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("checksum of backup %s does not match its manifest", m.ID),
+		}
+	}
+	return nil
+}
+
+// Sign sets the signature of the manifest to its HMAC-SHA256 keyed with key,
+// so that the manifest can not be changed without the key.
+func (m *BackupManifest) Sign(key []byte) error {
+	if len(key) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("a key is required to sign backup %s", m.ID),
+		}
+	}
+	sig, err := m.sum(hmac.New(sha256.New, key))
+	if err != nil {
+		return err
+	}
+	m.Signature = sig
+	return nil
+}
+
+// Verify returns an error unless the checksum of the manifest matches its
+// content. The manifest of an encrypted backup, or of any backup verified
+// with a key, must also be signed with key.
+func (m *BackupManifest) Verify(key []byte) error {
+	if err := m.VerifyChecksum(); err != nil {
+		return err
+	}
+	if m.Encryption == NoBackupEncryption && m.Signature == "" && len(key) == 0 {
+		return nil
+	}
+	if len(key) == 0 {
+		if m.Encryption != NoBackupEncryption {
+			return errBackupKeyRequired(m)
+		}
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("backup %s is signed; the key it was signed with is required", m.ID),
+		}
+	}
+	if m.Signature == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("backup %s is not signed", m.ID),
+		}
+	}
+	sig, err := m.sum(hmac.New(sha256.New, key))
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(sig), []byte(m.Signature)) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("signature of backup %s does not match its manifest", m.ID),
+		}
+	}
+	return nil
+}
+
+// sum returns the hex encoded sum of the manifest without its checksum
+// and signature.
+func (m *BackupManifest) sum(h hash.Hash) (string, error) {
+	content := *m
+	content.Checksum = ""
+	content.Signature = ""
+	octets, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	h.Write(octets)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func errBackupKeyRequired(m *BackupManifest) error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("backup %s is encrypted; the encryption key is required", m.ID),
+	}
+}
+
+// NewBackupFileEncoder returns a writer which compresses and encrypts the
+// files of the backup of m as m describes, before writing them to w. The
+// writer must be closed to write the end of the file.
+func NewBackupFileEncoder(w io.Writer, m *BackupManifest, key []byte) (io.WriteCloser, error) {
+	var wc io.WriteCloser = nopWriteCloser{w}
+
+	switch m.Encryption {
+	case NoBackupEncryption:
+	case AESGCMBackupEncryption:
+		aead, err := newBackupAEAD(m, key)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(nonce); err != nil {
+			return nil, err
+		}
+		wc = &backupSealWriter{w: w, aead: aead, nonce: nonce}
+	default:
+		return nil, errBackupEncryption(m)
+	}
+
+	switch m.Compression {
+	case NoBackupCompression:
+		return wc, nil
+	case GzipBackupCompression:
+		return &stackedWriteCloser{WriteCloser: gzip.NewWriter(wc), next: wc}, nil
+	case ZstdBackupCompression:
+		zw, err := zstd.NewWriter(wc)
+		if err != nil {
+			return nil, err
+		}
+		return &stackedWriteCloser{WriteCloser: zw, next: wc}, nil
+	default:
+		return nil, errBackupCompression(m)
+	}
+}
+
+// NewBackupFileDecoder returns a reader of the files of the backup of m read
+// from r, which decrypts and decompresses them as m describes.
+func NewBackupFileDecoder(r io.Reader, m *BackupManifest, key []byte) (io.ReadCloser, error) {
+	switch m.Encryption {
+	case NoBackupEncryption:
+	case AESGCMBackupEncryption:
+		aead, err := newBackupAEAD(m, key)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(r, nonce); err != nil {
+			return nil, errBackupFileCorrupt(m, err)
+		}
+		r = &backupOpenReader{r: r, m: m, aead: aead, nonce: nonce}
+	default:
+		return nil, errBackupEncryption(m)
+	}
+
+	switch m.Compression {
+	case NoBackupCompression:
+		return ioutil.NopCloser(r), nil
+	case GzipBackupCompression:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errBackupFileCorrupt(m, err)
+		}
+		return gr, nil
+	case ZstdBackupCompression:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, errBackupFileCorrupt(m, err)
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, errBackupCompression(m)
+	}
+}
+
+// OpenBackupFile opens the file f of the backup of m stored in the directory
+// dir. The file is decoded as m describes, and the reader returns an error
+// once it is read to the end if the file differs from f.
+func OpenBackupFile(dir string, m *BackupManifest, f BackupFile, key []byte) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(dir, f.Name))
+	if err != nil {
+		return nil, err
+	}
+	dec, err := NewBackupFileDecoder(file, m, key)
+	if err != nil {
+		return nil, multierr.Append(err, file.Close())
+	}
+	return &backupFileReadCloser{
+		Reader:  NewBackupFileReader(dec, f),
+		closers: []io.Closer{dec, file},
+	}, nil
+}
+
+type backupFileReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *backupFileReadCloser) Close() error {
+	var err error
+	for _, c := range r.closers {
+		err = multierr.Append(err, c.Close())
+	}
+	return err
+}
+
+// VerifyBackupFiles reads all of the files included in the backup of m stored
+// in the directory dir, and returns an error if any of them differs from m.
+func VerifyBackupFiles(dir string, m *BackupManifest, key []byte) error {
+	var files []BackupFile
+	for _, f := range []*BackupFile{m.KV, m.Credentials} {
+		if f != nil {
+			files = append(files, *f)
+		}
+	}
+	for _, f := range m.Files {
+		if f.Included {
+			files = append(files, f)
+		}
+	}
+
+	for _, f := range files {
+		r, err := OpenBackupFile(dir, m, f, key)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(ioutil.Discard, r)
+		if err = multierr.Append(err, r.Close()); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("failed to verify file %s of backup %s", f.Name, m.ID),
+				Err:  err,
+			}
+		}
+	}
+	return nil
+}
+
+func newBackupAEAD(m *BackupManifest, key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, errBackupKeyRequired(m)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "invalid backup encryption key",
+			Err:  err,
+		}
+	}
+	return cipher.NewGCM(block)
+}
+
+func errBackupEncryption(m *BackupManifest) error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("unsupported encryption %q of backup %s", m.Encryption, m.ID),
+	}
+}
+
+func errBackupCompression(m *BackupManifest) error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("unsupported compression %q of backup %s", m.Compression, m.ID),
+	}
+}
+
+func errBackupFileCorrupt(m *BackupManifest, err error) error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("file of backup %s is corrupt", m.ID),
+		Err:  err,
+	}
+}
+
+// The files of an encrypted backup start with a random nonce, followed by
+// chunks of at most backupChunkSize bytes, sealed with the nonce XORed with
+// the sequence of the chunk. Every chunk has a one byte header, which is 1 for
+// the last chunk and sealed with it, so that a truncated file is detected,
+// followed by the four byte length of the sealed chunk.
+
+type backupSealWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	nonce []byte
+	seq   uint64
+	buf   []byte
+}
+
+func (w *backupSealWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	// the last chunk is only sealed on close, so a full chunk is kept.
+	for len(w.buf) > backupChunkSize {
+		if err := w.seal(w.buf[:backupChunkSize], false); err != nil {
+			return 0, err
+		}
+		w.buf = append(w.buf[:0], w.buf[backupChunkSize:]...)
+	}
+	return len(p), nil
+}
+
+func (w *backupSealWriter) Close() error {
+	return w.seal(w.buf, true)
+}
+
+func (w *backupSealWriter) seal(p []byte, last bool) error {
+	hdr := make([]byte, 5)
+	if last {
+		hdr[0] = 1
+	}
+	sealed := w.aead.Seal(nil, backupChunkNonce(w.nonce, w.seq), p, hdr[:1])
+	w.seq++
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(sealed)))
+	if _, err := w.w.Write(hdr); err != nil {
+		return err
+	}
+	_, err := w.w.Write(sealed)
+	return err
+}
+
+type backupOpenReader struct {
+	r     io.Reader
+	m     *BackupManifest
+	aead  cipher.AEAD
+	nonce []byte
+	seq   uint64
+	buf   []byte
+	last  bool
+}
+
+func (r *backupOpenReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.last {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *backupOpenReader) open() error {
+	hdr := make([]byte, 5)
+	if _, err := io.ReadFull(r.r, hdr); err != nil {
+		return errBackupFileCorrupt(r.m, err)
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > uint32(backupChunkSize+r.aead.Overhead()) {
+		return errBackupFileCorrupt(r.m, fmt.Errorf("chunk of %d bytes exceeds the chunk size", n))
+	}
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(r.r, sealed); err != nil {
+		return errBackupFileCorrupt(r.m, err)
+	}
+	p, err := r.aead.Open(sealed[:0], backupChunkNonce(r.nonce, r.seq), sealed, hdr[:1])
+	if err != nil {
+		return errBackupFileCorrupt(r.m, err)
+	}
+	r.seq++
+	r.buf = p
+	r.last = hdr[0] == 1
+	if r.last {
+		if n, _ := r.r.Read(make([]byte, 1)); n > 0 {
+			return errBackupFileCorrupt(r.m, fmt.Errorf("data after the last chunk"))
+		}
+	}
+	return nil
+}
+
+func backupChunkNonce(nonce []byte, seq uint64) []byte {
+	n := make([]byte, len(nonce))
+	copy(n, nonce)
+	tail := n[len(n)-8:]
+	binary.BigEndian.PutUint64(tail, binary.BigEndian.Uint64(tail)^seq)
+	return n
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// stackedWriteCloser closes the writer it writes to once it is closed.
+type stackedWriteCloser struct {
+	io.WriteCloser
+	next io.Closer
+}
+
+func (w *stackedWriteCloser) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		w.next.Close()
+		return err
+	}
+	return w.next.Close()
+}
@@ -0,0 +1,168 @@
+package influxdb_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestBackupFileEncoder(t *testing.T) {
+	key, err := influxdb.ParseBackupKey("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// larger than a few chunks of an encrypted file.
+	data := make([]byte, 200<<10+17)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name        string
+		compression influxdb.BackupCompression
+		encryption  influxdb.BackupEncryption
+	}{
+		{name: "raw"},
+		{name: "gzip", compression: influxdb.GzipBackupCompression},
+		{name: "aes-gcm", encryption: influxdb.AESGCMBackupEncryption},
+		{name: "gzip and aes-gcm", compression: influxdb.GzipBackupCompression, encryption: influxdb.AESGCMBackupEncryption},
+		{name: "zstd", compression: influxdb.ZstdBackupCompression},
+		{name: "zstd and aes-gcm", compression: influxdb.ZstdBackupCompression, encryption: influxdb.AESGCMBackupEncryption},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &influxdb.BackupManifest{Compression: tt.compression, Encryption: tt.encryption}
+
+			var buf bytes.Buffer
+			w, err := influxdb.NewBackupFileEncoder(&buf, m, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write(data); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := influxdb.NewBackupFileDecoder(bytes.NewReader(buf.Bytes()), m, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatalf("decoded %d bytes differ from the %d bytes encoded", len(got), len(data))
+			}
+
+			if tt.encryption == influxdb.NoBackupEncryption {
+				return
+			}
+
+			if _, err := influxdb.NewBackupFileDecoder(bytes.NewReader(buf.Bytes()), m, nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected invalid error without the key, got %v", err)
+			}
+
+			corrupt := append([]byte(nil), buf.Bytes()...)
+			corrupt[len(corrupt)/2] ^= 0xff
+			if err := decodeAll(m, key, corrupt); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected invalid error for a corrupt file, got %v", err)
+			}
+
+			// a file truncated at the end of a chunk is not mistaken for a whole file.
+			truncated := buf.Bytes()[:12+5+64<<10+16]
+			if err := decodeAll(m, key, truncated); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected invalid error for a truncated file, got %v", err)
+			}
+		})
+	}
+}
+
+func decodeAll(m *influxdb.BackupManifest, key, data []byte) error {
+	r, err := influxdb.NewBackupFileDecoder(bytes.NewReader(data), m, key)
+	if err != nil {
+		return err
+	}
+	_, err = ioutil.ReadAll(r)
+	return err
+}
+
+func TestBackupManifest_Sign(t *testing.T) {
+	key := []byte("0123456789abcdef")
+
+	t.Run("encrypted backups are signed with the key", func(t *testing.T) {
+		m := &influxdb.BackupManifest{
+			ID:         1,
+			Files:      []influxdb.BackupFile{{Name: "000000001-000000001.tsm", Size: 3, SHA256: "abc", Included: true}},
+			Encryption: influxdb.AESGCMBackupEncryption,
+		}
+		if err := m.SetChecksum(); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(key); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error for an unsigned manifest, got %v", err)
+		}
+		if err := m.Sign(key); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(key); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify([]byte("fedcba9876543210")); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error with another key, got %v", err)
+		}
+		if err := m.Verify(nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error without the key, got %v", err)
+		}
+
+		// the checksum of a changed manifest can be set again, its signature can not.
+		m.Files[0].SHA256 = "def"
+		if err := m.SetChecksum(); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(key); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error for a changed manifest, got %v", err)
+		}
+
+		// the signature is not stripped from a manifest without the key either.
+		m.Encryption = influxdb.NoBackupEncryption
+		if err := m.SetChecksum(); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error for a signed manifest without the key, got %v", err)
+		}
+	})
+
+	t.Run("a key is required to sign", func(t *testing.T) {
+		m := &influxdb.BackupManifest{ID: 1}
+		if err := m.Sign(nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error without a key, got %v", err)
+		}
+	})
+
+	t.Run("other backups have a checksum", func(t *testing.T) {
+		m := &influxdb.BackupManifest{ID: 1, Compression: influxdb.GzipBackupCompression}
+		if err := m.Verify(nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error for a manifest without a checksum, got %v", err)
+		}
+		if err := m.SetChecksum(); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(key); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error for an unsigned manifest verified with a key, got %v", err)
+		}
+		m.Compression = influxdb.NoBackupCompression
+		if err := m.Verify(nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error for a changed manifest, got %v", err)
+		}
+	})
+}
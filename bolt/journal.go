@@ -0,0 +1,293 @@
+package bolt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+// The change journal records every put and delete made through the KVStore,
+// in the same transaction as the change itself, under an increasing change
+// sequence. It allows a backup to only carry the changes made since an
+// earlier backup, which are replayed on top of a full backup when restoring.
+var (
+	journalBucket     = []byte("kvjournalv1")
+	journalMetaBucket = []byte("kvjournalmetav1")
+
+	// journalTruncatedKey holds the sequence up to which the journal has
+	// been truncated. Changes up to that sequence are only part of full backups.
+	journalTruncatedKey = []byte("truncated")
+)
+
+const (
+	journalOpPut    byte = 1
+	journalOpDelete byte = 2
+)
+
+// ErrJournalTruncated is returned when the changes requested from the
+// journal have been truncated by a full backup.
+var ErrJournalTruncated = &influxdb.Error{
+	Code: influxdb.EConflict,
+	Msg:  "changes since the backup are no longer journaled, as a full backup has been taken since; take an incremental backup from the latest full backup",
+}
+
+// journalEntry is a single change of the journal.
+type journalEntry struct {
+	op     byte
+	bucket []byte
+	key    []byte
+	value  []byte
+}
+
+func (e journalEntry) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 1+2*binary.MaxVarintLen64+len(e.bucket)+len(e.key)+len(e.value))
+	buf = append(buf, e.op)
+	buf = appendUvarintBytes(buf, e.bucket)
+	buf = appendUvarintBytes(buf, e.key)
+	return append(buf, e.value...), nil
+}
+
+func (e *journalEntry) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return errors.New("empty journal entry")
+	}
+	e.op, b = b[0], b[1:]
+	if e.op != journalOpPut && e.op != journalOpDelete {
+		return fmt.Errorf("unknown journal operation %d", e.op)
+	}
+
+	var err error
+	if e.bucket, b, err = readUvarintBytes(b); err != nil {
+		return err
+	}
+	if e.key, b, err = readUvarintBytes(b); err != nil {
+		return err
+	}
+	e.value = b
+	return nil
+}
+
+func appendUvarintBytes(dst, b []byte) []byte {
+	var n [binary.MaxVarintLen64]byte
+	dst = append(dst, n[:binary.PutUvarint(n[:], uint64(len(b)))]...)
+	return append(dst, b...)
+}
+
+func readUvarintBytes(b []byte) ([]byte, []byte, error) {
+	n, sz := binary.Uvarint(b)
+	if sz <= 0 || uint64(len(b)-sz) < n {
+		return nil, nil, errors.New("malformed journal entry")
+	}
+	b = b[sz:]
+	return b[:n], b[n:], nil
+}
+
+func encodeSequence(seq uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], seq)
+	return b[:]
+}
+
+// appendJournal records the change e in the journal of tx.
+func appendJournal(tx *bolt.Tx, e journalEntry) error {
+	b, err := tx.CreateBucketIfNotExists(journalBucket)
+	if err != nil {
+		return err
+	}
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	v, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return b.Put(encodeSequence(seq), v)
+}
+
+// journalSequence returns the sequence of the last change of the journal of tx.
+func journalSequence(tx *bolt.Tx) uint64 {
+	b := tx.Bucket(journalBucket)
+	if b == nil {
+		return 0
+	}
+	return b.Sequence()
+}
+
+func journalTruncated(tx *bolt.Tx) uint64 {
+	b := tx.Bucket(journalMetaBucket)
+	if b == nil {
+		return 0
+	}
+	v := b.Get(journalTruncatedKey)
+	if len(v) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+// ChangeSequence returns the sequence of the last change made to the store.
+func (s *KVStore) ChangeSequence(ctx context.Context) (uint64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var seq uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		seq = journalSequence(tx)
+		return nil
+	})
+	return seq, err
+}
+
+// BackupChanges writes the changes made after the change sequence since to w,
+// and returns the sequence of the last change written. Each change is written
+// as its 8 byte sequence, the uvarint length of the change and the change.
+func (s *KVStore) BackupChanges(ctx context.Context, since uint64, w io.Writer) (uint64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	last := since
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if since < journalTruncated(tx) {
+			return ErrJournalTruncated
+		}
+
+		b := tx.Bucket(journalBucket)
+		if b == nil {
+			return nil
+		}
+
+		bw := bufio.NewWriter(w)
+		var n [binary.MaxVarintLen64]byte
+		c := b.Cursor()
+		for k, v := c.Seek(encodeSequence(since + 1)); k != nil; k, v = c.Next() {
+			if _, err := bw.Write(k); err != nil {
+				return err
+			}
+			if _, err := bw.Write(n[:binary.PutUvarint(n[:], uint64(len(v)))]); err != nil {
+				return err
+			}
+			if _, err := bw.Write(v); err != nil {
+				return err
+			}
+			last = binary.BigEndian.Uint64(k)
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return last, nil
+}
+
+// TruncateChanges removes the changes up to and including the change
+// sequence seq from the journal. It is called once a full backup including
+// those changes has been taken.
+func (s *KVStore) TruncateChanges(ctx context.Context, seq uint64) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if seq <= journalTruncated(tx) {
+			return nil
+		}
+
+		if b := tx.Bucket(journalBucket); b != nil {
+			// keys are collected first, as deleting under a cursor skips keys.
+			var keys [][]byte
+			c := b.Cursor()
+			for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) <= seq; k, _ = c.Next() {
+				keys = append(keys, k)
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(journalMetaBucket)
+		if err != nil {
+			return err
+		}
+		return meta.Put(journalTruncatedKey, encodeSequence(seq))
+	})
+}
+
+// ReplayChanges applies the changes written by BackupChanges to the store.
+// Changes that are already part of the store are skipped, so the changes of
+// a chain of backups can be replayed in order on top of a full backup.
+func (s *KVStore) ReplayChanges(ctx context.Context, r io.Reader) (int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	br := bufio.NewReader(r)
+	var replayed int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		journal, err := tx.CreateBucketIfNotExists(journalBucket)
+		if err != nil {
+			return err
+		}
+
+		var k [8]byte
+		for {
+			if _, err := io.ReadFull(br, k[:]); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to read journal: %v", err)
+			}
+			n, err := binary.ReadUvarint(br)
+			if err != nil {
+				return fmt.Errorf("failed to read journal: %v", err)
+			}
+			v := make([]byte, n)
+			if _, err := io.ReadFull(br, v); err != nil {
+				return fmt.Errorf("failed to read journal: %v", err)
+			}
+
+			seq := binary.BigEndian.Uint64(k[:])
+			if cur := journal.Sequence(); seq <= cur {
+				continue
+			} else if seq != cur+1 {
+				return fmt.Errorf("journal is missing changes %d through %d", cur+1, seq-1)
+			}
+
+			var e journalEntry
+			if err := e.UnmarshalBinary(v); err != nil {
+				return err
+			}
+			b, err := tx.CreateBucketIfNotExists(e.bucket)
+			if err != nil {
+				return err
+			}
+			switch e.op {
+			case journalOpPut:
+				err = b.Put(e.key, e.value)
+			case journalOpDelete:
+				err = b.Delete(e.key)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := journal.SetSequence(seq); err != nil {
+				return err
+			}
+			if err := journal.Put(encodeSequence(seq), v); err != nil {
+				return err
+			}
+			replayed++
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return replayed, nil
+}
@@ -0,0 +1,138 @@
+package bolt_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestKVStore_Changes(t *testing.T) {
+	ctx := context.Background()
+	s, closeFn, err := NewTestKVStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	bucket := []byte("b")
+	update := func(s *bolt.KVStore, fn func(b kv.Bucket) error) {
+		t.Helper()
+		err := s.Update(ctx, func(tx kv.Tx) error {
+			b, err := tx.Bucket(bucket)
+			if err != nil {
+				return err
+			}
+			return fn(b)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(s *bolt.KVStore, key string) string {
+		t.Helper()
+		var v []byte
+		err := s.View(ctx, func(tx kv.Tx) error {
+			b, err := tx.Bucket(bucket)
+			if err != nil {
+				return err
+			}
+			v, err = b.Get([]byte(key))
+			if kv.IsNotFound(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(v)
+	}
+
+	update(s, func(b kv.Bucket) error {
+		return b.Put([]byte("a"), []byte("1"))
+	})
+
+	seq, err := s.ChangeSequence(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := ioutil.TempFile("", "influxdata-platform-bolt-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(full.Name())
+	if err := s.Backup(ctx, full); err != nil {
+		t.Fatal(err)
+	}
+	full.Close()
+
+	update(s, func(b kv.Bucket) error {
+		if err := b.Put([]byte("b"), []byte("2")); err != nil {
+			return err
+		}
+		return b.Delete([]byte("a"))
+	})
+
+	var changes bytes.Buffer
+	last, err := s.BackupChanges(ctx, seq, &changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := last, seq+2; got != want {
+		t.Fatalf("unexpected last change: got %d want %d", got, want)
+	}
+
+	restored := bolt.NewKVStore(zaptest.NewLogger(t), full.Name())
+	if err := restored.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	n, err := restored.ReplayChanges(ctx, bytes.NewReader(changes.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("unexpected replayed changes: got %d want 2", n)
+	}
+	if got := get(restored, "a"); got != "" {
+		t.Errorf("expected deleted key to be restored as deleted, got %q", got)
+	}
+	if got := get(restored, "b"); got != "2" {
+		t.Errorf("unexpected restored value: got %q want %q", got, "2")
+	}
+
+	// replaying the same changes again skips them.
+	n, err = restored.ReplayChanges(ctx, bytes.NewReader(changes.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("expected no changes to be replayed again, got %d", n)
+	}
+	if got, err := restored.ChangeSequence(ctx); err != nil || got != last {
+		t.Errorf("unexpected restored change sequence: got %d want %d (%v)", got, last, err)
+	}
+
+	if err := s.TruncateChanges(ctx, last); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.BackupChanges(ctx, seq, &bytes.Buffer{}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected truncated changes to conflict, got %v", err)
+	}
+
+	var empty bytes.Buffer
+	if _, err := s.BackupChanges(ctx, last, &empty); err != nil {
+		t.Fatal(err)
+	}
+	if empty.Len() != 0 {
+		t.Errorf("expected no changes since the last change, got %d bytes", empty.Len())
+	}
+}
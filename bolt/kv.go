@@ -160,6 +160,8 @@ func (tx *Tx) createBucketIfNotExists(b []byte) (*Bucket, error) {
 	}
 	return &Bucket{
 		bucket: bkt,
+		name:   b,
+		tx:     tx.tx,
 	}, nil
 }
 
@@ -171,12 +173,16 @@ func (tx *Tx) Bucket(b []byte) (kv.Bucket, error) {
 	}
 	return &Bucket{
 		bucket: bkt,
+		name:   b,
+		tx:     tx.tx,
 	}, nil
 }
 
 // Bucket implements kv.Bucket.
 type Bucket struct {
 	bucket *bolt.Bucket
+	name   []byte
+	tx     *bolt.Tx
 }
 
 // Get retrieves the value at the provided key.
@@ -195,7 +201,10 @@ func (b *Bucket) Put(key []byte, value []byte) error {
 	if err == bolt.ErrTxNotWritable {
 		return kv.ErrTxNotWritable
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return appendJournal(b.tx, journalEntry{op: journalOpPut, bucket: b.name, key: key, value: value})
 }
 
 // Delete removes the provided key.
@@ -204,7 +213,10 @@ func (b *Bucket) Delete(key []byte) error {
 	if err == bolt.ErrTxNotWritable {
 		return kv.ErrTxNotWritable
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return appendJournal(b.tx, journalEntry{op: journalOpDelete, bucket: b.name, key: key})
 }
 
 // ForwardCursor retrieves a cursor for iterating through the entries
@@ -0,0 +1,108 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// Restore replaces all of the data of the store with the data of the bolt
+// file read from r, with the journaled changes read from changes replayed
+// on top of it, in order.
+//
+// The bolt file is restored next to the file of the store, and its data is
+// copied into the store in a single transaction, so the store is never left
+// partially restored and stays open while it is restored.
+func (s *KVStore) Restore(ctx context.Context, r io.Reader, changes ...io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	path := s.path + ".restore"
+	if err := copyRestoreFile(path, r); err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return fmt.Errorf("unable to open restored boltdb file %v", err)
+	}
+	defer db.Close()
+
+	restored := &KVStore{path: path, db: db, log: s.log}
+	for _, c := range changes {
+		n, err := restored.ReplayChanges(ctx, c)
+		if err != nil {
+			return err
+		}
+		s.log.Info("Replayed meta data changes", zap.Int("changes", n))
+	}
+
+	// the data of the restored file must stay valid until the store commits.
+	err = db.View(func(src *bolt.Tx) error {
+		return s.db.Update(func(dst *bolt.Tx) error {
+			var names [][]byte
+			if err := dst.ForEach(func(name []byte, _ *bolt.Bucket) error {
+				names = append(names, name)
+				return nil
+			}); err != nil {
+				return err
+			}
+			for _, name := range names {
+				if err := dst.DeleteBucket(name); err != nil {
+					return err
+				}
+			}
+
+			return src.ForEach(func(name []byte, b *bolt.Bucket) error {
+				bkt, err := dst.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(bkt, b)
+			})
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("Resources restored", zap.String("path", s.path))
+	return nil
+}
+
+// copyBucket copies the keys and the nested buckets of src to dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		nested, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(nested, src.Bucket(k))
+	})
+}
+
+func copyRestoreFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,82 @@
+package bolt_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestKVStore_Restore(t *testing.T) {
+	ctx := context.Background()
+	s, closeFn, err := NewTestKVStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	bucket := []byte("b")
+	put := func(key, value string) {
+		t.Helper()
+		err := s.Update(ctx, func(tx kv.Tx) error {
+			b, err := tx.Bucket(bucket)
+			if err != nil {
+				return err
+			}
+			return b.Put([]byte(key), []byte(value))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(key string) string {
+		t.Helper()
+		var v []byte
+		err := s.View(ctx, func(tx kv.Tx) error {
+			b, err := tx.Bucket(bucket)
+			if err != nil {
+				return err
+			}
+			v, err = b.Get([]byte(key))
+			if kv.IsNotFound(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(v)
+	}
+
+	put("a", "1")
+	seq, err := s.ChangeSequence(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var full bytes.Buffer
+	if err := s.Backup(ctx, &full); err != nil {
+		t.Fatal(err)
+	}
+
+	put("b", "2")
+	var changes bytes.Buffer
+	if _, err := s.BackupChanges(ctx, seq, &changes); err != nil {
+		t.Fatal(err)
+	}
+
+	// the data written after the last backup is discarded by the restore.
+	put("a", "3")
+	put("c", "4")
+
+	if err := s.Restore(ctx, &full, &changes); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{"a": "1", "b": "2", "c": ""} {
+		if got := get(key); got != want {
+			t.Errorf("unexpected restored value of %q: got %q want %q", key, got, want)
+		}
+	}
+}
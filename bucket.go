@@ -12,6 +12,8 @@ const (
 	TasksSystemBucketID = ID(10)
 	// MonitoringSystemBucketID is the fixed ID for our monitoring system bucket
 	MonitoringSystemBucketID = ID(11)
+	// QueriesSystemBucketID is the fixed ID for our queries system bucket
+	QueriesSystemBucketID = ID(12)
 
 	// BucketTypeUser is a user created bucket
 	BucketTypeUser = BucketType(0)
@@ -21,12 +23,15 @@ const (
 	MonitoringSystemBucketRetention = time.Hour * 24 * 7
 	// TasksSystemBucketRetention is the time we should retain task system bucket information
 	TasksSystemBucketRetention = time.Hour * 24 * 3
+	// QueriesSystemBucketRetention is the time we should retain query system bucket information
+	QueriesSystemBucketRetention = time.Hour * 24 * 7
 )
 
 // Bucket names constants
 const (
 	TasksSystemBucketName      = "_tasks"
 	MonitoringSystemBucketName = "_monitoring"
+	QueriesSystemBucketName    = "_queries"
 )
 
 // InfiniteRetention is default infinite retention period.
@@ -41,6 +46,7 @@ type Bucket struct {
 	Description         string        `json:"description"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	SchemaType          SchemaType    `json:"schemaType,omitempty"`
 	CRUDLog
 }
 
@@ -98,6 +104,12 @@ type BucketService interface {
 	FindBucketByName(ctx context.Context, orgID ID, name string) (*Bucket, error)
 }
 
+// BucketCardinalityService returns the number of series stored in buckets.
+type BucketCardinalityService interface {
+	// BucketSeriesCardinality returns the number of series in a bucket.
+	BucketSeriesCardinality(ctx context.Context, orgID, bucketID ID) (int64, error)
+}
+
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
@@ -0,0 +1,252 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaType differentiates the supported schema for a bucket.
+type SchemaType int
+
+const (
+	// SchemaTypeImplicit indicates the schema of a bucket is defined by the data written to it.
+	SchemaTypeImplicit SchemaType = iota
+	// SchemaTypeExplicit indicates points written to a bucket must conform to its measurement schemas.
+	SchemaTypeExplicit
+)
+
+// String converts a SchemaType into a human-readable string.
+func (st SchemaType) String() string {
+	if st == SchemaTypeExplicit {
+		return "explicit"
+	}
+	return "implicit"
+}
+
+// ParseSchemaType parses a schema type from a string.
+func ParseSchemaType(s string) (SchemaType, error) {
+	switch s {
+	case "", "implicit":
+		return SchemaTypeImplicit, nil
+	case "explicit":
+		return SchemaTypeExplicit, nil
+	default:
+		return 0, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid schema type %q; must be implicit or explicit", s),
+		}
+	}
+}
+
+// MarshalJSON encodes the schema type as a string.
+func (st SchemaType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(st.String())
+}
+
+// UnmarshalJSON decodes the schema type from a string.
+func (st *SchemaType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseSchemaType(s)
+	if err != nil {
+		return err
+	}
+	*st = v
+	return nil
+}
+
+// SemanticColumnType describes the role of a column in a measurement schema.
+type SemanticColumnType string
+
+// Semantic column types of a measurement schema.
+const (
+	SemanticColumnTypeTimestamp SemanticColumnType = "timestamp"
+	SemanticColumnTypeTag       SemanticColumnType = "tag"
+	SemanticColumnTypeField     SemanticColumnType = "field"
+)
+
+// SchemaColumnDataType is the data type of a field column in a measurement schema.
+type SchemaColumnDataType string
+
+// Data types of field columns.
+const (
+	SchemaColumnDataTypeFloat    SchemaColumnDataType = "float"
+	SchemaColumnDataTypeInteger  SchemaColumnDataType = "integer"
+	SchemaColumnDataTypeUnsigned SchemaColumnDataType = "unsigned"
+	SchemaColumnDataTypeString   SchemaColumnDataType = "string"
+	SchemaColumnDataTypeBoolean  SchemaColumnDataType = "boolean"
+)
+
+// Valid reports whether the data type is one of the supported field types.
+func (t SchemaColumnDataType) Valid() bool {
+	switch t {
+	case SchemaColumnDataTypeFloat, SchemaColumnDataTypeInteger, SchemaColumnDataTypeUnsigned,
+		SchemaColumnDataTypeString, SchemaColumnDataTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// MeasurementSchemaTimeColumn is the name of the timestamp column of every measurement schema.
+const MeasurementSchemaTimeColumn = "time"
+
+// MeasurementSchema declares the tag keys and typed fields of a measurement
+// in a bucket with an explicit schema.
+type MeasurementSchema struct {
+	ID       ID                        `json:"id,omitempty"`
+	OrgID    ID                        `json:"orgID"`
+	BucketID ID                        `json:"bucketID"`
+	Name     string                    `json:"name"`
+	Columns  []MeasurementSchemaColumn `json:"columns"`
+	CRUDLog
+}
+
+// MeasurementSchemaColumn is a column of a measurement schema. Field columns
+// must declare a data type; timestamp and tag columns must not.
+type MeasurementSchemaColumn struct {
+	Name     string               `json:"name"`
+	Type     SemanticColumnType   `json:"type"`
+	DataType SchemaColumnDataType `json:"dataType,omitempty"`
+}
+
+// Validate reports any validation errors for the measurement schema.
+func (m *MeasurementSchema) Validate() error {
+	if m.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurement schema name is required",
+		}
+	}
+	if strings.HasPrefix(m.Name, "_") {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("measurement schema name %q is invalid; names may not start with an underscore", m.Name),
+		}
+	}
+	return validateSchemaColumns(m.Columns)
+}
+
+func validateSchemaColumns(columns []MeasurementSchemaColumn) error {
+	invalid := func(format string, args ...interface{}) error {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf(format, args...),
+		}
+	}
+
+	var timestamps, fields int
+	seen := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		if c.Name == "" {
+			return invalid("measurement schema column name is required")
+		}
+		if _, ok := seen[c.Name]; ok {
+			return invalid("measurement schema column %q is declared more than once", c.Name)
+		}
+		seen[c.Name] = struct{}{}
+
+		switch c.Type {
+		case SemanticColumnTypeTimestamp:
+			if c.Name != MeasurementSchemaTimeColumn {
+				return invalid("timestamp column must be named %q", MeasurementSchemaTimeColumn)
+			}
+			if c.DataType != "" {
+				return invalid("timestamp column %q may not declare a data type", c.Name)
+			}
+			timestamps++
+		case SemanticColumnTypeTag, SemanticColumnTypeField:
+			if c.Name == MeasurementSchemaTimeColumn || strings.HasPrefix(c.Name, "_") {
+				return invalid("measurement schema column name %q is reserved", c.Name)
+			}
+			if c.Type == SemanticColumnTypeTag {
+				if c.DataType != "" {
+					return invalid("tag column %q may not declare a data type", c.Name)
+				}
+				continue
+			}
+			if !c.DataType.Valid() {
+				return invalid("field column %q has invalid data type %q; must be float, integer, unsigned, string or boolean", c.Name, c.DataType)
+			}
+			fields++
+		default:
+			return invalid("measurement schema column %q has invalid type %q; must be timestamp, tag or field", c.Name, c.Type)
+		}
+	}
+
+	if timestamps != 1 {
+		return invalid("measurement schema requires a single timestamp column")
+	}
+	if fields == 0 {
+		return invalid("measurement schema requires at least one field column")
+	}
+	return nil
+}
+
+// MeasurementSchemaUpdate represents updates to a measurement schema.
+// Columns may be added to a schema, but existing columns may not be
+// removed or changed.
+type MeasurementSchemaUpdate struct {
+	Columns []MeasurementSchemaColumn `json:"columns"`
+}
+
+// Apply applies an update to a measurement schema.
+func (u MeasurementSchemaUpdate) Apply(m *MeasurementSchema) error {
+	if err := validateSchemaColumns(u.Columns); err != nil {
+		return err
+	}
+
+	updated := make(map[string]MeasurementSchemaColumn, len(u.Columns))
+	for _, c := range u.Columns {
+		updated[c.Name] = c
+	}
+	for _, c := range m.Columns {
+		if uc, ok := updated[c.Name]; !ok || uc != c {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement schema column %q may not be removed or changed", c.Name),
+			}
+		}
+	}
+
+	m.Columns = u.Columns
+	return nil
+}
+
+// MeasurementSchemaFilter represents a set of filters that restrict the returned measurement schemas.
+type MeasurementSchemaFilter struct {
+	BucketID ID
+	Name     *string
+}
+
+// ops for measurement schema errors.
+var (
+	OpFindMeasurementSchemaByID = "FindMeasurementSchemaByID"
+	OpFindMeasurementSchemas    = "FindMeasurementSchemas"
+	OpCreateMeasurementSchema   = "CreateMeasurementSchema"
+	OpUpdateMeasurementSchema   = "UpdateMeasurementSchema"
+	OpDeleteMeasurementSchema   = "DeleteMeasurementSchema"
+)
+
+// BucketSchemaService manages the measurement schemas of buckets with an explicit schema.
+type BucketSchemaService interface {
+	// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+	FindMeasurementSchemaByID(ctx context.Context, bucketID, id ID) (*MeasurementSchema, error)
+
+	// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+	FindMeasurementSchemas(ctx context.Context, filter MeasurementSchemaFilter, opt ...FindOptions) ([]*MeasurementSchema, int, error)
+
+	// CreateMeasurementSchema creates a new measurement schema and sets m.ID with the new identifier.
+	CreateMeasurementSchema(ctx context.Context, m *MeasurementSchema) error
+
+	// UpdateMeasurementSchema updates the columns of a measurement schema.
+	// Returns the new measurement schema after update.
+	UpdateMeasurementSchema(ctx context.Context, bucketID, id ID, upd MeasurementSchemaUpdate) (*MeasurementSchema, error)
+
+	// DeleteMeasurementSchema removes a measurement schema of a bucket by ID.
+	DeleteMeasurementSchema(ctx context.Context, bucketID, id ID) error
+}
@@ -0,0 +1,145 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestMeasurementSchema_Validate(t *testing.T) {
+	col := func(name string, typ influxdb.SemanticColumnType, dt influxdb.SchemaColumnDataType) influxdb.MeasurementSchemaColumn {
+		return influxdb.MeasurementSchemaColumn{Name: name, Type: typ, DataType: dt}
+	}
+	timeCol := col("time", influxdb.SemanticColumnTypeTimestamp, "")
+
+	tests := []struct {
+		name    string
+		schema  influxdb.MeasurementSchema
+		wantErr string
+	}{
+		{
+			name: "valid",
+			schema: influxdb.MeasurementSchema{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					timeCol,
+					col("host", influxdb.SemanticColumnTypeTag, ""),
+					col("usage", influxdb.SemanticColumnTypeField, influxdb.SchemaColumnDataTypeFloat),
+				},
+			},
+		},
+		{
+			name:    "missing name",
+			schema:  influxdb.MeasurementSchema{},
+			wantErr: "measurement schema name is required",
+		},
+		{
+			name: "missing timestamp",
+			schema: influxdb.MeasurementSchema{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					col("usage", influxdb.SemanticColumnTypeField, influxdb.SchemaColumnDataTypeFloat),
+				},
+			},
+			wantErr: "measurement schema requires a single timestamp column",
+		},
+		{
+			name: "missing fields",
+			schema: influxdb.MeasurementSchema{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					timeCol,
+					col("host", influxdb.SemanticColumnTypeTag, ""),
+				},
+			},
+			wantErr: "measurement schema requires at least one field column",
+		},
+		{
+			name: "field without data type",
+			schema: influxdb.MeasurementSchema{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					timeCol,
+					col("usage", influxdb.SemanticColumnTypeField, ""),
+				},
+			},
+			wantErr: `field column "usage" has invalid data type ""; must be float, integer, unsigned, string or boolean`,
+		},
+		{
+			name: "tag with data type",
+			schema: influxdb.MeasurementSchema{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					timeCol,
+					col("host", influxdb.SemanticColumnTypeTag, influxdb.SchemaColumnDataTypeString),
+					col("usage", influxdb.SemanticColumnTypeField, influxdb.SchemaColumnDataTypeFloat),
+				},
+			},
+			wantErr: `tag column "host" may not declare a data type`,
+		},
+		{
+			name: "duplicate column",
+			schema: influxdb.MeasurementSchema{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					timeCol,
+					col("usage", influxdb.SemanticColumnTypeTag, ""),
+					col("usage", influxdb.SemanticColumnTypeField, influxdb.SchemaColumnDataTypeFloat),
+				},
+			},
+			wantErr: `measurement schema column "usage" is declared more than once`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("unexpected error: got %v want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMeasurementSchemaUpdate_Apply(t *testing.T) {
+	schema := func() *influxdb.MeasurementSchema {
+		return &influxdb.MeasurementSchema{
+			Name: "cpu",
+			Columns: []influxdb.MeasurementSchemaColumn{
+				{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+				{Name: "usage", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+			},
+		}
+	}
+
+	t.Run("add column", func(t *testing.T) {
+		m := schema()
+		upd := influxdb.MeasurementSchemaUpdate{
+			Columns: append(schema().Columns, influxdb.MeasurementSchemaColumn{Name: "host", Type: influxdb.SemanticColumnTypeTag}),
+		}
+		if err := upd.Apply(m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(m.Columns) != 3 {
+			t.Fatalf("unexpected columns: %v", m.Columns)
+		}
+	})
+
+	t.Run("change column", func(t *testing.T) {
+		m := schema()
+		cols := schema().Columns
+		cols[1].DataType = influxdb.SchemaColumnDataTypeInteger
+		err := influxdb.MeasurementSchemaUpdate{Columns: cols}.Apply(m)
+		if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+			t.Fatalf("unexpected error code: got %q want %q", got, want)
+		}
+		if m.Columns[1].DataType != influxdb.SchemaColumnDataTypeFloat {
+			t.Fatalf("schema should not be modified by a failed update")
+		}
+	})
+}
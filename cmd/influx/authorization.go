@@ -61,6 +61,15 @@ var authCreateFlags struct {
 
 	writeNotificationEndpointPermission bool
 	readNotificationEndpointPermission  bool
+
+	writeReplicationsPermission bool
+	readReplicationsPermission  bool
+
+	writeQueriesPermission bool
+	readQueriesPermission  bool
+
+	writeScriptsPermission bool
+	readScriptsPermission  bool
 }
 
 func authCreateCmd() *cobra.Command {
@@ -103,6 +112,15 @@ func authCreateCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&authCreateFlags.writeCheckPermission, "write-checks", "", false, "Grants the permission to create checks")
 	cmd.Flags().BoolVarP(&authCreateFlags.readCheckPermission, "read-checks", "", false, "Grants the permission to read checks")
 
+	cmd.Flags().BoolVarP(&authCreateFlags.writeReplicationsPermission, "write-replications", "", false, "Grants the permission to create replications")
+	cmd.Flags().BoolVarP(&authCreateFlags.readReplicationsPermission, "read-replications", "", false, "Grants the permission to read replications")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeQueriesPermission, "write-queries", "", false, "Grants the permission to cancel running queries")
+	cmd.Flags().BoolVarP(&authCreateFlags.readQueriesPermission, "read-queries", "", false, "Grants the permission to list running queries")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeScriptsPermission, "write-scripts", "", false, "Grants the permission to create scripts")
+	cmd.Flags().BoolVarP(&authCreateFlags.readScriptsPermission, "read-scripts", "", false, "Grants the permission to read and invoke scripts")
+
 	return cmd
 }
 
@@ -180,6 +198,21 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 			writePerm:    authCreateFlags.writeOrganizationsPermission,
 			ResourceType: platform.OrgsResourceType,
 		},
+		{
+			readPerm:     authCreateFlags.readReplicationsPermission,
+			writePerm:    authCreateFlags.writeReplicationsPermission,
+			ResourceType: platform.ReplicationsResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readQueriesPermission,
+			writePerm:    authCreateFlags.writeQueriesPermission,
+			ResourceType: platform.QueriesResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readScriptsPermission,
+			writePerm:    authCreateFlags.writeScriptsPermission,
+			ResourceType: platform.ScriptsResourceType,
+		},
 		{
 			readPerm:     authCreateFlags.readTasksPermission,
 			writePerm:    authCreateFlags.writeTasksPermission,
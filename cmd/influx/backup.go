@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -22,8 +25,22 @@ func cmdBackup() *cobra.Command {
 			`Backs up data and meta data for the running InfluxDB instance.
 Downloaded files are written to the directory indicated by --path.
 The target directory, and any parent directories, are created automatically.
-Data file have extension .tsm; meta data is written to %s in the same directory.`,
-			bolt.DefaultFilename),
+Data file have extension .tsm; meta data is written to %s in the same directory.
+
+With --incremental-from, only the data files and the meta data changes since the
+given backup are downloaded, and the meta data changes are written to %s.
+The backup ID of every backup is part of its %s, and restoring an incremental
+backup requires the full backup and every incremental backup after it.
+
+The %s lists the files of the backup with their sizes and checksums, and
+has a SHA-256 checksum of its own, which detects a corrupt manifest. With
+--compression gzip or zstd, the other files are compressed with it.
+With --encryption-key-file, they are encrypted with AES-GCM using the hex encoded
+16, 24 or 32 byte key read from the file, and the manifest is signed with an
+HMAC-SHA256 keyed with the key, so that it can not be changed without the key.
+The key is required to restore the backup.`,
+			bolt.DefaultFilename, influxdb.BackupKVChangesFilename, influxdb.BackupManifestFilename,
+			influxdb.BackupManifestFilename),
 		RunE: backupF,
 	}
 	opts := flagOpts{
@@ -35,6 +52,21 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 			Desc:     "directory path to write backup files to",
 			Required: true,
 		},
+		{
+			DestP: &backupFlags.IncrementalFrom,
+			Flag:  "incremental-from",
+			Desc:  "ID of the backup to only download the changes since",
+		},
+		{
+			DestP: &backupFlags.Compression,
+			Flag:  "compression",
+			Desc:  "compression of the backup files, none, gzip or zstd",
+		},
+		{
+			DestP: &backupFlags.EncryptionKeyFile,
+			Flag:  "encryption-key-file",
+			Desc:  "path to the file with the hex encoded key to encrypt the backup files with",
+		},
 	}
 	opts.mustRegister(cmd)
 
@@ -42,7 +74,10 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 }
 
 var backupFlags struct {
-	Path string
+	Path              string
+	IncrementalFrom   string
+	Compression       string
+	EncryptionKeyFile string
 }
 
 func init() {
@@ -55,7 +90,7 @@ func init() {
 	}
 }
 
-func newBackupService() (influxdb.BackupService, error) {
+func newBackupService() (*http.BackupService, error) {
 	return &http.BackupService{
 		Addr:  flags.host,
 		Token: flags.token,
@@ -73,7 +108,25 @@ func backupF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("must specify path")
 	}
 
-	err := os.MkdirAll(backupFlags.Path, 0777)
+	// the files are encoded as they are downloaded, and the manifest
+	// downloaded with them is updated to describe how.
+	encoding := &influxdb.BackupManifest{}
+	switch c := influxdb.BackupCompression(backupFlags.Compression); c {
+	case influxdb.GzipBackupCompression, influxdb.ZstdBackupCompression:
+		encoding.Compression = c
+	case "none", influxdb.NoBackupCompression:
+	default:
+		return fmt.Errorf("unsupported compression %q", backupFlags.Compression)
+	}
+	key, err := readBackupKey(backupFlags.EncryptionKeyFile)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		encoding.Encryption = influxdb.AESGCMBackupEncryption
+	}
+
+	err = os.MkdirAll(backupFlags.Path, 0777)
 	if err != nil && !os.IsExist(err) {
 		return err
 	}
@@ -83,29 +136,126 @@ func backupF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	id, backupFilenames, err := backupService.CreateBackup(ctx)
-	if err != nil {
-		return err
+	var (
+		id              int
+		backupFilenames []string
+	)
+	if backupFlags.IncrementalFrom != "" {
+		parentID, err := influxdb.IDFromString(backupFlags.IncrementalFrom)
+		if err != nil {
+			return fmt.Errorf("invalid backup ID to take an incremental backup from: %v", err)
+		}
+		id, backupFilenames, err = backupService.CreateIncrementalBackup(ctx, *parentID)
+		if err != nil {
+			return err
+		}
+	} else {
+		id, backupFilenames, err = backupService.CreateBackup(ctx)
+		if err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Backup ID %d contains %d files\n", id, len(backupFilenames))
 
+	var manifest *influxdb.BackupManifest
 	for _, backupFilename := range backupFilenames {
+		if backupFilename == influxdb.BackupManifestFilename {
+			var buf bytes.Buffer
+			if err := backupService.FetchBackupFile(ctx, id, backupFilename, &buf); err != nil {
+				return fmt.Errorf("error fetching file %s: %v", backupFilename, err)
+			}
+			manifest = &influxdb.BackupManifest{}
+			if err := json.Unmarshal(buf.Bytes(), manifest); err != nil {
+				return fmt.Errorf("failed to decode backup manifest: %v", err)
+			}
+			continue
+		}
+
 		dest := filepath.Join(backupFlags.Path, backupFilename)
-		w, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
-		if err != nil {
+		if err := fetchBackupFile(ctx, backupService, id, backupFilename, dest, encoding, key); err != nil {
 			return err
 		}
-		err = backupService.FetchBackupFile(ctx, id, backupFilename, w)
-		if err != nil {
-			return multierr.Append(fmt.Errorf("error fetching file %s: %v", backupFilename, err), w.Close())
-		}
-		if err = w.Close(); err != nil {
+	}
+
+	if manifest == nil {
+		fmt.Printf("Backup complete")
+		return nil
+	}
+
+	if err := manifest.VerifyChecksum(); err != nil {
+		return err
+	}
+	manifest.Compression = encoding.Compression
+	manifest.Encryption = encoding.Encryption
+	if err := manifest.SetChecksum(); err != nil {
+		return err
+	}
+	if key != nil {
+		if err := manifest.Sign(key); err != nil {
 			return err
 		}
 	}
+	octets, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(backupFlags.Path, influxdb.BackupManifestFilename), octets, 0666); err != nil {
+		return err
+	}
 
-	fmt.Printf("Backup complete")
-
+	fmt.Printf("Backup %s complete\n", manifest.ID)
 	return nil
 }
+
+// fetchBackupFile downloads a file of a backup to dest, encoded as encoding
+// describes.
+func fetchBackupFile(ctx context.Context, s *http.BackupService, id int, name, dest string, encoding *influxdb.BackupManifest, key []byte) error {
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	w, err := influxdb.NewBackupFileEncoder(f, encoding, key)
+	if err != nil {
+		return multierr.Append(err, f.Close())
+	}
+	if err := s.FetchBackupFile(ctx, id, name, w); err != nil {
+		return multierr.Append(fmt.Errorf("error fetching file %s: %v", name, err), f.Close())
+	}
+	if err := w.Close(); err != nil {
+		return multierr.Append(err, f.Close())
+	}
+	return f.Close()
+}
+
+// readBackupKey reads the encryption key of a backup from the file at path,
+// which is nil if path is empty.
+func readBackupKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	octets, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup encryption key: %v", err)
+	}
+	return influxdb.ParseBackupKey(string(octets))
+}
+
+// readBackupManifest reads the manifest of the backup at path, which is nil
+// for a backup taken by a server which did not write manifests.
+func readBackupManifest(path string) (*influxdb.BackupManifest, error) {
+	f, err := os.Open(filepath.Join(path, influxdb.BackupManifestFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var m influxdb.BackupManifest
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode backup manifest: %v", err)
+	}
+	return &m, nil
+}
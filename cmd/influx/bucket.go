@@ -14,6 +14,8 @@ import (
 
 type bucketSVCsFn func() (influxdb.BucketService, influxdb.OrganizationService, error)
 
+type bucketSchemaSVCFn func() (influxdb.BucketSchemaService, error)
+
 func cmdBucket(opts ...genericCLIOptFn) *cobra.Command {
 	return newCmdBucketBuilder(newBucketSVCs, opts...).cmd()
 }
@@ -21,7 +23,8 @@ func cmdBucket(opts ...genericCLIOptFn) *cobra.Command {
 type cmdBucketBuilder struct {
 	genericCLIOpts
 
-	svcFn bucketSVCsFn
+	svcFn       bucketSVCsFn
+	schemaSVCFn bucketSchemaSVCFn
 
 	id          string
 	headers     bool
@@ -29,6 +32,9 @@ type cmdBucketBuilder struct {
 	description string
 	org         organization
 	retention   time.Duration
+	schemaType  string
+	bucketID    string
+	columnsFile string
 }
 
 func newCmdBucketBuilder(svcsFn bucketSVCsFn, opts ...genericCLIOptFn) *cmdBucketBuilder {
@@ -43,6 +49,7 @@ func newCmdBucketBuilder(svcsFn bucketSVCsFn, opts ...genericCLIOptFn) *cmdBucke
 	return &cmdBucketBuilder{
 		genericCLIOpts: opt,
 		svcFn:          svcsFn,
+		schemaSVCFn:    newBucketSchemaSVC,
 	}
 }
 
@@ -55,6 +62,7 @@ func (b *cmdBucketBuilder) cmd() *cobra.Command {
 		b.cmdCreate(),
 		b.cmdDelete(),
 		b.cmdFind(),
+		b.cmdSchema(),
 		b.cmdUpdate(),
 	)
 
@@ -79,6 +87,7 @@ func (b *cmdBucketBuilder) cmdCreate() *cobra.Command {
 
 	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of bucket that will be created")
 	cmd.Flags().DurationVarP(&b.retention, "retention", "r", 0, "Duration in nanoseconds data will live in bucket")
+	cmd.Flags().StringVar(&b.schemaType, "schema-type", "implicit", "The schema type of the bucket (implicit, explicit)")
 	b.org.register(cmd, false)
 
 	return cmd
@@ -89,6 +98,11 @@ func (b *cmdBucketBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
 		return err
 	}
 
+	schemaType, err := influxdb.ParseSchemaType(b.schemaType)
+	if err != nil {
+		return err
+	}
+
 	bktSVC, orgSVC, err := b.svcFn()
 	if err != nil {
 		return err
@@ -98,6 +112,7 @@ func (b *cmdBucketBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
 		Name:            b.name,
 		Description:     b.description,
 		RetentionPeriod: b.retention,
+		SchemaType:      schemaType,
 	}
 	bkt.OrgID, err = b.org.getID(orgSVC)
 	if err != nil {
@@ -109,12 +124,13 @@ func (b *cmdBucketBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
 	}
 
 	w := internal.NewTabWriter(b.w)
-	w.WriteHeaders("ID", "Name", "Retention", "OrganizationID")
+	w.WriteHeaders("ID", "Name", "Retention", "OrganizationID", "SchemaType")
 	w.Write(map[string]interface{}{
 		"ID":             bkt.ID.String(),
 		"Name":           bkt.Name,
 		"Retention":      bkt.RetentionPeriod,
 		"OrganizationID": bkt.OrgID.String(),
+		"SchemaType":     bkt.SchemaType.String(),
 	})
 	w.Flush()
 
@@ -227,13 +243,14 @@ func (b *cmdBucketBuilder) cmdFindRunEFn(cmd *cobra.Command, args []string) erro
 
 	w := internal.NewTabWriter(b.w)
 	w.HideHeaders(!b.headers)
-	w.WriteHeaders("ID", "Name", "Retention", "OrganizationID")
+	w.WriteHeaders("ID", "Name", "Retention", "OrganizationID", "SchemaType")
 	for _, b := range buckets {
 		w.Write(map[string]interface{}{
 			"ID":             b.ID.String(),
 			"Name":           b.Name,
 			"Retention":      b.RetentionPeriod,
 			"OrganizationID": b.OrgID.String(),
+			"SchemaType":     b.SchemaType.String(),
 		})
 	}
 	w.Flush()
@@ -314,3 +331,11 @@ func newBucketSVCs() (influxdb.BucketService, influxdb.OrganizationService, erro
 
 	return &http.BucketService{Client: httpClient}, orgSvc, nil
 }
+
+func newBucketSchemaSVC() (influxdb.BucketSchemaService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	return &http.BucketSchemaService{Client: httpClient}, nil
+}
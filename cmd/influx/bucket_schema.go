@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/spf13/cobra"
+)
+
+func (b *cmdBucketBuilder) cmdSchema() *cobra.Command {
+	cmd := b.newCmd("schema", nil)
+	cmd.Short = "Measurement schema management commands for buckets with an explicit schema"
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdSchemaCreate(),
+		b.cmdSchemaDelete(),
+		b.cmdSchemaList(),
+		b.cmdSchemaUpdate(),
+	)
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) registerSchemaBucketFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&b.bucketID, "bucket-id", "i", "", "The bucket ID (required)")
+	cmd.MarkFlagRequired("bucket-id")
+}
+
+func (b *cmdBucketBuilder) registerColumnsFileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&b.columnsFile, "columns-file", "f", "", "Path to a JSON file of measurement schema columns; reads from stdin when omitted")
+}
+
+func (b *cmdBucketBuilder) cmdSchemaCreate() *cobra.Command {
+	cmd := b.newCmd("create", b.cmdSchemaCreateRunEFn)
+	cmd.Short = "Create a measurement schema"
+
+	b.registerSchemaBucketFlag(cmd)
+	b.registerColumnsFileFlag(cmd)
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The measurement name (required)")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdSchemaCreateRunEFn(*cobra.Command, []string) error {
+	bucketID, err := influxdb.IDFromString(b.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", b.bucketID, err)
+	}
+
+	columns, err := b.readSchemaColumns()
+	if err != nil {
+		return err
+	}
+
+	svc, err := b.schemaSVCFn()
+	if err != nil {
+		return err
+	}
+
+	m := &influxdb.MeasurementSchema{
+		BucketID: *bucketID,
+		Name:     b.name,
+		Columns:  columns,
+	}
+	if err := svc.CreateMeasurementSchema(context.Background(), m); err != nil {
+		return fmt.Errorf("failed to create measurement schema: %v", err)
+	}
+
+	b.writeSchemas(false, m)
+	return nil
+}
+
+func (b *cmdBucketBuilder) cmdSchemaDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdSchemaDeleteRunEFn)
+	cmd.Short = "Delete a measurement schema"
+
+	b.registerSchemaBucketFlag(cmd)
+	cmd.Flags().StringVar(&b.id, "id", "", "The measurement schema ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdSchemaDeleteRunEFn(*cobra.Command, []string) error {
+	bucketID, id, err := b.schemaIDs()
+	if err != nil {
+		return err
+	}
+
+	svc, err := b.schemaSVCFn()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	m, err := svc.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return fmt.Errorf("failed to find measurement schema with id %q: %v", id, err)
+	}
+
+	if err := svc.DeleteMeasurementSchema(ctx, bucketID, id); err != nil {
+		return fmt.Errorf("failed to delete measurement schema with id %q: %v", id, err)
+	}
+
+	b.writeSchemas(false, m)
+	return nil
+}
+
+func (b *cmdBucketBuilder) cmdSchemaList() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdSchemaListRunEFn)
+	cmd.Short = "List the measurement schemas of a bucket"
+
+	b.registerSchemaBucketFlag(cmd)
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The measurement name")
+	cmd.Flags().BoolVar(&b.headers, "headers", true, "To print the table headers; defaults true")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdSchemaListRunEFn(*cobra.Command, []string) error {
+	bucketID, err := influxdb.IDFromString(b.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", b.bucketID, err)
+	}
+
+	svc, err := b.schemaSVCFn()
+	if err != nil {
+		return err
+	}
+
+	filter := influxdb.MeasurementSchemaFilter{BucketID: *bucketID}
+	if b.name != "" {
+		filter.Name = &b.name
+	}
+
+	ms, _, err := svc.FindMeasurementSchemas(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve measurement schemas: %v", err)
+	}
+
+	b.writeSchemas(!b.headers, ms...)
+	return nil
+}
+
+func (b *cmdBucketBuilder) cmdSchemaUpdate() *cobra.Command {
+	cmd := b.newCmd("update", b.cmdSchemaUpdateRunEFn)
+	cmd.Short = "Add columns to a measurement schema"
+
+	b.registerSchemaBucketFlag(cmd)
+	b.registerColumnsFileFlag(cmd)
+	cmd.Flags().StringVar(&b.id, "id", "", "The measurement schema ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdSchemaUpdateRunEFn(*cobra.Command, []string) error {
+	bucketID, id, err := b.schemaIDs()
+	if err != nil {
+		return err
+	}
+
+	columns, err := b.readSchemaColumns()
+	if err != nil {
+		return err
+	}
+
+	svc, err := b.schemaSVCFn()
+	if err != nil {
+		return err
+	}
+
+	m, err := svc.UpdateMeasurementSchema(context.Background(), bucketID, id, influxdb.MeasurementSchemaUpdate{
+		Columns: columns,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update measurement schema: %v", err)
+	}
+
+	b.writeSchemas(false, m)
+	return nil
+}
+
+func (b *cmdBucketBuilder) schemaIDs() (bucketID, id influxdb.ID, err error) {
+	if err := bucketID.DecodeFromString(b.bucketID); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode bucket id %q: %v", b.bucketID, err)
+	}
+	if err := id.DecodeFromString(b.id); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode measurement schema id %q: %v", b.id, err)
+	}
+	return bucketID, id, nil
+}
+
+// readSchemaColumns decodes the JSON array of columns from the columns file,
+// or from stdin if no file was provided.
+func (b *cmdBucketBuilder) readSchemaColumns() ([]influxdb.MeasurementSchemaColumn, error) {
+	var r io.Reader = b.in
+	if b.columnsFile != "" {
+		f, err := os.Open(b.columnsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open columns file: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var columns []influxdb.MeasurementSchemaColumn
+	if err := json.NewDecoder(r).Decode(&columns); err != nil {
+		return nil, fmt.Errorf("failed to decode measurement schema columns: %v", err)
+	}
+	return columns, nil
+}
+
+func (b *cmdBucketBuilder) writeSchemas(hideHeaders bool, ms ...*influxdb.MeasurementSchema) {
+	w := internal.NewTabWriter(b.w)
+	w.HideHeaders(hideHeaders)
+	w.WriteHeaders("ID", "Name", "BucketID", "Columns")
+	for _, m := range ms {
+		columns := make([]string, 0, len(m.Columns))
+		for _, c := range m.Columns {
+			column := c.Name + ":" + string(c.Type)
+			if c.DataType != "" {
+				column += ":" + string(c.DataType)
+			}
+			columns = append(columns, column)
+		}
+		w.Write(map[string]interface{}{
+			"ID":       m.ID.String(),
+			"Name":     m.Name,
+			"BucketID": m.BucketID.String(),
+			"Columns":  strings.Join(columns, ","),
+		})
+	}
+	w.Flush()
+}
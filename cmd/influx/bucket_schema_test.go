@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdBucketSchema(t *testing.T) {
+	setViperOptions()
+
+	fakeSchemaSVCFn := func(svc influxdb.BucketSchemaService) bucketSchemaSVCFn {
+		return func() (influxdb.BucketSchemaService, error) {
+			return svc, nil
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		columns := `[
+			{"name": "time", "type": "timestamp"},
+			{"name": "host", "type": "tag"},
+			{"name": "usage", "type": "field", "dataType": "float"}
+		]`
+
+		var got influxdb.MeasurementSchema
+		svc := mock.NewBucketSchemaService()
+		svc.CreateMeasurementSchemaFn = func(ctx context.Context, m *influxdb.MeasurementSchema) error {
+			got = *m
+			return nil
+		}
+
+		builder := newCmdBucketBuilder(nil, in(strings.NewReader(columns)), out(ioutil.Discard))
+		builder.schemaSVCFn = fakeSchemaSVCFn(svc)
+		cmd := builder.cmdSchemaCreate()
+		cmd.RunE = builder.cmdSchemaCreateRunEFn
+		cmd.SetArgs([]string{"--bucket-id=" + influxdb.ID(1).String(), "--name=cpu"})
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, influxdb.MeasurementSchema{
+			BucketID: 1,
+			Name:     "cpu",
+			Columns: []influxdb.MeasurementSchemaColumn{
+				{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+				{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+				{Name: "usage", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+			},
+		}, got)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		svc := mock.NewBucketSchemaService()
+		svc.FindMeasurementSchemasFn = func(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+			if filter.BucketID != 1 || filter.Name == nil || *filter.Name != "cpu" {
+				t.Errorf("unexpected filter: %+v", filter)
+			}
+			return nil, 0, nil
+		}
+
+		builder := newCmdBucketBuilder(nil, out(ioutil.Discard))
+		builder.schemaSVCFn = fakeSchemaSVCFn(svc)
+		cmd := builder.cmdSchemaList()
+		cmd.RunE = builder.cmdSchemaListRunEFn
+		cmd.SetArgs([]string{"-i=" + influxdb.ID(1).String(), "-n=cpu"})
+		require.NoError(t, cmd.Execute())
+	})
+}
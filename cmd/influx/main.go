@@ -124,9 +124,11 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdOrganization(runEWrapper),
 		cmdPing(),
 		cmdPkg(runEWrapper),
-		cmdQuery(),
+		cmdQuery(runEWrapper),
 		cmdTranspile(),
 		cmdREPL(),
+		cmdReplication(runEWrapper),
+		cmdRestore(),
 		cmdSetup(),
 		cmdTask(),
 		cmdUser(runEWrapper),
@@ -57,6 +57,7 @@ type cmdPkgBuilder struct {
 		endpoints    string
 		labels       string
 		rules        string
+		scripts      string
 		tasks        string
 		telegrafs    string
 		variables    string
@@ -224,6 +225,7 @@ func (b *cmdPkgBuilder) cmdPkgExport() *cobra.Command {
 	cmd.Flags().StringVar(&b.exportOpts.endpoints, "endpoints", "", "List of notification endpoint ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.labels, "labels", "", "List of label ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.rules, "rules", "", "List of notification rule ids comma separated")
+	cmd.Flags().StringVar(&b.exportOpts.scripts, "scripts", "", "List of script ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.tasks, "tasks", "", "List of task ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.telegrafs, "telegraf-configs", "", "List of telegraf config ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.variables, "variables", "", "List of variable ids comma separated")
@@ -249,6 +251,7 @@ func (b *cmdPkgBuilder) pkgExportRunEFn(cmd *cobra.Command, args []string) error
 		{kind: pkger.KindLabel, idStrs: strings.Split(b.exportOpts.labels, ",")},
 		{kind: pkger.KindNotificationEndpoint, idStrs: strings.Split(b.exportOpts.endpoints, ",")},
 		{kind: pkger.KindNotificationRule, idStrs: strings.Split(b.exportOpts.rules, ",")},
+		{kind: pkger.KindScript, idStrs: strings.Split(b.exportOpts.scripts, ",")},
 		{kind: pkger.KindTask, idStrs: strings.Split(b.exportOpts.tasks, ",")},
 		{kind: pkger.KindTelegraf, idStrs: strings.Split(b.exportOpts.telegrafs, ",")},
 		{kind: pkger.KindVariable, idStrs: strings.Split(b.exportOpts.variables, ",")},
@@ -635,6 +638,23 @@ func (b *cmdPkgBuilder) printPkgDiff(diff pkger.Diff) {
 		})
 	}
 
+	if scripts := diff.Scripts; len(scripts) > 0 {
+		headers := []string{"New", "ID", "Name", "Description"}
+		tablePrintFn("SCRIPTS", headers, len(scripts), func(i int) []string {
+			sc := scripts[i]
+			var oldDesc string
+			if sc.Old != nil {
+				oldDesc = sc.Old.Description
+			}
+			return []string{
+				boolDiff(sc.IsNew()),
+				sc.ID.String(),
+				sc.Name,
+				diffLn(sc.IsNew(), oldDesc, sc.New.Description),
+			}
+		})
+	}
+
 	if tasks := diff.Tasks; len(tasks) > 0 {
 		headers := []string{"New", "Name", "Description", "Cycle"}
 		tablePrintFn("TASKS", headers, len(tasks), func(i int) []string {
@@ -773,6 +793,18 @@ func (b *cmdPkgBuilder) printPkgSummary(sum pkger.Summary) {
 		})
 	}
 
+	if scripts := sum.Scripts; len(scripts) > 0 {
+		headers := []string{"ID", "Name", "Description"}
+		tablePrintFn("SCRIPTS", headers, len(scripts), func(i int) []string {
+			sc := scripts[i]
+			return []string{
+				sc.ID.String(),
+				sc.Name,
+				sc.Description,
+			}
+		})
+	}
+
 	if tasks := sum.Tasks; len(tasks) > 0 {
 		headers := []string{"ID", "Name", "Description", "Cycle"}
 		tablePrintFn("TASKS", headers, len(tasks), func(i int) []string {
@@ -1,20 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	_ "github.com/influxdata/flux/stdlib"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/stdlib"
 	"github.com/spf13/cobra"
 )
 
 var queryFlags struct {
-	org organization
+	org    organization
+	format string
 }
 
-func cmdQuery() *cobra.Command {
+func cmdQuery(opts ...genericCLIOptFn) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "query [query literal or @/path/to/query.flux]",
 		Short: "Execute a Flux query",
@@ -24,6 +32,13 @@ or execute a literal Flux query contained in a file by specifying the file prefi
 		RunE: wrapCheckSetup(fluxQueryF),
 	}
 	queryFlags.org.register(cmd, true)
+	cmd.Flags().StringVar(&queryFlags.format, "format", "", "Write the raw results in the format csv, json, arrow or parquet instead of printing tables")
+
+	b := newCmdRunningQueryBuilder(newRunningQuerySVCs, opts...)
+	cmd.AddCommand(
+		b.cmdList(),
+		b.cmdKill(),
+	)
 
 	return cmd
 }
@@ -52,6 +67,10 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if queryFlags.format != "" {
+		return fluxQueryFormatted(q, orgID, queryFlags.format)
+	}
+
 	flux.FinalizeBuiltIns()
 
 	r, err := getFluxREPL(flags.host, flags.token, flags.skipVerify, orgID)
@@ -65,3 +84,43 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// fluxQueryFormatted writes the results of the query to stdout as
+// encoded by the server in the given format.
+func fluxQueryFormatted(q string, orgID platform.ID, format string) error {
+	var dialect flux.Dialect
+	switch format {
+	case "csv":
+		dialect = &csv.Dialect{
+			ResultEncoderConfig: csv.ResultEncoderConfig{
+				Annotations: []string{"datatype", "group", "default"},
+				Delimiter:   ',',
+			},
+		}
+	case "json":
+		dialect = query.NewJSONDialect()
+	case "arrow":
+		dialect = query.NewArrowDialect()
+	case "parquet":
+		dialect = query.NewParquetDialect()
+	default:
+		return fmt.Errorf("unsupported format %q: must be one of csv, json, arrow or parquet", format)
+	}
+
+	s := &http.FluxService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: orgID,
+			Compiler:       lang.FluxCompiler{Query: q},
+		},
+		Dialect: dialect,
+	}
+	if _, err := s.Query(context.Background(), os.Stdout, req); err != nil {
+		return fmt.Errorf("failed to execute query: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+type replicationSVCsFn func() (influxdb.ReplicationService, influxdb.OrganizationService, error)
+
+func cmdReplication(opts ...genericCLIOptFn) *cobra.Command {
+	return newCmdReplicationBuilder(newReplicationSVCs, opts...).cmd()
+}
+
+type cmdReplicationBuilder struct {
+	genericCLIOpts
+
+	svcFn replicationSVCsFn
+
+	id             string
+	headers        bool
+	name           string
+	description    string
+	org            organization
+	localBucketID  string
+	remoteURL      string
+	remoteToken    string
+	remoteOrgID    string
+	remoteBucketID string
+	maxQueueBytes  int64
+}
+
+func newCmdReplicationBuilder(svcsFn replicationSVCsFn, opts ...genericCLIOptFn) *cmdReplicationBuilder {
+	opt := genericCLIOpts{
+		in: os.Stdin,
+		w:  os.Stdout,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return &cmdReplicationBuilder{
+		genericCLIOpts: opt,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdReplicationBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("replication", nil)
+	cmd.Short = "Replication management commands"
+	cmd.TraverseChildren = true
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdCreate(),
+		b.cmdDelete(),
+		b.cmdList(),
+		b.cmdUpdate(),
+	)
+
+	return cmd
+}
+
+func (b *cmdReplicationBuilder) registerRemoteFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&b.remoteURL, "remote-url", "", "The URL of the remote InfluxDB instance")
+	cmd.Flags().StringVar(&b.remoteToken, "remote-api-token", "", "The token used to write to the remote bucket")
+	cmd.Flags().StringVar(&b.remoteOrgID, "remote-org-id", "", "The ID of the organization of the remote bucket")
+	cmd.Flags().StringVar(&b.remoteBucketID, "remote-bucket-id", "", "The ID of the remote bucket")
+	cmd.Flags().Int64Var(&b.maxQueueBytes, "max-queue-bytes", 0, "The maximum size of the queue of points waiting to be replicated")
+}
+
+func (b *cmdReplicationBuilder) cmdCreate() *cobra.Command {
+	cmd := b.newCmd("create", b.cmdCreateRunEFn)
+	cmd.Short = "Create a replication of a local bucket to a remote bucket"
+
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The replication name (required)")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of the replication")
+	cmd.Flags().StringVar(&b.localBucketID, "local-bucket-id", "", "The ID of the local bucket to replicate (required)")
+	cmd.MarkFlagRequired("local-bucket-id")
+	b.registerRemoteFlags(cmd)
+	cmd.MarkFlagRequired("remote-url")
+	cmd.MarkFlagRequired("remote-org-id")
+	cmd.MarkFlagRequired("remote-bucket-id")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdReplicationBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
+	if err := b.org.validOrgFlags(); err != nil {
+		return err
+	}
+
+	localBucketID, err := influxdb.IDFromString(b.localBucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode local bucket id %q: %v", b.localBucketID, err)
+	}
+	remoteOrgID, err := influxdb.IDFromString(b.remoteOrgID)
+	if err != nil {
+		return fmt.Errorf("failed to decode remote org id %q: %v", b.remoteOrgID, err)
+	}
+	remoteBucketID, err := influxdb.IDFromString(b.remoteBucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode remote bucket id %q: %v", b.remoteBucketID, err)
+	}
+
+	svc, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	r := &influxdb.Replication{
+		Name:              b.name,
+		Description:       b.description,
+		LocalBucketID:     *localBucketID,
+		RemoteURL:         b.remoteURL,
+		RemoteToken:       b.remoteToken,
+		RemoteOrgID:       *remoteOrgID,
+		RemoteBucketID:    *remoteBucketID,
+		MaxQueueSizeBytes: b.maxQueueBytes,
+	}
+	r.OrgID, err = b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.CreateReplication(context.Background(), r); err != nil {
+		return fmt.Errorf("failed to create replication: %v", err)
+	}
+
+	b.writeReplications(false, r)
+	return nil
+}
+
+func (b *cmdReplicationBuilder) cmdDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdDeleteRunEFn)
+	cmd.Short = "Delete a replication and discard the points in its queue"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The replication ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdReplicationBuilder) cmdDeleteRunEFn(*cobra.Command, []string) error {
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode replication id %q: %v", b.id, err)
+	}
+
+	svc, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	r, err := svc.FindReplicationByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find replication with id %q: %v", id, err)
+	}
+
+	if err := svc.DeleteReplication(ctx, *id); err != nil {
+		return fmt.Errorf("failed to delete replication with id %q: %v", id, err)
+	}
+
+	b.writeReplications(false, r)
+	return nil
+}
+
+func (b *cmdReplicationBuilder) cmdList() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdListRunEFn)
+	cmd.Short = "List replications and the status of their queues"
+
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The replication name")
+	cmd.Flags().StringVar(&b.localBucketID, "local-bucket-id", "", "The ID of the local bucket")
+	cmd.Flags().BoolVar(&b.headers, "headers", true, "To print the table headers; defaults true")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdReplicationBuilder) cmdListRunEFn(*cobra.Command, []string) error {
+	svc, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var filter influxdb.ReplicationFilter
+	if b.org.id != "" || b.org.name != "" {
+		orgID, err := b.org.getID(orgSVC)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+	if b.name != "" {
+		filter.Name = &b.name
+	}
+	if b.localBucketID != "" {
+		filter.LocalBucketID, err = influxdb.IDFromString(b.localBucketID)
+		if err != nil {
+			return fmt.Errorf("failed to decode local bucket id %q: %v", b.localBucketID, err)
+		}
+	}
+
+	rs, _, err := svc.FindReplications(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve replications: %v", err)
+	}
+
+	b.writeReplications(!b.headers, rs...)
+	return nil
+}
+
+func (b *cmdReplicationBuilder) cmdUpdate() *cobra.Command {
+	cmd := b.newCmd("update", b.cmdUpdateRunEFn)
+	cmd.Short = "Update a replication"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The replication ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "New replication name")
+	cmd.Flags().StringVarP(&b.description, "description", "d", "", "New description of the replication")
+	b.registerRemoteFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdReplicationBuilder) cmdUpdateRunEFn(*cobra.Command, []string) error {
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode replication id %q: %v", b.id, err)
+	}
+
+	var upd influxdb.ReplicationUpdate
+	if b.name != "" {
+		upd.Name = &b.name
+	}
+	if b.description != "" {
+		upd.Description = &b.description
+	}
+	if b.remoteURL != "" {
+		upd.RemoteURL = &b.remoteURL
+	}
+	if b.remoteToken != "" {
+		upd.RemoteToken = &b.remoteToken
+	}
+	if b.remoteOrgID != "" {
+		upd.RemoteOrgID, err = influxdb.IDFromString(b.remoteOrgID)
+		if err != nil {
+			return fmt.Errorf("failed to decode remote org id %q: %v", b.remoteOrgID, err)
+		}
+	}
+	if b.remoteBucketID != "" {
+		upd.RemoteBucketID, err = influxdb.IDFromString(b.remoteBucketID)
+		if err != nil {
+			return fmt.Errorf("failed to decode remote bucket id %q: %v", b.remoteBucketID, err)
+		}
+	}
+	if b.maxQueueBytes != 0 {
+		upd.MaxQueueSizeBytes = &b.maxQueueBytes
+	}
+
+	svc, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	r, err := svc.UpdateReplication(context.Background(), *id, upd)
+	if err != nil {
+		return fmt.Errorf("failed to update replication: %v", err)
+	}
+
+	b.writeReplications(false, r)
+	return nil
+}
+
+func (b *cmdReplicationBuilder) writeReplications(hideHeaders bool, rs ...*influxdb.Replication) {
+	w := internal.NewTabWriter(b.w)
+	w.HideHeaders(hideHeaders)
+	w.WriteHeaders("ID", "Name", "OrganizationID", "LocalBucketID", "RemoteURL", "RemoteBucketID", "QueueSizeBytes", "MaxQueueSizeBytes", "LatestStatusCode", "LatestError")
+	for _, r := range rs {
+		w.Write(map[string]interface{}{
+			"ID":                r.ID.String(),
+			"Name":              r.Name,
+			"OrganizationID":    r.OrgID.String(),
+			"LocalBucketID":     r.LocalBucketID.String(),
+			"RemoteURL":         r.RemoteURL,
+			"RemoteBucketID":    r.RemoteBucketID.String(),
+			"QueueSizeBytes":    r.CurrentQueueSizeBytes,
+			"MaxQueueSizeBytes": r.MaxQueueSizeBytes,
+			"LatestStatusCode":  r.LatestResponseCode,
+			"LatestError":       r.LatestErrorMessage,
+		})
+	}
+	w.Flush()
+}
+
+func newReplicationSVCs() (influxdb.ReplicationService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgSvc := &http.OrganizationService{Client: httpClient}
+
+	return &http.ReplicationService{Client: httpClient}, orgSvc, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdReplication(t *testing.T) {
+	setViperOptions()
+
+	fakeSVCFn := func(svc influxdb.ReplicationService) replicationSVCsFn {
+		return func() (influxdb.ReplicationService, influxdb.OrganizationService, error) {
+			return svc, mock.NewOrganizationService(), nil
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		var got influxdb.Replication
+		svc := mock.NewReplicationService()
+		svc.CreateReplicationFn = func(ctx context.Context, r *influxdb.Replication) error {
+			got = *r
+			return nil
+		}
+
+		builder := newCmdReplicationBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+		cmd := builder.cmdCreate()
+		cmd.RunE = builder.cmdCreateRunEFn
+		cmd.SetArgs([]string{
+			"--name=edge",
+			"--org-id=" + influxdb.ID(1).String(),
+			"--local-bucket-id=" + influxdb.ID(2).String(),
+			"--remote-url=https://cloud.example.com",
+			"--remote-api-token=secret",
+			"--remote-org-id=" + influxdb.ID(3).String(),
+			"--remote-bucket-id=" + influxdb.ID(4).String(),
+			"--max-queue-bytes=1048576",
+		})
+		require.NoError(t, cmd.Execute())
+
+		assert.Equal(t, influxdb.Replication{
+			OrgID:             1,
+			Name:              "edge",
+			LocalBucketID:     2,
+			RemoteURL:         "https://cloud.example.com",
+			RemoteToken:       "secret",
+			RemoteOrgID:       3,
+			RemoteBucketID:    4,
+			MaxQueueSizeBytes: 1048576,
+		}, got)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var got influxdb.ReplicationUpdate
+		svc := mock.NewReplicationService()
+		svc.UpdateReplicationFn = func(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+			if id != 5 {
+				t.Errorf("unexpected replication id: %s", id)
+			}
+			got = upd
+			return &influxdb.Replication{ID: id}, nil
+		}
+
+		builder := newCmdReplicationBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+		cmd := builder.cmdUpdate()
+		cmd.RunE = builder.cmdUpdateRunEFn
+		cmd.SetArgs([]string{"--id=" + influxdb.ID(5).String(), "--remote-url=https://other.example.com"})
+		require.NoError(t, cmd.Execute())
+
+		require.NotNil(t, got.RemoteURL)
+		assert.Equal(t, "https://other.example.com", *got.RemoteURL)
+		assert.Nil(t, got.Name)
+		assert.Nil(t, got.RemoteToken)
+	})
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+)
+
+func cmdRestore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a backup into the running InfluxDB instance",
+		Long: fmt.Sprintf(
+			`Restores the data and meta data of a backup written by influx backup into
+the running InfluxDB instance, replacing all of its data and meta data.
+
+The backup at --path must be a full backup. The incremental backups taken from it
+are restored on top of it with --incremental-path, once for every backup, in the
+order they were taken. Every backup of the chain requires its %s.
+The manifests and the files of the backups are verified before any data is
+restored. An encrypted backup requires its key, given with --encryption-key-file.
+
+The data files are restored first, then the meta data. The restored meta data
+replaces the tokens of the instance, so the token of the backup is written to the
+credentials file of influx once the restore is complete, replacing the token there.
+The token is printed as well with --print-token.`,
+			influxdb.BackupManifestFilename),
+		Args: cobra.NoArgs,
+		RunE: restoreF,
+	}
+	opts := flagOpts{
+		{
+			DestP:    &restoreFlags.Path,
+			Flag:     "path",
+			Short:    'p',
+			Desc:     "directory path of the full backup to restore",
+			Required: true,
+		},
+		{
+			DestP: &restoreFlags.IncrementalPaths,
+			Flag:  "incremental-path",
+			Desc:  "directory paths of the incremental backups to restore on top of the backup, in the order they were taken",
+		},
+		{
+			DestP: &restoreFlags.EncryptionKeyFile,
+			Flag:  "encryption-key-file",
+			Desc:  "path to the file with the hex encoded key the backup files are encrypted with",
+		},
+	}
+	opts.mustRegister(cmd)
+	cmd.Flags().BoolVar(&restoreFlags.PrintToken, "print-token", false, "print the token of the backup once it is restored")
+
+	return cmd
+}
+
+var restoreFlags struct {
+	Path              string
+	IncrementalPaths  []string
+	EncryptionKeyFile string
+	PrintToken        bool
+}
+
+func newRestoreService() (*http.RestoreService, error) {
+	return &http.RestoreService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if flags.local {
+		return fmt.Errorf("local flag not supported for restore command")
+	}
+
+	if restoreFlags.Path == "" {
+		return fmt.Errorf("must specify path")
+	}
+
+	key, err := readBackupKey(restoreFlags.EncryptionKeyFile)
+	if err != nil {
+		return err
+	}
+
+	paths := append([]string{restoreFlags.Path}, restoreFlags.IncrementalPaths...)
+	chain := make([]*influxdb.BackupManifest, 0, len(paths))
+	for _, path := range paths {
+		m, err := readBackupManifest(path)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return fmt.Errorf("backup at %s has no %s; restore it with influxd restore", path, influxdb.BackupManifestFilename)
+		}
+		if err := m.Verify(key); err != nil {
+			return err
+		}
+		if m.KV == nil || m.Credentials == nil {
+			return fmt.Errorf("backup %s at %s has no meta data", m.ID, path)
+		}
+		chain = append(chain, m)
+	}
+	if err := influxdb.ValidateBackupChain(chain); err != nil {
+		return err
+	}
+	manifest := chain[len(chain)-1]
+
+	// every file is verified before any data is restored, and then read
+	// once more to restore it.
+	for i, m := range chain {
+		if err := influxdb.VerifyBackupFiles(paths[i], m, key); err != nil {
+			return err
+		}
+		fmt.Printf("Verified backup %s\n", m.ID)
+	}
+
+	restoreService, err := newRestoreService()
+	if err != nil {
+		return err
+	}
+
+	files, closer, err := openRestoreEngineFiles(chain, paths, key)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	fmt.Printf("Restoring %d data files of backup %s\n", len(files), manifest.ID)
+	if err := restoreService.RestoreEngine(ctx, chain, files); err != nil {
+		return fmt.Errorf("failed to restore data files: %v", err)
+	}
+
+	var (
+		kv      io.Reader
+		changes = make([]io.Reader, 0, len(chain)-1)
+	)
+	for i, m := range chain {
+		r, err := influxdb.OpenBackupFile(paths[i], m, *m.KV, key)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		if i == 0 {
+			kv = r
+			continue
+		}
+		changes = append(changes, r)
+	}
+
+	fmt.Printf("Restoring meta data of backup %s\n", manifest.ID)
+	if err := restoreService.RestoreKVStore(ctx, chain, kv, changes); err != nil {
+		return fmt.Errorf("failed to restore meta data: %v", err)
+	}
+
+	cred, err := influxdb.OpenBackupFile(paths[len(paths)-1], manifest, *manifest.Credentials, key)
+	if err != nil {
+		return err
+	}
+	defer cred.Close()
+	token, err := ioutil.ReadAll(cred)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restore of backup %s complete\n", manifest.ID)
+
+	dPath, dir, err := defaultTokenPath()
+	if err != nil {
+		return err
+	}
+	tok := strings.TrimSpace(string(token))
+	if err := writeTokenToPath(tok, dPath, dir); err != nil {
+		return fmt.Errorf("failed to write token to path %q: %v", dPath, err)
+	}
+	fmt.Printf("The token of the backup has been stored in %s\n", dPath)
+	if restoreFlags.PrintToken {
+		fmt.Printf("The token of the backup is %s\n", tok)
+	}
+
+	return nil
+}
+
+// openRestoreEngineFiles opens the engine files of the last backup of chain.
+// Every file is read from the latest backup of chain it is included in,
+// paths being the directories of the backups of chain.
+func openRestoreEngineFiles(chain []*influxdb.BackupManifest, paths []string, key []byte) (map[string]io.Reader, io.Closer, error) {
+	var closer multiCloser
+
+	manifest := chain[len(chain)-1]
+	files := make(map[string]io.Reader, len(manifest.Files))
+	for _, bf := range manifest.Files {
+		i, err := findRestoreEngineFile(chain, bf.Name)
+		if err != nil {
+			closer.Close()
+			return nil, nil, err
+		}
+		r, err := influxdb.OpenBackupFile(paths[i], chain[i], bf, key)
+		if err != nil {
+			closer.Close()
+			return nil, nil, err
+		}
+		closer = append(closer, r)
+		files[bf.Name] = r
+	}
+	return files, closer, nil
+}
+
+type multiCloser []io.Closer
+
+func (c multiCloser) Close() error {
+	var err error
+	for _, cl := range c {
+		err = multierr.Append(err, cl.Close())
+	}
+	return err
+}
+
+// findRestoreEngineFile returns the index of the latest backup of chain the
+// file name is included in.
+func findRestoreEngineFile(chain []*influxdb.BackupManifest, name string) (int, error) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, f := range chain[i].Files {
+			if f.Name == name && f.Included {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("file %s of backup %s is not included in any backup", name, chain[len(chain)-1].ID)
+}
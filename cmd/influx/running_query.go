@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+type runningQuerySVCsFn func() (influxdb.RunningQueryService, influxdb.OrganizationService, error)
+
+type cmdRunningQueryBuilder struct {
+	genericCLIOpts
+
+	svcFn runningQuerySVCsFn
+
+	id      string
+	headers bool
+	org     organization
+}
+
+func newCmdRunningQueryBuilder(svcsFn runningQuerySVCsFn, opts ...genericCLIOptFn) *cmdRunningQueryBuilder {
+	opt := genericCLIOpts{
+		in: os.Stdin,
+		w:  os.Stdout,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return &cmdRunningQueryBuilder{
+		genericCLIOpts: opt,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdRunningQueryBuilder) cmdList() *cobra.Command {
+	cmd := b.newCmd("ls", b.cmdListRunEFn)
+	cmd.Short = "List the queries running on the server"
+	cmd.Aliases = []string{"list"}
+
+	cmd.Flags().BoolVar(&b.headers, "headers", true, "To print the table headers; defaults true")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdRunningQueryBuilder) cmdListRunEFn(*cobra.Command, []string) error {
+	svc, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var filter influxdb.RunningQueryFilter
+	if b.org.id != "" || b.org.name != "" {
+		orgID, err := b.org.getID(orgSVC)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	qs, err := svc.FindRunningQueries(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve running queries: %v", err)
+	}
+
+	b.writeRunningQueries(!b.headers, qs...)
+	return nil
+}
+
+func (b *cmdRunningQueryBuilder) cmdKill() *cobra.Command {
+	cmd := b.newCmd("kill", b.cmdKillRunEFn)
+	cmd.Short = "Cancel a running query"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The query ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdRunningQueryBuilder) cmdKillRunEFn(*cobra.Command, []string) error {
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode query id %q: %v", b.id, err)
+	}
+
+	svc, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	q, err := svc.FindRunningQueryByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find query with id %q: %v", id, err)
+	}
+
+	if err := svc.CancelRunningQuery(ctx, *id); err != nil {
+		return fmt.Errorf("failed to cancel query with id %q: %v", id, err)
+	}
+
+	b.writeRunningQueries(false, q)
+	return nil
+}
+
+func (b *cmdRunningQueryBuilder) writeRunningQueries(hideHeaders bool, qs ...*influxdb.RunningQuery) {
+	w := internal.NewTabWriter(b.w)
+	w.HideHeaders(hideHeaders)
+	w.WriteHeaders("ID", "OrganizationID", "UserID", "State", "CompileDuration", "QueueDuration", "ExecuteDuration", "MemoryBytes", "Query")
+	for _, q := range qs {
+		var userID string
+		if q.UserID.Valid() {
+			userID = q.UserID.String()
+		}
+		w.Write(map[string]interface{}{
+			"ID":              q.ID.String(),
+			"OrganizationID":  q.OrgID.String(),
+			"UserID":          userID,
+			"State":           q.State,
+			"CompileDuration": q.CompileDuration,
+			"QueueDuration":   q.QueueDuration,
+			"ExecuteDuration": q.ExecuteDuration,
+			"MemoryBytes":     q.MemoryBytes,
+			// the query is printed on a single line of the table.
+			"Query": strings.Join(strings.Fields(q.Query), " "),
+		})
+	}
+	w.Flush()
+}
+
+func newRunningQuerySVCs() (influxdb.RunningQueryService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgSvc := &http.OrganizationService{Client: httpClient}
+
+	return &http.RunningQueryService{Client: httpClient}, orgSvc, nil
+}
@@ -21,6 +21,7 @@ var writeFlags struct {
 	BucketID  string
 	Bucket    string
 	Precision string
+	Format    string
 }
 
 func cmdWrite() *cobra.Command {
@@ -28,7 +29,10 @@ func cmdWrite() *cobra.Command {
 		Use:   "write line protocol or @/path/to/points.txt",
 		Short: "Write points to InfluxDB",
 		Long: `Write a single line of line protocol to InfluxDB,
-or add an entire file specified with an @ prefix.`,
+or add an entire file specified with an @ prefix.
+
+With --format csv the data is annotated CSV, as returned by
+Flux queries, and with --format json it is a JSON array of points.`,
 		Args: cobra.ExactArgs(1),
 		RunE: wrapCheckSetup(fluxWriteF),
 	}
@@ -69,6 +73,13 @@ or add an entire file specified with an @ prefix.`,
 			Desc:       "Precision of the timestamps of the lines",
 			Persistent: true,
 		},
+		{
+			DestP:      &writeFlags.Format,
+			Flag:       "format",
+			Default:    string(write.FormatLineProtocol),
+			Desc:       "Format of the data; one of lp, csv or json",
+			Persistent: true,
+		},
 	}
 	opts.mustRegister(cmd)
 
@@ -90,6 +101,11 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
+	format, err := write.ParseFormat(writeFlags.Format)
+	if err != nil {
+		return err
+	}
+
 	bs, err := newBucketService()
 	if err != nil {
 		return err
@@ -147,13 +163,17 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		r = strings.NewReader(args[0])
 	}
 
-	s := write.Batcher{
-		Service: &http.WriteService{
-			Addr:               flags.host,
-			Token:              flags.token,
-			Precision:          writeFlags.Precision,
-			InsecureSkipVerify: flags.skipVerify,
-		},
+	var s platform.WriteService = &http.WriteService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		Precision:          writeFlags.Precision,
+		Format:             format,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+	// only line protocol can be split into batches at any line; annotated CSV
+	// and JSON documents are sent whole.
+	if format == write.FormatLineProtocol {
+		s = &write.Batcher{Service: s}
 	}
 
 	ctx = signals.WithStandardSignals(ctx)
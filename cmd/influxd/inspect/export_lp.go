@@ -0,0 +1,127 @@
+package inspect
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/spf13/cobra"
+)
+
+// exportLPFlags defines the `export-lp` Command.
+var exportLPFlags = struct {
+	enginePath      string
+	orgID, bucketID string
+	start, end      string
+	measurement     string
+	outputPath      string
+	compress        bool
+}{}
+
+func NewExportLineProtocolCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-lp",
+		Short: "Export the points of a bucket as line protocol",
+		Long: `
+This command exports the points of a bucket from the TSM files and the WAL of a
+storage engine directory as line protocol. The points deleted from the bucket
+are not exported.
+
+The engine must not be running, since the WAL is replayed as it is read; the
+points of a running instance are exported with the /api/v2/export endpoint.
+
+The points are exported series by series, in the order of their series keys.`,
+		Args: cobra.NoArgs,
+		RunE: inspectExportLineProtocol,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+	dir = filepath.Join(dir, "engine")
+	cmd.Flags().StringVarP(&exportLPFlags.enginePath, "engine-path", "", dir, fmt.Sprintf("path to the storage engine directory (defaults to %s).", dir))
+	cmd.Flags().StringVarP(&exportLPFlags.orgID, "org-id", "", "", "ID of the organization of the bucket to export.")
+	cmd.Flags().StringVarP(&exportLPFlags.bucketID, "bucket-id", "", "", "ID of the bucket to export.")
+	cmd.Flags().StringVarP(&exportLPFlags.start, "start", "", "", "export only points at or after the RFC3339 time.")
+	cmd.Flags().StringVarP(&exportLPFlags.end, "end", "", "", "export only points at or before the RFC3339 time.")
+	cmd.Flags().StringVarP(&exportLPFlags.measurement, "measurement", "", "", "export only points of the measurement.")
+	cmd.Flags().StringVarP(&exportLPFlags.outputPath, "output-path", "", "", "path to the file the points are written to (defaults to stdout).")
+	cmd.Flags().BoolVarP(&exportLPFlags.compress, "compress", "", false, "compress the points with gzip.")
+	cmd.MarkFlagRequired("org-id")
+	cmd.MarkFlagRequired("bucket-id")
+
+	return cmd
+}
+
+// inspectExportLineProtocol runs the export-lp tool.
+func inspectExportLineProtocol(cmd *cobra.Command, args []string) error {
+	orgID, err := influxdb.IDFromString(exportLPFlags.orgID)
+	if err != nil {
+		return fmt.Errorf("invalid org ID: %v", err)
+	}
+	bucketID, err := influxdb.IDFromString(exportLPFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("invalid bucket ID: %v", err)
+	}
+
+	req := influxdb.NewExportRequest(*orgID, *bucketID)
+	req.Measurement = exportLPFlags.measurement
+	if exportLPFlags.start != "" {
+		t, err := time.Parse(time.RFC3339Nano, exportLPFlags.start)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %v", err)
+		}
+		req.Start = t.UnixNano()
+	}
+	if exportLPFlags.end != "" {
+		t, err := time.Parse(time.RFC3339Nano, exportLPFlags.end)
+		if err != nil {
+			return fmt.Errorf("invalid end time: %v", err)
+		}
+		req.Stop = t.UnixNano()
+	}
+
+	out := os.Stdout
+	if exportLPFlags.outputPath != "" {
+		if out, err = os.Create(exportLPFlags.outputPath); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	bw := bufio.NewWriter(out)
+
+	w := io.Writer(bw)
+	var gw *gzip.Writer
+	if exportLPFlags.compress {
+		gw = gzip.NewWriter(bw)
+		w = gw
+	}
+
+	dataDir := filepath.Join(exportLPFlags.enginePath, storage.DefaultEngineDirectoryName)
+	walDir := filepath.Join(exportLPFlags.enginePath, storage.DefaultWALDirectoryName)
+	if err := storage.ExportLineProtocolFiles(context.Background(), dataDir, walDir, req, w); err != nil {
+		return err
+	}
+
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		return out.Close()
+	}
+	return nil
+}
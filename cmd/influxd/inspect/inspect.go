@@ -17,6 +17,7 @@ func NewCommand() *cobra.Command {
 		NewBuildTSICommand(),
 		NewExportBlocksCommand(),
 		NewExportIndexCommand(),
+		NewExportLineProtocolCommand(),
 		NewReportTSMCommand(),
 		NewVerifyTSMCommand(),
 		NewVerifyWALCommand(),
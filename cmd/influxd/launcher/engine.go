@@ -31,6 +31,9 @@ type Engine interface {
 	storage.BucketDeleter
 	prom.PrometheusCollector
 	influxdb.BackupService
+	influxdb.RestoreService
+	influxdb.ExportService
+	influxdb.BucketCardinalityService
 
 	SeriesCardinality() int64
 
@@ -115,6 +118,11 @@ func (t *TemporaryEngine) SeriesCardinality() int64 {
 	return t.engine.SeriesCardinality()
 }
 
+// BucketSeriesCardinality returns the number of series in a bucket.
+func (t *TemporaryEngine) BucketSeriesCardinality(ctx context.Context, orgID, bucketID influxdb.ID) (int64, error) {
+	return t.engine.BucketSeriesCardinality(ctx, orgID, bucketID)
+}
+
 // DeleteBucketRangePredicate will delete a bucket from the range and predicate.
 func (t *TemporaryEngine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
 	return t.engine.DeleteBucketRangePredicate(ctx, orgID, bucketID, min, max, pred)
@@ -179,3 +187,15 @@ func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, bac
 func (t *TemporaryEngine) InternalBackupPath(backupID int) string {
 	return t.engine.InternalBackupPath(backupID)
 }
+
+func (t *TemporaryEngine) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error) {
+	return t.engine.RestoreBucket(ctx, req, tsm, tombstone)
+}
+
+func (t *TemporaryEngine) RestoreEngine(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error {
+	return t.engine.RestoreEngine(ctx, chain, files)
+}
+
+func (t *TemporaryEngine) ExportLineProtocol(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+	return t.engine.ExportLineProtocol(ctx, req, w)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/influxdata/influxdb/cmd/influxd/inspect"
 	"github.com/influxdata/influxdb/endpoints"
 	"github.com/influxdata/influxdb/gather"
+	"github.com/influxdata/influxdb/graphite"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/internal/fs"
@@ -34,11 +35,13 @@ import (
 	"github.com/influxdata/influxdb/kv"
 	influxlogger "github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/opentsdb"
 	"github.com/influxdata/influxdb/pkger"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/replications"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
@@ -174,6 +177,42 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP:   &l.replicationsPath,
+			Flag:    "replications-path",
+			Default: filepath.Join(dir, "replicationq"),
+			Desc:    "path to the queues of points waiting to be replicated to remote instances",
+		},
+		{
+			DestP:   &l.StorageConfig.MaxSeriesPerBucket,
+			Flag:    "storage-max-series-per-bucket",
+			Default: 0,
+			Desc:    "maximum number of series in a bucket; writes which would create more are rejected. 0 is unlimited",
+		},
+		{
+			DestP:   &l.StorageConfig.MaxSeriesPerOrg,
+			Flag:    "storage-max-series-per-org",
+			Default: 0,
+			Desc:    "maximum number of series in all of the buckets of an organization; writes which would create more are rejected. 0 is unlimited",
+		},
+		{
+			DestP:   &l.queryOrgConcurrencyQuota,
+			Flag:    "query-org-concurrency-quota",
+			Default: 0,
+			Desc:    "maximum number of queued and executing queries of an organization; organizations may override it. 0 is unlimited",
+		},
+		{
+			DestP:   &l.queryOrgMemoryBytesQuota,
+			Flag:    "query-org-memory-bytes-quota",
+			Default: 0,
+			Desc:    "maximum number of bytes the executing queries of an organization may use; organizations may override it. 0 is unlimited",
+		},
+		{
+			DestP:   &l.queryLogDisabled,
+			Flag:    "query-log-disabled",
+			Default: false,
+			Desc:    "disables writing a log of every query to the _queries system bucket of its organization",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
@@ -198,6 +237,94 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.writeStreamChunkSize,
+			Flag:    "write-stream-chunk-size",
+			Default: 0,
+			Desc:    "parse line protocol as write requests are read and write it in chunks of this many lines; 0 reads the whole request first",
+		},
+		{
+			DestP:   &l.graphiteConfig.BindAddress,
+			Flag:    "graphite-bind-address",
+			Default: "",
+			Desc:    "bind address of the Graphite plaintext listener, for example :2003; the listener is disabled when empty",
+		},
+		{
+			DestP:   &l.graphiteConfig.Protocol,
+			Flag:    "graphite-protocol",
+			Default: graphite.DefaultProtocol,
+			Desc:    "protocol of the Graphite listener, tcp or udp",
+		},
+		{
+			DestP:   &l.graphiteOrgID,
+			Flag:    "graphite-org-id",
+			Default: "",
+			Desc:    "ID of the organization the Graphite listener writes to",
+		},
+		{
+			DestP:   &l.graphiteBucketID,
+			Flag:    "graphite-bucket-id",
+			Default: "",
+			Desc:    "ID of the bucket the Graphite listener writes to",
+		},
+		{
+			DestP: &l.graphiteConfig.Templates,
+			Flag:  "graphite-templates",
+			Desc:  "templates mapping Graphite metric names to a measurement, tags and a field, in the format \"[filter] <template> [tag1=value1,tag2=value2]\"",
+		},
+		{
+			DestP: &l.graphiteConfig.Tags,
+			Flag:  "graphite-tags",
+			Desc:  "tags added to every Graphite metric, in the format key=value",
+		},
+		{
+			DestP:   &l.graphiteConfig.Separator,
+			Flag:    "graphite-separator",
+			Default: graphite.DefaultSeparator,
+			Desc:    "separator joining the parts of a Graphite metric name mapped to the same measurement, tag or field",
+		},
+		{
+			DestP:   &l.graphiteConfig.BatchSize,
+			Flag:    "graphite-batch-size",
+			Default: graphite.DefaultBatchSize,
+			Desc:    "number of points the Graphite listener writes at a time",
+		},
+		{
+			DestP:   &l.graphiteConfig.BatchTimeout,
+			Flag:    "graphite-batch-timeout",
+			Default: graphite.DefaultBatchTimeout,
+			Desc:    "longest time a point received by the Graphite listener waits before it is written",
+		},
+		{
+			DestP:   &l.opentsdbConfig.BindAddress,
+			Flag:    "opentsdb-bind-address",
+			Default: "",
+			Desc:    "bind address of the OpenTSDB telnet and HTTP listener, for example :4242; the listener is disabled when empty",
+		},
+		{
+			DestP:   &l.opentsdbOrgID,
+			Flag:    "opentsdb-org-id",
+			Default: "",
+			Desc:    "ID of the organization the OpenTSDB listener writes to",
+		},
+		{
+			DestP:   &l.opentsdbBucketID,
+			Flag:    "opentsdb-bucket-id",
+			Default: "",
+			Desc:    "ID of the bucket the OpenTSDB listener writes to",
+		},
+		{
+			DestP:   &l.opentsdbConfig.BatchSize,
+			Flag:    "opentsdb-batch-size",
+			Default: opentsdb.DefaultBatchSize,
+			Desc:    "number of points the OpenTSDB listener writes at a time",
+		},
+		{
+			DestP:   &l.opentsdbConfig.BatchTimeout,
+			Flag:    "opentsdb-batch-timeout",
+			Default: opentsdb.DefaultBatchTimeout,
+			Desc:    "longest time a point received by the OpenTSDB listener waits before it is written",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -278,22 +405,39 @@ type Launcher struct {
 	testing              bool
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
+	writeStreamChunkSize int
+
+	graphiteConfig   graphite.Config
+	graphiteOrgID    string
+	graphiteBucketID string
+	graphiteService  *graphite.Service
+
+	opentsdbConfig   opentsdb.Config
+	opentsdbOrgID    string
+	opentsdbBucketID string
+	opentsdbService  *opentsdb.Service
 
 	logLevel          string
 	tracingType       string
 	reportingDisabled bool
 
-	httpBindAddress string
-	boltPath        string
-	enginePath      string
-	secretStore     string
+	httpBindAddress  string
+	boltPath         string
+	enginePath       string
+	replicationsPath string
+	secretStore      string
 
-	boltClient    *bolt.Client
-	kvService     *kv.Service
-	engine        Engine
-	StorageConfig storage.Config
+	boltClient         *bolt.Client
+	kvService          *kv.Service
+	engine             Engine
+	StorageConfig      storage.Config
+	replicationService *replications.Service
 
-	queryController *control.Controller
+	queryController          *control.Controller
+	queryOrgConcurrencyQuota int
+	queryOrgMemoryBytesQuota int
+	queryLogDisabled         bool
+	queryLogger              *readservice.QueryLogger
 
 	httpPort    int
 	httpServer  *nethttp.Server
@@ -371,6 +515,20 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 
 	m.scheduler.Stop()
 
+	if m.graphiteService != nil {
+		m.log.Info("Stopping", zap.String("service", "graphite"))
+		if err := m.graphiteService.Close(); err != nil {
+			m.log.Info("Failed closing graphite service", zap.Error(err))
+		}
+	}
+
+	if m.opentsdbService != nil {
+		m.log.Info("Stopping", zap.String("service", "opentsdb"))
+		if err := m.opentsdbService.Close(); err != nil {
+			m.log.Info("Failed closing opentsdb service", zap.Error(err))
+		}
+	}
+
 	m.log.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
 
@@ -384,6 +542,18 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.log.Info("Failed closing query service", zap.Error(err))
 	}
 
+	if m.queryLogger != nil {
+		m.log.Info("Stopping", zap.String("service", "query-log"))
+		if err := m.queryLogger.Close(); err != nil {
+			m.log.Info("Failed closing query logger", zap.Error(err))
+		}
+	}
+
+	m.log.Info("Stopping", zap.String("service", "replications"))
+	if err := m.replicationService.Close(); err != nil {
+		m.log.Error("Failed to close replication queues", zap.Error(err))
+	}
+
 	m.log.Info("Stopping", zap.String("service", "storage-engine"))
 	if err := m.engine.Close(); err != nil {
 		m.log.Error("Failed to close engine", zap.Error(err))
@@ -580,11 +750,29 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
 	var (
-		deleteService platform.DeleteService = m.engine
-		pointsWriter  storage.PointsWriter   = m.engine
-		backupService platform.BackupService = m.engine
+		deleteService  platform.DeleteService  = m.engine
+		pointsWriter   storage.PointsWriter    = m.engine
+		backupService  platform.BackupService  = m.engine
+		restoreService platform.RestoreService = m.engine
+		exportService  platform.ExportService  = m.engine
+		readStore      reads.Store             = readservice.NewStore(m.engine)
 	)
 
+	m.replicationService = replications.NewService(m.log.With(zap.String("service", "replications")), m.kvService, m.replicationsPath)
+	if err := m.replicationService.Open(ctx); err != nil {
+		m.log.Error("Failed to open replication queues", zap.Error(err))
+		return err
+	}
+	m.reg.MustRegister(m.replicationService.PrometheusCollectors()...)
+
+	// Points written to buckets with replications are queued to be written to remote instances.
+	pointsWriter = replications.NewPointsWriter(pointsWriter, m.replicationService)
+
+	// Points written to buckets with an explicit schema must conform to its measurement schemas.
+	// It caches the schemas of the buckets, so the schemas are changed through it.
+	schemaPointsWriter := storage.NewSchemaPointsWriter(pointsWriter, bucketSvc, m.kvService)
+	pointsWriter = schemaPointsWriter
+
 	// TODO(cwolff): Figure out a good default per-query memory limit:
 	//   https://github.com/influxdata/influxdb/issues/13642
 	const (
@@ -594,7 +782,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	)
 
 	deps, err := influxdb.NewDependencies(
-		reads.NewReader(readservice.NewStore(m.engine)),
+		reads.NewReader(readStore),
 		m.engine,
 		authorizer.NewBucketService(bucketSvc),
 		authorizer.NewOrgService(orgSvc),
@@ -610,6 +798,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		ConcurrencyQuota:         concurrencyQuota,
 		MemoryBytesQuotaPerQuery: int64(memoryBytesQuotaPerQuery),
 		QueueSize:                QueueSize,
+		OrgConcurrencyQuota:      m.queryOrgConcurrencyQuota,
+		OrgMemoryBytesQuota:      int64(m.queryOrgMemoryBytesQuota),
+		OrganizationService:      m.kvService,
 		Logger:                   m.log.With(zap.String("service", "storage-reads")),
 		ExecutorDependencies:     []flux.Dependency{deps},
 	})
@@ -621,6 +812,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
+	if !m.queryLogDisabled {
+		// Every query is logged to the queries system bucket of its organization.
+		log := m.log.With(zap.String("service", "query-log"))
+		m.queryLogger = readservice.NewQueryLogger(log, pointsWriter, m.kvService)
+		if err := m.queryLogger.Open(); err != nil {
+			m.log.Error("Failed to open query logger", zap.Error(err))
+			return err
+		}
+		storageQueryService = query.NewLoggingProxyQueryService(log, m.queryLogger, storageQueryService)
+	}
 	var taskSvc platform.TaskService
 	{
 		// create the task stack
@@ -761,24 +962,72 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		log.Info("Stopping")
 	}(m.log)
 
+	if m.graphiteConfig.BindAddress != "" {
+		if m.graphiteConfig.OrgID, m.graphiteConfig.BucketID, err = decodeOrgBucketIDs(m.graphiteOrgID, m.graphiteBucketID); err != nil {
+			m.log.Error("Invalid graphite configuration", zap.Error(err))
+			return err
+		}
+		svc, err := graphite.NewService(m.log.With(zap.String("service", "graphite")), pointsWriter, m.graphiteConfig)
+		if err != nil {
+			m.log.Error("Failed to create graphite service", zap.Error(err))
+			return err
+		}
+		if err := svc.Open(); err != nil {
+			m.log.Error("Failed to start graphite service", zap.Error(err))
+			return err
+		}
+		m.graphiteService = svc
+		m.reg.MustRegister(svc.PrometheusCollectors()...)
+	}
+
+	if m.opentsdbConfig.BindAddress != "" {
+		if m.opentsdbConfig.OrgID, m.opentsdbConfig.BucketID, err = decodeOrgBucketIDs(m.opentsdbOrgID, m.opentsdbBucketID); err != nil {
+			m.log.Error("Invalid opentsdb configuration", zap.Error(err))
+			return err
+		}
+		svc, err := opentsdb.NewService(m.log.With(zap.String("service", "opentsdb")), pointsWriter, m.opentsdbConfig)
+		if err != nil {
+			m.log.Error("Failed to create opentsdb service", zap.Error(err))
+			return err
+		}
+		if err := svc.Open(); err != nil {
+			m.log.Error("Failed to start opentsdb service", zap.Error(err))
+			return err
+		}
+		m.opentsdbService = svc
+		m.reg.MustRegister(svc.PrometheusCollectors()...)
+	}
+
 	m.httpServer = &nethttp.Server{
 		Addr: m.httpBindAddress,
 	}
 
 	m.apibackend = &http.APIBackend{
-		AssetsPath:           m.assetsPath,
-		HTTPErrorHandler:     kithttp.ErrorHandler(0),
-		Logger:               m.log,
-		SessionRenewDisabled: m.sessionRenewDisabled,
-		NewBucketService:     source.NewBucketService,
-		NewQueryService:      source.NewQueryService,
-		PointsWriter:         pointsWriter,
-		DeleteService:        deleteService,
-		BackupService:        backupService,
-		KVBackupService:      m.kvService,
-		AuthorizationService: authSvc,
+		AssetsPath:            m.assetsPath,
+		HTTPErrorHandler:      kithttp.ErrorHandler(0),
+		Logger:                m.log,
+		SessionRenewDisabled:  m.sessionRenewDisabled,
+		WriteStreamChunkSize:  m.writeStreamChunkSize,
+		NewBucketService:      source.NewBucketService,
+		NewQueryService:       source.NewQueryService,
+		PointsWriter:          pointsWriter,
+		ReadStore:             readStore,
+		DeleteService:         deleteService,
+		BackupService:         backupService,
+		KVBackupService:       m.kvService,
+		BackupManifestService: m.kvService,
+		RestoreService:        restoreService,
+		KVRestoreService:      m.kvService,
+		ExportService:         exportService,
+		AuthorizationService:  authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		BucketSchemaService:             schemaPointsWriter,
+		BucketCardinalityService:        m.engine,
+		ReplicationService:              m.replicationService,
+		ScriptService:                   m.kvService,
+		RunningQueryService:             m.queryController,
+		DBRPMappingService:              m.kvService,
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
@@ -793,7 +1042,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		VariableService:                 variableSvc,
 		PasswordsService:                passwdsSvc,
 		OnboardingService:               onboardingSvc,
-		InfluxQLService:                 nil, // No InfluxQL support
+		InfluxQLService:                 storageQueryService,
 		FluxService:                     storageQueryService,
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
@@ -821,12 +1070,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		pkgSVC = pkger.NewService(
 			pkger.WithLogger(pkgerLogger),
 			pkger.WithBucketSVC(authorizer.NewBucketService(b.BucketService)),
+			pkger.WithBucketSchemaSVC(authorizer.NewBucketSchemaService(b.BucketSchemaService)),
 			pkger.WithCheckSVC(authorizer.NewCheckService(b.CheckService, authedURMSVC, authedOrgSVC)),
 			pkger.WithDashboardSVC(authorizer.NewDashboardService(b.DashboardService)),
 			pkger.WithLabelSVC(authorizer.NewLabelService(b.LabelService)),
 			pkger.WithNotificationEndpointSVC(authorizer.NewNotificationEndpointService(b.NotificationEndpointService, authedURMSVC, authedOrgSVC)),
 			pkger.WithNotificationRuleSVC(authorizer.NewNotificationRuleStore(b.NotificationRuleStore, authedURMSVC, authedOrgSVC)),
 			pkger.WithSecretSVC(authorizer.NewSecretService(b.SecretService)),
+			pkger.WithScriptSVC(authorizer.NewScriptService(b.ScriptService)),
 			pkger.WithTaskSVC(authorizer.NewTaskService(pkgerLogger, b.TaskService)),
 			pkger.WithTelegrafSVC(authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)),
 			pkger.WithVariableSVC(authorizer.NewVariableService(b.VariableService)),
@@ -922,6 +1173,20 @@ func isAddressPortAvailable(address string, port int) (bool, error) {
 	return false, nil
 }
 
+// decodeOrgBucketIDs decodes the IDs of the organization and the bucket a
+// listener writes to.
+func decodeOrgBucketIDs(orgID, bucketID string) (platform.ID, platform.ID, error) {
+	org, err := platform.IDFromString(orgID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid organization ID %q: %v", orgID, err)
+	}
+	bucket, err := platform.IDFromString(bucketID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bucket ID %q: %v", bucketID, err)
+	}
+	return *org, *bucket, nil
+}
+
 // OrganizationService returns the internal organization service.
 func (m *Launcher) OrganizationService() platform.OrganizationService {
 	return m.apibackend.OrganizationService
@@ -82,6 +82,7 @@ func RunTestLauncherOrFail(tb testing.TB, ctx context.Context, args ...string) *
 func (tl *TestLauncher) Run(ctx context.Context, args ...string) error {
 	args = append(args, "--bolt-path", filepath.Join(tl.Path, bolt.DefaultFilename))
 	args = append(args, "--engine-path", filepath.Join(tl.Path, "engine"))
+	args = append(args, "--replications-path", filepath.Join(tl.Path, "replicationq"))
 	args = append(args, "--http-bind-address", "127.0.0.1:0")
 	args = append(args, "--log-level", "debug")
 	return tl.Launcher.Run(ctx, args...)
@@ -345,6 +346,11 @@ func (tl *TestLauncher) DashboardService(tb testing.TB) *http.DashboardService {
 	return &http.DashboardService{Client: tl.HTTPClient(tb)}
 }
 
+func (tl *TestLauncher) DBRPMappingService(tb testing.TB) *http.DBRPMappingService {
+	tb.Helper()
+	return &http.DBRPMappingService{Client: tl.HTTPClient(tb)}
+}
+
 func (tl *TestLauncher) LabelService(tb testing.TB) *http.LabelService {
 	tb.Helper()
 	return &http.LabelService{Client: tl.HTTPClient(tb)}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	nethttp "net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -405,3 +407,64 @@ from(bucket: "%s")
 		t.Fatal(err)
 	}
 }
+
+// This test maps a 1.x database to the bucket of the launcher, writes to it
+// and queries it through the 1.x endpoints.
+func TestPipeline_LegacyWrite_LegacyQuery(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	dbrpService := l.DBRPMappingService(t)
+	if err := dbrpService.Create(ctx, &influxdb.DBRPMapping{
+		Cluster:         "cluster",
+		Database:        "db0",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  l.Org.ID,
+		BucketID:        l.Bucket.ID,
+	}); err != nil {
+		t.Fatalf("failed to create dbrp mapping: %v", err)
+	}
+	m, err := dbrpService.FindBy(ctx, "cluster", "db0", "autogen")
+	if err != nil {
+		t.Fatalf("failed to find dbrp mapping: %v", err)
+	}
+	if m.BucketID != l.Bucket.ID {
+		t.Fatalf("unexpected bucket of dbrp mapping: %v", m.BucketID)
+	}
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", "/write?db=db0&precision=s", `cpu,host=a v=1 946684800`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("exp status %d; got %d, body: %s", nethttp.StatusNoContent, resp.StatusCode, body)
+	}
+
+	// the data written to the database is read from the bucket.
+	res := l.MustExecuteQuery(fmt.Sprintf(`from(bucket:"%s") |> range(start: 2000-01-01T00:00:00Z, stop: 2000-01-02T00:00:00Z)`, l.Bucket.Name))
+	defer res.Done()
+	res.HasTableCount(t, 1)
+
+	q := url.Values{}
+	q.Set("db", "db0")
+	q.Set("q", `SELECT v FROM cpu WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-01-02T00:00:00Z'`)
+	resp, err = nethttp.DefaultClient.Do(l.MustNewHTTPRequest("GET", "/query?"+q.Encode(), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("exp status %d; got %d, body: %s", nethttp.StatusOK, resp.StatusCode, body)
+	}
+	if want := `"values":[["2000-01-01T00:00:00Z",1]]`; !strings.Contains(string(body), want) {
+		t.Fatalf("unexpected query response, want it to contain %s: %s", want, body)
+	}
+}
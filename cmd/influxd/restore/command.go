@@ -1,19 +1,25 @@
 package restore
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/cmd/influxd/inspect"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/influxdata/influxdb/kit/cli"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 )
 
 var Command = &cobra.Command{
@@ -25,6 +31,14 @@ This command restores data and metadata from a backup fileset.
 Any existing metadata and data will be temporarily moved while restore runs
 and deleted after restore completes.
 
+An incremental backup is restored by giving the full backup it was taken
+from as the backup path, and every incremental backup from the full backup
+up to the incremental backup, in order, as incremental paths.
+
+The manifests and the files of backups with a manifest are verified before
+any existing data is moved. An encrypted backup requires its key, given with
+-encryption-key-file.
+
 Rebuilding the index and series file uses default options as in
 "influxd inspect build-tsi" with the given target engine path.
 For additional performance options, run restore with "-rebuild-index false"
@@ -44,9 +58,14 @@ var flags struct {
 	enginePath string
 	credPath   string
 	backupPath string
+	incPaths   []string
+	keyPath    string
 	rebuildTSI bool
 }
 
+// encryptionKey is the key the files of the backups are encrypted with.
+var encryptionKey []byte
+
 func init() {
 	dir, err := fs.InfluxDir()
 	if err != nil {
@@ -83,6 +102,16 @@ func init() {
 			Default: "",
 			Desc:    "path to backup files",
 		},
+		{
+			DestP: &flags.incPaths,
+			Flag:  "incremental-path",
+			Desc:  "paths to the incremental backups to restore on top of the backup, in the order they were taken",
+		},
+		{
+			DestP: &flags.keyPath,
+			Flag:  "encryption-key-file",
+			Desc:  "path to the file with the hex encoded key the backup files are encrypted with",
+		},
 		{
 			DestP:   &flags.rebuildTSI,
 			Flag:    "rebuild-index",
@@ -99,6 +128,25 @@ func restoreE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no backup path given")
 	}
 
+	if flags.keyPath != "" {
+		octets, err := ioutil.ReadFile(flags.keyPath)
+		if err != nil {
+			return fmt.Errorf("failed to read backup encryption key: %v", err)
+		}
+		if encryptionKey, err = influxdb.ParseBackupKey(string(octets)); err != nil {
+			return err
+		}
+	}
+
+	full, chain, err := loadChain()
+	if err != nil {
+		return err
+	}
+
+	if err := verifyChain(append([]backupLink{full}, chain...)); err != nil {
+		return fmt.Errorf("failed to verify backup: %v", err)
+	}
+
 	if err := moveBolt(); err != nil {
 		return fmt.Errorf("failed to move existing bolt file: %v", err)
 	}
@@ -111,18 +159,26 @@ func restoreE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to move existing engine data: %v", err)
 	}
 
-	if err := restoreBolt(); err != nil {
+	if err := restoreBolt(full); err != nil {
 		return fmt.Errorf("failed to restore bolt file: %v", err)
 	}
 
-	if err := restoreCred(); err != nil {
+	if err := replayKVChanges(chain); err != nil {
+		return fmt.Errorf("failed to replay meta data changes: %v", err)
+	}
+
+	if err := restoreCred(full, chain); err != nil {
 		return fmt.Errorf("failed to restore credentials file: %v", err)
 	}
 
-	if err := restoreEngine(); err != nil {
+	if err := restoreEngine(full); err != nil {
 		return fmt.Errorf("failed to restore all TSM files: %v", err)
 	}
 
+	if err := restoreIncrementalEngine(chain); err != nil {
+		return fmt.Errorf("failed to restore incremental TSM files: %v", err)
+	}
+
 	if flags.rebuildTSI {
 		sFilePath := filepath.Join(flags.enginePath, storage.DefaultSeriesFileDirectoryName)
 		indexPath := filepath.Join(flags.enginePath, storage.DefaultIndexDirectoryName)
@@ -215,10 +271,10 @@ func removeIfExists(path string) error {
 	}
 }
 
-func restoreBolt() error {
-	backupBolt := filepath.Join(flags.backupPath, bolt.DefaultFilename)
+func restoreBolt(full backupLink) error {
+	backupBolt := filepath.Join(full.path, bolt.DefaultFilename)
 
-	if err := restoreFile(backupBolt, flags.boltPath, "bolt"); err != nil {
+	if err := restoreFile(full, bolt.DefaultFilename, flags.boltPath, "bolt"); err != nil {
 		return err
 	}
 
@@ -226,15 +282,25 @@ func restoreBolt() error {
 	return nil
 }
 
-func restoreEngine() error {
+func restoreEngine(full backupLink) error {
 	dataDir := filepath.Join(flags.enginePath, "/data")
 	if err := os.MkdirAll(dataDir, 0777); err != nil {
 		return err
 	}
 
+	if full.manifest != nil {
+		for _, f := range full.manifest.Files {
+			if err := restoreFile(full, f.Name, filepath.Join(dataDir, f.Name), "engine"); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Restored %d TSM files to %v\n", len(full.manifest.Files), dataDir)
+		return nil
+	}
+
 	count := 0
 	err := filepath.Walk(flags.backupPath, func(path string, info os.FileInfo, err error) error {
-		if strings.Contains(path, ".tsm") {
+		if isEngineFile(path) {
 			f, err := os.OpenFile(path, os.O_RDONLY, 0666)
 			if err != nil {
 				return fmt.Errorf("error opening TSM file: %v", err)
@@ -261,8 +327,8 @@ func restoreEngine() error {
 	return err
 }
 
-func restoreFile(backup string, target string, filetype string) error {
-	f, err := os.Open(backup)
+func restoreFile(link backupLink, name string, target string, filetype string) error {
+	f, err := link.open(name)
 	if err != nil {
 		return fmt.Errorf("no %s file in backup: %v", filetype, err)
 	}
@@ -281,13 +347,194 @@ func restoreFile(backup string, target string, filetype string) error {
 	return err
 }
 
-func restoreCred() error {
-	backupCred := filepath.Join(flags.backupPath, http.DefaultTokenFile)
+func restoreCred(full backupLink, chain []backupLink) error {
+	// the credentials are restored from the latest backup of the chain.
+	link := full
+	if n := len(chain); n > 0 {
+		link = chain[n-1]
+	}
+	backupCred := filepath.Join(link.path, http.DefaultTokenFile)
 
-	if err := restoreFile(backupCred, flags.credPath, "credentials"); err != nil {
+	if err := restoreFile(link, http.DefaultTokenFile, flags.credPath, "credentials"); err != nil {
 		return err
 	}
 
 	fmt.Printf("Restored credentials to %s from %s\n", flags.credPath, backupCred)
 	return nil
 }
+
+// isEngineFile returns true if path is a TSM file or a tombstone file.
+func isEngineFile(path string) bool {
+	return strings.Contains(path, "."+tsm1.TSMFileExtension) || strings.HasSuffix(path, ".tombstone")
+}
+
+// backupLink is a backup of a chain of backups to restore. The manifest of a
+// full backup taken without a manifest is nil.
+type backupLink struct {
+	path     string
+	manifest *influxdb.BackupManifest
+}
+
+// open opens the file name of the backup. The files of a backup with a
+// manifest are decoded, and verified against the manifest as they are read.
+func (l backupLink) open(name string) (io.ReadCloser, error) {
+	if l.manifest == nil {
+		return os.Open(filepath.Join(l.path, name))
+	}
+
+	files := append([]influxdb.BackupFile(nil), l.manifest.Files...)
+	for _, f := range []*influxdb.BackupFile{l.manifest.KV, l.manifest.Credentials} {
+		if f != nil {
+			files = append(files, *f)
+		}
+	}
+	for _, f := range files {
+		if f.Name == name && f.Included {
+			return influxdb.OpenBackupFile(l.path, l.manifest, f, encryptionKey)
+		}
+	}
+	return nil, fmt.Errorf("file %s is not part of backup %s", name, l.manifest.ID)
+}
+
+// loadChain reads the manifests of the full backup and of the incremental
+// backups to restore, and verifies that each one was taken from the backup
+// before it.
+func loadChain() (backupLink, []backupLink, error) {
+	full := backupLink{path: flags.backupPath}
+	_, err := os.Stat(filepath.Join(flags.backupPath, influxdb.BackupManifestFilename))
+	if os.IsNotExist(err) && len(flags.incPaths) == 0 {
+		fmt.Printf("Backup %s has no manifest, its files are not verified\n", flags.backupPath)
+		return full, nil, nil
+	}
+	m, err := readManifest(flags.backupPath)
+	if err != nil {
+		return full, nil, err
+	}
+	if m.Incremental() {
+		return full, nil, fmt.Errorf("backup %s at %s is incremental; the full backup it was taken from is required", m.ID, flags.backupPath)
+	}
+	full.manifest = m
+
+	parent := m
+	chain := make([]backupLink, 0, len(flags.incPaths))
+	for _, path := range flags.incPaths {
+		m, err := readManifest(path)
+		if err != nil {
+			return full, nil, err
+		}
+		if !m.Incremental() {
+			return full, nil, fmt.Errorf("backup %s at %s is not incremental", m.ID, path)
+		}
+		if *m.ParentID != parent.ID {
+			return full, nil, fmt.Errorf("backup %s at %s was taken from backup %s, not from backup %s", m.ID, path, *m.ParentID, parent.ID)
+		}
+		chain = append(chain, backupLink{path: path, manifest: m})
+		parent = m
+	}
+	return full, chain, nil
+}
+
+// verifyChain verifies the manifests of the backups of chain, and reads all
+// of their files to verify them against the manifests.
+func verifyChain(chain []backupLink) error {
+	for _, link := range chain {
+		if link.manifest == nil {
+			continue
+		}
+		if err := link.manifest.Verify(encryptionKey); err != nil {
+			return err
+		}
+		if err := influxdb.VerifyBackupFiles(link.path, link.manifest, encryptionKey); err != nil {
+			return err
+		}
+		fmt.Printf("Verified backup %s at %s\n", link.manifest.ID, link.path)
+	}
+	return nil
+}
+
+func readManifest(path string) (*influxdb.BackupManifest, error) {
+	f, err := os.Open(filepath.Join(path, influxdb.BackupManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("no manifest in backup %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var m influxdb.BackupManifest
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of backup %s: %v", path, err)
+	}
+	return &m, nil
+}
+
+// replayKVChanges replays the meta data changes of the incremental backups
+// on top of the restored bolt file.
+func replayKVChanges(chain []backupLink) error {
+	if len(chain) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	store := bolt.NewKVStore(zap.NewNop(), flags.boltPath)
+	if err := store.Open(ctx); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	for _, link := range chain {
+		f, err := link.open(influxdb.BackupKVChangesFilename)
+		if err != nil {
+			return fmt.Errorf("no meta data changes in backup %s: %v", link.path, err)
+		}
+		n, err := store.ReplayChanges(ctx, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("backup %s: %v", link.path, err)
+		}
+		fmt.Printf("Replayed %d meta data changes from %s\n", n, link.path)
+	}
+	return store.Close()
+}
+
+// restoreIncrementalEngine applies the engine files of the incremental
+// backups on top of the restored engine files. The files which are no longer
+// part of the engine at the time of a backup, such as the files replaced by
+// a compaction, are removed.
+func restoreIncrementalEngine(chain []backupLink) error {
+	dataDir := filepath.Join(flags.enginePath, "/data")
+	for _, link := range chain {
+		var copied int
+		want := make(map[string]bool, len(link.manifest.Files))
+		for _, f := range link.manifest.Files {
+			want[f.Name] = true
+
+			target := filepath.Join(dataDir, f.Name)
+			if !f.Included {
+				if _, err := os.Stat(target); err != nil {
+					return fmt.Errorf("file %s of backup %s is missing from the backups before it: %v", f.Name, link.path, err)
+				}
+				continue
+			}
+			if err := restoreFile(link, f.Name, target, "engine"); err != nil {
+				return err
+			}
+			copied++
+		}
+
+		entries, err := ioutil.ReadDir(dataDir)
+		if err != nil {
+			return err
+		}
+		var removed int
+		for _, fi := range entries {
+			if !isEngineFile(fi.Name()) || want[fi.Name()] {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dataDir, fi.Name())); err != nil {
+				return err
+			}
+			removed++
+		}
+		fmt.Printf("Restored %d and removed %d TSM files from %s\n", copied, removed, link.path)
+	}
+	return nil
+}
@@ -868,6 +868,11 @@ type DashboardQuery struct {
 	EditMode      string        `json:"editMode"` // Either "builder" or "advanced"
 	Name          string        `json:"name"`     // Term or phrase that refers to the query
 	BuilderConfig BuilderConfig `json:"builderConfig"`
+
+	// Params are the parameters which are bound to the params record of
+	// the query when the cell is queried, encoded as the params of a query
+	// request.
+	Params json.RawMessage `json:"params,omitempty"`
 }
 
 type BuilderConfig struct {
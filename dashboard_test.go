@@ -55,6 +55,67 @@ func TestView_MarshalJSON(t *testing.T) {
 		"timeFormat": ""
   }
 }
+`,
+			},
+		},
+		{
+			name: "query with params",
+			args: args{
+				view: platform.View{
+					ViewContents: platform.ViewContents{
+						ID:   platformtesting.MustIDBase16("f01dab1ef005ba11"),
+						Name: "hello",
+					},
+					Properties: platform.XYViewProperties{
+						Type: "xy",
+						Queries: []platform.DashboardQuery{
+							{
+								Text:     `from(bucket: params.bucket) |> range(start: -params.window)`,
+								EditMode: "advanced",
+								Params:   json.RawMessage(`{"bucket":"telegraf","window":{"type":"duration","value":"1h"}}`),
+							},
+						},
+					},
+				},
+			},
+			wants: wants{
+				json: `
+{
+  "id": "f01dab1ef005ba11",
+  "name": "hello",
+  "properties": {
+    "shape": "chronograf-v2",
+    "queries": [
+      {
+        "text": "from(bucket: params.bucket) |> range(start: -params.window)",
+        "editMode": "advanced",
+        "name": "",
+        "builderConfig": {
+          "buckets": [],
+          "tags": [],
+          "functions": [],
+          "aggregateWindow": {"period": ""}
+        },
+        "params": {
+          "bucket": "telegraf",
+          "window": {"type": "duration", "value": "1h"}
+        }
+      }
+    ],
+    "axes": null,
+    "type": "xy",
+    "colors": null,
+    "legend": {},
+    "geom": "",
+    "note": "",
+    "showNoteWhenEmpty": false,
+    "xColumn": "",
+    "yColumn": "",
+    "shadeBelow": false,
+    "position": "",
+    "timeFormat": ""
+  }
+}
 `,
 			},
 		},
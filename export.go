@@ -0,0 +1,53 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+	"math"
+)
+
+// ExportRequest selects the points of a bucket to export.
+type ExportRequest struct {
+	OrgID    ID `json:"orgID"`
+	BucketID ID `json:"bucketID"`
+	// Start and Stop are the time range of the points to export, in
+	// nanoseconds since the epoch. Both are inclusive.
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+	// Measurement only exports the points of the measurement, if not empty.
+	Measurement string `json:"measurement,omitempty"`
+}
+
+// NewExportRequest returns a request to export all of the points of a bucket.
+func NewExportRequest(orgID, bucketID ID) ExportRequest {
+	return ExportRequest{
+		OrgID:    orgID,
+		BucketID: bucketID,
+		Start:    math.MinInt64,
+		Stop:     math.MaxInt64,
+	}
+}
+
+// Valid returns an error if the request can not select any point.
+func (r ExportRequest) Valid() error {
+	if !r.OrgID.Valid() || !r.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "organization and bucket are required to export points",
+		}
+	}
+	if r.Start > r.Stop {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "start of the time range to export must not be after its stop",
+		}
+	}
+	return nil
+}
+
+// ExportService represents the data export functions of InfluxDB.
+type ExportService interface {
+	// ExportLineProtocol writes the points of the bucket of req to w as line
+	// protocol, series by series. Deleted points are not exported.
+	ExportLineProtocol(ctx context.Context, req ExportRequest, w io.Writer) error
+}
@@ -0,0 +1,125 @@
+package graphite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+)
+
+// Default configuration values.
+const (
+	DefaultBindAddress  = ":2003"
+	DefaultProtocol     = "tcp"
+	DefaultSeparator    = "."
+	DefaultBatchSize    = 5000
+	DefaultBatchPending = 10
+	DefaultBatchTimeout = time.Second
+)
+
+// Config holds the configuration of a Graphite Service.
+type Config struct {
+	// BindAddress is the address the service listens on.
+	BindAddress string
+
+	// Protocol is either "tcp" or "udp".
+	Protocol string
+
+	// OrgID and BucketID identify the bucket points are written to.
+	OrgID    influxdb.ID
+	BucketID influxdb.ID
+
+	// Separator joins the parts of a metric name which map to the same
+	// measurement, field or tag.
+	Separator string
+
+	// Templates map metric names to a measurement, tags and a field. See
+	// Options for their format.
+	Templates []string
+
+	// Tags are added to every point, in the format "key=value".
+	Tags []string
+
+	// BatchSize is the number of points written to storage at a time.
+	BatchSize int
+
+	// BatchPending is the number of points which are buffered before the
+	// listener stops reading.
+	BatchPending int
+
+	// BatchTimeout is the longest a point waits before it is written.
+	BatchTimeout time.Duration
+}
+
+// WithDefaults returns a copy of c in which every unset value is set to its
+// default.
+func (c Config) WithDefaults() Config {
+	d := c
+	if d.BindAddress == "" {
+		d.BindAddress = DefaultBindAddress
+	}
+	if d.Protocol == "" {
+		d.Protocol = DefaultProtocol
+	}
+	if d.Separator == "" {
+		d.Separator = DefaultSeparator
+	}
+	if d.BatchSize == 0 {
+		d.BatchSize = DefaultBatchSize
+	}
+	if d.BatchPending == 0 {
+		d.BatchPending = DefaultBatchPending
+	}
+	if d.BatchTimeout == 0 {
+		d.BatchTimeout = DefaultBatchTimeout
+	}
+	return d
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c Config) Validate() error {
+	switch strings.ToLower(c.Protocol) {
+	case "tcp", "udp":
+	default:
+		return fmt.Errorf("invalid protocol %q, must be tcp or udp", c.Protocol)
+	}
+	if !c.OrgID.Valid() {
+		return fmt.Errorf("an organization ID is required")
+	}
+	if !c.BucketID.Valid() {
+		return fmt.Errorf("a bucket ID is required")
+	}
+	if _, err := c.DefaultTags(); err != nil {
+		return err
+	}
+	_, err := c.parser()
+	return err
+}
+
+// DefaultTags returns the tags added to every point.
+func (c Config) DefaultTags() (models.Tags, error) {
+	var tags models.Tags
+	for _, kv := range c.Tags {
+		parts := strings.Split(kv, "=")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid tag %q, must be in the format key=value", kv)
+		}
+		tags.SetString(parts[0], parts[1])
+	}
+	return tags, nil
+}
+
+// parser returns the parser of the metrics received by the service.
+func (c Config) parser() (*Parser, error) {
+	tags, err := c.DefaultTags()
+	if err != nil {
+		return nil, err
+	}
+	return NewParserWithOptions(Options{
+		Separator:   c.Separator,
+		Templates:   c.Templates,
+		DefaultTags: tags,
+	})
+}
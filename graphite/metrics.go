@@ -0,0 +1,73 @@
+package graphite
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// namespace is the leading part of all published metrics for the Graphite
+// service.
+const namespace = "graphite"
+
+// metrics is a set of metrics concerned with the metrics received by the
+// service.
+type metrics struct {
+	Connections       prometheus.Gauge
+	ConnectionsTotal  prometheus.Counter
+	PointsReceived    prometheus.Counter
+	ParseErrors       prometheus.Counter
+	Batches           *prometheus.CounterVec
+	PointsWritten     prometheus.Counter
+	BatchWriteSeconds prometheus.Histogram
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		Connections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "connections_active",
+			Help:      "Number of open TCP connections.",
+		}),
+		ConnectionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "connections_total",
+			Help:      "Number of TCP connections accepted.",
+		}),
+		PointsReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "points_received_total",
+			Help:      "Number of points parsed from the received metrics.",
+		}),
+		ParseErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "parse_errors_total",
+			Help:      "Number of received metrics which could not be parsed.",
+		}),
+		Batches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "batches_total",
+			Help:      "Number of batches of points written to storage.",
+		}, []string{"status"}),
+		PointsWritten: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "points_written_total",
+			Help:      "Number of points written to storage.",
+		}),
+		BatchWriteSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_write_duration_seconds",
+			Help:      "Time taken to write a batch of points to storage.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *metrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.Connections,
+		m.ConnectionsTotal,
+		m.PointsReceived,
+		m.ParseErrors,
+		m.Batches,
+		m.PointsWritten,
+		m.BatchWriteSeconds,
+	}
+}
@@ -0,0 +1,392 @@
+package graphite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+var (
+	// MinDate is the minimum timestamp of a metric.
+	MinDate = time.Date(1901, 12, 13, 0, 0, 0, 0, time.UTC)
+
+	// MaxDate is the maximum timestamp of a metric.
+	MaxDate = time.Date(2038, 1, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// defaultTemplate writes the whole metric name to the measurement.
+var defaultTemplate = &template{
+	parts:             []string{"measurement*"},
+	greedyMeasurement: true,
+	separator:         DefaultSeparator,
+}
+
+// UnsupportedValueError is returned when a metric has a value which cannot be
+// written, such as NaN.
+type UnsupportedValueError struct {
+	Field string
+	Value float64
+}
+
+func (err *UnsupportedValueError) Error() string {
+	return fmt.Sprintf(`field "%s" value: "%v" is unsupported`, err.Field, err.Value)
+}
+
+// Options are the options of a Parser.
+type Options struct {
+	// Separator joins the parts of the metric name which are written to the
+	// same measurement, field or tag. It defaults to DefaultSeparator.
+	Separator string
+
+	// Templates map metric names to a measurement, tags and a field. Each
+	// template has the format "[filter] <template> [tag1=value1,tag2=value2]".
+	Templates []string
+
+	// DefaultTags are added to every point which does not already have them.
+	DefaultTags models.Tags
+}
+
+// Parser parses lines of the Graphite plaintext protocol into points.
+type Parser struct {
+	matcher *matcher
+	tags    models.Tags
+}
+
+// NewParser returns a Parser which uses the given templates and default tags.
+func NewParser(templates []string, defaultTags models.Tags) (*Parser, error) {
+	return NewParserWithOptions(Options{
+		Templates:   templates,
+		DefaultTags: defaultTags,
+		Separator:   DefaultSeparator,
+	})
+}
+
+// NewParserWithOptions returns a Parser with the given options. An error is
+// returned if any of the templates is invalid.
+func NewParserWithOptions(options Options) (*Parser, error) {
+	if options.Separator == "" {
+		options.Separator = DefaultSeparator
+	}
+
+	m := newMatcher()
+	m.AddDefaultTemplate(defaultTemplate)
+
+	filters := make(map[string]struct{})
+	for _, pattern := range options.Templates {
+		// format is [filter] <template> [tag1=value1,tag2=value2]
+		parts := strings.Fields(pattern)
+		if len(parts) < 1 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid template format: %q", pattern)
+		}
+
+		var filter, tmpl, tagSet string
+		switch len(parts) {
+		case 1:
+			tmpl = parts[0]
+		case 2:
+			if strings.Contains(parts[1], "=") {
+				tmpl, tagSet = parts[0], parts[1]
+			} else {
+				filter, tmpl = parts[0], parts[1]
+			}
+		case 3:
+			filter, tmpl, tagSet = parts[0], parts[1], parts[2]
+		}
+
+		if _, ok := filters[filter]; ok {
+			return nil, fmt.Errorf("duplicate filter %q found in template: %q", filter, pattern)
+		}
+		filters[filter] = struct{}{}
+
+		var tags models.Tags
+		if tagSet != "" {
+			for _, kv := range strings.Split(tagSet, ",") {
+				parts := strings.Split(kv, "=")
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return nil, fmt.Errorf("invalid template tags: %q", pattern)
+				}
+				tags.SetString(parts[0], parts[1])
+			}
+		}
+
+		t, err := newTemplate(tmpl, tags, options.Separator)
+		if err != nil {
+			return nil, err
+		}
+		m.Add(filter, t)
+	}
+
+	return &Parser{matcher: m, tags: options.DefaultTags}, nil
+}
+
+// Parse parses a line of the Graphite plaintext protocol, which has the format
+// "<metric name> <value> [timestamp]". The timestamp is in seconds since the
+// epoch, and the current time is used when it is missing or -1.
+func (p *Parser) Parse(line string) (models.Point, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 && len(fields) != 3 {
+		return nil, fmt.Errorf("received %q which doesn't have required fields", line)
+	}
+
+	measurement, tags, field, err := p.ApplyTemplate(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf(`field "%s" value: %s`, fields[0], err)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, &UnsupportedValueError{Field: fields[0], Value: v}
+	}
+
+	if field == "" {
+		field = "value"
+	}
+
+	timestamp := time.Now().UTC()
+	if len(fields) == 3 {
+		unixTime, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf(`field "%s" time: %s`, fields[0], err)
+		}
+
+		// -1 is a special value which means the current time.
+		if unixTime != -1 {
+			sec, frac := math.Modf(unixTime)
+			timestamp = time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC()
+			if timestamp.Before(MinDate) || timestamp.After(MaxDate) {
+				return nil, fmt.Errorf("timestamp out of range")
+			}
+		}
+	}
+
+	return models.NewPoint(measurement, models.NewTags(tags), models.Fields{field: v}, timestamp)
+}
+
+// ApplyTemplate returns the measurement, tags and field of the metric with the
+// given name. The whole name is used as the measurement when the matching
+// template does not produce one.
+func (p *Parser) ApplyTemplate(name string) (string, map[string]string, string, error) {
+	t := p.matcher.Match(name)
+	measurement, tags, field, err := t.Apply(name)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if measurement == "" {
+		measurement = name
+	}
+
+	// the default tags of the parser do not override tags of the template.
+	for _, tag := range p.tags {
+		if _, ok := tags[string(tag.Key)]; !ok {
+			tags[string(tag.Key)] = string(tag.Value)
+		}
+	}
+	return measurement, tags, field, nil
+}
+
+// template maps the dot separated parts of a metric name to a measurement,
+// tags and a field.
+type template struct {
+	parts             []string
+	defaultTags       models.Tags
+	greedyField       bool
+	greedyMeasurement bool
+	separator         string
+}
+
+func newTemplate(pattern string, defaultTags models.Tags, separator string) (*template, error) {
+	t := &template{
+		parts:       strings.Split(pattern, "."),
+		defaultTags: defaultTags,
+		separator:   separator,
+	}
+
+	var hasMeasurement bool
+	for _, part := range t.parts {
+		if strings.HasPrefix(part, "measurement") {
+			hasMeasurement = true
+		}
+		switch part {
+		case "measurement*":
+			t.greedyMeasurement = true
+		case "field*":
+			t.greedyField = true
+		}
+	}
+	if !hasMeasurement {
+		return nil, fmt.Errorf("no measurement specified for template: %q", pattern)
+	}
+	if t.greedyField && t.greedyMeasurement {
+		return nil, fmt.Errorf("either 'field*' or 'measurement*' can be used in each template (but not both together): %q", pattern)
+	}
+	return t, nil
+}
+
+// Apply returns the measurement, tags and field of the metric with the given
+// name. Parts of the name which map to the same measurement, field or tag are
+// joined with the separator of the template.
+func (t *template) Apply(name string) (string, map[string]string, string, error) {
+	fields := strings.Split(name, ".")
+
+	var (
+		measurement []string
+		field       []string
+		tags        = make(map[string][]string)
+	)
+	for _, tag := range t.defaultTags {
+		tags[string(tag.Key)] = append(tags[string(tag.Key)], string(tag.Value))
+	}
+
+	for i, part := range t.parts {
+		if i >= len(fields) {
+			break
+		}
+
+		switch part {
+		case "measurement":
+			measurement = append(measurement, fields[i])
+		case "field":
+			field = append(field, fields[i])
+		case "measurement*":
+			measurement = append(measurement, fields[i:]...)
+		case "field*":
+			field = append(field, fields[i:]...)
+		case "":
+			// the part of the name is skipped.
+		default:
+			tags[part] = append(tags[part], fields[i])
+		}
+		if part == "measurement*" || part == "field*" {
+			break
+		}
+	}
+
+	out := make(map[string]string, len(tags))
+	for k, values := range tags {
+		out[k] = strings.Join(values, t.separator)
+	}
+	return strings.Join(measurement, t.separator), out, strings.Join(field, t.separator), nil
+}
+
+// matcher determines which template applies to a metric based on a filter
+// tree.
+type matcher struct {
+	root            *node
+	defaultTemplate *template
+}
+
+func newMatcher() *matcher {
+	return &matcher{root: &node{}}
+}
+
+// Add inserts the template in the filter tree. A template without a filter
+// replaces the default template.
+func (m *matcher) Add(filter string, t *template) {
+	if filter == "" {
+		m.AddDefaultTemplate(t)
+		return
+	}
+	m.root.Insert(filter, t)
+}
+
+// AddDefaultTemplate sets the template used by metrics which match no filter.
+func (m *matcher) AddDefaultTemplate(t *template) {
+	m.defaultTemplate = t
+}
+
+// Match returns the template which matches the metric name, or the default
+// template when no filter matches it.
+func (m *matcher) Match(name string) *template {
+	if t := m.root.Search(name); t != nil {
+		return t
+	}
+	return m.defaultTemplate
+}
+
+// node is an item in a sorted k-ary tree of filters. The children of a node
+// are sorted by their value, with the wildcard "*" always last.
+type node struct {
+	value    string
+	children nodes
+	template *template
+}
+
+// Insert inserts the template into the tree at the path of the dot separated
+// parts of filter.
+func (n *node) Insert(filter string, t *template) {
+	n.insert(strings.Split(filter, "."), t)
+}
+
+func (n *node) insert(values []string, t *template) {
+	if len(values) == 0 {
+		n.template = t
+		return
+	}
+
+	for _, child := range n.children {
+		if child.value == values[0] {
+			child.insert(values[1:], t)
+			return
+		}
+	}
+
+	child := &node{value: values[0]}
+	n.children = append(n.children, child)
+	sort.Sort(n.children)
+	child.insert(values[1:], t)
+}
+
+// Search returns the template of the most specific filter which matches the
+// metric name, or nil if none do.
+func (n *node) Search(name string) *template {
+	return n.search(strings.Split(name, "."))
+}
+
+func (n *node) search(parts []string) *template {
+	if len(parts) == 0 || len(n.children) == 0 {
+		return n.template
+	}
+
+	// the wildcard is sorted last, which is not its lexicographic position, so
+	// it is left out of the binary search.
+	length := len(n.children)
+	wildcard := n.children[length-1].value == "*"
+	if wildcard {
+		length--
+	}
+
+	i := sort.Search(length, func(i int) bool {
+		return n.children[i].value >= parts[0]
+	})
+	if i < length && n.children[i].value == parts[0] {
+		return n.children[i].search(parts[1:])
+	}
+	if wildcard {
+		return n.children[len(n.children)-1].search(parts[1:])
+	}
+	return n.template
+}
+
+type nodes []*node
+
+func (n nodes) Len() int      { return len(n) }
+func (n nodes) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+
+// Less sorts the nodes by value, with the wildcard last.
+func (n nodes) Less(i, j int) bool {
+	if n[i].value == "*" {
+		return false
+	}
+	if n[j].value == "*" {
+		return true
+	}
+	return n[i].value < n[j].value
+}
@@ -0,0 +1,187 @@
+package graphite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/graphite"
+	"github.com/influxdata/influxdb/models"
+)
+
+func TestParser_ApplyTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		templates   []string
+		separator   string
+		input       string
+		measurement string
+		tags        map[string]string
+		field       string
+	}{
+		{
+			name:        "default template uses the whole name",
+			input:       "servers.localhost.cpu_load",
+			measurement: "servers.localhost.cpu_load",
+			tags:        map[string]string{},
+		},
+		{
+			name:        "tags and measurement",
+			templates:   []string{"region.host.measurement"},
+			input:       "us-west.server01.cpu",
+			measurement: "cpu",
+			tags:        map[string]string{"region": "us-west", "host": "server01"},
+		},
+		{
+			name:        "greedy measurement with separator",
+			templates:   []string{"region.measurement*"},
+			separator:   "_",
+			input:       "us-west.cpu.load.shortterm",
+			measurement: "cpu_load_shortterm",
+			tags:        map[string]string{"region": "us-west"},
+		},
+		{
+			name:        "field",
+			templates:   []string{"host.measurement.field"},
+			input:       "server01.cpu.idle",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "server01"},
+			field:       "idle",
+		},
+		{
+			name:        "greedy field",
+			templates:   []string{"host.measurement.field*"},
+			input:       "server01.disk.bytes.free",
+			measurement: "disk",
+			tags:        map[string]string{"host": "server01"},
+			field:       "bytes.free",
+		},
+		{
+			name:        "repeated tag is joined",
+			templates:   []string{"measurement.host.host"},
+			input:       "cpu.server01.example",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "server01.example"},
+		},
+		{
+			name:        "skipped parts",
+			templates:   []string{"..measurement.host"},
+			input:       "stats.prod.cpu.server01",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "server01"},
+		},
+		{
+			name: "filter selects the template",
+			templates: []string{
+				"stats.* .measurement.host region=us-east",
+				"stats.prod.* ..measurement.host",
+				"measurement*",
+			},
+			input:       "stats.prod.cpu.server01",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "server01"},
+		},
+		{
+			name: "wildcard filter and template tags",
+			templates: []string{
+				"stats.* .measurement.host region=us-east",
+				"stats.prod.* ..measurement.host",
+			},
+			input:       "stats.cpu.server01",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "server01", "region": "us-east"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := graphite.NewParserWithOptions(graphite.Options{
+				Templates: tt.templates,
+				Separator: tt.separator,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			measurement, tags, field, err := p.ApplyTemplate(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if measurement != tt.measurement {
+				t.Errorf("unexpected measurement; got %q, want %q", measurement, tt.measurement)
+			}
+			if !cmp.Equal(tt.tags, tags) {
+				t.Errorf("unexpected tags -want/+got:\n%s", cmp.Diff(tt.tags, tags))
+			}
+			if field != tt.field {
+				t.Errorf("unexpected field; got %q, want %q", field, tt.field)
+			}
+		})
+	}
+}
+
+func TestNewParserWithOptions_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+	}{
+		{name: "no measurement", templates: []string{"host.field"}},
+		{name: "greedy measurement and field", templates: []string{"measurement*.field*"}},
+		{name: "duplicate filter", templates: []string{"cpu.* measurement.host", "cpu.* measurement.region"}},
+		{name: "invalid tags", templates: []string{"measurement.host region"}},
+		{name: "too many parts", templates: []string{"cpu.* measurement a=b c=d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := graphite.NewParser(tt.templates, nil); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParser_Parse(t *testing.T) {
+	p, err := graphite.NewParser(
+		[]string{"host.measurement.field"},
+		models.NewTags(map[string]string{"dc": "eu", "host": "ignored"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pt, err := p.Parse("server01.cpu.idle 50.5 1590000000.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"dc": "eu", "host": "server01"}),
+		models.Fields{"idle": 50.5},
+		time.Unix(1590000000, int64(500*time.Millisecond)),
+	)
+	if got := pt.String(); got != want.String() {
+		t.Errorf("unexpected point; got %s, want %s", got, want.String())
+	}
+
+	for _, line := range []string{
+		"server01.cpu.idle",
+		"server01.cpu.idle abc 1590000000",
+		"server01.cpu.idle NaN 1590000000",
+		"server01.cpu.idle 1 abc",
+		"server01.cpu.idle 1 99999999999",
+	} {
+		if _, err := p.Parse(line); err == nil {
+			t.Errorf("expected an error parsing %q", line)
+		}
+	}
+
+	// a missing timestamp or -1 is the current time.
+	for _, line := range []string{"server01.cpu.idle 1", "server01.cpu.idle 1 -1"} {
+		pt, err := p.Parse(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := time.Since(pt.Time()); d < 0 || d > time.Minute {
+			t.Errorf("unexpected time %v parsing %q", pt.Time(), line)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Package graphite implements a listener for the Graphite plaintext protocol.
+//
+// Every line received has the format "<metric name> <value> [timestamp]".
+// The dot separated parts of the metric name are mapped to a measurement, tags
+// and a field by templates, and the resulting points are written to a single
+// bucket in batches.
+package graphite
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// udpBufferSize is the largest UDP packet which can be read.
+const udpBufferSize = 65536
+
+// Service receives metrics in the Graphite plaintext protocol over TCP or UDP
+// and writes them to storage.
+type Service struct {
+	config       Config
+	parser       *Parser
+	pointsWriter storage.PointsWriter
+	batcher      *storage.PointBatcher
+	metrics      *metrics
+	log          *zap.Logger
+
+	mu       sync.Mutex
+	ln       net.Listener
+	conn     net.PacketConn
+	conns    map[net.Conn]struct{}
+	closed   bool
+	closing  chan struct{}
+	wg       sync.WaitGroup // listeners and connections
+	writesWg sync.WaitGroup // batch writer
+}
+
+// NewService returns a Service which writes the metrics it receives to pw.
+func NewService(log *zap.Logger, pw storage.PointsWriter, c Config) (*Service, error) {
+	c = c.WithDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	parser, err := c.parser()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		config:       c,
+		parser:       parser,
+		pointsWriter: pw,
+		metrics:      newMetrics(),
+		log:          log,
+	}, nil
+}
+
+// Open starts listening on the bind address.
+func (s *Service) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing != nil {
+		return errors.New("graphite service already open")
+	}
+
+	s.closed = false
+	s.batcher = storage.NewPointBatcher(s.config.BatchSize, s.config.BatchPending, s.config.BatchTimeout)
+
+	switch strings.ToLower(s.config.Protocol) {
+	case "tcp":
+		ln, err := net.Listen("tcp", s.config.BindAddress)
+		if err != nil {
+			return err
+		}
+		s.ln = ln
+		s.conns = make(map[net.Conn]struct{})
+		s.wg.Add(1)
+		go s.serveTCP()
+	case "udp":
+		conn, err := net.ListenPacket("udp", s.config.BindAddress)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		s.wg.Add(1)
+		go s.serveUDP()
+	}
+
+	s.closing = make(chan struct{})
+	s.batcher.Start()
+	s.writesWg.Add(1)
+	go s.processBatches()
+
+	s.log.Info("Listening",
+		zap.String("protocol", s.config.Protocol),
+		zap.Stringer("addr", s.Addr()),
+		zap.Stringer("org_id", s.config.OrgID),
+		zap.Stringer("bucket_id", s.config.BucketID))
+	return nil
+}
+
+// Close stops the listener, closes every connection and writes the points
+// which are still buffered.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	if s.closing == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.ln != nil {
+		s.ln.Close()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	// the batch writer consumes the last batch emitted by the batcher.
+	s.batcher.Stop()
+	close(s.closing)
+	s.writesWg.Wait()
+
+	s.mu.Lock()
+	s.closing = nil
+	s.mu.Unlock()
+	return nil
+}
+
+// Addr returns the address the service listens on, or nil if it is not open.
+func (s *Service) Addr() net.Addr {
+	if s.ln != nil {
+		return s.ln.Addr()
+	}
+	if s.conn != nil {
+		return s.conn.LocalAddr()
+	}
+	return nil
+}
+
+// PrometheusCollectors returns the metrics of the service.
+func (s *Service) PrometheusCollectors() []prometheus.Collector {
+	return s.metrics.PrometheusCollectors()
+}
+
+func (s *Service) serveTCP() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if isClosed(err) {
+				return
+			}
+			s.log.Error("Failed to accept connection", zap.Error(err))
+			continue
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.metrics.ConnectionsTotal.Inc()
+		s.metrics.Connections.Inc()
+		s.wg.Add(1)
+		go s.handleTCPConn(conn)
+	}
+}
+
+func (s *Service) handleTCPConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+		s.metrics.Connections.Dec()
+		s.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		s.handleLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil && !isClosed(err) {
+		s.log.Info("Error reading from connection", zap.Stringer("remote_addr", conn.RemoteAddr()), zap.Error(err))
+	}
+}
+
+func (s *Service) serveUDP() {
+	defer s.wg.Done()
+
+	buf := make([]byte, udpBufferSize)
+	for {
+		n, _, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			if isClosed(err) {
+				return
+			}
+			s.log.Error("Failed to read UDP message", zap.Error(err))
+			continue
+		}
+
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			s.handleLine(line)
+		}
+	}
+}
+
+// handleLine parses a line and sends the point to the batcher.
+func (s *Service) handleLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	pt, err := s.parser.Parse(line)
+	if err != nil {
+		s.metrics.ParseErrors.Inc()
+		s.log.Debug("Unable to parse line", zap.String("line", line), zap.Error(err))
+		return
+	}
+
+	s.metrics.PointsReceived.Inc()
+	s.batcher.In() <- pt
+}
+
+// processBatches writes the batches emitted by the batcher until the service
+// is closed.
+func (s *Service) processBatches() {
+	defer s.writesWg.Done()
+
+	for {
+		select {
+		case batch := <-s.batcher.Out():
+			s.writeBatch(batch)
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+func (s *Service) writeBatch(batch []models.Point) {
+	start := time.Now()
+	err := s.write(batch)
+	s.metrics.BatchWriteSeconds.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		s.metrics.Batches.WithLabelValues("error").Inc()
+		s.log.Error("Failed to write batch", zap.Int("points", len(batch)), zap.Error(err))
+		return
+	}
+	s.metrics.Batches.WithLabelValues("ok").Inc()
+	s.metrics.PointsWritten.Add(float64(len(batch)))
+}
+
+func (s *Service) write(batch []models.Point) error {
+	points, err := tsdb.ExplodePoints(s.config.OrgID, s.config.BucketID, batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode points: %v", err)
+	}
+	return s.pointsWriter.WritePoints(context.Background(), points)
+}
+
+// isClosed reports whether err was returned by a closed listener or
+// connection.
+func isClosed(err error) bool {
+	return strings.Contains(err.Error(), "use of closed network connection")
+}
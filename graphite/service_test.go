@@ -0,0 +1,82 @@
+package graphite_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/graphite"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService(t *testing.T) {
+	for _, protocol := range []string{"tcp", "udp"} {
+		t.Run(protocol, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			s, err := graphite.NewService(zaptest.NewLogger(t), pw, graphite.Config{
+				BindAddress:  "127.0.0.1:0",
+				Protocol:     protocol,
+				OrgID:        1,
+				BucketID:     2,
+				Templates:    []string{"host.measurement.field"},
+				Tags:         []string{"dc=eu"},
+				BatchSize:    2,
+				BatchTimeout: time.Hour,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Open(); err != nil {
+				t.Fatal(err)
+			}
+
+			conn, err := net.Dial(protocol, s.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := conn.Write([]byte("server01.cpu.idle 50 1590000000\nserver01.cpu.user 25 1590000000\ninvalid\n")); err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+
+			// both points are written as soon as the batch is full.
+			deadline := time.Now().Add(5 * time.Second)
+			for pw.WritePointsCalled() == 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("timed out waiting for points")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			name := tsdb.EncodeNameString(1, 2)
+			want := []string{
+				models.MustNewPoint(name, models.NewTags(map[string]string{
+					models.MeasurementTagKey: "cpu",
+					"dc":                     "eu",
+					"host":                   "server01",
+					models.FieldKeyTagKey:    "idle",
+				}), models.Fields{"idle": 50.0}, time.Unix(1590000000, 0)).String(),
+				models.MustNewPoint(name, models.NewTags(map[string]string{
+					models.MeasurementTagKey: "cpu",
+					"dc":                     "eu",
+					"host":                   "server01",
+					models.FieldKeyTagKey:    "user",
+				}), models.Fields{"user": 25.0}, time.Unix(1590000000, 0)).String(),
+			}
+			if got := len(pw.Points); got != len(want) {
+				t.Fatalf("unexpected number of points; got %d, want %d", got, len(want))
+			}
+			for i, pt := range pw.Points {
+				if got := pt.String(); got != want[i] {
+					t.Errorf("unexpected point %d; got %q, want %q", i, got, want[i])
+				}
+			}
+		})
+	}
+}
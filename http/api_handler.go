@@ -12,6 +12,7 @@ import (
 	kithttp "github.com/influxdata/influxdb/kit/transport/http"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -44,6 +45,11 @@ type APIBackend struct {
 	// write request. A value of zero specifies there is no limit.
 	WriteParserMaxValues int
 
+	// WriteStreamChunkSize specifies the number of lines parsed and written to storage at a time
+	// when line protocol is parsed as the request body is read. A value of zero specifies that
+	// the whole request body is read before it is parsed.
+	WriteStreamChunkSize int
+
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
 
@@ -51,11 +57,22 @@ type APIBackend struct {
 	QueryEventRecorder metric.EventRecorder
 
 	PointsWriter                    storage.PointsWriter
+	ReadStore                       reads.Store
 	DeleteService                   influxdb.DeleteService
 	BackupService                   influxdb.BackupService
 	KVBackupService                 influxdb.KVBackupService
+	BackupManifestService           influxdb.BackupManifestService
+	RestoreService                  influxdb.RestoreService
+	KVRestoreService                influxdb.KVRestoreService
+	ExportService                   influxdb.ExportService
 	AuthorizationService            influxdb.AuthorizationService
 	BucketService                   influxdb.BucketService
+	BucketSchemaService             influxdb.BucketSchemaService
+	BucketCardinalityService        influxdb.BucketCardinalityService
+	ReplicationService              influxdb.ReplicationService
+	ScriptService                   influxdb.ScriptService
+	RunningQueryService             influxdb.RunningQueryService
+	DBRPMappingService              influxdb.DBRPMappingService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
@@ -128,6 +145,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 
 	bucketBackend := NewBucketBackend(b.Logger.With(zap.String("handler", "bucket")), b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.BucketSchemaService = authorizer.NewBucketSchemaService(b.BucketSchemaService)
 	h.Mount(prefixBuckets, NewBucketHandler(b.Logger, bucketBackend))
 
 	checkBackend := NewCheckBackend(b.Logger.With(zap.String("handler", "check")), b)
@@ -150,6 +168,18 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	fluxBackend := NewFluxBackend(b.Logger.With(zap.String("handler", "query")), b)
 	h.Mount(prefixQuery, NewFluxHandler(b.Logger, fluxBackend))
 
+	legacyBackend := NewLegacyBackend(b.Logger.With(zap.String("handler", "legacy")), b)
+	legacyHandler := NewLegacyHandler(b.Logger, legacyBackend)
+	h.Mount(prefixLegacyQuery, legacyHandler)
+	h.Mount(prefixLegacyWrite, legacyHandler)
+
+	promBackend := NewPromBackend(b.Logger.With(zap.String("handler", "prom")), b)
+	promHandler := NewPromHandler(b.Logger, promBackend)
+	h.Mount(prefixProm, promHandler)
+	for _, prefix := range promQueryPrefixes {
+		h.Mount(prefix, promHandler)
+	}
+
 	h.Mount(prefixLabels, NewLabelHandler(b.Logger, authorizer.NewLabelService(b.LabelService), b.HTTPErrorHandler))
 
 	notificationEndpointBackend := NewNotificationEndpointBackend(b.Logger.With(zap.String("handler", "notificationEndpoint")), b)
@@ -172,6 +202,22 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 		b.OrganizationService)
 	h.Mount(prefixTargets, NewScraperHandler(b.Logger, scraperBackend))
 
+	dbrpBackend := NewDBRPMappingBackend(b.Logger.With(zap.String("handler", "dbrp")), b)
+	dbrpBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	h.Mount(prefixDBRPs, NewDBRPMappingHandler(b.Logger, dbrpBackend))
+
+	replicationBackend := NewReplicationBackend(b.Logger.With(zap.String("handler", "replication")), b)
+	replicationBackend.ReplicationService = authorizer.NewReplicationService(b.ReplicationService)
+	h.Mount(prefixReplications, NewReplicationHandler(b.Logger, replicationBackend))
+
+	scriptBackend := NewScriptBackend(b.Logger.With(zap.String("handler", "script")), b)
+	scriptBackend.ScriptService = authorizer.NewScriptService(b.ScriptService)
+	h.Mount(prefixScripts, NewScriptHandler(b.Logger, scriptBackend))
+
+	runningQueryBackend := NewRunningQueryBackend(b.Logger.With(zap.String("handler", "running_query")), b)
+	runningQueryBackend.RunningQueryService = authorizer.NewRunningQueryService(b.RunningQueryService)
+	h.Mount(prefixRunningQueries, NewRunningQueryHandler(b.Logger, runningQueryBackend))
+
 	sessionBackend := newSessionBackend(b.Logger.With(zap.String("handler", "session")), b)
 	sessionHandler := NewSessionHandler(b.Logger, sessionBackend)
 	h.Mount(prefixSignIn, sessionHandler)
@@ -212,12 +258,25 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	backupBackend.BackupService = authorizer.NewBackupService(backupBackend.BackupService)
 	h.Mount(prefixBackup, NewBackupHandler(backupBackend))
 
+	restoreBackend := NewRestoreBackend(b)
+	restoreBackend.RestoreService = authorizer.NewRestoreService(b.RestoreService)
+	restoreBackend.KVRestoreService = authorizer.NewKVRestoreService(b.KVRestoreService)
+	restoreBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	h.Mount(prefixRestore, NewRestoreHandler(restoreBackend))
+
+	exportBackend := NewExportBackend(b)
+	exportBackend.ExportService = authorizer.NewExportService(b.ExportService)
+	exportBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	exportBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.Mount(prefixExport, NewExportHandler(exportBackend))
+
 	writeBackend := NewWriteBackend(b.Logger.With(zap.String("handler", "write")), b)
 	h.Mount(prefixWrite, NewWriteHandler(b.Logger, writeBackend,
 		WithMaxBatchSizeBytes(b.MaxBatchSizeBytes),
 		WithParserMaxBytes(b.WriteParserMaxBytes),
 		WithParserMaxLines(b.WriteParserMaxLines),
 		WithParserMaxValues(b.WriteParserMaxValues),
+		WithStreamChunkSize(b.WriteStreamChunkSize),
 	))
 
 	for _, o := range opts {
@@ -233,27 +292,33 @@ var apiLinks = map[string]interface{}{
 	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"dbrps":          "/api/v2/dbrps",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
+	"export":                "/api/v2/export",
 	"labels":                "/api/v2/labels",
 	"variables":             "/api/v2/variables",
 	"me":                    "/api/v2/me",
 	"notificationRules":     "/api/v2/notificationRules",
 	"notificationEndpoints": "/api/v2/notificationEndpoints",
 	"orgs":                  "/api/v2/orgs",
+	"queries":               "/api/v2/queries",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
 		"analyze":     "/api/v2/query/analyze",
 		"suggestions": "/api/v2/query/suggestions",
 	},
-	"setup":    "/api/v2/setup",
-	"signin":   "/api/v2/signin",
-	"signout":  "/api/v2/signout",
-	"sources":  "/api/v2/sources",
-	"scrapers": "/api/v2/scrapers",
-	"swagger":  "/api/v2/swagger.json",
+	"replications": "/api/v2/replications",
+	"restore":      "/api/v2/restore",
+	"scripts":      "/api/v2/scripts",
+	"setup":        "/api/v2/setup",
+	"signin":       "/api/v2/signin",
+	"signout":      "/api/v2/signout",
+	"sources":      "/api/v2/sources",
+	"scrapers":     "/api/v2/scrapers",
+	"swagger":      "/api/v2/swagger.json",
 	"system": map[string]string{
 		"metrics": "/metrics",
 		"debug":   "/debug/pprof",
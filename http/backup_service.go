@@ -2,6 +2,8 @@ package http
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,8 +31,11 @@ type BackupBackend struct {
 	Logger *zap.Logger
 	influxdb.HTTPErrorHandler
 
-	BackupService   influxdb.BackupService
-	KVBackupService influxdb.KVBackupService
+	BackupService         influxdb.BackupService
+	KVBackupService       influxdb.KVBackupService
+	BackupManifestService influxdb.BackupManifestService
+	BucketService         influxdb.BucketService
+	OrganizationService   influxdb.OrganizationService
 }
 
 // NewBackupBackend returns a new instance of BackupBackend.
@@ -38,9 +43,12 @@ func NewBackupBackend(b *APIBackend) *BackupBackend {
 	return &BackupBackend{
 		Logger: b.Logger.With(zap.String("handler", "backup")),
 
-		HTTPErrorHandler: b.HTTPErrorHandler,
-		BackupService:    b.BackupService,
-		KVBackupService:  b.KVBackupService,
+		HTTPErrorHandler:      b.HTTPErrorHandler,
+		BackupService:         b.BackupService,
+		KVBackupService:       b.KVBackupService,
+		BackupManifestService: b.BackupManifestService,
+		BucketService:         b.BucketService,
+		OrganizationService:   b.OrganizationService,
 	}
 }
 
@@ -49,13 +57,17 @@ type BackupHandler struct {
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
-	BackupService   influxdb.BackupService
-	KVBackupService influxdb.KVBackupService
+	BackupService         influxdb.BackupService
+	KVBackupService       influxdb.KVBackupService
+	BackupManifestService influxdb.BackupManifestService
+	BucketService         influxdb.BucketService
+	OrganizationService   influxdb.OrganizationService
 }
 
 const (
 	prefixBackup        = "/api/v2/backup"
 	backupIDParamName   = "backup_id"
+	backupParentIDParam = "parentID"
 	backupFileParamName = "backup_file"
 	backupFilePath      = prefixBackup + "/:" + backupIDParamName + "/file/:" + backupFileParamName
 
@@ -69,11 +81,14 @@ func composeBackupFilePath(backupID int, backupFile string) string {
 // NewBackupHandler creates a new handler at /api/v2/backup to receive backup requests.
 func NewBackupHandler(b *BackupBackend) *BackupHandler {
 	h := &BackupHandler{
-		HTTPErrorHandler: b.HTTPErrorHandler,
-		Router:           NewRouter(b.HTTPErrorHandler),
-		Logger:           b.Logger,
-		BackupService:    b.BackupService,
-		KVBackupService:  b.KVBackupService,
+		HTTPErrorHandler:      b.HTTPErrorHandler,
+		Router:                NewRouter(b.HTTPErrorHandler),
+		Logger:                b.Logger,
+		BackupService:         b.BackupService,
+		KVBackupService:       b.KVBackupService,
+		BackupManifestService: b.BackupManifestService,
+		BucketService:         b.BucketService,
+		OrganizationService:   b.OrganizationService,
 	}
 
 	h.HandlerFunc(http.MethodPost, prefixBackup, h.handleCreate)
@@ -87,12 +102,29 @@ type backup struct {
 	Files []string `json:"files,omitempty"`
 }
 
+// handleCreate creates a backup of the engine files and the meta data. With the
+// parentID query parameter, the backup is incremental: it only carries the
+// engine files and the meta data changes since the parent backup.
 func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleCreate")
 	defer span.Finish()
 
 	ctx := r.Context()
 
+	var parentID *influxdb.ID
+	if v := r.URL.Query().Get(backupParentIDParam); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid parent backup ID",
+				Err:  err,
+			}, w)
+			return
+		}
+		parentID = id
+	}
+
 	id, files, err := h.BackupService.CreateBackup(ctx)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -101,51 +133,227 @@ func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 
 	internalBackupPath := h.BackupService.InternalBackupPath(id)
 
-	boltPath := filepath.Join(internalBackupPath, bolt.DefaultFilename)
-	boltFile, err := os.OpenFile(boltPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+	files, err = h.createBackup(ctx, internalBackupPath, parentID, files)
 	if err != nil {
 		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	if err = h.KVBackupService.Backup(ctx, boltFile); err != nil {
+	b := backup{
+		ID:    id,
+		Files: files,
+	}
+	if err = json.NewEncoder(w).Encode(&b); err != nil {
 		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+}
 
-	files = append(files, bolt.DefaultFilename)
+// createBackup adds the meta data, the credentials and the manifest to the
+// engine files of the backup at internalBackupPath, and returns the files to
+// download. An incremental backup drops the engine files which are part of
+// its parent backup.
+func (h *BackupHandler) createBackup(ctx context.Context, internalBackupPath string, parentID *influxdb.ID, engineFiles []string) ([]string, error) {
+	manifest := influxdb.BackupManifest{
+		ParentID:      parentID,
+		CreatedAt:     time.Now().UTC(),
+		EngineVersion: influxdb.GetBuildInfo().Version,
+	}
+
+	buckets, err := h.backupBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Buckets = buckets
+
+	var parent *influxdb.BackupManifest
+	if parentID != nil {
+		p, err := h.BackupManifestService.FindBackupManifestByID(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		parent = p
+	}
+
+	// the change sequence is read before the meta data is backed up, so no
+	// change is missed by the next incremental backup. Changes which are part
+	// of both backups are skipped when the backups are restored.
+	journaled := true
+	seq, err := h.KVBackupService.ChangeSequence(ctx)
+	if err != nil {
+		if parent != nil || influxdb.ErrorCode(err) != influxdb.EMethodNotAllowed {
+			return nil, err
+		}
+		journaled = false
+	}
+
+	parentFiles := make(map[string]influxdb.BackupFile)
+	if parent != nil {
+		for _, f := range parent.Files {
+			parentFiles[f.Name] = f
+		}
+	}
+
+	files := make([]string, 0, len(engineFiles)+3)
+	for _, name := range engineFiles {
+		fi, err := os.Stat(filepath.Join(internalBackupPath, name))
+		if err != nil {
+			return nil, err
+		}
+
+		// TSM files are immutable, a compaction writes a new generation of
+		// files, whereas tombstone files grow as deletes are applied.
+		pf, ok := parentFiles[name]
+		if ok && pf.Size == fi.Size() {
+			manifest.Files = append(manifest.Files, influxdb.BackupFile{
+				Name:   name,
+				Size:   pf.Size,
+				SHA256: pf.SHA256,
+			})
+			if err := os.Remove(filepath.Join(internalBackupPath, name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		f, err := newBackupFile(internalBackupPath, name)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, *f)
+		files = append(files, name)
+	}
+
+	if parent == nil {
+		boltPath := filepath.Join(internalBackupPath, bolt.DefaultFilename)
+		boltFile, err := os.OpenFile(boltPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+		if err != nil {
+			return nil, err
+		}
+		err = h.KVBackupService.Backup(ctx, boltFile)
+		if err = multierr.Append(err, boltFile.Close()); err != nil {
+			return nil, err
+		}
+		manifest.KVSequence = seq
+		if manifest.KV, err = newBackupFile(internalBackupPath, bolt.DefaultFilename); err != nil {
+			return nil, err
+		}
+		files = append(files, bolt.DefaultFilename)
+	} else {
+		changesPath := filepath.Join(internalBackupPath, influxdb.BackupKVChangesFilename)
+		changesFile, err := os.OpenFile(changesPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+		if err != nil {
+			return nil, err
+		}
+		last, err := h.KVBackupService.BackupChanges(ctx, parent.KVSequence, changesFile)
+		if err = multierr.Append(err, changesFile.Close()); err != nil {
+			return nil, err
+		}
+		manifest.KVSequence = last
+		if manifest.KV, err = newBackupFile(internalBackupPath, influxdb.BackupKVChangesFilename); err != nil {
+			return nil, err
+		}
+		files = append(files, influxdb.BackupKVChangesFilename)
+	}
 
 	credBackupPath := filepath.Join(internalBackupPath, DefaultTokenFile)
 
 	credPath, err := defaultTokenPath()
 	if err != nil {
-		h.HandleHTTPError(ctx, err, w)
-		return
+		return nil, err
 	}
 	token, err := ioutil.ReadFile(credPath)
 	if err != nil {
-		h.HandleHTTPError(ctx, err, w)
-		return
+		return nil, err
 	}
 
 	if err := ioutil.WriteFile(credBackupPath, []byte(token), 0600); err != nil {
-		h.HandleHTTPError(ctx, err, w)
-		return
+		return nil, err
 	}
 
+	if manifest.Credentials, err = newBackupFile(internalBackupPath, DefaultTokenFile); err != nil {
+		return nil, err
+	}
 	files = append(files, DefaultTokenFile)
 
-	b := backup{
-		ID:    id,
-		Files: files,
+	// a backup of a meta data store without a journal has a manifest, but
+	// no incremental backup can be taken from it.
+	if err := h.BackupManifestService.CreateBackupManifest(ctx, &manifest); err != nil {
+		return nil, err
 	}
-	if err = json.NewEncoder(w).Encode(&b); err != nil {
-		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
-		h.HandleHTTPError(ctx, err, w)
-		return
+	if err := manifest.SetChecksum(); err != nil {
+		return nil, err
 	}
+
+	octets, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(internalBackupPath, influxdb.BackupManifestFilename), octets, 0600); err != nil {
+		return nil, err
+	}
+	files = append(files, influxdb.BackupManifestFilename)
+
+	// the changes which are part of a full backup are no longer needed
+	// by incremental backups taken from it or from backups after it.
+	if journaled && parent == nil {
+		if err := h.KVBackupService.TruncateChanges(ctx, seq); err != nil {
+			h.Logger.Warn("Failed to truncate journaled meta data changes", zap.Error(err))
+		}
+	}
+
+	return files, nil
+}
+
+// backupBuckets returns all of the buckets, with the names of their organizations.
+func (h *BackupHandler) backupBuckets(ctx context.Context) ([]influxdb.BackupBucket, error) {
+	orgs, _, err := h.OrganizationService.FindOrganizations(ctx, influxdb.OrganizationFilter{})
+	if err != nil {
+		return nil, err
+	}
+	orgNames := make(map[influxdb.ID]string, len(orgs))
+	for _, o := range orgs {
+		orgNames[o.ID] = o.Name
+	}
+
+	buckets, _, err := h.BucketService.FindBuckets(ctx, influxdb.BucketFilter{})
+	if err != nil {
+		return nil, err
+	}
+	bs := make([]influxdb.BackupBucket, 0, len(buckets))
+	for _, b := range buckets {
+		bs = append(bs, influxdb.BackupBucket{
+			OrgID:    b.OrgID,
+			Org:      orgNames[b.OrgID],
+			BucketID: b.ID,
+			Bucket:   b.Name,
+		})
+	}
+	return bs, nil
+}
+
+// newBackupFile returns the manifest entry of the file name of the backup at
+// internalBackupPath, which is part of the backup.
+func newBackupFile(internalBackupPath, name string) (*influxdb.BackupFile, error) {
+	f, err := os.Open(filepath.Join(internalBackupPath, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.BackupFile{
+		Name:     name,
+		Size:     n,
+		Included: true,
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+	}, nil
 }
 
 func (h *BackupHandler) handleFetchFile(w http.ResponseWriter, r *http.Request) {
@@ -179,10 +387,28 @@ func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error)
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	return s.createBackup(ctx, nil)
+}
+
+// CreateIncrementalBackup creates a backup which only carries the changes
+// made since the backup with the ID parentID.
+func (s *BackupService) CreateIncrementalBackup(ctx context.Context, parentID influxdb.ID) (int, []string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.createBackup(ctx, &parentID)
+}
+
+func (s *BackupService) createBackup(ctx context.Context, parentID *influxdb.ID) (int, []string, error) {
 	u, err := NewURL(s.Addr, prefixBackup)
 	if err != nil {
 		return 0, nil, err
 	}
+	if parentID != nil {
+		params := u.Query()
+		params.Set(backupParentIDParam, parentID.String())
+		u.RawQuery = params.Encode()
+	}
 
 	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
 	if err != nil {
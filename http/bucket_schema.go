@@ -0,0 +1,273 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+type measurementSchemasResponse struct {
+	MeasurementSchemas []*influxdb.MeasurementSchema `json:"measurementSchemas"`
+}
+
+type postMeasurementSchemaRequest struct {
+	Name    string                             `json:"name"`
+	Columns []influxdb.MeasurementSchemaColumn `json:"columns"`
+}
+
+// handleGetMeasurementSchemas is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handleGetMeasurementSchemas(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BucketHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	b, err := h.findSchemaBucket(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter := influxdb.MeasurementSchemaFilter{BucketID: b.ID}
+	if name := r.URL.Query().Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	ms, _, err := h.BucketSchemaService.FindMeasurementSchemas(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Measurement schemas retrieved", zap.Int("count", len(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, &measurementSchemasResponse{MeasurementSchemas: ms}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePostMeasurementSchema is the HTTP handler for the POST /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handlePostMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	b, err := h.findSchemaBucket(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req postMeasurementSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	m := &influxdb.MeasurementSchema{
+		OrgID:    b.OrgID,
+		BucketID: b.ID,
+		Name:     req.Name,
+		Columns:  req.Columns,
+	}
+	if err := h.BucketSchemaService.CreateMeasurementSchema(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Measurement schema created", zap.String("measurementSchema", m.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, m); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetMeasurementSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements/:measurementID route.
+func (h *BucketHandler) handleGetMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	m, err := h.BucketSchemaService.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Measurement schema retrieved", zap.String("measurementSchema", m.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, m); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePatchMeasurementSchema is the HTTP handler for the PATCH /api/v2/buckets/:id/schema/measurements/:measurementID route.
+func (h *BucketHandler) handlePatchMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.MeasurementSchemaUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	m, err := h.BucketSchemaService.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Measurement schema updated", zap.String("measurementSchema", m.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, m); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteMeasurementSchema is the HTTP handler for the DELETE /api/v2/buckets/:id/schema/measurements/:measurementID route.
+func (h *BucketHandler) handleDeleteMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BucketSchemaService.DeleteMeasurementSchema(ctx, bucketID, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Measurement schema deleted", zap.String("measurementSchemaID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findSchemaBucket returns the bucket in the request path, which the caller
+// must be able to read.
+func (h *BucketHandler) findSchemaBucket(ctx context.Context, r *http.Request) (*influxdb.Bucket, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return h.BucketService.FindBucketByID(ctx, req.BucketID)
+}
+
+func decodeMeasurementSchemaIDs(ctx context.Context) (bucketID, id influxdb.ID, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+	if err := bucketID.DecodeFromString(params.ByName("id")); err != nil {
+		return 0, 0, err
+	}
+	if err := id.DecodeFromString(params.ByName("measurementID")); err != nil {
+		return 0, 0, err
+	}
+	return bucketID, id, nil
+}
+
+func measurementSchemasPath(bucketID influxdb.ID) string {
+	return path.Join(bucketIDPath(bucketID), "schema", "measurements")
+}
+
+func measurementSchemaIDPath(bucketID, id influxdb.ID) string {
+	return path.Join(measurementSchemasPath(bucketID), id.String())
+}
+
+// BucketSchemaService connects to Influx via HTTP using tokens to manage measurement schemas.
+type BucketSchemaService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (s *BucketSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var m influxdb.MeasurementSchema
+	err := s.Client.
+		Get(measurementSchemaIDPath(bucketID, id)).
+		DecodeJSON(&m).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.Name != nil {
+		params = append(params, [2]string{"name", *filter.Name})
+	}
+
+	var resp measurementSchemasResponse
+	err := s.Client.
+		Get(measurementSchemasPath(filter.BucketID)).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.MeasurementSchemas, len(resp.MeasurementSchemas), nil
+}
+
+// CreateMeasurementSchema creates a new measurement schema and sets m.ID with the new identifier.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	req := postMeasurementSchemaRequest{
+		Name:    m.Name,
+		Columns: m.Columns,
+	}
+	return s.Client.
+		PostJSON(req, measurementSchemasPath(m.BucketID)).
+		DecodeJSON(m).
+		Do(ctx)
+}
+
+// UpdateMeasurementSchema updates the columns of a measurement schema.
+// Returns the new measurement schema after update.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var m influxdb.MeasurementSchema
+	err := s.Client.
+		PatchJSON(upd, measurementSchemaIDPath(bucketID, id)).
+		DecodeJSON(&m).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// DeleteMeasurementSchema removes a measurement schema of a bucket by ID.
+func (s *BucketSchemaService) DeleteMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID) error {
+	return s.Client.
+		Delete(measurementSchemaIDPath(bucketID, id)).
+		Do(ctx)
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestBucketSchemaService(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "explicit", SchemaType: influxdb.SchemaTypeExplicit}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	bucketBackend := NewMockBucketBackend(t)
+	bucketBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	bucketBackend.BucketService = svc
+	bucketBackend.BucketSchemaService = svc
+	server := httptest.NewServer(NewBucketHandler(zaptest.NewLogger(t), bucketBackend))
+	defer server.Close()
+
+	client := &BucketSchemaService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+	buckets := &BucketService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	b, err := buckets.FindBucketByID(ctx, bucket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.SchemaType != influxdb.SchemaTypeExplicit {
+		t.Errorf("unexpected schema type: got %s want %s", b.SchemaType, influxdb.SchemaTypeExplicit)
+	}
+
+	columns := []influxdb.MeasurementSchemaColumn{
+		{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+		{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+		{Name: "usage", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+	}
+	m := &influxdb.MeasurementSchema{BucketID: bucket.ID, Name: "cpu", Columns: columns}
+	if err := client.CreateMeasurementSchema(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if !m.ID.Valid() || m.OrgID != org.ID {
+		t.Fatalf("unexpected measurement schema: %+v", m)
+	}
+
+	err = client.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{BucketID: bucket.ID, Name: "mem"})
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Errorf("unexpected error code creating invalid schema: got %q want %q", got, want)
+	}
+
+	got, err := client.FindMeasurementSchemaByID(ctx, bucket.ID, m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Columns, got.Columns); diff != "" {
+		t.Errorf("unexpected columns -want/+got:\n%s", diff)
+	}
+
+	upd := influxdb.MeasurementSchemaUpdate{Columns: columns[:2]}
+	if _, err := client.UpdateMeasurementSchema(ctx, bucket.ID, m.ID, upd); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected removing a column to be invalid, got %v", err)
+	}
+
+	name := "cpu"
+	ms, n, err := client.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucket.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || ms[0].ID != m.ID {
+		t.Fatalf("unexpected measurement schemas: %v", ms)
+	}
+
+	if err := client.DeleteMeasurementSchema(ctx, bucket.ID, m.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindMeasurementSchemaByID(ctx, bucket.ID, m.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected measurement schema to be deleted, got %v", err)
+	}
+}
@@ -24,6 +24,8 @@ type BucketBackend struct {
 
 	BucketService              influxdb.BucketService
 	BucketOperationLogService  influxdb.BucketOperationLogService
+	BucketSchemaService        influxdb.BucketSchemaService
+	BucketCardinalityService   influxdb.BucketCardinalityService
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
@@ -38,6 +40,8 @@ func NewBucketBackend(log *zap.Logger, b *APIBackend) *BucketBackend {
 
 		BucketService:              b.BucketService,
 		BucketOperationLogService:  b.BucketOperationLogService,
+		BucketSchemaService:        b.BucketSchemaService,
+		BucketCardinalityService:   b.BucketCardinalityService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -53,6 +57,8 @@ type BucketHandler struct {
 
 	BucketService              influxdb.BucketService
 	BucketOperationLogService  influxdb.BucketOperationLogService
+	BucketSchemaService        influxdb.BucketSchemaService
+	BucketCardinalityService   influxdb.BucketCardinalityService
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
@@ -69,6 +75,9 @@ const (
 	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
 	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
 	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+
+	bucketsIDSchemaMeasurementsPath   = "/api/v2/buckets/:id/schema/measurements"
+	bucketsIDSchemaMeasurementsIDPath = "/api/v2/buckets/:id/schema/measurements/:measurementID"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -80,6 +89,8 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 
 		BucketService:              b.BucketService,
 		BucketOperationLogService:  b.BucketOperationLogService,
+		BucketSchemaService:        b.BucketSchemaService,
+		BucketCardinalityService:   b.BucketCardinalityService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -93,6 +104,12 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsPath, h.handleGetMeasurementSchemas)
+	h.HandlerFunc("POST", bucketsIDSchemaMeasurementsPath, h.handlePostMeasurementSchema)
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsIDPath, h.handleGetMeasurementSchema)
+	h.HandlerFunc("PATCH", bucketsIDSchemaMeasurementsIDPath, h.handlePatchMeasurementSchema)
+	h.HandlerFunc("DELETE", bucketsIDSchemaMeasurementsIDPath, h.handleDeleteMeasurementSchema)
+
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
 		log:                        b.log.With(zap.String("handler", "member")),
@@ -139,6 +156,7 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	SchemaType          string          `json:"schemaType,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -178,6 +196,11 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		}
 	}
 
+	st, err := influxdb.ParseSchemaType(b.SchemaType)
+	if err != nil {
+		return nil, err
+	}
+
 	return &influxdb.Bucket{
 		ID:                  b.ID,
 		OrgID:               b.OrgID,
@@ -186,6 +209,7 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		SchemaType:          st,
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -204,6 +228,11 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		})
 	}
 
+	var schemaType string
+	if pb.SchemaType == influxdb.SchemaTypeExplicit {
+		schemaType = pb.SchemaType.String()
+	}
+
 	return &bucket{
 		ID:                  pb.ID,
 		OrgID:               pb.OrgID,
@@ -212,6 +241,7 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		SchemaType:          schemaType,
 		CRUDLog:             pb.CRUDLog,
 	}
 }
@@ -269,8 +299,9 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 
 type bucketResponse struct {
 	bucket
-	Links  map[string]string `json:"links"`
-	Labels []influxdb.Label  `json:"labels"`
+	SeriesCardinality *int64            `json:"seriesCardinality,omitempty"`
+	Links             map[string]string `json:"links"`
+	Labels            []influxdb.Label  `json:"labels"`
 }
 
 func newBucketResponse(b *influxdb.Bucket, labels []*influxdb.Label) *bucketResponse {
@@ -351,6 +382,7 @@ type postBucketRequest struct {
 	Description         string          `json:"description"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	SchemaType          string          `json:"schemaType,omitempty"`
 }
 
 func (b postBucketRequest) Validate() error {
@@ -376,6 +408,11 @@ func (b postBucketRequest) toInfluxDB() (*influxdb.Bucket, error) {
 		}
 	}
 
+	st, err := influxdb.ParseSchemaType(b.SchemaType)
+	if err != nil {
+		return nil, err
+	}
+
 	return &influxdb.Bucket{
 		OrgID:               b.OrgID,
 		Description:         b.Description,
@@ -383,7 +420,8 @@ func (b postBucketRequest) toInfluxDB() (*influxdb.Bucket, error) {
 		Type:                influxdb.BucketTypeUser,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     dur,
-	}, err
+		SchemaType:          st,
+	}, nil
 }
 
 func decodePostBucketRequest(ctx context.Context, r *http.Request) (*postBucketRequest, error) {
@@ -422,12 +460,29 @@ func (h *BucketHandler) handleGetBucket(w http.ResponseWriter, r *http.Request)
 
 	h.log.Debug("Bucket retrieved", zap.String("bucket", fmt.Sprint(b)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newBucketResponse(b, labels)); err != nil {
+	res := newBucketResponse(b, labels)
+	h.setSeriesCardinality(ctx, res, b)
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
 		logEncodingError(h.log, r, err)
 		return
 	}
 }
 
+// setSeriesCardinality sets the number of series in a bucket on its response,
+// if the storage engine is able to report it.
+func (h *BucketHandler) setSeriesCardinality(ctx context.Context, res *bucketResponse, b *influxdb.Bucket) {
+	if h.BucketCardinalityService == nil {
+		return
+	}
+
+	n, err := h.BucketCardinalityService.BucketSeriesCardinality(ctx, b.OrgID, b.ID)
+	if err != nil {
+		h.log.Info("Failed to retrieve bucket series cardinality", zap.Stringer("bucket_id", b.ID), zap.Error(err))
+		return
+	}
+	res.SeriesCardinality = &n
+}
+
 type getBucketRequest struct {
 	BucketID influxdb.ID
 }
@@ -587,7 +642,10 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 	}
 	h.log.Debug("Buckets retrieved", zap.String("buckets", fmt.Sprint(bs)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService)); err != nil {
+	// the series cardinality is only reported for a single bucket, so that
+	// listing buckets does not count the series of each of them.
+	res := newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService)
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
 		logEncodingError(h.log, r, err)
 		return
 	}
@@ -31,6 +31,7 @@ func NewMockBucketBackend(t *testing.T) *BucketBackend {
 
 		BucketService:              mock.NewBucketService(),
 		BucketOperationLogService:  mock.NewBucketOperationLogService(),
+		BucketSchemaService:        mock.NewBucketSchemaService(),
 		UserResourceMappingService: mock.NewUserResourceMappingService(),
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
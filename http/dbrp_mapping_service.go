@@ -0,0 +1,255 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixDBRPs = "/api/v2/dbrps"
+)
+
+// DBRPMappingBackend is all services and associated parameters required to construct
+// the DBRPMappingHandler.
+type DBRPMappingBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	DBRPMappingService influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingBackend creates a backend used by the dbrp mapping handler.
+func NewDBRPMappingBackend(log *zap.Logger, b *APIBackend) *DBRPMappingBackend {
+	return &DBRPMappingBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		DBRPMappingService: b.DBRPMappingService,
+	}
+}
+
+// DBRPMappingHandler is the handler for the dbrp mapping service, which maps the
+// databases and retention policies of the 1.x write and query endpoints to buckets.
+type DBRPMappingHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingHandler creates a new DBRPMappingHandler.
+func NewDBRPMappingHandler(log *zap.Logger, b *DBRPMappingBackend) *DBRPMappingHandler {
+	h := &DBRPMappingHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		DBRPMappingService: b.DBRPMappingService,
+	}
+
+	// a mapping has no id, it is identified by its cluster, database and retention policy.
+	h.HandlerFunc("GET", prefixDBRPs, h.handleGetDBRPs)
+	h.HandlerFunc("POST", prefixDBRPs, h.handlePostDBRP)
+	h.HandlerFunc("DELETE", prefixDBRPs, h.handleDeleteDBRP)
+
+	return h
+}
+
+type dbrpsResponse struct {
+	DBRPs []*influxdb.DBRPMapping `json:"dbrps"`
+}
+
+// handleGetDBRPs is the HTTP handler for the GET /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handleGetDBRPs(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DBRPMappingHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := decodeDBRPMappingFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.DBRPMappingService.FindMany(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("DBRP mappings retrieved", zap.Int("count", len(ms)))
+
+	resp := dbrpsResponse{DBRPs: ms}
+	if resp.DBRPs == nil {
+		resp.DBRPs = []*influxdb.DBRPMapping{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeDBRPMappingFilter(r *http.Request) (influxdb.DBRPMappingFilter, error) {
+	var filter influxdb.DBRPMappingFilter
+	qp := r.URL.Query()
+	if cluster := qp.Get("cluster"); cluster != "" {
+		filter.Cluster = &cluster
+	}
+	if db := qp.Get("db"); db != "" {
+		filter.Database = &db
+	}
+	if rp := qp.Get("rp"); rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+	if v := qp.Get("default"); v != "" {
+		isDefault, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid default",
+				Err:  err,
+			}
+		}
+		filter.Default = &isDefault
+	}
+	return filter, nil
+}
+
+// handlePostDBRP is the HTTP handler for the POST /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handlePostDBRP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var m influxdb.DBRPMapping
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Create(ctx, &m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("DBRP mapping created", zap.String("db", m.Database), zap.String("rp", m.RetentionPolicy))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, &m); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteDBRP is the HTTP handler for the DELETE /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handleDeleteDBRP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qp := r.URL.Query()
+	cluster, db, rp := qp.Get("cluster"), qp.Get("db"), qp.Get("rp")
+	if cluster == "" || db == "" || rp == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "cluster, db and rp are required",
+		}, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Delete(ctx, cluster, db, rp); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("DBRP mapping deleted", zap.String("db", db), zap.String("rp", rp))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DBRPMappingService connects to Influx via HTTP using tokens to manage dbrp mappings.
+type DBRPMappingService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.DBRPMappingService = (*DBRPMappingService)(nil)
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *DBRPMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	return s.Find(ctx, influxdb.DBRPMappingFilter{
+		Cluster:         &cluster,
+		Database:        &db,
+		RetentionPolicy: &rp,
+	})
+}
+
+// Find returns the first dbrp mapping that matches the filter.
+func (s *DBRPMappingService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	ms, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "dbrp mapping not found",
+		}
+	}
+	return ms[0], nil
+}
+
+// FindMany returns the dbrp mappings that match the filter.
+func (s *DBRPMappingService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.Cluster != nil {
+		params = append(params, [2]string{"cluster", *filter.Cluster})
+	}
+	if filter.Database != nil {
+		params = append(params, [2]string{"db", *filter.Database})
+	}
+	if filter.RetentionPolicy != nil {
+		params = append(params, [2]string{"rp", *filter.RetentionPolicy})
+	}
+	if filter.Default != nil {
+		params = append(params, [2]string{"default", strconv.FormatBool(*filter.Default)})
+	}
+
+	var resp dbrpsResponse
+	err := s.Client.
+		Get(prefixDBRPs).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.DBRPs, len(resp.DBRPs), nil
+}
+
+// Create creates a dbrp mapping.
+func (s *DBRPMappingService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		PostJSON(m, prefixDBRPs).
+		DecodeJSON(m).
+		Do(ctx)
+}
+
+// Delete removes a dbrp mapping. Deleting a mapping that does not exist is not an error.
+func (s *DBRPMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(prefixDBRPs).
+		QueryParams([2]string{"cluster", cluster}, [2]string{"db", db}, [2]string{"rp", rp}).
+		Do(ctx)
+}
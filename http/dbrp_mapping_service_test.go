@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestDBRPMappingService(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewDBRPMappingHandler(zaptest.NewLogger(t), &DBRPMappingBackend{
+		HTTPErrorHandler:   kithttp.ErrorHandler(0),
+		log:                zaptest.NewLogger(t),
+		DBRPMappingService: svc,
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := &DBRPMappingService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	m := &influxdb.DBRPMapping{
+		Cluster:         "cluster",
+		Database:        "db0",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  1,
+		BucketID:        2,
+	}
+	if err := client.Create(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	isDefault := true
+	db := "db0"
+	found, err := client.Find(ctx, influxdb.DBRPMappingFilter{Database: &db, Default: &isDefault})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.Equal(m) {
+		t.Errorf("unexpected found mapping: %+v", found)
+	}
+
+	err = client.Create(ctx, &influxdb.DBRPMapping{Cluster: "cluster", Database: "db0", RetentionPolicy: "autogen", OrganizationID: 1, BucketID: 3})
+	if got, want := influxdb.ErrorCode(err), influxdb.EConflict; got != want {
+		t.Errorf("unexpected error code creating conflicting mapping: got %q want %q", got, want)
+	}
+	err = client.Create(ctx, &influxdb.DBRPMapping{Database: "db1"})
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Errorf("unexpected error code creating invalid mapping: got %q want %q", got, want)
+	}
+
+	if err := client.Delete(ctx, "cluster", "db0", "autogen"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindBy(ctx, "cluster", "db0", "autogen"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected deleted mapping to be not found, got %v", err)
+	}
+}
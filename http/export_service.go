@@ -0,0 +1,230 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/NYTimes/gziphandler"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// ExportBackend is all services and associated parameters required to construct the ExportHandler.
+type ExportBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	ExportService       influxdb.ExportService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewExportBackend returns a new instance of ExportBackend.
+func NewExportBackend(b *APIBackend) *ExportBackend {
+	return &ExportBackend{
+		Logger: b.Logger.With(zap.String("handler", "export")),
+
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		ExportService:       b.ExportService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// ExportHandler streams the points of a bucket as line protocol.
+type ExportHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	ExportService       influxdb.ExportService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	prefixExport = "/api/v2/export"
+
+	exportStartParam       = "start"
+	exportStopParam        = "stop"
+	exportMeasurementParam = "measurement"
+)
+
+// NewExportHandler creates a new handler at /api/v2/export to receive export requests.
+func NewExportHandler(b *ExportBackend) *ExportHandler {
+	h := &ExportHandler{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Router:              NewRouter(b.HTTPErrorHandler),
+		Logger:              b.Logger,
+		ExportService:       b.ExportService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	// exported points can optionally be gzip encoded
+	h.Handler(http.MethodGet, prefixExport, gziphandler.GzipHandler(http.HandlerFunc(h.handleExport)))
+
+	return h
+}
+
+// handleExport streams the points of a bucket as line protocol. The status
+// of the response is only sent with the first points, so an error which
+// occurs before is returned as usual, and an error which occurs after ends
+// the response early.
+func (h *ExportHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "ExportHandler.handleExport")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	req, err := decodeExportRequest(ctx, r, h.OrganizationService, h.BucketService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ew := &exportResponseWriter{ResponseWriter: w}
+	if err := h.ExportService.ExportLineProtocol(ctx, *req, ew); err != nil {
+		if !ew.written {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		h.Logger.Info("Failed to export points",
+			zap.String("orgID", req.OrgID.String()),
+			zap.String("bucketID", req.BucketID.String()),
+			zap.Error(err))
+		return
+	}
+	ew.writeHeader()
+}
+
+// exportResponseWriter sends the status of the response with the first points
+// written to it.
+type exportResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *exportResponseWriter) writeHeader() {
+	if w.written {
+		return
+	}
+	w.written = true
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	w.writeHeader()
+	return w.ResponseWriter.Write(p)
+}
+
+func decodeExportRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService, bucketSvc influxdb.BucketService) (*influxdb.ExportRequest, error) {
+	o, err := queryOrganization(ctx, r, orgSvc)
+	if err != nil {
+		return nil, err
+	}
+	b, err := queryBucket(ctx, r, bucketSvc)
+	if err != nil {
+		return nil, err
+	}
+	if b.OrgID != o.ID {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "bucket does not belong to the organization",
+		}
+	}
+
+	req := influxdb.NewExportRequest(o.ID, b.ID)
+	qp := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		ts   *int64
+	}{
+		{exportStartParam, &req.Start},
+		{exportStopParam, &req.Stop},
+	} {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid RFC3339Nano for %s, please format your time with RFC3339Nano format, example: 2009-01-02T23:00:00Z", p.name),
+				Err:  err,
+			}
+		}
+		*p.ts = t.UnixNano()
+	}
+	req.Measurement = qp.Get(exportMeasurementParam)
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ExportService connects to Influx via HTTP using tokens to export points.
+type ExportService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.ExportService = (*ExportService)(nil)
+
+// ExportLineProtocol streams the points of the bucket of req to w as line
+// protocol.
+func (s *ExportService) ExportLineProtocol(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, prefixExport)
+	if err != nil {
+		return err
+	}
+	params := u.Query()
+	params.Set(OrgID, req.OrgID.String())
+	params.Set(BucketID, req.BucketID.String())
+	if req.Start != math.MinInt64 {
+		params.Set(exportStartParam, time.Unix(0, req.Start).UTC().Format(time.RFC3339Nano))
+	}
+	if req.Stop != math.MaxInt64 {
+		params.Set(exportStopParam, time.Unix(0, req.Stop).UTC().Format(time.RFC3339Nano))
+	}
+	if req.Measurement != "" {
+		params.Set(exportMeasurementParam, req.Measurement)
+	}
+	u.RawQuery = params.Encode()
+
+	hreq, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, hreq)
+	hreq = hreq.WithContext(ctx)
+
+	// the points of a bucket may take longer than any timeout to stream, so
+	// the export is only ended by ctx.
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestExportService_ExportLineProtocol(t *testing.T) {
+	var (
+		orgID      = influxdbtesting.MustIDBase16("0000000000000001")
+		bucketID   = influxdbtesting.MustIDBase16("0000000000000002")
+		otherOrgID = influxdbtesting.MustIDBase16("0000000000000003")
+		otherID    = influxdbtesting.MustIDBase16("0000000000000004")
+	)
+
+	orgSvc := mock.NewOrganizationService()
+	orgSvc.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+		return &influxdb.Organization{ID: *filter.ID, Name: "org"}, nil
+	}
+	bucketSvc := mock.NewBucketService()
+	bucketSvc.FindBucketFn = func(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		switch *filter.ID {
+		case bucketID:
+			return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: "bucket"}, nil
+		case otherID:
+			return &influxdb.Bucket{ID: otherID, OrgID: otherOrgID, Name: "other"}, nil
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+	}
+
+	var got influxdb.ExportRequest
+	exportSvc := mock.NewExportService()
+	exportSvc.ExportLineProtocolFn = func(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+		got = req
+		_, err := io.WriteString(w, "cpu,host=a value=1 1\n")
+		return err
+	}
+
+	h := NewExportHandler(&ExportBackend{
+		Logger:              zaptest.NewLogger(t),
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		ExportService:       exportSvc,
+		BucketService:       bucketSvc,
+		OrganizationService: orgSvc,
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := &influxdb.Authorization{Status: influxdb.Active, Permissions: influxdb.OperPermissions()}
+		h.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(r.Context(), a)))
+	}))
+	defer server.Close()
+	client := &ExportService{Addr: server.URL}
+
+	t.Run("exports the points of the bucket", func(t *testing.T) {
+		req := influxdb.NewExportRequest(orgID, bucketID)
+		req.Start, req.Stop = 1, 2e9
+		req.Measurement = "cpu"
+
+		var buf bytes.Buffer
+		if err := client.ExportLineProtocol(context.Background(), req, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(req, got); diff != "" {
+			t.Errorf("unexpected export request -want/+got:\n%s", diff)
+		}
+		if got, exp := buf.String(), "cpu,host=a value=1 1\n"; got != exp {
+			t.Errorf("got %q, exp %q", got, exp)
+		}
+	})
+
+	t.Run("exports all points without a time range", func(t *testing.T) {
+		req := influxdb.NewExportRequest(orgID, bucketID)
+		if err := client.ExportLineProtocol(context.Background(), req, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(req, got); diff != "" {
+			t.Errorf("unexpected export request -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("bucket of another org", func(t *testing.T) {
+		req := influxdb.NewExportRequest(orgID, otherID)
+		if err := client.ExportLineProtocol(context.Background(), req, &bytes.Buffer{}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+
+	t.Run("errors before any point is exported", func(t *testing.T) {
+		exportSvc.ExportLineProtocolFn = func(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+			return &influxdb.Error{Code: influxdb.EUnauthorized, Msg: "unauthorized"}
+		}
+		defer func() { exportSvc.ExportLineProtocolFn = mock.NewExportService().ExportLineProtocolFn }()
+
+		req := influxdb.NewExportRequest(orgID, bucketID)
+		if err := client.ExportLineProtocol(context.Background(), req, &bytes.Buffer{}); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			t.Errorf("expected unauthorized error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,516 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NYTimes/gziphandler"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/jsonweb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxdb/storage"
+	iql "github.com/influxdata/influxql"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixLegacyWrite = "/write"
+	prefixLegacyQuery = "/query"
+
+	// defaultLegacyChunkSize is the number of values in each chunk when a
+	// chunked query does not specify a chunk size, matching influxdb 1.x.
+	defaultLegacyChunkSize = 10000
+)
+
+// LegacyBackend is all services and associated parameters required to construct
+// the LegacyHandler.
+type LegacyBackend struct {
+	log                *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	MaxBatchSizeBytes    int64
+	WriteParserMaxBytes  int
+	WriteParserMaxLines  int
+	WriteParserMaxValues int
+	WriteStreamChunkSize int
+
+	PointsWriter        storage.PointsWriter
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+	DBRPMappingService  influxdb.DBRPMappingService
+	ProxyQueryService   query.ProxyQueryService
+}
+
+// NewLegacyBackend returns a new instance of LegacyBackend.
+func NewLegacyBackend(log *zap.Logger, b *APIBackend) *LegacyBackend {
+	return &LegacyBackend{
+		log:                log,
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		MaxBatchSizeBytes:    b.MaxBatchSizeBytes,
+		WriteParserMaxBytes:  b.WriteParserMaxBytes,
+		WriteParserMaxLines:  b.WriteParserMaxLines,
+		WriteParserMaxValues: b.WriteParserMaxValues,
+		WriteStreamChunkSize: b.WriteStreamChunkSize,
+
+		PointsWriter:        b.PointsWriter,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		DBRPMappingService:  b.DBRPMappingService,
+		ProxyQueryService:   b.InfluxQLService,
+	}
+}
+
+// LegacyHandler serves the influxdb 1.x /write and /query endpoints. The database
+// and retention policy of a request are resolved to a bucket with the DBRP mappings.
+type LegacyHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+	ProxyQueryService  query.ProxyQueryService
+
+	EventRecorder metric.EventRecorder
+
+	writeHandler *WriteHandler
+}
+
+// NewLegacyHandler creates a new handler at /write and /query for influxdb 1.x clients.
+func NewLegacyHandler(log *zap.Logger, b *LegacyBackend) *LegacyHandler {
+	var errorHandler legacyErrorHandler
+	h := &LegacyHandler{
+		Router:           NewRouter(errorHandler),
+		HTTPErrorHandler: errorHandler,
+		log:              log,
+
+		DBRPMappingService: b.DBRPMappingService,
+		ProxyQueryService:  b.ProxyQueryService,
+		EventRecorder:      b.QueryEventRecorder,
+	}
+
+	h.writeHandler = NewWriteHandler(log, &WriteBackend{
+		HTTPErrorHandler:    errorHandler,
+		log:                 log,
+		WriteEventRecorder:  b.WriteEventRecorder,
+		PointsWriter:        b.PointsWriter,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	},
+		WithMaxBatchSizeBytes(b.MaxBatchSizeBytes),
+		WithParserMaxBytes(b.WriteParserMaxBytes),
+		WithParserMaxLines(b.WriteParserMaxLines),
+		WithParserMaxValues(b.WriteParserMaxValues),
+		WithStreamChunkSize(b.WriteStreamChunkSize),
+	)
+	h.writeHandler.legacyPrecision = true
+
+	h.HandlerFunc("POST", prefixLegacyWrite, h.handleWrite)
+
+	// query reponses can optionally be gzip encoded
+	qh := gziphandler.GzipHandler(http.HandlerFunc(h.handleQuery))
+	h.Handler("GET", prefixLegacyQuery, qh)
+	h.Handler("POST", prefixLegacyQuery, qh)
+	return h
+}
+
+// findMapping returns the dbrp mapping for a database and retention policy.
+// When the retention policy is empty, the default mapping of the database is used.
+func (h *LegacyHandler) findMapping(ctx context.Context, db, rp string) (*influxdb.DBRPMapping, error) {
+	if db == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "database is required",
+		}
+	}
+
+	filter := influxdb.DBRPMappingFilter{Database: &db}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		isDefault := true
+		filter.Default = &isDefault
+	}
+
+	m, err := h.DBRPMappingService.Find(ctx, filter)
+	if err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return nil, &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  fmt.Sprintf("database not found: %q", strings.TrimSuffix(db+"/"+rp, "/")),
+			}
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// findQueryMappings returns the dbrp mappings of the databases read by the statements
+// of a query. A statement which does not name its database reads the database of the
+// request, which is only required then. Every database must belong to the same
+// organization, which runs the query.
+func (h *LegacyHandler) findQueryMappings(ctx context.Context, req *legacyQueryRequest) ([]*influxdb.DBRPMapping, error) {
+	q, err := iql.ParseQuery(req.Query)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to parse query",
+			Err:  err,
+		}
+	}
+
+	type dbrp struct{ db, rp string }
+	var sources []dbrp
+	add := func(db, rp string) {
+		for _, src := range sources {
+			if src.db == db && src.rp == rp {
+				return
+			}
+		}
+		sources = append(sources, dbrp{db: db, rp: rp})
+	}
+	for _, stmt := range q.Statements {
+		switch stmt := stmt.(type) {
+		case *iql.SelectStatement:
+			// the retention policy of the request is one of the database of the
+			// request, a database named by a statement is read with its default one.
+			for _, m := range stmt.Sources.Measurements() {
+				db, rp := m.Database, m.RetentionPolicy
+				if db == "" {
+					db = req.DB
+					if rp == "" {
+						rp = req.RP
+					}
+				}
+				add(db, rp)
+			}
+		case iql.HasDefaultDatabase:
+			// these statements read the default retention policy of their database.
+			db := stmt.DefaultDatabase()
+			if db == "" {
+				db = req.DB
+			}
+			add(db, "")
+		default:
+			add(req.DB, req.RP)
+		}
+	}
+	// a query which reads no database runs in the organization of the database of the request.
+	if len(sources) == 0 {
+		add(req.DB, req.RP)
+	}
+
+	mappings := make([]*influxdb.DBRPMapping, 0, len(sources))
+	for _, src := range sources {
+		m, err := h.findMapping(ctx, src.db, src.rp)
+		if err != nil {
+			return nil, err
+		}
+		if len(mappings) > 0 && m.OrganizationID != mappings[0].OrganizationID {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "the databases of a query must belong to the same organization",
+			}
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, nil
+}
+
+// handleWrite translates a 1.x write request into a write to the mapped bucket.
+func (h *LegacyHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qp := r.URL.Query()
+
+	m, err := h.findMapping(ctx, qp.Get("db"), qp.Get("rp"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// 1.x clients use single letter units for nanoseconds and microseconds,
+	// and may write timestamps in minutes and hours.
+	precision := qp.Get("precision")
+	switch precision {
+	case "n":
+		precision = "ns"
+	case "u":
+		precision = "us"
+	case "", "ns", "us", "ms", "s", "m", "h":
+	default:
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid precision; valid precision units are n, u, ms, s, m, and h",
+		}, w)
+		return
+	}
+
+	params := url.Values{}
+	params.Set(OrgID, m.OrganizationID.String())
+	params.Set(Bucket, m.BucketID.String())
+	if precision != "" {
+		params.Set("precision", precision)
+	}
+
+	wr := r.WithContext(ctx)
+	wr.URL = new(url.URL)
+	*wr.URL = *r.URL
+	wr.URL.RawQuery = params.Encode()
+	h.writeHandler.handleWrite(w, wr)
+}
+
+// handleQuery runs a 1.x InfluxQL query against the mapped bucket.
+func (h *LegacyHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleLegacyQuery"
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	log := h.log.With(logger.TraceFields(ctx)...)
+	if id, _, found := tracing.InfoFromContext(ctx); found {
+		w.Header().Set(traceIDHeader, id)
+	}
+
+	var orgID influxdb.ID
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	req, err := decodeLegacyQueryRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	mappings, err := h.findQueryMappings(ctx, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	m := mappings[0]
+	orgID = m.OrganizationID
+
+	var token *influxdb.Authorization
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		token = a
+	case *influxdb.Session:
+		token = a.EphemeralAuth(m.OrganizationID)
+	case *jsonweb.Token:
+		token = a.EphemeralAuth(m.OrganizationID)
+	default:
+		h.HandleHTTPError(ctx, influxdb.ErrAuthorizerNotSupported, w)
+		return
+	}
+
+	for _, m := range mappings {
+		p, err := influxdb.NewPermissionAtID(m.BucketID, influxdb.ReadAction, influxdb.BucketsResourceType, m.OrganizationID)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+				Op:   op,
+				Err:  err,
+			}, w)
+			return
+		}
+		if !token.Allowed(*p) {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Msg:  "insufficient permissions for query",
+				Op:   op,
+			}, w)
+			return
+		}
+	}
+
+	compiler := influxql.NewCompiler(h.DBRPMappingService)
+	compiler.Cluster = m.Cluster
+	compiler.DB = req.DB
+	compiler.RP = req.RP
+	compiler.Query = req.Query
+
+	dialect := &influxql.Dialect{
+		TimeFormat: req.TimeFormat,
+		Encoding:   influxql.JSON,
+		ChunkSize:  req.ChunkSize,
+	}
+
+	pr := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  token,
+			OrganizationID: m.OrganizationID,
+			Compiler:       compiler,
+			Source:         r.Header.Get("User-Agent"),
+		},
+		Dialect: dialect,
+	}
+
+	// Transform the context into one with the request's authorization.
+	ctx = pcontext.SetAuthorizer(ctx, token)
+
+	dialect.SetHeaders(w)
+
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, pr); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		_ = tracing.LogError(span, err)
+		log.Info("Error writing response to client",
+			zap.String("handler", "legacy"),
+			zap.Error(err),
+		)
+	}
+}
+
+type legacyQueryRequest struct {
+	DB         string
+	RP         string
+	Query      string
+	TimeFormat influxql.TimeFormat
+	ChunkSize  int
+}
+
+// decodeLegacyQueryRequest decodes the parameters of a 1.x query, which may be
+// in the url or in a form encoded body.
+func decodeLegacyQueryRequest(r *http.Request) (*legacyQueryRequest, error) {
+	req := &legacyQueryRequest{
+		DB:    r.FormValue("db"),
+		RP:    r.FormValue("rp"),
+		Query: r.FormValue("q"),
+	}
+	if req.Query == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  `missing required parameter "q"`,
+		}
+	}
+
+	switch epoch := r.FormValue("epoch"); epoch {
+	case "":
+		req.TimeFormat = influxql.RFC3339Nano
+	case "h":
+		req.TimeFormat = influxql.Hour
+	case "m":
+		req.TimeFormat = influxql.Minute
+	case "s":
+		req.TimeFormat = influxql.Second
+	case "ms":
+		req.TimeFormat = influxql.Millisecond
+	case "u", "us":
+		req.TimeFormat = influxql.Microsecond
+	case "n", "ns":
+		req.TimeFormat = influxql.Nanosecond
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid epoch %q; valid epochs are h, m, s, ms, u, and ns", epoch),
+		}
+	}
+
+	if chunked, _ := strconv.ParseBool(r.FormValue("chunked")); chunked {
+		req.ChunkSize = defaultLegacyChunkSize
+		if v := r.FormValue("chunk_size"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "chunk_size must be a positive integer",
+				}
+			}
+			req.ChunkSize = n
+		}
+	}
+
+	return req, nil
+}
+
+// legacyErrorHandler reports errors in the influxdb 1.x format, which clients
+// read from the error field of the response body.
+type legacyErrorHandler struct{}
+
+// HandleHTTPError encodes err with the status code for its platform error code.
+func (legacyErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		return
+	}
+
+	code := influxdb.ErrorCode(err)
+	w.Header().Set(kithttp.PlatformErrorCodeHeader, code)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(kithttp.ErrorCodeToStatusCode(code))
+
+	var e struct {
+		Err string `json:"error"`
+	}
+	if err, ok := err.(*influxdb.Error); ok {
+		e.Err = err.Error()
+	} else {
+		e.Err = "An internal error has occurred"
+	}
+	b, _ := json.Marshal(e)
+	_, _ = w.Write(b)
+}
+
+// legacyTokenHandler lets influxdb 1.x clients authenticate to the /write and
+// /query endpoints by passing a token as the password, either with the p query
+// parameter or with basic authentication. The username is ignored.
+//
+// Prometheus servers and clients of the Prometheus query API, such as Grafana,
+// authenticate to the /api/v1 endpoints by passing a token as the password with
+// basic authentication or as a bearer token.
+func legacyTokenHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == prefixLegacyWrite || r.URL.Path == prefixLegacyQuery:
+			if _, password, ok := r.BasicAuth(); ok {
+				SetToken(password, r)
+			} else if password := r.URL.Query().Get("p"); password != "" && r.Header.Get("Authorization") == "" {
+				SetToken(password, r)
+			}
+		case strings.HasPrefix(r.URL.Path, prefixProm+"/") || isPromQueryPath(r.URL.Path):
+			if _, password, ok := r.BasicAuth(); ok {
+				SetToken(password, r)
+			} else if token := r.Header.Get("Authorization"); strings.HasPrefix(token, "Bearer ") {
+				SetToken(strings.TrimPrefix(token, "Bearer "), r)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,405 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	influxtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLegacyHandler_handleWrite(t *testing.T) {
+	mapping := &influxdb.DBRPMapping{
+		Cluster:         "cluster",
+		Database:        "db0",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  influxtesting.MustIDBase16("043e0780ee2b1000"),
+		BucketID:        influxtesting.MustIDBase16("04504b356e23b000"),
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		auth   influxdb.Authorizer
+		filter influxdb.DBRPMappingFilter
+		code   int
+		body   string
+		time   time.Time
+	}{
+		{
+			name:   "default retention policy",
+			query:  "db=db0&precision=s",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db0"), Default: boolPtr(true)},
+			code:   http.StatusNoContent,
+		},
+		{
+			name:   "explicit retention policy",
+			query:  "db=db0&rp=autogen&precision=n",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db0"), RetentionPolicy: strPtr("autogen")},
+			code:   http.StatusNoContent,
+		},
+		{
+			name:   "hour precision",
+			query:  "db=db0&precision=h",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db0"), Default: boolPtr(true)},
+			code:   http.StatusNoContent,
+			time:   time.Unix(0, 0).Add(time.Hour),
+		},
+		{
+			name:   "minute precision",
+			query:  "db=db0&precision=m",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db0"), Default: boolPtr(true)},
+			code:   http.StatusNoContent,
+			time:   time.Unix(0, 0).Add(time.Minute),
+		},
+		{
+			name:   "invalid precision",
+			query:  "db=db0&precision=d",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db0"), Default: boolPtr(true)},
+			code:   http.StatusBadRequest,
+			body:   `{"error":"invalid precision; valid precision units are n, u, ms, s, m, and h"}`,
+		},
+		{
+			name:   "unknown database",
+			query:  "db=db1",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db1"), Default: boolPtr(true)},
+			code:   http.StatusNotFound,
+			body:   `{"error":"database not found: \"db1\""}`,
+		},
+		{
+			name:  "missing database",
+			query: "",
+			auth:  bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusBadRequest,
+			body:  `{"error":"database is required"}`,
+		},
+		{
+			name:   "insufficient permissions",
+			query:  "db=db0",
+			auth:   bucketWritePermission("043e0780ee2b1000", "0000000000000001"),
+			filter: influxdb.DBRPMappingFilter{Database: strPtr("db0"), Default: boolPtr(true)},
+			code:   http.StatusForbidden,
+			body:   `{"error":"insufficient permissions for write"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbrps := mock.NewDBRPMappingService()
+			dbrps.FindFn = func(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+				if diff := cmp.Diff(tt.filter, filter); diff != "" {
+					t.Errorf("unexpected filter -want/+got:\n%s", diff)
+				}
+				if *filter.Database != mapping.Database {
+					return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "dbrp mapping not found"}
+				}
+				return mapping, nil
+			}
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return testOrg(filter.ID.String()), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return testBucket(filter.OrganizationID.String(), filter.ID.String()), nil
+			}
+
+			pw := &mock.PointsWriter{}
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				DBRPMappingService:  dbrps,
+				PointsWriter:        pw,
+				WriteEventRecorder:  &metric.NopEventRecorder{},
+				QueryEventRecorder:  &metric.NopEventRecorder{},
+			}
+			h := NewLegacyHandler(zaptest.NewLogger(t), NewLegacyBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(h, tt.auth)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999/write?"+tt.query, strings.NewReader("m1,t1=v1 f1=1 1"))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+			if !tt.time.IsZero() {
+				if len(pw.Points) != 1 {
+					t.Fatalf("expected 1 point written, got %d", len(pw.Points))
+				}
+				if got, want := pw.Points[0].Time(), tt.time; !got.Equal(want) {
+					t.Errorf("unexpected point time: got %v want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLegacyHandler_handleQuery(t *testing.T) {
+	mapping := &influxdb.DBRPMapping{
+		Cluster:         "cluster",
+		Database:        "db0",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  influxtesting.MustIDBase16("043e0780ee2b1000"),
+		BucketID:        influxtesting.MustIDBase16("04504b356e23b000"),
+	}
+	// the mappings by database, and by database and retention policy when one is given.
+	mappings := map[string]*influxdb.DBRPMapping{
+		"db0":         mapping,
+		"db0/autogen": mapping,
+		"db1": {
+			Cluster:         "cluster",
+			Database:        "db1",
+			RetentionPolicy: "autogen",
+			Default:         true,
+			OrganizationID:  influxtesting.MustIDBase16("043e0780ee2b1000"),
+			BucketID:        influxtesting.MustIDBase16("04504b356e23b001"),
+		},
+		"db2": {
+			Cluster:         "cluster",
+			Database:        "db2",
+			RetentionPolicy: "autogen",
+			Default:         true,
+			OrganizationID:  influxtesting.MustIDBase16("043e0780ee2b1001"),
+			BucketID:        influxtesting.MustIDBase16("04504b356e23b002"),
+		},
+	}
+
+	readBuckets := func(buckets ...string) *influxdb.Authorization {
+		a := bucketReadPermission("043e0780ee2b1000", buckets[0])
+		for _, b := range buckets[1:] {
+			a.Permissions = append(a.Permissions, bucketReadPermission("043e0780ee2b1000", b).Permissions...)
+		}
+		return a
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		auth     influxdb.Authorizer
+		compiler *influxql.Compiler
+		dialect  *influxql.Dialect
+		code     int
+		body     string
+	}{
+		{
+			name:     "query",
+			query:    "db=db0&q=SELECT+f1+FROM+m1",
+			auth:     bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			compiler: &influxql.Compiler{Cluster: "cluster", DB: "db0", Query: "SELECT f1 FROM m1"},
+			dialect:  &influxql.Dialect{TimeFormat: influxql.RFC3339Nano, Encoding: influxql.JSON},
+			code:     http.StatusOK,
+			body:     `{"results":[]}`,
+		},
+		{
+			name:     "chunked with epoch",
+			query:    "db=db0&rp=autogen&epoch=ms&chunked=true&chunk_size=100&q=SELECT+f1+FROM+m1",
+			auth:     bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			compiler: &influxql.Compiler{Cluster: "cluster", DB: "db0", RP: "autogen", Query: "SELECT f1 FROM m1"},
+			dialect:  &influxql.Dialect{TimeFormat: influxql.Millisecond, Encoding: influxql.JSON, ChunkSize: 100},
+			code:     http.StatusOK,
+			body:     `{"results":[]}`,
+		},
+		{
+			name:     "database of the statements",
+			query:    "q=SELECT+f1+FROM+db0.autogen.m1%3B+SHOW+TAG+VALUES+ON+db0+WITH+KEY+%3D+t1",
+			auth:     bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			compiler: &influxql.Compiler{Cluster: "cluster", Query: "SELECT f1 FROM db0.autogen.m1; SHOW TAG VALUES ON db0 WITH KEY = t1"},
+			dialect:  &influxql.Dialect{TimeFormat: influxql.RFC3339Nano, Encoding: influxql.JSON},
+			code:     http.StatusOK,
+			body:     `{"results":[]}`,
+		},
+		{
+			name:     "default retention policy of the database of a statement",
+			query:    "db=db0&rp=autogen&q=SELECT+f1+FROM+m1%3B+SELECT+f1+FROM+db1..m1",
+			auth:     readBuckets("04504b356e23b000", "04504b356e23b001"),
+			compiler: &influxql.Compiler{Cluster: "cluster", DB: "db0", RP: "autogen", Query: "SELECT f1 FROM m1; SELECT f1 FROM db1..m1"},
+			dialect:  &influxql.Dialect{TimeFormat: influxql.RFC3339Nano, Encoding: influxql.JSON},
+			code:     http.StatusOK,
+			body:     `{"results":[]}`,
+		},
+		{
+			name:  "statement without a database",
+			query: "q=SELECT+f1+FROM+db0..m1%3B+SELECT+f1+FROM+m1",
+			auth:  bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusBadRequest,
+			body:  `{"error":"database is required"}`,
+		},
+		{
+			name:  "insufficient permissions for the database of a statement",
+			query: "db=db0&q=SELECT+f1+FROM+m1%3B+SELECT+f1+FROM+db1..m1",
+			auth:  bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusForbidden,
+			body:  `{"error":"insufficient permissions for query"}`,
+		},
+		{
+			name:  "databases of different organizations",
+			query: "db=db0&q=SELECT+f1+FROM+m1%3B+SELECT+f1+FROM+db2..m1",
+			auth:  bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusBadRequest,
+			body:  `{"error":"the databases of a query must belong to the same organization"}`,
+		},
+		{
+			name:  "missing query",
+			query: "db=db0",
+			auth:  bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusBadRequest,
+			body:  `{"error":"missing required parameter \"q\""}`,
+		},
+		{
+			name:  "invalid epoch",
+			query: "db=db0&epoch=d&q=SELECT+f1+FROM+m1",
+			auth:  bucketReadPermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusBadRequest,
+			body:  `{"error":"invalid epoch \"d\"; valid epochs are h, m, s, ms, u, and ns"}`,
+		},
+		{
+			name:  "insufficient permissions",
+			query: "db=db0&q=SELECT+f1+FROM+m1",
+			auth:  bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			code:  http.StatusForbidden,
+			body:  `{"error":"insufficient permissions for query"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbrps := mock.NewDBRPMappingService()
+			dbrps.FindFn = func(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+				key := *filter.Database
+				if filter.RetentionPolicy != nil {
+					key += "/" + *filter.RetentionPolicy
+				}
+				m, ok := mappings[key]
+				if !ok {
+					return nil, &influxdb.Error{Code: influxdb.ENotFound}
+				}
+				return m, nil
+			}
+			queries := &querymock.ProxyQueryService{
+				QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+					compiler := req.Request.Compiler.(*influxql.Compiler)
+					if compiler.Cluster != tt.compiler.Cluster || compiler.DB != tt.compiler.DB ||
+						compiler.RP != tt.compiler.RP || compiler.Query != tt.compiler.Query {
+						t.Errorf("unexpected compiler: got %+v want %+v", compiler, tt.compiler)
+					}
+					if got, want := *req.Dialect.(*influxql.Dialect), *tt.dialect; got != want {
+						t.Errorf("unexpected dialect: got %+v want %+v", got, want)
+					}
+					if got, want := req.Request.OrganizationID, mapping.OrganizationID; got != want {
+						t.Errorf("unexpected organization: got %s want %s", got, want)
+					}
+					_, err := io.WriteString(w, `{"results":[]}`)
+					return flux.Statistics{}, err
+				},
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:   DefaultErrorHandler,
+				Logger:             zaptest.NewLogger(t),
+				DBRPMappingService: dbrps,
+				InfluxQLService:    queries,
+				WriteEventRecorder: &metric.NopEventRecorder{},
+				QueryEventRecorder: &metric.NopEventRecorder{},
+			}
+			h := NewLegacyHandler(zaptest.NewLogger(t), NewLegacyBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(h, tt.auth)
+
+			r := httptest.NewRequest("GET", "http://localhost:9999/query?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestLegacyTokenHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   func() *http.Request
+		token string
+	}{
+		{
+			name: "password parameter",
+			req: func() *http.Request {
+				return httptest.NewRequest("GET", "http://localhost:9999/query?u=me&p=secret", nil)
+			},
+			token: "Token secret",
+		},
+		{
+			name: "basic auth",
+			req: func() *http.Request {
+				r := httptest.NewRequest("POST", "http://localhost:9999/write", nil)
+				r.SetBasicAuth("me", "secret")
+				return r
+			},
+			token: "Token secret",
+		},
+		{
+			name: "token header",
+			req: func() *http.Request {
+				r := httptest.NewRequest("POST", "http://localhost:9999/write?p=ignored", nil)
+				SetToken("secret", r)
+				return r
+			},
+			token: "Token secret",
+		},
+		{
+			name: "other endpoints",
+			req: func() *http.Request {
+				return httptest.NewRequest("GET", "http://localhost:9999/api/v2/buckets?p=secret", nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var token string
+			h := legacyTokenHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				token = r.Header.Get("Authorization")
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), tt.req())
+			if token != tt.token {
+				t.Errorf("unexpected authorization header: got %q want %q", token, tt.token)
+			}
+		})
+	}
+}
+
+func bucketReadPermission(org, bucket string) *influxdb.Authorization {
+	a := bucketWritePermission(org, bucket)
+	a.Permissions[0].Action = influxdb.ReadAction
+	return a
+}
+
+func strPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
@@ -33,7 +33,7 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	assetHandler := NewAssetHandler()
 	assetHandler.Path = b.AssetsPath
 
-	wrappedHandler := kithttp.SetCORS(h)
+	wrappedHandler := kithttp.SetCORS(legacyTokenHandler(h))
 	wrappedHandler = kithttp.SkipOptions(wrappedHandler)
 
 	return &PlatformHandler{
@@ -53,6 +53,10 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
+		r.URL.Path != prefixLegacyWrite &&
+		r.URL.Path != prefixLegacyQuery &&
+		!strings.HasPrefix(r.URL.Path, prefixProm) &&
+		!isPromQueryPath(r.URL.Path) &&
 		!strings.HasPrefix(r.URL.Path, "/api/v2") &&
 		!strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.AssetHandler.ServeHTTP(w, r)
@@ -0,0 +1,405 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixProm      = "/api/v1/prom"
+	prefixPromWrite = prefixProm + "/write"
+	prefixPromRead  = prefixProm + "/read"
+
+	prefixPromQuery      = "/api/v1/query"
+	prefixPromQueryRange = "/api/v1/query_range"
+	prefixPromSeries     = "/api/v1/series"
+	prefixPromLabels     = "/api/v1/labels"
+)
+
+// promQueryPrefixes are the prefixes of the Prometheus query API, which are
+// mounted next to the remote storage endpoints at prefixProm.
+var promQueryPrefixes = []string{
+	prefixPromQuery,
+	prefixPromQueryRange,
+	prefixPromSeries,
+	prefixPromLabels,
+}
+
+// isPromQueryPath returns whether the path is of the Prometheus query API.
+func isPromQueryPath(path string) bool {
+	for _, prefix := range promQueryPrefixes {
+		if path == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// PromBackend is all services and associated parameters required to construct
+// the PromHandler.
+type PromBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	MaxBatchSizeBytes    int64
+	WriteParserMaxValues int
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	ProxyQueryService   query.ProxyQueryService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewPromBackend returns a new instance of PromBackend.
+func NewPromBackend(log *zap.Logger, b *APIBackend) *PromBackend {
+	return &PromBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		MaxBatchSizeBytes:    b.MaxBatchSizeBytes,
+		WriteParserMaxValues: b.WriteParserMaxValues,
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		ProxyQueryService:   b.FluxService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// PromHandler serves the Prometheus remote storage endpoints and the Prometheus
+// query API. The bucket of a request is chosen with the org and bucket query
+// parameters, as with writes to /api/v2/write. See package prometheus/remote
+// for how samples map to points, and package query/promql for how queries are
+// evaluated.
+type PromHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+
+	PointsWriter      storage.PointsWriter
+	ReadStore         reads.Store
+	ProxyQueryService query.ProxyQueryService
+
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	maxBatchSizeBytes int64
+	maxValues         int
+}
+
+// NewPromHandler creates a new handler at /api/v1/prom to receive Prometheus
+// remote write and remote read requests, and at /api/v1/query, query_range,
+// series and labels to serve Prometheus queries.
+func NewPromHandler(log *zap.Logger, b *PromBackend) *PromHandler {
+	h := &PromHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		ProxyQueryService:   b.ProxyQueryService,
+		WriteEventRecorder:  b.WriteEventRecorder,
+		QueryEventRecorder:  b.QueryEventRecorder,
+
+		maxBatchSizeBytes: b.MaxBatchSizeBytes,
+		maxValues:         b.WriteParserMaxValues,
+	}
+
+	h.HandlerFunc("POST", prefixPromWrite, h.handleWrite)
+	h.HandlerFunc("POST", prefixPromRead, h.handleRead)
+
+	h.HandlerFunc("GET", prefixPromQuery, h.handleQuery)
+	h.HandlerFunc("POST", prefixPromQuery, h.handleQuery)
+	h.HandlerFunc("GET", prefixPromQueryRange, h.handleQueryRange)
+	h.HandlerFunc("POST", prefixPromQueryRange, h.handleQueryRange)
+	h.HandlerFunc("GET", prefixPromSeries, h.handleSeries)
+	h.HandlerFunc("POST", prefixPromSeries, h.handleSeries)
+	h.HandlerFunc("GET", prefixPromLabels, h.handleLabels)
+	h.HandlerFunc("POST", prefixPromLabels, h.handleLabels)
+	return h
+}
+
+// Prefix provides the route prefix.
+func (*PromHandler) Prefix() string {
+	return prefixProm
+}
+
+// findBucket returns the organization and bucket of a request, and checks that
+// the authorizer of the request has permission to perform action on the bucket.
+func (h *PromHandler) findBucket(r *http.Request, action influxdb.Action) (*influxdb.Organization, *influxdb.Bucket, error) {
+	ctx := r.Context()
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bucket, err := queryBucket(ctx, r, h.BucketService)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bucket.OrgID != org.ID {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "bucket not found",
+		}
+	}
+
+	p, err := influxdb.NewPermissionAtID(bucket.ID, action, influxdb.BucketsResourceType, org.ID)
+	if err != nil {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}
+	}
+	if !a.Allowed(*p) {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions to %s bucket", action),
+		}
+	}
+	return org, bucket, nil
+}
+
+// handleWrite writes the samples of a snappy compressed remote write request.
+func (h *PromHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handlePromWrite"
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var (
+		orgID       influxdb.ID
+		cr          = &countingReader{r: r.Body}
+		sw          = kithttp.NewStatusResponseWriter(w)
+		handleError = func(err error, code, message string) {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: code,
+				Op:   op,
+				Msg:  message,
+				Err:  err,
+			}, w)
+		}
+	)
+	w = sw
+	defer func() {
+		h.WriteEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  cr.n,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	org, bucket, err := h.findBucket(r, influxdb.WriteAction)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	orgID = org.ID
+	span.LogKV("org_id", org.ID, "bucket_id", bucket.ID)
+	log := h.log.With(zap.Stringer("org_id", org.ID), zap.Stringer("bucket_id", bucket.ID))
+
+	req, err := remote.DecodeWriteRequest(cr, h.maxBatchSizeBytes)
+	if err != nil {
+		log.Error("Error decoding remote write request", zap.Error(err))
+		code := influxdb.EInvalid
+		if errors.Is(err, remote.ErrMaxSizeExceeded) {
+			code = influxdb.ETooLarge
+		}
+		handleError(err, code, "unable to decode remote write request")
+		return
+	}
+
+	encoded := tsdb.EncodeName(org.ID, bucket.ID)
+	points, err := remote.Points(string(encoded[:]), req)
+	if err != nil {
+		handleError(err, influxdb.EInvalid, "")
+		return
+	}
+	if h.maxValues > 0 && len(points) > h.maxValues {
+		handleError(models.ErrLimitMaxValuesExceeded, influxdb.ETooLarge, "")
+		return
+	}
+	span.LogKV("values_total", len(points))
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		log.Error("Error writing points", zap.Error(err))
+		if code := writeErrorCode(err); code != influxdb.EInternal {
+			handleError(err, code, "failure writing points to database")
+			return
+		}
+		handleError(err, influxdb.EInternal, "unexpected error writing points to database")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRead responds with the series matching the queries of a snappy
+// compressed remote read request, either as a single response of samples or as
+// a stream of XOR encoded chunks, as preferred by the client.
+func (h *PromHandler) handleRead(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handlePromRead"
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var (
+		orgID       influxdb.ID
+		sw          = kithttp.NewStatusResponseWriter(w)
+		handleError = func(err error, code, message string) {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: code,
+				Op:   op,
+				Msg:  message,
+				Err:  err,
+			}, w)
+		}
+	)
+	w = sw
+	defer func() {
+		h.QueryEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	org, bucket, err := h.findBucket(r, influxdb.ReadAction)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	orgID = org.ID
+	span.LogKV("org_id", org.ID, "bucket_id", bucket.ID)
+	log := h.log.With(zap.Stringer("org_id", org.ID), zap.Stringer("bucket_id", bucket.ID))
+
+	req, err := remote.DecodeReadRequest(r.Body, h.maxBatchSizeBytes)
+	if err != nil {
+		code := influxdb.EInvalid
+		if errors.Is(err, remote.ErrMaxSizeExceeded) {
+			code = influxdb.ETooLarge
+		}
+		handleError(err, code, "unable to decode remote read request")
+		return
+	}
+	for _, q := range req.Queries {
+		if _, err := remote.Predicate(q.Matchers); err != nil {
+			handleError(err, influxdb.EInvalid, "")
+			return
+		}
+	}
+
+	if remote.ResponseType(req) == remote.ReadRequest_STREAMED_XOR_CHUNKS {
+		h.readStream(w, r, log, org.ID, bucket.ID, req)
+		return
+	}
+
+	resp := &remote.ReadResponse{Results: make([]*remote.QueryResult, len(req.Queries))}
+	for i, q := range req.Queries {
+		result := &remote.QueryResult{}
+		err := remote.ReadTimeSeries(ctx, h.ReadStore, org.ID, bucket.ID, q, func(ts *remote.TimeSeries) error {
+			result.Timeseries = append(result.Timeseries, ts)
+			return nil
+		})
+		if err != nil {
+			log.Error("Error reading series", zap.Error(err))
+			handleError(err, influxdb.EInternal, "unexpected error reading series")
+			return
+		}
+		resp.Results[i] = result
+	}
+
+	w.Header().Set("Content-Type", remote.ContentType)
+	w.Header().Set("Content-Encoding", "snappy")
+	if err := remote.EncodeReadResponse(w, resp); err != nil {
+		log.Info("Error writing response to client", zap.Error(err))
+	}
+}
+
+// readStream writes every series matching the queries of req as a frame of XOR
+// encoded chunks, as the series are read. Once the first frame is written an
+// error can no longer be reported to the client, which sees the truncated stream.
+func (h *PromHandler) readStream(w http.ResponseWriter, r *http.Request, log *zap.Logger, orgID, bucketID influxdb.ID, req *remote.ReadRequest) {
+	ctx := r.Context()
+	w.Header().Set("Content-Type", remote.StreamedContentType)
+
+	var (
+		cw      = remote.NewChunkedWriter(w)
+		written bool
+	)
+	for i, q := range req.Queries {
+		err := remote.ReadTimeSeries(ctx, h.ReadStore, orgID, bucketID, q, func(ts *remote.TimeSeries) error {
+			written = true
+			return cw.Write(&remote.ChunkedReadResponse{
+				ChunkedSeries: []*remote.ChunkedSeries{remote.EncodeChunks(ts)},
+				QueryIndex:    int64(i),
+			})
+		})
+		if err != nil {
+			if !written {
+				h.HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.EInternal,
+					Op:   "http/handlePromRead",
+					Msg:  "unexpected error reading series",
+					Err:  err,
+				}, w)
+				return
+			}
+			log.Info("Error streaming series to client", zap.Error(err))
+			return
+		}
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"go.uber.org/zap/zaptest"
+)
+
+func encodeRemoteWriteRequest(t *testing.T, req *remote.WriteRequest) []byte {
+	t.Helper()
+	data, err := req.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snappy.Encode(nil, data)
+}
+
+func TestPromHandler_handleWrite(t *testing.T) {
+	const (
+		orgID    = "043e0780ee2b1000"
+		bucketID = "04504b356e23b000"
+	)
+
+	series := &remote.WriteRequest{
+		Timeseries: []*remote.TimeSeries{
+			{
+				Labels: []*remote.Label{
+					{Name: "__name__", Value: "up"},
+					{Name: "job", Value: "node"},
+				},
+				Samples: []*remote.Sample{
+					{Value: 1, Timestamp: 1590000000000},
+					{Value: 0, Timestamp: 1590000015000},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		auth    influxdb.Authorizer
+		body    []byte
+		backend func(*APIBackend)
+		code    int
+		points  int
+	}{
+		{
+			name:   "samples are written",
+			auth:   bucketWritePermission(orgID, bucketID),
+			body:   encodeRemoteWriteRequest(t, series),
+			code:   204,
+			points: 2,
+		},
+		{
+			name: "write permission is required",
+			auth: bucketWritePermission(orgID, "0000000000000001"),
+			body: encodeRemoteWriteRequest(t, series),
+			code: 403,
+		},
+		{
+			name: "body must be snappy compressed",
+			auth: bucketWritePermission(orgID, bucketID),
+			body: []byte("up,job=node value=1"),
+			code: 400,
+		},
+		{
+			name: "time series requires a metric name",
+			auth: bucketWritePermission(orgID, bucketID),
+			body: encodeRemoteWriteRequest(t, &remote.WriteRequest{
+				Timeseries: []*remote.TimeSeries{
+					{
+						Labels:  []*remote.Label{{Name: "job", Value: "node"}},
+						Samples: []*remote.Sample{{Value: 1, Timestamp: 1590000000000}},
+					},
+				},
+			}),
+			code: 400,
+		},
+		{
+			name: "decompressed request exceeds the max batch size",
+			auth: bucketWritePermission(orgID, bucketID),
+			body: encodeRemoteWriteRequest(t, series),
+			backend: func(b *APIBackend) {
+				b.MaxBatchSizeBytes = 8
+			},
+			code: 413,
+		},
+		{
+			name: "samples exceed the max values",
+			auth: bucketWritePermission(orgID, bucketID),
+			body: encodeRemoteWriteRequest(t, series),
+			backend: func(b *APIBackend) {
+				b.WriteParserMaxValues = 1
+			},
+			code: 413,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return testOrg(orgID), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return testBucket(orgID, bucketID), nil
+			}
+			pw := &mock.PointsWriter{}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				PointsWriter:        pw,
+				WriteEventRecorder:  &metric.NopEventRecorder{},
+			}
+			if tt.backend != nil {
+				tt.backend(b)
+			}
+			promHandler := NewPromHandler(zaptest.NewLogger(t), NewPromBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(promHandler, tt.auth)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999/api/v1/prom/write?org="+orgID+"&bucket="+bucketID, bytes.NewReader(tt.body))
+			r.Header.Set("Content-Encoding", "snappy")
+			r.Header.Set("Content-Type", "application/x-protobuf")
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d, body: %s", got, want, w.Body.String())
+			}
+			if got, want := len(pw.Points), tt.points; got != want {
+				t.Errorf("unexpected number of points written: got %d want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestPromHandler_handleRead(t *testing.T) {
+	const (
+		orgID    = "043e0780ee2b1000"
+		bucketID = "04504b356e23b000"
+	)
+
+	// the store holds a single series, up{job="node"}, with two samples.
+	store := mock.NewStoreReader()
+	store.ReadFilterFunc = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		var read, next bool
+		cur := mock.NewFloatArrayCursor()
+		cur.NextFunc = func() *cursors.FloatArray {
+			if read {
+				return &cursors.FloatArray{}
+			}
+			read = true
+			return &cursors.FloatArray{
+				Timestamps: []int64{1590000000000000000, 1590000015000000000},
+				Values:     []float64{1, 0},
+			}
+		}
+
+		rs := mock.NewResultSet()
+		rs.NextFunc = func() bool {
+			if next {
+				return false
+			}
+			next = true
+			return true
+		}
+		rs.CursorFunc = func() cursors.Cursor { return cur }
+		rs.TagsFunc = func() models.Tags {
+			return models.NewTags(map[string]string{
+				models.MeasurementTagKey: "up",
+				models.FieldKeyTagKey:    "value",
+				"job":                    "node",
+			})
+		}
+		return rs, nil
+	}
+
+	series := &remote.TimeSeries{
+		Labels: []*remote.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "node"}},
+		Samples: []*remote.Sample{
+			{Value: 1, Timestamp: 1590000000000},
+			{Value: 0, Timestamp: 1590000015000},
+		},
+	}
+
+	newRequest := func(t *testing.T, accepted ...remote.ReadRequest_ResponseType) []byte {
+		data, err := (&remote.ReadRequest{
+			Queries: []*remote.Query{
+				{
+					StartTimestampMs: 1590000000000,
+					EndTimestampMs:   1590000015000,
+					Matchers:         []*remote.LabelMatcher{{Type: remote.LabelMatcher_EQ, Name: "__name__", Value: "up"}},
+				},
+			},
+			AcceptedResponseTypes: accepted,
+		}).Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return snappy.Encode(nil, data)
+	}
+
+	tests := []struct {
+		name  string
+		auth  influxdb.Authorizer
+		body  []byte
+		code  int
+		check func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "samples response",
+			auth: bucketReadPermission(orgID, bucketID),
+			body: newRequest(t),
+			code: 200,
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				if got, want := w.Header().Get("Content-Encoding"), "snappy"; got != want {
+					t.Errorf("unexpected content encoding; got %q, want %q", got, want)
+				}
+				data, err := snappy.Decode(nil, w.Body.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got remote.ReadResponse
+				if err := got.Unmarshal(data); err != nil {
+					t.Fatal(err)
+				}
+				want := remote.ReadResponse{
+					Results: []*remote.QueryResult{{Timeseries: []*remote.TimeSeries{series}}},
+				}
+				if !cmp.Equal(want, got) {
+					t.Errorf("unexpected response -want/+got:\n%s", cmp.Diff(want, got))
+				}
+			},
+		},
+		{
+			name: "streamed chunks response",
+			auth: bucketReadPermission(orgID, bucketID),
+			body: newRequest(t, remote.ReadRequest_STREAMED_XOR_CHUNKS, remote.ReadRequest_SAMPLES),
+			code: 200,
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				if got, want := w.Header().Get("Content-Type"), remote.StreamedContentType; got != want {
+					t.Errorf("unexpected content type; got %q, want %q", got, want)
+				}
+
+				var want bytes.Buffer
+				if err := remote.NewChunkedWriter(&want).Write(&remote.ChunkedReadResponse{
+					ChunkedSeries: []*remote.ChunkedSeries{remote.EncodeChunks(series)},
+				}); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(want.Bytes(), w.Body.Bytes()) {
+					t.Errorf("unexpected response; got %x, want %x", w.Body.Bytes(), want.Bytes())
+				}
+			},
+		},
+		{
+			name: "read permission is required",
+			auth: bucketWritePermission(orgID, bucketID),
+			body: newRequest(t),
+			code: 403,
+		},
+		{
+			name: "invalid regular expression",
+			auth: bucketReadPermission(orgID, bucketID),
+			body: func() []byte {
+				data, _ := (&remote.ReadRequest{
+					Queries: []*remote.Query{
+						{Matchers: []*remote.LabelMatcher{{Type: remote.LabelMatcher_RE, Name: "job", Value: "("}}},
+					},
+				}).Marshal()
+				return snappy.Encode(nil, data)
+			}(),
+			code: 400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return testOrg(orgID), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return testBucket(orgID, bucketID), nil
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				ReadStore:           store,
+				QueryEventRecorder:  &metric.NopEventRecorder{},
+			}
+			promHandler := NewPromHandler(zaptest.NewLogger(t), NewPromBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(promHandler, tt.auth)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999/api/v1/prom/read?org="+orgID+"&bucket="+bucketID, bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Fatalf("unexpected status code: got %d want %d, body: %s", got, want, w.Body.String())
+			}
+			if tt.check != nil {
+				tt.check(t, w)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/promql"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+)
+
+// maxPromQueryPoints is the maximum number of evaluations of a range query,
+// which is the same as the limit of Prometheus.
+const maxPromQueryPoints = 11000
+
+// The types of the errors of the Prometheus query API.
+const (
+	promErrorBadData   = "bad_data"
+	promErrorExecution = "execution"
+	promErrorNotFound  = "not_found"
+	promErrorInternal  = "internal"
+)
+
+// promQueryResponse is the response of the Prometheus query API.
+type promQueryResponse struct {
+	Status    string      `json:"status"`
+	Data      interface{} `json:"data,omitempty"`
+	ErrorType string      `json:"errorType,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// promQueryData is the data of the response of a query.
+type promQueryData struct {
+	ResultType promql.ResultType `json:"resultType"`
+	Result     []*promSeries     `json:"result"`
+}
+
+// promSeries is a series of a vector, which has a value,
+// or of a matrix, which has values.
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  *promSample       `json:"value,omitempty"`
+	Values []promSample      `json:"values,omitempty"`
+}
+
+// promSample is encoded as a pair of the unix time in seconds and the value as a string.
+type promSample promql.Sample
+
+func (s promSample) MarshalJSON() ([]byte, error) {
+	b := []byte{'['}
+	b = strconv.AppendFloat(b, float64(s.Time.UnixNano())/1e9, 'f', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendQuote(b, strconv.FormatFloat(s.Value, 'f', -1, 64))
+	return append(b, ']'), nil
+}
+
+// promAPIError is an error of a request to the Prometheus query API.
+type promAPIError struct {
+	typ string
+	err error
+}
+
+func (e *promAPIError) Error() string {
+	return e.err.Error()
+}
+
+func promBadData(err error) error {
+	return &promAPIError{typ: promErrorBadData, err: err}
+}
+
+// handleQuery evaluates an instant query at the time parameter, which is now by default.
+func (h *PromHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	h.servePromQuery(w, r, "http/handlePromQuery", func(ctx context.Context, r *http.Request, org *influxdb.Organization, bucket *influxdb.Bucket) (interface{}, error) {
+		ts, err := promFormTime(r, "time", time.Now())
+		if err != nil {
+			return nil, promBadData(err)
+		}
+		eval := promql.Evaluation{
+			BucketID: bucket.ID.String(),
+			End:      ts,
+		}
+		return h.evaluate(ctx, r, org, &eval, r.FormValue("query"))
+	})
+}
+
+// handleQueryRange evaluates a range query at each step from the start parameter to the end parameter.
+func (h *PromHandler) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	h.servePromQuery(w, r, "http/handlePromQueryRange", func(ctx context.Context, r *http.Request, org *influxdb.Organization, bucket *influxdb.Bucket) (interface{}, error) {
+		start, err := parsePromTime(r.FormValue("start"))
+		if err != nil {
+			return nil, promBadData(err)
+		}
+		end, err := parsePromTime(r.FormValue("end"))
+		if err != nil {
+			return nil, promBadData(err)
+		}
+		if end.Before(start) {
+			return nil, promBadData(errors.New("end timestamp must not be before start time"))
+		}
+		step, err := parsePromDuration(r.FormValue("step"))
+		if err != nil {
+			return nil, promBadData(err)
+		}
+		if step <= 0 {
+			return nil, promBadData(errors.New("zero or negative query resolution step widths are not accepted, try a positive integer"))
+		}
+		if end.Sub(start)/step > maxPromQueryPoints {
+			return nil, promBadData(fmt.Errorf("exceeded maximum resolution of %d points per timeseries, try decreasing the query resolution (?step=XX)", maxPromQueryPoints))
+		}
+
+		eval := promql.Evaluation{
+			BucketID: bucket.ID.String(),
+			Start:    start,
+			End:      end,
+			Step:     step,
+		}
+		return h.evaluate(ctx, r, org, &eval, r.FormValue("query"))
+	})
+}
+
+// handleSeries responds with the label sets of the series which match any of the
+// match[] selectors and have a sample between the start and end parameters.
+func (h *PromHandler) handleSeries(w http.ResponseWriter, r *http.Request) {
+	h.servePromQuery(w, r, "http/handlePromSeries", func(ctx context.Context, r *http.Request, org *influxdb.Organization, bucket *influxdb.Bucket) (interface{}, error) {
+		if len(r.Form["match[]"]) == 0 {
+			return nil, promBadData(errors.New("no match[] parameter provided"))
+		}
+		series, err := h.matchSeries(ctx, r, org, bucket)
+		if err != nil {
+			return nil, err
+		}
+		data := make([]map[string]string, len(series))
+		for i, s := range series {
+			data[i] = s.Labels
+		}
+		return data, nil
+	})
+}
+
+// handleLabels responds with the sorted names of the labels of the series which
+// match any of the match[] selectors, or of all series if there are none.
+func (h *PromHandler) handleLabels(w http.ResponseWriter, r *http.Request) {
+	h.servePromQuery(w, r, "http/handlePromLabels", func(ctx context.Context, r *http.Request, org *influxdb.Organization, bucket *influxdb.Bucket) (interface{}, error) {
+		series, err := h.matchSeries(ctx, r, org, bucket)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]bool)
+		for _, s := range series {
+			for name := range s.Labels {
+				names[name] = true
+			}
+		}
+		data := make([]string, 0, len(names))
+		for name := range names {
+			data = append(data, name)
+		}
+		sort.Strings(data)
+		return data, nil
+	})
+}
+
+// servePromQuery finds the bucket of a request to the Prometheus query API and
+// responds with the data of the request, or with its error. The data is returned
+// by fn, which is passed the request with its parsed form.
+func (h *PromHandler) servePromQuery(w http.ResponseWriter, r *http.Request, op string, fn func(context.Context, *http.Request, *influxdb.Organization, *influxdb.Bucket) (interface{}, error)) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var (
+		orgID influxdb.ID
+		sw    = kithttp.NewStatusResponseWriter(w)
+	)
+	w = sw
+	defer func() {
+		h.QueryEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	// The parameters of a POST request are in its form encoded body, which
+	// may also have the org and bucket parameters. These are copied to the
+	// url to find the bucket as for any other request.
+	if err := r.ParseForm(); err != nil {
+		h.encodePromError(ctx, w, op, promBadData(err))
+		return
+	}
+	params := r.URL.Query()
+	for _, k := range []string{Org, OrgID, Bucket, BucketID} {
+		if v := r.PostForm.Get(k); v != "" && params.Get(k) == "" {
+			params.Set(k, v)
+		}
+	}
+	r.URL.RawQuery = params.Encode()
+
+	org, bucket, err := h.findBucket(r, influxdb.ReadAction)
+	if err != nil {
+		h.encodePromError(ctx, w, op, err)
+		return
+	}
+	orgID = org.ID
+	span.LogKV("org_id", org.ID, "bucket_id", bucket.ID)
+
+	data, err := fn(ctx, r, org, bucket)
+	if err != nil {
+		var apiErr *promAPIError
+		if errors.As(err, &apiErr) && apiErr.typ == promErrorExecution {
+			h.log.Info("Error executing query",
+				zap.Stringer("org_id", org.ID),
+				zap.Stringer("bucket_id", bucket.ID),
+				zap.Error(err))
+		}
+		h.encodePromError(ctx, w, op, err)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, &promQueryResponse{
+		Status: "success",
+		Data:   data,
+	}); err != nil {
+		logEncodingError(h.log, r, err)
+	}
+}
+
+// evaluate evaluates the promql query and returns the data of its result.
+func (h *PromHandler) evaluate(ctx context.Context, r *http.Request, org *influxdb.Organization, eval *promql.Evaluation, promQL string) (*promQueryData, error) {
+	if promQL == "" {
+		return nil, promBadData(errors.New("no query parameter provided"))
+	}
+	q, err := eval.Query(promQL)
+	if err != nil {
+		return nil, promBadData(err)
+	}
+	series, err := h.querySeries(ctx, r, org, q)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &promQueryData{
+		ResultType: q.Type,
+		Result:     make([]*promSeries, 0, len(series)),
+	}
+	for _, s := range series {
+		if len(s.Samples) == 0 {
+			continue
+		}
+		ps := &promSeries{Metric: s.Labels}
+		if q.Type == promql.VectorResult {
+			v := promSample(s.Samples[len(s.Samples)-1])
+			ps.Value = &v
+		} else {
+			ps.Values = make([]promSample, len(s.Samples))
+			for i := range s.Samples {
+				ps.Values[i] = promSample(s.Samples[i])
+			}
+		}
+		data.Result = append(data.Result, ps)
+	}
+	return data, nil
+}
+
+// matchSeries returns the distinct series which match any of the match[]
+// selectors, or all series if there are none, and which have a sample
+// between the start and end parameters. The series are read from the
+// storage engine rather than through a query, so that finding the labels
+// of a bucket does not read the latest sample of every one of its series.
+func (h *PromHandler) matchSeries(ctx context.Context, r *http.Request, org *influxdb.Organization, bucket *influxdb.Bucket) ([]*promql.Series, error) {
+	start, err := promFormTime(r, "start", time.Unix(0, 0))
+	if err != nil {
+		return nil, promBadData(err)
+	}
+	end, err := promFormTime(r, "end", time.Now())
+	if err != nil {
+		return nil, promBadData(err)
+	}
+	if end.Before(start) {
+		return nil, promBadData(errors.New("end timestamp must not be before start time"))
+	}
+
+	matchers := [][]*remote.LabelMatcher{nil}
+	if matches := r.Form["match[]"]; len(matches) > 0 {
+		matchers = matchers[:0]
+		for _, m := range matches {
+			parsed, err := promql.ParsePromQL(m)
+			if err != nil {
+				return nil, promBadData(err)
+			}
+			sel, ok := parsed.(*promql.Selector)
+			if !ok {
+				return nil, promBadData(fmt.Errorf("invalid series selector %q", m))
+			}
+			ms, err := remoteMatchers(sel)
+			if err != nil {
+				return nil, promBadData(err)
+			}
+			if _, err := remote.SeriesPredicate(ms); err != nil {
+				return nil, promBadData(err)
+			}
+			matchers = append(matchers, ms)
+		}
+	}
+
+	var (
+		matched []*promql.Series
+		seen    = make(map[string]bool)
+	)
+	for _, ms := range matchers {
+		// The end of the range of a read is exclusive.
+		err := remote.ReadSeries(ctx, h.ReadStore, org.ID, bucket.ID, ms, start.UnixNano(), end.UnixNano()+1, func(tags models.Tags) error {
+			s := &promql.Series{Labels: promql.TagsLabels(tags)}
+			if id := s.ID(); !seen[id] {
+				seen[id] = true
+				matched = append(matched, s)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, &promAPIError{typ: promErrorExecution, err: err}
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID() < matched[j].ID() })
+	return matched, nil
+}
+
+// remoteMatchers returns the label matchers of the storage engine which
+// select the series of the selector.
+func remoteMatchers(sel *promql.Selector) ([]*remote.LabelMatcher, error) {
+	var ms []*remote.LabelMatcher
+	if sel.Name != "" {
+		ms = append(ms, &remote.LabelMatcher{Type: remote.LabelMatcher_EQ, Name: promql.MetricNameLabel, Value: sel.Name})
+	}
+	for _, m := range sel.LabelMatchers {
+		var typ remote.LabelMatcher_Type
+		switch m.Kind {
+		case promql.Equal:
+			typ = remote.LabelMatcher_EQ
+		case promql.NotEqual:
+			typ = remote.LabelMatcher_NEQ
+		case promql.RegexMatch:
+			typ = remote.LabelMatcher_RE
+		case promql.RegexNoMatch:
+			typ = remote.LabelMatcher_NRE
+		default:
+			return nil, fmt.Errorf("unknown label match kind %d", m.Kind)
+		}
+		if m.Value.Type() != promql.StringKind {
+			return nil, fmt.Errorf("label matcher value of %s must be a string", m.Name)
+		}
+		ms = append(ms, &remote.LabelMatcher{Type: typ, Name: m.Name, Value: m.Value.Value().(string)})
+	}
+	return ms, nil
+}
+
+// querySeries runs the query as the authorizer of the request and returns the series of its results.
+func (h *PromHandler) querySeries(ctx context.Context, r *http.Request, org *influxdb.Organization, q *promql.Query) ([]*promql.Series, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := queryAuthorization(a, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transform the context into one with the request's authorization.
+	ctx = pcontext.SetAuthorizer(ctx, token)
+
+	qs := query.QueryServiceProxyBridge{ProxyQueryService: h.ProxyQueryService}
+	results, err := qs.Query(ctx, &query.Request{
+		Authorization:  token,
+		OrganizationID: org.ID,
+		Compiler:       repl.Compiler{Spec: q.Spec},
+		Source:         r.Header.Get("User-Agent"),
+		RemoteAddr:     r.RemoteAddr,
+	})
+	if err != nil {
+		return nil, &promAPIError{typ: promErrorExecution, err: err}
+	}
+	series, err := q.Series(results)
+	if err != nil {
+		return nil, &promAPIError{typ: promErrorExecution, err: err}
+	}
+	return series, nil
+}
+
+// encodePromError responds with the error in the format of the Prometheus query API.
+// The errors of the platform, such as those of finding the bucket of the request,
+// keep their status code.
+func (h *PromHandler) encodePromError(ctx context.Context, w http.ResponseWriter, op string, err error) {
+	var (
+		typ  = promErrorInternal
+		code = http.StatusInternalServerError
+		msg  = err.Error()
+	)
+	var apiErr *promAPIError
+	if errors.As(err, &apiErr) {
+		typ = apiErr.typ
+		switch typ {
+		case promErrorBadData:
+			code = http.StatusBadRequest
+		case promErrorExecution:
+			code = http.StatusUnprocessableEntity
+		}
+	} else {
+		switch ecode := influxdb.ErrorCode(err); ecode {
+		case influxdb.EInvalid:
+			typ = promErrorBadData
+		case influxdb.ENotFound:
+			typ = promErrorNotFound
+		case influxdb.EInternal:
+			typ = promErrorInternal
+		default:
+			typ = ecode
+		}
+		code = kithttp.ErrorCodeToStatusCode(influxdb.ErrorCode(err))
+		msg = influxdb.ErrorMessage(err)
+	}
+
+	if err := encodeResponse(ctx, w, code, &promQueryResponse{
+		Status:    "error",
+		ErrorType: typ,
+		Error:     msg,
+	}); err != nil {
+		h.log.Info("Error encoding error response", zap.String("op", op), zap.Error(err))
+	}
+}
+
+// promFormTime returns the time of the form value of the key, or def if it has none.
+func promFormTime(r *http.Request, key string, def time.Time) (time.Time, error) {
+	v := r.FormValue(key)
+	if v == "" {
+		return def, nil
+	}
+	return parsePromTime(v)
+}
+
+// parsePromTime parses a time as a unix timestamp in seconds, or in the RFC 3339 format.
+func parsePromTime(s string) (time.Time, error) {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parsePromDuration parses a duration as a number of seconds, or in the
+// format of Prometheus durations such as 5m.
+func parsePromDuration(s string) (time.Duration, error) {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		ns := v * float64(time.Second)
+		if ns > math.MaxInt64 || ns < math.MinInt64 {
+			return 0, fmt.Errorf("cannot parse %q to a valid duration, it overflows int64", s)
+		}
+		return time.Duration(ns), nil
+	}
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
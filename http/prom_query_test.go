@@ -0,0 +1,211 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	influxdbsource "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPromHandler_Query(t *testing.T) {
+	const (
+		orgID    = "043e0780ee2b1000"
+		bucketID = "04504b356e23b000"
+	)
+
+	// Every query returns the latest sample of up{job="node"}
+	// for an evaluation at 1590000000, which is also the only
+	// series of the store.
+	const results = `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string,string
+#group,false,false,true,true,false,false,true,true,true
+#default,_result,,,,,,,,
+,result,table,_start,_stop,_time,_value,_field,_measurement,job
+,,0,2020-05-20T18:35:00Z,2020-05-20T18:40:00.000000001Z,2020-05-20T18:39:50Z,1,gauge,up,node
+
+`
+
+	tests := []struct {
+		name   string
+		auth   influxdb.Authorizer
+		method string
+		path   string
+		form   url.Values
+		code   int
+		body   string
+	}{
+		{
+			name:   "instant query",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "GET",
+			path:   "/api/v1/query?query=up&time=1590000000",
+			code:   200,
+			body:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","job":"node"},"value":[1590000000,"1"]}]}}`,
+		},
+		{
+			name:   "range query",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "POST",
+			path:   "/api/v1/query_range",
+			form:   url.Values{"query": {`up{job=~"no.*"}`}, "start": {"2020-05-20T18:39:00Z"}, "end": {"1590000000"}, "step": {"1m"}},
+			code:   200,
+			body:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"node"},"values":[[1590000000,"1"]]}]}}`,
+		},
+		{
+			name:   "series",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "POST",
+			path:   "/api/v1/series",
+			form:   url.Values{"match[]": {"up", `up{job="node"}`}, "start": {"1589990000"}, "end": {"1590000000"}},
+			code:   200,
+			body:   `{"status":"success","data":[{"__name__":"up","job":"node"}]}`,
+		},
+		{
+			name:   "labels",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "GET",
+			path:   "/api/v1/labels?end=1590000000",
+			code:   200,
+			body:   `{"status":"success","data":["__name__","job"]}`,
+		},
+		{
+			name:   "read permission is required",
+			auth:   bucketWritePermission(orgID, bucketID),
+			method: "GET",
+			path:   "/api/v1/query?query=up",
+			code:   403,
+			body:   `{"status":"error","errorType":"forbidden","error":"insufficient permissions to read bucket"}`,
+		},
+		{
+			name:   "invalid query",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "GET",
+			path:   "/api/v1/query?query=" + url.QueryEscape("sum without (job) (up)"),
+			code:   400,
+			body:   `{"status":"error","errorType":"bad_data","error":"unable to aggregate using ` + "`without`" + `"}`,
+		},
+		{
+			name:   "range query requires a step",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "GET",
+			path:   "/api/v1/query_range?query=up&start=1589990000&end=1590000000",
+			code:   400,
+			body:   `{"status":"error","errorType":"bad_data","error":"cannot parse \"\" to a valid duration"}`,
+		},
+		{
+			name:   "series requires a selector",
+			auth:   bucketReadPermission(orgID, bucketID),
+			method: "GET",
+			path:   "/api/v1/series",
+			code:   400,
+			body:   `{"status":"error","errorType":"bad_data","error":"no match[] parameter provided"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return testOrg(orgID), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return testBucket(orgID, bucketID), nil
+			}
+			queries := &querymock.ProxyQueryService{
+				QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+					// The queries read from the bucket of the request.
+					spec := req.Request.Compiler.(repl.Compiler).Spec
+					if from := spec.Operations[0].Spec.(*influxdbsource.FromOpSpec); from.BucketID != bucketID {
+						t.Errorf("unexpected bucket of query: %q", from.BucketID)
+					}
+					_, err := io.WriteString(w, results)
+					return flux.Statistics{}, err
+				},
+			}
+
+			store := mock.NewStoreReader()
+			store.ReadFilterFunc = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+				var read, next bool
+				cur := mock.NewFloatArrayCursor()
+				cur.NextFunc = func() *cursors.FloatArray {
+					if read {
+						return &cursors.FloatArray{}
+					}
+					read = true
+					return &cursors.FloatArray{
+						Timestamps: []int64{1589999990000000000},
+						Values:     []float64{1},
+					}
+				}
+
+				rs := mock.NewResultSet()
+				rs.NextFunc = func() bool {
+					if next {
+						return false
+					}
+					next = true
+					return true
+				}
+				rs.CursorFunc = func() cursors.Cursor { return cur }
+				rs.TagsFunc = func() models.Tags {
+					return models.NewTags(map[string]string{
+						models.MeasurementTagKey: "up",
+						models.FieldKeyTagKey:    "gauge",
+						"job":                    "node",
+					})
+				}
+				return rs, nil
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				FluxService:         queries,
+				ReadStore:           store,
+				QueryEventRecorder:  &metric.NopEventRecorder{},
+			}
+			promHandler := NewPromHandler(zaptest.NewLogger(t), NewPromBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(promHandler, tt.auth)
+
+			target := "http://localhost:9999" + tt.path
+			if strings.Contains(target, "?") {
+				target += "&org=" + orgID + "&bucket=" + bucketID
+			} else {
+				target += "?org=" + orgID + "&bucket=" + bucketID
+			}
+			r := httptest.NewRequest(tt.method, target, strings.NewReader(tt.form.Encode()))
+			if tt.form != nil {
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Fatalf("unexpected status code: got %d want %d, body: %s", got, want, w.Body.String())
+			}
+			if eq, diff, err := jsonEqual(w.Body.String(), tt.body); err != nil {
+				t.Fatal(err)
+			} else if !eq {
+				t.Errorf("unexpected body -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
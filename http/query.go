@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -35,6 +36,16 @@ type QueryRequest struct {
 	Type    string       `json:"type"`
 	Dialect QueryDialect `json:"dialect"`
 
+	// Profile requests the profile of the query, which is returned
+	// as the _profile result after the results of the query.
+	// For a query which is not encoded as JSON, the profile is requested
+	// with the `profile=true` query parameter of the HTTP request.
+	Profile bool `json:"profile,omitempty"`
+
+	// Params are bound to the params record of the query, such as
+	// params.bucket, rather than being spliced into the query.
+	Params query.Params `json:"params,omitempty"`
+
 	Org *influxdb.Organization `json:"-"`
 
 	// PreferNoContent specifies if the Response to this request should
@@ -53,6 +64,11 @@ type QueryRequest struct {
 	// To obtain a QueryRequest with no result but runtime errors,
 	// add the header `Prefer: return-no-content-with-error` to the HTTP request.
 	PreferNoContentWithError bool
+
+	// ContentType is the media type the results of the query are encoded in.
+	// It is negotiated with the Accept header of the HTTP request, and the
+	// results are encoded as annotated CSV unless another encoding is preferred.
+	ContentType string `json:"-"`
 }
 
 // QueryDialect is the formatting options for the query response.
@@ -97,6 +113,13 @@ func (r QueryRequest) Validate() error {
 		}
 	}
 
+	if r.Spec != nil && len(r.Params) > 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "request body cannot specify both a spec and params",
+		}
+	}
+
 	if r.Type != "flux" {
 		return fmt.Errorf(`unknown query type: %s`, r.Type)
 	}
@@ -283,11 +306,18 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 			Delimiter:   delimiter,
 			Annotations: r.Dialect.Annotations,
 		}
-		if r.PreferNoContentWithError {
+		switch {
+		case r.PreferNoContentWithError:
 			dialect = &query.NoContentWithErrorDialect{
 				ResultEncoderConfig: encConfig,
 			}
-		} else {
+		case r.ContentType == query.JSONContentType:
+			dialect = query.NewJSONDialect()
+		case r.ContentType == query.ArrowContentType:
+			dialect = query.NewArrowDialect()
+		case r.ContentType == query.ParquetContentType:
+			dialect = query.NewParquetDialect()
+		default:
 			dialect = &csv.Dialect{
 				ResultEncoderConfig: encConfig,
 			}
@@ -298,6 +328,8 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 		Request: query.Request{
 			OrganizationID: r.Org.ID,
 			Compiler:       compiler,
+			Profile:        r.Profile,
+			Params:         r.Params,
 		},
 		Dialect: dialect,
 	}, nil
@@ -321,6 +353,8 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 	default:
 		return nil, fmt.Errorf("unsupported compiler %T", c)
 	}
+	qr.Profile = req.Request.Profile
+	qr.Params = req.Request.Params
 	switch d := req.Dialect.(type) {
 	case *csv.Dialect:
 		var header = !d.ResultEncoderConfig.NoHeader
@@ -333,6 +367,12 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 		qr.PreferNoContent = true
 	case *query.NoContentWithErrorDialect:
 		qr.PreferNoContentWithError = true
+	case *query.JSONDialect:
+		qr.ContentType = query.JSONContentType
+	case *query.ArrowDialect:
+		qr.ContentType = query.ArrowContentType
+	case *query.ParquetDialect:
+		qr.ContentType = query.ParquetContentType
 	default:
 		return nil, fmt.Errorf("unsupported dialect %T", d)
 	}
@@ -372,6 +412,10 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 	case query.PreferNoContentWErrHeaderValue:
 		req.PreferNoContentWithError = true
 	}
+	req.ContentType = negotiateQueryContentType(r.Header.Get("Accept"))
+	if r.URL.Query().Get("profile") == "true" {
+		req.Profile = true
+	}
 
 	req = req.WithDefaults()
 	if err := req.Validate(); err != nil {
@@ -382,6 +426,41 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 	return &req, body.bytesRead, err
 }
 
+// negotiateQueryContentType returns the media type of the Accept header with the
+// highest quality which query results can be encoded in. An empty media type is
+// returned if none of them can, and the results are then encoded as annotated CSV.
+func negotiateQueryContentType(accept string) string {
+	var (
+		best    string
+		quality float64
+	)
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		switch mt {
+		case "text/csv", "application/csv":
+		case query.JSONContentType, query.ArrowContentType, query.ParquetContentType:
+		case "application/vnd.influx.arrow":
+			mt = query.ArrowContentType
+		default:
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > quality {
+			best, quality = mt, q
+		}
+	}
+	return best
+}
+
 type countReader struct {
 	bytesRead int
 	io.Reader
@@ -404,18 +483,26 @@ func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth influxdb
 		return nil, n, err
 	}
 
-	var token *influxdb.Authorization
+	token, err := queryAuthorization(auth, req.Org.ID)
+	if err != nil {
+		return pr, n, err
+	}
+
+	pr.Request.Authorization = token
+	return pr, n, nil
+}
+
+// queryAuthorization returns the authorization of a query of the authorizer in
+// the organization. Sessions and tokens are given an ephemeral authorization.
+func queryAuthorization(auth influxdb.Authorizer, orgID influxdb.ID) (*influxdb.Authorization, error) {
 	switch a := auth.(type) {
 	case *influxdb.Authorization:
-		token = a
+		return a, nil
 	case *influxdb.Session:
-		token = a.EphemeralAuth(req.Org.ID)
+		return a.EphemeralAuth(orgID), nil
 	case *jsonweb.Token:
-		token = a.EphemeralAuth(req.Org.ID)
+		return a.EphemeralAuth(orgID), nil
 	default:
-		return pr, n, influxdb.ErrAuthorizerNotSupported
+		return nil, influxdb.ErrAuthorizerNotSupported
 	}
-
-	pr.Request.Authorization = token
-	return pr, n, nil
 }
@@ -157,6 +157,7 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	req.Request.Source = r.Header.Get("User-Agent")
+	req.Request.RemoteAddr = r.RemoteAddr
 	orgID = req.Request.OrganizationID
 	requestBytes = n
 
@@ -384,8 +385,12 @@ func (s *FluxService) Query(ctx context.Context, w io.Writer, r *query.ProxyRequ
 
 	SetToken(s.Token, hreq)
 
+	accept := "text/csv"
+	if qreq.ContentType != "" {
+		accept = qreq.ContentType
+	}
 	hreq.Header.Set("Content-Type", "application/json")
-	hreq.Header.Set("Accept", "text/csv")
+	hreq.Header.Set("Accept", accept)
 	if r.Request.Source != "" {
 		hreq.Header.Add("User-Agent", r.Request.Source)
 	} else if s.Name != "" {
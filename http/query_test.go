@@ -82,6 +82,7 @@ func TestQueryRequest_Validate(t *testing.T) {
 		Query   string
 		Type    string
 		Dialect QueryDialect
+		Params  query.Params
 		org     *platform.Organization
 	}
 	tests := []struct {
@@ -96,6 +97,19 @@ func TestQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "query cannot have both params and spec",
+			fields: fields{
+				Params: query.Params{"n": 1},
+				Spec:   &flux.Spec{},
+				Type:   "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "query cannot have both extern and spec",
 			fields: fields{
@@ -195,6 +209,7 @@ func TestQueryRequest_Validate(t *testing.T) {
 				Query:   tt.fields.Query,
 				Type:    tt.fields.Type,
 				Dialect: tt.fields.Dialect,
+				Params:  tt.fields.Params,
 				Org:     tt.fields.org,
 			}
 			if err := r.Validate(); (err != nil) != tt.wantErr {
@@ -206,13 +221,14 @@ func TestQueryRequest_Validate(t *testing.T) {
 
 func TestQueryRequest_proxyRequest(t *testing.T) {
 	type fields struct {
-		Extern  *ast.File
-		Spec    *flux.Spec
-		AST     *ast.Package
-		Query   string
-		Type    string
-		Dialect QueryDialect
-		org     *platform.Organization
+		Extern      *ast.File
+		Spec        *flux.Spec
+		AST         *ast.Package
+		Query       string
+		Type        string
+		Dialect     QueryDialect
+		ContentType string
+		org         *platform.Organization
 	}
 	tests := []struct {
 		name    string
@@ -255,6 +271,29 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid query with json content type",
+			fields: fields{
+				Query: "howdy",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+				ContentType: query.JSONContentType,
+				org:         &platform.Organization{},
+			},
+			now: func() time.Time { return time.Unix(1, 1) },
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Now:   time.Unix(1, 1),
+						Query: `howdy`,
+					},
+				},
+				Dialect: &query.JSONDialect{},
+			},
+		},
 		{
 			name: "valid AST",
 			fields: fields{
@@ -365,13 +404,14 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := QueryRequest{
-				Extern:  tt.fields.Extern,
-				Spec:    tt.fields.Spec,
-				AST:     tt.fields.AST,
-				Query:   tt.fields.Query,
-				Type:    tt.fields.Type,
-				Dialect: tt.fields.Dialect,
-				Org:     tt.fields.org,
+				Extern:      tt.fields.Extern,
+				Spec:        tt.fields.Spec,
+				AST:         tt.fields.AST,
+				Query:       tt.fields.Query,
+				Type:        tt.fields.Type,
+				Dialect:     tt.fields.Dialect,
+				ContentType: tt.fields.ContentType,
+				Org:         tt.fields.org,
 			}
 			got, err := r.proxyRequest(tt.now)
 			if (err != nil) != tt.wantErr {
@@ -451,6 +491,96 @@ func Test_decodeQueryRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid query request with accept",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from()"}`))
+					r.Header.Set("Accept", "text/csv;q=0.5, application/vnd.apache.arrow.stream")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+				},
+				ContentType: "application/vnd.apache.arrow.stream",
+				Org: &platform.Organization{
+					ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+			},
+		},
+		{
+			name: "valid query request with params",
+			args: args{
+				r: httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from(bucket: params.bucket)", "params": {"bucket": "b", "n": 10, "every": {"type": "duration", "value": "1m"}}}`)),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from(bucket: params.bucket)",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+				},
+				Params: query.Params{
+					"bucket": "b",
+					"n":      int64(10),
+					"every":  query.Duration("1m"),
+				},
+				Org: &platform.Organization{
+					ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+			},
+		},
+		{
+			name: "valid query request with profile",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/?profile=true", bytes.NewBufferString(`from()`))
+					r.Header.Set("Content-Type", "application/vnd.flux")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+				},
+				Profile: true,
+				Org: &platform.Organization{
+					ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+			},
+		},
 		{
 			name: "error decoding json",
 			args: args{
@@ -480,6 +610,30 @@ func Test_decodeQueryRequest(t *testing.T) {
 	}
 }
 
+func Test_negotiateQueryContentType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: ""},
+		{accept: "*/*", want: ""},
+		{accept: "text/csv", want: "text/csv"},
+		{accept: "application/csv", want: "application/csv"},
+		{accept: "application/json", want: query.JSONContentType},
+		{accept: "application/vnd.influx.arrow", want: query.ArrowContentType},
+		{accept: "text/html, application/vnd.apache.parquet", want: query.ParquetContentType},
+		{accept: "application/json;q=0.9, application/vnd.apache.arrow.stream", want: query.ArrowContentType},
+		{accept: "application/json, text/csv", want: query.JSONContentType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := negotiateQueryContentType(tt.accept); got != tt.want {
+				t.Errorf("negotiateQueryContentType(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_decodeProxyQueryRequest(t *testing.T) {
 	type args struct {
 		ctx  context.Context
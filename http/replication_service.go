@@ -0,0 +1,351 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixReplications = "/api/v2/replications"
+)
+
+// ReplicationBackend is all services and associated parameters required to construct
+// the ReplicationHandler.
+type ReplicationBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	ReplicationService influxdb.ReplicationService
+}
+
+// NewReplicationBackend creates a backend used by the replication handler.
+func NewReplicationBackend(log *zap.Logger, b *APIBackend) *ReplicationBackend {
+	return &ReplicationBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		ReplicationService: b.ReplicationService,
+	}
+}
+
+// ReplicationHandler is the handler for the replication service.
+type ReplicationHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	ReplicationService influxdb.ReplicationService
+}
+
+// NewReplicationHandler creates a new ReplicationHandler.
+func NewReplicationHandler(log *zap.Logger, b *ReplicationBackend) *ReplicationHandler {
+	h := &ReplicationHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		ReplicationService: b.ReplicationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixReplications)
+
+	h.HandlerFunc("GET", prefixReplications, h.handleGetReplications)
+	h.HandlerFunc("POST", prefixReplications, h.handlePostReplication)
+	h.HandlerFunc("GET", entityPath, h.handleGetReplication)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchReplication)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteReplication)
+
+	return h
+}
+
+type replicationsResponse struct {
+	Replications []*influxdb.Replication `json:"replications"`
+}
+
+// redactReplication returns a copy of a replication without the token of
+// its remote, which is never returned by the API.
+func redactReplication(r *influxdb.Replication) *influxdb.Replication {
+	redacted := *r
+	redacted.RemoteToken = ""
+	return &redacted
+}
+
+// handleGetReplications is the HTTP handler for the GET /api/v2/replications route.
+func (h *ReplicationHandler) handleGetReplications(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "ReplicationHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := decodeReplicationFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.ReplicationService.FindReplications(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Replications retrieved", zap.Int("count", len(rs)))
+
+	resp := replicationsResponse{Replications: make([]*influxdb.Replication, 0, len(rs))}
+	for _, rep := range rs {
+		resp.Replications = append(resp.Replications, redactReplication(rep))
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeReplicationFilter(r *http.Request) (influxdb.ReplicationFilter, error) {
+	var filter influxdb.ReplicationFilter
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	}
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+	if bucketID := qp.Get("localBucketID"); bucketID != "" {
+		id, err := influxdb.IDFromString(bucketID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid localBucketID",
+				Err:  err,
+			}
+		}
+		filter.LocalBucketID = id
+	}
+	return filter, nil
+}
+
+// handlePostReplication is the HTTP handler for the POST /api/v2/replications route.
+func (h *ReplicationHandler) handlePostReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var rep influxdb.Replication
+	if err := json.NewDecoder(r.Body).Decode(&rep); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.ReplicationService.CreateReplication(ctx, &rep); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Replication created", zap.String("replication", rep.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, redactReplication(&rep)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetReplication is the HTTP handler for the GET /api/v2/replications/:id route.
+func (h *ReplicationHandler) handleGetReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReplicationID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rep, err := h.ReplicationService.FindReplicationByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Replication retrieved", zap.String("replication", rep.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, redactReplication(rep)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePatchReplication is the HTTP handler for the PATCH /api/v2/replications/:id route.
+func (h *ReplicationHandler) handlePatchReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReplicationID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.ReplicationUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	rep, err := h.ReplicationService.UpdateReplication(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Replication updated", zap.String("replication", rep.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, redactReplication(rep)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteReplication is the HTTP handler for the DELETE /api/v2/replications/:id route.
+func (h *ReplicationHandler) handleDeleteReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReplicationID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.ReplicationService.DeleteReplication(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Replication deleted", zap.String("replicationID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func requestReplicationID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+	return *id, nil
+}
+
+func replicationIDPath(id influxdb.ID) string {
+	return path.Join(prefixReplications, id.String())
+}
+
+// ReplicationService connects to Influx via HTTP using tokens to manage replications.
+type ReplicationService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.ReplicationService = (*ReplicationService)(nil)
+
+// FindReplicationByID returns a single replication by ID.
+func (s *ReplicationService) FindReplicationByID(ctx context.Context, id influxdb.ID) (*influxdb.Replication, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r influxdb.Replication
+	err := s.Client.
+		Get(replicationIDPath(id)).
+		DecodeJSON(&r).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// FindReplications returns the replications that match filter.
+func (s *ReplicationService) FindReplications(ctx context.Context, filter influxdb.ReplicationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Replication, int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.Name != nil {
+		params = append(params, [2]string{"name", *filter.Name})
+	}
+	if filter.LocalBucketID != nil {
+		params = append(params, [2]string{"localBucketID", filter.LocalBucketID.String()})
+	}
+
+	var resp replicationsResponse
+	err := s.Client.
+		Get(prefixReplications).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Replications, len(resp.Replications), nil
+}
+
+// CreateReplication creates a new replication and sets r.ID with the new identifier.
+// The response does not have the remote token, so only the identifier and the
+// timestamps of the response are copied to r.
+func (s *ReplicationService) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp influxdb.Replication
+	err := s.Client.
+		PostJSON(r, prefixReplications).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	r.ID = resp.ID
+	r.CRUDLog = resp.CRUDLog
+	return nil
+}
+
+// UpdateReplication updates a single replication with changeset.
+// Returns the new replication after update.
+func (s *ReplicationService) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r influxdb.Replication
+	err := s.Client.
+		PatchJSON(upd, replicationIDPath(id)).
+		DecodeJSON(&r).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// DeleteReplication removes a replication by ID.
+func (s *ReplicationService) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(replicationIDPath(id)).
+		Do(ctx)
+}
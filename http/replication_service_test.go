@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestReplicationService(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewReplicationHandler(zaptest.NewLogger(t), &ReplicationBackend{
+		HTTPErrorHandler:   kithttp.ErrorHandler(0),
+		log:                zaptest.NewLogger(t),
+		ReplicationService: svc,
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := &ReplicationService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	r := &influxdb.Replication{
+		OrgID:          org.ID,
+		Name:           "edge",
+		LocalBucketID:  bucket.ID,
+		RemoteURL:      "https://cloud.example.com",
+		RemoteToken:    "secret",
+		RemoteOrgID:    1,
+		RemoteBucketID: 2,
+	}
+	if err := client.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.ID.Valid() || r.RemoteToken != "secret" {
+		t.Fatalf("unexpected replication: %+v", r)
+	}
+
+	found, err := client.FindReplicationByID(ctx, r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != r.Name || found.RemoteToken != "" {
+		t.Errorf("unexpected found replication: %+v", found)
+	}
+
+	stored, err := svc.FindReplicationByID(ctx, r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.RemoteToken != "secret" {
+		t.Errorf("expected remote token to be stored, got %q", stored.RemoteToken)
+	}
+
+	err = client.CreateReplication(ctx, &influxdb.Replication{OrgID: org.ID, Name: "invalid", LocalBucketID: bucket.ID, RemoteURL: "ftp://example.com"})
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Errorf("unexpected error code creating invalid replication: got %q want %q", got, want)
+	}
+
+	desc := "replicate to cloud"
+	updated, err := client.UpdateReplication(ctx, r.ID, influxdb.ReplicationUpdate{Description: &desc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Description != desc || updated.RemoteToken != "" {
+		t.Errorf("unexpected updated replication: %+v", updated)
+	}
+
+	rs, n, err := client.FindReplications(ctx, influxdb.ReplicationFilter{LocalBucketID: &bucket.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || rs[0].ID != r.ID || rs[0].RemoteToken != "" {
+		t.Fatalf("unexpected replications: %v", rs)
+	}
+
+	if err := client.DeleteReplication(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindReplicationByID(ctx, r.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected replication to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,556 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// RestoreBackend is all services and associated parameters required to construct the RestoreHandler.
+type RestoreBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	RestoreService   influxdb.RestoreService
+	KVRestoreService influxdb.KVRestoreService
+	BucketService    influxdb.BucketService
+}
+
+// NewRestoreBackend returns a new instance of RestoreBackend.
+func NewRestoreBackend(b *APIBackend) *RestoreBackend {
+	return &RestoreBackend{
+		Logger: b.Logger.With(zap.String("handler", "restore")),
+
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		RestoreService:   b.RestoreService,
+		KVRestoreService: b.KVRestoreService,
+		BucketService:    b.BucketService,
+	}
+}
+
+// RestoreHandler receives the data files of a backup and restores their data online.
+type RestoreHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RestoreService   influxdb.RestoreService
+	KVRestoreService influxdb.KVRestoreService
+	BucketService    influxdb.BucketService
+}
+
+const (
+	prefixRestore     = "/api/v2/restore"
+	restoreBucketPath = prefixRestore + "/bucket"
+	restoreKVPath     = prefixRestore + "/kv"
+	restoreShardsPath = prefixRestore + "/shards"
+
+	restoreSourceOrgIDParam    = "sourceOrgID"
+	restoreSourceBucketIDParam = "sourceBucketID"
+
+	// restoreTSMFormFile and restoreTombstoneFormFile are the parts of the
+	// multipart body of a restore request holding a TSM file of a backup
+	// and its tombstone file.
+	restoreTSMFormFile       = "tsm"
+	restoreTombstoneFormFile = "tombstone"
+
+	// restoreManifestFormFile are the parts of the multipart body of a full
+	// restore request holding the manifests of the chain of backups, in
+	// order. The meta data files of the chain are sent as restoreKVFormFile
+	// and restoreChangesFormFile parts, and the engine files as
+	// restoreEngineFormFile parts named after the files.
+	restoreManifestFormFile = "manifest"
+	restoreKVFormFile       = "kv"
+	restoreChangesFormFile  = "changes"
+	restoreEngineFormFile   = "file"
+
+	// restoreMaxMemory is the size of the files of a restore request kept
+	// in memory, larger files are stored in temporary files.
+	restoreMaxMemory = 32 << 20
+)
+
+// NewRestoreHandler creates a new handler at /api/v2/restore to receive restore requests.
+func NewRestoreHandler(b *RestoreBackend) *RestoreHandler {
+	h := &RestoreHandler{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Router:           NewRouter(b.HTTPErrorHandler),
+		Logger:           b.Logger,
+		RestoreService:   b.RestoreService,
+		KVRestoreService: b.KVRestoreService,
+		BucketService:    b.BucketService,
+	}
+
+	h.HandlerFunc(http.MethodPost, restoreBucketPath, h.handleRestoreBucket)
+	h.HandlerFunc(http.MethodPost, restoreKVPath, h.handleRestoreKV)
+	h.HandlerFunc(http.MethodPost, restoreShardsPath, h.handleRestoreShards)
+
+	return h
+}
+
+type restoreBucketResponse struct {
+	Points int `json:"points"`
+}
+
+// handleRestoreBucket restores the data of one bucket of a TSM file of a
+// backup into an existing bucket, which may belong to another organization.
+// The data of a bucket spread over several TSM files is restored with one
+// request per file.
+func (h *RestoreHandler) handleRestoreBucket(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RestoreHandler.handleRestoreBucket")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	req, err := decodeRestoreBucketRequest(ctx, r, h.BucketService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := parseRestoreForm(r); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	tsm, _, err := r.FormFile(restoreTSMFormFile)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid restore request; missing %q file", restoreTSMFormFile),
+			Err:  err,
+		}, w)
+		return
+	}
+	defer tsm.Close()
+
+	var tombstone io.Reader
+	if f, _, err := r.FormFile(restoreTombstoneFormFile); err == nil {
+		defer f.Close()
+		tombstone = f
+	} else if err != http.ErrMissingFile {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	n, err := h.RestoreService.RestoreBucket(ctx, *req, tsm, tombstone)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, restoreBucketResponse{Points: n}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeRestoreBucketRequest(ctx context.Context, r *http.Request, svc influxdb.BucketService) (*influxdb.RestoreBucketRequest, error) {
+	qp := r.URL.Query()
+
+	req := &influxdb.RestoreBucketRequest{}
+	for _, p := range []struct {
+		name string
+		id   *influxdb.ID
+	}{
+		{restoreSourceOrgIDParam, &req.SourceOrgID},
+		{restoreSourceBucketIDParam, &req.SourceBucketID},
+		{BucketID, &req.BucketID},
+	} {
+		v := qp.Get(p.name)
+		if v == "" {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("missing %s", p.name),
+			}
+		}
+		if err := p.id.DecodeFromString(v); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid %s", p.name),
+				Err:  err,
+			}
+		}
+	}
+
+	// the data is restored into an existing bucket, the bucket is created
+	// beforehand to restore into a new bucket.
+	b, err := svc.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		return nil, err
+	}
+	req.OrgID = b.OrgID
+
+	if v := qp.Get(OrgID); v != "" {
+		orgID, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		if *orgID != b.OrgID {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "bucket does not belong to the organization",
+			}
+		}
+	}
+
+	return req, nil
+}
+
+func parseRestoreForm(r *http.Request) error {
+	if err := r.ParseMultipartForm(restoreMaxMemory); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid restore request; expected a multipart body",
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// decodeRestoreChain decodes the manifests of the chain of backups of a full
+// restore request.
+func decodeRestoreChain(r *http.Request) ([]*influxdb.BackupManifest, error) {
+	var chain []*influxdb.BackupManifest
+	for _, fh := range r.MultipartForm.File[restoreManifestFormFile] {
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		var m influxdb.BackupManifest
+		err = json.NewDecoder(f).Decode(&m)
+		f.Close()
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid backup manifest",
+				Err:  err,
+			}
+		}
+		chain = append(chain, &m)
+	}
+	if err := influxdb.ValidateBackupChain(chain); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// openRestoreFiles opens the files of the parts name of a restore request.
+// The files are closed by closing the returned closer.
+func openRestoreFiles(r *http.Request, name string) ([]*multipart.FileHeader, []io.Reader, io.Closer, error) {
+	fhs := r.MultipartForm.File[name]
+	var (
+		files  = make([]io.Reader, 0, len(fhs))
+		closer multiCloser
+	)
+	for _, fh := range fhs {
+		f, err := fh.Open()
+		if err != nil {
+			closer.Close()
+			return nil, nil, nil, err
+		}
+		files = append(files, f)
+		closer = append(closer, f)
+	}
+	return fhs, files, closer, nil
+}
+
+type multiCloser []io.Closer
+
+func (c multiCloser) Close() error {
+	var err error
+	for _, cl := range c {
+		err = multierr.Append(err, cl.Close())
+	}
+	return err
+}
+
+// handleRestoreKV replaces the meta data with the meta data of a chain of
+// backups: the bolt file of the full backup the chain starts with, and the
+// meta data changes of the incremental backups of the chain.
+func (h *RestoreHandler) handleRestoreKV(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RestoreHandler.handleRestoreKV")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	if err := parseRestoreForm(r); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	chain, err := decodeRestoreChain(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	kv, _, err := r.FormFile(restoreKVFormFile)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid restore request; missing %q file", restoreKVFormFile),
+			Err:  err,
+		}, w)
+		return
+	}
+	defer kv.Close()
+
+	_, changes, closer, err := openRestoreFiles(r, restoreChangesFormFile)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	defer closer.Close()
+
+	if err := h.KVRestoreService.RestoreKVStore(ctx, chain, kv, changes); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Info("Restored meta data", zap.String("backup_id", chain[len(chain)-1].ID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRestoreShards replaces the data of the engine with the engine files
+// of the last backup of a chain of backups. The files of the backup which
+// are part of the backups before it in the chain are sent along with the
+// files included in the backup.
+func (h *RestoreHandler) handleRestoreShards(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RestoreHandler.handleRestoreShards")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	if err := parseRestoreForm(r); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	chain, err := decodeRestoreChain(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	fhs, readers, closer, err := openRestoreFiles(r, restoreEngineFormFile)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	defer closer.Close()
+
+	files := make(map[string]io.Reader, len(fhs))
+	for i, fh := range fhs {
+		if _, ok := files[fh.Filename]; ok {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid restore request; file %s is sent more than once", fh.Filename),
+			}, w)
+			return
+		}
+		files[fh.Filename] = readers[i]
+	}
+
+	if err := h.RestoreService.RestoreEngine(ctx, chain, files); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Info("Restored engine", zap.String("backup_id", chain[len(chain)-1].ID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreService is the client implementation of influxdb.RestoreService.
+type RestoreService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+var _ influxdb.KVRestoreService = (*RestoreService)(nil)
+
+// RestoreBucket uploads a TSM file of a backup, and its tombstone file if not
+// nil, to restore the data of the source bucket of req into the bucket of req.
+func (s *RestoreService) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, restoreBucketPath)
+	if err != nil {
+		return 0, err
+	}
+	params := u.Query()
+	params.Set(restoreSourceOrgIDParam, req.SourceOrgID.String())
+	params.Set(restoreSourceBucketIDParam, req.SourceBucketID.String())
+	params.Set(BucketID, req.BucketID.String())
+	if req.OrgID.Valid() {
+		params.Set(OrgID, req.OrgID.String())
+	}
+	u.RawQuery = params.Encode()
+
+	resp, err := s.post(ctx, u, func(mw *multipart.Writer) error {
+		if err := writeRestoreFile(mw, restoreTSMFormFile, "restore.tsm", tsm); err != nil {
+			return err
+		}
+		if tombstone == nil {
+			return nil
+		}
+		return writeRestoreFile(mw, restoreTombstoneFormFile, "restore.tombstone", tombstone)
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return 0, err
+	}
+
+	var res restoreBucketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return 0, err
+	}
+	return res.Points, nil
+}
+
+// RestoreKVStore uploads the meta data files of a chain of backups to replace
+// the meta data with them.
+func (s *RestoreService) RestoreKVStore(ctx context.Context, chain []*influxdb.BackupManifest, kv io.Reader, changes []io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, restoreKVPath)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.post(ctx, u, func(mw *multipart.Writer) error {
+		if err := writeRestoreManifests(mw, chain); err != nil {
+			return err
+		}
+		if err := writeRestoreFile(mw, restoreKVFormFile, restoreKVFormFile, kv); err != nil {
+			return err
+		}
+		for _, c := range changes {
+			if err := writeRestoreFile(mw, restoreChangesFormFile, influxdb.BackupKVChangesFilename, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+// RestoreEngine uploads the engine files of the last backup of a chain of
+// backups to replace the data of the engine with them.
+func (s *RestoreService) RestoreEngine(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, restoreShardsPath)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp, err := s.post(ctx, u, func(mw *multipart.Writer) error {
+		if err := writeRestoreManifests(mw, chain); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := writeRestoreFile(mw, restoreEngineFormFile, name, files[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+// post sends a restore request to u, with the multipart body written by fn.
+// The body is streamed to the request as it is written.
+func (s *RestoreService) post(ctx context.Context, u *url.URL, fn func(mw *multipart.Writer) error) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		err := fn(mw)
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	SetToken(s.Token, req)
+	req = req.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	hc.Timeout = httpClientTimeout
+	resp, err := hc.Do(req)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+func writeRestoreManifests(mw *multipart.Writer, chain []*influxdb.BackupManifest) error {
+	for _, m := range chain {
+		part, err := mw.CreateFormFile(restoreManifestFormFile, influxdb.BackupManifestFilename)
+		if err != nil {
+			return err
+		}
+		if err := json.NewEncoder(part).Encode(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRestoreFile(mw *multipart.Writer, field, name string, r io.Reader) error {
+	part, err := mw.CreateFormFile(field, name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, r)
+	return err
+}
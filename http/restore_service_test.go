@@ -0,0 +1,244 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRestoreService_RestoreBucket(t *testing.T) {
+	var (
+		sourceOrgID    = influxdbtesting.MustIDBase16("0000000000000001")
+		sourceBucketID = influxdbtesting.MustIDBase16("0000000000000002")
+		orgID          = influxdbtesting.MustIDBase16("0000000000000003")
+		bucketID       = influxdbtesting.MustIDBase16("0000000000000004")
+	)
+
+	bucketSvc := mock.NewBucketService()
+	bucketSvc.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id != bucketID {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: "restored"}, nil
+	}
+
+	type restored struct {
+		req       influxdb.RestoreBucketRequest
+		tsm       string
+		tombstone string
+	}
+	var got restored
+	restoreSvc := mock.NewRestoreService()
+	restoreSvc.RestoreBucketFn = func(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error) {
+		got = restored{req: req}
+		b, err := ioutil.ReadAll(tsm)
+		if err != nil {
+			return 0, err
+		}
+		got.tsm = string(b)
+		if tombstone != nil {
+			b, err := ioutil.ReadAll(tombstone)
+			if err != nil {
+				return 0, err
+			}
+			got.tombstone = string(b)
+		}
+		return 3, nil
+	}
+
+	newClient := func(t *testing.T, permissions []influxdb.Permission) (*RestoreService, func()) {
+		h := NewRestoreHandler(&RestoreBackend{
+			Logger:           zaptest.NewLogger(t),
+			HTTPErrorHandler: kithttp.ErrorHandler(0),
+			RestoreService:   restoreSvc,
+			BucketService:    bucketSvc,
+		})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			a := &influxdb.Authorization{Status: influxdb.Active, Permissions: permissions}
+			h.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(r.Context(), a)))
+		}))
+		return &RestoreService{Addr: server.URL}, server.Close
+	}
+
+	writeBucket, err := influxdb.NewPermissionAtID(bucketID, influxdb.WriteAction, influxdb.BucketsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := influxdb.RestoreBucketRequest{
+		SourceOrgID:    sourceOrgID,
+		SourceBucketID: sourceBucketID,
+		BucketID:       bucketID,
+	}
+
+	t.Run("restores into the bucket", func(t *testing.T) {
+		client, done := newClient(t, []influxdb.Permission{*writeBucket})
+		defer done()
+
+		n, err := client.RestoreBucket(context.Background(), req, bytes.NewBufferString("tsm"), bytes.NewBufferString("tombstone"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 3 {
+			t.Errorf("unexpected points restored: got %d want 3", n)
+		}
+
+		want := restored{
+			req: influxdb.RestoreBucketRequest{
+				SourceOrgID:    sourceOrgID,
+				SourceBucketID: sourceBucketID,
+				OrgID:          orgID,
+				BucketID:       bucketID,
+			},
+			tsm:       "tsm",
+			tombstone: "tombstone",
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(restored{})); diff != "" {
+			t.Errorf("unexpected restore -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("tombstone is optional", func(t *testing.T) {
+		client, done := newClient(t, []influxdb.Permission{*writeBucket})
+		defer done()
+
+		if _, err := client.RestoreBucket(context.Background(), req, bytes.NewBufferString("tsm"), nil); err != nil {
+			t.Fatal(err)
+		}
+		if got.tombstone != "" {
+			t.Errorf("expected no tombstone, got %q", got.tombstone)
+		}
+	})
+
+	t.Run("bucket must belong to the organization", func(t *testing.T) {
+		client, done := newClient(t, []influxdb.Permission{*writeBucket})
+		defer done()
+
+		req := req
+		req.OrgID = sourceOrgID
+		_, err := client.RestoreBucket(context.Background(), req, bytes.NewBufferString("tsm"), nil)
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+}
+
+func TestRestoreService_RestoreChain(t *testing.T) {
+	fullID := influxdbtesting.MustIDBase16("0000000000000001")
+	chain := []*influxdb.BackupManifest{
+		{
+			ID:         fullID,
+			KVSequence: 1,
+			Files:      []influxdb.BackupFile{{Name: "000000001-000000001.tsm", Size: 3, Included: true}},
+			KV:         &influxdb.BackupFile{Name: "influxd.bolt", Size: 2, Included: true},
+		},
+		{
+			ID:         influxdbtesting.MustIDBase16("0000000000000002"),
+			ParentID:   &fullID,
+			KVSequence: 2,
+			Files: []influxdb.BackupFile{
+				{Name: "000000001-000000001.tsm", Size: 3},
+				{Name: "000000002-000000001.tsm", Size: 3, Included: true},
+			},
+			KV: &influxdb.BackupFile{Name: influxdb.BackupKVChangesFilename, Size: 7, Included: true},
+		},
+	}
+
+	readAll := func(r io.Reader) string {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	var (
+		gotChain   []*influxdb.BackupManifest
+		gotFiles   map[string]string
+		gotKV      string
+		gotChanges []string
+	)
+	restoreSvc := mock.NewRestoreService()
+	restoreSvc.RestoreEngineFn = func(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error {
+		gotChain = chain
+		gotFiles = make(map[string]string)
+		for name, r := range files {
+			gotFiles[name] = readAll(r)
+		}
+		return nil
+	}
+	kvRestoreSvc := mock.NewKVRestoreService()
+	kvRestoreSvc.RestoreKVStoreFn = func(ctx context.Context, chain []*influxdb.BackupManifest, kv io.Reader, changes []io.Reader) error {
+		gotChain = chain
+		gotKV = readAll(kv)
+		gotChanges = nil
+		for _, r := range changes {
+			gotChanges = append(gotChanges, readAll(r))
+		}
+		return nil
+	}
+
+	h := NewRestoreHandler(&RestoreBackend{
+		Logger:           zaptest.NewLogger(t),
+		HTTPErrorHandler: kithttp.ErrorHandler(0),
+		RestoreService:   restoreSvc,
+		KVRestoreService: kvRestoreSvc,
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := &RestoreService{Addr: server.URL}
+
+	t.Run("restores the engine files", func(t *testing.T) {
+		files := map[string]io.Reader{
+			"000000001-000000001.tsm": bytes.NewBufferString("one"),
+			"000000002-000000001.tsm": bytes.NewBufferString("two"),
+		}
+		if err := client.RestoreEngine(context.Background(), chain, files); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(chain, gotChain); diff != "" {
+			t.Errorf("unexpected chain -want/+got:\n%s", diff)
+		}
+		want := map[string]string{
+			"000000001-000000001.tsm": "one",
+			"000000002-000000001.tsm": "two",
+		}
+		if diff := cmp.Diff(want, gotFiles); diff != "" {
+			t.Errorf("unexpected files -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("restores the meta data files", func(t *testing.T) {
+		err := client.RestoreKVStore(context.Background(), chain, bytes.NewBufferString("kv"), []io.Reader{bytes.NewBufferString("changes")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(chain, gotChain); diff != "" {
+			t.Errorf("unexpected chain -want/+got:\n%s", diff)
+		}
+		if gotKV != "kv" {
+			t.Errorf("unexpected kv file: got %q want %q", gotKV, "kv")
+		}
+		if diff := cmp.Diff([]string{"changes"}, gotChanges); diff != "" {
+			t.Errorf("unexpected changes -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("chain must start with a full backup", func(t *testing.T) {
+		err := client.RestoreEngine(context.Background(), chain[1:], nil)
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixRunningQueries = "/api/v2/queries"
+)
+
+// RunningQueryBackend is all services and associated parameters required to construct
+// the RunningQueryHandler.
+type RunningQueryBackend struct {
+	influxdb.HTTPErrorHandler
+	log                 *zap.Logger
+	RunningQueryService influxdb.RunningQueryService
+}
+
+// NewRunningQueryBackend creates a backend used by the running query handler.
+func NewRunningQueryBackend(log *zap.Logger, b *APIBackend) *RunningQueryBackend {
+	return &RunningQueryBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		RunningQueryService: b.RunningQueryService,
+	}
+}
+
+// RunningQueryHandler is the handler for the queries running on the server.
+type RunningQueryHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RunningQueryService influxdb.RunningQueryService
+}
+
+// NewRunningQueryHandler creates a new RunningQueryHandler.
+func NewRunningQueryHandler(log *zap.Logger, b *RunningQueryBackend) *RunningQueryHandler {
+	h := &RunningQueryHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		RunningQueryService: b.RunningQueryService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixRunningQueries)
+
+	h.HandlerFunc("GET", prefixRunningQueries, h.handleGetRunningQueries)
+	h.HandlerFunc("GET", entityPath, h.handleGetRunningQuery)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteRunningQuery)
+
+	return h
+}
+
+type runningQueriesResponse struct {
+	Queries []*influxdb.RunningQuery `json:"queries"`
+}
+
+// handleGetRunningQueries is the HTTP handler for the GET /api/v2/queries route.
+func (h *RunningQueryHandler) handleGetRunningQueries(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RunningQueryHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	var filter influxdb.RunningQueryFilter
+	if orgID := r.URL.Query().Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}, w)
+			return
+		}
+		filter.OrgID = id
+	}
+
+	qs, err := h.RunningQueryService.FindRunningQueries(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Running queries retrieved", zap.Int("count", len(qs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, runningQueriesResponse{Queries: qs}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetRunningQuery is the HTTP handler for the GET /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleGetRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRunningQueryID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	q, err := h.RunningQueryService.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Running query retrieved", zap.String("queryID", id.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, q); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteRunningQuery is the HTTP handler for the DELETE /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleDeleteRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRunningQueryID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RunningQueryService.CancelRunningQuery(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Running query canceled", zap.String("queryID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func requestRunningQueryID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+	return *id, nil
+}
+
+func runningQueryIDPath(id influxdb.ID) string {
+	return path.Join(prefixRunningQueries, id.String())
+}
+
+// RunningQueryService connects to Influx via HTTP using tokens to manage running queries.
+type RunningQueryService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var q influxdb.RunningQuery
+	err := s.Client.
+		Get(runningQueryIDPath(id)).
+		DecodeJSON(&q).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// FindRunningQueries returns the running queries that match filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+
+	var resp runningQueriesResponse
+	err := s.Client.
+		Get(prefixRunningQueries).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Queries, nil
+}
+
+// CancelRunningQuery stops the execution of a running query.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(runningQueryIDPath(id)).
+		Do(ctx)
+}
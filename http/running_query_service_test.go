@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRunningQueryService(t *testing.T) {
+	ctx := context.Background()
+	queries := map[influxdb.ID]*influxdb.RunningQuery{
+		1: {ID: 1, OrgID: 10, State: "executing", Query: `from(bucket: "b") |> range(start: -1h)`, MemoryBytes: 1024},
+		2: {ID: 2, OrgID: 11, State: "queueing"},
+	}
+
+	svc := mock.NewRunningQueryService()
+	svc.FindRunningQueriesFn = func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+		var qs []*influxdb.RunningQuery
+		for _, id := range []influxdb.ID{1, 2} {
+			if q, ok := queries[id]; ok && (filter.OrgID == nil || q.OrgID == *filter.OrgID) {
+				qs = append(qs, q)
+			}
+		}
+		return qs, nil
+	}
+	svc.FindRunningQueryByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+		q, ok := queries[id]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "query not found"}
+		}
+		return q, nil
+	}
+	svc.CancelRunningQueryFn = func(ctx context.Context, id influxdb.ID) error {
+		if _, ok := queries[id]; !ok {
+			return &influxdb.Error{Code: influxdb.ENotFound, Msg: "query not found"}
+		}
+		delete(queries, id)
+		return nil
+	}
+
+	handler := NewRunningQueryHandler(zaptest.NewLogger(t), &RunningQueryBackend{
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		log:                 zaptest.NewLogger(t),
+		RunningQueryService: svc,
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := &RunningQueryService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	orgID := influxdb.ID(10)
+	qs, err := client.FindRunningQueries(ctx, influxdb.RunningQueryFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 || qs[0].ID != 1 || qs[0].Query != queries[1].Query || qs[0].MemoryBytes != 1024 {
+		t.Fatalf("unexpected running queries: %v", qs)
+	}
+
+	if err := client.CancelRunningQuery(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindRunningQueryByID(ctx, 1); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected canceled query to not be found, got %v", err)
+	}
+	if err := client.CancelRunningQuery(ctx, 1); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected canceling a finished query to fail, got %v", err)
+	}
+
+	q, err := client.FindRunningQueryByID(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.OrgID != 11 || q.State != "queueing" {
+		t.Errorf("unexpected running query: %+v", q)
+	}
+}
@@ -0,0 +1,509 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixScripts = "/api/v2/scripts"
+)
+
+// ScriptBackend is all services and associated parameters required to construct
+// the ScriptHandler.
+type ScriptBackend struct {
+	influxdb.HTTPErrorHandler
+	log               *zap.Logger
+	ScriptService     influxdb.ScriptService
+	LabelService      influxdb.LabelService
+	ProxyQueryService query.ProxyQueryService
+}
+
+// NewScriptBackend creates a backend used by the script handler.
+func NewScriptBackend(log *zap.Logger, b *APIBackend) *ScriptBackend {
+	return &ScriptBackend{
+		HTTPErrorHandler:  b.HTTPErrorHandler,
+		log:               log,
+		ScriptService:     b.ScriptService,
+		LabelService:      b.LabelService,
+		ProxyQueryService: b.FluxService,
+	}
+}
+
+// ScriptHandler is the handler for the script service.
+type ScriptHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	ScriptService     influxdb.ScriptService
+	LabelService      influxdb.LabelService
+	ProxyQueryService query.ProxyQueryService
+}
+
+// NewScriptHandler creates a new ScriptHandler.
+func NewScriptHandler(log *zap.Logger, b *ScriptBackend) *ScriptHandler {
+	h := &ScriptHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		ScriptService:     b.ScriptService,
+		LabelService:      b.LabelService,
+		ProxyQueryService: b.ProxyQueryService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixScripts)
+	entityInvokePath := fmt.Sprintf("%s/invoke", entityPath)
+	entityLabelsPath := fmt.Sprintf("%s/labels", entityPath)
+	entityLabelsIDPath := fmt.Sprintf("%s/:lid", entityLabelsPath)
+
+	h.HandlerFunc("GET", prefixScripts, h.handleGetScripts)
+	h.HandlerFunc("POST", prefixScripts, h.handlePostScript)
+	h.HandlerFunc("GET", entityPath, h.handleGetScript)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchScript)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteScript)
+	h.HandlerFunc("POST", entityInvokePath, h.handleInvokeScript)
+
+	labelBackend := &LabelBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              b.log.With(zap.String("handler", "label")),
+		LabelService:     b.LabelService,
+		ResourceType:     influxdb.ScriptsResourceType,
+	}
+	h.HandlerFunc("GET", entityLabelsPath, newGetLabelsHandler(labelBackend))
+	h.HandlerFunc("POST", entityLabelsPath, newPostLabelHandler(labelBackend))
+	h.HandlerFunc("DELETE", entityLabelsIDPath, newDeleteLabelHandler(labelBackend))
+
+	return h
+}
+
+type scriptLinks struct {
+	Self   string `json:"self"`
+	Labels string `json:"labels"`
+	Invoke string `json:"invoke"`
+}
+
+type scriptResponse struct {
+	influxdb.Script
+	Labels []influxdb.Label `json:"labels"`
+	Links  scriptLinks      `json:"links"`
+}
+
+func newScriptResponse(s *influxdb.Script, labels []*influxdb.Label) scriptResponse {
+	self := scriptIDPath(s.ID)
+	res := scriptResponse{
+		Script: *s,
+		Labels: []influxdb.Label{},
+		Links: scriptLinks{
+			Self:   self,
+			Labels: path.Join(self, "labels"),
+			Invoke: path.Join(self, "invoke"),
+		},
+	}
+	for _, l := range labels {
+		res.Labels = append(res.Labels, *l)
+	}
+	return res
+}
+
+type scriptsResponse struct {
+	Scripts []scriptResponse `json:"scripts"`
+}
+
+// handleGetScripts is the HTTP handler for the GET /api/v2/scripts route.
+func (h *ScriptHandler) handleGetScripts(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "ScriptHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := decodeScriptFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, _, err := h.ScriptService.FindScripts(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Scripts retrieved", zap.Int("count", len(ss)))
+
+	resp := scriptsResponse{Scripts: make([]scriptResponse, 0, len(ss))}
+	for _, s := range ss {
+		labels, _ := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+			ResourceID:   s.ID,
+			ResourceType: influxdb.ScriptsResourceType,
+		})
+		resp.Scripts = append(resp.Scripts, newScriptResponse(s, labels))
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeScriptFilter(r *http.Request) (influxdb.ScriptFilter, error) {
+	var filter influxdb.ScriptFilter
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	}
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+	return filter, nil
+}
+
+// handlePostScript is the HTTP handler for the POST /api/v2/scripts route.
+func (h *ScriptHandler) handlePostScript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var s influxdb.Script
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.ScriptService.CreateScript(ctx, &s); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Script created", zap.String("script", s.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newScriptResponse(&s, nil)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetScript is the HTTP handler for the GET /api/v2/scripts/:id route.
+func (h *ScriptHandler) handleGetScript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestScriptID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.ScriptService.FindScriptByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Script retrieved", zap.String("script", s.Name))
+
+	h.encodeScript(w, r, s)
+}
+
+// handlePatchScript is the HTTP handler for the PATCH /api/v2/scripts/:id route.
+func (h *ScriptHandler) handlePatchScript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestScriptID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.ScriptUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	s, err := h.ScriptService.UpdateScript(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Script updated", zap.String("script", s.Name))
+
+	h.encodeScript(w, r, s)
+}
+
+func (h *ScriptHandler) encodeScript(w http.ResponseWriter, r *http.Request, s *influxdb.Script) {
+	ctx := r.Context()
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+		ResourceID:   s.ID,
+		ResourceType: influxdb.ScriptsResourceType,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newScriptResponse(s, labels)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteScript is the HTTP handler for the DELETE /api/v2/scripts/:id route.
+func (h *ScriptHandler) handleDeleteScript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestScriptID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.ScriptService.DeleteScript(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Script deleted", zap.String("scriptID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// scriptInvocation is the body of a request to invoke a script.
+type scriptInvocation struct {
+	Params  query.Params `json:"params,omitempty"`
+	Dialect QueryDialect `json:"dialect"`
+}
+
+// handleInvokeScript is the HTTP handler for the POST /api/v2/scripts/:id/invoke route.
+// The script is queried with the parameters of the request, and its results are
+// returned as they are by the POST /api/v2/query route.
+func (h *ScriptHandler) handleInvokeScript(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleInvokeScript"
+	span, r := tracing.ExtractFromHTTPRequest(r, "ScriptHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestScriptID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var inv scriptInvocation
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&inv); err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "failed to decode request body",
+				Op:   op,
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	s, err := h.ScriptService.FindScriptByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+	auth, err := queryAuthorization(a, s.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	qr := QueryRequest{
+		Type:    "flux",
+		Query:   s.Script,
+		Params:  inv.Params,
+		Dialect: inv.Dialect,
+		Org:     &influxdb.Organization{ID: s.OrgID},
+	}.WithDefaults()
+	req, err := qr.ProxyRequest()
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+	req.Request.Authorization = auth
+	req.Request.Source = r.Header.Get("User-Agent")
+	req.Request.RemoteAddr = r.RemoteAddr
+
+	// Transform the context into one with the request's authorization.
+	ctx = pcontext.SetAuthorizer(ctx, auth)
+
+	hd, ok := req.Dialect.(HTTPDialect)
+	if !ok {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("unsupported dialect over HTTP: %T", req.Dialect),
+			Op:   op,
+		}, w)
+		return
+	}
+	hd.SetHeaders(w)
+
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		_ = tracing.LogError(span, err)
+		h.log.Info("Error writing response to client",
+			zap.String("handler", "script"),
+			zap.Error(err),
+		)
+	}
+}
+
+func requestScriptID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+	return *id, nil
+}
+
+func scriptIDPath(id influxdb.ID) string {
+	return path.Join(prefixScripts, id.String())
+}
+
+// ScriptService connects to Influx via HTTP using tokens to manage scripts.
+type ScriptService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.ScriptService = (*ScriptService)(nil)
+
+// FindScriptByID returns a single script by ID.
+func (s *ScriptService) FindScriptByID(ctx context.Context, id influxdb.ID) (*influxdb.Script, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp scriptResponse
+	err := s.Client.
+		Get(scriptIDPath(id)).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Script, nil
+}
+
+// FindScripts returns the scripts that match filter.
+func (s *ScriptService) FindScripts(ctx context.Context, filter influxdb.ScriptFilter, opt ...influxdb.FindOptions) ([]*influxdb.Script, int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.Name != nil {
+		params = append(params, [2]string{"name", *filter.Name})
+	}
+
+	var resp scriptsResponse
+	err := s.Client.
+		Get(prefixScripts).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	scripts := make([]*influxdb.Script, 0, len(resp.Scripts))
+	for i := range resp.Scripts {
+		scripts = append(scripts, &resp.Scripts[i].Script)
+	}
+	return scripts, len(scripts), nil
+}
+
+// CreateScript creates a new script and sets sc.ID with the new identifier.
+func (s *ScriptService) CreateScript(ctx context.Context, sc *influxdb.Script) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp scriptResponse
+	err := s.Client.
+		PostJSON(sc, prefixScripts).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*sc = resp.Script
+	return nil
+}
+
+// UpdateScript updates a single script with changeset.
+// Returns the new script after update.
+func (s *ScriptService) UpdateScript(ctx context.Context, id influxdb.ID, upd influxdb.ScriptUpdate) (*influxdb.Script, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp scriptResponse
+	err := s.Client.
+		PatchJSON(upd, scriptIDPath(id)).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Script, nil
+}
+
+// DeleteScript removes a script by ID.
+func (s *ScriptService) DeleteScript(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(scriptIDPath(id)).
+		Do(ctx)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestScriptService(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotReq *query.ProxyRequest
+	handler := NewScriptHandler(zaptest.NewLogger(t), &ScriptBackend{
+		HTTPErrorHandler: kithttp.ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		ScriptService:    svc,
+		LabelService:     svc,
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				gotReq = req
+				_, err := io.WriteString(w, "result\n")
+				return flux.Statistics{}, err
+			},
+		},
+	})
+	auth := &influxdb.Authorization{ID: 1, OrgID: org.ID}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(r.Context(), auth)))
+	}))
+	defer server.Close()
+
+	client := &ScriptService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	s := &influxdb.Script{
+		OrgID:  org.ID,
+		Name:   "cpu",
+		Script: `from(bucket: params.bucket) |> range(start: params.start)`,
+	}
+	if err := client.CreateScript(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.ID.Valid() {
+		t.Fatalf("unexpected script: %+v", s)
+	}
+
+	err := client.CreateScript(ctx, &influxdb.Script{OrgID: org.ID, Name: "empty"})
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Errorf("unexpected error code creating invalid script: got %q want %q", got, want)
+	}
+
+	desc := "cpu over a range"
+	updated, err := client.UpdateScript(ctx, s.ID, influxdb.ScriptUpdate{Description: &desc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Description != desc || updated.Script != s.Script {
+		t.Errorf("unexpected updated script: %+v", updated)
+	}
+
+	ss, n, err := client.FindScripts(ctx, influxdb.ScriptFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || ss[0].ID != s.ID {
+		t.Fatalf("unexpected scripts: %v", ss)
+	}
+
+	body := bytes.NewBufferString(`{"params": {"bucket": "telegraf", "start": {"type": "duration", "value": "-1h"}}}`)
+	resp, err := http.Post(server.URL+scriptIDPath(s.ID)+"/invoke", "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	out, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || string(out) != "result\n" {
+		t.Fatalf("unexpected invoke response: %d %q", resp.StatusCode, out)
+	}
+	if gotReq == nil {
+		t.Fatal("expected the script to be queried")
+	}
+	if got, want := gotReq.Request.OrganizationID, org.ID; got != want {
+		t.Errorf("unexpected organization: got %v want %v", got, want)
+	}
+	compiler, ok := gotReq.Request.Compiler.(lang.FluxCompiler)
+	if !ok {
+		t.Fatalf("unexpected compiler type: %T", gotReq.Request.Compiler)
+	}
+	if compiler.Query != s.Script {
+		t.Errorf("unexpected query: got %q want %q", compiler.Query, s.Script)
+	}
+	wantParams := query.Params{"bucket": "telegraf", "start": query.Duration("-1h")}
+	if diff := cmp.Diff(wantParams, gotReq.Request.Params); diff != "" {
+		t.Errorf("unexpected params -want/+got:\n%s", diff)
+	}
+
+	if err := client.DeleteScript(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindScriptByID(ctx, s.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected script to be deleted, got %v", err)
+	}
+}
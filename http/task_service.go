@@ -18,6 +18,7 @@ import (
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
 	"go.uber.org/zap"
 )
@@ -170,6 +171,7 @@ type Task struct {
 	CreatedAt       string                 `json:"createdAt,omitempty"`
 	UpdatedAt       string                 `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	Params          json.RawMessage        `json:"params,omitempty"`
 }
 
 type taskResponse struct {
@@ -215,6 +217,7 @@ func NewFrontEndTask(t influxdb.Task) Task {
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Metadata:        t.Metadata,
+		Params:          t.Params,
 	}
 }
 
@@ -611,12 +614,31 @@ func decodePostTaskRequest(ctx context.Context, r *http.Request) (*postTaskReque
 	if err := tc.Validate(); err != nil {
 		return nil, err
 	}
+	if err := validateTaskParams(tc.Params); err != nil {
+		return nil, err
+	}
 
 	return &postTaskRequest{
 		TaskCreate: tc,
 	}, nil
 }
 
+// validateTaskParams returns an error if the params of a task
+// are not parameters which can be bound to its query.
+func validateTaskParams(params json.RawMessage) error {
+	if len(params) == 0 {
+		return nil
+	}
+	var p query.Params
+	if err := json.Unmarshal(params, &p); err != nil {
+		return influxdb.ErrTaskParamsParse(err)
+	}
+	if _, err := p.File(); err != nil {
+		return influxdb.ErrTaskParamsParse(err)
+	}
+	return nil
+}
+
 func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req, err := decodeGetTaskRequest(ctx, r)
@@ -752,6 +774,9 @@ func decodeUpdateTaskRequest(ctx context.Context, r *http.Request) (*updateTaskR
 	if err := upd.Validate(); err != nil {
 		return nil, err
 	}
+	if err := validateTaskParams(upd.Params); err != nil {
+		return nil, err
+	}
 
 	return &updateTaskRequest{
 		Update: upd,
@@ -488,6 +488,77 @@ func TestTaskHandler_handlePostTasks(t *testing.T) {
 `,
 			},
 		},
+		{
+			name: "create task with params",
+			args: args{
+				taskCreate: platform.TaskCreate{
+					OrganizationID: 1,
+					Flux:           "abc",
+					Params:         json.RawMessage(`{"bucket":"telegraf"}`),
+				},
+			},
+			fields: fields{
+				taskService: &mock.TaskService{
+					CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+						return &platform.Task{
+							ID:             1,
+							Name:           "task1",
+							OrganizationID: 1,
+							OwnerID:        1,
+							Organization:   "test",
+							Flux:           "abc",
+							Params:         tc.Params,
+						}, nil
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "self": "/api/v2/tasks/0000000000000001",
+    "owners": "/api/v2/tasks/0000000000000001/owners",
+    "members": "/api/v2/tasks/0000000000000001/members",
+    "labels": "/api/v2/tasks/0000000000000001/labels",
+    "runs": "/api/v2/tasks/0000000000000001/runs",
+    "logs": "/api/v2/tasks/0000000000000001/logs"
+  },
+  "id": "0000000000000001",
+  "name": "task1",
+  "labels": [],
+  "orgID": "0000000000000001",
+  "ownerID": "0000000000000001",
+  "org": "test",
+  "status": "",
+  "flux": "abc",
+  "params": {"bucket": "telegraf"}
+}
+`,
+			},
+		},
+		{
+			name: "create task - invalid params",
+			args: args{
+				taskCreate: platform.TaskCreate{
+					OrganizationID: 1,
+					Flux:           "abc",
+					Params:         json.RawMessage(`{"bucket":null}`),
+				},
+			},
+			fields: fields{
+				taskService: &mock.TaskService{
+					CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+						return nil, errors.New("task should not be created")
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusBadRequest,
+				contentType: "application/json; charset=utf-8",
+			},
+		},
 		{
 			name: "create task - platform error creating task",
 			args: args{
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"bufio"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -8,6 +10,9 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb"
@@ -18,6 +23,7 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/write"
 	"go.uber.org/zap"
 )
 
@@ -71,6 +77,10 @@ type WriteHandler struct {
 	parserMaxBytes    int
 	parserMaxLines    int
 	parserMaxValues   int
+	streamChunkSize   int
+
+	// legacyPrecision accepts the minute and hour precisions of influxdb 1.x.
+	legacyPrecision bool
 }
 
 // WriteHandlerOption is a functional option for a *WriteHandler
@@ -108,6 +118,15 @@ func WithParserMaxValues(n int) WriteHandlerOption {
 	}
 }
 
+// WithStreamChunkSize specifies that line protocol is parsed as the request body is read,
+// rather than once the whole body has been read, and that the points of every n lines are
+// parsed and written together. When n is zero, the whole body is read before it is parsed.
+func WithStreamChunkSize(n int) WriteHandlerOption {
+	return func(w *WriteHandler) {
+		w.streamChunkSize = n
+	}
+}
+
 // Prefix provides the route prefix.
 func (*WriteHandler) Prefix() string {
 	return prefixWrite
@@ -190,7 +209,7 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := decodeWriteRequest(ctx, r)
+	req, err := decodeWriteRequest(ctx, r, h.legacyPrecision)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -249,6 +268,12 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format := write.FormatFromContentType(r.Header.Get("Content-Type"))
+	if h.streamChunkSize > 0 && format == write.FormatLineProtocol && !req.Partial {
+		requestBytes = h.writeStream(ctx, w, r, log, org.ID, bucket.ID, req)
+		return
+	}
+
 	data, err := readWriteRequest(ctx, r.Body, r.Header.Get("Content-Encoding"), h.maxBatchSizeBytes)
 	if err != nil {
 		log.Error("Error reading body", zap.Error(err))
@@ -270,39 +295,70 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Partial && format != write.FormatLineProtocol {
+		handleError(nil, influxdb.EInvalid, "partial writes are only supported for line protocol")
+		return
+	}
+
 	span, _ = tracing.StartSpanFromContextWithOperationName(ctx, "encoding and parsing")
 	encoded := tsdb.EncodeName(org.ID, bucket.ID)
-	mm := models.EscapeMeasurement(encoded[:])
 
-	var options []models.ParserOption
-	if len(h.parserOptions) > 0 {
-		options = make([]models.ParserOption, 0, len(h.parserOptions)+1)
-		options = append(options, h.parserOptions...)
-	}
+	var (
+		points []models.Point
+		report *models.LineReport
+	)
+	switch format {
+	case write.FormatCSV:
+		points, err = write.DecodeCSV(bytes.NewReader(data), string(encoded[:]))
+	case write.FormatJSON:
+		points, err = write.DecodeJSON(bytes.NewReader(data), string(encoded[:]), req.Unit)
+	default:
+		mm := models.EscapeMeasurement(encoded[:])
+
+		var options []models.ParserOption
+		if len(h.parserOptions) > 0 {
+			options = make([]models.ParserOption, 0, len(h.parserOptions)+1)
+			options = append(options, h.parserOptions...)
+		}
 
-	if req.Precision != nil {
-		options = append(options, req.Precision)
-	}
+		if req.Precision != nil {
+			options = append(options, req.Precision)
+		}
+
+		if req.Partial {
+			report = new(models.LineReport)
+			options = append(options, models.WithParserLineReport(report))
+		}
 
-	points, err := models.ParsePointsWithOptions(data, mm, options...)
+		points, err = models.ParsePointsWithOptions(data, mm, options...)
+	}
+	// the parser enforces the value limit on line protocol as it goes; points
+	// decoded from other formats are checked once they are all decoded.
+	if err == nil && format != write.FormatLineProtocol && h.parserMaxValues > 0 && len(points) > h.parserMaxValues {
+		err = models.ErrLimitMaxValuesExceeded
+	}
 	span.LogKV("values_total", len(points))
 	span.Finish()
 	if err != nil {
-		log.Error("Error parsing points", zap.Error(err))
+		log.Error("Error parsing points", zap.Error(err), zap.String("format", string(format)))
 
-		code := influxdb.EInvalid
-		if errors.Is(err, models.ErrLimitMaxBytesExceeded) ||
-			errors.Is(err, models.ErrLimitMaxLinesExceeded) ||
-			errors.Is(err, models.ErrLimitMaxValuesExceeded) {
-			code = influxdb.ETooLarge
-		}
+		handleError(err, parseErrorCode(err), "")
+		return
+	}
 
-		handleError(err, code, "")
+	if report != nil {
+		h.writePartial(ctx, w, log, bucket, points, report)
 		return
 	}
 
 	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
 		log.Error("Error writing points", zap.Error(err))
+		// points rejected by storage, such as those which do not conform
+		// to the schema of the bucket, are a client error.
+		if code := writeErrorCode(err); code != influxdb.EInternal {
+			handleError(err, code, "failure writing points to database")
+			return
+		}
 		handleError(err, influxdb.EInternal, "unexpected error writing points to database")
 		return
 	}
@@ -310,14 +366,319 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
+// writePartial writes every point that was parsed successfully and responds with
+// the lines which were rejected, either by the parser or when writing to storage.
+func (h *WriteHandler) writePartial(ctx context.Context, w http.ResponseWriter, log *zap.Logger, bucket *influxdb.Bucket, points []models.Point, report *models.LineReport) {
+	rejected := make(map[int]*influxdb.Error)
+	for _, le := range report.Errors {
+		rejected[le.Line] = &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to parse line",
+			Err:  le.Err,
+		}
+	}
+
+	// points older than the retention period of the bucket would be removed by
+	// the next retention check, so they are rejected rather than written.
+	lines := report.PointLines
+	if bucket.RetentionPeriod > 0 {
+		min := time.Now().Add(-bucket.RetentionPeriod)
+		kept := points[:0]
+		lines = make([]int, 0, len(points))
+		for i, p := range points {
+			if p.Time().Before(min) {
+				rejected[report.PointLines[i]] = &influxdb.Error{
+					Code: influxdb.EUnprocessableEntity,
+					Msg:  fmt.Sprintf("point is outside the retention period of %s", bucket.RetentionPeriod),
+				}
+				continue
+			}
+			kept = append(kept, p)
+			lines = append(lines, report.PointLines[i])
+		}
+		points = kept
+	}
+
+	if len(points) > 0 {
+		err := h.PointsWriter.WritePoints(ctx, points)
+
+		// storage may drop the points of different series for different
+		// reasons in the same write.
+		for _, err := range tsdb.WriteErrors(err) {
+			var (
+				conflictErr *tsdb.FieldTypeConflictError
+				limitErr    *tsdb.SeriesLimitError
+				partialErr  tsdb.PartialWriteError
+				dropped     [][]byte
+				reject      *influxdb.Error
+			)
+			switch {
+			case errors.As(err, &conflictErr):
+				dropped = conflictErr.SeriesKeys
+				reject = &influxdb.Error{Code: influxdb.EConflict, Msg: "field type conflict"}
+			case errors.As(err, &limitErr):
+				dropped = limitErr.SeriesKeys
+				reject = &influxdb.Error{Code: influxdb.ETooLarge, Msg: limitErr.Reason}
+			case errors.As(err, &partialErr):
+				dropped = partialErr.DroppedKeys
+				reject = &influxdb.Error{Code: influxdb.EInvalid, Msg: partialErr.Reason}
+			default:
+				log.Error("Error writing points", zap.Error(err))
+				h.HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.EInternal,
+					Op:   "http/handleWrite",
+					Msg:  "unexpected error writing points to database",
+					Err:  err,
+				}, w)
+				return
+			}
+
+			keys := make(map[string]struct{}, len(dropped))
+			for _, k := range dropped {
+				keys[string(k)] = struct{}{}
+			}
+			for i, p := range points {
+				if _, ok := keys[string(p.Key())]; ok {
+					rejected[lines[i]] = reject
+				}
+			}
+		}
+	}
+
+	if len(rejected) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	res := partialWriteResponse{
+		Code:    influxdb.EInvalid,
+		Message: fmt.Sprintf("partial write: rejected=%d", len(rejected)),
+		Lines:   make([]rejectedLine, 0, len(rejected)),
+	}
+	for _, le := range report.Errors {
+		res.Lines = append(res.Lines, rejectedLine{Line: le.Line, Text: le.Text, Err: rejected[le.Line]})
+		delete(rejected, le.Line)
+	}
+	for line, err := range rejected {
+		res.Lines = append(res.Lines, rejectedLine{Line: line, Text: report.Text(line), Err: err})
+	}
+	sort.Slice(res.Lines, func(i, j int) bool {
+		return res.Lines[i].Line < res.Lines[j].Line
+	})
+
+	w.Header().Set(kithttp.PlatformErrorCodeHeader, res.Code)
+	if err := encodeResponse(ctx, w, http.StatusBadRequest, res); err != nil {
+		log.Info("Error encoding response", zap.Error(err))
+	}
+}
+
+// writeStream parses line protocol as the request body is read and writes the points
+// of every streamChunkSize lines together. A chunk always ends on a line boundary,
+// so a line is either written in full or not at all. When the stream fails, every line
+// before the failing chunk has been written and no line after it has; the response
+// reports the number of written lines. It returns the number of bytes read.
+func (h *WriteHandler) writeStream(ctx context.Context, w http.ResponseWriter, r *http.Request, log *zap.Logger, orgID, bucketID influxdb.ID, req *postWriteRequest) int {
+	span, ctx := tracing.StartSpanFromContextWithOperationName(ctx, "streaming write")
+	defer span.Finish()
+
+	var (
+		requestBytes int
+		lines        int // lines read from the body
+		committed    int // lines written to storage
+		values       int
+		chunk        []byte
+		chunkLines   int
+	)
+	fail := func(err error, code, message string) {
+		log.Error("Error streaming points", zap.Error(err), zap.Int("committed_lines", committed))
+		res := streamWriteResponse{
+			Code:           code,
+			Message:        (&influxdb.Error{Code: code, Msg: message, Err: err}).Error(),
+			CommittedLines: committed,
+		}
+		w.Header().Set(kithttp.PlatformErrorCodeHeader, code)
+		if err := encodeResponse(ctx, w, kithttp.ErrorCodeToStatusCode(code), res); err != nil {
+			log.Info("Error encoding response", zap.Error(err))
+		}
+	}
+
+	body, err := newWriteRequestReader(r.Body, r.Header.Get("Content-Encoding"), h.maxBatchSizeBytes)
+	if err != nil {
+		fail(err, influxdb.EInvalid, "unable to read data")
+		return 0
+	}
+	defer body.Close()
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	mm := models.EscapeMeasurement(encoded[:])
+
+	// a single parser parses every chunk, so that the limits of the handler apply
+	// to the whole request and every line shares the default time of the request,
+	// as they do when the whole body is parsed at once.
+	options := make([]models.ParserOption, 0, len(h.parserOptions)+2)
+	options = append(options, h.parserOptions...)
+	options = append(options, models.WithParserDefaultTime(time.Now().UTC()))
+	if req.Precision != nil {
+		options = append(options, req.Precision)
+	}
+	parser := models.NewPointsParser(mm, options...)
+
+	// parse parses the lines of the chunk. The points refer to the chunk, so the
+	// next lines are read into a new one.
+	parse := func() ([]models.Point, error) {
+		points, err := parser.ParsePoints(chunk)
+		values += len(points)
+		chunk, chunkLines = nil, 0
+		return points, err
+	}
+	write := func(points []models.Point) error {
+		if len(points) == 0 {
+			return nil
+		}
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			return err
+		}
+		committed = lines
+		return nil
+	}
+
+	maxLine := bufio.MaxScanTokenSize
+	if h.maxBatchSizeBytes > 0 {
+		maxLine = int(h.maxBatchSizeBytes)
+	}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLine)
+	scanner.Split(models.ScanLines)
+	for scanner.Scan() {
+		// the scanner returns the lines read along with a failure to read the
+		// body, such as a body exceeding the batch size, and the last of them
+		// may be truncated, so none of them is written.
+		if err := scanner.Err(); err != nil {
+			fail(err, readErrorCode(err), "unable to read data")
+			return requestBytes
+		}
+
+		line := scanner.Bytes()
+		requestBytes += len(line)
+		lines++
+		chunk = append(chunk, line...)
+		chunkLines++
+		if chunkLines < h.streamChunkSize {
+			continue
+		}
+
+		points, err := parse()
+		if err != nil {
+			fail(err, parseErrorCode(err), "")
+			return requestBytes
+		}
+		if err := write(points); err != nil {
+			fail(err, writeErrorCode(err), "failure writing points to database")
+			return requestBytes
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fail(err, readErrorCode(err), "unable to read data")
+		return requestBytes
+	}
+
+	// closing the body reports whether it exceeded the batch size, which must be
+	// known before the last chunk is written.
+	if err := body.Close(); err != nil {
+		fail(err, readErrorCode(err), "unable to read data")
+		return requestBytes
+	}
+
+	points, err := parse()
+	if err != nil {
+		fail(err, parseErrorCode(err), "")
+		return requestBytes
+	}
+	if values == 0 {
+		fail(nil, influxdb.EInvalid, "writing requires points")
+		return requestBytes
+	}
+	if err := write(points); err != nil {
+		fail(err, writeErrorCode(err), "failure writing points to database")
+		return requestBytes
+	}
+	span.LogKV("values_total", values)
+
+	w.WriteHeader(http.StatusNoContent)
+	return requestBytes
+}
+
+// streamWriteResponse is returned when a streaming write fails. The first
+// CommittedLines lines of the request have been written.
+type streamWriteResponse struct {
+	Code           string `json:"code"`
+	Message        string `json:"message"`
+	CommittedLines int    `json:"committedLines"`
+}
+
+// readErrorCode returns the error code of a failure to read a write request.
+func readErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrMaxBatchSizeExceeded), errors.Is(err, bufio.ErrTooLong):
+		return influxdb.ETooLarge
+	case errors.Is(err, gzip.ErrHeader), errors.Is(err, gzip.ErrChecksum):
+		return influxdb.EInvalid
+	default:
+		return influxdb.EInternal
+	}
+}
+
+// parseErrorCode returns the error code of a failure to parse points.
+func parseErrorCode(err error) string {
+	if errors.Is(err, models.ErrLimitMaxBytesExceeded) ||
+		errors.Is(err, models.ErrLimitMaxLinesExceeded) ||
+		errors.Is(err, models.ErrLimitMaxValuesExceeded) {
+		return influxdb.ETooLarge
+	}
+	return influxdb.EInvalid
+}
+
+// writeErrorCode returns the error code of a failure to write points to storage.
+// Points rejected by storage, such as those which do not conform to the schema of
+// the bucket, are a client error. Points which would create more series than
+// the bucket or organization allows are rejected as too large.
+func writeErrorCode(err error) string {
+	code := influxdb.EInternal
+	for _, err := range tsdb.WriteErrors(err) {
+		var partialErr tsdb.PartialWriteError
+		switch {
+		case errors.Is(err, tsdb.ErrSeriesLimitExceeded):
+			return influxdb.ETooLarge
+		case errors.As(err, &partialErr):
+			code = influxdb.EUnprocessableEntity
+		}
+	}
+	return code
+}
+
+// partialWriteResponse is returned by a partial write when one or more lines were
+// rejected. Every accepted line has been written.
+type partialWriteResponse struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Lines   []rejectedLine `json:"lines"`
+}
+
+// rejectedLine describes a single line of a partial write which was not written.
+type rejectedLine struct {
+	Line int             `json:"line"`
+	Text string          `json:"text"`
+	Err  *influxdb.Error `json:"error"`
+}
+
+func decodeWriteRequest(ctx context.Context, r *http.Request, legacyPrecision bool) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
 	if p == "" {
 		p = "ns"
 	}
 
-	if !models.ValidPrecision(p) {
+	if !models.ValidPrecision(p) && !(legacyPrecision && (p == "m" || p == "h")) {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Op:   "http/decodeWriteRequest",
@@ -330,14 +691,32 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		precision = models.WithParserPrecision(p)
 	}
 
+	var partial bool
+	if v := qp.Get("partial"); v != "" {
+		var err error
+		if partial, err = strconv.ParseBool(v); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  "invalid partial; must be true or false",
+			}
+		}
+	}
+
 	return &postWriteRequest{
 		Bucket:    qp.Get("bucket"),
 		Org:       qp.Get("org"),
 		Precision: precision,
+		Unit:      p,
+		Partial:   partial,
 	}, nil
 }
 
 func readWriteRequest(ctx context.Context, rc io.ReadCloser, encoding string, maxBatchSizeBytes int64) (v []byte, err error) {
+	rc, err = newWriteRequestReader(rc, encoding, maxBatchSizeBytes)
+	if err != nil {
+		return nil, err
+	}
 	defer func() {
 		// close the reader now that all bytes have been consumed
 		// this will return non-nil in the case of a configured limit
@@ -347,12 +726,27 @@ func readWriteRequest(ctx context.Context, rc io.ReadCloser, encoding string, ma
 		}
 	}()
 
+	span, _ := tracing.StartSpanFromContextWithOperationName(ctx, "read request body")
+	defer func() {
+		span.LogKV("request_bytes", len(v))
+		span.Finish()
+	}()
+
+	return ioutil.ReadAll(rc)
+}
+
+// newWriteRequestReader returns a reader of the decompressed request body. When
+// a limit is configured on the number of bytes in a batch, reading and closing
+// the reader return ErrMaxBatchSizeExceeded once more bytes than the limit have
+// been read.
+func newWriteRequestReader(rc io.ReadCloser, encoding string, maxBatchSizeBytes int64) (io.ReadCloser, error) {
 	switch encoding {
 	case "gzip", "x-gzip":
-		rc, err = gzip.NewReader(rc)
+		gz, err := gzip.NewReader(rc)
 		if err != nil {
 			return nil, err
 		}
+		rc = gz
 	}
 
 	// given a limit is configured on the number of bytes in a
@@ -360,27 +754,24 @@ func readWriteRequest(ctx context.Context, rc io.ReadCloser, encoding string, ma
 	if maxBatchSizeBytes > 0 {
 		rc = newLimitedReadCloser(rc, maxBatchSizeBytes)
 	}
-
-	span, _ := tracing.StartSpanFromContextWithOperationName(ctx, "read request body")
-	defer func() {
-		span.LogKV("request_bytes", len(v))
-		span.Finish()
-	}()
-
-	return ioutil.ReadAll(rc)
+	return rc, nil
 }
 
 type postWriteRequest struct {
 	Org       string
 	Bucket    string
 	Precision models.ParserOption
+	Unit      string // Unit is the name of the precision, such as ms.
+	Partial   bool
 }
 
-// WriteService sends data over HTTP to influxdb via line protocol.
+// WriteService sends data over HTTP to influxdb via line protocol, or in the
+// annotated CSV or JSON points format when Format is set.
 type WriteService struct {
 	Addr               string
 	Token              string
 	Precision          string
+	Format             write.Format
 	InsecureSkipVerify bool
 }
 
@@ -415,7 +806,7 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID influxdb.ID, r
 		return err
 	}
 
-	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Type", s.Format.ContentType())
 	req.Header.Set("Content-Encoding", "gzip")
 	SetToken(s.Token, req)
 
@@ -476,6 +867,18 @@ func newLimitedReadCloser(r io.ReadCloser, n int64) *limitedReader {
 	}
 }
 
+// Read returns an ErrMaxBatchSizeExceeded once the wrapped reader exceeds
+// the set limit for number of bytes, so that a batch cut at the limit is not
+// mistaken for the whole batch.
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.LimitedReader.Read(p)
+	if l.N < 1 {
+		l.err = ErrMaxBatchSizeExceeded
+		return n, l.err
+	}
+	return n, err
+}
+
 // Close returns an ErrMaxBatchSizeExceeded when the wrapped reader
 // exceeds the set limit for number of bytes.
 // This is safe to call more than once but not concurrently.
@@ -10,13 +10,16 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http/metric"
 	httpmock "github.com/influxdata/influxdb/http/mock"
 	kithttp "github.com/influxdata/influxdb/kit/transport/http"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
 	influxtesting "github.com/influxdata/influxdb/testing"
+	"github.com/influxdata/influxdb/tsdb"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -96,10 +99,12 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 
 	// request is sent to the HTTP endpoint
 	type request struct {
-		auth   influxdb.Authorizer
-		org    string
-		bucket string
-		body   string
+		auth        influxdb.Authorizer
+		org         string
+		bucket      string
+		body        string
+		contentType string
+		partial     bool
 	}
 
 	tests := []struct {
@@ -329,6 +334,195 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"request too large","message":"points: number of values exceeded"}`,
 			},
 		},
+		{
+			name: "partial write of valid lines is accepted",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				body:    "m1,t1=v1 f1=1\nm1,t1=v2 f1=2\n",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				partial: true,
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "partial write reports lines which cannot be parsed",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				body:    "m1,t1=v1 f1=1\ninvalid\nm1,t1=v2 f1=2\n",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				partial: true,
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"partial write: rejected=1","lines":[{"line":2,"text":"invalid","error":{"code":"invalid","message":"unable to parse line","error":"missing fields"}}]}` + "\n",
+			},
+		},
+		{
+			name: "partial write reports field type conflicts",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				body:    "m1,t1=v1 f1=1\nm1,t1=v2 f1=2\n",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				partial: true,
+			},
+			state: state{
+				org:      testOrg("043e0780ee2b1000"),
+				bucket:   testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				writeErr: fieldTypeConflict("043e0780ee2b1000", "04504b356e23b000", "m1,t1=v2 f1=2"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"partial write: rejected=1","lines":[{"line":2,"text":"m1,t1=v2 f1=2","error":{"code":"conflict","message":"field type conflict"}}]}` + "\n",
+			},
+		},
+		{
+			name: "partial write reports points outside retention",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				body:    "m1,t1=v1 f1=1\nm1,t1=v2 f1=2 1000\n",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				partial: true,
+			},
+			state: state{
+				org: testOrg("043e0780ee2b1000"),
+				bucket: &influxdb.Bucket{
+					ID:              influxtesting.MustIDBase16("04504b356e23b000"),
+					OrgID:           influxtesting.MustIDBase16("043e0780ee2b1000"),
+					RetentionPeriod: time.Hour,
+				},
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"partial write: rejected=1","lines":[{"line":2,"text":"m1,t1=v2 f1=2 1000","error":{"code":"unprocessable entity","message":"point is outside the retention period of 1h0m0s"}}]}` + "\n",
+			},
+		},
+		{
+			name: "partial write still enforces limits",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				body:    "m1,t1=v1 f1=1\nm1,t1=v1 f1=1\nm1,t1=v1 f1=1\n",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				partial: true,
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				opts:   []WriteHandlerOption{WithParserMaxLines(2)},
+			},
+			wants: wants{
+				code: 413,
+				body: `{"code":"request too large","message":"points: number of lines exceeded"}`,
+			},
+		},
+		{
+			name: "annotated CSV body is accepted",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body: "#datatype,string,long,dateTime:RFC3339,string,string,string,double\n" +
+					"#group,false,false,false,true,true,true,false\n" +
+					"#default,_result,,,,,,\n" +
+					",result,table,_time,_measurement,_field,host,_value\n" +
+					",,0,2020-01-01T00:00:00Z,cpu,usage,a,0.5\n",
+				contentType: "text/csv",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "JSON body is accepted",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"measurement":"cpu","tags":{"host":"a"},"fields":{"usage":0.5},"time":1}]`,
+				contentType: "application/json",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "invalid JSON points return 400",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"fields":{"usage":0.5}}]`,
+				contentType: "application/json",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"point 0: missing measurement"}`,
+			},
+		},
+		{
+			name: "JSON points are subject to the values limit",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"measurement":"cpu","fields":{"usage":0.5,"idle":0.5}}]`,
+				contentType: "application/json",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				opts:   []WriteHandlerOption{WithParserMaxValues(1)},
+			},
+			wants: wants{
+				code: 413,
+				body: `{"code":"request too large","message":"points: number of values exceeded"}`,
+			},
+		},
+		{
+			name: "partial write requires line protocol",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"measurement":"cpu","fields":{"usage":0.5}}]`,
+				contentType: "application/json",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				partial:     true,
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"partial writes are only supported for line protocol"}`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -357,10 +551,16 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				"http://localhost:9999/api/v2/write",
 				strings.NewReader(tt.request.body),
 			)
+			if tt.request.contentType != "" {
+				r.Header.Set("Content-Type", tt.request.contentType)
+			}
 
 			params := r.URL.Query()
 			params.Set("org", tt.request.org)
 			params.Set("bucket", tt.request.bucket)
+			if tt.request.partial {
+				params.Set("partial", "true")
+			}
 			r.URL.RawQuery = params.Encode()
 
 			w := httptest.NewRecorder()
@@ -376,6 +576,144 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 	}
 }
 
+func TestWriteHandler_handleWriteStream(t *testing.T) {
+	type wants struct {
+		code   int
+		body   string
+		writes int // number of chunks written
+		points int // number of points written
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		opts  []WriteHandlerOption
+		wants wants
+	}{
+		{
+			name: "points are written in chunks",
+			body: "m1 f1=1\nm1 f1=2\nm1 f1=3\nm1 f1=4\nm1 f1=5\n",
+			wants: wants{
+				code:   204,
+				writes: 3,
+				points: 5,
+			},
+		},
+		{
+			name: "chunks end on a line boundary",
+			body: "m1 f1=1,f2=1,f3=1\nm1 f1=2\nm1 f1=3\n",
+			wants: wants{
+				code:   204,
+				writes: 2,
+				points: 5,
+			},
+		},
+		{
+			name: "lines before the failing chunk are committed",
+			body: "m1 f1=1\nm1 f1=2\nm1 f1=3\ninvalid\nm1 f1=5\n",
+			wants: wants{
+				code:   400,
+				body:   `{"code":"invalid","message":"unable to parse 'invalid': missing fields","committedLines":2}` + "\n",
+				writes: 1,
+				points: 2,
+			},
+		},
+		{
+			name: "lines limit is enforced across chunks",
+			body: "m1 f1=1\nm1 f1=2\nm1 f1=3\n",
+			opts: []WriteHandlerOption{WithParserMaxLines(2)},
+			wants: wants{
+				code:   413,
+				body:   `{"code":"request too large","message":"points: number of lines exceeded","committedLines":2}` + "\n",
+				writes: 1,
+				points: 2,
+			},
+		},
+		{
+			name: "values limit is enforced across chunks",
+			body: "m1 f1=1\nm1 f1=2\nm1 f1=3,f2=3,f3=3\n",
+			opts: []WriteHandlerOption{WithParserMaxValues(3)},
+			wants: wants{
+				code:   413,
+				body:   `{"code":"request too large","message":"points: number of values exceeded","committedLines":2}` + "\n",
+				writes: 1,
+				points: 2,
+			},
+		},
+		{
+			name: "empty body returns 400",
+			body: "",
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"writing requires points","committedLines":0}` + "\n",
+			},
+		},
+		{
+			name: "body without points returns 400",
+			body: "# comment\n\n",
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"writing requires points","committedLines":0}` + "\n",
+			},
+		},
+		{
+			name: "line cut at the batch size is not written",
+			body: "m1 f1=1\nm1 f1=12345\n",
+			opts: []WriteHandlerOption{WithMaxBatchSizeBytes(14)},
+			wants: wants{
+				code: 413,
+				body: `{"code":"request too large","message":"unable to read data: points batch is too large","committedLines":0}` + "\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return testOrg("043e0780ee2b1000"), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return testBucket("043e0780ee2b1000", "04504b356e23b000"), nil
+			}
+			pw := &mock.PointsWriter{}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				PointsWriter:        pw,
+				WriteEventRecorder:  &metric.NopEventRecorder{},
+			}
+			opts := append([]WriteHandlerOption{WithStreamChunkSize(2)}, tt.opts...)
+			writeHandler := NewWriteHandler(zaptest.NewLogger(t), NewWriteBackend(zaptest.NewLogger(t), b), opts...)
+			handler := httpmock.NewAuthMiddlewareHandler(writeHandler, bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"))
+
+			r := httptest.NewRequest(
+				"POST",
+				"http://localhost:9999/api/v2/write?org=043e0780ee2b1000&bucket=04504b356e23b000",
+				strings.NewReader(tt.body),
+			)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+			if got, want := pw.WritePointsCalled(), tt.wants.writes; got != want {
+				t.Errorf("unexpected number of chunks written: got %d want %d", got, want)
+			}
+			if got, want := len(pw.Points), tt.wants.points; got != want {
+				t.Errorf("unexpected number of points written: got %d want %d", got, want)
+			}
+		})
+	}
+}
+
 var DefaultErrorHandler = kithttp.ErrorHandler(0)
 
 func bucketWritePermission(org, bucket string) *influxdb.Authorization {
@@ -404,6 +742,22 @@ func testOrg(org string) *influxdb.Organization {
 	}
 }
 
+// fieldTypeConflict returns the error reported by the storage engine when the
+// series written by line conflict with the types already stored.
+func fieldTypeConflict(org, bucket, line string) error {
+	encoded := tsdb.EncodeName(influxtesting.MustIDBase16(org), influxtesting.MustIDBase16(bucket))
+	points, err := models.ParsePointsWithOptions([]byte(line), models.EscapeMeasurement(encoded[:]))
+	if err != nil {
+		panic(err)
+	}
+
+	var keys [][]byte
+	for _, p := range points {
+		keys = append(keys, p.Key())
+	}
+	return &tsdb.FieldTypeConflictError{SeriesKeys: keys}
+}
+
 func testBucket(org, bucket string) *influxdb.Bucket {
 	oid := influxtesting.MustIDBase16(org)
 	bid := influxtesting.MustIDBase16(bucket)
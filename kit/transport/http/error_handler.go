@@ -24,10 +24,7 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	}
 
 	code := influxdb.ErrorCode(err)
-	httpCode, ok := statusCodePlatformError[code]
-	if !ok {
-		httpCode = http.StatusBadRequest
-	}
+	httpCode := ErrorCodeToStatusCode(code)
 	w.Header().Set(PlatformErrorCodeHeader, code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(httpCode)
@@ -45,6 +42,14 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	_, _ = w.Write(b)
 }
 
+// ErrorCodeToStatusCode returns the http status code used to report a platform error code.
+func ErrorCodeToStatusCode(code string) int {
+	if httpCode, ok := statusCodePlatformError[code]; ok {
+		return httpCode
+	}
+	return http.StatusBadRequest
+}
+
 // statusCodePlatformError is the map convert platform.Error to error
 var statusCodePlatformError = map[string]int{
 	influxdb.EInternal:            http.StatusInternalServerError,
@@ -26,6 +26,14 @@ func (w *StatusResponseWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Flush sends any buffered data to the client, if the wrapped ResponseWriter
+// supports it, so that streamed responses are not held back.
+func (w *StatusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func (w *StatusResponseWriter) Code() int {
 	code := w.statusCode
 	if code == 0 {
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusResponseWriter_Flush(t *testing.T) {
+	rec := httptest.NewRecorder()
+	var w http.ResponseWriter = NewStatusResponseWriter(rec)
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		t.Fatal("expected the status response writer to be a flusher")
+	}
+	if _, err := w.Write([]byte("frame")); err != nil {
+		t.Fatal(err)
+	}
+	f.Flush()
+	if !rec.Flushed {
+		t.Error("expected the wrapped response writer to be flushed")
+	}
+}
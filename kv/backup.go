@@ -2,9 +2,219 @@ package kv
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var (
+	backupManifestBucket = []byte("backupmanifestsv1")
 )
 
+var _ influxdb.KVBackupService = (*Service)(nil)
+var _ influxdb.BackupManifestService = (*Service)(nil)
+var _ influxdb.KVRestoreService = (*Service)(nil)
+
+// ChangeJournal is implemented by stores which journal the changes made to
+// them, so that only the changes made since an earlier backup are backed up.
+type ChangeJournal interface {
+	// ChangeSequence returns the sequence of the last change made to the store.
+	ChangeSequence(ctx context.Context) (uint64, error)
+	// BackupChanges writes the changes made after the change sequence since to w,
+	// and returns the sequence of the last change written.
+	BackupChanges(ctx context.Context, since uint64, w io.Writer) (uint64, error)
+	// TruncateChanges removes the changes up to the change sequence seq.
+	TruncateChanges(ctx context.Context, seq uint64) error
+}
+
+// Restorer is implemented by stores which can replace all of their data
+// with the data of a backup while they are open.
+type Restorer interface {
+	// Restore replaces the data of the store with the backup read from r,
+	// with the changes read from changes replayed on top of it, in order.
+	Restore(ctx context.Context, r io.Reader, changes ...io.Reader) error
+}
+
 func (s *Service) Backup(ctx context.Context, w io.Writer) error {
 	return s.kv.Backup(ctx, w)
 }
+
+func (s *Service) changeJournal() (ChangeJournal, error) {
+	j, ok := s.kv.(ChangeJournal)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Msg:  "store does not journal changes, incremental backups are not supported",
+		}
+	}
+	return j, nil
+}
+
+// ChangeSequence returns the sequence of the last change made to the store.
+func (s *Service) ChangeSequence(ctx context.Context) (uint64, error) {
+	j, err := s.changeJournal()
+	if err != nil {
+		return 0, err
+	}
+	return j.ChangeSequence(ctx)
+}
+
+// BackupChanges writes the changes made to the store after the change
+// sequence since to w.
+func (s *Service) BackupChanges(ctx context.Context, since uint64, w io.Writer) (uint64, error) {
+	j, err := s.changeJournal()
+	if err != nil {
+		return 0, err
+	}
+	return j.BackupChanges(ctx, since, w)
+}
+
+// TruncateChanges removes the changes up to the change sequence seq from the
+// journal of the store.
+func (s *Service) TruncateChanges(ctx context.Context, seq uint64) error {
+	j, err := s.changeJournal()
+	if err != nil {
+		return err
+	}
+	return j.TruncateChanges(ctx, seq)
+}
+
+// RestoreKVStore replaces the data of the store with the meta data of a
+// chain of backups, once the chain is validated. The meta data files are
+// verified against the manifests of the chain as they are read, and the store
+// is left untouched if they do not match.
+func (s *Service) RestoreKVStore(ctx context.Context, chain []*influxdb.BackupManifest, kv io.Reader, changes []io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, ok := s.kv.(Restorer)
+	if !ok {
+		return &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Op:   OpPrefix + influxdb.OpRestoreKVStore,
+			Msg:  "store does not support online restores",
+		}
+	}
+
+	if err := influxdb.ValidateBackupChain(chain); err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpRestoreKVStore,
+			Err: err,
+		}
+	}
+	if len(changes) != len(chain)-1 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   OpPrefix + influxdb.OpRestoreKVStore,
+			Msg:  fmt.Sprintf("expected the meta data changes of %d incremental backups, got %d", len(chain)-1, len(changes)),
+		}
+	}
+
+	kv = verifyKVFile(kv, chain[0])
+	verified := make([]io.Reader, len(changes))
+	for i, c := range changes {
+		verified[i] = verifyKVFile(c, chain[i+1])
+	}
+
+	if err := r.Restore(ctx, kv, verified...); err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpRestoreKVStore,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// verifyKVFile verifies the meta data file read from r against the manifest
+// m, if the backup of m recorded it.
+func verifyKVFile(r io.Reader, m *influxdb.BackupManifest) io.Reader {
+	if m.KV == nil {
+		return r
+	}
+	return influxdb.NewBackupFileReader(r, *m.KV)
+}
+
+func (s *Service) initializeBackupManifests(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(backupManifestBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindBackupManifestByID returns the manifest of a backup.
+func (s *Service) FindBackupManifestByID(ctx context.Context, id influxdb.ID) (*influxdb.BackupManifest, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var m *influxdb.BackupManifest
+	err := s.kv.View(ctx, func(tx Tx) error {
+		encodedID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(backupManifestBucket)
+		if err != nil {
+			return err
+		}
+
+		v, err := b.Get(encodedID)
+		if IsNotFound(err) {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "backup not found",
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		m = &influxdb.BackupManifest{}
+		return json.Unmarshal(v, m)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindBackupManifestByID,
+			Err: err,
+		}
+	}
+	return m, nil
+}
+
+// CreateBackupManifest stores the manifest of a backup and sets m.ID.
+func (s *Service) CreateBackupManifest(ctx context.Context, m *influxdb.BackupManifest) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m.ID = s.IDGenerator.ID()
+		encodedID, err := m.ID.Encode()
+		if err != nil {
+			return err
+		}
+
+		v, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(backupManifestBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(encodedID, v)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpCreateBackupManifest,
+			Err: err,
+		}
+	}
+	return nil
+}
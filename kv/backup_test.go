@@ -0,0 +1,70 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_BackupManifests(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	full := &influxdb.BackupManifest{
+		KVSequence: 10,
+		Files: []influxdb.BackupFile{
+			{Name: "000000001-000000001.tsm", Size: 1024, Included: true},
+		},
+	}
+	if err := svc.CreateBackupManifest(ctx, full); err != nil {
+		t.Fatal(err)
+	}
+	if !full.ID.Valid() {
+		t.Fatal("expected manifest to have an ID")
+	}
+
+	inc := &influxdb.BackupManifest{
+		ParentID:   &full.ID,
+		KVSequence: 12,
+		Files: []influxdb.BackupFile{
+			{Name: "000000001-000000001.tsm", Size: 1024},
+			{Name: "000000002-000000001.tsm", Size: 512, Included: true},
+		},
+	}
+	if err := svc.CreateBackupManifest(ctx, inc); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.FindBackupManifestByID(ctx, inc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(inc, got); diff != "" {
+		t.Errorf("unexpected manifest -want/+got:\n%s", diff)
+	}
+	if !got.Incremental() {
+		t.Error("expected manifest to be incremental")
+	}
+
+	if _, err := svc.FindBackupManifestByID(ctx, influxdb.ID(1)); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	// the inmem store does not journal its changes.
+	if _, err := svc.ChangeSequence(ctx); influxdb.ErrorCode(err) != influxdb.EMethodNotAllowed {
+		t.Errorf("expected changes of the inmem store to not be supported, got %v", err)
+	}
+}
@@ -133,7 +133,7 @@ func (s *Service) FindBucketByName(ctx context.Context, orgID influxdb.ID, n str
 	return b, err
 }
 
-// CreateSystemBuckets creates the task and monitoring system buckets for an organization
+// CreateSystemBuckets creates the task, monitoring and query system buckets for an organization
 func (s *Service) createSystemBuckets(ctx context.Context, tx Tx, o *influxdb.Organization) error {
 	tb := &influxdb.Bucket{
 		OrgID:           o.ID,
@@ -155,7 +155,19 @@ func (s *Service) createSystemBuckets(ctx context.Context, tx Tx, o *influxdb.Or
 		Description:     "System bucket for monitoring logs",
 	}
 
-	return s.createBucket(ctx, tx, mb)
+	if err := s.createBucket(ctx, tx, mb); err != nil {
+		return err
+	}
+
+	qb := &influxdb.Bucket{
+		OrgID:           o.ID,
+		Type:            influxdb.BucketTypeSystem,
+		Name:            influxdb.QueriesSystemBucketName,
+		RetentionPeriod: influxdb.QueriesSystemBucketRetention,
+		Description:     "System bucket for query logs",
+	}
+
+	return s.createBucket(ctx, tx, qb)
 }
 
 func (s *Service) findBucketByName(ctx context.Context, tx Tx, orgID influxdb.ID, n string) (*influxdb.Bucket, error) {
@@ -200,6 +212,15 @@ func (s *Service) findBucketByName(ctx context.Context, tx Tx, orgID influxdb.ID
 				Description:     "System bucket for monitoring logs",
 				OrgID:           orgID,
 			}, nil
+		case influxdb.QueriesSystemBucketName:
+			return &influxdb.Bucket{
+				ID:              influxdb.QueriesSystemBucketID,
+				Type:            influxdb.BucketTypeSystem,
+				Name:            influxdb.QueriesSystemBucketName,
+				RetentionPeriod: influxdb.QueriesSystemBucketRetention,
+				Description:     "System bucket for query logs",
+				OrgID:           orgID,
+			}, nil
 		default:
 			return nil, &influxdb.Error{
 				Code: influxdb.ENotFound,
@@ -381,6 +402,16 @@ func (s *Service) FindBuckets(ctx context.Context, filter influxdb.BucketFilter,
 		}
 
 		bs = append(bs, mb)
+
+		qb := &influxdb.Bucket{
+			ID:              influxdb.QueriesSystemBucketID,
+			Type:            influxdb.BucketTypeSystem,
+			Name:            influxdb.QueriesSystemBucketName,
+			RetentionPeriod: influxdb.QueriesSystemBucketRetention,
+			Description:     "System bucket for query logs",
+		}
+
+		bs = append(bs, qb)
 	}
 
 	if err != nil {
@@ -814,7 +845,7 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
-	return nil
+	return s.deleteBucketMeasurementSchemas(ctx, tx, id)
 }
 
 const bucketOperationLogKeyPrefix = "bucket"
@@ -0,0 +1,249 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketSchemaService = (*Service)(nil)
+
+func newMeasurementSchemaStore() *IndexStore {
+	const resource = "measurement schema"
+
+	var decodeSchemaEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var m influxdb.MeasurementSchema
+		return key, &m, json.Unmarshal(val, &m)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, i interface{}) (Entity, error) {
+		m, ok := i.(*influxdb.MeasurementSchema)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return measurementSchemaEntity(m), nil
+	}
+
+	return &IndexStore{
+		Resource:   resource,
+		EntStore:   NewStoreBase(resource, []byte("measurementschemasv1"), EncIDKey, EncBodyJSON, decodeSchemaEntFn, decValToEntFn),
+		IndexStore: NewOrgNameKeyStore(resource, []byte("measurementschemasindexv1"), true),
+	}
+}
+
+// measurementSchemaEntity returns the entity of a measurement schema, which
+// is unique by name within its bucket.
+func measurementSchemaEntity(m *influxdb.MeasurementSchema) Entity {
+	return Entity{
+		PK:        EncID(m.ID),
+		UniqueKey: Encode(EncID(m.BucketID), EncString(m.Name)),
+		Body:      m,
+	}
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (s *Service) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var m *influxdb.MeasurementSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		schema, err := s.findMeasurementSchemaByID(ctx, tx, bucketID, id)
+		if err != nil {
+			return err
+		}
+		m = schema
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindMeasurementSchemaByID,
+			Err: err,
+		}
+	}
+	return m, nil
+}
+
+func (s *Service) findMeasurementSchemaByID(ctx context.Context, tx Tx, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	body, err := s.measurementSchemaStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return nil, err
+	}
+
+	m, ok := body.(*influxdb.MeasurementSchema)
+	if err != nil || !ok || m.BucketID != bucketID {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "measurement schema not found",
+		}
+	}
+	return m, nil
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+func (s *Service) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var ms []*influxdb.MeasurementSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		schemas, err := s.findMeasurementSchemas(ctx, tx, filter, opt...)
+		if err != nil {
+			return err
+		}
+		ms = schemas
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindMeasurementSchemas,
+			Err: err,
+		}
+	}
+	return ms, len(ms), nil
+}
+
+func (s *Service) findMeasurementSchemas(ctx context.Context, tx Tx, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, error) {
+	if filter.Name != nil {
+		body, err := s.measurementSchemaStore.FindEnt(ctx, tx, Entity{
+			UniqueKey: Encode(EncID(filter.BucketID), EncString(*filter.Name)),
+		})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.MeasurementSchema{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		m, ok := body.(*influxdb.MeasurementSchema)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return nil, err
+		}
+		return []*influxdb.MeasurementSchema{m}, nil
+	}
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	ms := make([]*influxdb.MeasurementSchema, 0)
+	err := s.measurementSchemaStore.Find(ctx, tx, FindOpts{
+		Descending: o.Descending,
+		Offset:     o.Offset,
+		Limit:      o.Limit,
+		FilterEntFn: func(k []byte, v interface{}) bool {
+			m, ok := v.(*influxdb.MeasurementSchema)
+			return ok && m.BucketID == filter.BucketID
+		},
+		CaptureFn: func(k []byte, v interface{}) error {
+			m, ok := v.(*influxdb.MeasurementSchema)
+			if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+				return err
+			}
+			ms = append(ms, m)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// CreateMeasurementSchema creates a new measurement schema and sets m.ID with the new identifier.
+// The bucket of the schema must have an explicit schema type.
+func (s *Service) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+
+		b, err := s.findBucketByID(ctx, tx, m.BucketID)
+		if err != nil {
+			return err
+		}
+		if b.SchemaType != influxdb.SchemaTypeExplicit {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("bucket %q does not have an explicit schema", b.Name),
+			}
+		}
+
+		m.ID = s.IDGenerator.ID()
+		m.OrgID = b.OrgID
+		now := s.Now()
+		m.CreatedAt = now
+		m.UpdatedAt = now
+		return s.measurementSchemaStore.Put(ctx, tx, measurementSchemaEntity(m), PutNew())
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpCreateMeasurementSchema,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateMeasurementSchema updates the columns of a measurement schema.
+func (s *Service) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var m *influxdb.MeasurementSchema
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		schema, err := s.findMeasurementSchemaByID(ctx, tx, bucketID, id)
+		if err != nil {
+			return err
+		}
+		if err := upd.Apply(schema); err != nil {
+			return err
+		}
+		schema.UpdatedAt = s.Now()
+		m = schema
+		return s.measurementSchemaStore.Put(ctx, tx, measurementSchemaEntity(schema), PutUpdate())
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpUpdateMeasurementSchema,
+			Err: err,
+		}
+	}
+	return m, nil
+}
+
+// DeleteMeasurementSchema removes a measurement schema of a bucket by ID.
+func (s *Service) DeleteMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findMeasurementSchemaByID(ctx, tx, bucketID, id); err != nil {
+			return err
+		}
+		return s.measurementSchemaStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpDeleteMeasurementSchema,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// deleteBucketMeasurementSchemas removes the measurement schemas of a deleted bucket.
+func (s *Service) deleteBucketMeasurementSchemas(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	return s.measurementSchemaStore.Delete(ctx, tx, DeleteOpts{
+		FilterFn: func(k []byte, v interface{}) bool {
+			m, ok := v.(*influxdb.MeasurementSchema)
+			return ok && m.BucketID == bucketID
+		},
+	})
+}
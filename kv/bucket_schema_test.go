@@ -0,0 +1,87 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_MeasurementSchemas(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	implicit := &influxdb.Bucket{OrgID: org.ID, Name: "implicit"}
+	if err := svc.CreateBucket(ctx, implicit); err != nil {
+		t.Fatal(err)
+	}
+	explicit := &influxdb.Bucket{OrgID: org.ID, Name: "explicit", SchemaType: influxdb.SchemaTypeExplicit}
+	if err := svc.CreateBucket(ctx, explicit); err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []influxdb.MeasurementSchemaColumn{
+		{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+		{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+		{Name: "usage", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+	}
+
+	err = svc.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{BucketID: implicit.ID, Name: "cpu", Columns: columns})
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Fatalf("unexpected error code creating schema in implicit bucket: got %q want %q", got, want)
+	}
+
+	m := &influxdb.MeasurementSchema{BucketID: explicit.ID, Name: "cpu", Columns: columns}
+	if err := svc.CreateMeasurementSchema(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.OrgID != org.ID {
+		t.Errorf("unexpected org ID: got %s want %s", m.OrgID, org.ID)
+	}
+
+	err = svc.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{BucketID: explicit.ID, Name: "cpu", Columns: columns})
+	if got, want := influxdb.ErrorCode(err), influxdb.EConflict; got != want {
+		t.Fatalf("unexpected error code creating duplicate schema: got %q want %q", got, want)
+	}
+
+	updated, err := svc.UpdateMeasurementSchema(ctx, explicit.ID, m.ID, influxdb.MeasurementSchemaUpdate{
+		Columns: append(columns, influxdb.MeasurementSchemaColumn{Name: "idle", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(updated.Columns), 4; got != want {
+		t.Errorf("unexpected column count: got %d want %d", got, want)
+	}
+
+	name := "cpu"
+	ms, n, err := svc.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: explicit.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || ms[0].ID != m.ID {
+		t.Fatalf("unexpected measurement schemas: %v", ms)
+	}
+
+	if err := svc.DeleteBucket(ctx, explicit.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindMeasurementSchemaByID(ctx, explicit.ID, m.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected measurement schema to be deleted with its bucket, got %v", err)
+	}
+}
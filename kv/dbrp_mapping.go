@@ -0,0 +1,237 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	dbrpMappingBucket = []byte("dbrpmappingsv1")
+
+	errDBRPMappingNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "dbrp mapping not found",
+	}
+)
+
+var _ influxdb.DBRPMappingService = (*Service)(nil)
+
+func (s *Service) initializeDBRPMappings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dbrpMappingBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// dbrpMappingKey encodes the cluster, database and retention policy of a mapping.
+// Valid names cannot contain a '/', so the key is unique for each mapping.
+func dbrpMappingKey(cluster, db, rp string) []byte {
+	return []byte(strings.Join([]string{cluster, db, rp}, "/"))
+}
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *Service) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	var m *influxdb.DBRPMapping
+	err := s.kv.View(ctx, func(tx Tx) error {
+		dbrp, err := s.findDBRPMapping(ctx, tx, cluster, db, rp)
+		if err != nil {
+			return err
+		}
+		m = dbrp
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return m, nil
+}
+
+func (s *Service) findDBRPMapping(ctx context.Context, tx Tx, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(dbrpMappingKey(cluster, db, rp))
+	if IsNotFound(err) {
+		return nil, errDBRPMappingNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m influxdb.DBRPMapping
+	if err := json.Unmarshal(v, &m); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return &m, nil
+}
+
+// Find returns the first dbrp mapping that matches filter.
+func (s *Service) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	if filter.Cluster == nil && filter.Database == nil && filter.RetentionPolicy == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "no filter parameters provided",
+		}
+	}
+
+	mappings, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 1 {
+		return nil, errDBRPMappingNotFound
+	}
+
+	return mappings[0], nil
+}
+
+// FindMany returns a list of dbrp mappings that match filter and the total count of matching dbrp mappings.
+func (s *Service) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	// a fully specified key is a single lookup.
+	if filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
+		m, err := s.FindBy(ctx, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
+		if err != nil {
+			return nil, 0, err
+		}
+		if filter.Default != nil && *filter.Default != m.Default {
+			return []*influxdb.DBRPMapping{}, 0, nil
+		}
+		return []*influxdb.DBRPMapping{m}, 1, nil
+	}
+
+	ms := []*influxdb.DBRPMapping{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.forEachDBRPMapping(ctx, tx, func(m *influxdb.DBRPMapping) bool {
+			if (filter.Cluster == nil || *filter.Cluster == m.Cluster) &&
+				(filter.Database == nil || *filter.Database == m.Database) &&
+				(filter.RetentionPolicy == nil || *filter.RetentionPolicy == m.RetentionPolicy) &&
+				(filter.Default == nil || *filter.Default == m.Default) {
+				ms = append(ms, m)
+			}
+			return true
+		})
+	})
+
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return ms, len(ms), nil
+}
+
+// forEachDBRPMapping will iterate through all dbrp mappings while fn returns true.
+func (s *Service) forEachDBRPMapping(ctx context.Context, tx Tx, fn func(*influxdb.DBRPMapping) bool) error {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		m := &influxdb.DBRPMapping{}
+		if err := json.Unmarshal(v, m); err != nil {
+			return err
+		}
+		if !fn(m) {
+			break
+		}
+	}
+
+	return cur.Err()
+}
+
+// Create creates a new dbrp mapping. Creating a mapping identical to an existing
+// one is not an error; if a different mapping exists for the same key a conflict
+// is returned.
+func (s *Service) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		existing, err := s.findDBRPMapping(ctx, tx, m.Cluster, m.Database, m.RetentionPolicy)
+		if err == nil {
+			if !existing.Equal(m) {
+				return &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "dbrp mapping already exists",
+				}
+			}
+			return nil
+		}
+
+		if influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+
+		return s.putDBRPMapping(ctx, tx, m)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) putDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMapping) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(dbrpMappingKey(m.Cluster, m.Database, m.RetentionPolicy), v)
+}
+
+// Delete removes a dbrp mapping. Deleting a mapping that does not exist is not an error.
+func (s *Service) Delete(ctx context.Context, cluster, db, rp string) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(dbrpMappingBucket)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Delete(dbrpMappingKey(cluster, db, rp)); err != nil && !IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
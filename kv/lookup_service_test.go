@@ -13,7 +13,8 @@ import (
 )
 
 var (
-	existingBucketID = platform.ID(mock.FirstMockID + 3)
+	// the organization and its _tasks, _monitoring and _queries buckets take the first ids.
+	existingBucketID = platform.ID(mock.FirstMockID + 4)
 	firstMockID      = platform.ID(mock.FirstMockID)
 	nonexistantID    = platform.ID(10001)
 )
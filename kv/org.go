@@ -468,6 +468,14 @@ func (s *Service) updateOrganization(ctx context.Context, tx Tx, id influxdb.ID,
 		o.Description = *upd.Description
 	}
 
+	if upd.QueryQuotas != nil {
+		if err := upd.QueryQuotas.Validate(); err != nil {
+			return nil, err
+		}
+		quotas := *upd.QueryQuotas
+		o.QueryQuotas = &quotas
+	}
+
 	o.UpdatedAt = s.Now()
 
 	if err := s.appendOrganizationEventToLog(ctx, tx, o.ID, organizationUpdatedEvent); err != nil {
@@ -718,6 +726,12 @@ func (s *Service) FindResourceOrganizationID(ctx context.Context, rt influxdb.Re
 			return influxdb.InvalidID(), err
 		}
 		return r.OrgID, nil
+	case influxdb.ScriptsResourceType:
+		r, err := s.FindScriptByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
 	case influxdb.VariablesResourceType:
 		r, err := s.FindVariableByID(ctx, id)
 		if err != nil {
@@ -0,0 +1,274 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.ReplicationService = (*Service)(nil)
+
+func newReplicationStore() *IndexStore {
+	const resource = "replication"
+
+	var decodeReplicationEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var r influxdb.Replication
+		return key, &r, json.Unmarshal(val, &r)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, i interface{}) (Entity, error) {
+		r, ok := i.(*influxdb.Replication)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return replicationEntity(r), nil
+	}
+
+	return &IndexStore{
+		Resource:   resource,
+		EntStore:   NewStoreBase(resource, []byte("replicationsv1"), EncIDKey, EncBodyJSON, decodeReplicationEntFn, decValToEntFn),
+		IndexStore: NewOrgNameKeyStore(resource, []byte("replicationsindexv1"), true),
+	}
+}
+
+// replicationEntity returns the entity of a replication, which is unique by
+// name within its organization.
+func replicationEntity(r *influxdb.Replication) Entity {
+	return Entity{
+		PK:        EncID(r.ID),
+		UniqueKey: Encode(EncID(r.OrgID), EncString(r.Name)),
+		Body:      r,
+	}
+}
+
+// FindReplicationByID returns a single replication by ID.
+func (s *Service) FindReplicationByID(ctx context.Context, id influxdb.ID) (*influxdb.Replication, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Replication
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rep, err := s.findReplicationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = rep
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindReplicationByID,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+func (s *Service) findReplicationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Replication, error) {
+	body, err := s.replicationStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := body.(*influxdb.Replication)
+	if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FindReplications returns the replications that match filter.
+func (s *Service) FindReplications(ctx context.Context, filter influxdb.ReplicationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Replication, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var rs []*influxdb.Replication
+	err := s.kv.View(ctx, func(tx Tx) error {
+		reps, err := s.findReplications(ctx, tx, filter, opt...)
+		if err != nil {
+			return err
+		}
+		rs = reps
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindReplications,
+			Err: err,
+		}
+	}
+	return rs, len(rs), nil
+}
+
+func (s *Service) findReplications(ctx context.Context, tx Tx, filter influxdb.ReplicationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Replication, error) {
+	if filter.OrgID != nil && filter.Name != nil {
+		body, err := s.replicationStore.FindEnt(ctx, tx, Entity{
+			UniqueKey: Encode(EncID(*filter.OrgID), EncString(*filter.Name)),
+		})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.Replication{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		r, ok := body.(*influxdb.Replication)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return nil, err
+		}
+		if filter.LocalBucketID != nil && r.LocalBucketID != *filter.LocalBucketID {
+			return []*influxdb.Replication{}, nil
+		}
+		return []*influxdb.Replication{r}, nil
+	}
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	rs := make([]*influxdb.Replication, 0)
+	err := s.replicationStore.Find(ctx, tx, FindOpts{
+		Descending: o.Descending,
+		Offset:     o.Offset,
+		Limit:      o.Limit,
+		FilterEntFn: func(k []byte, v interface{}) bool {
+			r, ok := v.(*influxdb.Replication)
+			if !ok {
+				return false
+			}
+			if filter.OrgID != nil && r.OrgID != *filter.OrgID {
+				return false
+			}
+			if filter.Name != nil && r.Name != *filter.Name {
+				return false
+			}
+			return filter.LocalBucketID == nil || r.LocalBucketID == *filter.LocalBucketID
+		},
+		CaptureFn: func(k []byte, v interface{}) error {
+			r, ok := v.(*influxdb.Replication)
+			if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+				return err
+			}
+			rs = append(rs, r)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// CreateReplication creates a new replication and sets r.ID with the new identifier.
+// The local bucket of the replication must belong to its organization.
+func (s *Service) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if r.MaxQueueSizeBytes == 0 {
+		r.MaxQueueSizeBytes = influxdb.DefaultReplicationMaxQueueSizeBytes
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+
+		b, err := s.findBucketByID(ctx, tx, r.LocalBucketID)
+		if err != nil {
+			return err
+		}
+		if b.OrgID != r.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "replication local bucket must belong to the organization of the replication",
+			}
+		}
+
+		r.ID = s.IDGenerator.ID()
+		now := s.Now()
+		r.CreatedAt = now
+		r.UpdatedAt = now
+		return s.replicationStore.Put(ctx, tx, replicationEntity(r), PutNew())
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpCreateReplication,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateReplication updates a single replication with changeset.
+func (s *Service) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Replication
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		rep, err := s.findReplicationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		oldName := rep.Name
+		if err := upd.Apply(rep); err != nil {
+			return err
+		}
+
+		// the unique name index is keyed by the old name, which is only
+		// removed once the new name is known to be free.
+		if rep.Name != oldName {
+			_, err := s.replicationStore.FindEnt(ctx, tx, Entity{
+				UniqueKey: Encode(EncID(rep.OrgID), EncString(rep.Name)),
+			})
+			if err == nil {
+				return &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  fmt.Sprintf("replication with name %s already exists", rep.Name),
+				}
+			} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+
+			ent := Entity{
+				UniqueKey: Encode(EncID(rep.OrgID), EncString(oldName)),
+			}
+			if err := s.replicationStore.IndexStore.DeleteEnt(ctx, tx, ent); err != nil {
+				return err
+			}
+		}
+
+		rep.UpdatedAt = s.Now()
+		r = rep
+		return s.replicationStore.Put(ctx, tx, replicationEntity(rep), PutUpdate())
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpUpdateReplication,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// DeleteReplication removes a replication by ID.
+func (s *Service) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.replicationStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpDeleteReplication,
+			Err: err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Replications(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	other := &influxdb.Organization{Name: "other"}
+	if err := svc.CreateOrganization(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	newReplication := func(orgID influxdb.ID, name string) *influxdb.Replication {
+		return &influxdb.Replication{
+			OrgID:          orgID,
+			Name:           name,
+			LocalBucketID:  bucket.ID,
+			RemoteURL:      "https://cloud.example.com",
+			RemoteToken:    "token",
+			RemoteOrgID:    1,
+			RemoteBucketID: 2,
+		}
+	}
+
+	err = svc.CreateReplication(ctx, newReplication(other.ID, "edge"))
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Fatalf("unexpected error code replicating a bucket of another org: got %q want %q", got, want)
+	}
+
+	r := newReplication(org.ID, "edge")
+	if err := svc.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.ID.Valid() {
+		t.Fatalf("expected replication to have an ID")
+	}
+	if r.MaxQueueSizeBytes != influxdb.DefaultReplicationMaxQueueSizeBytes {
+		t.Errorf("unexpected max queue size: got %d want %d", r.MaxQueueSizeBytes, influxdb.DefaultReplicationMaxQueueSizeBytes)
+	}
+
+	err = svc.CreateReplication(ctx, newReplication(org.ID, "edge"))
+	if got, want := influxdb.ErrorCode(err), influxdb.EConflict; got != want {
+		t.Fatalf("unexpected error code creating duplicate replication: got %q want %q", got, want)
+	}
+
+	second := newReplication(org.ID, "backup")
+	if err := svc.CreateReplication(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+
+	name, url := "primary", "https://other.example.com"
+	updated, err := svc.UpdateReplication(ctx, r.ID, influxdb.ReplicationUpdate{Name: &name, RemoteURL: &url})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != name || updated.RemoteURL != url || updated.RemoteToken != "token" {
+		t.Errorf("unexpected updated replication: %+v", updated)
+	}
+
+	// the old name is free once the replication is renamed.
+	if err := svc.CreateReplication(ctx, newReplication(org.ID, "edge")); err != nil {
+		t.Fatalf("unexpected error reusing name of renamed replication: %v", err)
+	}
+
+	_, err = svc.UpdateReplication(ctx, r.ID, influxdb.ReplicationUpdate{Name: &second.Name})
+	if got, want := influxdb.ErrorCode(err), influxdb.EConflict; got != want {
+		t.Fatalf("unexpected error code renaming to existing name: got %q want %q", got, want)
+	}
+
+	rs, n, err := svc.FindReplications(ctx, influxdb.ReplicationFilter{OrgID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || rs[0].ID != r.ID {
+		t.Fatalf("unexpected replications: %v", rs)
+	}
+
+	rs, n, err = svc.FindReplications(ctx, influxdb.ReplicationFilter{LocalBucketID: &bucket.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("unexpected replications of bucket: %v", rs)
+	}
+
+	if err := svc.DeleteReplication(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindReplicationByID(ctx, r.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected replication to be deleted, got %v", err)
+	}
+	rs, _, err = svc.FindReplications(ctx, influxdb.ReplicationFilter{OrgID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 0 {
+		t.Errorf("expected no replications named %q, got %v", name, rs)
+	}
+}
@@ -0,0 +1,256 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.ScriptService = (*Service)(nil)
+
+func newScriptStore() *IndexStore {
+	const resource = "script"
+
+	var decodeScriptEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var s influxdb.Script
+		return key, &s, json.Unmarshal(val, &s)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, i interface{}) (Entity, error) {
+		s, ok := i.(*influxdb.Script)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return scriptEntity(s), nil
+	}
+
+	return &IndexStore{
+		Resource:   resource,
+		EntStore:   NewStoreBase(resource, []byte("scriptsv1"), EncIDKey, EncBodyJSON, decodeScriptEntFn, decValToEntFn),
+		IndexStore: NewOrgNameKeyStore(resource, []byte("scriptsindexv1"), true),
+	}
+}
+
+// scriptEntity returns the entity of a script, which is unique by name
+// within its organization.
+func scriptEntity(s *influxdb.Script) Entity {
+	return Entity{
+		PK:        EncID(s.ID),
+		UniqueKey: Encode(EncID(s.OrgID), EncString(s.Name)),
+		Body:      s,
+	}
+}
+
+// FindScriptByID returns a single script by ID.
+func (s *Service) FindScriptByID(ctx context.Context, id influxdb.ID) (*influxdb.Script, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var script *influxdb.Script
+	err := s.kv.View(ctx, func(tx Tx) error {
+		sc, err := s.findScriptByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		script = sc
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindScriptByID,
+			Err: err,
+		}
+	}
+	return script, nil
+}
+
+func (s *Service) findScriptByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Script, error) {
+	body, err := s.scriptStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if err != nil {
+		return nil, err
+	}
+
+	script, ok := body.(*influxdb.Script)
+	if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+// FindScripts returns the scripts that match filter.
+func (s *Service) FindScripts(ctx context.Context, filter influxdb.ScriptFilter, opt ...influxdb.FindOptions) ([]*influxdb.Script, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var scripts []*influxdb.Script
+	err := s.kv.View(ctx, func(tx Tx) error {
+		ss, err := s.findScripts(ctx, tx, filter, opt...)
+		if err != nil {
+			return err
+		}
+		scripts = ss
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpFindScripts,
+			Err: err,
+		}
+	}
+	return scripts, len(scripts), nil
+}
+
+func (s *Service) findScripts(ctx context.Context, tx Tx, filter influxdb.ScriptFilter, opt ...influxdb.FindOptions) ([]*influxdb.Script, error) {
+	if filter.OrgID != nil && filter.Name != nil {
+		body, err := s.scriptStore.FindEnt(ctx, tx, Entity{
+			UniqueKey: Encode(EncID(*filter.OrgID), EncString(*filter.Name)),
+		})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.Script{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		script, ok := body.(*influxdb.Script)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return nil, err
+		}
+		return []*influxdb.Script{script}, nil
+	}
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	scripts := make([]*influxdb.Script, 0)
+	err := s.scriptStore.Find(ctx, tx, FindOpts{
+		Descending: o.Descending,
+		Offset:     o.Offset,
+		Limit:      o.Limit,
+		FilterEntFn: func(k []byte, v interface{}) bool {
+			script, ok := v.(*influxdb.Script)
+			if !ok {
+				return false
+			}
+			if filter.OrgID != nil && script.OrgID != *filter.OrgID {
+				return false
+			}
+			return filter.Name == nil || script.Name == *filter.Name
+		},
+		CaptureFn: func(k []byte, v interface{}) error {
+			script, ok := v.(*influxdb.Script)
+			if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+				return err
+			}
+			scripts = append(scripts, script)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scripts, nil
+}
+
+// CreateScript creates a new script and sets sc.ID with the new identifier.
+func (s *Service) CreateScript(ctx context.Context, sc *influxdb.Script) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := sc.Validate(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, sc.OrgID); err != nil {
+			return err
+		}
+
+		sc.ID = s.IDGenerator.ID()
+		now := s.Now()
+		sc.CreatedAt = now
+		sc.UpdatedAt = now
+		return s.scriptStore.Put(ctx, tx, scriptEntity(sc), PutNew())
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpCreateScript,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateScript updates a single script with changeset.
+func (s *Service) UpdateScript(ctx context.Context, id influxdb.ID, upd influxdb.ScriptUpdate) (*influxdb.Script, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var script *influxdb.Script
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		sc, err := s.findScriptByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		oldName := sc.Name
+		if err := upd.Apply(sc); err != nil {
+			return err
+		}
+
+		// the unique name index is keyed by the old name, which is only
+		// removed once the new name is known to be free.
+		if sc.Name != oldName {
+			_, err := s.scriptStore.FindEnt(ctx, tx, Entity{
+				UniqueKey: Encode(EncID(sc.OrgID), EncString(sc.Name)),
+			})
+			if err == nil {
+				return &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  fmt.Sprintf("script with name %s already exists", sc.Name),
+				}
+			} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+
+			ent := Entity{
+				UniqueKey: Encode(EncID(sc.OrgID), EncString(oldName)),
+			}
+			if err := s.scriptStore.IndexStore.DeleteEnt(ctx, tx, ent); err != nil {
+				return err
+			}
+		}
+
+		sc.UpdatedAt = s.Now()
+		script = sc
+		return s.scriptStore.Put(ctx, tx, scriptEntity(sc), PutUpdate())
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpUpdateScript,
+			Err: err,
+		}
+	}
+	return script, nil
+}
+
+// DeleteScript removes a script by ID.
+func (s *Service) DeleteScript(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.scriptStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  OpPrefix + influxdb.OpDeleteScript,
+			Err: err,
+		}
+	}
+	return nil
+}
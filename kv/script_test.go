@@ -0,0 +1,118 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Scripts(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	other := &influxdb.Organization{Name: "other"}
+	if err := svc.CreateOrganization(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	newScript := func(orgID influxdb.ID, name string) *influxdb.Script {
+		return &influxdb.Script{
+			OrgID:  orgID,
+			Name:   name,
+			Script: `from(bucket: params.bucket) |> range(start: -1h)`,
+		}
+	}
+
+	err = svc.CreateScript(ctx, &influxdb.Script{OrgID: org.ID, Name: "empty"})
+	if got, want := influxdb.ErrorCode(err), influxdb.EInvalid; got != want {
+		t.Fatalf("unexpected error code creating an empty script: got %q want %q", got, want)
+	}
+
+	s := newScript(org.ID, "cpu")
+	if err := svc.CreateScript(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.ID.Valid() {
+		t.Fatalf("expected script to have an ID")
+	}
+
+	err = svc.CreateScript(ctx, newScript(org.ID, "cpu"))
+	if got, want := influxdb.ErrorCode(err), influxdb.EConflict; got != want {
+		t.Fatalf("unexpected error code creating duplicate script: got %q want %q", got, want)
+	}
+
+	// names are only unique within an organization.
+	if err := svc.CreateScript(ctx, newScript(other.ID, "cpu")); err != nil {
+		t.Fatalf("unexpected error creating script in another org: %v", err)
+	}
+
+	second := newScript(org.ID, "mem")
+	if err := svc.CreateScript(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+
+	name, script := "cpu_usage", `from(bucket: params.bucket) |> range(start: params.start)`
+	updated, err := svc.UpdateScript(ctx, s.ID, influxdb.ScriptUpdate{Name: &name, Script: &script})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != name || updated.Script != script {
+		t.Errorf("unexpected updated script: %+v", updated)
+	}
+
+	// the old name is free once the script is renamed.
+	if err := svc.CreateScript(ctx, newScript(org.ID, "cpu")); err != nil {
+		t.Fatalf("unexpected error reusing name of renamed script: %v", err)
+	}
+
+	_, err = svc.UpdateScript(ctx, s.ID, influxdb.ScriptUpdate{Name: &second.Name})
+	if got, want := influxdb.ErrorCode(err), influxdb.EConflict; got != want {
+		t.Fatalf("unexpected error code renaming to existing name: got %q want %q", got, want)
+	}
+
+	ss, n, err := svc.FindScripts(ctx, influxdb.ScriptFilter{OrgID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || ss[0].ID != s.ID {
+		t.Fatalf("unexpected scripts: %v", ss)
+	}
+
+	ss, n, err = svc.FindScripts(ctx, influxdb.ScriptFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("unexpected scripts of org: %v", ss)
+	}
+
+	if err := svc.DeleteScript(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindScriptByID(ctx, s.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected script to be deleted, got %v", err)
+	}
+	ss, _, err = svc.FindScripts(ctx, influxdb.ScriptFilter{OrgID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss) != 0 {
+		t.Errorf("expected no scripts named %q, got %v", name, ss)
+	}
+}
@@ -41,9 +41,12 @@ type Service struct {
 	influxdb.TimeGenerator
 	Hash Crypt
 
-	checkStore    *IndexStore
-	endpointStore *IndexStore
-	variableStore *IndexStore
+	checkStore             *IndexStore
+	endpointStore          *IndexStore
+	variableStore          *IndexStore
+	measurementSchemaStore *IndexStore
+	replicationStore       *IndexStore
+	scriptStore            *IndexStore
 }
 
 // NewService returns an instance of a Service.
@@ -61,6 +64,10 @@ func NewService(log *zap.Logger, kv Store, configs ...ServiceConfig) *Service {
 		checkStore:     newCheckStore(),
 		endpointStore:  newEndpointStore(),
 		variableStore:  newVariableStore(),
+
+		measurementSchemaStore: newMeasurementSchemaStore(),
+		replicationStore:       newReplicationStore(),
+		scriptStore:            newScriptStore(),
 	}
 
 	if len(configs) > 0 {
@@ -94,6 +101,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeBackupManifests(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeBuckets(ctx, tx); err != nil {
 			return err
 		}
@@ -102,6 +113,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeDBRPMappings(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeKVLog(ctx, tx); err != nil {
 			return err
 		}
@@ -171,6 +186,18 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.measurementSchemaStore.Init(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.replicationStore.Init(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.scriptStore.Init(ctx, tx); err != nil {
+			return err
+		}
+
 		return s.initializeUsers(ctx, tx)
 	})
 }
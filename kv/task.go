@@ -56,6 +56,7 @@ type kvTask struct {
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	Params          json.RawMessage        `json:"params,omitempty"`
 }
 
 func kvToInfluxTask(k *kvTask) *influxdb.Task {
@@ -79,6 +80,7 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		CreatedAt:       k.CreatedAt,
 		UpdatedAt:       k.UpdatedAt,
 		Metadata:        k.Metadata,
+		Params:          k.Params,
 	}
 }
 
@@ -571,6 +573,7 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		Organization:    org.Name,
 		OwnerID:         tc.OwnerID,
 		Metadata:        tc.Metadata,
+		Params:          tc.Params,
 		Name:            opt.Name,
 		Description:     tc.Description,
 		Status:          tc.Status,
@@ -749,6 +752,11 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.UpdatedAt = updatedAt
 	}
 
+	if upd.Params != nil {
+		task.Params = upd.Params
+		task.UpdatedAt = updatedAt
+	}
+
 	if upd.LatestCompleted != nil {
 		// make sure we only update latest completed one way
 		tlc := task.LatestCompleted
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService is a mock implementation of platform.BucketSchemaService.
+type BucketSchemaService struct {
+	FindMeasurementSchemaByIDFn func(context.Context, platform.ID, platform.ID) (*platform.MeasurementSchema, error)
+	FindMeasurementSchemasFn    func(context.Context, platform.MeasurementSchemaFilter, ...platform.FindOptions) ([]*platform.MeasurementSchema, int, error)
+	CreateMeasurementSchemaFn   func(context.Context, *platform.MeasurementSchema) error
+	UpdateMeasurementSchemaFn   func(context.Context, platform.ID, platform.ID, platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error)
+	DeleteMeasurementSchemaFn   func(context.Context, platform.ID, platform.ID) error
+}
+
+// NewBucketSchemaService returns a mock BucketSchemaService where its methods
+// will return zero values.
+func NewBucketSchemaService() *BucketSchemaService {
+	return &BucketSchemaService{
+		FindMeasurementSchemaByIDFn: func(context.Context, platform.ID, platform.ID) (*platform.MeasurementSchema, error) { return nil, nil },
+		FindMeasurementSchemasFn: func(context.Context, platform.MeasurementSchemaFilter, ...platform.FindOptions) ([]*platform.MeasurementSchema, int, error) {
+			return nil, 0, nil
+		},
+		CreateMeasurementSchemaFn: func(context.Context, *platform.MeasurementSchema) error { return nil },
+		UpdateMeasurementSchemaFn: func(context.Context, platform.ID, platform.ID, platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+		DeleteMeasurementSchemaFn: func(context.Context, platform.ID, platform.ID) error { return nil },
+	}
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema by ID.
+func (s *BucketSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id platform.ID) (*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemaByIDFn(ctx, bucketID, id)
+}
+
+// FindMeasurementSchemas returns the measurement schemas that match filter.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, filter platform.MeasurementSchemaFilter, opts ...platform.FindOptions) ([]*platform.MeasurementSchema, int, error) {
+	return s.FindMeasurementSchemasFn(ctx, filter, opts...)
+}
+
+// CreateMeasurementSchema creates a new measurement schema.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, m *platform.MeasurementSchema) error {
+	return s.CreateMeasurementSchemaFn(ctx, m)
+}
+
+// UpdateMeasurementSchema updates the columns of a measurement schema.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id platform.ID, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+	return s.UpdateMeasurementSchemaFn(ctx, bucketID, id, upd)
+}
+
+// DeleteMeasurementSchema removes a measurement schema by ID.
+func (s *BucketSchemaService) DeleteMeasurementSchema(ctx context.Context, bucketID, id platform.ID) error {
+	return s.DeleteMeasurementSchemaFn(ctx, bucketID, id)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ExportService = (*ExportService)(nil)
+
+// ExportService is a mock implementation of influxdb.ExportService.
+type ExportService struct {
+	ExportLineProtocolFn func(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error
+}
+
+// NewExportService returns a mock ExportService where its methods
+// will return zero values.
+func NewExportService() *ExportService {
+	return &ExportService{
+		ExportLineProtocolFn: func(context.Context, influxdb.ExportRequest, io.Writer) error {
+			return nil
+		},
+	}
+}
+
+// ExportLineProtocol writes the points of a bucket as line protocol.
+func (s *ExportService) ExportLineProtocol(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+	return s.ExportLineProtocolFn(ctx, req, w)
+}
@@ -0,0 +1,60 @@
+package mock
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+var _ influxdb.KVRestoreService = (*KVRestoreService)(nil)
+
+// RestoreService is a mock implementation of influxdb.RestoreService.
+type RestoreService struct {
+	RestoreBucketFn func(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error)
+	RestoreEngineFn func(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error
+}
+
+// NewRestoreService returns a mock RestoreService where its methods
+// will return zero values.
+func NewRestoreService() *RestoreService {
+	return &RestoreService{
+		RestoreBucketFn: func(context.Context, influxdb.RestoreBucketRequest, io.Reader, io.Reader) (int, error) {
+			return 0, nil
+		},
+		RestoreEngineFn: func(context.Context, []*influxdb.BackupManifest, map[string]io.Reader) error {
+			return nil
+		},
+	}
+}
+
+// RestoreBucket restores the data of a bucket of a backup.
+func (s *RestoreService) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error) {
+	return s.RestoreBucketFn(ctx, req, tsm, tombstone)
+}
+
+// RestoreEngine restores the engine files of a chain of backups.
+func (s *RestoreService) RestoreEngine(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error {
+	return s.RestoreEngineFn(ctx, chain, files)
+}
+
+// KVRestoreService is a mock implementation of influxdb.KVRestoreService.
+type KVRestoreService struct {
+	RestoreKVStoreFn func(ctx context.Context, chain []*influxdb.BackupManifest, kv io.Reader, changes []io.Reader) error
+}
+
+// NewKVRestoreService returns a mock KVRestoreService where its methods
+// will return zero values.
+func NewKVRestoreService() *KVRestoreService {
+	return &KVRestoreService{
+		RestoreKVStoreFn: func(context.Context, []*influxdb.BackupManifest, io.Reader, []io.Reader) error {
+			return nil
+		},
+	}
+}
+
+// RestoreKVStore restores the meta data files of a chain of backups.
+func (s *KVRestoreService) RestoreKVStore(ctx context.Context, chain []*influxdb.BackupManifest, kv io.Reader, changes []io.Reader) error {
+	return s.RestoreKVStoreFn(ctx, chain, kv, changes)
+}
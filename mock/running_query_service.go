@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RunningQueryService = (*RunningQueryService)(nil)
+
+// RunningQueryService is a mock implementation of platform.RunningQueryService.
+type RunningQueryService struct {
+	FindRunningQueryByIDFn func(context.Context, platform.ID) (*platform.RunningQuery, error)
+	FindRunningQueriesFn   func(context.Context, platform.RunningQueryFilter) ([]*platform.RunningQuery, error)
+	CancelRunningQueryFn   func(context.Context, platform.ID) error
+}
+
+// NewRunningQueryService returns a mock RunningQueryService where its methods
+// will return zero values.
+func NewRunningQueryService() *RunningQueryService {
+	return &RunningQueryService{
+		FindRunningQueryByIDFn: func(context.Context, platform.ID) (*platform.RunningQuery, error) { return nil, nil },
+		FindRunningQueriesFn: func(context.Context, platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+			return nil, nil
+		},
+		CancelRunningQueryFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	return s.FindRunningQueryByIDFn(ctx, id)
+}
+
+// FindRunningQueries returns the running queries that match filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	return s.FindRunningQueriesFn(ctx, filter)
+}
+
+// CancelRunningQuery stops the execution of a running query.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id platform.ID) error {
+	return s.CancelRunningQueryFn(ctx, id)
+}
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.ScriptService = (*ScriptService)(nil)
+
+// ScriptService is a mock implementation of platform.ScriptService.
+type ScriptService struct {
+	FindScriptByIDFn func(context.Context, platform.ID) (*platform.Script, error)
+	FindScriptsFn    func(context.Context, platform.ScriptFilter, ...platform.FindOptions) ([]*platform.Script, int, error)
+	CreateScriptFn   func(context.Context, *platform.Script) error
+	UpdateScriptFn   func(context.Context, platform.ID, platform.ScriptUpdate) (*platform.Script, error)
+	DeleteScriptFn   func(context.Context, platform.ID) error
+}
+
+// NewScriptService returns a mock ScriptService where its methods
+// will return zero values.
+func NewScriptService() *ScriptService {
+	return &ScriptService{
+		FindScriptByIDFn: func(context.Context, platform.ID) (*platform.Script, error) { return nil, nil },
+		FindScriptsFn: func(context.Context, platform.ScriptFilter, ...platform.FindOptions) ([]*platform.Script, int, error) {
+			return nil, 0, nil
+		},
+		CreateScriptFn: func(context.Context, *platform.Script) error { return nil },
+		UpdateScriptFn: func(context.Context, platform.ID, platform.ScriptUpdate) (*platform.Script, error) {
+			return nil, nil
+		},
+		DeleteScriptFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindScriptByID returns a single script by ID.
+func (s *ScriptService) FindScriptByID(ctx context.Context, id platform.ID) (*platform.Script, error) {
+	return s.FindScriptByIDFn(ctx, id)
+}
+
+// FindScripts returns the scripts that match filter.
+func (s *ScriptService) FindScripts(ctx context.Context, filter platform.ScriptFilter, opts ...platform.FindOptions) ([]*platform.Script, int, error) {
+	return s.FindScriptsFn(ctx, filter, opts...)
+}
+
+// CreateScript creates a new script.
+func (s *ScriptService) CreateScript(ctx context.Context, sc *platform.Script) error {
+	return s.CreateScriptFn(ctx, sc)
+}
+
+// UpdateScript updates a script.
+func (s *ScriptService) UpdateScript(ctx context.Context, id platform.ID, upd platform.ScriptUpdate) (*platform.Script, error) {
+	return s.UpdateScriptFn(ctx, id, upd)
+}
+
+// DeleteScript removes a script by ID.
+func (s *ScriptService) DeleteScript(ctx context.Context, id platform.ID) error {
+	return s.DeleteScriptFn(ctx, id)
+}
@@ -376,6 +376,26 @@ func ParsePointsWithOptions(buf []byte, mm []byte, opts ...ParserOption) (_ []Po
 	return pp.points, err
 }
 
+// PointsParser parses line protocol which is read in several buffers, such as
+// a request body which is parsed as it is read. The limits set by its options
+// apply to all of the buffers it parses, as if they were a single buffer.
+type PointsParser struct {
+	pp *pointsParser
+}
+
+// NewPointsParser returns a parser of the points of the measurement mm.
+func NewPointsParser(mm []byte, opts ...ParserOption) *PointsParser {
+	return &PointsParser{pp: newPointsParser(mm, opts...)}
+}
+
+// ParsePoints parses the points of buf, which must end on a line boundary.
+// The points refer to buf, which must not be modified while they are in use.
+func (p *PointsParser) ParsePoints(buf []byte) ([]Point, error) {
+	p.pp.points = nil
+	err := p.pp.parsePoints(buf)
+	return p.pp.points, err
+}
+
 // ParsePointsWithPrecision is similar to ParsePoints, but allows the
 // caller to provide a precision for time.
 //
@@ -397,6 +417,10 @@ func GetPrecisionMultiplier(precision string) int64 {
 		d = time.Millisecond
 	case "s":
 		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
 	}
 	return int64(d)
 }
@@ -1072,6 +1096,29 @@ func scanLine(buf []byte, i int) (int, []byte) {
 	return i, buf[start:i]
 }
 
+// ScanLines is a split function for a bufio.Scanner that returns each line
+// of line protocol, including its trailing newline. Unlike bufio.ScanLines, a
+// newline within a quoted string field value does not end the line.
+func ScanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	// the byte after a newline must be available, otherwise an escaped newline
+	// at the end of data could not be told apart from one ending the line.
+	if i, _ := scanLine(data, 0); i+1 < len(data) || (atEOF && i < len(data)) {
+		return i + 1, data[:i+1], nil
+	}
+
+	// If we're at EOF, we have a final, non-terminated line. Return it.
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	// Request more data.
+	return 0, nil, nil
+}
+
 // scanTo returns the end position in buf and the next consecutive block
 // of bytes, starting from i and ending with stop byte, where stop byte
 // has not been escaped.
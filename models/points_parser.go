@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 	"unsafe"
@@ -31,6 +32,43 @@ type ParserStats struct {
 	BytesN int
 }
 
+// LineError describes a single line of the source buffer which could not be parsed.
+type LineError struct {
+	// Line is the 1-based line number within the source buffer.
+	Line int
+	// Text is the content of the line, without the trailing newline.
+	Text string
+	// Err is the reason the line was rejected.
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: unable to parse '%s': %v", e.Line, e.Text, e.Err)
+}
+
+// LineReport records the source line of every parsed point and the lines which could not be parsed.
+type LineReport struct {
+	// PointLines holds the 1-based line number from which each parsed point was read,
+	// indexed in the same order as the points returned by the parser.
+	PointLines []int
+	// Errors holds an entry for each line which failed to parse.
+	Errors []LineError
+
+	// text holds the source text of each parsed point, as a subslice of the
+	// source buffer, indexed in the same order as PointLines.
+	text [][]byte
+}
+
+// Text returns the source text of a line which produced points, or an empty
+// string if no point was read from line.
+func (r *LineReport) Text(line int) string {
+	i := sort.SearchInts(r.PointLines, line)
+	if i < len(r.PointLines) && r.PointLines[i] == line {
+		return string(r.text[i])
+	}
+	return ""
+}
+
 type ParserOption func(*pointsParser)
 
 // WithParserPrecision specifies the default precision for to use to truncate timestamps.
@@ -68,6 +106,15 @@ func WithParserMaxValues(n int) ParserOption {
 	}
 }
 
+// WithParserLineReport specifies that r will record the source line of each parsed point
+// and every line which failed to parse. When set, lines which fail to parse are excluded
+// from the result and do not cause an error to be returned; limit errors are still returned.
+func WithParserLineReport(r *LineReport) ParserOption {
+	return func(pp *pointsParser) {
+		pp.report = r
+	}
+}
+
 // WithParserStats specifies that s will contain statistics about the parsed request.
 func WithParserStats(s *ParserStats) ParserOption {
 	return func(pp *pointsParser) {
@@ -88,12 +135,15 @@ type pointsParser struct {
 	maxBytes    int
 	maxValues   int
 	bytesN      int
+	lines       int // lines of the buffers parsed before
+	values      int // values of the buffers parsed before
 	orgBucket   []byte
 	defaultTime time.Time // truncated time to assign to points which have no associated timestamp.
 	precision   string
 	points      []Point
 	state       parserState
 	stats       *ParserStats
+	report      *LineReport
 }
 
 func newPointsParser(orgBucket []byte, opts ...ParserOption) *pointsParser {
@@ -116,9 +166,10 @@ func newPointsParser(orgBucket []byte, opts ...ParserOption) *pointsParser {
 
 func (pp *pointsParser) parsePoints(buf []byte) (err error) {
 	lineCount := bytes.Count(buf, []byte{'\n'})
-	if pp.maxLines > 0 && lineCount > pp.maxLines {
+	if pp.maxLines > 0 && pp.lines+lineCount > pp.maxLines {
 		return ErrLimitMaxLinesExceeded
 	}
+	pp.lines += lineCount
 
 	if !pp.checkAlloc(lineCount+1, int(unsafe.Sizeof(Point(nil)))) {
 		return ErrLimitMaxBytesExceeded
@@ -128,6 +179,8 @@ func (pp *pointsParser) parsePoints(buf []byte) (err error) {
 
 	var (
 		pos    int
+		line   int
+		next   = 1
 		block  []byte
 		failed []string
 	)
@@ -135,6 +188,9 @@ func (pp *pointsParser) parsePoints(buf []byte) (err error) {
 		pos, block = scanLine(buf, pos)
 		pos++
 
+		// quoted string fields may span several lines
+		line, next = next, next+1+bytes.Count(block, []byte{'\n'})
+
 		if len(block) == 0 {
 			continue
 		}
@@ -156,6 +212,7 @@ func (pp *pointsParser) parsePoints(buf []byte) (err error) {
 			block = block[:len(block)-1]
 		}
 
+		n := len(pp.points)
 		err = pp.parsePointsAppend(block[start:])
 		if err != nil {
 			if errors.Is(err, errLimit) {
@@ -167,10 +224,25 @@ func (pp *pointsParser) parsePoints(buf []byte) (err error) {
 				break
 			}
 
+			if pp.report != nil {
+				// discard any points appended before the line was rejected
+				pp.points = pp.points[:n]
+				pp.report.Errors = append(pp.report.Errors, LineError{Line: line, Text: string(block[start:]), Err: err})
+				continue
+			}
+
 			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
 		}
+
+		if pp.report != nil {
+			for i := n; i < len(pp.points); i++ {
+				pp.report.PointLines = append(pp.report.PointLines, line)
+				pp.report.text = append(pp.report.text, block[start:])
+			}
+		}
 	}
 
+	pp.values += len(pp.points)
 	if pp.stats != nil {
 		pp.stats.BytesN = pp.bytesN
 	}
@@ -287,7 +359,7 @@ func (pp *pointsParser) parsePointsAppend(buf []byte) error {
 }
 
 func (pp *pointsParser) append(p point) error {
-	if pp.maxValues > 0 && len(pp.points) > pp.maxValues {
+	if pp.maxValues > 0 && pp.values+len(pp.points) > pp.maxValues {
 		pp.state = parserStateValueLimit
 		return errLimit
 	}
@@ -343,6 +415,10 @@ func truncateTimeWithPrecision(t time.Time, precision string) time.Time {
 		return t.Truncate(time.Millisecond)
 	case "s":
 		return t.Truncate(time.Second)
+	case "m":
+		return t.Truncate(time.Minute)
+	case "h":
+		return t.Truncate(time.Hour)
 	default:
 		return t
 	}
@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -2506,6 +2508,102 @@ func TestParsePointsWithOptions(t *testing.T) {
 	}
 }
 
+func TestParsePointsWithOptions_LineReport(t *testing.T) {
+	buf := []byte(`cpu,host=a value=1 100
+# comment
+cpu,host=b value= 100
+
+mem,host=a free=1i,used=oops 100
+mem,host=a msg="multi
+line" 100
+disk,host=a bad
+`)
+	encoded := tsdb.EncodeName(influxdb.ID(1000), influxdb.ID(2000))
+	mm := models.EscapeMeasurement(encoded[:])
+
+	var report models.LineReport
+	points, err := models.ParsePointsWithOptions(buf, mm, models.WithParserLineReport(&report))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, exp := len(points), 2; got != exp {
+		t.Fatalf("unexpected number of points; got %d, exp %d", got, exp)
+	}
+
+	if got, exp := report.PointLines, []int{1, 6}; !cmp.Equal(got, exp) {
+		t.Errorf("unexpected point lines; -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+
+	var lines []int
+	for _, le := range report.Errors {
+		lines = append(lines, le.Line)
+		if le.Err == nil {
+			t.Errorf("line %d: expected a reason", le.Line)
+		}
+	}
+	if exp := []int{3, 5, 8}; !cmp.Equal(lines, exp) {
+		t.Errorf("unexpected error lines; -got/+exp\n%s", cmp.Diff(lines, exp))
+	}
+
+	if got, exp := report.Errors[1].Text, "mem,host=a free=1i,used=oops 100"; got != exp {
+		t.Errorf("unexpected error text; got %q, exp %q", got, exp)
+	}
+
+	if got, exp := report.Text(6), "mem,host=a msg=\"multi\nline\" 100"; got != exp {
+		t.Errorf("unexpected point text; got %q, exp %q", got, exp)
+	}
+}
+
+func TestPointsParser(t *testing.T) {
+	encoded := tsdb.EncodeName(influxdb.ID(1000), influxdb.ID(2000))
+	mm := models.EscapeMeasurement(encoded[:])
+
+	tests := []struct {
+		name string
+		opts []models.ParserOption
+		exp  error
+	}{
+		{
+			name: "lines are limited across buffers",
+			opts: []models.ParserOption{models.WithParserMaxLines(4)},
+			exp:  models.ErrLimitMaxLinesExceeded,
+		},
+		{
+			name: "values are limited across buffers",
+			opts: []models.ParserOption{models.WithParserMaxValues(3)},
+			exp:  models.ErrLimitMaxValuesExceeded,
+		},
+		{
+			name: "buffers are not limited",
+			opts: []models.ParserOption{models.WithParserMaxLines(5), models.WithParserMaxValues(5)},
+			exp:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pp := models.NewPointsParser(mm, test.opts...)
+
+			points, err := pp.ParsePoints([]byte("cpu value=1 100\ncpu value=2 200\n"))
+			if err != nil {
+				t.Fatalf("unexpected error parsing the first buffer: %v", err)
+			}
+			if got, exp := len(points), 2; got != exp {
+				t.Fatalf("unexpected number of points; got %d, exp %d", got, exp)
+			}
+
+			points, err = pp.ParsePoints([]byte("mem free=1i 100\nmem free=2i 200\nmem free=3i 300\n"))
+			if err != test.exp {
+				t.Fatalf("unexpected error parsing the second buffer; got %v, exp %v", err, test.exp)
+			}
+			if got, exp := len(points), 3; err == nil && got != exp {
+				t.Fatalf("unexpected number of points; got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
+
 func TestNewPointsWithBytesWithCorruptData(t *testing.T) {
 	corrupted := []byte{0, 0, 0, 3, 102, 111, 111, 0, 0, 0, 4, 61, 34, 65, 34, 1, 0, 0, 0, 14, 206, 86, 119, 24, 32, 72, 233, 168, 2, 148}
 	p, err := models.NewPointFromBytes(corrupted)
@@ -2844,6 +2942,54 @@ func BenchmarkEscapeStringField_Plain(b *testing.B) {
 	}
 }
 
+func TestScanLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "lines including their newlines",
+			input: "m1 f=1\nm2 f=2\nm3 f=3",
+			want:  []string{"m1 f=1\n", "m2 f=2\n", "m3 f=3"},
+		},
+		{
+			name:  "newline within a quoted string field",
+			input: "m1 f=\"a\nb\"\nm2 f=2\n",
+			want:  []string{"m1 f=\"a\nb\"\n", "m2 f=2\n"},
+		},
+		{
+			name:  "blank lines and comments",
+			input: "# comment\n\nm1 f=1\n",
+			want:  []string{"# comment\n", "\n", "m1 f=1\n"},
+		},
+		{
+			name:  "no lines",
+			input: "",
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a reader which returns a byte at a time splits every line
+			// across several reads.
+			scanner := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(tt.input)))
+			scanner.Split(models.ScanLines)
+			got := []string{}
+			for scanner.Scan() {
+				got = append(got, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("unexpected lines -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
 func BenchmarkEscapeString_Quotes(b *testing.B) {
 	s := `Hello, "world"`
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,67 @@
+package opentsdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// Default configuration values.
+const (
+	DefaultBindAddress  = ":4242"
+	DefaultBatchSize    = 1000
+	DefaultBatchPending = 5
+	DefaultBatchTimeout = time.Second
+)
+
+// Config holds the configuration of an OpenTSDB Service.
+type Config struct {
+	// BindAddress is the address the service listens on for both the telnet
+	// and the HTTP protocol.
+	BindAddress string
+
+	// OrgID and BucketID identify the bucket points are written to.
+	OrgID    influxdb.ID
+	BucketID influxdb.ID
+
+	// BatchSize is the number of points written to storage at a time.
+	BatchSize int
+
+	// BatchPending is the number of points which are buffered before the
+	// listener stops reading.
+	BatchPending int
+
+	// BatchTimeout is the longest a point waits before it is written.
+	BatchTimeout time.Duration
+}
+
+// WithDefaults returns a copy of c in which every unset value is set to its
+// default.
+func (c Config) WithDefaults() Config {
+	d := c
+	if d.BindAddress == "" {
+		d.BindAddress = DefaultBindAddress
+	}
+	if d.BatchSize == 0 {
+		d.BatchSize = DefaultBatchSize
+	}
+	if d.BatchPending == 0 {
+		d.BatchPending = DefaultBatchPending
+	}
+	if d.BatchTimeout == 0 {
+		d.BatchTimeout = DefaultBatchTimeout
+	}
+	return d
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c Config) Validate() error {
+	if !c.OrgID.Valid() {
+		return fmt.Errorf("an organization ID is required")
+	}
+	if !c.BucketID.Valid() {
+		return fmt.Errorf("a bucket ID is required")
+	}
+	return nil
+}
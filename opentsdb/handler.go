@@ -0,0 +1,60 @@
+package opentsdb
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// maxRequestSize is the largest request body accepted by the HTTP API.
+const maxRequestSize = 32 << 20
+
+// newHandler returns the handler of the OpenTSDB HTTP API, which passes the
+// data points it receives to the service.
+func newHandler(s *Service) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/put", s.handlePut)
+	// metadata is accepted for compatibility with OpenTSDB clients, but it is
+	// not stored.
+	mux.HandleFunc("/api/metadata/put", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// handlePut handles a request to write data points, which may be gzip
+// compressed.
+func (s *Service) handlePut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			s.metrics.ParseErrors.WithLabelValues("http").Inc()
+			http.Error(w, "invalid gzip body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gr.Close()
+		body = gr
+	}
+
+	points, err := DecodeDataPoints(body)
+	if err != nil {
+		s.metrics.ParseErrors.WithLabelValues("http").Inc()
+		s.log.Debug("Unable to decode data points", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, pt := range points {
+		s.submit(pt, "http")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,135 @@
+package opentsdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// FieldName is the field the value of every data point is written to.
+const FieldName = "value"
+
+// ParsePut parses a telnet put command, which has the format
+// "put <metric> <timestamp> <value> <tagk1=tagv1 ...>". The timestamp is in
+// seconds or milliseconds since the epoch.
+func ParsePut(line string) (models.Point, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "put" {
+		return nil, fmt.Errorf("malformed put command: %q", line)
+	}
+
+	ts, err := parseTimestamp(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := strconv.ParseFloat(fields[3], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: %v", fields[3], err)
+	}
+
+	tags := make(map[string]string, len(fields)-4)
+	for _, kv := range fields[4:] {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("malformed tag %q", kv)
+		}
+		tags[parts[0]] = parts[1]
+	}
+
+	return newPoint(fields[1], tags, v, ts)
+}
+
+// parseTimestamp parses a timestamp of 10 digits as seconds and of 13 digits
+// as milliseconds since the epoch.
+func parseTimestamp(s string) (time.Time, error) {
+	ts, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %v", s, err)
+	}
+
+	switch len(s) {
+	case 10:
+		return time.Unix(ts, 0).UTC(), nil
+	case 13:
+		return time.Unix(0, ts*int64(time.Millisecond)).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid timestamp %q, must be 10 or 13 digits", s)
+	}
+}
+
+// DataPoint is a data point of the OpenTSDB HTTP API.
+type DataPoint struct {
+	Metric    string            `json:"metric"`
+	Timestamp int64             `json:"timestamp"`
+	Value     float64           `json:"value"`
+	Tags      map[string]string `json:"tags"`
+}
+
+// DecodeDataPoints decodes a single data point or an array of data points
+// sent to the /api/put endpoint.
+func DecodeDataPoints(r io.Reader) ([]models.Point, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var dps []DataPoint
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(raw, &dps); err != nil {
+			return nil, err
+		}
+	} else {
+		var dp DataPoint
+		if err := json.Unmarshal(raw, &dp); err != nil {
+			return nil, err
+		}
+		dps = append(dps, dp)
+	}
+
+	points := make([]models.Point, 0, len(dps))
+	for _, dp := range dps {
+		pt, err := dp.Point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+// Point returns the point of the data point. A timestamp less than 10^10 is
+// in seconds, and any other timestamp is in milliseconds, since the epoch.
+func (dp DataPoint) Point() (models.Point, error) {
+	if dp.Metric == "" {
+		return nil, errors.New("data point is missing the metric name")
+	}
+
+	var ts time.Time
+	if dp.Timestamp < 1e10 {
+		ts = time.Unix(dp.Timestamp, 0).UTC()
+	} else {
+		ts = time.Unix(0, dp.Timestamp*int64(time.Millisecond)).UTC()
+	}
+	return newPoint(dp.Metric, dp.Tags, dp.Value, ts)
+}
+
+// newPoint returns a point of the metric with the value written to the
+// "value" field.
+func newPoint(metric string, tags map[string]string, v float64, ts time.Time) (models.Point, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("metric %s: unsupported value %v", metric, v)
+	}
+	pt, err := models.NewPoint(metric, models.NewTags(tags), models.Fields{FieldName: v}, ts)
+	if err != nil {
+		return nil, fmt.Errorf("metric %s: %v", metric, err)
+	}
+	return pt, nil
+}
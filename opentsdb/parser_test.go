@@ -0,0 +1,103 @@
+package opentsdb_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/opentsdb"
+)
+
+func TestParsePut(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "seconds",
+			line: "put sys.cpu.user 1356998400 42.5 host=webserver01 cpu=0",
+			want: models.MustNewPoint("sys.cpu.user",
+				models.NewTags(map[string]string{"host": "webserver01", "cpu": "0"}),
+				models.Fields{"value": 42.5}, time.Unix(1356998400, 0)).String(),
+		},
+		{
+			name: "milliseconds",
+			line: "put sys.cpu.user 1356998400500 1",
+			want: models.MustNewPoint("sys.cpu.user", nil,
+				models.Fields{"value": 1.0}, time.Unix(1356998400, int64(500*time.Millisecond))).String(),
+		},
+		{name: "missing value", line: "put sys.cpu.user 1356998400", wantErr: true},
+		{name: "not a put command", line: "get sys.cpu.user 1356998400 1", wantErr: true},
+		{name: "invalid timestamp", line: "put sys.cpu.user 13569984 1", wantErr: true},
+		{name: "invalid value", line: "put sys.cpu.user 1356998400 abc", wantErr: true},
+		{name: "invalid tag", line: "put sys.cpu.user 1356998400 1 host", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt, err := opentsdb.ParsePut(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := pt.String(); got != tt.want {
+				t.Errorf("unexpected point; got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDataPoints(t *testing.T) {
+	single := models.MustNewPoint("sys.cpu.nice",
+		models.NewTags(map[string]string{"host": "web01", "dc": "lga"}),
+		models.Fields{"value": 18.0}, time.Unix(1346846400, 0)).String()
+	millis := models.MustNewPoint("sys.cpu.nice",
+		models.NewTags(map[string]string{"host": "web02"}),
+		models.Fields{"value": 9.0}, time.Unix(1346846400, int64(250*time.Millisecond))).String()
+
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "single data point",
+			body: `{"metric": "sys.cpu.nice", "timestamp": 1346846400, "value": 18, "tags": {"host": "web01", "dc": "lga"}}`,
+			want: []string{single},
+		},
+		{
+			name: "array of data points",
+			body: `[
+				{"metric": "sys.cpu.nice", "timestamp": 1346846400, "value": 18, "tags": {"host": "web01", "dc": "lga"}},
+				{"metric": "sys.cpu.nice", "timestamp": 1346846400250, "value": 9, "tags": {"host": "web02"}}
+			]`,
+			want: []string{single, millis},
+		},
+		{name: "invalid json", body: `{"metric":`, wantErr: true},
+		{name: "missing metric", body: `{"timestamp": 1346846400, "value": 18}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := opentsdb.DecodeDataPoints(strings.NewReader(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(points) != len(tt.want) {
+				t.Fatalf("unexpected number of points; got %d, want %d", len(points), len(tt.want))
+			}
+			for i, pt := range points {
+				if got := pt.String(); got != tt.want[i] {
+					t.Errorf("unexpected point %d; got %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+// Package opentsdb implements a listener for the OpenTSDB telnet and HTTP
+// protocols.
+//
+// Both protocols are served on the same address: connections which start with
+// a put command are read as telnet connections, and every other connection is
+// served by the /api/put HTTP endpoint. Every data point is written as a point
+// of the metric with its tags, and its value in the "value" field, to a single
+// bucket in batches.
+package opentsdb
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Service receives data points in the OpenTSDB telnet and HTTP protocols and
+// writes them to storage.
+type Service struct {
+	config       Config
+	pointsWriter storage.PointsWriter
+	batcher      *storage.PointBatcher
+	metrics      *metrics
+	log          *zap.Logger
+
+	mu         sync.Mutex
+	ln         net.Listener
+	httpLn     *chanListener
+	httpServer *http.Server
+	conns      map[net.Conn]struct{}
+	closed     bool
+	closing    chan struct{}
+	wg         sync.WaitGroup // listeners and connections
+	writesWg   sync.WaitGroup // batch writer
+}
+
+// NewService returns a Service which writes the data points it receives to
+// pw.
+func NewService(log *zap.Logger, pw storage.PointsWriter, c Config) (*Service, error) {
+	c = c.WithDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		config:       c,
+		pointsWriter: pw,
+		metrics:      newMetrics(),
+		log:          log,
+	}, nil
+}
+
+// Open starts listening on the bind address.
+func (s *Service) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing != nil {
+		return errors.New("opentsdb service already open")
+	}
+
+	ln, err := net.Listen("tcp", s.config.BindAddress)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	s.closed = false
+	s.conns = make(map[net.Conn]struct{})
+	s.closing = make(chan struct{})
+
+	s.batcher = storage.NewPointBatcher(s.config.BatchSize, s.config.BatchPending, s.config.BatchTimeout)
+	s.batcher.Start()
+	s.writesWg.Add(1)
+	go s.processBatches()
+
+	s.httpLn = newChanListener(ln.Addr())
+	s.httpServer = &http.Server{
+		Handler: newHandler(s),
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			if state == http.StateClosed || state == http.StateHijacked {
+				s.metrics.Connections.Dec()
+			}
+		},
+	}
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		if err := s.httpServer.Serve(s.httpLn); err != nil && err != http.ErrServerClosed {
+			s.log.Error("HTTP server stopped", zap.Error(err))
+		}
+	}()
+	go s.serve()
+
+	s.log.Info("Listening",
+		zap.Stringer("addr", ln.Addr()),
+		zap.Stringer("org_id", s.config.OrgID),
+		zap.Stringer("bucket_id", s.config.BucketID))
+	return nil
+}
+
+// Close stops the listener, closes every connection and writes the points
+// which are still buffered.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	if s.closing == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.ln.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	// closing the HTTP server closes its listener and connections.
+	err := s.httpServer.Close()
+	s.wg.Wait()
+
+	// the batch writer consumes the last batch emitted by the batcher.
+	s.batcher.Stop()
+	close(s.closing)
+	s.writesWg.Wait()
+
+	s.mu.Lock()
+	s.closing = nil
+	s.mu.Unlock()
+	return err
+}
+
+// Addr returns the address the service listens on, or nil if it is not open.
+func (s *Service) Addr() net.Addr {
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+// PrometheusCollectors returns the metrics of the service.
+func (s *Service) PrometheusCollectors() []prometheus.Collector {
+	return s.metrics.PrometheusCollectors()
+}
+
+func (s *Service) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if isClosed(err) {
+				return
+			}
+			s.log.Error("Failed to accept connection", zap.Error(err))
+			continue
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.metrics.ConnectionsTotal.Inc()
+		s.metrics.Connections.Inc()
+		s.wg.Add(1)
+		go s.handleConn(conn)
+	}
+}
+
+// handleConn reads a telnet connection if it starts with a put command, and
+// passes any other connection to the HTTP server.
+func (s *Service) handleConn(conn net.Conn) {
+	defer s.wg.Done()
+
+	r := bufio.NewReader(conn)
+	prefix, err := r.Peek(4)
+	if err != nil {
+		s.closeConn(conn)
+		return
+	}
+
+	if string(prefix) != "put " {
+		// the HTTP server closes the connection and updates the metrics.
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		if !s.httpLn.deliver(&readerConn{Conn: conn, r: r}) {
+			conn.Close()
+			s.metrics.Connections.Dec()
+		}
+		return
+	}
+
+	defer s.closeConn(conn)
+	s.handleTelnetConn(conn, r)
+}
+
+// closeConn closes a connection which is not served by the HTTP server.
+func (s *Service) closeConn(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	conn.Close()
+	s.metrics.Connections.Dec()
+}
+
+// handleTelnetConn reads put commands until the connection is closed.
+func (s *Service) handleTelnetConn(conn net.Conn, r *bufio.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		pt, err := ParsePut(line)
+		if err != nil {
+			s.metrics.ParseErrors.WithLabelValues("telnet").Inc()
+			s.log.Debug("Unable to parse put command", zap.String("line", line), zap.Error(err))
+			continue
+		}
+		s.submit(pt, "telnet")
+	}
+	if err := scanner.Err(); err != nil && !isClosed(err) {
+		s.log.Info("Error reading from connection", zap.Stringer("remote_addr", conn.RemoteAddr()), zap.Error(err))
+	}
+}
+
+// submit sends a point received with the protocol to the batcher.
+func (s *Service) submit(pt models.Point, protocol string) {
+	s.metrics.PointsReceived.WithLabelValues(protocol).Inc()
+	s.batcher.In() <- pt
+}
+
+// processBatches writes the batches emitted by the batcher until the service
+// is closed.
+func (s *Service) processBatches() {
+	defer s.writesWg.Done()
+
+	for {
+		select {
+		case batch := <-s.batcher.Out():
+			s.writeBatch(batch)
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+func (s *Service) writeBatch(batch []models.Point) {
+	start := time.Now()
+	err := s.write(batch)
+	s.metrics.BatchWriteSeconds.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		s.metrics.Batches.WithLabelValues("error").Inc()
+		s.log.Error("Failed to write batch", zap.Int("points", len(batch)), zap.Error(err))
+		return
+	}
+	s.metrics.Batches.WithLabelValues("ok").Inc()
+	s.metrics.PointsWritten.Add(float64(len(batch)))
+}
+
+func (s *Service) write(batch []models.Point) error {
+	points, err := tsdb.ExplodePoints(s.config.OrgID, s.config.BucketID, batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode points: %v", err)
+	}
+	return s.pointsWriter.WritePoints(context.Background(), points)
+}
+
+// chanListener is a net.Listener which accepts the connections delivered to
+// it, so that connections of the shared listener can be served over HTTP.
+type chanListener struct {
+	addr    net.Addr
+	conns   chan net.Conn
+	done    chan struct{}
+	closeMu sync.Once
+}
+
+func newChanListener(addr net.Addr) *chanListener {
+	return &chanListener{
+		addr:  addr,
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+// deliver passes the connection to Accept. It returns false if the listener
+// is closed.
+func (ln *chanListener) deliver(conn net.Conn) bool {
+	select {
+	case ln.conns <- conn:
+		return true
+	case <-ln.done:
+		return false
+	}
+}
+
+func (ln *chanListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-ln.conns:
+		return conn, nil
+	case <-ln.done:
+		return nil, errors.New("use of closed network connection")
+	}
+}
+
+func (ln *chanListener) Close() error {
+	ln.closeMu.Do(func() { close(ln.done) })
+	return nil
+}
+
+func (ln *chanListener) Addr() net.Addr { return ln.addr }
+
+// readerConn is a connection whose data is read from r, which buffers the
+// data peeked from the connection.
+type readerConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *readerConn) Read(p []byte) (int, error) { return c.r.Read(p) }
+
+// isClosed reports whether err was returned by a closed listener or
+// connection.
+func isClosed(err error) bool {
+	return strings.Contains(err.Error(), "use of closed network connection")
+}
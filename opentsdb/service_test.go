@@ -0,0 +1,95 @@
+package opentsdb_test
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/opentsdb"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService(t *testing.T) {
+	pw := &mock.PointsWriter{}
+	s, err := opentsdb.NewService(zaptest.NewLogger(t), pw, opentsdb.Config{
+		BindAddress:  "127.0.0.1:0",
+		OrgID:        1,
+		BucketID:     2,
+		BatchSize:    2,
+		BatchTimeout: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// a point is sent with each protocol, which fills a batch.
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("put sys.cpu.user 1356998400 42.5 host=web01\nput invalid\n")); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	resp, err := http.Post("http://"+s.Addr().String()+"/api/put", "application/json",
+		strings.NewReader(`{"metric": "sys.cpu.nice", "timestamp": 1356998400, "value": 18, "tags": {"host": "web02"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusNoContent; got != want {
+		t.Fatalf("unexpected status code; got %d, want %d", got, want)
+	}
+
+	resp, err = http.Post("http://"+s.Addr().String()+"/api/put", "application/json", strings.NewReader(`{`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusBadRequest; got != want {
+		t.Fatalf("unexpected status code; got %d, want %d", got, want)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for pw.WritePointsCalled() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for points")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	name := tsdb.EncodeNameString(1, 2)
+	want := map[string]bool{
+		models.MustNewPoint(name, models.NewTags(map[string]string{
+			models.MeasurementTagKey: "sys.cpu.user",
+			"host":                   "web01",
+			models.FieldKeyTagKey:    "value",
+		}), models.Fields{"value": 42.5}, time.Unix(1356998400, 0)).String(): true,
+		models.MustNewPoint(name, models.NewTags(map[string]string{
+			models.MeasurementTagKey: "sys.cpu.nice",
+			"host":                   "web02",
+			models.FieldKeyTagKey:    "value",
+		}), models.Fields{"value": 18.0}, time.Unix(1356998400, 0)).String(): true,
+	}
+	if got := len(pw.Points); got != len(want) {
+		t.Fatalf("unexpected number of points; got %d, want %d", got, len(want))
+	}
+	for _, pt := range pw.Points {
+		if !want[pt.String()] {
+			t.Errorf("unexpected point %q", pt.String())
+		}
+	}
+}
@@ -7,12 +7,40 @@ import (
 
 // Organization is an organization. 🎉
 type Organization struct {
-	ID          ID     `json:"id,omitempty"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          ID              `json:"id,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	QueryQuotas *OrgQueryQuotas `json:"queryQuotas,omitempty"`
 	CRUDLog
 }
 
+// OrgQueryQuotas limit the resources used by the queries of an organization.
+// Quotas which are not set use the defaults of the query controller.
+type OrgQueryQuotas struct {
+	// ConcurrencyQuota is the number of queries of the organization which
+	// may be queued or executing at once.
+	ConcurrencyQuota int `json:"concurrencyQuota,omitempty"`
+
+	// MemoryBytesQuota is the number of bytes which may be allocated by all
+	// of the executing queries of the organization.
+	MemoryBytesQuota int64 `json:"memoryBytesQuota,omitempty"`
+
+	// Weight is the share of the query queue given to the organization
+	// relative to other organizations. The default weight is 1.
+	Weight int `json:"weight,omitempty"`
+}
+
+// Validate reports any validation errors for the quotas.
+func (q *OrgQueryQuotas) Validate() error {
+	if q.ConcurrencyQuota < 0 || q.MemoryBytesQuota < 0 || q.Weight < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "org query quotas must not be negative",
+		}
+	}
+	return nil
+}
+
 // errors of org
 var (
 	// ErrOrgNameisEmpty is error when org name is empty
@@ -60,7 +88,8 @@ type OrganizationService interface {
 // Only fields which are set are updated.
 type OrganizationUpdate struct {
 	Name        *string
-	Description *string `json:"description,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	QueryQuotas *OrgQueryQuotas `json:"queryQuotas,omitempty"`
 }
 
 // ErrInvalidOrgFilter is the error indicate org filter is empty
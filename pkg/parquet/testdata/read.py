@@ -0,0 +1,20 @@
+"""Prints the row groups, metadata and columns of a Parquet file read by pyarrow.
+
+Used by TestWriter_Interop to check that the files of the writer are read by a
+Parquet implementation other than its own tests.
+"""
+import json
+import sys
+
+import pyarrow as pa
+import pyarrow.parquet as pq
+
+f = pq.ParquetFile(sys.argv[1])
+print("row_groups", f.metadata.num_row_groups)
+table = f.read()
+for k, v in sorted((table.schema.metadata or {}).items()):
+    print("metadata", k.decode(), v.decode())
+for field, column in zip(table.schema, table.columns):
+    if pa.types.is_timestamp(field.type):
+        column = column.cast(pa.int64())
+    print(field.name, field.type, json.dumps(column.to_pylist()))
@@ -0,0 +1,117 @@
+package parquet
+
+import "encoding/binary"
+
+// Types of the thrift compact protocol.
+const (
+	compactBooleanTrue  = 1
+	compactBooleanFalse = 2
+	compactByte         = 3
+	compactI32          = 5
+	compactI64          = 6
+	compactBinary       = 8
+	compactList         = 9
+	compactStruct       = 12
+)
+
+// thriftWriter encodes the parquet metadata with the thrift compact protocol.
+// Only the parts of the protocol needed to write the metadata are implemented.
+type thriftWriter struct {
+	buf []byte
+
+	// last is the id of the last field written in the current struct
+	// and stack holds the ids of the structs it is nested in.
+	last  int16
+	stack []int16
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	if delta := id - w.last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	w.last = id
+}
+
+// varint writes v zigzag encoded, as are the integers of the protocol.
+func (w *thriftWriter) varint(v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	w.buf = append(w.buf, buf[:n]...)
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	w.buf = append(w.buf, buf[:n]...)
+}
+
+func (w *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		w.field(id, compactBooleanTrue)
+	} else {
+		w.field(id, compactBooleanFalse)
+	}
+}
+
+func (w *thriftWriter) i8(id int16, v int8) {
+	w.field(id, compactByte)
+	w.buf = append(w.buf, byte(v))
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, compactI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, compactI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) str(id int16, v string) {
+	w.field(id, compactBinary)
+	w.elemString(v)
+}
+
+// beginStruct begins a struct field, which must be ended with endStruct.
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, compactStruct)
+	w.beginElemStruct()
+}
+
+// beginList begins a list field of n elements, which must be
+// written with the elem methods.
+func (w *thriftWriter) beginList(id int16, typ byte, n int) {
+	w.field(id, compactList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|typ)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|typ)
+	w.uvarint(uint64(n))
+}
+
+func (w *thriftWriter) elemI32(v int32) {
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) elemString(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// beginElemStruct begins a struct element of a list, or the
+// top level struct, which must be ended with endStruct.
+func (w *thriftWriter) beginElemStruct() {
+	w.stack = append(w.stack, w.last)
+	w.last = 0
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf = append(w.buf, 0)
+	w.last = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
@@ -0,0 +1,460 @@
+// Package parquet writes flat Apache Parquet files.
+//
+// The writer supports the subset of the format needed to stream tables of
+// scalar values: every column is optional and written as a single uncompressed
+// data page per row group, with its values in the plain encoding. Each row group
+// is written as soon as it is complete, and the schema of the file is the union
+// of the columns of its row groups.
+//
+// A column added to the schema after row groups were written is null in those
+// row groups, and its null chunks are written when the file is closed, after
+// the last row group. The chunks of such a row group are then not contiguous.
+// The format allows this: it guarantees that a column chunk is contiguous, but
+// no physical structure for a row group, and readers locate every chunk by the
+// offset of its data page in the metadata of the file.
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Type is the type of the values of a column.
+type Type int
+
+const (
+	Boolean Type = iota
+	Int64
+	Uint64
+	Double
+	String
+	// Timestamp is a time in nanoseconds since the Unix epoch in UTC.
+	Timestamp
+)
+
+func (t Type) String() string {
+	switch t {
+	case Boolean:
+		return "boolean"
+	case Int64:
+		return "int64"
+	case Uint64:
+		return "uint64"
+	case Double:
+		return "double"
+	case String:
+		return "string"
+	case Timestamp:
+		return "timestamp"
+	default:
+		return "unknown"
+	}
+}
+
+// Physical types, converted types and encodings of the parquet format.
+const (
+	typeBoolean   = 0
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	convertedUTF8   = 0
+	convertedUint64 = 14
+
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+)
+
+const magic = "PAR1"
+
+func (t Type) physical() int32 {
+	switch t {
+	case Boolean:
+		return typeBoolean
+	case Double:
+		return typeDouble
+	case String:
+		return typeByteArray
+	default:
+		return typeInt64
+	}
+}
+
+// Column accumulates the values of a column of a row group.
+// Values must be appended with the method matching the type of the column.
+type Column struct {
+	Name string
+	Type Type
+
+	// defs holds the definition level of each value,
+	// which is 0 for null values and 1 otherwise.
+	defs   []byte
+	values []byte
+	bits   int
+}
+
+// NewColumn returns an empty column.
+func NewColumn(name string, typ Type) *Column {
+	return &Column{Name: name, Type: typ}
+}
+
+// Len returns the number of values in the column.
+func (c *Column) Len() int {
+	return len(c.defs)
+}
+
+// AppendNull appends a null value to the column.
+func (c *Column) AppendNull() {
+	c.defs = append(c.defs, 0)
+}
+
+// AppendBool appends a value to a Boolean column.
+func (c *Column) AppendBool(v bool) {
+	c.defs = append(c.defs, 1)
+	if c.bits%8 == 0 {
+		c.values = append(c.values, 0)
+	}
+	if v {
+		c.values[len(c.values)-1] |= 1 << uint(c.bits%8)
+	}
+	c.bits++
+}
+
+// AppendInt appends a value to an Int64 or a Timestamp column.
+func (c *Column) AppendInt(v int64) {
+	c.AppendUint(uint64(v))
+}
+
+// AppendUint appends a value to a Uint64 column.
+func (c *Column) AppendUint(v uint64) {
+	c.defs = append(c.defs, 1)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	c.values = append(c.values, buf[:]...)
+}
+
+// AppendFloat appends a value to a Double column.
+func (c *Column) AppendFloat(v float64) {
+	c.AppendUint(math.Float64bits(v))
+}
+
+// AppendString appends a value to a String column.
+func (c *Column) AppendString(v string) {
+	c.defs = append(c.defs, 1)
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(len(v)))
+	c.values = append(c.values, buf[:]...)
+	c.values = append(c.values, v...)
+}
+
+// Reset removes the values of the column so that it can be reused.
+func (c *Column) Reset() {
+	c.defs = c.defs[:0]
+	c.values = c.values[:0]
+	c.bits = 0
+}
+
+// columnChunk is the location of a column in a row group.
+type columnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+type rowGroup struct {
+	numRows int64
+	size    int64
+	chunks  map[int]columnChunk
+}
+
+// Writer writes a parquet file.
+type Writer struct {
+	w   io.Writer
+	pos int64
+	err error
+
+	columns   []*Column
+	index     map[string]int
+	rowGroups []*rowGroup
+	numRows   int64
+
+	keys, values []string
+}
+
+// NewWriter returns a writer of a parquet file to w.
+// The file is complete once the writer is closed.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:     w,
+		index: make(map[string]int),
+	}
+}
+
+// SetKeyValue adds the key and value to the metadata of the file.
+func (w *Writer) SetKeyValue(key, value string) {
+	w.keys = append(w.keys, key)
+	w.values = append(w.values, value)
+}
+
+// WriteRowGroup writes a row group of the columns, which must have the same length.
+// Columns which were not in the previous row groups are added to the schema of the
+// file and a column of the schema which is not in cols is null in the row group.
+// The values of cols may be reset once this returns.
+func (w *Writer) WriteRowGroup(cols []*Column) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	numRows := cols[0].Len()
+	if numRows == 0 {
+		return nil
+	}
+	for _, c := range cols {
+		if c.Len() != numRows {
+			return fmt.Errorf("column %q has %d values instead of %d", c.Name, c.Len(), numRows)
+		}
+		if i, ok := w.index[c.Name]; ok && w.columns[i].Type != c.Type {
+			return fmt.Errorf("column %q is %s in one row group and %s in another", c.Name, w.columns[i].Type, c.Type)
+		}
+	}
+
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	rg := &rowGroup{
+		numRows: int64(numRows),
+		chunks:  make(map[int]columnChunk, len(cols)),
+	}
+	for _, c := range cols {
+		i, ok := w.index[c.Name]
+		if !ok {
+			i = len(w.columns)
+			w.index[c.Name] = i
+			w.columns = append(w.columns, NewColumn(c.Name, c.Type))
+		}
+		chunk, err := w.writePage(c.defs, c.values)
+		if err != nil {
+			return err
+		}
+		rg.chunks[i] = chunk
+		rg.size += chunk.size
+	}
+
+	// The columns of the schema which are not in this row group are written
+	// as nulls now, or when the file is closed if they are added later.
+	if err := w.writeNulls(rg); err != nil {
+		return err
+	}
+	w.rowGroups = append(w.rowGroups, rg)
+	w.numRows += rg.numRows
+	return nil
+}
+
+// Close writes the remaining null columns and the metadata of the file.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	for _, rg := range w.rowGroups {
+		if err := w.writeNulls(rg); err != nil {
+			return err
+		}
+	}
+
+	meta := w.fileMetadata()
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(meta)))
+	if err := w.write(meta); err != nil {
+		return err
+	}
+	if err := w.write(size[:]); err != nil {
+		return err
+	}
+	return w.write([]byte(magic))
+}
+
+func (w *Writer) start() error {
+	if w.pos > 0 || w.err != nil {
+		return w.err
+	}
+	return w.write([]byte(magic))
+}
+
+func (w *Writer) write(p []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	n, err := w.w.Write(p)
+	w.pos += int64(n)
+	w.err = err
+	return err
+}
+
+func (w *Writer) writeNulls(rg *rowGroup) error {
+	var defs []byte
+	for i := range w.columns {
+		if _, ok := rg.chunks[i]; ok {
+			continue
+		}
+		if defs == nil {
+			defs = make([]byte, rg.numRows)
+		}
+		chunk, err := w.writePage(defs, nil)
+		if err != nil {
+			return err
+		}
+		rg.chunks[i] = chunk
+		rg.size += chunk.size
+	}
+	return nil
+}
+
+// writePage writes a column chunk of a single data page.
+func (w *Writer) writePage(defs, values []byte) (columnChunk, error) {
+	page := appendLevels(nil, defs)
+	page = append(page, values...)
+
+	var tw thriftWriter
+	tw.beginElemStruct()
+	tw.i32(1, pageTypeData)
+	tw.i32(2, int32(len(page)))
+	tw.i32(3, int32(len(page)))
+	tw.beginStruct(5)
+	tw.i32(1, int32(len(defs)))
+	tw.i32(2, encodingPlain)
+	tw.i32(3, encodingRLE)
+	tw.i32(4, encodingRLE)
+	tw.endStruct()
+	tw.endStruct()
+
+	chunk := columnChunk{
+		offset:    w.pos,
+		size:      int64(len(tw.buf) + len(page)),
+		numValues: int64(len(defs)),
+	}
+	if err := w.write(tw.buf); err != nil {
+		return chunk, err
+	}
+	return chunk, w.write(page)
+}
+
+// appendLevels appends the definition levels in the run length encoding,
+// prefixed by their length as is done in the data pages.
+func appendLevels(buf, defs []byte) []byte {
+	start := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	var varint [binary.MaxVarintLen64]byte
+	for i := 0; i < len(defs); {
+		j := i + 1
+		for j < len(defs) && defs[j] == defs[i] {
+			j++
+		}
+		// The header of a run is its length shifted left by one, followed
+		// by the repeated level in a single byte as the levels are one bit.
+		n := binary.PutUvarint(varint[:], uint64(j-i)<<1)
+		buf = append(buf, varint[:n]...)
+		buf = append(buf, defs[i])
+		i = j
+	}
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(buf)-start-4))
+	return buf
+}
+
+func (w *Writer) fileMetadata() []byte {
+	var tw thriftWriter
+	tw.beginElemStruct()
+	tw.i32(1, 1)
+
+	tw.beginList(2, compactStruct, len(w.columns)+1)
+	tw.beginElemStruct()
+	tw.str(4, "schema")
+	tw.i32(5, int32(len(w.columns)))
+	tw.endStruct()
+	for _, c := range w.columns {
+		tw.beginElemStruct()
+		tw.i32(1, c.Type.physical())
+		tw.i32(3, repetitionOptional)
+		tw.str(4, c.Name)
+		writeLogicalType(&tw, c.Type)
+		tw.endStruct()
+	}
+
+	tw.i64(3, w.numRows)
+
+	tw.beginList(4, compactStruct, len(w.rowGroups))
+	for _, rg := range w.rowGroups {
+		tw.beginElemStruct()
+		tw.beginList(1, compactStruct, len(w.columns))
+		for i, c := range w.columns {
+			chunk := rg.chunks[i]
+			tw.beginElemStruct()
+			tw.i64(2, chunk.offset)
+			tw.beginStruct(3)
+			tw.i32(1, c.Type.physical())
+			tw.beginList(2, compactI32, 2)
+			tw.elemI32(encodingPlain)
+			tw.elemI32(encodingRLE)
+			tw.beginList(3, compactBinary, 1)
+			tw.elemString(c.Name)
+			tw.i32(4, 0) // uncompressed
+			tw.i64(5, chunk.numValues)
+			tw.i64(6, chunk.size)
+			tw.i64(7, chunk.size)
+			tw.i64(9, chunk.offset)
+			tw.endStruct()
+			tw.endStruct()
+		}
+		tw.i64(2, rg.size)
+		tw.i64(3, rg.numRows)
+		tw.endStruct()
+	}
+
+	if len(w.keys) > 0 {
+		tw.beginList(5, compactStruct, len(w.keys))
+		for i, k := range w.keys {
+			tw.beginElemStruct()
+			tw.str(1, k)
+			tw.str(2, w.values[i])
+			tw.endStruct()
+		}
+	}
+	tw.str(6, "influxdb")
+	tw.endStruct()
+	return tw.buf
+}
+
+// writeLogicalType writes the converted and logical types of a schema element.
+func writeLogicalType(tw *thriftWriter, typ Type) {
+	switch typ {
+	case String:
+		tw.i32(6, convertedUTF8)
+		tw.beginStruct(10)
+		tw.beginStruct(1)
+		tw.endStruct()
+		tw.endStruct()
+	case Uint64:
+		tw.i32(6, convertedUint64)
+		tw.beginStruct(10)
+		tw.beginStruct(10)
+		tw.i8(1, 64)
+		tw.boolean(2, false)
+		tw.endStruct()
+		tw.endStruct()
+	case Timestamp:
+		tw.beginStruct(10)
+		tw.beginStruct(8)
+		tw.boolean(1, true)
+		tw.beginStruct(2)
+		tw.beginStruct(3)
+		tw.endStruct()
+		tw.endStruct()
+		tw.endStruct()
+		tw.endStruct()
+	}
+}
@@ -0,0 +1,525 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.SetKeyValue("k", "v")
+
+	a, s := NewColumn("a", Int64), NewColumn("s", String)
+	a.AppendInt(1)
+	a.AppendNull()
+	s.AppendString("x")
+	s.AppendString("y")
+	if err := w.WriteRowGroup([]*Column{a, s}); err != nil {
+		t.Fatal(err)
+	}
+
+	a.Reset()
+	b := NewColumn("b", Boolean)
+	a.AppendInt(2)
+	b.AppendBool(true)
+	if err := w.WriteRowGroup([]*Column{a, b}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteRowGroup([]*Column{NewColumn("a", Double)}); err != nil {
+		t.Fatal(err)
+	}
+	c := NewColumn("a", Double)
+	c.AppendFloat(1)
+	if err := w.WriteRowGroup([]*Column{c}); err == nil {
+		t.Fatal("expected an error about the type of a column changing")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte(magic)) || !bytes.HasSuffix(data, []byte(magic)) {
+		t.Fatal("expected the file to start and end with the magic number")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	r := &thriftReader{buf: data[len(data)-8-size : len(data)-8]}
+	meta := r.readStruct()
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+
+	if got := meta[3]; got != int64(3) {
+		t.Errorf("unexpected number of rows: %v", got)
+	}
+	var names []string
+	for _, e := range meta[2].([]interface{}) {
+		names = append(names, e.(map[int16]interface{})[4].(string))
+	}
+	if got, want := fmt.Sprint(names), "[schema a s b]"; got != want {
+		t.Errorf("unexpected schema: got %s want %s", got, want)
+	}
+	rowGroups := meta[4].([]interface{})
+	if len(rowGroups) != 2 {
+		t.Fatalf("unexpected number of row groups: %d", len(rowGroups))
+	}
+	for i, rg := range rowGroups {
+		// Every row group has a chunk for every column, in the order
+		// of the schema, including the columns which were added later.
+		chunks := rg.(map[int16]interface{})[1].([]interface{})
+		for j, chunk := range chunks {
+			path := chunk.(map[int16]interface{})[3].(map[int16]interface{})[3].([]interface{})
+			if got, want := path[0], names[j+1]; got != want {
+				t.Errorf("unexpected column %d in row group %d: got %v want %v", j, i, got, want)
+			}
+		}
+		if len(chunks) != 3 {
+			t.Errorf("unexpected number of columns in row group %d: %d", i, len(chunks))
+		}
+	}
+	kv := meta[5].([]interface{})[0].(map[int16]interface{})
+	if kv[1] != "k" || kv[2] != "v" {
+		t.Errorf("unexpected key value metadata: %v", kv)
+	}
+}
+
+// thriftReader decodes the structs written by the thriftWriter.
+type thriftReader struct {
+	buf []byte
+	err error
+}
+
+func (r *thriftReader) byte() byte {
+	if len(r.buf) == 0 {
+		r.err = fmt.Errorf("unexpected end of buffer")
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *thriftReader) varint() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = fmt.Errorf("invalid varint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = fmt.Errorf("invalid uvarint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for r.err == nil {
+		h := r.byte()
+		if h == 0 {
+			break
+		}
+		id, typ := last+int16(h>>4), h&0x0f
+		if h>>4 == 0 {
+			id = int16(r.varint())
+		}
+		last = id
+		switch typ {
+		case compactBooleanTrue:
+			fields[id] = true
+		case compactBooleanFalse:
+			fields[id] = false
+		default:
+			fields[id] = r.readValue(typ)
+		}
+	}
+	return fields
+}
+
+func (r *thriftReader) readValue(typ byte) interface{} {
+	switch typ {
+	case compactByte:
+		return int8(r.byte())
+	case compactI32, compactI64:
+		return r.varint()
+	case compactBinary:
+		n := int(r.uvarint())
+		if n > len(r.buf) {
+			r.err = fmt.Errorf("unexpected end of buffer")
+			return nil
+		}
+		s := string(r.buf[:n])
+		r.buf = r.buf[n:]
+		return s
+	case compactList:
+		h := r.byte()
+		n, elem := int(h>>4), h&0x0f
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.readValue(elem)
+		}
+		return list
+	case compactStruct:
+		return r.readStruct()
+	default:
+		r.err = fmt.Errorf("unexpected type %d", typ)
+		return nil
+	}
+}
+
+// writeTestFile writes a file of two row groups, the second of which has
+// columns which are not in the first.
+func writeTestFile(w *Writer) error {
+	w.SetKeyValue("k", "v")
+
+	ts, a, s := NewColumn("time", Timestamp), NewColumn("a", Int64), NewColumn("s", String)
+	ts.AppendInt(1)
+	ts.AppendInt(2)
+	a.AppendInt(1)
+	a.AppendNull()
+	s.AppendString("x")
+	s.AppendString("y")
+	if err := w.WriteRowGroup([]*Column{ts, a, s}); err != nil {
+		return err
+	}
+
+	// the columns first seen in the second row group are null in the first.
+	ts.Reset()
+	a.Reset()
+	b, u, d := NewColumn("b", Boolean), NewColumn("u", Uint64), NewColumn("d", Double)
+	ts.AppendInt(3)
+	a.AppendInt(2)
+	b.AppendBool(true)
+	u.AppendUint(math.MaxUint64)
+	d.AppendFloat(1.5)
+	if err := w.WriteRowGroup([]*Column{ts, a, b, u, d}); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// TestWriter_Golden checks that the writer writes the file in testdata, and
+// decodes that file as described by the format, independently of the writer.
+func TestWriter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTestFile(NewWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "rowgroups.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), golden) {
+		t.Errorf("the file written differs from testdata/rowgroups.parquet")
+	}
+
+	f, err := decodeFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.rowGroups != 2 {
+		t.Errorf("unexpected number of row groups: %d", f.rowGroups)
+	}
+	if got, want := fmt.Sprint(f.metadata), "map[k:v]"; got != want {
+		t.Errorf("unexpected metadata: got %s want %s", got, want)
+	}
+	want := []struct {
+		name   string
+		values []interface{}
+	}{
+		{name: "time", values: []interface{}{int64(1), int64(2), int64(3)}},
+		{name: "a", values: []interface{}{int64(1), nil, int64(2)}},
+		{name: "s", values: []interface{}{"x", "y", nil}},
+		{name: "b", values: []interface{}{nil, nil, true}},
+		{name: "u", values: []interface{}{nil, nil, uint64(math.MaxUint64)}},
+		{name: "d", values: []interface{}{nil, nil, 1.5}},
+	}
+	if len(f.columns) != len(want) {
+		t.Fatalf("unexpected number of columns: %d", len(f.columns))
+	}
+	for i, c := range f.columns {
+		if c.name != want[i].name {
+			t.Errorf("unexpected column %d: got %s want %s", i, c.name, want[i].name)
+		}
+		if got, want := fmt.Sprint(c.values), fmt.Sprint(want[i].values); got != want {
+			t.Errorf("unexpected values of column %s: got %s want %s", c.name, got, want)
+		}
+	}
+}
+
+// TestWriter_ChunkLayout checks the layout of the column chunks of a file
+// with columns added after its first row group. The chunks of those columns
+// in the first row group are written after the second row group, so the
+// chunks of the first row group are not contiguous.
+//
+// The format guarantees that a column chunk is contiguous, but "there is no
+// physical structure that is guaranteed for a row group": every chunk is read
+// from the offset of its data page in the metadata. So the chunks must not
+// overlap, and must cover the file between its magic number and its metadata.
+func TestWriter_ChunkLayout(t *testing.T) {
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "rowgroups.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := decodeFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := append([]decodedChunk(nil), f.chunks...)
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].offset < chunks[j].offset })
+	pos := int64(len(magic))
+	for _, c := range chunks {
+		if c.offset != pos {
+			t.Errorf("chunk of column %s of row group %d starts at %d instead of %d", c.column, c.rowGroup, c.offset, pos)
+		}
+		pos = c.offset + c.size
+	}
+	if pos != f.metadataOffset {
+		t.Errorf("the chunks end at %d instead of the metadata at %d", pos, f.metadataOffset)
+	}
+
+	// the chunks of the columns added by the second row group are written
+	// in the first row group when the file is closed, after the second.
+	last := make(map[int]int64)
+	for _, c := range f.chunks {
+		if c.offset > last[c.rowGroup] {
+			last[c.rowGroup] = c.offset
+		}
+	}
+	for _, c := range f.chunks {
+		added := c.column == "b" || c.column == "u" || c.column == "d"
+		if c.rowGroup == 0 && added && c.offset < last[1] {
+			t.Errorf("chunk of column %s of the first row group is before the second row group", c.column)
+		}
+		if c.rowGroup == 0 && !added && c.offset > last[1] {
+			t.Errorf("chunk of column %s of the first row group is after the second row group", c.column)
+		}
+	}
+}
+
+type decodedFile struct {
+	rowGroups      int
+	metadata       map[string]string
+	columns        []decodedColumn
+	chunks         []decodedChunk
+	metadataOffset int64
+}
+
+type decodedColumn struct {
+	name      string
+	physical  int64
+	converted interface{}
+	values    []interface{}
+}
+
+type decodedChunk struct {
+	rowGroup     int
+	column       string
+	offset, size int64
+}
+
+// decodeFile decodes the subset of the format written by the writer: a flat
+// schema of optional columns, whose chunks have a single uncompressed data
+// page of plain values. Every column chunk is read from the offset of its
+// data page in the metadata, as readers of the format do.
+func decodeFile(data []byte) (*decodedFile, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) || !bytes.HasSuffix(data, []byte(magic)) {
+		return nil, fmt.Errorf("expected the file to start and end with the magic number")
+	}
+	size := int64(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	f := &decodedFile{
+		metadata:       make(map[string]string),
+		metadataOffset: int64(len(data)) - 8 - size,
+	}
+	r := &thriftReader{buf: data[f.metadataOffset : len(data)-8]}
+	meta := r.readStruct()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	schema := meta[2].([]interface{})
+	for _, e := range schema[1:] {
+		e := e.(map[int16]interface{})
+		f.columns = append(f.columns, decodedColumn{
+			name:      e[4].(string),
+			physical:  e[1].(int64),
+			converted: e[6],
+		})
+	}
+	if kvs, ok := meta[5].([]interface{}); ok {
+		for _, kv := range kvs {
+			kv := kv.(map[int16]interface{})
+			f.metadata[kv[1].(string)] = kv[2].(string)
+		}
+	}
+
+	rowGroups := meta[4].([]interface{})
+	f.rowGroups = len(rowGroups)
+	for i, rg := range rowGroups {
+		rg := rg.(map[int16]interface{})
+		numRows := rg[3].(int64)
+		cols := rg[1].([]interface{})
+		if len(cols) != len(f.columns) {
+			return nil, fmt.Errorf("row group %d has %d columns instead of %d", i, len(cols), len(f.columns))
+		}
+		for j, cc := range cols {
+			md := cc.(map[int16]interface{})[3].(map[int16]interface{})
+			c := &f.columns[j]
+			if path := md[3].([]interface{}); len(path) != 1 || path[0] != c.name {
+				return nil, fmt.Errorf("column %d of row group %d is %v instead of %s", j, i, path, c.name)
+			}
+			chunk := decodedChunk{
+				rowGroup: i,
+				column:   c.name,
+				offset:   md[9].(int64),
+				size:     md[7].(int64),
+			}
+			if chunk.offset < int64(len(magic)) || chunk.offset+chunk.size > f.metadataOffset {
+				return nil, fmt.Errorf("chunk of column %s of row group %d is out of the file", c.name, i)
+			}
+			values, err := decodeChunk(data[chunk.offset:chunk.offset+chunk.size], c)
+			if err != nil {
+				return nil, fmt.Errorf("chunk of column %s of row group %d: %v", c.name, i, err)
+			}
+			if int64(len(values)) != numRows || md[5].(int64) != numRows {
+				return nil, fmt.Errorf("chunk of column %s of row group %d has %d values instead of %d", c.name, i, len(values), numRows)
+			}
+			c.values = append(c.values, values...)
+			f.chunks = append(f.chunks, chunk)
+		}
+	}
+	return f, nil
+}
+
+// decodeChunk decodes the values of a column chunk of a single data page.
+func decodeChunk(chunk []byte, c *decodedColumn) ([]interface{}, error) {
+	r := &thriftReader{buf: chunk}
+	hdr := r.readStruct()
+	if r.err != nil {
+		return nil, r.err
+	}
+	page := r.buf
+	if hdr[1] != int64(pageTypeData) || hdr[3] != int64(len(page)) {
+		return nil, fmt.Errorf("unexpected page header %v for a page of %d bytes", hdr, len(page))
+	}
+	dph := hdr[5].(map[int16]interface{})
+	numValues := int(dph[1].(int64))
+
+	// the definition levels are a run length encoding prefixed by its length.
+	n := int(binary.LittleEndian.Uint32(page))
+	levels := &thriftReader{buf: page[4 : 4+n]}
+	var defs []byte
+	for len(levels.buf) > 0 && levels.err == nil {
+		run := int(levels.uvarint())
+		if run&1 != 0 {
+			return nil, fmt.Errorf("unexpected bit packed run of definition levels")
+		}
+		v := levels.byte()
+		for k := 0; k < run>>1; k++ {
+			defs = append(defs, v)
+		}
+	}
+	if levels.err != nil {
+		return nil, levels.err
+	}
+	if len(defs) != numValues {
+		return nil, fmt.Errorf("%d definition levels instead of %d", len(defs), numValues)
+	}
+
+	plain := page[4+n:]
+	values := make([]interface{}, numValues)
+	bit := 0
+	for i, def := range defs {
+		if def == 0 {
+			continue
+		}
+		switch c.physical {
+		case typeBoolean:
+			values[i] = plain[bit/8]&(1<<uint(bit%8)) != 0
+			bit++
+		case typeInt64:
+			v := binary.LittleEndian.Uint64(plain)
+			plain = plain[8:]
+			if c.converted == int64(convertedUint64) {
+				values[i] = v
+			} else {
+				values[i] = int64(v)
+			}
+		case typeDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(plain))
+			plain = plain[8:]
+		case typeByteArray:
+			n := binary.LittleEndian.Uint32(plain)
+			values[i] = string(plain[4 : 4+n])
+			plain = plain[4+n:]
+		default:
+			return nil, fmt.Errorf("unexpected physical type %d", c.physical)
+		}
+	}
+	return values, nil
+}
+
+// TestWriter_Interop reads a file of the writer with pyarrow, if it is
+// installed, to check that it is read by another implementation of the format.
+// TestWriter_Golden decodes the same file without pyarrow.
+func TestWriter_Interop(t *testing.T) {
+	if err := exec.Command("python3", "-c", "import pyarrow.parquet").Run(); err != nil {
+		t.Skip("pyarrow is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := writeTestFile(NewWriter(f)); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("python3", filepath.Join("testdata", "read.py"), path).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to read the file with pyarrow: %v\n%s", err, out)
+	}
+	want := `row_groups 2
+metadata k v
+time timestamp[ns, tz=UTC] [1, 2, 3]
+a int64 [1, null, 2]
+s string ["x", "y", null]
+b bool [null, null, true]
+u uint64 [null, null, 18446744073709551615]
+d double [null, null, 1.5]
+`
+	if got := string(out); got != want {
+		t.Errorf("unexpected file read by pyarrow:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -57,7 +57,7 @@ func uniqResourcesToClone(resources []ResourceToClone) []ResourceToClone {
 	return out
 }
 
-func bucketToObject(bkt influxdb.Bucket, name string) Object {
+func bucketToObject(bkt influxdb.Bucket, name string, schemas ...*influxdb.MeasurementSchema) Object {
 	if name == "" {
 		name = bkt.Name
 	}
@@ -67,10 +67,32 @@ func bucketToObject(bkt influxdb.Bucket, name string) Object {
 		Metadata:   Metadata{Name: name},
 		Spec:       make(Resource),
 	}
-	assignNonZeroStrings(k.Spec, map[string]string{fieldDescription: bkt.Description})
+	assignNonZeroStrings(k.Spec, map[string]string{
+		fieldDescription:      bkt.Description,
+		fieldBucketSchemaType: schemaTypeString(bkt.SchemaType),
+	})
 	if bkt.RetentionPeriod != 0 {
 		k.Spec[fieldBucketRetentionRules] = retentionRules{newRetentionRule(bkt.RetentionPeriod)}
 	}
+	if len(schemas) > 0 {
+		ms := make([]Resource, 0, len(schemas))
+		for _, m := range schemas {
+			columns := make([]Resource, 0, len(m.Columns))
+			for _, c := range m.Columns {
+				column := Resource{
+					fieldName: c.Name,
+					fieldType: string(c.Type),
+				}
+				assignNonZeroStrings(column, map[string]string{fieldMeasurementSchemaColumnDataType: string(c.DataType)})
+				columns = append(columns, column)
+			}
+			ms = append(ms, Resource{
+				fieldName:                     m.Name,
+				fieldMeasurementSchemaColumns: columns,
+			})
+		}
+		k.Spec[fieldBucketMeasurementSchemas] = ms
+	}
 	return k
 }
 
@@ -550,6 +572,24 @@ func taskToObject(t influxdb.Task, name string) Object {
 	return k
 }
 
+func scriptToObject(s influxdb.Script, name string) Object {
+	if name == "" {
+		name = s.Name
+	}
+	k := Object{
+		APIVersion: APIVersion,
+		Type:       KindScript,
+		Metadata:   Metadata{Name: name},
+		Spec: Resource{
+			fieldScript: strings.TrimSpace(s.Script),
+		},
+	}
+	assignNonZeroStrings(k.Spec, map[string]string{
+		fieldDescription: s.Description,
+	})
+	return k
+}
+
 func telegrafToObject(t influxdb.TelegrafConfig, name string) Object {
 	if name == "" {
 		name = t.Name
@@ -33,6 +33,7 @@ const (
 	KindNotificationEndpointSlack     Kind = "NotificationEndpointSlack"
 	KindNotificationRule              Kind = "NotificationRule"
 	KindPackage                       Kind = "Package"
+	KindScript                        Kind = "Script"
 	KindTask                          Kind = "Task"
 	KindTelegraf                      Kind = "Telegraf"
 	KindVariable                      Kind = "Variable"
@@ -50,6 +51,7 @@ var kinds = map[Kind]bool{
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
 	KindNotificationRule:              true,
+	KindScript:                        true,
 	KindTask:                          true,
 	KindTelegraf:                      true,
 	KindVariable:                      true,
@@ -65,6 +67,7 @@ var kindsUniqByName = map[Kind]bool{
 	KindNotificationEndpointHTTP:      true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindScript:                        true,
 	KindVariable:                      true,
 }
 
@@ -111,6 +114,8 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
+	case KindScript:
+		return influxdb.ScriptsResourceType
 	case KindTask:
 		return influxdb.TasksResourceType
 	case KindTelegraf:
@@ -163,6 +168,7 @@ type Diff struct {
 	LabelMappings         []DiffLabelMapping         `json:"labelMappings"`
 	NotificationEndpoints []DiffNotificationEndpoint `json:"notificationEndpoints"`
 	NotificationRules     []DiffNotificationRule     `json:"notificationRules"`
+	Scripts               []DiffScript               `json:"scripts"`
 	Tasks                 []DiffTask                 `json:"tasks"`
 	Telegrafs             []DiffTelegraf             `json:"telegrafConfigs"`
 	Variables             []DiffVariable             `json:"variables"`
@@ -183,6 +189,12 @@ func (d Diff) HasConflicts() bool {
 		}
 	}
 
+	for _, s := range d.Scripts {
+		if s.hasConflict() {
+			return true
+		}
+	}
+
 	for _, v := range d.Variables {
 		if v.hasConflict() {
 			return true
@@ -194,8 +206,10 @@ func (d Diff) HasConflicts() bool {
 
 // DiffBucketValues are the varying values for a bucket.
 type DiffBucketValues struct {
-	Description    string         `json:"description"`
-	RetentionRules retentionRules `json:"retentionRules"`
+	Description        string                     `json:"description"`
+	RetentionRules     retentionRules             `json:"retentionRules"`
+	SchemaType         string                     `json:"schemaType,omitempty"`
+	MeasurementSchemas []SummaryMeasurementSchema `json:"measurementSchemas,omitempty"`
 }
 
 // DiffBucket is a diff of an individual bucket.
@@ -210,14 +224,17 @@ func newDiffBucket(b *bucket, i *influxdb.Bucket) DiffBucket {
 	diff := DiffBucket{
 		Name: b.Name(),
 		New: DiffBucketValues{
-			Description:    b.Description,
-			RetentionRules: b.RetentionRules,
+			Description:        b.Description,
+			RetentionRules:     b.RetentionRules,
+			SchemaType:         schemaTypeString(b.SchemaType),
+			MeasurementSchemas: b.MeasurementSchemas.summarize(),
 		},
 	}
 	if i != nil {
 		diff.ID = SafeID(i.ID)
 		diff.Old = &DiffBucketValues{
 			Description: i.Description,
+			SchemaType:  schemaTypeString(i.SchemaType),
 		}
 		if i.RetentionPeriod > 0 {
 			diff.Old.RetentionRules = retentionRules{newRetentionRule(i.RetentionPeriod)}
@@ -437,6 +454,47 @@ func newDiffNotificationRule(r *notificationRule, iEndpoint influxdb.Notificatio
 	return sum
 }
 
+// DiffScriptValues are the varying values for a script.
+type DiffScriptValues struct {
+	Description string `json:"description"`
+	Script      string `json:"script"`
+}
+
+// DiffScript is a diff of an individual script.
+type DiffScript struct {
+	ID   SafeID            `json:"id"`
+	Name string            `json:"name"`
+	New  DiffScriptValues  `json:"new"`
+	Old  *DiffScriptValues `json:"old,omitempty"` // using omitempty here to signal there was no prev state with a nil
+}
+
+func newDiffScript(s *script, i *influxdb.Script) DiffScript {
+	diff := DiffScript{
+		Name: s.Name(),
+		New: DiffScriptValues{
+			Description: s.Description,
+			Script:      s.Script,
+		},
+	}
+	if i != nil {
+		diff.ID = SafeID(i.ID)
+		diff.Old = &DiffScriptValues{
+			Description: i.Description,
+			Script:      i.Script,
+		}
+	}
+	return diff
+}
+
+// IsNew indicates whether a pkg script is going to be new to the platform.
+func (d DiffScript) IsNew() bool {
+	return d.ID == SafeID(0)
+}
+
+func (d DiffScript) hasConflict() bool {
+	return !d.IsNew() && d.Old != nil && *d.Old != d.New
+}
+
 // DiffTask is a diff of an individual task. This resource is always new.
 type DiffTask struct {
 	Name        string          `json:"name"`
@@ -524,6 +582,7 @@ type Summary struct {
 	Labels                []SummaryLabel                `json:"labels"`
 	LabelMappings         []SummaryLabelMapping         `json:"labelMappings"`
 	MissingSecrets        []string                      `json:"missingSecrets"`
+	Scripts               []SummaryScript               `json:"scripts"`
 	Tasks                 []SummaryTask                 `json:"summaryTask"`
 	TelegrafConfigs       []SummaryTelegraf             `json:"telegrafConfigs"`
 	Variables             []SummaryVariable             `json:"variables"`
@@ -536,8 +595,16 @@ type SummaryBucket struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	// TODO: return retention rules?
-	RetentionPeriod   time.Duration  `json:"retentionPeriod"`
-	LabelAssociations []SummaryLabel `json:"labelAssociations"`
+	RetentionPeriod    time.Duration              `json:"retentionPeriod"`
+	SchemaType         string                     `json:"schemaType,omitempty"`
+	MeasurementSchemas []SummaryMeasurementSchema `json:"measurementSchemas,omitempty"`
+	LabelAssociations  []SummaryLabel             `json:"labelAssociations"`
+}
+
+// SummaryMeasurementSchema provides a summary of a measurement schema of a pkg bucket.
+type SummaryMeasurementSchema struct {
+	Name    string                             `json:"name"`
+	Columns []influxdb.MeasurementSchemaColumn `json:"columns"`
 }
 
 // SummaryCheck provides a summary of a pkg check.
@@ -735,6 +802,16 @@ type SummaryLabelMapping struct {
 	LabelID      SafeID                `json:"labelID"`
 }
 
+// SummaryScript provides a summary of a pkg script.
+type SummaryScript struct {
+	ID                SafeID         `json:"id,omitempty"`
+	OrgID             SafeID         `json:"orgID,omitempty"`
+	Name              string         `json:"name"`
+	Description       string         `json:"description"`
+	Script            string         `json:"script"`
+	LabelAssociations []SummaryLabel `json:"labelAssociations"`
+}
+
 // SummaryTask provides a summary of a task.
 type SummaryTask struct {
 	ID          SafeID          `json:"id"`
@@ -789,16 +866,29 @@ const (
 )
 
 const (
-	fieldBucketRetentionRules = "retentionRules"
+	fieldBucketMeasurementSchemas = "measurementSchemas"
+	fieldBucketRetentionRules     = "retentionRules"
+	fieldBucketSchemaType         = "schemaType"
+)
+
+const (
+	fieldMeasurementSchemaColumns        = "columns"
+	fieldMeasurementSchemaColumnDataType = "dataType"
 )
 
 type bucket struct {
-	id             influxdb.ID
-	OrgID          influxdb.ID
-	Description    string
-	name           string
-	RetentionRules retentionRules
-	labels         sortedLabels
+	id                 influxdb.ID
+	OrgID              influxdb.ID
+	Description        string
+	name               string
+	RetentionRules     retentionRules
+	SchemaType         influxdb.SchemaType
+	MeasurementSchemas measurementSchemas
+	labels             sortedLabels
+
+	// createdSchemas are the IDs of the measurement schemas created when
+	// the bucket was applied, which are removed on rollback.
+	createdSchemas []influxdb.ID
 
 	// existing provides context for a resource that already
 	// exists in the platform. If a resource already exists
@@ -831,24 +921,90 @@ func (b *bucket) Exists() bool {
 
 func (b *bucket) summarize() SummaryBucket {
 	return SummaryBucket{
-		ID:                SafeID(b.ID()),
-		OrgID:             SafeID(b.OrgID),
-		Name:              b.Name(),
-		Description:       b.Description,
-		RetentionPeriod:   b.RetentionRules.RP(),
-		LabelAssociations: toSummaryLabels(b.labels...),
+		ID:                 SafeID(b.ID()),
+		OrgID:              SafeID(b.OrgID),
+		Name:               b.Name(),
+		Description:        b.Description,
+		RetentionPeriod:    b.RetentionRules.RP(),
+		SchemaType:         schemaTypeString(b.SchemaType),
+		MeasurementSchemas: b.MeasurementSchemas.summarize(),
+		LabelAssociations:  toSummaryLabels(b.labels...),
 	}
 }
 
 func (b *bucket) valid() []validationErr {
-	return b.RetentionRules.valid()
+	failures := b.RetentionRules.valid()
+	if len(b.MeasurementSchemas) > 0 && b.SchemaType != influxdb.SchemaTypeExplicit {
+		failures = append(failures, validationErr{
+			Field: fieldBucketMeasurementSchemas,
+			Msg:   "measurement schemas require a bucket with an explicit schema type",
+		})
+	}
+	return append(failures, b.MeasurementSchemas.valid()...)
 }
 
 func (b *bucket) shouldApply() bool {
 	return b.existing == nil ||
 		b.Description != b.existing.Description ||
 		b.Name() != b.existing.Name ||
-		b.RetentionRules.RP() != b.existing.RetentionPeriod
+		b.RetentionRules.RP() != b.existing.RetentionPeriod ||
+		len(b.MeasurementSchemas) > 0
+}
+
+// schemaTypeString returns the name of an explicit schema type, leaving
+// the default implicit schema type empty.
+func schemaTypeString(st influxdb.SchemaType) string {
+	if st == influxdb.SchemaTypeImplicit {
+		return ""
+	}
+	return st.String()
+}
+
+type measurementSchema struct {
+	Name    string
+	Columns []influxdb.MeasurementSchemaColumn
+}
+
+type measurementSchemas []measurementSchema
+
+func (m measurementSchemas) summarize() []SummaryMeasurementSchema {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make([]SummaryMeasurementSchema, 0, len(m))
+	for _, ms := range m {
+		out = append(out, SummaryMeasurementSchema{
+			Name:    ms.Name,
+			Columns: ms.Columns,
+		})
+	}
+	return out
+}
+
+func (m measurementSchemas) valid() []validationErr {
+	var failures []validationErr
+	seen := make(map[string]bool)
+	for i, ms := range m {
+		if seen[ms.Name] {
+			failures = append(failures, validationErr{
+				Field: fieldBucketMeasurementSchemas,
+				Index: intPtr(i),
+				Msg:   "duplicate measurement schema name: " + ms.Name,
+			})
+			continue
+		}
+		seen[ms.Name] = true
+
+		schema := influxdb.MeasurementSchema{Name: ms.Name, Columns: ms.Columns}
+		if err := schema.Validate(); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldBucketMeasurementSchemas,
+				Index: intPtr(i),
+				Msg:   influxdb.ErrorMessage(err),
+			})
+		}
+	}
+	return failures
 }
 
 type mapperBuckets []*bucket
@@ -1907,6 +2063,82 @@ func (m mapperTasks) Len() int {
 	return len(m)
 }
 
+const (
+	fieldScript = "script"
+)
+
+type script struct {
+	id          influxdb.ID
+	OrgID       influxdb.ID
+	name        string
+	Description string
+	Script      string
+
+	labels sortedLabels
+
+	existing *influxdb.Script
+}
+
+func (s *script) ID() influxdb.ID {
+	if s.existing != nil {
+		return s.existing.ID
+	}
+	return s.id
+}
+
+func (s *script) Exists() bool {
+	return s.existing != nil
+}
+
+func (s *script) Labels() []*label {
+	return s.labels
+}
+
+func (s *script) Name() string {
+	return s.name
+}
+
+func (s *script) ResourceType() influxdb.ResourceType {
+	return KindScript.ResourceType()
+}
+
+func (s *script) shouldApply() bool {
+	return s.existing == nil ||
+		s.existing.Description != s.Description ||
+		s.existing.Script != s.Script
+}
+
+func (s *script) summarize() SummaryScript {
+	return SummaryScript{
+		ID:                SafeID(s.ID()),
+		OrgID:             SafeID(s.OrgID),
+		Name:              s.Name(),
+		Description:       s.Description,
+		Script:            s.Script,
+		LabelAssociations: toSummaryLabels(s.labels...),
+	}
+}
+
+func (s *script) valid() []validationErr {
+	if s.Script == "" {
+		return []validationErr{{
+			Field: fieldScript,
+			Msg:   "must provide a non zero value",
+		}}
+	}
+	return nil
+}
+
+type mapperScripts []*script
+
+func (m mapperScripts) Association(i int) labelAssociater {
+	return m[i]
+}
+
+func (m mapperScripts) Len() int {
+	return len(m)
+}
+
 const (
 	fieldTelegrafConfig = "config"
 )
@@ -236,6 +236,7 @@ type Pkg struct {
 	mDashboards            []*dashboard
 	mNotificationEndpoints map[string]*notificationEndpoint
 	mNotificationRules     []*notificationRule
+	mScripts               map[string]*script
 	mTasks                 []*task
 	mTelegrafs             []*telegraf
 	mVariables             map[string]*variable
@@ -287,6 +288,7 @@ func (p *Pkg) Summary() Summary {
 		NotificationRules:     []SummaryNotificationRule{},
 		Labels:                []SummaryLabel{},
 		MissingSecrets:        []string{},
+		Scripts:               []SummaryScript{},
 		Tasks:                 []SummaryTask{},
 		TelegrafConfigs:       []SummaryTelegraf{},
 		Variables:             []SummaryVariable{},
@@ -323,6 +325,10 @@ func (p *Pkg) Summary() Summary {
 		sum.NotificationRules = append(sum.NotificationRules, r.summarize())
 	}
 
+	for _, s := range p.scripts() {
+		sum.Scripts = append(sum.Scripts, s.summarize())
+	}
+
 	for _, t := range p.tasks() {
 		sum.Tasks = append(sum.Tasks, t.summarize())
 	}
@@ -475,6 +481,17 @@ func (p *Pkg) missingSecrets() []string {
 	return secrets
 }
 
+func (p *Pkg) scripts() []*script {
+	scripts := make([]*script, 0, len(p.mScripts))
+	for _, s := range p.mScripts {
+		scripts = append(scripts, s)
+	}
+
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].Name() < scripts[j].Name() })
+
+	return scripts
+}
+
 func (p *Pkg) tasks() []*task {
 	tasks := p.mTasks[:]
 
@@ -561,6 +578,7 @@ func (p *Pkg) graphResources() error {
 		p.graphDashboards,
 		p.graphNotificationEndpoints,
 		p.graphNotificationRules,
+		p.graphScripts,
 		p.graphTasks,
 		p.graphTelegrafs,
 	}
@@ -608,11 +626,33 @@ func (p *Pkg) graphBuckets() *parseErr {
 			}
 		}
 
-		failures := p.parseNestedLabels(k.Spec, func(l *label) error {
+		var failures []validationErr
+		schemaType, err := influxdb.ParseSchemaType(k.Spec.stringShort(fieldBucketSchemaType))
+		if err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldBucketSchemaType,
+				Msg:   influxdb.ErrorMessage(err),
+			})
+		}
+		bkt.SchemaType = schemaType
+
+		for _, ms := range k.Spec.slcResource(fieldBucketMeasurementSchemas) {
+			schema := measurementSchema{Name: ms.Name()}
+			for _, c := range ms.slcResource(fieldMeasurementSchemaColumns) {
+				schema.Columns = append(schema.Columns, influxdb.MeasurementSchemaColumn{
+					Name:     c.Name(),
+					Type:     influxdb.SemanticColumnType(c.stringShort(fieldType)),
+					DataType: influxdb.SchemaColumnDataType(c.stringShort(fieldMeasurementSchemaColumnDataType)),
+				})
+			}
+			bkt.MeasurementSchemas = append(bkt.MeasurementSchemas, schema)
+		}
+
+		failures = append(failures, p.parseNestedLabels(k.Spec, func(l *label) error {
 			bkt.labels = append(bkt.labels, l)
 			p.mLabels[l.Name()].setMapping(bkt, false)
 			return nil
-		})
+		})...)
 		sort.Sort(bkt.labels)
 
 		p.mBuckets[k.Name()] = bkt
@@ -857,6 +897,35 @@ func (p *Pkg) graphNotificationRules() *parseErr {
 	})
 }
 
+func (p *Pkg) graphScripts() *parseErr {
+	p.mScripts = make(map[string]*script)
+	return p.eachResource(KindScript, 1, func(k Object) []validationErr {
+		if _, ok := p.mScripts[k.Name()]; ok {
+			return []validationErr{{
+				Field: "name",
+				Msg:   "duplicate name: " + k.Name(),
+			}}
+		}
+
+		s := &script{
+			name:        k.Name(),
+			Description: k.Spec.stringShort(fieldDescription),
+			Script:      strings.TrimSpace(k.Spec.stringShort(fieldScript)),
+		}
+
+		failures := p.parseNestedLabels(k.Spec, func(l *label) error {
+			s.labels = append(s.labels, l)
+			p.mLabels[l.Name()].setMapping(s, false)
+			return nil
+		})
+		sort.Sort(s.labels)
+
+		p.mScripts[k.Name()] = s
+
+		return append(failures, s.valid()...)
+	})
+}
+
 func (p *Pkg) graphTasks() *parseErr {
 	p.mTasks = make([]*task, 0)
 	return p.eachResource(KindTask, 1, func(k Object) []validationErr {
@@ -34,6 +34,31 @@ func TestParse(t *testing.T) {
 			})
 		})
 
+		t.Run("with explicit schema bucket pkg should be valid", func(t *testing.T) {
+			testfileRunner(t, "testdata/bucket_schema", func(t *testing.T, pkg *Pkg) {
+				buckets := pkg.Summary().Buckets
+				require.Len(t, buckets, 1)
+
+				actual := buckets[0]
+				expectedBucket := SummaryBucket{
+					Name:       "rucket_explicit",
+					SchemaType: "explicit",
+					MeasurementSchemas: []SummaryMeasurementSchema{
+						{
+							Name: "cpu",
+							Columns: []influxdb.MeasurementSchemaColumn{
+								{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+								{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+								{Name: "usage_user", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+							},
+						},
+					},
+					LabelAssociations: []SummaryLabel{},
+				}
+				assert.Equal(t, expectedBucket, actual)
+			})
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []testPkgResourceError{
 				{
@@ -96,6 +121,37 @@ apiVersion: influxdata.com/v2alpha1
 kind: Bucket
 metadata:
   name:  valid name
+`,
+				},
+				{
+					name:           "invalid schema type",
+					validationErrs: 1,
+					valFields:      []string{fieldBucketSchemaType},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Bucket
+metadata:
+  name:  rucket_11
+spec:
+  schemaType: strict
+`,
+				},
+				{
+					name:           "measurement schemas on implicit bucket",
+					validationErrs: 1,
+					valFields:      []string{fieldBucketMeasurementSchemas},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Bucket
+metadata:
+  name:  rucket_11
+spec:
+  measurementSchemas:
+    - name: cpu
+      columns:
+        - name: time
+          type: timestamp
+        - name: usage_user
+          type: field
+          dataType: float
 `,
 				},
 			}
@@ -3047,6 +3103,85 @@ spec:
 		})
 	})
 
+	t.Run("pkg with scripts", func(t *testing.T) {
+		t.Run("happy path", func(t *testing.T) {
+			testfileRunner(t, "testdata/scripts", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				scripts := sum.Scripts
+				require.Len(t, scripts, 2)
+
+				expectedScript := "from(bucket: params.bucket)\n  |> range(start: -1h)\n  |> filter(fn: (r) => r._measurement == \"cpu\")"
+				for i, actual := range scripts {
+					assert.Equal(t, "script_"+strconv.Itoa(i), actual.Name)
+					assert.Equal(t, "desc_"+strconv.Itoa(i), actual.Description)
+					assert.Equal(t, expectedScript, actual.Script)
+
+					require.Len(t, actual.LabelAssociations, 1)
+					assert.Equal(t, "label_1", actual.LabelAssociations[0].Name)
+				}
+
+				require.Len(t, sum.LabelMappings, 2)
+				expectedMapping := SummaryLabelMapping{
+					ResourceName: "script_0",
+					LabelName:    "label_1",
+					ResourceType: influxdb.ScriptsResourceType,
+				}
+				assert.Equal(t, expectedMapping, sum.LabelMappings[0])
+			})
+		})
+
+		t.Run("handles bad config", func(t *testing.T) {
+			tests := []struct {
+				kind   Kind
+				resErr testPkgResourceError
+			}{
+				{
+					kind: KindScript,
+					resErr: testPkgResourceError{
+						name:           "missing script",
+						validationErrs: 1,
+						valFields:      []string{fieldScript},
+						pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Script
+metadata:
+  name: script_0
+spec:
+  description: desc_0
+`,
+					},
+				},
+				{
+					kind: KindScript,
+					resErr: testPkgResourceError{
+						name:           "duplicate name",
+						validationErrs: 1,
+						valFields:      []string{fieldName},
+						pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Script
+metadata:
+  name: script_0
+spec:
+  script: |
+    from(bucket: "rucket_1") |> range(start: -1h)
+---
+apiVersion: influxdata.com/v2alpha1
+kind: Script
+metadata:
+  name: script_0
+spec:
+  script: |
+    from(bucket: "rucket_1") |> range(start: -1h)
+`,
+					},
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, tt.kind, tt.resErr)
+			}
+		})
+	})
+
 	t.Run("pkg with telegraf and label associations", func(t *testing.T) {
 		t.Run("with valid fields", func(t *testing.T) {
 			testfileRunner(t, "testdata/telegraf", func(t *testing.T, pkg *Pkg) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -32,16 +33,18 @@ type serviceOpt struct {
 
 	applyReqLimit int
 
-	bucketSVC   influxdb.BucketService
-	checkSVC    influxdb.CheckService
-	dashSVC     influxdb.DashboardService
-	labelSVC    influxdb.LabelService
-	endpointSVC influxdb.NotificationEndpointService
-	ruleSVC     influxdb.NotificationRuleStore
-	secretSVC   influxdb.SecretService
-	taskSVC     influxdb.TaskService
-	teleSVC     influxdb.TelegrafConfigStore
-	varSVC      influxdb.VariableService
+	bucketSVC       influxdb.BucketService
+	bucketSchemaSVC influxdb.BucketSchemaService
+	checkSVC        influxdb.CheckService
+	dashSVC         influxdb.DashboardService
+	labelSVC        influxdb.LabelService
+	endpointSVC     influxdb.NotificationEndpointService
+	ruleSVC         influxdb.NotificationRuleStore
+	scriptSVC       influxdb.ScriptService
+	secretSVC       influxdb.SecretService
+	taskSVC         influxdb.TaskService
+	teleSVC         influxdb.TelegrafConfigStore
+	varSVC          influxdb.VariableService
 }
 
 // ServiceSetterFn is a means of setting dependencies on the Service type.
@@ -61,6 +64,13 @@ func WithBucketSVC(bktSVC influxdb.BucketService) ServiceSetterFn {
 	}
 }
 
+// WithBucketSchemaSVC sets the bucket schema service.
+func WithBucketSchemaSVC(schemaSVC influxdb.BucketSchemaService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.bucketSchemaSVC = schemaSVC
+	}
+}
+
 // WithCheckSVC sets the check service.
 func WithCheckSVC(checkSVC influxdb.CheckService) ServiceSetterFn {
 	return func(opt *serviceOpt) {
@@ -96,6 +106,13 @@ func WithLabelSVC(labelSVC influxdb.LabelService) ServiceSetterFn {
 	}
 }
 
+// WithScriptSVC sets the script service.
+func WithScriptSVC(scriptSVC influxdb.ScriptService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.scriptSVC = scriptSVC
+	}
+}
+
 // WithSecretSVC sets the secret service.
 func WithSecretSVC(secretSVC influxdb.SecretService) ServiceSetterFn {
 	return func(opt *serviceOpt) {
@@ -129,16 +146,18 @@ func WithVariableSVC(varSVC influxdb.VariableService) ServiceSetterFn {
 type Service struct {
 	log *zap.Logger
 
-	bucketSVC   influxdb.BucketService
-	checkSVC    influxdb.CheckService
-	dashSVC     influxdb.DashboardService
-	labelSVC    influxdb.LabelService
-	endpointSVC influxdb.NotificationEndpointService
-	ruleSVC     influxdb.NotificationRuleStore
-	secretSVC   influxdb.SecretService
-	taskSVC     influxdb.TaskService
-	teleSVC     influxdb.TelegrafConfigStore
-	varSVC      influxdb.VariableService
+	bucketSVC       influxdb.BucketService
+	bucketSchemaSVC influxdb.BucketSchemaService
+	checkSVC        influxdb.CheckService
+	dashSVC         influxdb.DashboardService
+	labelSVC        influxdb.LabelService
+	endpointSVC     influxdb.NotificationEndpointService
+	ruleSVC         influxdb.NotificationRuleStore
+	scriptSVC       influxdb.ScriptService
+	secretSVC       influxdb.SecretService
+	taskSVC         influxdb.TaskService
+	teleSVC         influxdb.TelegrafConfigStore
+	varSVC          influxdb.VariableService
 
 	applyReqLimit int
 }
@@ -156,18 +175,20 @@ func NewService(opts ...ServiceSetterFn) *Service {
 	}
 
 	return &Service{
-		log:           opt.logger,
-		bucketSVC:     opt.bucketSVC,
-		checkSVC:      opt.checkSVC,
-		labelSVC:      opt.labelSVC,
-		dashSVC:       opt.dashSVC,
-		endpointSVC:   opt.endpointSVC,
-		ruleSVC:       opt.ruleSVC,
-		secretSVC:     opt.secretSVC,
-		taskSVC:       opt.taskSVC,
-		teleSVC:       opt.teleSVC,
-		varSVC:        opt.varSVC,
-		applyReqLimit: opt.applyReqLimit,
+		log:             opt.logger,
+		bucketSVC:       opt.bucketSVC,
+		bucketSchemaSVC: opt.bucketSchemaSVC,
+		checkSVC:        opt.checkSVC,
+		labelSVC:        opt.labelSVC,
+		dashSVC:         opt.dashSVC,
+		endpointSVC:     opt.endpointSVC,
+		ruleSVC:         opt.ruleSVC,
+		scriptSVC:       opt.scriptSVC,
+		secretSVC:       opt.secretSVC,
+		taskSVC:         opt.taskSVC,
+		teleSVC:         opt.teleSVC,
+		varSVC:          opt.varSVC,
+		applyReqLimit:   opt.applyReqLimit,
 	}
 }
 
@@ -256,6 +277,7 @@ func (s *Service) CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pk
 		KindVariable:                      9,
 		KindTelegraf:                      10,
 		KindDashboard:                     11,
+		KindScript:                        12,
 	}
 
 	sort.Slice(pkg.Objects, func(i, j int) bool {
@@ -300,6 +322,10 @@ func (s *Service) cloneOrgResources(ctx context.Context, orgID influxdb.ID) ([]R
 			resType: KindNotificationRule.ResourceType(),
 			cloneFn: s.cloneOrgNotificationRules,
 		},
+		{
+			resType: KindScript.ResourceType(),
+			cloneFn: s.cloneOrgScripts,
+		},
 		{
 			resType: KindTask.ResourceType(),
 			cloneFn: s.cloneOrgTasks,
@@ -437,6 +463,26 @@ func (s *Service) cloneOrgNotificationRules(ctx context.Context, orgID influxdb.
 	return resources, nil
 }
 
+func (s *Service) cloneOrgScripts(ctx context.Context, orgID influxdb.ID) ([]ResourceToClone, error) {
+	if s.scriptSVC == nil {
+		return nil, nil
+	}
+
+	scripts, _, err := s.scriptSVC.FindScripts(ctx, influxdb.ScriptFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]ResourceToClone, 0, len(scripts))
+	for _, sc := range scripts {
+		resources = append(resources, ResourceToClone{
+			Kind: KindScript,
+			ID:   sc.ID,
+		})
+	}
+	return resources, nil
+}
+
 func (s *Service) cloneOrgTasks(ctx context.Context, orgID influxdb.ID) ([]ResourceToClone, error) {
 	teles, _, err := s.taskSVC.FindTasks(ctx, influxdb.TaskFilter{OrganizationID: &orgID})
 	if err != nil {
@@ -528,7 +574,14 @@ func (s *Service) resourceCloneToKind(ctx context.Context, r ResourceToClone, cF
 		if err != nil {
 			return nil, err
 		}
-		newKind = bucketToObject(*bkt, r.Name)
+		var schemas []*influxdb.MeasurementSchema
+		if bkt.SchemaType == influxdb.SchemaTypeExplicit && s.bucketSchemaSVC != nil {
+			schemas, _, err = s.bucketSchemaSVC.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bkt.ID})
+			if err != nil {
+				return nil, err
+			}
+		}
+		newKind = bucketToObject(*bkt, r.Name, schemas...)
 	case r.Kind.is(KindCheck),
 		r.Kind.is(KindCheckDeadman),
 		r.Kind.is(KindCheckThreshold):
@@ -564,6 +617,12 @@ func (s *Service) resourceCloneToKind(ctx context.Context, r ResourceToClone, cF
 			return nil, err
 		}
 		newKind, sidecarKinds = ruleRes, append(sidecarKinds, endpointRes)
+	case r.Kind.is(KindScript):
+		sc, err := s.scriptSVC.FindScriptByID(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		newKind = scriptToObject(*sc, r.Name)
 	case r.Kind.is(KindTask):
 		t, err := s.taskSVC.FindTaskByID(ctx, r.ID)
 		if err != nil {
@@ -683,6 +742,7 @@ func (s *Service) DryRun(ctx context.Context, orgID, userID influxdb.ID, pkg *Pk
 		Checks:     s.dryRunChecks(ctx, orgID, pkg),
 		Dashboards: s.dryRunDashboards(pkg),
 		Labels:     s.dryRunLabels(ctx, orgID, pkg),
+		Scripts:    s.dryRunScripts(ctx, orgID, pkg),
 		Tasks:      s.dryRunTasks(pkg),
 		Telegrafs:  s.dryRunTelegraf(pkg),
 		Variables:  s.dryRunVariables(ctx, orgID, pkg),
@@ -724,7 +784,19 @@ func (s *Service) dryRunBuckets(ctx context.Context, orgID influxdb.ID, pkg *Pkg
 		//  err isn't a not found (some other error)
 		case nil:
 			b.existing = existingBkt
-			mExistingBkts[b.Name()] = newDiffBucket(b, existingBkt)
+			diff := newDiffBucket(b, existingBkt)
+			if existingBkt.SchemaType == influxdb.SchemaTypeExplicit && s.bucketSchemaSVC != nil {
+				schemas, _, err := s.bucketSchemaSVC.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: existingBkt.ID})
+				if err == nil {
+					for _, m := range schemas {
+						diff.Old.MeasurementSchemas = append(diff.Old.MeasurementSchemas, SummaryMeasurementSchema{
+							Name:    m.Name,
+							Columns: m.Columns,
+						})
+					}
+				}
+			}
+			mExistingBkts[b.Name()] = diff
 		default:
 			mExistingBkts[b.Name()] = newDiffBucket(b, nil)
 		}
@@ -898,6 +970,26 @@ func (s *Service) dryRunSecrets(ctx context.Context, orgID influxdb.ID, pkg *Pkg
 	return nil
 }
 
+func (s *Service) dryRunScripts(ctx context.Context, orgID influxdb.ID, pkg *Pkg) []DiffScript {
+	scripts := pkg.scripts()
+	diffs := make([]DiffScript, 0, len(scripts))
+	for _, sc := range scripts {
+		name := sc.Name()
+		existing, _, err := s.scriptSVC.FindScripts(ctx, influxdb.ScriptFilter{
+			OrgID: &orgID,
+			Name:  &name,
+		})
+		switch {
+		case err == nil && len(existing) > 0:
+			sc.existing = existing[0]
+			diffs = append(diffs, newDiffScript(sc, existing[0]))
+		default:
+			diffs = append(diffs, newDiffScript(sc, nil))
+		}
+	}
+	return diffs
+}
+
 func (s *Service) dryRunTasks(pkg *Pkg) []DiffTask {
 	var diffs []DiffTask
 	for _, t := range pkg.tasks() {
@@ -982,6 +1074,7 @@ func (s *Service) dryRunLabelMappings(ctx context.Context, pkg *Pkg) ([]DiffLabe
 		mapperDashboards(pkg.mDashboards),
 		mapperNotificationEndpoints(pkg.notificationEndpoints()),
 		mapperNotificationRules(pkg.mNotificationRules),
+		mapperScripts(pkg.scripts()),
 		mapperTasks(pkg.mTasks),
 		mapperTelegrafs(pkg.mTelegrafs),
 		mapperVariables(pkg.variables()),
@@ -1132,6 +1225,7 @@ func (s *Service) Apply(ctx context.Context, orgID, userID influxdb.ID, pkg *Pkg
 			s.applyChecks(pkg.checks()),
 			s.applyDashboards(pkg.dashboards()),
 			s.applyNotificationEndpoints(pkg.notificationEndpoints()),
+			s.applyScripts(pkg.scripts()),
 			s.applyTasks(pkg.tasks()),
 			s.applyTelegrafs(pkg.telegrafs()),
 		},
@@ -1189,10 +1283,18 @@ func (s *Service) applyBuckets(buckets []*bucket) applier {
 			}
 		}
 
+		createdSchemas, err := s.applyMeasurementSchemas(ctx, influxBucket.ID, b.MeasurementSchemas)
 		mutex.Do(func() {
 			buckets[i].id = influxBucket.ID
+			buckets[i].createdSchemas = createdSchemas
 			rollbackBuckets = append(rollbackBuckets, buckets[i])
 		})
+		if err != nil {
+			return &applyErrBody{
+				name: b.Name(),
+				msg:  err.Error(),
+			}
+		}
 
 		return nil
 	}
@@ -1220,6 +1322,12 @@ func (s *Service) rollbackBuckets(buckets []*bucket) error {
 			continue
 		}
 
+		for _, id := range b.createdSchemas {
+			if err := s.bucketSchemaSVC.DeleteMeasurementSchema(context.Background(), b.ID(), id); err != nil {
+				errs = append(errs, b.ID().String())
+			}
+		}
+
 		rp := b.RetentionRules.RP()
 		_, err := s.bucketSVC.UpdateBucket(context.Background(), b.ID(), influxdb.BucketUpdate{
 			Description:     &b.Description,
@@ -1241,6 +1349,13 @@ func (s *Service) rollbackBuckets(buckets []*bucket) error {
 func (s *Service) applyBucket(ctx context.Context, b bucket) (influxdb.Bucket, error) {
 	rp := b.RetentionRules.RP()
 	if b.existing != nil {
+		if b.existing.SchemaType != b.SchemaType {
+			return influxdb.Bucket{}, &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "schema type of an existing bucket may not be changed",
+			}
+		}
+
 		influxBucket, err := s.bucketSVC.UpdateBucket(ctx, b.ID(), influxdb.BucketUpdate{
 			Description:     &b.Description,
 			RetentionPeriod: &rp,
@@ -1256,6 +1371,7 @@ func (s *Service) applyBucket(ctx context.Context, b bucket) (influxdb.Bucket, e
 		Description:     b.Description,
 		Name:            b.Name(),
 		RetentionPeriod: rp,
+		SchemaType:      b.SchemaType,
 	}
 	err := s.bucketSVC.CreateBucket(ctx, &influxBucket)
 	if err != nil {
@@ -1265,6 +1381,54 @@ func (s *Service) applyBucket(ctx context.Context, b bucket) (influxdb.Bucket, e
 	return influxBucket, nil
 }
 
+// applyMeasurementSchemas creates the measurement schemas of a bucket, or adds
+// columns to those that already exist. The IDs of the created schemas are returned
+// so that they can be rolled back.
+func (s *Service) applyMeasurementSchemas(ctx context.Context, bucketID influxdb.ID, schemas measurementSchemas) ([]influxdb.ID, error) {
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	if s.bucketSchemaSVC == nil {
+		return nil, errors.New("measurement schemas are not supported")
+	}
+
+	var created []influxdb.ID
+	for i := range schemas {
+		name := schemas[i].Name
+		existing, _, err := s.bucketSchemaSVC.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{
+			BucketID: bucketID,
+			Name:     &name,
+		})
+		if err != nil {
+			return created, err
+		}
+
+		if len(existing) > 0 {
+			if reflect.DeepEqual(existing[0].Columns, schemas[i].Columns) {
+				continue
+			}
+			_, err := s.bucketSchemaSVC.UpdateMeasurementSchema(ctx, bucketID, existing[0].ID, influxdb.MeasurementSchemaUpdate{
+				Columns: schemas[i].Columns,
+			})
+			if err != nil {
+				return created, err
+			}
+			continue
+		}
+
+		m := &influxdb.MeasurementSchema{
+			BucketID: bucketID,
+			Name:     name,
+			Columns:  schemas[i].Columns,
+		}
+		if err := s.bucketSchemaSVC.CreateMeasurementSchema(ctx, m); err != nil {
+			return created, err
+		}
+		created = append(created, m.ID)
+	}
+	return created, nil
+}
+
 func (s *Service) applyChecks(checks []*check) applier {
 	const resource = "check"
 
@@ -1875,6 +2039,99 @@ func (s *Service) applyTelegrafs(teles []*telegraf) applier {
 	}
 }
 
+func (s *Service) applyScripts(scripts []*script) applier {
+	const resource = "script"
+
+	mutex := new(doMutex)
+	rollbackScripts := make([]*script, 0, len(scripts))
+
+	createFn := func(ctx context.Context, i int, orgID, userID influxdb.ID) *applyErrBody {
+		var sc script
+		mutex.Do(func() {
+			scripts[i].OrgID = orgID
+			sc = *scripts[i]
+		})
+		if !sc.shouldApply() {
+			return nil
+		}
+
+		influxScript, err := s.applyScript(ctx, sc)
+		if err != nil {
+			return &applyErrBody{
+				name: sc.Name(),
+				msg:  err.Error(),
+			}
+		}
+
+		mutex.Do(func() {
+			scripts[i].id = influxScript.ID
+			rollbackScripts = append(rollbackScripts, scripts[i])
+		})
+		return nil
+	}
+
+	return applier{
+		creater: creater{
+			entries: len(scripts),
+			fn:      createFn,
+		},
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func(_ influxdb.ID) error { return s.rollbackScripts(rollbackScripts) },
+		},
+	}
+}
+
+func (s *Service) rollbackScripts(scripts []*script) error {
+	var errs []string
+	for _, sc := range scripts {
+		if sc.existing == nil {
+			if err := s.scriptSVC.DeleteScript(context.Background(), sc.ID()); err != nil {
+				errs = append(errs, sc.ID().String())
+			}
+			continue
+		}
+
+		_, err := s.scriptSVC.UpdateScript(context.Background(), sc.ID(), influxdb.ScriptUpdate{
+			Description: &sc.existing.Description,
+			Script:      &sc.existing.Script,
+		})
+		if err != nil {
+			errs = append(errs, sc.ID().String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(`script_ids=[%s] err="unable to delete script"`, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+func (s *Service) applyScript(ctx context.Context, sc script) (influxdb.Script, error) {
+	if sc.existing != nil {
+		updated, err := s.scriptSVC.UpdateScript(ctx, sc.ID(), influxdb.ScriptUpdate{
+			Description: &sc.Description,
+			Script:      &sc.Script,
+		})
+		if err != nil {
+			return influxdb.Script{}, err
+		}
+		return *updated, nil
+	}
+
+	influxScript := influxdb.Script{
+		OrgID:       sc.OrgID,
+		Name:        sc.Name(),
+		Description: sc.Description,
+		Script:      sc.Script,
+	}
+	if err := s.scriptSVC.CreateScript(ctx, &influxScript); err != nil {
+		return influxdb.Script{}, err
+	}
+	return influxScript, nil
+}
+
 func (s *Service) applyVariables(vars []*variable) applier {
 	const resource = "variable"
 
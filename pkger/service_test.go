@@ -31,6 +31,7 @@ func TestService(t *testing.T) {
 			labelSVC:    mock.NewLabelService(),
 			endpointSVC: mock.NewNotificationEndpointService(),
 			ruleSVC:     mock.NewNotificationRuleStore(),
+			scriptSVC:   mock.NewScriptService(),
 			taskSVC:     mock.NewTaskService(),
 			teleSVC:     mock.NewTelegrafConfigStore(),
 			varSVC:      mock.NewVariableService(),
@@ -46,6 +47,7 @@ func TestService(t *testing.T) {
 			WithLabelSVC(opt.labelSVC),
 			WithNotificationEndpointSVC(opt.endpointSVC),
 			WithNotificationRuleSVC(opt.ruleSVC),
+			WithScriptSVC(opt.scriptSVC),
 			WithSecretSVC(opt.secretSVC),
 			WithTaskSVC(opt.taskSVC),
 			WithTelegrafSVC(opt.teleSVC),
@@ -1277,6 +1279,65 @@ func TestService(t *testing.T) {
 			})
 		})
 
+		t.Run("scripts", func(t *testing.T) {
+			t.Run("successfully creates pkg of scripts", func(t *testing.T) {
+				testfileRunner(t, "testdata/scripts.yml", func(t *testing.T, pkg *Pkg) {
+					fakeScriptSVC := mock.NewScriptService()
+					fakeScriptSVC.CreateScriptFn = func(_ context.Context, sc *influxdb.Script) error {
+						id, err := strconv.Atoi(sc.Name[len(sc.Name)-1:])
+						if err != nil {
+							return err
+						}
+						sc.ID = influxdb.ID(id + 1)
+						return nil
+					}
+
+					svc := newTestService(WithScriptSVC(fakeScriptSVC))
+
+					orgID := influxdb.ID(9000)
+
+					sum, err := svc.Apply(context.TODO(), orgID, 0, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.Scripts, 2)
+					for i, actual := range sum.Scripts {
+						assert.Equal(t, SafeID(i+1), actual.ID)
+						assert.Equal(t, SafeID(orgID), actual.OrgID)
+						assert.Equal(t, "script_"+strconv.Itoa(i), actual.Name)
+						assert.Equal(t, "desc_"+strconv.Itoa(i), actual.Description)
+					}
+				})
+			})
+
+			t.Run("rolls back all created scripts on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/scripts.yml", func(t *testing.T, pkg *Pkg) {
+					fakeScriptSVC := mock.NewScriptService()
+					fakeScriptSVC.CreateScriptFn = func(_ context.Context, sc *influxdb.Script) error {
+						// script_1 fails, and script_0 before it should be rolled back
+						if sc.Name == "script_1" {
+							return errors.New("blowed up ")
+						}
+						sc.ID = 1
+						return nil
+					}
+					var deleted int
+					fakeScriptSVC.DeleteScriptFn = func(_ context.Context, id influxdb.ID) error {
+						deleted++
+						return nil
+					}
+
+					svc := newTestService(WithScriptSVC(fakeScriptSVC))
+
+					orgID := influxdb.ID(9000)
+
+					_, err := svc.Apply(context.TODO(), orgID, 0, pkg)
+					require.Error(t, err)
+
+					assert.Equal(t, 1, deleted)
+				})
+			})
+		})
+
 		t.Run("variables", func(t *testing.T) {
 			t.Run("successfully creates pkg of variables", func(t *testing.T) {
 				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
@@ -0,0 +1,279 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// Predicate returns the storage predicate which matches the series selected by
+// the label matchers. Label matchers on the metric name match the measurement,
+// and every series is limited to the field samples are written to. Regular
+// expressions are anchored at both ends, as they are by Prometheus.
+func Predicate(matchers []*LabelMatcher) (*datatypes.Predicate, error) {
+	root := comparisonNode(datatypes.ComparisonEqual, models.FieldKeyTagKey, stringLiteral(FieldName))
+	return matchersPredicate(root, matchers)
+}
+
+// SeriesPredicate returns the storage predicate which matches the series
+// selected by the label matchers, like Predicate, but of any field. It
+// returns nil if there are no matchers.
+func SeriesPredicate(matchers []*LabelMatcher) (*datatypes.Predicate, error) {
+	if len(matchers) == 0 {
+		return nil, nil
+	}
+	return matchersPredicate(nil, matchers)
+}
+
+// matchersPredicate returns the predicate of root and every label matcher.
+func matchersPredicate(root *datatypes.Node, matchers []*LabelMatcher) (*datatypes.Predicate, error) {
+	for _, m := range matchers {
+		key := m.Name
+		switch key {
+		case MetricNameLabel:
+			key = models.MeasurementTagKey
+		case fieldLabel:
+			key = models.FieldKeyTagKey
+		}
+
+		var n *datatypes.Node
+		switch m.Type {
+		case LabelMatcher_EQ:
+			n = comparisonNode(datatypes.ComparisonEqual, key, stringLiteral(m.Value))
+		case LabelMatcher_NEQ:
+			n = comparisonNode(datatypes.ComparisonNotEqual, key, stringLiteral(m.Value))
+		case LabelMatcher_RE, LabelMatcher_NRE:
+			re := "^(?:" + m.Value + ")$"
+			if _, err := regexp.Compile(re); err != nil {
+				return nil, fmt.Errorf("invalid regular expression for label %s: %v", m.Name, err)
+			}
+			op := datatypes.ComparisonRegex
+			if m.Type == LabelMatcher_NRE {
+				op = datatypes.ComparisonNotRegex
+			}
+			n = comparisonNode(op, key, &datatypes.Node{
+				NodeType: datatypes.NodeTypeLiteral,
+				Value:    &datatypes.Node_RegexValue{RegexValue: re},
+			})
+		default:
+			return nil, fmt.Errorf("unknown label matcher type %v", m.Type)
+		}
+
+		if root == nil {
+			root = n
+			continue
+		}
+		root = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+			Children: []*datatypes.Node{root, n},
+		}
+	}
+	return &datatypes.Predicate{Root: root}, nil
+}
+
+func comparisonNode(op datatypes.Node_Comparison, key string, literal *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: op},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			literal,
+		},
+	}
+}
+
+func stringLiteral(s string) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLiteral,
+		Value:    &datatypes.Node_StringValue{StringValue: s},
+	}
+}
+
+// ReadTimeSeries calls fn with every time series of the bucket that matches q.
+// Series are read with the ReadFilter method of store and passed to fn one at a
+// time, so that they can be encoded as they are read. Series with string or
+// boolean values are skipped, as Prometheus samples are floats.
+func ReadTimeSeries(ctx context.Context, store reads.Store, orgID, bucketID influxdb.ID, q *Query, fn func(*TimeSeries) error) error {
+	predicate, err := Predicate(q.Matchers)
+	if err != nil {
+		return err
+	}
+
+	src, err := types.MarshalAny(store.GetSource(uint64(orgID), uint64(bucketID)))
+	if err != nil {
+		return err
+	}
+
+	req := &datatypes.ReadFilterRequest{
+		ReadSource: src,
+		Predicate:  predicate,
+		Range: datatypes.TimestampRange{
+			Start: q.StartTimestampMs * int64(time.Millisecond),
+			// the end of a query is inclusive whereas the end of a range is not.
+			End: (q.EndTimestampMs + 1) * int64(time.Millisecond),
+		},
+	}
+
+	rs, err := store.ReadFilter(ctx, req)
+	if err != nil {
+		return err
+	}
+	if rs == nil {
+		return nil
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			continue
+		}
+
+		samples, err := readSamples(cur)
+		cur.Close()
+		if err != nil {
+			return err
+		}
+		if len(samples) == 0 {
+			continue
+		}
+
+		if err := fn(&TimeSeries{Labels: labels(rs.Tags()), Samples: samples}); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+// ReadSeries calls fn with the tags of every series of the bucket that matches
+// the label matchers and has a value between start and end, in nanoseconds,
+// of which end is exclusive. The series of every field are read, unlike those
+// of ReadTimeSeries, so that the caller decides which fields are labels.
+func ReadSeries(ctx context.Context, store reads.Store, orgID, bucketID influxdb.ID, matchers []*LabelMatcher, start, end int64, fn func(models.Tags) error) error {
+	predicate, err := SeriesPredicate(matchers)
+	if err != nil {
+		return err
+	}
+
+	src, err := types.MarshalAny(store.GetSource(uint64(orgID), uint64(bucketID)))
+	if err != nil {
+		return err
+	}
+
+	rs, err := store.ReadFilter(ctx, &datatypes.ReadFilterRequest{
+		ReadSource: src,
+		Predicate:  predicate,
+		Range:      datatypes.TimestampRange{Start: start, End: end},
+	})
+	if err != nil {
+		return err
+	}
+	if rs == nil {
+		return nil
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			continue
+		}
+		ok, err := hasValues(cur)
+		cur.Close()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(rs.Tags()); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+// hasValues returns true if the cursor has any value.
+func hasValues(cur cursors.Cursor) (bool, error) {
+	var n int
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		n = cur.Next().Len()
+	case cursors.IntegerArrayCursor:
+		n = cur.Next().Len()
+	case cursors.UnsignedArrayCursor:
+		n = cur.Next().Len()
+	case cursors.StringArrayCursor:
+		n = cur.Next().Len()
+	case cursors.BooleanArrayCursor:
+		n = cur.Next().Len()
+	default:
+		return false, errors.New("unsupported cursor type")
+	}
+	return n > 0, cur.Err()
+}
+
+// labels returns the labels of the series with the given tags, sorted by name.
+func labels(tags models.Tags) []*Label {
+	ls := make([]*Label, 0, len(tags))
+	for _, t := range tags {
+		switch string(t.Key) {
+		case models.MeasurementTagKey:
+			ls = append(ls, &Label{Name: MetricNameLabel, Value: string(t.Value)})
+		case models.FieldKeyTagKey:
+			// every sample is read from the same field.
+		default:
+			ls = append(ls, &Label{Name: string(t.Key), Value: string(t.Value)})
+		}
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].Name < ls[j].Name })
+	return ls
+}
+
+// readSamples reads every value of a float, integer or unsigned cursor as a
+// sample. It returns no samples for any other type of cursor.
+func readSamples(cur cursors.Cursor) ([]*Sample, error) {
+	var samples []*Sample
+	appendSamples := func(ts []int64, value func(i int) float64) {
+		for i := range ts {
+			samples = append(samples, &Sample{
+				Timestamp: ts[i] / int64(time.Millisecond),
+				Value:     value(i),
+			})
+		}
+	}
+
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			appendSamples(a.Timestamps, func(i int) float64 { return a.Values[i] })
+		}
+	case cursors.IntegerArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			appendSamples(a.Timestamps, func(i int) float64 { return float64(a.Values[i]) })
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			appendSamples(a.Timestamps, func(i int) float64 { return float64(a.Values[i]) })
+		}
+	case cursors.StringArrayCursor, cursors.BooleanArrayCursor:
+		return nil, nil
+	default:
+		return nil, errors.New("unsupported cursor type")
+	}
+	return samples, cur.Err()
+}
@@ -0,0 +1,199 @@
+package remote_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+func TestPredicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchers []*remote.LabelMatcher
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "no matchers selects the value field",
+			want: "'\xff' = \"value\"",
+		},
+		{
+			name: "all matcher types",
+			matchers: []*remote.LabelMatcher{
+				{Type: remote.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+				{Type: remote.LabelMatcher_NEQ, Name: "instance", Value: ""},
+				{Type: remote.LabelMatcher_RE, Name: "job", Value: "node|prometheus"},
+				{Type: remote.LabelMatcher_NRE, Name: "env", Value: "dev.*"},
+			},
+			want: "'\xff' = \"value\" AND '\x00' = \"up\" AND 'instance' != \"\" AND 'job' =~ /^(?:node|prometheus)$/ AND 'env' !~ /^(?:dev.*)$/",
+		},
+		{
+			name: "invalid regular expression",
+			matchers: []*remote.LabelMatcher{
+				{Type: remote.LabelMatcher_RE, Name: "job", Value: "("},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := remote.Predicate(tt.matchers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := reads.PredicateToExprString(p); got != tt.want {
+				t.Errorf("unexpected predicate; got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newStore returns a store whose ReadFilter returns a single float series with
+// the given tags and values.
+func newStore(tags models.Tags, values *cursors.FloatArray, req **datatypes.ReadFilterRequest) *mock.StoreReader {
+	store := mock.NewStoreReader()
+	store.ReadFilterFunc = func(ctx context.Context, r *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		*req = r
+
+		var read, next bool
+		cur := mock.NewFloatArrayCursor()
+		cur.NextFunc = func() *cursors.FloatArray {
+			if read {
+				return &cursors.FloatArray{}
+			}
+			read = true
+			return values
+		}
+
+		rs := mock.NewResultSet()
+		rs.NextFunc = func() bool {
+			if next {
+				return false
+			}
+			next = true
+			return true
+		}
+		rs.CursorFunc = func() cursors.Cursor { return cur }
+		rs.TagsFunc = func() models.Tags { return tags }
+		return rs, nil
+	}
+	return store
+}
+
+func TestReadTimeSeries(t *testing.T) {
+	tags := models.NewTags(map[string]string{
+		models.MeasurementTagKey: "up",
+		models.FieldKeyTagKey:    "value",
+		"job":                    "node",
+	})
+	values := &cursors.FloatArray{
+		Timestamps: []int64{1590000000000000000, 1590000015000000000},
+		Values:     []float64{1, 0},
+	}
+
+	var req *datatypes.ReadFilterRequest
+	store := newStore(tags, values, &req)
+
+	q := &remote.Query{
+		StartTimestampMs: 1590000000000,
+		EndTimestampMs:   1590000015000,
+		Matchers:         []*remote.LabelMatcher{{Type: remote.LabelMatcher_EQ, Name: "__name__", Value: "up"}},
+	}
+
+	var got []*remote.TimeSeries
+	err := remote.ReadTimeSeries(context.Background(), store, 1, 2, q, func(ts *remote.TimeSeries) error {
+		got = append(got, ts)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*remote.TimeSeries{
+		{
+			Labels: []*remote.Label{
+				{Name: "__name__", Value: "up"},
+				{Name: "job", Value: "node"},
+			},
+			Samples: []*remote.Sample{
+				{Value: 1, Timestamp: 1590000000000},
+				{Value: 0, Timestamp: 1590000015000},
+			},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected series -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	wantRange := datatypes.TimestampRange{Start: 1590000000000000000, End: 1590000015001000000}
+	if req.Range != wantRange {
+		t.Errorf("unexpected range; got %v, want %v", req.Range, wantRange)
+	}
+}
+
+func TestReadSeries(t *testing.T) {
+	tags := models.NewTags(map[string]string{
+		models.MeasurementTagKey: "http_request_duration_seconds",
+		models.FieldKeyTagKey:    "0.5",
+		"job":                    "api",
+	})
+
+	tests := []struct {
+		name     string
+		matchers []*remote.LabelMatcher
+		values   *cursors.FloatArray
+		want     []models.Tags
+		wantPred string
+	}{
+		{
+			name: "series of any field",
+			matchers: []*remote.LabelMatcher{
+				{Type: remote.LabelMatcher_EQ, Name: "__name__", Value: "http_request_duration_seconds"},
+				{Type: remote.LabelMatcher_NEQ, Name: "_field", Value: "sum"},
+			},
+			values:   &cursors.FloatArray{Timestamps: []int64{1590000000000000000}, Values: []float64{0.25}},
+			want:     []models.Tags{tags},
+			wantPred: "'\x00' = \"http_request_duration_seconds\" AND '\xff' != \"sum\"",
+		},
+		{
+			name:   "series without values are skipped",
+			values: &cursors.FloatArray{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req *datatypes.ReadFilterRequest
+			store := newStore(tags, tt.values, &req)
+
+			var got []models.Tags
+			err := remote.ReadSeries(context.Background(), store, 1, 2, tt.matchers, 1590000000000000000, 1590000015000000000, func(tags models.Tags) error {
+				got = append(got, tags)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("unexpected series -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+
+			if req.Predicate == nil {
+				if tt.wantPred != "" {
+					t.Errorf("unexpected predicate; got none, want %q", tt.wantPred)
+				}
+			} else if got := reads.PredicateToExprString(req.Predicate); got != tt.wantPred {
+				t.Errorf("unexpected predicate; got %q, want %q", got, tt.wantPred)
+			}
+		})
+	}
+}
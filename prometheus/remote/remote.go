@@ -0,0 +1,158 @@
+// Package remote implements the Prometheus remote storage protocol, which
+// allows Prometheus servers to write their samples to influxdb and to read
+// them back.
+//
+// A sample of a Prometheus time series is written as a point with the
+// following mapping:
+//
+//	metric name (the __name__ label)  -> measurement
+//	every other label                 -> tag with the same key and value
+//	sample value                      -> float field named "value"
+//	sample timestamp (milliseconds)   -> point time
+//
+// Labels with an empty value are not written, as Prometheus treats them as if
+// they were absent. Samples whose value is NaN or infinite, which includes the
+// staleness markers Prometheus writes when a series disappears, cannot be
+// stored and are dropped.
+//
+// Reads select series with label matchers on the same mapping, and return the
+// float, integer and unsigned values of the "value" field as samples.
+package remote
+
+//go:generate protoc --plugin ../../scripts/protoc-gen-gogofaster --gogofaster_out=. remote.proto
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	// MetricNameLabel is the label holding the name of a metric. Its value is
+	// the measurement of the points written for the time series.
+	MetricNameLabel = "__name__"
+
+	// FieldName is the field the value of every sample is written to.
+	FieldName = "value"
+
+	// fieldLabel is the label matched against the field of a series, which
+	// is only matched by the series of every field read by ReadSeries.
+	fieldLabel = "_field"
+)
+
+// ErrMaxSizeExceeded is returned when a decompressed request is larger than
+// the maximum allowed size.
+var ErrMaxSizeExceeded = errors.New("decompressed request is too large")
+
+// DecodeWriteRequest reads a snappy compressed WriteRequest from r. When max is
+// greater than zero, ErrMaxSizeExceeded is returned if the compressed or the
+// decompressed request is larger than max bytes.
+func DecodeWriteRequest(r io.Reader, max int64) (*WriteRequest, error) {
+	data, err := readSnappy(r, max)
+	if err != nil {
+		return nil, err
+	}
+
+	var req WriteRequest
+	if err := req.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("failed to decode write request: %v", err)
+	}
+	return &req, nil
+}
+
+// DecodeReadRequest reads a snappy compressed ReadRequest from r. When max is
+// greater than zero, ErrMaxSizeExceeded is returned if the compressed or the
+// decompressed request is larger than max bytes.
+func DecodeReadRequest(r io.Reader, max int64) (*ReadRequest, error) {
+	data, err := readSnappy(r, max)
+	if err != nil {
+		return nil, err
+	}
+
+	var req ReadRequest
+	if err := req.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("failed to decode read request: %v", err)
+	}
+	return &req, nil
+}
+
+// readSnappy reads and decompresses the snappy block format used by the
+// remote storage protocol.
+func readSnappy(r io.Reader, max int64) ([]byte, error) {
+	if max > 0 {
+		r = io.LimitReader(r, max+1)
+	}
+	compressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && int64(len(compressed)) > max {
+		return nil, ErrMaxSizeExceeded
+	}
+
+	n, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress request: %v", err)
+	}
+	if max > 0 && int64(n) > max {
+		return nil, ErrMaxSizeExceeded
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress request: %v", err)
+	}
+	return data, nil
+}
+
+// Points returns a point named name for every sample in req. The name is
+// the encoded organization and bucket ID the points are written to.
+func Points(name string, req *WriteRequest) ([]models.Point, error) {
+	var points []models.Point
+	for _, ts := range req.Timeseries {
+		kv := make([][]byte, 0, 2*len(ts.Labels)+2)
+		var measurement string
+		for _, l := range ts.Labels {
+			if l.Name == MetricNameLabel {
+				measurement = l.Value
+				continue
+			}
+			if l.Value == "" {
+				continue
+			}
+			kv = append(kv, []byte(l.Name), []byte(l.Value))
+		}
+		if measurement == "" {
+			return nil, fmt.Errorf("time series is missing the %s label", MetricNameLabel)
+		}
+		kv = append(kv,
+			models.MeasurementTagKeyBytes, []byte(measurement),
+			models.FieldKeyTagKeyBytes, []byte(FieldName),
+		)
+
+		tags, err := models.NewTagsKeyValues(nil, kv...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range ts.Samples {
+			if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+				continue
+			}
+
+			t := time.Unix(0, s.Timestamp*int64(time.Millisecond)).UTC()
+			pt, err := models.NewPoint(name, tags, models.Fields{FieldName: s.Value}, t)
+			if err != nil {
+				return nil, fmt.Errorf("metric %s: %v", measurement, err)
+			}
+			points = append(points, pt)
+		}
+	}
+	return points, nil
+}
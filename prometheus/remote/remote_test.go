@@ -0,0 +1,141 @@
+package remote_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus/remote"
+)
+
+func encode(t *testing.T, req *remote.WriteRequest) []byte {
+	t.Helper()
+	data, err := req.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snappy.Encode(nil, data)
+}
+
+func TestDecodeWriteRequest(t *testing.T) {
+	want := &remote.WriteRequest{
+		Timeseries: []*remote.TimeSeries{
+			{
+				Labels:  []*remote.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "node"}},
+				Samples: []*remote.Sample{{Value: 1, Timestamp: 1000}},
+			},
+		},
+	}
+
+	got, err := remote.DecodeWriteRequest(bytes.NewReader(encode(t, want)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected request -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestDecodeWriteRequest_Errors(t *testing.T) {
+	big := &remote.WriteRequest{
+		Timeseries: []*remote.TimeSeries{
+			{Labels: []*remote.Label{{Name: "__name__", Value: string(make([]byte, 1024))}}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		max  int64
+		want error
+	}{
+		{
+			name: "decompressed request exceeds max",
+			data: encode(t, big),
+			max:  512,
+			want: remote.ErrMaxSizeExceeded,
+		},
+		{
+			name: "not snappy compressed",
+			data: []byte("up 1"),
+		},
+		{
+			name: "not a write request",
+			data: snappy.Encode(nil, []byte{0xff, 0xff, 0xff}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := remote.DecodeWriteRequest(bytes.NewReader(tt.data), tt.max)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.want != nil && err != tt.want {
+				t.Errorf("unexpected error; got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoints(t *testing.T) {
+	req := &remote.WriteRequest{
+		Timeseries: []*remote.TimeSeries{
+			{
+				Labels: []*remote.Label{
+					{Name: "job", Value: "node"},
+					{Name: "__name__", Value: "node_load1"},
+					{Name: "instance", Value: "server01:9100"},
+					{Name: "empty", Value: ""},
+				},
+				Samples: []*remote.Sample{
+					{Value: 0.64, Timestamp: 1590000000000},
+					{Value: math.NaN(), Timestamp: 1590000015000},
+					{Value: math.Inf(1), Timestamp: 1590000030000},
+					{Value: 0.5, Timestamp: 1590000045000},
+				},
+			},
+		},
+	}
+
+	points, err := remote.Points("name", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := models.NewTags(map[string]string{
+		models.MeasurementTagKey: "node_load1",
+		"instance":               "server01:9100",
+		"job":                    "node",
+		models.FieldKeyTagKey:    "value",
+	})
+	want := []models.Point{
+		models.MustNewPoint("name", tags, models.Fields{"value": 0.64}, time.Unix(1590000000, 0).UTC()),
+		models.MustNewPoint("name", tags, models.Fields{"value": 0.5}, time.Unix(1590000045, 0).UTC()),
+	}
+	if len(points) != len(want) {
+		t.Fatalf("unexpected number of points; got %d, want %d", len(points), len(want))
+	}
+	for i := range want {
+		if got, exp := points[i].String(), want[i].String(); got != exp {
+			t.Errorf("unexpected point %d; got %q, want %q", i, got, exp)
+		}
+	}
+}
+
+func TestPoints_MissingMetricName(t *testing.T) {
+	req := &remote.WriteRequest{
+		Timeseries: []*remote.TimeSeries{
+			{
+				Labels:  []*remote.Label{{Name: "job", Value: "node"}},
+				Samples: []*remote.Sample{{Value: 1, Timestamp: 1000}},
+			},
+		},
+	}
+	if _, err := remote.Points("name", req); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,84 @@
+package remote
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net/http"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	// ContentType is the Content-Type of a snappy compressed request or
+	// SAMPLES response.
+	ContentType = "application/x-protobuf"
+
+	// StreamedContentType is the Content-Type of a STREAMED_XOR_CHUNKS response.
+	StreamedContentType = "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse"
+)
+
+// ResponseType returns the first response type accepted by req, or SAMPLES
+// when req does not list any.
+func ResponseType(req *ReadRequest) ReadRequest_ResponseType {
+	for _, t := range req.AcceptedResponseTypes {
+		switch t {
+		case ReadRequest_SAMPLES, ReadRequest_STREAMED_XOR_CHUNKS:
+			return t
+		}
+	}
+	return ReadRequest_SAMPLES
+}
+
+// EncodeReadResponse writes resp to w, snappy compressed.
+func EncodeReadResponse(w io.Writer, resp *ReadResponse) error {
+	data, err := resp.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(snappy.Encode(nil, data))
+	return err
+}
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ChunkedWriter writes the frames of a STREAMED_XOR_CHUNKS response. Each frame
+// is a ChunkedReadResponse prefixed with its size as a uvarint and the big
+// endian CRC32 checksum, with the Castagnoli polynomial, of the message.
+type ChunkedWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	buf     []byte
+}
+
+// NewChunkedWriter returns a ChunkedWriter that writes to w. When w is an
+// http.Flusher, every frame is flushed to the client once it is written.
+func NewChunkedWriter(w io.Writer) *ChunkedWriter {
+	cw := &ChunkedWriter{w: w}
+	cw.flusher, _ = w.(http.Flusher)
+	return cw
+}
+
+// Write writes resp as a single frame.
+func (w *ChunkedWriter) Write(resp *ChunkedReadResponse) error {
+	size := resp.Size()
+	n := binary.MaxVarintLen64 + 4 + size
+	if cap(w.buf) < n {
+		w.buf = make([]byte, n)
+	}
+	buf := w.buf[:n]
+
+	i := binary.PutUvarint(buf, uint64(size))
+	if _, err := resp.MarshalTo(buf[i+4:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(buf[i:], crc32.Checksum(buf[i+4:i+4+size], castagnoliTable))
+
+	if _, err := w.w.Write(buf[:i+4+size]); err != nil {
+		return err
+	}
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+	return nil
+}
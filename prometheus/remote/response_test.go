@@ -0,0 +1,94 @@
+package remote_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/prometheus/remote"
+)
+
+func crc32c(b []byte) uint32 {
+	return crc32.Checksum(b, crc32.MakeTable(crc32.Castagnoli))
+}
+
+func TestResponseType(t *testing.T) {
+	tests := []struct {
+		name     string
+		accepted []remote.ReadRequest_ResponseType
+		want     remote.ReadRequest_ResponseType
+	}{
+		{
+			name: "samples by default",
+			want: remote.ReadRequest_SAMPLES,
+		},
+		{
+			name:     "first accepted type",
+			accepted: []remote.ReadRequest_ResponseType{remote.ReadRequest_STREAMED_XOR_CHUNKS, remote.ReadRequest_SAMPLES},
+			want:     remote.ReadRequest_STREAMED_XOR_CHUNKS,
+		},
+		{
+			name:     "unknown types are skipped",
+			accepted: []remote.ReadRequest_ResponseType{7, remote.ReadRequest_SAMPLES},
+			want:     remote.ReadRequest_SAMPLES,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := remote.ResponseType(&remote.ReadRequest{AcceptedResponseTypes: tt.accepted})
+			if got != tt.want {
+				t.Errorf("unexpected response type; got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkedWriter(t *testing.T) {
+	resps := []*remote.ChunkedReadResponse{
+		{
+			ChunkedSeries: []*remote.ChunkedSeries{
+				{
+					Labels: []*remote.Label{{Name: "__name__", Value: "up"}},
+					Chunks: []*remote.Chunk{{MinTimeMs: 1000, MaxTimeMs: 1000, Type: remote.Chunk_XOR, Data: []byte{0, 1}}},
+				},
+			},
+		},
+		{QueryIndex: 1},
+	}
+
+	var buf bytes.Buffer
+	w := remote.NewChunkedWriter(&buf)
+	for _, resp := range resps {
+		if err := w.Write(resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range resps {
+		size, err := binary.ReadUvarint(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var checksum [4]byte
+		if _, err := buf.Read(checksum[:]); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Next(int(size))
+		if got, exp := binary.BigEndian.Uint32(checksum[:]), crc32c(data); got != exp {
+			t.Errorf("unexpected checksum; got %x, want %x", got, exp)
+		}
+
+		var got remote.ChunkedReadResponse
+		if err := got.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(want, &got) {
+			t.Errorf("unexpected frame -want/+got:\n%s", cmp.Diff(want, &got))
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected %d bytes after the last frame", buf.Len())
+	}
+}
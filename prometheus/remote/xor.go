@@ -0,0 +1,192 @@
+package remote
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// maxSamplesPerChunk is the maximum number of samples in a chunk, matching
+// the chunks Prometheus cuts for its own storage.
+const maxSamplesPerChunk = 120
+
+// EncodeChunks returns the samples of ts as a series of XOR encoded chunks.
+func EncodeChunks(ts *TimeSeries) *ChunkedSeries {
+	cs := &ChunkedSeries{Labels: ts.Labels}
+	for i := 0; i < len(ts.Samples); i += maxSamplesPerChunk {
+		end := i + maxSamplesPerChunk
+		if end > len(ts.Samples) {
+			end = len(ts.Samples)
+		}
+		samples := ts.Samples[i:end]
+
+		var a xorAppender
+		for _, s := range samples {
+			a.append(s.Timestamp, s.Value)
+		}
+		cs.Chunks = append(cs.Chunks, &Chunk{
+			MinTimeMs: samples[0].Timestamp,
+			MaxTimeMs: samples[len(samples)-1].Timestamp,
+			Type:      Chunk_XOR,
+			Data:      a.b.bytes(),
+		})
+	}
+	return cs
+}
+
+// xorAppender encodes samples in the XOR chunk format of Prometheus, which
+// is the compression described in the Facebook Gorilla paper with a larger
+// range of timestamp deltas. The chunk starts with the number of samples as a
+// big endian uint16.
+type xorAppender struct {
+	b bstream
+
+	n        uint16
+	t        int64
+	v        float64
+	tDelta   uint64
+	leading  uint8
+	trailing uint8
+}
+
+func (a *xorAppender) append(t int64, v float64) {
+	var tDelta uint64
+
+	switch a.n {
+	case 0:
+		a.b = bstream{stream: []byte{0, 0}}
+		a.leading = 0xff
+
+		var buf [binary.MaxVarintLen64]byte
+		a.b.writeBytes(buf[:binary.PutVarint(buf[:], t)]...)
+		a.b.writeBits(math.Float64bits(v), 64)
+	case 1:
+		tDelta = uint64(t - a.t)
+
+		var buf [binary.MaxVarintLen64]byte
+		a.b.writeBytes(buf[:binary.PutUvarint(buf[:], tDelta)]...)
+		a.writeVDelta(v)
+	default:
+		tDelta = uint64(t - a.t)
+		dod := int64(tDelta - a.tDelta)
+
+		switch {
+		case dod == 0:
+			a.b.writeBit(false)
+		case bitRange(dod, 14):
+			a.b.writeBits(0x02, 2)
+			a.b.writeBits(uint64(dod), 14)
+		case bitRange(dod, 17):
+			a.b.writeBits(0x06, 3)
+			a.b.writeBits(uint64(dod), 17)
+		case bitRange(dod, 20):
+			a.b.writeBits(0x0e, 4)
+			a.b.writeBits(uint64(dod), 20)
+		default:
+			a.b.writeBits(0x0f, 4)
+			a.b.writeBits(uint64(dod), 64)
+		}
+		a.writeVDelta(v)
+	}
+
+	a.t, a.v, a.tDelta = t, v, tDelta
+	a.n++
+	binary.BigEndian.PutUint16(a.b.stream, a.n)
+}
+
+func (a *xorAppender) writeVDelta(v float64) {
+	vDelta := math.Float64bits(v) ^ math.Float64bits(a.v)
+	if vDelta == 0 {
+		a.b.writeBit(false)
+		return
+	}
+	a.b.writeBit(true)
+
+	leading := uint8(bits.LeadingZeros64(vDelta))
+	trailing := uint8(bits.TrailingZeros64(vDelta))
+
+	// the number of leading zeros is written with 5 bits.
+	if leading >= 32 {
+		leading = 31
+	}
+
+	if a.leading != 0xff && leading >= a.leading && trailing >= a.trailing {
+		a.b.writeBit(false)
+		a.b.writeBits(vDelta>>a.trailing, 64-int(a.leading)-int(a.trailing))
+		return
+	}
+
+	a.leading, a.trailing = leading, trailing
+	a.b.writeBit(true)
+	a.b.writeBits(uint64(leading), 5)
+
+	// 64 significant bits do not fit in 6 bits and are written as 0, which
+	// is otherwise never written as a zero delta takes the branch above.
+	sigbits := 64 - leading - trailing
+	a.b.writeBits(uint64(sigbits), 6)
+	a.b.writeBits(vDelta>>trailing, int(sigbits))
+}
+
+// bitRange reports whether x can be written with nbits bits.
+func bitRange(x int64, nbits uint8) bool {
+	return -((1<<(nbits-1))-1) <= x && x <= 1<<(nbits-1)
+}
+
+// bstream is a stream of bits written most significant bit first.
+type bstream struct {
+	stream []byte
+	count  uint8 // number of bits free in the last byte
+}
+
+func (b *bstream) bytes() []byte {
+	return b.stream
+}
+
+func (b *bstream) writeBit(bit bool) {
+	if b.count == 0 {
+		b.stream = append(b.stream, 0)
+		b.count = 8
+	}
+
+	i := len(b.stream) - 1
+	if bit {
+		b.stream[i] |= 1 << (b.count - 1)
+	}
+	b.count--
+}
+
+func (b *bstream) writeBytes(bs ...byte) {
+	for _, byt := range bs {
+		b.writeByte(byt)
+	}
+}
+
+func (b *bstream) writeByte(byt byte) {
+	if b.count == 0 {
+		b.stream = append(b.stream, 0)
+		b.count = 8
+	}
+
+	// fill up the free bits of the last byte and start a new byte with the
+	// remaining bits.
+	i := len(b.stream) - 1
+	b.stream[i] |= byt >> (8 - b.count)
+	b.stream = append(b.stream, 0)
+	i++
+	b.stream[i] = byt << b.count
+}
+
+func (b *bstream) writeBits(u uint64, nbits int) {
+	u <<= 64 - uint(nbits)
+	for nbits >= 8 {
+		b.writeByte(byte(u >> 56))
+		u <<= 8
+		nbits -= 8
+	}
+
+	for nbits > 0 {
+		b.writeBit((u >> 63) == 1)
+		u <<= 1
+		nbits--
+	}
+}
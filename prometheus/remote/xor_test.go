@@ -0,0 +1,162 @@
+package remote_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/prometheus/remote"
+)
+
+// bitReader reads a stream of bits most significant bit first.
+type bitReader struct {
+	b   []byte
+	pos uint
+}
+
+func (r *bitReader) readBit() uint64 {
+	bit := (r.b[r.pos/8] >> (7 - r.pos%8)) & 1
+	r.pos++
+	return uint64(bit)
+}
+
+func (r *bitReader) readBits(n int) uint64 {
+	var u uint64
+	for i := 0; i < n; i++ {
+		u = u<<1 | r.readBit()
+	}
+	return u
+}
+
+func (r *bitReader) ReadByte() (byte, error) {
+	return byte(r.readBits(8)), nil
+}
+
+// decodeXOR decodes an XOR chunk in the same way as the Prometheus chunk
+// iterator.
+func decodeXOR(t *testing.T, data []byte) []*remote.Sample {
+	t.Helper()
+
+	n := int(binary.BigEndian.Uint16(data))
+	r := &bitReader{b: data[2:]}
+
+	var (
+		samples           []*remote.Sample
+		ts                int64
+		vbits             uint64
+		tDelta            uint64
+		leading, trailing uint64
+	)
+	readValue := func() {
+		if r.readBit() == 0 {
+			return
+		}
+		if r.readBit() == 1 {
+			leading = r.readBits(5)
+			sigbits := r.readBits(6)
+			if sigbits == 0 {
+				sigbits = 64
+			}
+			trailing = 64 - leading - sigbits
+		}
+		vbits ^= r.readBits(int(64-leading-trailing)) << trailing
+	}
+
+	for i := 0; i < n; i++ {
+		switch i {
+		case 0:
+			v, err := binary.ReadVarint(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ts = v
+			vbits = r.readBits(64)
+		case 1:
+			d, err := binary.ReadUvarint(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tDelta = d
+			ts += int64(tDelta)
+			readValue()
+		default:
+			var prefix byte
+			for j := 0; j < 4; j++ {
+				prefix <<= 1
+				if r.readBit() == 0 {
+					break
+				}
+				prefix |= 1
+			}
+
+			var sz int
+			switch prefix {
+			case 0x02:
+				sz = 14
+			case 0x06:
+				sz = 17
+			case 0x0e:
+				sz = 20
+			case 0x0f:
+				sz = 64
+			}
+
+			var dod int64
+			if sz != 0 {
+				bits := r.readBits(sz)
+				if sz != 64 && bits > 1<<uint(sz-1) {
+					bits -= 1 << uint(sz)
+				}
+				dod = int64(bits)
+			}
+			tDelta = uint64(int64(tDelta) + dod)
+			ts += int64(tDelta)
+			readValue()
+		}
+		samples = append(samples, &remote.Sample{Timestamp: ts, Value: math.Float64frombits(vbits)})
+	}
+	return samples
+}
+
+func TestEncodeChunks(t *testing.T) {
+	ts := &remote.TimeSeries{
+		Labels: []*remote.Label{{Name: "__name__", Value: "up"}},
+	}
+
+	// irregular intervals and values exercise every encoding of the delta of
+	// deltas and of the values.
+	var timestamp int64 = 1590000000000
+	intervals := []int64{15000, 15000, 15001, 14000, 30000, 1, 200000, 15000, 5000000000}
+	values := []float64{1, 1, 2, 2.5, -3.75, 1e10, 0, math.MaxFloat64, 0.1, 42}
+	for i := 0; i < 250; i++ {
+		ts.Samples = append(ts.Samples, &remote.Sample{
+			Timestamp: timestamp,
+			Value:     values[i%len(values)] * float64(i%7),
+		})
+		timestamp += intervals[i%len(intervals)]
+	}
+
+	cs := remote.EncodeChunks(ts)
+	if !cmp.Equal(ts.Labels, cs.Labels) {
+		t.Errorf("unexpected labels -want/+got:\n%s", cmp.Diff(ts.Labels, cs.Labels))
+	}
+	if got, want := len(cs.Chunks), 3; got != want {
+		t.Fatalf("unexpected number of chunks; got %d, want %d", got, want)
+	}
+
+	var got []*remote.Sample
+	for _, c := range cs.Chunks {
+		if c.Type != remote.Chunk_XOR {
+			t.Errorf("unexpected chunk encoding %v", c.Type)
+		}
+		samples := decodeXOR(t, c.Data)
+		if c.MinTimeMs != samples[0].Timestamp || c.MaxTimeMs != samples[len(samples)-1].Timestamp {
+			t.Errorf("unexpected chunk time range [%d, %d]", c.MinTimeMs, c.MaxTimeMs)
+		}
+		got = append(got, samples...)
+	}
+	if !cmp.Equal(ts.Samples, got) {
+		t.Errorf("unexpected samples -want/+got:\n%s", cmp.Diff(ts.Samples, got))
+	}
+}
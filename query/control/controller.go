@@ -50,7 +50,9 @@ type Controller struct {
 	lastID     uint64
 	queriesMu  sync.RWMutex
 	queries    map[QueryID]*Query
-	queryQueue chan *Query
+	queryQueue chan struct{}
+	queue      *fairQueue
+	queueSize  int
 	wg         sync.WaitGroup
 	shutdown   bool
 	done       chan struct{}
@@ -58,6 +60,10 @@ type Controller struct {
 	abort      chan struct{}
 	memory     *memoryManager
 
+	orgs                OrganizationFinder
+	orgConcurrencyQuota int
+	orgMemoryBytesQuota int64
+
 	metrics   *controllerMetrics
 	labelKeys []string
 
@@ -92,7 +98,22 @@ type Config struct {
 	// QueueSize is the number of queries that are allowed to be awaiting execution before new queries are
 	// rejected.
 	QueueSize int
-	Logger    *zap.Logger
+
+	// OrgConcurrencyQuota is the number of queries of a single organization that are allowed
+	// to be queued or executing at the same time. Queries over the quota are rejected.
+	// If this is unset, the queries of an organization are only limited by the QueueSize.
+	OrgConcurrencyQuota int
+
+	// OrgMemoryBytesQuota is the maximum number of bytes the executing queries of a single
+	// organization are allowed to use at any given time. If this is unset, the memory of
+	// an organization is only limited by the MaxMemoryBytes.
+	OrgMemoryBytesQuota int64
+
+	// OrganizationService finds the quotas of an organization, which override the
+	// OrgConcurrencyQuota and the OrgMemoryBytesQuota if they are set.
+	OrganizationService OrganizationFinder
+
+	Logger *zap.Logger
 	// MetricLabelKeys is a list of labels to add to the metrics produced by the controller.
 	// The value for a given key will be read off the context.
 	// The context value must be a string or an implementation of the Stringer interface.
@@ -136,6 +157,12 @@ func (c *Config) validate(isComplete bool) error {
 	if c.QueueSize <= 0 {
 		return errors.New("QueueSize must be positive")
 	}
+	if c.OrgConcurrencyQuota < 0 {
+		return errors.New("OrgConcurrencyQuota must not be negative")
+	}
+	if c.OrgMemoryBytesQuota < 0 {
+		return errors.New("OrgMemoryBytesQuota must not be negative")
+	}
 	return nil
 }
 
@@ -161,7 +188,11 @@ func New(config Config) (*Controller, error) {
 		zap.Int64("initial_memory_bytes_quota_per_query", c.InitialMemoryBytesQuotaPerQuery),
 		zap.Int64("memory_bytes_quota_per_query", c.MemoryBytesQuotaPerQuery),
 		zap.Int64("max_memory_bytes", c.MaxMemoryBytes),
-		zap.Int("queue_size", c.QueueSize))
+		zap.Int("queue_size", c.QueueSize),
+		zap.Int("org_concurrency_quota", c.OrgConcurrencyQuota),
+		zap.Int64("org_memory_bytes_quota", c.OrgMemoryBytesQuota))
+
+	profileNodes()
 
 	mm := &memoryManager{
 		initialBytesQuotaPerQuery: c.InitialMemoryBytesQuotaPerQuery,
@@ -172,16 +203,22 @@ func New(config Config) (*Controller, error) {
 	} else {
 		mm.unlimited = true
 	}
+	metrics := newControllerMetrics(c.MetricLabelKeys)
 	ctrl := &Controller{
-		queries:      make(map[QueryID]*Query),
-		queryQueue:   make(chan *Query, c.QueueSize),
-		done:         make(chan struct{}),
-		abort:        make(chan struct{}),
-		memory:       mm,
-		log:          logger,
-		metrics:      newControllerMetrics(c.MetricLabelKeys),
-		labelKeys:    c.MetricLabelKeys,
-		dependencies: c.ExecutorDependencies,
+		queries:             make(map[QueryID]*Query),
+		queryQueue:          make(chan struct{}, c.QueueSize),
+		queue:               newFairQueue(metrics),
+		queueSize:           c.QueueSize,
+		done:                make(chan struct{}),
+		abort:               make(chan struct{}),
+		memory:              mm,
+		orgs:                c.OrganizationService,
+		orgConcurrencyQuota: c.OrgConcurrencyQuota,
+		orgMemoryBytesQuota: c.OrgMemoryBytesQuota,
+		log:                 logger,
+		metrics:             metrics,
+		labelKeys:           c.MetricLabelKeys,
+		dependencies:        c.ExecutorDependencies,
 	}
 	ctrl.wg.Add(c.ConcurrencyQuota)
 	for i := 0; i < c.ConcurrencyQuota; i++ {
@@ -206,7 +243,15 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	for _, dep := range c.dependencies {
 		ctx = dep.Inject(ctx)
 	}
-	q, err := c.query(ctx, req.Compiler)
+	compiler, err := req.Params.Bind(req.Compiler)
+	if err != nil {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "invalid query parameters",
+			Err:  err,
+		}
+	}
+	q, err := c.query(ctx, compiler)
 	if err != nil {
 		return q, err
 	}
@@ -271,6 +316,7 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 	)
 	q := &Query{
 		id:                 id,
+		createdAt:          time.Now(),
 		labelValues:        labelValues,
 		compileLabelValues: compileLabelValues,
 		state:              Created,
@@ -281,6 +327,14 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		cancel:             cancel,
 		doneCh:             make(chan struct{}),
 	}
+	if req := query.RequestFromContext(ctx); req != nil {
+		q.orgID = req.OrganizationID
+		if req.Authorization != nil {
+			q.userID = req.Authorization.UserID
+		}
+		q.text = QueryText(req.Compiler)
+		q.profile = req.Profile
+	}
 
 	// Lock the queries mutex for the rest of this method.
 	c.queriesMu.Lock()
@@ -313,6 +367,14 @@ func (c *Controller) countQueryRequest(q *Query, result requestsLabel) {
 	c.metrics.requests.WithLabelValues(lvs...).Inc()
 }
 
+func (c *Controller) countQuotaExceeded(q *Query, quota quotaLabel) {
+	l := len(q.labelValues)
+	lvs := make([]string, l+1)
+	copy(lvs, q.labelValues)
+	lvs[l] = string(quota)
+	c.metrics.quotaExceeded.WithLabelValues(lvs...).Inc()
+}
+
 func (c *Controller) compileQuery(q *Query, compiler flux.Compiler) (err error) {
 	log := c.log.With(influxlogger.TraceFields(q.parentCtx)...)
 
@@ -362,15 +424,18 @@ func (c *Controller) enqueueQuery(q *Query) error {
 		}
 	}
 
-	select {
-	case c.queryQueue <- q:
-	default:
-		return &flux.Error{
-			Code: codes.ResourceExhausted,
-			Msg:  "queue length exceeded",
+	quotas := c.findOrgQuotas(q.parentCtx, q.orgID)
+	if err := c.queue.admit(q, quotas, c.queueSize); err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ETooManyRequests {
+			c.countQuotaExceeded(q, labelConcurrencyQuota)
 		}
+		return err
 	}
 
+	// The queue holds as many queries as the channel holds tokens
+	// so this never blocks. Each token lets a worker execute the
+	// query which is next in the queue, in turn by organization.
+	c.queryQueue <- struct{}{}
 	return nil
 }
 
@@ -379,8 +444,10 @@ func (c *Controller) processQueryQueue() {
 		select {
 		case <-c.done:
 			return
-		case q := <-c.queryQueue:
-			c.executeQuery(q)
+		case <-c.queryQueue:
+			if q := c.queue.pop(); q != nil {
+				c.executeQuery(q)
+			}
 		}
 	}
 }
@@ -418,7 +485,20 @@ func (c *Controller) executeQuery(q *Query) {
 		return
 	}
 
-	q.c.createAllocator(q)
+	// The allocator is guarded by the state mutex as the memory
+	// used by the query is reported while it executes.
+	q.stateMu.Lock()
+	err := q.c.createAllocator(q)
+	q.stateMu.Unlock()
+	if err != nil {
+		q.c.countQuotaExceeded(q, labelMemoryQuota)
+		q.setErr(err)
+		return
+	}
+	if q.profile {
+		q.nodes = newNodeProfiler()
+		ctx = withNodeProfiler(ctx, q.nodes)
+	}
 	exec, err := q.program.Start(ctx, q.alloc)
 	if err != nil {
 		q.setErr(err)
@@ -437,6 +517,8 @@ func (c *Controller) waitForQuery(q *Query) {
 }
 
 func (c *Controller) finish(q *Query) {
+	c.queue.release(q)
+
 	c.queriesMu.Lock()
 	delete(c.queries, q.id)
 	if len(c.queries) == 0 && c.shutdown {
@@ -507,7 +589,20 @@ func (c *Controller) PrometheusCollectors() []prometheus.Collector {
 
 // Query represents a single request.
 type Query struct {
-	id QueryID
+	id        QueryID
+	createdAt time.Time
+
+	// the organization and user the query was requested by,
+	// and the text of the query, if it was requested with text.
+	orgID  influxdb.ID
+	userID influxdb.ID
+	text   string
+
+	// profile is set when the profile of the query is requested,
+	// which is sent as a result after the results of the query.
+	// The nodes of the plan of a profiled query are profiled by nodes.
+	profile bool
+	nodes   *nodeProfiler
 
 	labelValues        []string
 	compileLabelValues []string
@@ -534,6 +629,12 @@ type Query struct {
 
 	memoryManager *queryMemoryManager
 	alloc         *memory.Allocator
+
+	// org is the state of the organization while the query is admitted.
+	// orgQuotaExceeded is set when the query was denied memory because
+	// of the quota of its organization. It is accessed atomically.
+	org              *orgState
+	orgQuotaExceeded int32
 }
 
 // ID reports an ephemeral unique ID for the query.
@@ -593,7 +694,7 @@ func (q *Query) Done() {
 			if q.err == nil {
 				// TODO(jsternberg): The underlying program never returns
 				// this so maybe their interface should change?
-				q.err = q.exec.Err()
+				q.err = q.quotaError(q.exec.Err())
 			}
 			// Merge the metadata from the program into the controller stats.
 			stats := q.exec.Statistics()
@@ -607,14 +708,16 @@ func (q *Query) Done() {
 		}
 		q.stats.RuntimeErrors = errMsgs
 
-		// Mark the query as finished so it is removed from the query map.
-		q.c.finish(q)
-
 		// Release the additional memory associated with this query.
+		// This happens before the query is removed from the query map
+		// so that its memory is returned before its organization is.
 		if q.memoryManager != nil {
 			q.memoryManager.Release()
 		}
 
+		// Mark the query as finished so it is removed from the query map.
+		q.c.finish(q)
+
 		// count query request
 		if q.err != nil || len(q.runtimeErrs) > 0 {
 			q.c.countQueryRequest(q, labelRuntimeError)
@@ -785,6 +888,19 @@ func (q *Query) setErr(err error) {
 	close(q.results)
 }
 
+// quotaError replaces the error of a query which ran out of memory because
+// of the quota of its organization, so that it is reported as such.
+func (q *Query) quotaError(err error) error {
+	if err == nil || atomic.LoadInt32(&q.orgQuotaExceeded) == 0 {
+		return err
+	}
+	if flux.ErrorCode(err) != codes.ResourceExhausted {
+		return err
+	}
+	q.c.countQuotaExceeded(q, labelMemoryQuota)
+	return q.org.memoryQuotaError()
+}
+
 func (q *Query) addRuntimeError(e error) {
 	q.stateMu.Lock()
 	defer q.stateMu.Unlock()
@@ -811,6 +927,12 @@ func (q *Query) pump(exec flux.Query, done <-chan struct{}) {
 		select {
 		case res, ok := <-exec.Results():
 			if !ok {
+				if q.profile {
+					select {
+					case <-done:
+					case q.results <- &profileResult{q: q, exec: exec}:
+					}
+				}
 				return
 			}
 
@@ -888,7 +1010,7 @@ type errorCollectingTableIterator struct {
 func (ti *errorCollectingTableIterator) Do(f func(t flux.Table) error) error {
 	err := ti.TableIterator.Do(f)
 	if err != nil {
-		err = handleFluxError(err)
+		err = handleFluxError(ti.q.quotaError(err))
 		ti.q.addRuntimeError(err)
 	}
 	return err
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
@@ -20,6 +21,7 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/query/control"
@@ -31,6 +33,52 @@ import (
 
 func init() {
 	execute.RegisterSource(executetest.AllocatingFromTestKind, executetest.CreateAllocatingFromSource)
+	execute.RegisterSource(finishingAllocatingFromTestKind, createFinishingAllocatingFromSource)
+}
+
+const finishingAllocatingFromTestKind = "finishing-allocating-from-test"
+
+// finishingAllocatingFromProcedureSpec is an allocating source which
+// finishes once it has allocated, so its results can be read to the end.
+type finishingAllocatingFromProcedureSpec struct {
+	executetest.AllocatingFromProcedureSpec
+}
+
+func (finishingAllocatingFromProcedureSpec) Kind() plan.ProcedureKind {
+	return finishingAllocatingFromTestKind
+}
+
+func (s *finishingAllocatingFromProcedureSpec) Copy() plan.ProcedureSpec {
+	return &finishingAllocatingFromProcedureSpec{
+		AllocatingFromProcedureSpec: executetest.AllocatingFromProcedureSpec{ByteCount: s.ByteCount},
+	}
+}
+
+func createFinishingAllocatingFromSource(spec plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	s := spec.(*finishingAllocatingFromProcedureSpec)
+	src, err := executetest.CreateAllocatingFromSource(&s.AllocatingFromProcedureSpec, id, a)
+	if err != nil {
+		return nil, err
+	}
+	return &finishingSource{Source: src, id: id}, nil
+}
+
+type finishingSource struct {
+	execute.Source
+	id execute.DatasetID
+	ts []execute.Transformation
+}
+
+func (s *finishingSource) AddTransformation(t execute.Transformation) {
+	s.Source.AddTransformation(t)
+	s.ts = append(s.ts, t)
+}
+
+func (s *finishingSource) Run(ctx context.Context) {
+	s.Source.Run(ctx)
+	for _, t := range s.ts {
+		t.Finish(s.id, nil)
+	}
 }
 
 var (
@@ -569,6 +617,255 @@ func TestController_ShutdownWithRunningQuery(t *testing.T) {
 	wg.Wait()
 }
 
+func TestController_RunningQueries(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					close(executing)
+					<-ctx.Done()
+				},
+			}, nil
+		},
+	}
+
+	ctx := context.Background()
+	req := makeRequest(compiler)
+	req.OrganizationID = 1
+	q, err := ctrl.Query(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Wait until execution has started.
+	<-executing
+
+	qs, err := ctrl.FindRunningQueries(ctx, platform.RunningQueryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 {
+		t.Fatalf("expected 1 running query, got %d", len(qs))
+	}
+	if got, want := qs[0].OrgID, platform.ID(1); got != want {
+		t.Errorf("unexpected org id: got %s want %s", got, want)
+	}
+	if got, want := qs[0].State, "executing"; got != want {
+		t.Errorf("unexpected state: got %s want %s", got, want)
+	}
+
+	otherOrg := platform.ID(2)
+	if qs, err := ctrl.FindRunningQueries(ctx, platform.RunningQueryFilter{OrgID: &otherOrg}); err != nil {
+		t.Fatal(err)
+	} else if len(qs) != 0 {
+		t.Errorf("expected no running queries in other org, got %d", len(qs))
+	}
+
+	if err := ctrl.CancelRunningQuery(ctx, qs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	rq, err := ctrl.FindRunningQueryByID(ctx, qs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rq.State, "canceled"; got != want {
+		t.Errorf("unexpected state: got %s want %s", got, want)
+	}
+
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	if _, err := ctrl.FindRunningQueryByID(ctx, qs[0].ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected finished query to not be found, got %v", err)
+	}
+}
+
+func TestController_OrgConcurrencyQuota(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.QueueSize = 4
+	config.OrgConcurrencyQuota = 1
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+	reg := setupPromRegistry(ctrl)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	executing := make(chan struct{}, 2)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- struct{}{}
+					<-done
+				},
+			}, nil
+		},
+	}
+
+	for _, orgID := range []platform.ID{1, 2} {
+		req := makeRequest(compiler)
+		req.OrganizationID = orgID
+		q, err := ctrl.Query(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for range q.Results() {
+				// discard the results
+			}
+			q.Done()
+		}()
+		<-executing
+	}
+
+	req := makeRequest(compiler)
+	req.OrganizationID = 1
+	if _, err := ctrl.Query(context.Background(), req); platform.ErrorCode(err) != platform.ETooManyRequests {
+		t.Fatalf("expected an error about the org concurrency quota, got %v", err)
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := FindMetric(mfs, "query_control_org_quota_exceeded_total", map[string]string{
+		"org":   platform.ID(1).String(),
+		"quota": "concurrency",
+	})
+	if m == nil {
+		t.Fatal("expected the org quota exceeded metric")
+	} else if got := m.GetCounter().GetValue(); got != 1 {
+		t.Errorf("unexpected org quota exceeded count: got %v want 1", got)
+	}
+}
+
+func TestController_OrgMemoryQuota(t *testing.T) {
+	config := config
+	config.InitialMemoryBytesQuotaPerQuery = 128
+	config.OrgMemoryBytesQuota = 512
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					defer func() {
+						if err, ok := recover().(error); ok && err != nil {
+							q.SetErr(err)
+						}
+					}()
+
+					// The query is within its own limit, but not
+					// within the limit of its organization.
+					mem := arrow.NewAllocator(alloc)
+					b := mem.Allocate(768)
+					mem.Free(b)
+				},
+			}, nil
+		},
+	}
+
+	req := makeRequest(compiler)
+	req.OrganizationID = 1
+	q, err := ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	if err := q.Err(); platform.ErrorCode(err) != platform.ETooLarge {
+		t.Fatalf("expected an error about the org memory quota, got %v", err)
+	}
+}
+
+func TestController_OrgFairQueue(t *testing.T) {
+	config := config
+	config.QueueSize = 4
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	var (
+		mu    sync.Mutex
+		order []platform.ID
+	)
+	block := make(chan struct{})
+	executing := make(chan struct{})
+	compilerFor := func(orgID platform.ID) flux.Compiler {
+		return &mock.Compiler{
+			CompileFn: func(ctx context.Context) (flux.Program, error) {
+				return &mock.Program{
+					ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+						if orgID == 3 {
+							close(executing)
+							<-block
+							return
+						}
+						mu.Lock()
+						order = append(order, orgID)
+						mu.Unlock()
+					},
+				}, nil
+			},
+		}
+	}
+
+	var wg sync.WaitGroup
+	submit := func(orgID platform.ID) {
+		req := makeRequest(compilerFor(orgID))
+		req.OrganizationID = orgID
+		q, err := ctrl.Query(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range q.Results() {
+				// discard the results
+			}
+			q.Done()
+		}()
+	}
+
+	// Keep the only worker busy while the queue fills up
+	// with the queries of one organization and then another.
+	submit(3)
+	<-executing
+	for _, orgID := range []platform.ID{1, 1, 1, 2} {
+		submit(orgID)
+	}
+	close(block)
+	wg.Wait()
+
+	if want := []platform.ID{1, 2, 1, 1}; fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Fatalf("unexpected execution order: got %v want %v", order, want)
+	}
+}
+
 func TestController_ShutdownWithTimeout(t *testing.T) {
 	ctrl, err := control.New(config)
 	if err != nil {
@@ -1254,6 +1551,116 @@ func TestController_ReserveMemoryWithoutExceedingMax(t *testing.T) {
 	}
 }
 
+func TestController_Profile(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			pts := plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("allocating-from-test", &finishingAllocatingFromProcedureSpec{
+						AllocatingFromProcedureSpec: executetest.AllocatingFromProcedureSpec{ByteCount: 16},
+					}),
+					plan.CreatePhysicalNode("yield", &universe.YieldProcedureSpec{Name: "_result"}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+				Resources: flux.ResourceManagement{
+					ConcurrencyQuota: 1,
+				},
+			}
+			return &lang.Program{
+				Logger:   zaptest.NewLogger(t),
+				PlanSpec: plantest.CreatePlanSpec(&pts),
+			}, nil
+		},
+	}
+
+	req := makeRequest(compiler)
+	req.Profile = true
+	q, err := ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rows of the profile by the table and the column.
+	var names []string
+	profile := make(map[string][]map[string]string)
+	ri := flux.NewResultIteratorFromQuery(q)
+	for ri.More() {
+		res := ri.Next()
+		names = append(names, res.Name())
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			if res.Name() != control.ProfileResultName {
+				return nil
+			}
+			name := tbl.Key().ValueString(0)
+			return tbl.Do(func(cr flux.ColReader) error {
+				for i := 0; i < cr.Len(); i++ {
+					row := make(map[string]string)
+					for j, c := range cr.Cols() {
+						if c.Type == flux.TString {
+							row[c.Label] = cr.Strings(j).ValueString(i)
+						} else if vs := cr.Ints(j); vs.IsValid(i) {
+							row[c.Label] = fmt.Sprint(vs.Value(i))
+						}
+					}
+					profile[name] = append(profile[name], row)
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ri.Release()
+	if err := ri.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"_result", control.ProfileResultName}; !cmp.Equal(want, names) {
+		t.Fatalf("unexpected results -want/+got:\n%s", cmp.Diff(want, names))
+	}
+	if got := len(profile["query"]); got != 1 {
+		t.Fatalf("unexpected number of rows of the query table: %d", got)
+	}
+	if got := profile["query"][0]["concurrency"]; got != "1" {
+		t.Errorf("unexpected concurrency: %s", got)
+	}
+	if got := profile["query"][0]["plan"]; !strings.Contains(got, "allocating-from-test") {
+		t.Errorf("expected the plan to have the source node, got %q", got)
+	}
+
+	var nodes []string
+	for _, row := range profile["plan"] {
+		nodes = append(nodes, row["node"]+"<-"+row["predecessors"])
+	}
+	if want := []string{"allocating-from-test<-", "yield<-allocating-from-test"}; !cmp.Equal(want, nodes) {
+		t.Errorf("unexpected plan nodes -want/+got:\n%s", cmp.Diff(want, nodes))
+	}
+
+	// The source is executed and allocates its byte count, but the
+	// yield is not executed so it has no duration or memory.
+	src, yield := profile["plan"][0], profile["plan"][1]
+	if _, ok := src["duration"]; !ok {
+		t.Error("expected the source to have a duration")
+	}
+	if got := src["max_allocated"]; got != "16" {
+		t.Errorf("unexpected max allocated of the source: %s", got)
+	}
+	if got := src["total_allocated"]; got != "16" {
+		t.Errorf("unexpected total allocated of the source: %s", got)
+	}
+	if _, ok := yield["duration"]; ok {
+		t.Errorf("expected the yield to have no duration, got %s", yield["duration"])
+	}
+}
+
 func consumeResults(tb testing.TB, q flux.Query) {
 	tb.Helper()
 	for res := range q.Results() {
@@ -29,22 +29,38 @@ type memoryManager struct {
 }
 
 // createAllocator will construct an allocator and memory manager
-// for the given query.
-func (c *Controller) createAllocator(q *Query) {
+// for the given query. The initial memory of the query counts against
+// the quota of its organization and an error is returned if it does
+// not fit.
+func (c *Controller) createAllocator(q *Query) error {
+	initial := c.memory.initialBytesQuotaPerQuery
+	for {
+		used, unused := q.org.memory()
+		if unused < initial {
+			return q.org.memoryQuotaError()
+		}
+		if q.org.reserveMemory(used, initial) {
+			break
+		}
+	}
+
 	q.memoryManager = &queryMemoryManager{
 		m:     c.memory,
-		limit: c.memory.initialBytesQuotaPerQuery,
+		q:     q,
+		limit: initial,
 	}
 	q.alloc = &memory.Allocator{
 		// Use an anonymous function to ensure the value is copied.
 		Limit:   func(v int64) *int64 { return &v }(q.memoryManager.limit),
 		Manager: q.memoryManager,
 	}
+	return nil
 }
 
 // queryMemoryManager is a memory manager for a specific query.
 type queryMemoryManager struct {
 	m     *memoryManager
+	q     *Query
 	limit int64
 	given int64
 }
@@ -65,6 +81,7 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 		return 0, errors.New("query hit hard limit")
 	}
 
+	org := q.q.org
 	for {
 		unused := int64(math.MaxInt64)
 		if !q.m.unlimited {
@@ -76,11 +93,23 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 			}
 		}
 
+		// The organization of the query may have less memory
+		// left than the controller.
+		orgUsed, orgUnused := org.memory()
+		if orgUnused < want {
+			atomic.StoreInt32(&q.q.orgQuotaExceeded, 1)
+			return 0, errors.New("organization memory quota exceeded")
+		}
+		available := unused
+		if orgUnused < available {
+			available = orgUnused
+		}
+
 		// The memory allocator will only request the bare amount of
 		// memory it needs, but it will probably ask for more memory
 		// so, if possible, give it more so it isn't repeatedly calling
 		// this method.
-		given := q.giveMemory(want, unused)
+		given := q.giveMemory(want, available)
 
 		// Reserve this memory for our own use.
 		if !q.m.unlimited {
@@ -90,6 +119,14 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 				continue
 			}
 		}
+		if !org.reserveMemory(orgUsed, given) {
+			// Another query of the organization took memory first
+			// so return what we reserved and retry.
+			if !q.m.unlimited {
+				atomic.AddInt64(&q.m.unusedMemoryBytes, given)
+			}
+			continue
+		}
 
 		// Successfully reserved the memory so update our own internal
 		// counter for the limit.
@@ -140,6 +177,7 @@ func (q *queryMemoryManager) Release() {
 	if !q.m.unlimited {
 		atomic.AddInt64(&q.m.unusedMemoryBytes, q.given)
 	}
+	q.q.org.releaseMemory(q.limit)
 	q.limit = q.m.initialBytesQuotaPerQuery
 	q.given = 0
 }
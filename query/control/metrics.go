@@ -7,6 +7,9 @@ type controllerMetrics struct {
 	requests  *prometheus.CounterVec
 	functions *prometheus.CounterVec
 
+	quotaExceeded *prometheus.CounterVec
+	orgMemory     *prometheus.GaugeVec
+
 	all       *prometheus.GaugeVec
 	compiling *prometheus.GaugeVec
 	queueing  *prometheus.GaugeVec
@@ -27,6 +30,13 @@ const (
 	labelQueueError   = requestsLabel("queue_error")
 )
 
+type quotaLabel string
+
+const (
+	labelConcurrencyQuota = quotaLabel("concurrency")
+	labelMemoryQuota      = quotaLabel("memory")
+)
+
 func newControllerMetrics(labels []string) *controllerMetrics {
 	const (
 		namespace = "query"
@@ -48,6 +58,20 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Help:      "Count of functions in queries",
 		}, append(labels, "function")),
 
+		quotaExceeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_quota_exceeded_total",
+			Help:      "Count of the queries rejected because the quota of their organization was exceeded",
+		}, append(labels, "quota")),
+
+		orgMemory: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_memory_bytes",
+			Help:      "Memory reserved by the executing queries of each organization",
+		}, []string{orgLabel}),
+
 		all: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
@@ -116,6 +140,9 @@ func (cm *controllerMetrics) PrometheusCollectors() []prometheus.Collector {
 		cm.requests,
 		cm.functions,
 
+		cm.quotaExceeded,
+		cm.orgMemory,
+
 		cm.all,
 		cm.compiling,
 		cm.queueing,
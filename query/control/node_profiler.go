@@ -0,0 +1,304 @@
+package control
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+	_ "unsafe" // for go:linkname
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+)
+
+// The executor creates the sources and the transformations of the nodes of a
+// plan with the functions registered for their procedure kinds. Flux has no
+// way to get those functions back, so their registries are linked here to wrap
+// each function with one that profiles the nodes of the profiled queries.
+
+//go:linkname procedureToSource github.com/influxdata/flux/execute.procedureToSource
+var procedureToSource map[plan.ProcedureKind]execute.CreateSource
+
+//go:linkname procedureToTransformation github.com/influxdata/flux/execute.procedureToTransformation
+var procedureToTransformation map[plan.ProcedureKind]execute.CreateTransformation
+
+var profileNodesOnce sync.Once
+
+// profileNodes wraps the registered sources and transformations so that
+// the nodes of the queries with a node profiler in their context are
+// timed and their memory is measured.
+//
+// It is called when a controller is created, after every source and
+// transformation has been registered and before any query is executed.
+func profileNodes() {
+	profileNodesOnce.Do(func() {
+		for k, fn := range procedureToSource {
+			procedureToSource[k] = profileSource(fn)
+		}
+		for k, fn := range procedureToTransformation {
+			procedureToTransformation[k] = profileTransformation(fn)
+		}
+	})
+}
+
+type nodeProfilerKey struct{}
+
+// withNodeProfiler returns a context with the profiler of the nodes of a query.
+func withNodeProfiler(ctx context.Context, p *nodeProfiler) context.Context {
+	return context.WithValue(ctx, nodeProfilerKey{}, p)
+}
+
+func nodeProfilerFromContext(ctx context.Context) *nodeProfiler {
+	p, _ := ctx.Value(nodeProfilerKey{}).(*nodeProfiler)
+	return p
+}
+
+// nodeProfiler holds the profiles of the nodes of the plan of a query by their dataset.
+type nodeProfiler struct {
+	mu    sync.Mutex
+	nodes map[execute.DatasetID]*nodeProfile
+}
+
+func newNodeProfiler() *nodeProfiler {
+	return &nodeProfiler{nodes: make(map[execute.DatasetID]*nodeProfile)}
+}
+
+// node returns a new profile for the node of the dataset. The memory of the
+// node is allocated from its own allocator, which reserves the memory it
+// needs from the allocator of the query.
+func (p *nodeProfiler) node(id execute.DatasetID, parent *memory.Allocator) *nodeProfile {
+	n := &nodeProfile{}
+	if parent != nil {
+		n.mem = &nodeMemory{parent: parent}
+		n.alloc = &memory.Allocator{
+			Limit:     new(int64),
+			Manager:   n.mem,
+			Allocator: parent.Allocator,
+		}
+	}
+
+	p.mu.Lock()
+	p.nodes[id] = n
+	p.mu.Unlock()
+	return n
+}
+
+// lookup returns the profile of the node of the dataset, or nil if
+// the node has not been profiled.
+func (p *nodeProfiler) lookup(id execute.DatasetID) *nodeProfile {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.nodes[id]
+}
+
+// nodeProfile is the time spent in a node and the memory it allocated.
+//
+// The time of a transformation is the time spent processing the tables and
+// the messages it is sent. The time of a source is the time it runs for,
+// which includes the time its tables are read by the transformations after
+// it when its tables are read as they are consumed, as the storage tables are.
+type nodeProfile struct {
+	// duration is accessed atomically.
+	duration int64
+
+	alloc *memory.Allocator
+	mem   *nodeMemory
+}
+
+// Duration returns the time spent in the node.
+func (n *nodeProfile) Duration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&n.duration))
+}
+
+// MaxAllocated returns the maximum memory allocated by the node at once.
+func (n *nodeProfile) MaxAllocated() int64 {
+	if n.alloc == nil {
+		return 0
+	}
+	return n.alloc.MaxAllocated()
+}
+
+// TotalAllocated returns the total memory allocated by the node.
+func (n *nodeProfile) TotalAllocated() int64 {
+	if n.alloc == nil {
+		return 0
+	}
+	return n.alloc.TotalAllocated()
+}
+
+func (n *nodeProfile) since(start time.Time) {
+	atomic.AddInt64(&n.duration, int64(time.Since(start)))
+}
+
+// finish returns the memory that the node reserved and is not using
+// anymore to the allocator of the query.
+func (n *nodeProfile) finish() {
+	if n.mem != nil {
+		n.mem.release(n.alloc.Allocated())
+	}
+}
+
+// nodeMemory is the memory manager of the allocator of a node.
+// It reserves the memory of the node from the allocator of the query,
+// so that the memory of the node counts against the limit of the query.
+//
+// The allocator of a node only asks for memory when it allocates more than
+// it ever had, so the memory freed by a node stays reserved until the node
+// has finished, when the memory which is not in use anymore is released.
+type nodeMemory struct {
+	parent *memory.Allocator
+
+	mu       sync.Mutex
+	reserved int64
+}
+
+func (m *nodeMemory) RequestMemory(want int64) (got int64, err error) {
+	if err := m.parent.Account(int(want)); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	m.reserved += want
+	m.mu.Unlock()
+	return want, nil
+}
+
+func (m *nodeMemory) FreeMemory(bytes int64) {}
+
+// release returns the memory reserved above the memory still allocated.
+func (m *nodeMemory) release(allocated int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n := m.reserved - allocated; n > 0 {
+		_ = m.parent.Account(int(-n))
+		m.reserved -= n
+	}
+}
+
+// nodeAdministration is the administration of a profiled node,
+// which allocates the memory of the node from its own allocator.
+type nodeAdministration struct {
+	execute.Administration
+	alloc *memory.Allocator
+}
+
+func (a nodeAdministration) Allocator() *memory.Allocator {
+	return a.alloc
+}
+
+// administration returns the administration of the node, which allocates
+// from the allocator of the node.
+func (n *nodeProfile) administration(a execute.Administration) execute.Administration {
+	if n.alloc == nil {
+		return a
+	}
+	return nodeAdministration{Administration: a, alloc: n.alloc}
+}
+
+func profileSource(create execute.CreateSource) execute.CreateSource {
+	return func(spec plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+		p := nodeProfilerFromContext(a.Context())
+		if p == nil {
+			return create(spec, id, a)
+		}
+		n := p.node(id, a.Allocator())
+		src, err := create(spec, id, n.administration(a))
+		if err != nil {
+			return nil, err
+		}
+		// The executor reads the metadata of the sources which have some.
+		if md, ok := src.(execute.MetadataNode); ok {
+			return &profiledMetadataSource{
+				profiledSource: profiledSource{Source: src, n: n},
+				md:             md,
+			}, nil
+		}
+		return &profiledSource{Source: src, n: n}, nil
+	}
+}
+
+// profiledSource is a source which is profiled.
+type profiledSource struct {
+	execute.Source
+	n *nodeProfile
+}
+
+func (s *profiledSource) Run(ctx context.Context) {
+	defer s.n.finish()
+	defer s.n.since(time.Now())
+	s.Source.Run(ctx)
+}
+
+// profiledMetadataSource is a source with metadata which is profiled.
+type profiledMetadataSource struct {
+	profiledSource
+	md execute.MetadataNode
+}
+
+func (s *profiledMetadataSource) Metadata() flux.Metadata {
+	return s.md.Metadata()
+}
+
+func profileTransformation(create execute.CreateTransformation) execute.CreateTransformation {
+	return func(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+		p := nodeProfilerFromContext(a.Context())
+		if p == nil {
+			return create(id, mode, spec, a)
+		}
+		n := p.node(id, a.Allocator())
+		t, d, err := create(id, mode, spec, n.administration(a))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &profiledTransformation{
+			Transformation: t,
+			n:              n,
+			parents:        int32(len(a.Parents())),
+		}, d, nil
+	}
+}
+
+// profiledTransformation is a transformation which is profiled.
+type profiledTransformation struct {
+	execute.Transformation
+	n *nodeProfile
+
+	// parents is the number of the parents which have not finished.
+	// It is accessed atomically.
+	parents int32
+}
+
+func (t *profiledTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	defer t.n.since(time.Now())
+	return t.Transformation.RetractTable(id, key)
+}
+
+func (t *profiledTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	defer t.n.since(time.Now())
+	return t.Transformation.Process(id, tbl)
+}
+
+func (t *profiledTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	defer t.n.since(time.Now())
+	return t.Transformation.UpdateWatermark(id, mark)
+}
+
+func (t *profiledTransformation) UpdateProcessingTime(id execute.DatasetID, now execute.Time) error {
+	defer t.n.since(time.Now())
+	return t.Transformation.UpdateProcessingTime(id, now)
+}
+
+func (t *profiledTransformation) Finish(id execute.DatasetID, err error) {
+	start := time.Now()
+	t.Transformation.Finish(id, err)
+	t.n.since(start)
+
+	// The transformation has finished once each of its parents has.
+	if atomic.AddInt32(&t.parents, -1) <= 0 {
+		t.n.finish()
+	}
+}
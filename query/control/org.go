@@ -0,0 +1,242 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/influxdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// OrganizationFinder finds the organizations queries are requested in,
+// so that the query quotas of each organization can be enforced.
+type OrganizationFinder interface {
+	FindOrganizationByID(ctx context.Context, id influxdb.ID) (*influxdb.Organization, error)
+}
+
+// orgQuotas are the quotas of the queries of an organization.
+// A zero quota is unlimited.
+type orgQuotas struct {
+	concurrency int
+	memoryBytes int64
+	weight      int
+}
+
+// findOrgQuotas returns the quotas of an organization, which are the
+// defaults of the controller unless the organization overrides them.
+func (c *Controller) findOrgQuotas(ctx context.Context, orgID influxdb.ID) orgQuotas {
+	quotas := orgQuotas{
+		concurrency: c.orgConcurrencyQuota,
+		memoryBytes: c.orgMemoryBytesQuota,
+		weight:      1,
+	}
+	if c.orgs == nil || !orgID.Valid() {
+		return quotas
+	}
+
+	o, err := c.orgs.FindOrganizationByID(ctx, orgID)
+	if err != nil {
+		c.log.Info("Failed to find organization query quotas, using defaults", zap.Stringer("org_id", orgID), zap.Error(err))
+		return quotas
+	}
+	if q := o.QueryQuotas; q != nil {
+		if q.ConcurrencyQuota > 0 {
+			quotas.concurrency = q.ConcurrencyQuota
+		}
+		if q.MemoryBytesQuota > 0 {
+			quotas.memoryBytes = q.MemoryBytesQuota
+		}
+		if q.Weight > 0 {
+			quotas.weight = q.Weight
+		}
+	}
+	return quotas
+}
+
+// orgState tracks the admitted queries of an organization.
+type orgState struct {
+	// memoryBytes is the memory reserved by the executing queries
+	// of the organization, and memoryQuota the memory they may
+	// reserve. Both are accessed atomically.
+	memoryBytes int64
+	memoryQuota int64
+
+	// the fields below are guarded by the mutex of the queue.
+	id          influxdb.ID
+	concurrency int
+	weight      int
+
+	// active is the number of queries which are queued or executing.
+	active int
+
+	// queue holds the queries waiting to be executed, and pass is the
+	// virtual time at which the next of them is due.
+	queue []*Query
+	pass  float64
+
+	memoryGauge prometheus.Gauge
+}
+
+// memory returns the memory reserved by the organization and the memory it
+// may still reserve.
+func (o *orgState) memory() (used, unused int64) {
+	if o == nil {
+		return 0, math.MaxInt64
+	}
+	used = atomic.LoadInt64(&o.memoryBytes)
+	quota := atomic.LoadInt64(&o.memoryQuota)
+	if quota <= 0 {
+		return used, math.MaxInt64
+	}
+	return used, quota - used
+}
+
+// reserveMemory reserves n bytes for the organization if the memory it has
+// reserved is still used.
+func (o *orgState) reserveMemory(used, n int64) bool {
+	if o == nil {
+		return true
+	}
+	if !atomic.CompareAndSwapInt64(&o.memoryBytes, used, used+n) {
+		return false
+	}
+	o.memoryGauge.Set(float64(used + n))
+	return true
+}
+
+// releaseMemory releases n bytes reserved by the organization.
+func (o *orgState) releaseMemory(n int64) {
+	if o == nil {
+		return
+	}
+	o.memoryGauge.Set(float64(atomic.AddInt64(&o.memoryBytes, -n)))
+}
+
+// memoryQuotaError returns the error of a query which needs more memory than
+// its organization has left.
+func (o *orgState) memoryQuotaError() error {
+	return &influxdb.Error{
+		Code: influxdb.ETooLarge,
+		Msg:  fmt.Sprintf("organization query memory quota of %d bytes exceeded", atomic.LoadInt64(&o.memoryQuota)),
+	}
+}
+
+// fairQueue holds the queries waiting to be executed. The organizations with
+// waiting queries take turns in proportion to their weight, so that the
+// queries of one organization cannot delay those of the others for long.
+type fairQueue struct {
+	metrics *controllerMetrics
+
+	mu     sync.Mutex
+	orgs   map[influxdb.ID]*orgState
+	queued int
+
+	// vtime is the virtual time of the last query to be dequeued.
+	vtime float64
+}
+
+func newFairQueue(metrics *controllerMetrics) *fairQueue {
+	return &fairQueue{
+		metrics: metrics,
+		orgs:    make(map[influxdb.ID]*orgState),
+	}
+}
+
+// admit counts a query against the concurrency quota of its organization and
+// adds it to the queue, unless the quota or the queue is full.
+func (f *fairQueue) admit(q *Query, quotas orgQuotas, queueSize int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.queued >= queueSize {
+		return &flux.Error{
+			Code: codes.ResourceExhausted,
+			Msg:  "queue length exceeded",
+		}
+	}
+
+	o, ok := f.orgs[q.orgID]
+	if !ok {
+		o = &orgState{
+			id:          q.orgID,
+			pass:        f.vtime,
+			memoryGauge: f.metrics.orgMemory.WithLabelValues(q.orgID.String()),
+		}
+		f.orgs[q.orgID] = o
+	}
+	o.concurrency, o.weight = quotas.concurrency, quotas.weight
+	atomic.StoreInt64(&o.memoryQuota, quotas.memoryBytes)
+
+	if o.concurrency > 0 && o.active >= o.concurrency {
+		return &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Msg:  fmt.Sprintf("organization query concurrency quota of %d exceeded", o.concurrency),
+		}
+	}
+	o.active++
+	q.org = o
+
+	// an organization which had nothing queued does not keep
+	// the turns it did not take while it was idle.
+	if len(o.queue) == 0 && o.pass < f.vtime {
+		o.pass = f.vtime
+	}
+	o.queue = append(o.queue, q)
+	f.queued++
+	return nil
+}
+
+// pop removes the next query to execute from the queue.
+func (f *fairQueue) pop() *Query {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var next *orgState
+	for _, o := range f.orgs {
+		if len(o.queue) == 0 {
+			continue
+		}
+		if next == nil || o.pass < next.pass || (o.pass == next.pass && o.id < next.id) {
+			next = o
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	q := next.queue[0]
+	next.queue[0] = nil
+	next.queue = next.queue[1:]
+	f.queued--
+
+	f.vtime = next.pass
+	next.pass += 1 / float64(next.weight)
+
+	// the query may have been released while it was queued.
+	if next.active == 0 && len(next.queue) == 0 {
+		delete(f.orgs, next.id)
+	}
+	return q
+}
+
+// release stops counting a finished query against the quota of its organization.
+func (f *fairQueue) release(q *Query) {
+	if q.org == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	o := q.org
+	o.active--
+	if o.active == 0 && len(o.queue) == 0 {
+		delete(f.orgs, o.id)
+	}
+}
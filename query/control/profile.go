@@ -0,0 +1,263 @@
+package control
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	// ProfileResultName is the name of the result of the profile of a query.
+	ProfileResultName = "_profile"
+
+	// profileTableLabel is the column of the group key of the profile
+	// tables, which is either the query or the plan table.
+	profileTableLabel = "_profile"
+
+	// The storage sources report their stats in the metadata of a query.
+	// Each source is identified by its dataset under sourceMetadataKey
+	// and the stats of a source are at the same index in their keys.
+	storageMetadataPrefix = "influxdb/"
+	sourceMetadataKey     = storageMetadataPrefix + "source"
+)
+
+// planRuler is implemented by the procedure specs which record
+// the planner rules that fired to produce them.
+type planRuler interface {
+	PlanRules() []string
+}
+
+// profileResult is the profile of a query, which is sent as a result
+// after the results of the query when the profile is requested.
+//
+// The tables of the profile are built when they are read, which is after the
+// results of the query have been read and its execution has finished.
+type profileResult struct {
+	q    *Query
+	exec flux.Query
+}
+
+func (r *profileResult) Name() string               { return ProfileResultName }
+func (r *profileResult) Tables() flux.TableIterator { return r }
+
+func (r *profileResult) Do(f func(flux.Table) error) error {
+	// Wait for the sources to finish so that their
+	// metadata has been merged into the statistics.
+	r.exec.Done()
+	stats := r.exec.Statistics()
+	spec := planSpec(r.q.program)
+
+	alloc := &memory.Allocator{}
+	tbl, err := r.queryTable(stats, spec, alloc)
+	if err != nil {
+		return err
+	}
+	if err := f(tbl); err != nil {
+		return err
+	}
+
+	if spec == nil {
+		return nil
+	}
+	tbl, err = planTable(spec, r.q.nodes, stats.Metadata, alloc)
+	if err != nil {
+		return err
+	}
+	return f(tbl)
+}
+
+// queryTable returns the table of the durations and the memory of the query,
+// and of its physical plan.
+func (r *profileResult) queryTable(stats flux.Statistics, spec *plan.Spec, alloc *memory.Allocator) (flux.Table, error) {
+	q := r.q
+	q.stateMu.RLock()
+	compile, queue, executing := q.stats.CompileDuration, q.stats.QueueDuration, q.stats.ExecuteDuration
+	if q.state == Executing && q.currentSpan != nil {
+		executing += time.Since(q.currentSpan.start)
+	}
+	q.stateMu.RUnlock()
+
+	var (
+		concurrency int
+		formatted   string
+	)
+	if spec != nil {
+		concurrency = spec.Resources.ConcurrencyQuota
+		formatted = fmt.Sprintf("%v", plan.Formatted(spec, plan.WithDetails()))
+	}
+
+	b, err := newProfileTableBuilder("query", alloc, []flux.ColMeta{
+		{Label: "total_duration", Type: flux.TInt},
+		{Label: "compile_duration", Type: flux.TInt},
+		{Label: "queue_duration", Type: flux.TInt},
+		{Label: "execute_duration", Type: flux.TInt},
+		{Label: "concurrency", Type: flux.TInt},
+		{Label: "max_allocated", Type: flux.TInt},
+		{Label: "total_allocated", Type: flux.TInt},
+		{Label: "plan", Type: flux.TString},
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = b.AppendString(0, "query")
+	_ = b.AppendInt(1, int64(time.Since(q.createdAt)))
+	_ = b.AppendInt(2, int64(compile))
+	_ = b.AppendInt(3, int64(queue))
+	_ = b.AppendInt(4, int64(executing))
+	_ = b.AppendInt(5, int64(concurrency))
+	_ = b.AppendInt(6, stats.MaxAllocated)
+	_ = b.AppendInt(7, stats.TotalAllocated)
+	_ = b.AppendString(8, formatted)
+	return b.Table()
+}
+
+// planTable returns the table of the nodes of the physical plan, with the
+// planner rules which produced each node, the time spent in each node and
+// the memory it allocated, and the stats of the storage reads.
+// The time and the memory are null for the nodes which were not executed,
+// and the stats are null for the nodes which do not read from storage.
+func planTable(spec *plan.Spec, nodes *nodeProfiler, md flux.Metadata, alloc *memory.Allocator) (flux.Table, error) {
+	cols := []flux.ColMeta{
+		{Label: "node", Type: flux.TString},
+		{Label: "kind", Type: flux.TString},
+		{Label: "predecessors", Type: flux.TString},
+		{Label: "details", Type: flux.TString},
+		{Label: "rules", Type: flux.TString},
+		{Label: "duration", Type: flux.TInt},
+		{Label: "max_allocated", Type: flux.TInt},
+		{Label: "total_allocated", Type: flux.TInt},
+	}
+	keys := storageMetadataKeys(md)
+	for _, k := range keys {
+		label := strings.Replace(strings.TrimPrefix(k, storageMetadataPrefix), "-", "_", -1)
+		cols = append(cols, flux.ColMeta{Label: label, Type: flux.TInt})
+	}
+
+	b, err := newProfileTableBuilder("plan", alloc, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	// The index of the stats of the source of each dataset.
+	sources := make(map[string]int)
+	for i, v := range md[sourceMetadataKey] {
+		if id, ok := v.(string); ok {
+			sources[id] = i
+		}
+	}
+
+	if err := spec.BottomUpWalk(func(node plan.Node) error {
+		preds := make([]string, len(node.Predecessors()))
+		for i, pred := range node.Predecessors() {
+			preds[i] = string(pred.ID())
+		}
+		var details, rules string
+		if d, ok := node.ProcedureSpec().(plan.Detailer); ok {
+			details = d.PlanDetails()
+		}
+		if r, ok := node.ProcedureSpec().(planRuler); ok {
+			rules = strings.Join(r.PlanRules(), ",")
+		}
+
+		_ = b.AppendString(0, "plan")
+		_ = b.AppendString(1, string(node.ID()))
+		_ = b.AppendString(2, string(node.Kind()))
+		_ = b.AppendString(3, strings.Join(preds, ","))
+		_ = b.AppendString(4, details)
+		_ = b.AppendString(5, rules)
+
+		id := execute.DatasetIDFromNodeID(node.ID())
+		if n := nodes.lookup(id); n != nil {
+			_ = b.AppendInt(6, int64(n.Duration()))
+			_ = b.AppendInt(7, n.MaxAllocated())
+			_ = b.AppendInt(8, n.TotalAllocated())
+		} else {
+			_ = b.AppendNil(6)
+			_ = b.AppendNil(7)
+			_ = b.AppendNil(8)
+		}
+
+		i, ok := sources[id.String()]
+		for j, k := range keys {
+			if v, isInt := metadataInt(md[k], i); ok && isInt {
+				_ = b.AppendInt(9+j, v)
+			} else {
+				_ = b.AppendNil(9 + j)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return b.Table()
+}
+
+// newProfileTableBuilder returns a builder of the profile table of the name,
+// with the group key column followed by the columns.
+func newProfileTableBuilder(name string, alloc *memory.Allocator, cols []flux.ColMeta) (*execute.ColListTableBuilder, error) {
+	kb := execute.NewGroupKeyBuilder(nil)
+	kb.AddKeyValue(profileTableLabel, values.NewString(name))
+	gk, err := kb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	b := execute.NewColListTableBuilder(gk, alloc)
+	if _, err := b.AddCol(flux.ColMeta{Label: profileTableLabel, Type: flux.TString}); err != nil {
+		return nil, err
+	}
+	for _, c := range cols {
+		if _, err := b.AddCol(c); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// storageMetadataKeys returns the sorted keys of the stats of the storage sources.
+func storageMetadataKeys(md flux.Metadata) []string {
+	var keys []string
+	for k := range md {
+		if strings.HasPrefix(k, storageMetadataPrefix) && k != sourceMetadataKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// metadataInt returns the integer at the index of the metadata values.
+func metadataInt(vs []interface{}, i int) (int64, bool) {
+	if i >= len(vs) {
+		return 0, false
+	}
+	switch v := vs[i].(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case time.Duration:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// planSpec returns the physical plan of the program, which is set once
+// the program has been started, or nil if the program has no plan.
+func planSpec(p flux.Program) *plan.Spec {
+	switch p := p.(type) {
+	case *lang.Program:
+		return p.PlanSpec
+	case *lang.AstProgram:
+		return p.PlanSpec
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/influxql"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.RunningQueryService = (*Controller)(nil)
+
+// FindRunningQueryByID returns a single query that has not finished.
+func (c *Controller) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	q, err := c.findQuery(id)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindRunningQueryByID,
+			Err: err,
+		}
+	}
+	return q.running(), nil
+}
+
+// FindRunningQueries returns the queries that have not finished, in the
+// order they were created.
+func (c *Controller) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	queries := c.Queries()
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].id < queries[j].id
+	})
+
+	rqs := make([]*influxdb.RunningQuery, 0, len(queries))
+	for _, q := range queries {
+		if filter.OrgID != nil && q.orgID != *filter.OrgID {
+			continue
+		}
+		rqs = append(rqs, q.running())
+	}
+	return rqs, nil
+}
+
+// CancelRunningQuery cancels a query that has not finished. The query is
+// finished once the client that requested it has been sent the error.
+func (c *Controller) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	q, err := c.findQuery(id)
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCancelRunningQuery,
+			Err: err,
+		}
+	}
+	c.log.Info("Canceling query", zap.Uint64("query_id", uint64(q.id)), zap.Stringer("org_id", q.orgID))
+	q.Cancel()
+	return nil
+}
+
+func (c *Controller) findQuery(id influxdb.ID) (*Query, error) {
+	c.queriesMu.RLock()
+	q, ok := c.queries[QueryID(id)]
+	c.queriesMu.RUnlock()
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "query not found",
+		}
+	}
+	return q, nil
+}
+
+// running reports the progress of the query.
+func (q *Query) running() *influxdb.RunningQuery {
+	// the state is reported as canceled as soon as the query is canceled.
+	state := q.State()
+
+	q.stateMu.RLock()
+	defer q.stateMu.RUnlock()
+
+	rq := &influxdb.RunningQuery{
+		ID:              influxdb.ID(q.id),
+		OrgID:           q.orgID,
+		UserID:          q.userID,
+		State:           state.String(),
+		Query:           q.text,
+		CreatedAt:       q.createdAt,
+		CompileDuration: q.stats.CompileDuration,
+		QueueDuration:   q.stats.QueueDuration,
+		ExecuteDuration: q.stats.ExecuteDuration,
+	}
+
+	// the duration of the current state is only added to the
+	// statistics when the query leaves it.
+	if q.currentSpan != nil {
+		d := time.Since(q.currentSpan.start)
+		switch q.state {
+		case Compiling:
+			rq.CompileDuration += d
+		case Queueing:
+			rq.QueueDuration += d
+		case Executing:
+			rq.ExecuteDuration += d
+		}
+	}
+	if q.alloc != nil {
+		rq.MemoryBytes = q.alloc.Allocated()
+	}
+	return rq
+}
+
+// QueryText returns the text of the query compiled by a compiler,
+// if it has one.
+func QueryText(c flux.Compiler) string {
+	switch c := c.(type) {
+	case lang.FluxCompiler:
+		return c.Query
+	case *lang.FluxCompiler:
+		return c.Query
+	case lang.ASTCompiler:
+		if c.AST != nil {
+			return ast.Format(c.AST)
+		}
+	case *lang.ASTCompiler:
+		if c.AST != nil {
+			return ast.Format(c.AST)
+		}
+	case *influxql.Compiler:
+		return c.Query
+	}
+	return ""
+}
@@ -0,0 +1,191 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+)
+
+const (
+	ArrowDialectType = "arrow"
+
+	// ArrowContentType is the media type of query results encoded as Arrow IPC streams.
+	ArrowContentType = "application/vnd.apache.arrow.stream"
+)
+
+// Metadata keys of the Arrow schemas written by the ArrowEncoder.
+const (
+	arrowResultKey = "flux.result"
+	arrowTableKey  = "flux.table"
+	arrowGroupKey  = "flux.group"
+)
+
+// ArrowDialect is a dialect that encodes query results in the Arrow IPC streaming format.
+//
+// Each table of each result is written as its own stream, so the response is a
+// sequence of streams which must be read one after another. The schema of each
+// stream has the result name in its "flux.result" metadata, the index of the table
+// within the result in "flux.table" and the comma separated labels of the group key
+// columns in "flux.group". The group key columns also have "true" in the "flux.group"
+// metadata of their field. Every column is nullable.
+//
+// Flux values map to Arrow as follows:
+//
+//	bool            bool
+//	int             int64
+//	uint            uint64
+//	float           float64
+//	string          utf8
+//	time            timestamp with nanosecond unit in UTC
+//
+// Errors are not encoded. If the query fails after tables were written,
+// the response ends without the end of its last stream.
+type ArrowDialect struct{}
+
+func NewArrowDialect() *ArrowDialect {
+	return &ArrowDialect{}
+}
+
+func (d *ArrowDialect) Encoder() flux.MultiResultEncoder {
+	return &ArrowEncoder{}
+}
+
+func (d *ArrowDialect) DialectType() flux.DialectType {
+	return ArrowDialectType
+}
+
+func (d *ArrowDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ArrowContentType)
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// ArrowEncoder encodes query results as described by the ArrowDialect.
+type ArrowEncoder struct{}
+
+func (e *ArrowEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	defer results.Release()
+
+	cw := &iocounter.Writer{Writer: w}
+	for results.More() {
+		res := results.Next()
+		table := 0
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			defer func() { table++ }()
+			return encodeArrowTable(cw, res.Name(), table, tbl)
+		}); err != nil {
+			return cw.Count(), err
+		}
+	}
+	results.Release()
+	return cw.Count(), results.Err()
+}
+
+func encodeArrowTable(w io.Writer, name string, table int, tbl flux.Table) error {
+	schema, err := arrowSchema(name, table, tbl.Key(), tbl.Cols())
+	if err != nil {
+		return err
+	}
+
+	sw := ipc.NewWriter(w, ipc.WithSchema(schema))
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		rec := arrowRecord(schema, cr)
+		defer rec.Release()
+		return sw.Write(rec)
+	}); err != nil {
+		return err
+	}
+	return sw.Close()
+}
+
+func arrowSchema(name string, table int, key flux.GroupKey, cols []flux.ColMeta) (*arrow.Schema, error) {
+	var group []string
+	fields := make([]arrow.Field, len(cols))
+	for j, c := range cols {
+		typ, err := arrowType(c.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[j] = arrow.Field{
+			Name:     c.Label,
+			Type:     typ,
+			Nullable: true,
+		}
+		if key.HasCol(c.Label) {
+			fields[j].Metadata = arrow.NewMetadata([]string{arrowGroupKey}, []string{"true"})
+			group = append(group, c.Label)
+		}
+	}
+
+	meta := arrow.NewMetadata(
+		[]string{arrowResultKey, arrowTableKey, arrowGroupKey},
+		[]string{name, strconv.Itoa(table), strings.Join(group, ",")},
+	)
+	return arrow.NewSchema(fields, &meta), nil
+}
+
+func arrowType(typ flux.ColType) (arrow.DataType, error) {
+	switch typ {
+	case flux.TBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case flux.TInt:
+		return arrow.PrimitiveTypes.Int64, nil
+	case flux.TUInt:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case flux.TFloat:
+		return arrow.PrimitiveTypes.Float64, nil
+	case flux.TString:
+		return arrow.BinaryTypes.String, nil
+	case flux.TTime:
+		return arrow.FixedWidthTypes.Timestamp_ns, nil
+	default:
+		return nil, fmt.Errorf("unsupported column type for arrow encoding: %s", typ)
+	}
+}
+
+// arrowRecord returns the columns read by cr as a record. Flux stores strings and
+// times as binary and int64 arrays, which have the same layout as the utf8 and
+// timestamp arrays of the schema, so the buffers of the columns are shared.
+func arrowRecord(schema *arrow.Schema, cr flux.ColReader) array.Record {
+	cols := make([]array.Interface, len(cr.Cols()))
+	for j, c := range cr.Cols() {
+		var arr array.Interface
+		switch c.Type {
+		case flux.TBool:
+			arr = cr.Bools(j)
+		case flux.TInt:
+			arr = cr.Ints(j)
+		case flux.TUInt:
+			arr = cr.UInts(j)
+		case flux.TFloat:
+			arr = cr.Floats(j)
+		case flux.TString:
+			arr = cr.Strings(j)
+		case flux.TTime:
+			arr = cr.Times(j)
+		}
+		data := arr.Data()
+		data = array.NewData(
+			schema.Field(j).Type,
+			data.Len(),
+			data.Buffers(),
+			nil,
+			data.NullN(),
+			data.Offset(),
+		)
+		cols[j] = array.MakeFromData(data)
+		data.Release()
+	}
+	rec := array.NewRecord(schema, cols, int64(cr.Len()))
+	for _, col := range cols {
+		col.Release()
+	}
+	return rec
+}
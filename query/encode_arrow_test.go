@@ -0,0 +1,74 @@
+package query_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestArrowEncoder(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{newEncoderTestResult()})
+	var w bytes.Buffer
+	if _, err := (&query.ArrowEncoder{}).Encode(&w, results); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each table is a stream of its own.
+	r := bytes.NewReader(w.Bytes())
+	for table, want := range []struct {
+		index string
+		rows  int64
+		t1    string
+	}{
+		{index: "0", rows: 2, t1: "a"},
+		{index: "1", rows: 1, t1: "b"},
+	} {
+		sr, err := ipc.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema := sr.Schema()
+		meta := schema.Metadata()
+		for key, value := range map[string]string{
+			"flux.result": "_result",
+			"flux.table":  want.index,
+			"flux.group":  "t1",
+		} {
+			if i := meta.FindKey(key); i < 0 || meta.Values()[i] != value {
+				t.Errorf("table %d: expected %q in metadata %q", table, value, key)
+			}
+		}
+		if got := schema.Field(0).Type; !arrow.TypeEquals(got, arrow.FixedWidthTypes.Timestamp_ns) {
+			t.Errorf("table %d: unexpected type of _time: %s", table, got)
+		}
+
+		var rows int64
+		for sr.Next() {
+			rec := sr.Record()
+			rows += rec.NumRows()
+			t1 := rec.Column(5).(*array.String)
+			if got := t1.Value(0); got != want.t1 {
+				t.Errorf("table %d: unexpected t1: got %s want %s", table, got, want.t1)
+			}
+			if table == 0 && !rec.Column(1).IsNull(1) {
+				t.Errorf("table %d: expected a null _value", table)
+			}
+		}
+		if err := sr.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if rows != want.rows {
+			t.Errorf("table %d: unexpected number of rows: got %d want %d", table, rows, want.rows)
+		}
+		sr.Release()
+	}
+	if r.Len() != 0 {
+		t.Errorf("unexpected %d bytes after the last stream", r.Len())
+	}
+}
@@ -0,0 +1,222 @@
+package query
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+)
+
+const (
+	JSONDialectType = "json"
+
+	// JSONContentType is the media type of query results encoded as JSON rows.
+	JSONContentType = "application/json"
+)
+
+// JSONDialect is a dialect that encodes query results as a JSON array of rows.
+//
+// Each row is an object with the result name in "result", the index of its
+// table within the result in "table", and a member for each column of the table.
+// The group key is not encoded separately: the columns of the group key have the
+// same value in every row of a table, and the rows of a table share its "table".
+//
+// Flux values map to JSON as follows:
+//
+//	bool            boolean
+//	int, uint       number
+//	float           number, or null if the value is NaN or infinite
+//	string          string
+//	time            string in RFC3339 format with nanosecond precision
+//	null            null
+//
+// If the query fails after rows were written, the array ends with an object
+// holding the message of the error in "error".
+type JSONDialect struct{}
+
+func NewJSONDialect() *JSONDialect {
+	return &JSONDialect{}
+}
+
+func (d *JSONDialect) Encoder() flux.MultiResultEncoder {
+	return &JSONEncoder{}
+}
+
+func (d *JSONDialect) DialectType() flux.DialectType {
+	return JSONDialectType
+}
+
+func (d *JSONDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", JSONContentType+"; charset=utf-8")
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// JSONEncoder encodes query results as described by the JSONDialect.
+type JSONEncoder struct{}
+
+func (e *JSONEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	defer results.Release()
+
+	cw := &iocounter.Writer{Writer: w}
+	bw := bufio.NewWriter(cw)
+	enc := &jsonRowEncoder{w: bw}
+
+	err := enc.encodeResults(results)
+	if err == nil {
+		results.Release()
+		err = results.Err()
+	}
+	if err != nil {
+		if enc.rows == 0 {
+			// Nothing was written so the error can
+			// still be returned in place of the results.
+			return cw.Count(), err
+		}
+		enc.encodeError(err)
+	}
+	enc.end()
+	if err := bw.Flush(); err != nil {
+		return cw.Count(), err
+	}
+	return cw.Count(), nil
+}
+
+type jsonRowEncoder struct {
+	w    *bufio.Writer
+	buf  []byte
+	rows int
+}
+
+func (e *jsonRowEncoder) encodeResults(results flux.ResultIterator) error {
+	for results.More() {
+		res := results.Next()
+		name, err := json.Marshal(res.Name())
+		if err != nil {
+			return err
+		}
+
+		table := 0
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			defer func() { table++ }()
+			return tbl.Do(func(cr flux.ColReader) error {
+				return e.encodeRows(name, table, cr)
+			})
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *jsonRowEncoder) encodeRows(name []byte, table int, cr flux.ColReader) error {
+	cols := cr.Cols()
+	labels := make([][]byte, len(cols))
+	for j, c := range cols {
+		label, err := json.Marshal(c.Label)
+		if err != nil {
+			return err
+		}
+		labels[j] = label
+	}
+
+	for i := 0; i < cr.Len(); i++ {
+		e.begin()
+		e.buf = append(e.buf, `{"result":`...)
+		e.buf = append(e.buf, name...)
+		e.buf = append(e.buf, `,"table":`...)
+		e.buf = strconv.AppendInt(e.buf, int64(table), 10)
+		for j, c := range cols {
+			e.buf = append(e.buf, ',')
+			e.buf = append(e.buf, labels[j]...)
+			e.buf = append(e.buf, ':')
+			e.buf = appendJSONValue(e.buf, cr, c.Type, i, j)
+		}
+		e.buf = append(e.buf, '}')
+		if _, err := e.w.Write(e.buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// begin starts a new element of the array of rows.
+func (e *jsonRowEncoder) begin() {
+	e.buf = e.buf[:0]
+	if e.rows == 0 {
+		e.buf = append(e.buf, '[')
+	} else {
+		e.buf = append(e.buf, ',')
+	}
+	e.rows++
+}
+
+func (e *jsonRowEncoder) encodeError(err error) {
+	msg, _ := json.Marshal(err.Error())
+	e.begin()
+	e.buf = append(e.buf, `{"error":`...)
+	e.buf = append(e.buf, msg...)
+	e.buf = append(e.buf, '}')
+	_, _ = e.w.Write(e.buf)
+}
+
+// end closes the array of rows.
+func (e *jsonRowEncoder) end() {
+	if e.rows == 0 {
+		_, _ = e.w.WriteString("[]\n")
+		return
+	}
+	_, _ = e.w.WriteString("]\n")
+}
+
+func appendJSONValue(buf []byte, cr flux.ColReader, typ flux.ColType, i, j int) []byte {
+	switch typ {
+	case flux.TBool:
+		vs := cr.Bools(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return strconv.AppendBool(buf, vs.Value(i))
+	case flux.TInt:
+		vs := cr.Ints(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return strconv.AppendInt(buf, vs.Value(i), 10)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return strconv.AppendUint(buf, vs.Value(i), 10)
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		if vs.IsNull(i) {
+			break
+		}
+		if v := vs.Value(i); !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return strconv.AppendFloat(buf, v, 'g', -1, 64)
+		}
+	case flux.TString:
+		vs := cr.Strings(j)
+		if vs.IsNull(i) {
+			break
+		}
+		s, _ := json.Marshal(vs.ValueString(i))
+		return append(buf, s...)
+	case flux.TTime:
+		vs := cr.Times(j)
+		if vs.IsNull(i) {
+			break
+		}
+		buf = append(buf, '"')
+		buf = time.Unix(0, vs.Value(i)).UTC().AppendFormat(buf, time.RFC3339Nano)
+		return append(buf, '"')
+	}
+	return append(buf, "null"...)
+}
@@ -0,0 +1,109 @@
+package query_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+)
+
+// newEncoderTestResult returns a result with two tables,
+// a null value and every type of column.
+func newEncoderTestResult() flux.Result {
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "count", Type: flux.TInt},
+		{Label: "n", Type: flux.TUInt},
+		{Label: "ok", Type: flux.TBool},
+		{Label: "t1", Type: flux.TString},
+	}
+	r := executetest.NewResult([]*executetest.Table{
+		{
+			KeyCols: []string{"t1"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), 1.5, int64(-1), uint64(1), true, "a"},
+				{execute.Time(10), nil, int64(2), uint64(2), false, "a"},
+			},
+		},
+		{
+			KeyCols: []string{"t1"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(20), 3.0, int64(3), uint64(3), true, "b"},
+			},
+		},
+	})
+	r.Nm = "_result"
+	return r
+}
+
+func TestJSONEncoder(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "rows",
+			want: `[{"result":"_result","table":0,"_time":"1970-01-01T00:00:00Z","_value":1.5,"count":-1,"n":1,"ok":true,"t1":"a"},` +
+				`{"result":"_result","table":0,"_time":"1970-01-01T00:00:00.00000001Z","_value":null,"count":2,"n":2,"ok":false,"t1":"a"},` +
+				`{"result":"_result","table":1,"_time":"1970-01-01T00:00:00.00000002Z","_value":3,"count":3,"n":3,"ok":true,"t1":"b"}]` + "\n",
+		},
+		{
+			name: "runtime error",
+			err:  fmt.Errorf("I am a runtime error"),
+			want: `[{"result":"_result","table":0,"_time":"1970-01-01T00:00:00Z","_value":1.5,"count":-1,"n":1,"ok":true,"t1":"a"},` +
+				`{"result":"_result","table":0,"_time":"1970-01-01T00:00:00.00000001Z","_value":null,"count":2,"n":2,"ok":false,"t1":"a"},` +
+				`{"result":"_result","table":1,"_time":"1970-01-01T00:00:00.00000002Z","_value":3,"count":3,"n":3,"ok":true,"t1":"b"},` +
+				`{"error":"I am a runtime error"}]` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			bridge := query.ProxyQueryServiceAsyncBridge{
+				AsyncQueryService: &mock.AsyncQueryService{
+					QueryF: func(ctx context.Context, req *query.Request) (flux.Query, error) {
+						q := mock.NewQuery()
+						q.SetResults(newEncoderTestResult())
+						if tc.err != nil {
+							q.SetErr(tc.err)
+						}
+						return q, nil
+					},
+				},
+			}
+
+			var w bytes.Buffer
+			if _, err := bridge.Query(context.Background(), &w, &query.ProxyRequest{
+				Dialect: query.NewJSONDialect(),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, w.String()); diff != "" {
+				t.Errorf("unexpected JSON -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONEncoder_NoResults(t *testing.T) {
+	results := flux.NewSliceResultIterator(nil)
+	var w bytes.Buffer
+	if _, err := (&query.JSONEncoder{}).Encode(&w, results); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.String(), "[]\n"; got != want {
+		t.Errorf("unexpected JSON: got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,204 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxdb/pkg/parquet"
+)
+
+const (
+	ParquetDialectType = "parquet"
+
+	// ParquetContentType is the media type of query results encoded as a Parquet file.
+	ParquetContentType = "application/vnd.apache.parquet"
+)
+
+// Columns added to the rows of the Parquet file written by the ParquetEncoder.
+const (
+	parquetResultColumn = "result"
+	parquetTableColumn  = "table"
+)
+
+// ParquetDialect is a dialect that encodes query results as a single Parquet file.
+//
+// Each table of each result is written as a row group as soon as it has been read,
+// with the result name in the "result" column and the index of the table within the
+// result in the "table" column. The schema of the file is the union of the columns
+// of the tables, and a column is null in the tables which do not have it. A column
+// must have the same type in every table. The labels of the group key columns of
+// each table are written, comma separated, to the key-value metadata of the file
+// with the key "flux.group.<result>.<table>".
+//
+// Flux values map to Parquet as follows, and every column is optional:
+//
+//	bool            BOOLEAN
+//	int             INT64
+//	uint            INT64 annotated as an unsigned 64 bit integer
+//	float           DOUBLE
+//	string          BYTE_ARRAY annotated as UTF8
+//	time            INT64 annotated as a timestamp in nanoseconds in UTC
+//
+// Errors are not encoded. If the query fails after row groups were written,
+// the response ends without the metadata of the file, which is then invalid.
+type ParquetDialect struct{}
+
+func NewParquetDialect() *ParquetDialect {
+	return &ParquetDialect{}
+}
+
+func (d *ParquetDialect) Encoder() flux.MultiResultEncoder {
+	return &ParquetEncoder{}
+}
+
+func (d *ParquetDialect) DialectType() flux.DialectType {
+	return ParquetDialectType
+}
+
+func (d *ParquetDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ParquetContentType)
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// ParquetEncoder encodes query results as described by the ParquetDialect.
+type ParquetEncoder struct{}
+
+func (e *ParquetEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	defer results.Release()
+
+	cw := &iocounter.Writer{Writer: w}
+	pw := parquet.NewWriter(cw)
+	for results.More() {
+		res := results.Next()
+		table := 0
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			defer func() { table++ }()
+			return encodeParquetTable(pw, res.Name(), table, tbl)
+		}); err != nil {
+			return cw.Count(), err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return cw.Count(), err
+	}
+	// closing the writer writes the footer, which is counted.
+	err := pw.Close()
+	return cw.Count(), err
+}
+
+func encodeParquetTable(pw *parquet.Writer, name string, table int, tbl flux.Table) error {
+	result := parquet.NewColumn(parquetResultColumn, parquet.String)
+	index := parquet.NewColumn(parquetTableColumn, parquet.Int64)
+	cols := []*parquet.Column{result, index}
+	var group []string
+	for _, c := range tbl.Cols() {
+		typ, err := parquetType(c.Type)
+		if err != nil {
+			return err
+		}
+		cols = append(cols, parquet.NewColumn(c.Label, typ))
+		if tbl.Key().HasCol(c.Label) {
+			group = append(group, c.Label)
+		}
+	}
+
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			result.AppendString(name)
+			index.AppendInt(int64(table))
+		}
+		for j, c := range cr.Cols() {
+			appendParquetValues(cols[j+2], cr, c.Type, j)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if result.Len() > 0 {
+		pw.SetKeyValue(fmt.Sprintf("flux.group.%s.%d", name, table), strings.Join(group, ","))
+	}
+	return pw.WriteRowGroup(cols)
+}
+
+func parquetType(typ flux.ColType) (parquet.Type, error) {
+	switch typ {
+	case flux.TBool:
+		return parquet.Boolean, nil
+	case flux.TInt:
+		return parquet.Int64, nil
+	case flux.TUInt:
+		return parquet.Uint64, nil
+	case flux.TFloat:
+		return parquet.Double, nil
+	case flux.TString:
+		return parquet.String, nil
+	case flux.TTime:
+		return parquet.Timestamp, nil
+	default:
+		return 0, fmt.Errorf("unsupported column type for parquet encoding: %s", typ)
+	}
+}
+
+func appendParquetValues(col *parquet.Column, cr flux.ColReader, typ flux.ColType, j int) {
+	switch typ {
+	case flux.TBool:
+		vs := cr.Bools(j)
+		for i := 0; i < vs.Len(); i++ {
+			if vs.IsNull(i) {
+				col.AppendNull()
+			} else {
+				col.AppendBool(vs.Value(i))
+			}
+		}
+	case flux.TInt:
+		vs := cr.Ints(j)
+		for i := 0; i < vs.Len(); i++ {
+			if vs.IsNull(i) {
+				col.AppendNull()
+			} else {
+				col.AppendInt(vs.Value(i))
+			}
+		}
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		for i := 0; i < vs.Len(); i++ {
+			if vs.IsNull(i) {
+				col.AppendNull()
+			} else {
+				col.AppendUint(vs.Value(i))
+			}
+		}
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		for i := 0; i < vs.Len(); i++ {
+			if vs.IsNull(i) {
+				col.AppendNull()
+			} else {
+				col.AppendFloat(vs.Value(i))
+			}
+		}
+	case flux.TString:
+		vs := cr.Strings(j)
+		for i := 0; i < vs.Len(); i++ {
+			if vs.IsNull(i) {
+				col.AppendNull()
+			} else {
+				col.AppendString(vs.ValueString(i))
+			}
+		}
+	case flux.TTime:
+		vs := cr.Times(j)
+		for i := 0; i < vs.Len(); i++ {
+			if vs.IsNull(i) {
+				col.AppendNull()
+			} else {
+				col.AppendInt(vs.Value(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package query_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestParquetEncoder(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{newEncoderTestResult()})
+	var w bytes.Buffer
+	n, err := (&query.ParquetEncoder{}).Encode(&w, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(w.Len()) {
+		t.Errorf("unexpected number of bytes written: got %d want %d", n, w.Len())
+	}
+
+	data := w.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("expected a parquet file")
+	}
+	for _, s := range []string{"flux.group._result.0", "flux.group._result.1", "_value", "t1"} {
+		if !bytes.Contains(data, []byte(s)) {
+			t.Errorf("expected %q in the metadata of the file", s)
+		}
+	}
+}
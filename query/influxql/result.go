@@ -13,7 +13,14 @@ import (
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// TimeFormat is the format of encoded timestamps; defaults to RFC3339Nano.
+	TimeFormat TimeFormat
+
+	// ChunkSize is the maximum number of values encoded in a single chunk.
+	// When zero, the whole response is encoded as a single JSON document.
+	ChunkSize int
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -27,164 +34,291 @@ type MultiResultEncoder struct{}
 //  4.  All other columns are fields and will be output in the order they are found.
 //      TODO(jsternberg): This function currently requires the first column to be a time field, but this isn't
 //      a strict requirement and will be lifted when we begin to work on transpiling meta queries.
+//
+// When ChunkSize is set, the response is written as a stream of JSON documents, one per line,
+// in the same way as a chunked influxdb 1.X response. A series with more than ChunkSize values
+// is split across documents with partial set on every row except the last, and partial is set
+// on every result except the last one for a statement.
 func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	if e.ChunkSize > 0 {
+		return e.encodeChunked(w, results)
+	}
+
 	resp := Response{}
 	wc := &iocounter.Writer{Writer: w}
 
 	for results.More() {
 		res := results.Next()
-		name := res.Name()
-		id, err := strconv.Atoi(name)
+		id, err := statementID(res)
 		if err != nil {
-			resp.error(fmt.Errorf("unable to parse statement id from result name: %s", err))
+			resp.error(err)
 			results.Release()
 			break
 		}
 
-		tables := res.Tables()
-
 		result := Result{StatementID: id}
-		if err := tables.Do(func(tbl flux.Table) error {
-			var row Row
-
-			for j, c := range tbl.Key().Cols() {
-				if c.Type != flux.TString {
-					// Skip any columns that aren't strings. They are extra ones that
-					// flux includes by default like the start and end times that we do not
-					// care about.
-					continue
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			row, err := e.encodeTable(tbl)
+			if err != nil {
+				return err
+			}
+			result.Series = append(result.Series, row)
+			return nil
+		}); err != nil {
+			resp.error(err)
+			results.Release()
+			break
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if err := results.Err(); err != nil && resp.Err == "" {
+		resp.error(err)
+	}
+
+	err := json.NewEncoder(wc).Encode(resp)
+	return wc.Count(), err
+}
+
+// encodeChunked writes each result as a stream of chunked responses.
+func (e *MultiResultEncoder) encodeChunked(w io.Writer, results flux.ResultIterator) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	enc := json.NewEncoder(wc)
+
+	// pending holds the last chunk of the current statement, which can only be
+	// written once it is known whether more chunks follow it.
+	var pending *Result
+	flush := func(partial bool) error {
+		if pending == nil {
+			return nil
+		}
+		pending.Partial = partial
+		err := enc.Encode(Response{Results: []Result{*pending}})
+		pending = nil
+		return err
+	}
+
+	writeErr := func(err error) (int64, error) {
+		var resp Response
+		resp.error(err)
+		if err := flush(false); err != nil {
+			return wc.Count(), err
+		}
+		return wc.Count(), enc.Encode(resp)
+	}
+
+	for results.More() {
+		res := results.Next()
+		id, err := statementID(res)
+		if err != nil {
+			results.Release()
+			return writeErr(err)
+		}
+
+		pending = &Result{StatementID: id}
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			row, err := e.encodeTable(tbl)
+			if err != nil {
+				return err
+			}
+
+			for values := row.Values; ; {
+				chunk := *row
+				n := len(values)
+				if n > e.ChunkSize {
+					n = e.ChunkSize
 				}
-				v := tbl.Key().Value(j).Str()
-				if c.Label == "_measurement" {
-					row.Name = v
-				} else if c.Label == "_field" {
-					// If the field key was not removed by a previous operation, we explicitly
-					// ignore it here when encoding the result back.
-				} else {
-					if row.Tags == nil {
-						row.Tags = make(map[string]string)
+				chunk.Values, values = values[:n], values[n:]
+				chunk.Partial = len(values) > 0
+
+				// the previous chunk is not the last one for this statement
+				if len(pending.Series) > 0 {
+					if err := flush(true); err != nil {
+						return err
 					}
-					row.Tags[c.Label] = v
+					pending = &Result{StatementID: id}
 				}
-			}
+				pending.Series = []*Row{&chunk}
 
-			// TODO: resultColMap should be constructed from query metadata once it is provided.
-			// for now we know that an influxql query ALWAYS has time first, so we put this placeholder
-			// here to catch this most obvious requirement.  Column orderings should be explicitly determined
-			// from the ordering given in the original flux.
-			resultColMap := map[string]int{}
-			j := 1
-			for _, c := range tbl.Cols() {
-				if c.Label == execute.DefaultTimeColLabel {
-					resultColMap[c.Label] = 0
-				} else if !tbl.Key().HasCol(c.Label) {
-					resultColMap[c.Label] = j
-					j++
+				if len(values) == 0 {
+					return nil
 				}
 			}
+		}); err != nil {
+			results.Release()
+			return writeErr(err)
+		}
 
-			if _, ok := resultColMap[execute.DefaultTimeColLabel]; !ok {
-				for k, v := range resultColMap {
-					resultColMap[k] = v - 1
-				}
+		if err := flush(false); err != nil {
+			return wc.Count(), err
+		}
+	}
+
+	if err := results.Err(); err != nil {
+		return writeErr(err)
+	}
+	return wc.Count(), nil
+}
+
+// statementID interprets the result name as the statement id.
+func statementID(res flux.Result) (int, error) {
+	id, err := strconv.Atoi(res.Name())
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse statement id from result name: %s", err)
+	}
+	return id, nil
+}
+
+// encodeTable converts a table into a single series.
+func (e *MultiResultEncoder) encodeTable(tbl flux.Table) (*Row, error) {
+	var row Row
+
+	for j, c := range tbl.Key().Cols() {
+		if c.Type != flux.TString {
+			// Skip any columns that aren't strings. They are extra ones that
+			// flux includes by default like the start and end times that we do not
+			// care about.
+			continue
+		}
+		v := tbl.Key().Value(j).Str()
+		if c.Label == "_measurement" {
+			row.Name = v
+		} else if c.Label == "_field" {
+			// If the field key was not removed by a previous operation, we explicitly
+			// ignore it here when encoding the result back.
+		} else {
+			if row.Tags == nil {
+				row.Tags = make(map[string]string)
 			}
+			row.Tags[c.Label] = v
+		}
+	}
 
-			row.Columns = make([]string, len(resultColMap))
-			for k, v := range resultColMap {
-				if k == execute.DefaultTimeColLabel {
-					k = "time"
-				}
-				row.Columns[v] = k
+	// TODO: resultColMap should be constructed from query metadata once it is provided.
+	// for now we know that an influxql query ALWAYS has time first, so we put this placeholder
+	// here to catch this most obvious requirement.  Column orderings should be explicitly determined
+	// from the ordering given in the original flux.
+	resultColMap := map[string]int{}
+	j := 1
+	for _, c := range tbl.Cols() {
+		if c.Label == execute.DefaultTimeColLabel {
+			resultColMap[c.Label] = 0
+		} else if !tbl.Key().HasCol(c.Label) {
+			resultColMap[c.Label] = j
+			j++
+		}
+	}
+
+	if _, ok := resultColMap[execute.DefaultTimeColLabel]; !ok {
+		for k, v := range resultColMap {
+			resultColMap[k] = v - 1
+		}
+	}
+
+	row.Columns = make([]string, len(resultColMap))
+	for k, v := range resultColMap {
+		if k == execute.DefaultTimeColLabel {
+			k = "time"
+		}
+		row.Columns[v] = k
+	}
+
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		// Preallocate the number of rows for the response to make this section
+		// of code easier to read. Find a time column which should exist
+		// in the output.
+		values := make([][]interface{}, cr.Len())
+		for j := range values {
+			values[j] = make([]interface{}, len(row.Columns))
+		}
+
+		j := 0
+		for idx, c := range tbl.Cols() {
+			if cr.Key().HasCol(c.Label) {
+				continue
 			}
 
-			if err := tbl.Do(func(cr flux.ColReader) error {
-				// Preallocate the number of rows for the response to make this section
-				// of code easier to read. Find a time column which should exist
-				// in the output.
-				values := make([][]interface{}, cr.Len())
-				for j := range values {
-					values[j] = make([]interface{}, len(row.Columns))
+			j = resultColMap[c.Label]
+			// Fill in the values for each column.
+			switch c.Type {
+			case flux.TFloat:
+				vs := cr.Floats(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
 				}
-
-				j := 0
-				for idx, c := range tbl.Cols() {
-					if cr.Key().HasCol(c.Label) {
-						continue
+			case flux.TInt:
+				vs := cr.Ints(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
 					}
-
-					j = resultColMap[c.Label]
-					// Fill in the values for each column.
-					switch c.Type {
-					case flux.TFloat:
-						vs := cr.Floats(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TInt:
-						vs := cr.Ints(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TString:
-						vs := cr.Strings(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.ValueString(i)
-							}
-						}
-					case flux.TUInt:
-						vs := cr.UInts(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TBool:
-						vs := cr.Bools(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TTime:
-						vs := cr.Times(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
-							}
-						}
-					default:
-						return fmt.Errorf("unsupported column type: %s", c.Type)
+				}
+			case flux.TString:
+				vs := cr.Strings(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.ValueString(i)
 					}
-
 				}
-				row.Values = append(row.Values, values...)
-				return nil
-			}); err != nil {
-				return err
+			case flux.TUInt:
+				vs := cr.UInts(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
+				}
+			case flux.TBool:
+				vs := cr.Bools(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
+				}
+			case flux.TTime:
+				vs := cr.Times(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = e.formatTime(execute.Time(vs.Value(i)))
+					}
+				}
+			default:
+				return fmt.Errorf("unsupported column type: %s", c.Type)
 			}
 
-			result.Series = append(result.Series, &row)
-			return nil
-		}); err != nil {
-			resp.error(err)
-			results.Release()
-			break
 		}
-		resp.Results = append(resp.Results, result)
+		row.Values = append(row.Values, values...)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	if err := results.Err(); err != nil && resp.Err == "" {
-		resp.error(err)
-	}
+	return &row, nil
+}
 
-	err := json.NewEncoder(wc).Encode(resp)
-	return wc.Count(), err
+// formatTime formats t according to the configured TimeFormat.
+func (e *MultiResultEncoder) formatTime(t execute.Time) interface{} {
+	var d time.Duration
+	switch e.TimeFormat {
+	case Hour:
+		d = time.Hour
+	case Minute:
+		d = time.Minute
+	case Second:
+		d = time.Second
+	case Millisecond:
+		d = time.Millisecond
+	case Microsecond:
+		d = time.Microsecond
+	case Nanosecond:
+		d = time.Nanosecond
+	default:
+		return t.Time().Format(time.RFC3339Nano)
+	}
+	return int64(t) / int64(d)
 }
+
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
 }
@@ -659,9 +659,10 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 			return nil, errors.New("database is required")
 		}
 		db = t.config.DefaultDatabase
-	}
-	if rp == "" {
-		if t.config.DefaultRetentionPolicy != "" {
+
+		// the default retention policy is one of the default database; another
+		// database is read with its own default retention policy.
+		if rp == "" {
 			rp = t.config.DefaultRetentionPolicy
 		}
 	}
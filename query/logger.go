@@ -16,6 +16,8 @@ type Logger interface {
 type Log struct {
 	// Time is the time the query was completed
 	Time time.Time
+	// Duration is the time from the request of the query until it was completed
+	Duration time.Duration
 	// OrganizationID is the ID of the organization that requested the query
 	OrganizationID platform.ID
 	// TraceID is the ID of the trace related to this query
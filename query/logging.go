@@ -41,6 +41,7 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	start := s.nowFunction()
 	var n int64
 	defer func() {
 		if r := recover(); r != nil {
@@ -51,13 +52,15 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 			}
 		}
 		traceID, sampled, _ := tracing.InfoFromContext(ctx)
+		now := s.nowFunction()
 		log := Log{
 			OrganizationID: req.Request.OrganizationID,
 			TraceID:        traceID,
 			Sampled:        sampled,
 			ProxyRequest:   req,
 			ResponseSize:   n,
-			Time:           s.nowFunction(),
+			Time:           now,
+			Duration:       now.Sub(start),
 			Statistics:     stats,
 			Error:          err,
 		}
@@ -66,10 +69,10 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 
 	wc := &iocounter.Writer{Writer: w}
 	stats, err = s.proxyQueryService.Query(ctx, wc, req)
+	n = wc.Count()
 	if err != nil {
 		return stats, tracing.LogError(span, err)
 	}
-	n = wc.Count()
 	return stats, nil
 }
 
@@ -0,0 +1,361 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+)
+
+// ParamsIdentifier is the identifier of the record which the
+// parameters of a query are bound to, such as params.bucket.
+const ParamsIdentifier = "params"
+
+// The types of the parameters which are encoded as JSON objects,
+// such as {"type": "duration", "value": "1h30m"}.
+const (
+	durationParamType = "duration"
+	timeParamType     = "time"
+)
+
+// Duration is a duration parameter in the syntax of Flux durations,
+// which may have calendar units such as 1mo.
+type Duration string
+
+// Params are the parameters of a query, which are bound to the params record
+// of the query as literals. The values of parameters are never spliced into
+// the text of a query, so that they cannot change its meaning.
+//
+// A parameter is a string, a boolean, an integer, an unsigned integer, a float,
+// a time.Time, a time.Duration, a Duration, or an array of parameters. Arrays
+// are slices of any of these types. In JSON, times and durations are objects
+// with a type and a value, such as {"type": "time", "value": "2020-05-20T18:40:00Z"}.
+type Params map[string]interface{}
+
+// Bind returns the compiler with the parameters bound to the params record of
+// its query. The compiler is returned unchanged if there are no parameters.
+func (p Params) Bind(c flux.Compiler) (flux.Compiler, error) {
+	if len(p) == 0 {
+		return c, nil
+	}
+	file, err := p.File()
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := c.(type) {
+	case lang.FluxCompiler:
+		c.Extern = appendFile(c.Extern, file)
+		return c, nil
+	case *lang.FluxCompiler:
+		bound := *c
+		bound.Extern = appendFile(c.Extern, file)
+		return bound, nil
+	case lang.ASTCompiler:
+		return bindAST(c, file), nil
+	case *lang.ASTCompiler:
+		return bindAST(*c, file), nil
+	default:
+		return nil, fmt.Errorf("query parameters are not supported by %s queries", c.CompilerType())
+	}
+}
+
+// bindAST prepends the file to a copy of the package of the compiler,
+// so that the package of the request itself is left unchanged.
+func bindAST(c lang.ASTCompiler, file *ast.File) lang.ASTCompiler {
+	if c.AST != nil {
+		c.AST = c.AST.Copy().(*ast.Package)
+	} else {
+		c.AST = &ast.Package{}
+	}
+	c.PrependFile(file)
+	return c
+}
+
+// appendFile returns the external declarations with the statements of the file
+// appended to them, which are evaluated before the query.
+func appendFile(extern, file *ast.File) *ast.File {
+	if extern == nil {
+		return file
+	}
+	merged := extern.Copy().(*ast.File)
+	merged.Body = append(merged.Body, file.Body...)
+	return merged
+}
+
+// File returns the file of the assignment of the parameters to the params record.
+func (p Params) File() (*ast.File, error) {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	record := &ast.ObjectExpression{
+		Properties: make([]*ast.Property, 0, len(keys)),
+	}
+	for _, k := range keys {
+		if !isIdentifier(k) {
+			return nil, fmt.Errorf("invalid query parameter name %q", k)
+		}
+		v, err := paramExpression(p[k])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q: %v", k, err)
+		}
+		record.Properties = append(record.Properties, &ast.Property{
+			Key:   &ast.Identifier{Name: k},
+			Value: v,
+		})
+	}
+	return &ast.File{
+		Body: []ast.Statement{
+			&ast.VariableAssignment{
+				ID:   &ast.Identifier{Name: ParamsIdentifier},
+				Init: record,
+			},
+		},
+	}, nil
+}
+
+// paramExpression returns the literal of the value of a parameter.
+func paramExpression(v interface{}) (ast.Expression, error) {
+	switch v := v.(type) {
+	case string:
+		return &ast.StringLiteral{Value: v}, nil
+	case bool:
+		return &ast.BooleanLiteral{Value: v}, nil
+	case int:
+		return &ast.IntegerLiteral{Value: int64(v)}, nil
+	case int32:
+		return &ast.IntegerLiteral{Value: int64(v)}, nil
+	case int64:
+		return &ast.IntegerLiteral{Value: v}, nil
+	case uint:
+		return &ast.UnsignedIntegerLiteral{Value: uint64(v)}, nil
+	case uint32:
+		return &ast.UnsignedIntegerLiteral{Value: uint64(v)}, nil
+	case uint64:
+		return &ast.UnsignedIntegerLiteral{Value: v}, nil
+	case float32:
+		return &ast.FloatLiteral{Value: float64(v)}, nil
+	case float64:
+		return &ast.FloatLiteral{Value: v}, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return &ast.IntegerLiteral{Value: i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.FloatLiteral{Value: f}, nil
+	case time.Time:
+		return &ast.DateTimeLiteral{Value: v}, nil
+	case time.Duration:
+		return durationExpression(formatDuration(v))
+	case Duration:
+		return durationExpression(string(v))
+	case nil:
+		return nil, fmt.Errorf("null values are not supported")
+	}
+
+	rv := reflect.ValueOf(v)
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+	array := &ast.ArrayExpression{
+		Elements: make([]ast.Expression, rv.Len()),
+	}
+	for i := 0; i < rv.Len(); i++ {
+		e, err := paramExpression(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		array.Elements[i] = e
+	}
+	return array, nil
+}
+
+// durationExpression returns the literal of a Flux duration, which is the
+// negation of the literal of its magnitude if the duration is negative.
+func durationExpression(s string) (ast.Expression, error) {
+	neg := strings.HasPrefix(s, "-")
+	lit, err := parser.ParseDuration(strings.TrimPrefix(s, "-"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration %q: %v", s, err)
+	}
+	// The location of the literal refers to the source of the
+	// literal, which is not the source of the query.
+	lit.Loc = nil
+	if neg {
+		return &ast.UnaryExpression{
+			Operator: ast.SubtractionOperator,
+			Argument: lit,
+		}, nil
+	}
+	return lit, nil
+}
+
+// durationUnits are the units which a time.Duration is formatted in.
+var durationUnits = []struct {
+	unit string
+	d    time.Duration
+}{
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"us", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+// formatDuration formats a time.Duration in the syntax of Flux durations,
+// which has no fractions unlike time.Duration.String.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+	}
+	// The magnitude is unsigned so that the smallest duration can be negated.
+	n := uint64(d)
+	if d < 0 {
+		n = -n
+	}
+	for _, u := range durationUnits {
+		if m := n / uint64(u.d); m > 0 {
+			fmt.Fprintf(&b, "%d%s", m, u.unit)
+			n -= m * uint64(u.d)
+		}
+	}
+	return b.String()
+}
+
+// isIdentifier returns whether the name is a Flux identifier,
+// so that the parameter is a property of the params record.
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// MarshalJSON encodes the parameters with times and durations as typed objects.
+func (p Params) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p))
+	for k, v := range p {
+		m[k] = encodeParam(v)
+	}
+	return json.Marshal(m)
+}
+
+func encodeParam(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		return typedParam{Type: timeParamType, Value: v.Format(time.RFC3339Nano)}
+	case time.Duration:
+		return typedParam{Type: durationParamType, Value: formatDuration(v)}
+	case Duration:
+		return typedParam{Type: durationParamType, Value: string(v)}
+	case string:
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return v
+	}
+	vs := make([]interface{}, rv.Len())
+	for i := range vs {
+		vs[i] = encodeParam(rv.Index(i).Interface())
+	}
+	return vs
+}
+
+// UnmarshalJSON decodes the parameters. Numbers are integers unless they
+// have a fraction or an exponent, and objects are times and durations.
+func (p *Params) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*p = nil
+		return nil
+	}
+
+	params := make(Params, len(raw))
+	for k, v := range raw {
+		param, err := decodeParam(v)
+		if err != nil {
+			return fmt.Errorf("invalid query parameter %q: %v", k, err)
+		}
+		params[k] = param
+	}
+	*p = params
+	return nil
+}
+
+// typedParam is the encoding of a parameter whose type is not a JSON type.
+type typedParam struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func decodeParam(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		vs := make([]interface{}, len(v))
+		for i := range v {
+			e, err := decodeParam(v[i])
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = e
+		}
+		return vs, nil
+	case map[string]interface{}:
+		typ, _ := v["type"].(string)
+		value, ok := v["value"].(string)
+		if !ok || len(v) != 2 {
+			return nil, fmt.Errorf(`objects must have a type and a string value`)
+		}
+		switch typ {
+		case timeParamType:
+			return time.Parse(time.RFC3339Nano, value)
+		case durationParamType:
+			if _, err := durationExpression(value); err != nil {
+				return nil, err
+			}
+			return Duration(value), nil
+		default:
+			return nil, fmt.Errorf("unknown type %q", typ)
+		}
+	case nil:
+		return nil, fmt.Errorf("null values are not supported")
+	}
+	return v, nil
+}
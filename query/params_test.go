@@ -0,0 +1,177 @@
+package query_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestParams_File(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  query.Params
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "strings are never spliced into the query",
+			params: query.Params{"bucket": `b") |> drop(columns: ["_value"]) //`},
+			want:   `params = {bucket: "b\") |> drop(columns: [\"_value\"]) //"}`,
+		},
+		{
+			name:   "numbers",
+			params: query.Params{"i": -1, "u": uint64(2), "f": 1.0},
+			want:   `params = {f: 1.0, i: -1, u: 2}`,
+		},
+		{
+			name: "times and durations",
+			params: query.Params{
+				"start": time.Date(2020, 5, 20, 18, 40, 0, 0, time.UTC),
+				"every": 90 * time.Second,
+				"ago":   query.Duration("-1mo"),
+			},
+			want: `params = {ago: -1mo, every: 1m30s, start: 2020-05-20T18:40:00Z}`,
+		},
+		{
+			name:   "arrays",
+			params: query.Params{"hosts": []string{"a", "b"}, "empty": []interface{}{}},
+			want:   `params = {empty: [], hosts: ["a", "b"]}`,
+		},
+		{
+			name:    "name must be an identifier",
+			params:  query.Params{"a-b": "c"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			params:  query.Params{"m": map[string]string{}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			params:  query.Params{"d": query.Duration("1.5h")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := tt.params.File()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("File() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := ast.Format(file); got != tt.want {
+				t.Errorf("unexpected file:\ngot  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParams_JSON(t *testing.T) {
+	data := []byte(`{
+		"s": "a",
+		"i": 10,
+		"f": 1.5,
+		"b": true,
+		"d": {"type": "duration", "value": "1h"},
+		"t": {"type": "time", "value": "2020-05-20T18:40:00Z"},
+		"a": [1, 2]
+	}`)
+	var got query.Params
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := query.Params{
+		"s": "a",
+		"i": int64(10),
+		"f": 1.5,
+		"b": true,
+		"d": query.Duration("1h"),
+		"t": time.Date(2020, 5, 20, 18, 40, 0, 0, time.UTC),
+		"a": []interface{}{int64(1), int64(2)},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected params -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	// The parameters are the same once encoded and decoded again.
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded query.Params
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, decoded) {
+		t.Errorf("unexpected params after encoding -want/+got:\n%s", cmp.Diff(want, decoded))
+	}
+
+	for _, data := range []string{
+		`{"n": null}`,
+		`{"d": {"type": "duration", "value": "1.5h"}}`,
+		`{"r": {"type": "record", "value": "a"}}`,
+	} {
+		var p query.Params
+		if err := json.Unmarshal([]byte(data), &p); err == nil {
+			t.Errorf("expected error decoding %s", data)
+		}
+	}
+}
+
+func TestParams_Bind(t *testing.T) {
+	params := query.Params{"bucket": "b"}
+
+	// The params record is declared after the external declarations.
+	extern := &ast.File{Body: []ast.Statement{
+		&ast.OptionStatement{
+			Assignment: &ast.VariableAssignment{
+				ID:   &ast.Identifier{Name: "now"},
+				Init: &ast.DateTimeLiteral{Value: time.Unix(0, 0).UTC()},
+			},
+		},
+	}}
+	c, err := params.Bind(lang.FluxCompiler{Query: `from(bucket: params.bucket)`, Extern: extern})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc := c.(lang.FluxCompiler)
+	if got, want := ast.Format(fc.Extern), "option now = 1970-01-01T00:00:00Z\n\nparams = {bucket: \"b\"}"; got != want {
+		t.Errorf("unexpected extern:\ngot  %s\nwant %s", got, want)
+	}
+	if len(extern.Body) != 1 {
+		t.Errorf("the extern of the request was modified")
+	}
+
+	// The package of the request is not modified.
+	pkg := parser.ParseSource(`from(bucket: params.bucket)`)
+	c, err = params.Bind(lang.ASTCompiler{AST: pkg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(c.(lang.ASTCompiler).AST.Files); got != 2 {
+		t.Errorf("unexpected number of files: %d", got)
+	}
+	if len(pkg.Files) != 1 {
+		t.Errorf("the package of the request was modified")
+	}
+
+	if _, err := params.Bind(repl.Compiler{}); err == nil {
+		t.Error("expected error binding params to a spec")
+	}
+
+	// Without params the compiler is unchanged.
+	compiler := lang.FluxCompiler{Query: `from(bucket: "b")`}
+	if c, err := query.Params(nil).Bind(compiler); err != nil || c != compiler {
+		t.Errorf("unexpected compiler %v, error %v", c, err)
+	}
+}
@@ -0,0 +1,545 @@
+package promql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+// DefaultLookbackDelta is how far back the latest sample of a series is
+// looked for when evaluating an instant vector selector, as in Prometheus.
+const DefaultLookbackDelta = 5 * time.Minute
+
+// MetricNameLabel is the label of the metric name of a series.
+const MetricNameLabel = "__name__"
+
+// FieldLabel is the label of the field of a series, which is only a label
+// if the field does not hold the value of the metric.
+const FieldLabel = "_field"
+
+// valueFields are the fields which hold the value of a metric. The field is
+// dropped from the labels of the series of these fields and kept otherwise,
+// such as for the quantiles and buckets of summaries and histograms.
+var valueFields = map[string]bool{
+	"value":   true,
+	"gauge":   true,
+	"counter": true,
+}
+
+// ResultType is the type of the result of an expression.
+type ResultType string
+
+// Possible ResultTypes.
+const (
+	VectorResult ResultType = "vector"
+	MatrixResult ResultType = "matrix"
+)
+
+// Sample is the value of a series at a time.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is the samples of a series, which is identified by its labels.
+type Series struct {
+	Labels  map[string]string
+	Samples []Sample
+}
+
+// ID returns the identity of the series, which is given by its labels.
+func (s *Series) ID() string {
+	return labelsID(s.Labels)
+}
+
+// Evaluation is the evaluation of expressions against a bucket, either at
+// a single time (an instant query) or at each step between Start and End
+// (a range query).
+type Evaluation struct {
+	BucketID string
+
+	// Start and End are the times of the evaluations of a range query.
+	// An instant query is evaluated at End only.
+	Start, End time.Time
+	// Step is the duration between the evaluations of a range query,
+	// and is zero for an instant query.
+	Step time.Duration
+	// LookbackDelta is how far back the latest sample of a series is looked
+	// for by an instant vector selector. DefaultLookbackDelta is used if zero.
+	LookbackDelta time.Duration
+}
+
+// Query is an expression compiled into a flux.Spec for an Evaluation.
+type Query struct {
+	Spec *flux.Spec
+	Type ResultType
+
+	start, end time.Time
+	offset     time.Duration
+	// raw is set when the samples are the samples of a range vector
+	// selector, rather than the samples at the times of the evaluation.
+	raw bool
+
+	// fn is the function of the range vector selector, which is evaluated
+	// from the raw samples of its range at each step of the evaluation.
+	fn   rangeFunction
+	rng  time.Duration
+	step time.Duration
+}
+
+// Query compiles the promql expression. Only vector selectors, aggregations
+// of instant vector selectors, and the rate, irate and increase functions of
+// range vector selectors are supported. The functions can not be aggregated.
+func (e *Evaluation) Query(promql string) (*Query, error) {
+	if name, arg, ok := parseCall(promql); ok {
+		return e.functionQuery(name, arg)
+	}
+	parsed, err := ParsePromQL(promql)
+	if err != nil {
+		return nil, err
+	}
+	switch expr := parsed.(type) {
+	case *Selector:
+		return e.selectorQuery(expr, nil)
+	case *AggregateExpr:
+		if expr.Selector.Range != 0 {
+			return nil, fmt.Errorf("unable to aggregate a range vector selector")
+		}
+		return e.selectorQuery(expr.Selector, expr)
+	default:
+		return nil, fmt.Errorf("unsupported expression %q", promql)
+	}
+}
+
+// parseCall splits a call of a function into the name of the function and
+// its argument. The aggregation operators are parsed as expressions instead.
+func parseCall(promql string) (name, arg string, ok bool) {
+	promql = strings.TrimSpace(promql)
+	i := strings.IndexByte(promql, '(')
+	if i < 0 || !strings.HasSuffix(promql, ")") {
+		return "", "", false
+	}
+	name = strings.TrimSpace(promql[:i])
+	if name == "" || ToOperatorKind(name) != UnknownOpKind {
+		return "", "", false
+	}
+	for _, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "", "", false
+		}
+	}
+	return name, promql[i+1 : len(promql)-1], true
+}
+
+// functionQuery compiles a query of the raw samples of the range vector
+// selector of the argument, from which the function is evaluated.
+func (e *Evaluation) functionQuery(name, arg string) (*Query, error) {
+	fn, ok := rangeFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function %q, only rate, irate and increase are supported", name)
+	}
+	parsed, err := ParsePromQL(arg)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := parsed.(*Selector)
+	if !ok || sel.Range == 0 {
+		return nil, fmt.Errorf("expected a range vector selector as the argument of %s", name)
+	}
+
+	q := &Query{
+		Type:   VectorResult,
+		start:  e.End,
+		end:    e.End,
+		offset: sel.Offset,
+		raw:    true,
+		fn:     fn,
+		rng:    sel.Range,
+	}
+	if e.Step != 0 {
+		if e.Step < 0 {
+			return nil, fmt.Errorf("step must be positive")
+		}
+		if e.End.Before(e.Start) {
+			return nil, fmt.Errorf("end time must not be before start time")
+		}
+		q.start = e.Start
+		q.end = e.Start.Add(e.End.Sub(e.Start) / e.Step * e.Step)
+		q.step = e.Step
+		q.Type = MatrixResult
+	}
+
+	// The samples of the evaluations are those of the ranges which end at
+	// the times of the evaluations, shifted back by the offset.
+	b, err := e.readSpec(sel, q.start.Add(-sel.Offset-sel.Range+1), q.end.Add(-sel.Offset+1))
+	if err != nil {
+		return nil, err
+	}
+	q.Spec = b.spec
+	return q, nil
+}
+
+func (e *Evaluation) selectorQuery(sel *Selector, agg *AggregateExpr) (*Query, error) {
+	lookback := e.LookbackDelta
+	if lookback <= 0 {
+		lookback = DefaultLookbackDelta
+	}
+
+	q := &Query{
+		Type:   VectorResult,
+		start:  e.End,
+		end:    e.End,
+		offset: sel.Offset,
+	}
+	isRange := e.Step != 0
+	if isRange {
+		if e.Step < 0 {
+			return nil, fmt.Errorf("step must be positive")
+		}
+		if e.End.Before(e.Start) {
+			return nil, fmt.Errorf("end time must not be before start time")
+		}
+		// The query is evaluated at each step from the start, up to the end.
+		q.start = e.Start
+		q.end = e.Start.Add(e.End.Sub(e.Start) / e.Step * e.Step)
+		q.Type = MatrixResult
+	}
+
+	// The samples at a time t are in the window (t - lookback, t], shifted
+	// back by the offset, which is [t - lookback + 1ns, t + 1ns) in Flux.
+	start := q.start.Add(-sel.Offset - lookback + 1)
+	if sel.Range != 0 {
+		if isRange {
+			return nil, fmt.Errorf("range vector selectors are only supported by instant queries")
+		}
+		start = q.end.Add(-sel.Offset - sel.Range + 1)
+		q.Type = MatrixResult
+		q.raw = true
+	}
+	stop := q.end.Add(-sel.Offset + 1)
+
+	b, err := e.readSpec(sel, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	if q.raw {
+		q.Spec = b.spec
+		return q, nil
+	}
+
+	if isRange {
+		b.add("window", &universe.WindowOpSpec{
+			Every:       flux.ConvertDuration(e.Step),
+			Period:      flux.ConvertDuration(lookback),
+			Offset:      flux.ConvertDuration(windowOffset(stop, e.Step)),
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+		})
+	}
+	b.add("last", &universe.LastOpSpec{
+		SelectorConfig: execute.DefaultSelectorConfig,
+	})
+
+	if agg != nil {
+		// The windows of the evaluations are kept apart by their bounds,
+		// and the fields of a metric by their field.
+		group := &universe.GroupOpSpec{
+			Mode:    "by",
+			Columns: []string{execute.DefaultStartColLabel, execute.DefaultStopColLabel, "_field"},
+		}
+		if a := agg.Aggregate; a != nil {
+			if a.Without {
+				return nil, fmt.Errorf("unable to aggregate using `without`")
+			}
+			for _, l := range a.Labels {
+				group.Columns = append(group.Columns, l.Name)
+			}
+		}
+		b.add("group", group)
+
+		id, spec, err := aggregateOp(agg.Op)
+		if err != nil {
+			return nil, err
+		}
+		b.add(id, spec)
+	}
+	q.Spec = b.spec
+	return q, nil
+}
+
+// readSpec returns a builder of a spec which reads the series of the
+// selector between start and stop.
+func (e *Evaluation) readSpec(sel *Selector, start, stop time.Time) (*specBuilder, error) {
+	b := &specBuilder{spec: &flux.Spec{}}
+	b.add("from", &influxdb.FromOpSpec{
+		BucketID: e.BucketID,
+	})
+	b.add("range", &universe.RangeOpSpec{
+		Start: flux.Time{Absolute: start},
+		Stop:  flux.Time{Absolute: stop},
+	})
+	if sel.Name != "" || len(sel.LabelMatchers) > 0 {
+		where, err := NewWhereOperation(sel.Name, sel.LabelMatchers)
+		if err != nil {
+			return nil, err
+		}
+		b.add(where.ID, where.Spec)
+	}
+	return b, nil
+}
+
+// windowOffset returns the offset of the windows of a step which stop at stop.
+func windowOffset(stop time.Time, step time.Duration) time.Duration {
+	offset := time.Duration(stop.UnixNano() % int64(step))
+	if offset < 0 {
+		offset += step
+	}
+	return offset
+}
+
+// aggregateOp returns the operation of the aggregation of the operator.
+func aggregateOp(op *Operator) (flux.OperationID, flux.OperationSpec, error) {
+	switch op.Kind {
+	case SumKind:
+		return "sum", &universe.SumOpSpec{AggregateConfig: execute.DefaultAggregateConfig}, nil
+	case CountKind:
+		return "count", &universe.CountOpSpec{AggregateConfig: execute.DefaultAggregateConfig}, nil
+	case AvgKind:
+		return "mean", &universe.MeanOpSpec{AggregateConfig: execute.DefaultAggregateConfig}, nil
+	case MinKind:
+		return "min", &universe.MinOpSpec{SelectorConfig: execute.DefaultSelectorConfig}, nil
+	case MaxKind:
+		return "max", &universe.MaxOpSpec{SelectorConfig: execute.DefaultSelectorConfig}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported aggregation operator %d", op.Kind)
+	}
+}
+
+// specBuilder builds a flux.Spec of a chain of operations.
+type specBuilder struct {
+	spec *flux.Spec
+}
+
+func (b *specBuilder) add(id flux.OperationID, spec flux.OperationSpec) {
+	if n := len(b.spec.Operations); n > 0 {
+		b.spec.Edges = append(b.spec.Edges, flux.Edge{
+			Parent: b.spec.Operations[n-1].ID,
+			Child:  id,
+		})
+	}
+	b.spec.Operations = append(b.spec.Operations, &flux.Operation{
+		ID:   id,
+		Spec: spec,
+	})
+}
+
+// Series reads the series of the results of the query, sorted by their
+// labels. The tables of a series are merged and their samples sorted by time.
+func (q *Query) Series(results flux.ResultIterator) ([]*Series, error) {
+	defer results.Release()
+
+	index := make(map[string]*Series)
+	for results.More() {
+		if err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			labels := seriesLabels(tbl.Key())
+			id := labelsID(labels)
+			s, ok := index[id]
+			if !ok {
+				s = &Series{Labels: labels}
+				index[id] = s
+			}
+			return tbl.Do(func(cr flux.ColReader) error {
+				return q.appendSamples(s, cr)
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+	results.Release()
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+
+	if q.fn != nil {
+		// The metric name is dropped from the series of a function,
+		// which must still be told apart by their other labels.
+		evaluated := make(map[string]*Series, len(index))
+		for _, s := range index {
+			sortSamples(s.Samples)
+			s.Samples = q.evaluate(s.Samples)
+			delete(s.Labels, MetricNameLabel)
+			id := s.ID()
+			if _, ok := evaluated[id]; ok {
+				return nil, fmt.Errorf("vector cannot contain metrics with the same labelset")
+			}
+			evaluated[id] = s
+		}
+		index = evaluated
+	}
+
+	ids := make([]string, 0, len(index))
+	for id := range index {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	series := make([]*Series, len(ids))
+	for i, id := range ids {
+		s := index[id]
+		sortSamples(s.Samples)
+		series[i] = s
+	}
+	return series, nil
+}
+
+func sortSamples(samples []Sample) {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+}
+
+// evaluate evaluates the function of the query at each step from the raw
+// samples of the range which ends at the step, shifted back by the offset.
+func (q *Query) evaluate(samples []Sample) []Sample {
+	var evaluated []Sample
+	for t := q.start; !t.After(q.end); t = t.Add(q.step) {
+		end := t.Add(-q.offset)
+		start := end.Add(-q.rng)
+		// The range of the samples is (start, end].
+		i := sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(start) })
+		j := sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(end) })
+		if v, ok := q.fn(samples[i:j], start, end); ok {
+			evaluated = append(evaluated, Sample{Time: t, Value: v})
+		}
+		if q.step == 0 {
+			break
+		}
+	}
+	return evaluated
+}
+
+// appendSamples appends the samples of the rows of the column reader to the series.
+// The time of the sample of a window is the stop of the window, which is the
+// time of its evaluation shifted back by the offset of the selector.
+func (q *Query) appendSamples(s *Series, cr flux.ColReader) error {
+	timeCol := execute.DefaultStopColLabel
+	if q.raw {
+		timeCol = execute.DefaultTimeColLabel
+	}
+	tj := execute.ColIdx(timeCol, cr.Cols())
+	vj := execute.ColIdx(execute.DefaultValueColLabel, cr.Cols())
+	if tj < 0 || vj < 0 {
+		return nil
+	}
+	if typ := cr.Cols()[tj].Type; typ != flux.TTime {
+		return fmt.Errorf("column %q is of type %s, not time", timeCol, typ)
+	}
+
+	times := cr.Times(tj)
+	for i := 0; i < cr.Len(); i++ {
+		v, ok, err := floatValue(cr, vj, i)
+		if err != nil {
+			return err
+		}
+		if !ok || times.IsNull(i) {
+			continue
+		}
+		t := time.Unix(0, times.Value(i)).UTC()
+		if !q.raw {
+			t = t.Add(q.offset - 1)
+			if t.Before(q.start) || t.After(q.end) {
+				continue
+			}
+		}
+		s.Samples = append(s.Samples, Sample{Time: t, Value: v})
+	}
+	return nil
+}
+
+// floatValue returns the value of the row of the column as a float,
+// and whether the value is not null.
+func floatValue(cr flux.ColReader, j, i int) (float64, bool, error) {
+	switch typ := cr.Cols()[j].Type; typ {
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		return vs.Value(i), vs.IsValid(i), nil
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return float64(vs.Value(i)), vs.IsValid(i), nil
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return float64(vs.Value(i)), vs.IsValid(i), nil
+	default:
+		return 0, false, fmt.Errorf("unsupported value type %s", typ)
+	}
+}
+
+// seriesLabels returns the labels of the series of the group key, which are
+// its string columns. The measurement is the metric name and the field is
+// only a label if it is not the value of the metric.
+func seriesLabels(key flux.GroupKey) map[string]string {
+	labels := make(map[string]string)
+	for j, c := range key.Cols() {
+		if c.Type != flux.TString || key.IsNull(j) {
+			continue
+		}
+		setLabel(labels, c.Label, key.ValueString(j))
+	}
+	return labels
+}
+
+// TagsLabels returns the labels of the series with the tags of the storage,
+// as seriesLabels does for the columns of a group key.
+func TagsLabels(tags models.Tags) map[string]string {
+	labels := make(map[string]string, len(tags))
+	for _, t := range tags {
+		switch k := string(t.Key); k {
+		case models.MeasurementTagKey:
+			setLabel(labels, MetricNameColumn, string(t.Value))
+		case models.FieldKeyTagKey:
+			setLabel(labels, FieldLabel, string(t.Value))
+		default:
+			setLabel(labels, k, string(t.Value))
+		}
+	}
+	return labels
+}
+
+func setLabel(labels map[string]string, column, value string) {
+	switch column {
+	case MetricNameColumn:
+		labels[MetricNameLabel] = value
+	case FieldLabel:
+		if !valueFields[value] {
+			labels[column] = value
+		}
+	default:
+		labels[column] = value
+	}
+}
+
+// labelsID returns the identity of a set of labels.
+func labelsID(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0xfe)
+		b.WriteString(labels[k])
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
@@ -0,0 +1,314 @@
+package promql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+func TestEvaluation_Query(t *testing.T) {
+	end := time.Unix(1000, 0).UTC()
+	tests := []struct {
+		name     string
+		eval     Evaluation
+		promql   string
+		wantOps  []flux.OperationID
+		wantType ResultType
+		wantErr  bool
+
+		// The bounds of the range and the window of the query, if any.
+		wantStart, wantStop time.Time
+		wantWindow          *universe.WindowOpSpec
+	}{
+		{
+			name:      "instant vector",
+			eval:      Evaluation{BucketID: "b", End: end},
+			promql:    `node_cpu{mode="user"}`,
+			wantOps:   []flux.OperationID{"from", "range", "where", "last"},
+			wantType:  VectorResult,
+			wantStart: end.Add(-DefaultLookbackDelta + 1),
+			wantStop:  end.Add(1),
+		},
+		{
+			name:      "range vector with offset",
+			eval:      Evaluation{BucketID: "b", End: end},
+			promql:    `node_cpu{mode="user"}[2m] offset 1m`,
+			wantOps:   []flux.OperationID{"from", "range", "where"},
+			wantType:  MatrixResult,
+			wantStart: end.Add(-3*time.Minute + 1),
+			wantStop:  end.Add(-time.Minute + 1),
+		},
+		{
+			name:      "aggregate",
+			eval:      Evaluation{BucketID: "b", End: end, LookbackDelta: time.Minute},
+			promql:    `sum by (cpu) (node_cpu)`,
+			wantOps:   []flux.OperationID{"from", "range", "where", "last", "group", "sum"},
+			wantType:  VectorResult,
+			wantStart: end.Add(-time.Minute + 1),
+			wantStop:  end.Add(1),
+		},
+		{
+			name: "range query",
+			eval: Evaluation{
+				BucketID: "b",
+				Start:    end.Add(-time.Hour),
+				End:      end.Add(30 * time.Second),
+				Step:     time.Minute,
+			},
+			promql:    `node_cpu`,
+			wantOps:   []flux.OperationID{"from", "range", "where", "window", "last"},
+			wantType:  MatrixResult,
+			wantStart: end.Add(-time.Hour - DefaultLookbackDelta + 1),
+			wantStop:  end.Add(1),
+			wantWindow: &universe.WindowOpSpec{
+				Every:       flux.ConvertDuration(time.Minute),
+				Period:      flux.ConvertDuration(DefaultLookbackDelta),
+				Offset:      flux.ConvertDuration(40*time.Second + 1),
+				TimeColumn:  execute.DefaultTimeColLabel,
+				StartColumn: execute.DefaultStartColLabel,
+				StopColumn:  execute.DefaultStopColLabel,
+			},
+		},
+		{
+			name:      "rate of range vector",
+			eval:      Evaluation{BucketID: "b", End: end},
+			promql:    `rate(node_cpu{mode="user"}[2m])`,
+			wantOps:   []flux.OperationID{"from", "range", "where"},
+			wantType:  VectorResult,
+			wantStart: end.Add(-2*time.Minute + 1),
+			wantStop:  end.Add(1),
+		},
+		{
+			name:      "increase in range query",
+			eval:      Evaluation{BucketID: "b", Start: end.Add(-time.Hour), End: end, Step: time.Minute},
+			promql:    `increase(node_cpu[5m] offset 1m)`,
+			wantOps:   []flux.OperationID{"from", "range", "where"},
+			wantType:  MatrixResult,
+			wantStart: end.Add(-time.Hour - 6*time.Minute + 1),
+			wantStop:  end.Add(-time.Minute + 1),
+		},
+		{
+			name:    "function of instant vector",
+			eval:    Evaluation{BucketID: "b", End: end},
+			promql:  `rate(node_cpu)`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported function",
+			eval:    Evaluation{BucketID: "b", End: end},
+			promql:  `delta(node_cpu[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "range vector in range query",
+			eval:    Evaluation{BucketID: "b", Start: end.Add(-time.Hour), End: end, Step: time.Minute},
+			promql:  `node_cpu[5m]`,
+			wantErr: true,
+		},
+		{
+			name:    "aggregate of range vector",
+			eval:    Evaluation{BucketID: "b", End: end},
+			promql:  `sum(node_cpu[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "aggregate without",
+			eval:    Evaluation{BucketID: "b", End: end},
+			promql:  `sum without (cpu) (node_cpu)`,
+			wantErr: true,
+		},
+		{
+			name:    "comment",
+			eval:    Evaluation{BucketID: "b", End: end},
+			promql:  `# just a comment`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.eval.Query(tt.promql)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Query() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var ops []flux.OperationID
+			for _, op := range q.Spec.Operations {
+				ops = append(ops, op.ID)
+				switch spec := op.Spec.(type) {
+				case *universe.RangeOpSpec:
+					if !spec.Start.Absolute.Equal(tt.wantStart) || !spec.Stop.Absolute.Equal(tt.wantStop) {
+						t.Errorf("unexpected range: got [%s, %s) want [%s, %s)",
+							spec.Start.Absolute, spec.Stop.Absolute, tt.wantStart, tt.wantStop)
+					}
+				case *universe.WindowOpSpec:
+					if !cmp.Equal(tt.wantWindow, spec) {
+						t.Errorf("unexpected window -want/+got:\n%s", cmp.Diff(tt.wantWindow, spec))
+					}
+				}
+			}
+			if !cmp.Equal(tt.wantOps, ops) {
+				t.Errorf("unexpected operations -want/+got:\n%s", cmp.Diff(tt.wantOps, ops))
+			}
+			if len(q.Spec.Edges) != len(ops)-1 {
+				t.Errorf("unexpected number of edges %d", len(q.Spec.Edges))
+			}
+			if q.Type != tt.wantType {
+				t.Errorf("unexpected result type: got %s want %s", q.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestQuery_Series(t *testing.T) {
+	start := time.Unix(60, 0).UTC()
+	eval := Evaluation{
+		BucketID: "b",
+		Start:    start,
+		End:      start.Add(2 * time.Minute),
+		Step:     time.Minute,
+	}
+	q, err := eval.Query(`node_cpu`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stop of the window of each evaluation is 1ns after its time.
+	stop := func(t time.Time) execute.Time {
+		return execute.Time(t.UnixNano() + 1)
+	}
+	cols := []flux.ColMeta{
+		{Label: "_start", Type: flux.TTime},
+		{Label: "_stop", Type: flux.TTime},
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "_field", Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "cpu", Type: flux.TString},
+	}
+	keyCols := []string{"_start", "_stop", "_field", "_measurement", "cpu"}
+	tables := []*executetest.Table{
+		{
+			KeyCols: keyCols,
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), stop(start.Add(time.Minute)), execute.Time(100), 2.0, "gauge", "node_cpu", "cpu1"},
+			},
+		},
+		{
+			KeyCols: keyCols,
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), stop(start), execute.Time(50), 1.0, "gauge", "node_cpu", "cpu1"},
+			},
+		},
+		{
+			KeyCols: keyCols,
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), stop(start), execute.Time(50), 3.0, "0.5", "node_cpu", "cpu0"},
+			},
+		},
+		{
+			// The window after the end of the query is dropped.
+			KeyCols: keyCols,
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), stop(start.Add(3 * time.Minute)), execute.Time(150), 4.0, "0.5", "node_cpu", "cpu0"},
+			},
+		},
+	}
+	result := executetest.NewResult(tables)
+	got, err := q.Series(flux.NewSliceResultIterator([]flux.Result{result}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Series{
+		{
+			Labels:  map[string]string{"__name__": "node_cpu", "_field": "0.5", "cpu": "cpu0"},
+			Samples: []Sample{{Time: start, Value: 3}},
+		},
+		{
+			Labels: map[string]string{"__name__": "node_cpu", "cpu": "cpu1"},
+			Samples: []Sample{
+				{Time: start, Value: 1},
+				{Time: start.Add(time.Minute), Value: 2},
+			},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected series -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestQuery_SeriesFunction(t *testing.T) {
+	start := time.Unix(60, 0).UTC()
+	eval := Evaluation{
+		BucketID: "b",
+		Start:    start,
+		End:      start.Add(time.Minute),
+		Step:     time.Minute,
+	}
+	q, err := eval.Query(`rate(http_requests_total[1m])`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cols := []flux.ColMeta{
+		{Label: "_start", Type: flux.TTime},
+		{Label: "_stop", Type: flux.TTime},
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "_field", Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "job", Type: flux.TString},
+	}
+	sec := func(s int64) execute.Time {
+		return execute.Time(s * int64(time.Second))
+	}
+	tables := []*executetest.Table{
+		{
+			// The counter is reset between 90s and 105s.
+			KeyCols: []string{"_start", "_stop", "_field", "_measurement", "job"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{sec(0), sec(120), sec(15), 15.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(30), 30.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(45), 45.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(60), 60.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(75), 75.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(90), 90.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(105), 15.0, "counter", "http_requests_total", "api"},
+				{sec(0), sec(120), sec(120), 30.0, "counter", "http_requests_total", "api"},
+			},
+		},
+	}
+	result := executetest.NewResult(tables)
+	got, err := q.Series(flux.NewSliceResultIterator([]flux.Result{result}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The samples of each range are 15s apart, so the increase of each is
+	// extrapolated by 15s back to the start of its range.
+	want := []*Series{
+		{
+			Labels: map[string]string{"job": "api"},
+			Samples: []Sample{
+				{Time: start, Value: 1},
+				{Time: start.Add(time.Minute), Value: 1},
+			},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected series -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,90 @@
+package promql
+
+import "time"
+
+// rangeFunction evaluates the samples of the range (start, end] of a range
+// vector selector. It returns false if there is no value for the range.
+type rangeFunction func(samples []Sample, start, end time.Time) (float64, bool)
+
+// rangeFunctions are the supported functions of range vectors.
+var rangeFunctions = map[string]rangeFunction{
+	"rate": func(samples []Sample, start, end time.Time) (float64, bool) {
+		return extrapolatedDelta(samples, start, end, true)
+	},
+	"increase": func(samples []Sample, start, end time.Time) (float64, bool) {
+		return extrapolatedDelta(samples, start, end, false)
+	},
+	"irate": instantRate,
+}
+
+// extrapolatedDelta returns the increase of the counter of the samples over
+// the range, or its per-second rate if isRate is set. As in Prometheus, the
+// counter is reset when it decreases, and the increase between the first and
+// the last sample is extrapolated to the bounds of the range, unless they
+// are further than about the average interval between samples.
+func extrapolatedDelta(samples []Sample, start, end time.Time, isRate bool) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+
+	delta := last.Value - first.Value
+	prev := first.Value
+	for _, s := range samples[1:] {
+		if s.Value < prev {
+			delta += prev
+		}
+		prev = s.Value
+	}
+
+	durationToStart := first.Time.Sub(start).Seconds()
+	durationToEnd := end.Sub(last.Time).Seconds()
+	sampledInterval := last.Time.Sub(first.Time).Seconds()
+	averageInterval := sampledInterval / float64(len(samples)-1)
+
+	// A counter is not extrapolated below zero.
+	if delta > 0 && first.Value >= 0 {
+		if durationToZero := sampledInterval * (first.Value / delta); durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	threshold := averageInterval * 1.1
+	interval := sampledInterval
+	if durationToStart < threshold {
+		interval += durationToStart
+	} else {
+		interval += averageInterval / 2
+	}
+	if durationToEnd < threshold {
+		interval += durationToEnd
+	} else {
+		interval += averageInterval / 2
+	}
+
+	delta *= interval / sampledInterval
+	if isRate {
+		delta /= end.Sub(start).Seconds()
+	}
+	return delta, true
+}
+
+// instantRate returns the per-second rate of the counter between the last
+// two samples of the range.
+func instantRate(samples []Sample, start, end time.Time) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	last, prev := samples[len(samples)-1], samples[len(samples)-2]
+
+	interval := last.Time.Sub(prev.Time).Seconds()
+	if interval == 0 {
+		return 0, false
+	}
+	delta := last.Value - prev.Value
+	if last.Value < prev.Value {
+		// The counter was reset.
+		delta = last.Value
+	}
+	return delta / interval, true
+}
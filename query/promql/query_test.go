@@ -387,7 +387,7 @@ func TestBuild(t *testing.T) {
 														Object: &semantic.IdentifierExpression{
 															Name: "r",
 														},
-														Property: "_metric",
+														Property: MetricNameColumn,
 													},
 													Right: &semantic.StringLiteral{
 														Value: "node_cpu",
@@ -473,7 +473,7 @@ func TestBuild(t *testing.T) {
 													Object: &semantic.IdentifierExpression{
 														Name: "r",
 													},
-													Property: "_metric",
+													Property: MetricNameColumn,
 												},
 												Right: &semantic.StringLiteral{
 													Value: "node_cpu",
@@ -544,7 +544,7 @@ func TestBuild(t *testing.T) {
 													Object: &semantic.IdentifierExpression{
 														Name: "r",
 													},
-													Property: "_metric",
+													Property: MetricNameColumn,
 												},
 												Right: &semantic.StringLiteral{
 													Value: "node_cpu",
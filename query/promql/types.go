@@ -2,6 +2,7 @@ package promql
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -205,22 +206,31 @@ func NewRangeOp(rng, offset time.Duration) (*flux.Operation, error) {
 var operatorLookup = map[MatchKind]ast.OperatorKind{
 	Equal:        ast.EqualOperator,
 	NotEqual:     ast.NotEqualOperator,
-	RegexMatch:   ast.EqualOperator,
-	RegexNoMatch: ast.NotEqualOperator,
+	RegexMatch:   ast.RegexpMatchOperator,
+	RegexNoMatch: ast.NotRegexpMatchOperator,
 }
 
+// MetricNameColumn is the column of the metric name. The Prometheus
+// scrapers and remote write store the metric name as the measurement.
+const MetricNameColumn = "_measurement"
+
+// NewWhereOperation returns the filter of the metric name and the label
+// matchers. The metric name is not filtered if it is empty.
 func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operation, error) {
-	var node semantic.Expression = &semantic.BinaryExpression{
-		Operator: ast.EqualOperator,
-		Left: &semantic.MemberExpression{
-			Object: &semantic.IdentifierExpression{
-				Name: "r",
+	var node semantic.Expression
+	if metricName != "" {
+		node = &semantic.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left: &semantic.MemberExpression{
+				Object: &semantic.IdentifierExpression{
+					Name: "r",
+				},
+				Property: MetricNameColumn,
 			},
-			Property: "_metric",
-		},
-		Right: &semantic.StringLiteral{
-			Value: metricName,
-		},
+			Right: &semantic.StringLiteral{
+				Value: metricName,
+			},
+		}
 	}
 	for _, label := range labels {
 		op, ok := operatorLookup[label.Kind]
@@ -234,7 +244,15 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 			Property: label.Name,
 		}
 		var value semantic.Expression
-		if label.Value.Type() == StringKind {
+		if label.Kind == RegexMatch || label.Kind == RegexNoMatch {
+			re, err := labelRegexp(label.Value)
+			if err != nil {
+				return nil, err
+			}
+			value = &semantic.RegexpLiteral{
+				Value: re,
+			}
+		} else if label.Value.Type() == StringKind {
 			value = &semantic.StringLiteral{
 				Value: label.Value.Value().(string),
 			}
@@ -243,15 +261,22 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 				Value: label.Value.Value().(float64),
 			}
 		}
-		node = &semantic.LogicalExpression{
-			Operator: ast.AndOperator,
-			Left:     node,
-			Right: &semantic.BinaryExpression{
-				Operator: op,
-				Left:     ref,
-				Right:    value,
-			},
+		var match semantic.Expression = &semantic.BinaryExpression{
+			Operator: op,
+			Left:     ref,
+			Right:    value,
+		}
+		if node != nil {
+			match = &semantic.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     node,
+				Right:    match,
+			}
 		}
+		node = match
+	}
+	if node == nil {
+		node = &semantic.BooleanLiteral{Value: true}
 	}
 
 	return &flux.Operation{
@@ -272,6 +297,19 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 	}, nil
 }
 
+// labelRegexp compiles the value of a regex label matcher. As in Prometheus,
+// the regular expression is anchored to match the whole label value.
+func labelRegexp(v Arg) (*regexp.Regexp, error) {
+	if v.Type() != StringKind {
+		return nil, fmt.Errorf("regex label matcher value must be a string")
+	}
+	re, err := regexp.Compile("^(?:" + v.Value().(string) + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex label matcher %q: %v", v.Value(), err)
+	}
+	return re, nil
+}
+
 func (s *Selector) Type() ArgKind {
 	return SelectorKind
 }
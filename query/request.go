@@ -33,6 +33,16 @@ type Request struct {
 	// Source represents the ultimate source of the request.
 	Source string `json:"source"`
 
+	// RemoteAddr is the network address of the client that sent the request.
+	RemoteAddr string `json:"remote_addr,omitempty"`
+
+	// Profile requests the profile of the query, which is returned
+	// as a result of its own after the results of the query.
+	Profile bool `json:"profile,omitempty"`
+
+	// Params are bound to the params record of the query when it is compiled.
+	Params Params `json:"params,omitempty"`
+
 	// compilerMappings maps compiler types to creation methods
 	compilerMappings flux.CompilerMappings
 
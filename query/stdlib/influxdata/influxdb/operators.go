@@ -13,10 +13,12 @@ import (
 )
 
 const (
-	ReadRangePhysKind     = "ReadRangePhysKind"
-	ReadGroupPhysKind     = "ReadGroupPhysKind"
-	ReadTagKeysPhysKind   = "ReadTagKeysPhysKind"
-	ReadTagValuesPhysKind = "ReadTagValuesPhysKind"
+	ReadRangePhysKind           = "ReadRangePhysKind"
+	ReadGroupPhysKind           = "ReadGroupPhysKind"
+	ReadAggregatePhysKind       = "ReadAggregatePhysKind"
+	ReadWindowAggregatePhysKind = "ReadWindowAggregatePhysKind"
+	ReadTagKeysPhysKind         = "ReadTagKeysPhysKind"
+	ReadTagValuesPhysKind       = "ReadTagValuesPhysKind"
 )
 
 type ReadGroupPhysSpec struct {
@@ -44,6 +46,56 @@ func (s *ReadGroupPhysSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// ReadAggregatePhysSpec reads the aggregate of each series in the range,
+// which is computed by the storage engine.
+type ReadAggregatePhysSpec struct {
+	plan.DefaultCost
+	ReadRangePhysSpec
+
+	AggregateMethod string
+}
+
+func (s *ReadAggregatePhysSpec) Kind() plan.ProcedureKind {
+	return ReadAggregatePhysKind
+}
+
+func (s *ReadAggregatePhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadAggregatePhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+
+	ns.AggregateMethod = s.AggregateMethod
+	return ns
+}
+
+// ReadWindowAggregatePhysSpec reads the aggregate of each window of each
+// series in the range, which is computed by the storage engine.
+type ReadWindowAggregatePhysSpec struct {
+	plan.DefaultCost
+	ReadRangePhysSpec
+
+	WindowEvery flux.Duration
+	Offset      flux.Duration
+	CreateEmpty bool
+
+	AggregateMethod string
+}
+
+func (s *ReadWindowAggregatePhysSpec) Kind() plan.ProcedureKind {
+	return ReadWindowAggregatePhysKind
+}
+
+func (s *ReadWindowAggregatePhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadWindowAggregatePhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+
+	ns.WindowEvery = s.WindowEvery
+	ns.Offset = s.Offset
+	ns.CreateEmpty = s.CreateEmpty
+
+	ns.AggregateMethod = s.AggregateMethod
+	return ns
+}
+
 type ReadRangePhysSpec struct {
 	plan.DefaultCost
 
@@ -58,6 +110,10 @@ type ReadRangePhysSpec struct {
 	Filter *semantic.FunctionExpression
 
 	Bounds flux.Bounds
+
+	// Rules are the names of the planner rules which pushed
+	// operations down into the read, in the order they fired.
+	Rules []string
 }
 
 func (s *ReadRangePhysSpec) Kind() plan.ProcedureKind {
@@ -76,9 +132,24 @@ func (s *ReadRangePhysSpec) Copy() plan.ProcedureSpec {
 
 	ns.Bounds = s.Bounds
 
+	if len(s.Rules) > 0 {
+		ns.Rules = make([]string, len(s.Rules))
+		copy(ns.Rules, s.Rules)
+	}
+
 	return ns
 }
 
+// PlanRules returns the names of the planner rules which pushed
+// operations down into the read.
+func (s *ReadRangePhysSpec) PlanRules() []string {
+	return s.Rules
+}
+
+func (s *ReadRangePhysSpec) recordRule(name string) {
+	s.Rules = append(s.Rules, name)
+}
+
 func (s *ReadRangePhysSpec) LookupBucketID(ctx context.Context, orgID influxdb.ID, buckets BucketLookup) (influxdb.ID, error) {
 	// Determine bucketID
 	switch {
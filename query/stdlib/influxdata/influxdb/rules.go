@@ -1,6 +1,8 @@
 package influxdb
 
 import (
+	"math"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -10,14 +12,60 @@ import (
 )
 
 func init() {
-	plan.RegisterPhysicalRules(
+	plan.RegisterPhysicalRules(recordRules(
 		PushDownRangeRule{},
 		PushDownFilterRule{},
 		PushDownGroupRule{},
 		PushDownReadTagKeysRule{},
 		PushDownReadTagValuesRule{},
 		SortedPivotRule{},
-	)
+		PushDownAggregateRule{Kind: universe.MinKind},
+		PushDownAggregateRule{Kind: universe.MaxKind},
+		PushDownAggregateRule{Kind: universe.FirstKind},
+		PushDownAggregateRule{Kind: universe.LastKind},
+		PushDownAggregateRule{Kind: universe.MeanKind},
+		PushDownAggregateRule{Kind: universe.SumKind},
+		PushDownAggregateRule{Kind: universe.CountKind},
+		PushDownWindowAggregateRule{Kind: universe.MinKind},
+		PushDownWindowAggregateRule{Kind: universe.MaxKind},
+		PushDownWindowAggregateRule{Kind: universe.FirstKind},
+		PushDownWindowAggregateRule{Kind: universe.LastKind},
+		PushDownWindowAggregateRule{Kind: universe.MeanKind},
+		PushDownWindowAggregateRule{Kind: universe.SumKind},
+		PushDownWindowAggregateRule{Kind: universe.CountKind},
+	)...)
+}
+
+// ruleRecorder is implemented by the procedure specs of storage reads,
+// which record the names of the rules that pushed operations down into them.
+type ruleRecorder interface {
+	recordRule(name string)
+}
+
+// recordedRule is a rule which records its name into the spec of the node
+// it rewrites to, so that the pushed down rules of a read can be profiled.
+type recordedRule struct {
+	plan.Rule
+}
+
+// recordRules wraps the rules so that they are recorded when they fire.
+func recordRules(rules ...plan.Rule) []plan.Rule {
+	recorded := make([]plan.Rule, len(rules))
+	for i, rule := range rules {
+		recorded[i] = recordedRule{Rule: rule}
+	}
+	return recorded
+}
+
+func (rule recordedRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
+	n, changed, err := rule.Rule.Rewrite(node)
+	if err != nil || !changed {
+		return n, changed, err
+	}
+	if r, ok := n.ProcedureSpec().(ruleRecorder); ok {
+		r.recordRule(rule.Name())
+	}
+	return n, changed, nil
 }
 
 // PushDownGroupRule pushes down a group operation to storage
@@ -58,6 +106,121 @@ func (rule PushDownGroupRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
 	}), true, nil
 }
 
+// PushDownAggregateRule pushes down an aggregate or a selector of the _value
+// column to storage, which then computes it for each series.
+// The rule matches 'ReadRange |> <kind>()', where kind is one of
+// min, max, first, last, mean, sum or count, which are also the
+// names of the aggregate methods of storage.
+//
+// Storage fails the query when it reads a series it cannot aggregate,
+// such as the string series of a mean, as the aggregate of Flux does.
+type PushDownAggregateRule struct {
+	Kind plan.ProcedureKind
+}
+
+func (rule PushDownAggregateRule) Name() string {
+	return "PushDownAggregateRule(" + string(rule.Kind) + ")"
+}
+
+func (rule PushDownAggregateRule) Pattern() plan.Pattern {
+	return plan.Pat(rule.Kind, plan.Pat(ReadRangePhysKind))
+}
+
+func (rule PushDownAggregateRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	fromNode := pn.Predecessors()[0]
+	fromSpec := fromNode.ProcedureSpec().(*ReadRangePhysSpec)
+
+	if !isPushableAggregate(pn.ProcedureSpec()) {
+		return pn, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadAggregate", &ReadAggregatePhysSpec{
+		ReadRangePhysSpec: *fromSpec.Copy().(*ReadRangePhysSpec),
+		AggregateMethod:   string(pn.Kind()),
+	}), true, nil
+}
+
+// isPushableAggregate reports whether storage can compute the aggregate
+// or the selector, which it does only for the values of the series.
+func isPushableAggregate(spec plan.ProcedureSpec) bool {
+	var columns []string
+	switch spec := spec.(type) {
+	case *universe.MinProcedureSpec:
+		columns = []string{spec.Column}
+	case *universe.MaxProcedureSpec:
+		columns = []string{spec.Column}
+	case *universe.FirstProcedureSpec:
+		columns = []string{spec.Column}
+	case *universe.LastProcedureSpec:
+		columns = []string{spec.Column}
+	case *universe.MeanProcedureSpec:
+		columns = spec.Columns
+	case *universe.SumProcedureSpec:
+		columns = spec.Columns
+	case *universe.CountProcedureSpec:
+		columns = spec.Columns
+	default:
+		return false
+	}
+	return len(columns) == 1 && columns[0] == execute.DefaultValueColLabel
+}
+
+// PushDownWindowAggregateRule pushes down an aggregate or a selector of the
+// _value column of windows to storage, which then computes it for each window
+// of each series. The rule matches 'ReadRange |> window() |> <kind>()', which
+// is how aggregateWindow starts, where kind is one of min, max, first, last,
+// mean, sum or count.
+//
+// Storage only partitions the series into windows which follow each other,
+// so the windows must have a period equal to their duration, which cannot
+// be in months, and the default time columns.
+type PushDownWindowAggregateRule struct {
+	Kind plan.ProcedureKind
+}
+
+func (rule PushDownWindowAggregateRule) Name() string {
+	return "PushDownWindowAggregateRule(" + string(rule.Kind) + ")"
+}
+
+func (rule PushDownWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.Pat(rule.Kind, plan.Pat(universe.WindowKind, plan.Pat(ReadRangePhysKind)))
+}
+
+func (rule PushDownWindowAggregateRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	windowNode := pn.Predecessors()[0]
+	fromNode := windowNode.Predecessors()[0]
+	fromSpec := fromNode.ProcedureSpec().(*ReadRangePhysSpec)
+
+	// The spec is read from the node since Copy does
+	// not copy the columns of a window.
+	windowSpec := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	if !isPushableWindow(windowSpec) || !isPushableAggregate(pn.ProcedureSpec()) {
+		return pn, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadWindowAggregate", &ReadWindowAggregatePhysSpec{
+		ReadRangePhysSpec: *fromSpec.Copy().(*ReadRangePhysSpec),
+		WindowEvery:       windowSpec.Window.Every,
+		Offset:            windowSpec.Window.Offset,
+		CreateEmpty:       windowSpec.CreateEmpty,
+		AggregateMethod:   string(pn.Kind()),
+	}), true, nil
+}
+
+func isPushableWindow(spec *universe.WindowProcedureSpec) bool {
+	every, offset := spec.Window.Every, spec.Window.Offset
+	if !every.IsPositive() || every.Months() != 0 || every.Nanoseconds() == math.MaxInt64 {
+		// The window is in months or infinite.
+		return false
+	}
+	if !spec.Window.Period.Equal(every) || offset.Months() != 0 {
+		return false
+	}
+	return spec.TimeColumn == execute.DefaultTimeColLabel &&
+		spec.StartColumn == execute.DefaultStartColLabel &&
+		spec.StopColumn == execute.DefaultStopColLabel
+}
+
 // PushDownRangeRule pushes down a range filter to storage
 type PushDownRangeRule struct{}
 
@@ -1,9 +1,11 @@
 package influxdb_test
 
 import (
+	"math"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -12,6 +14,7 @@ import (
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 )
 
@@ -853,6 +856,373 @@ func TestPushDownGroupRule(t *testing.T) {
 	}
 }
 
+func TestPushDownAggregateRule(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		},
+	}
+
+	rules := []plan.Rule{
+		influxdb.PushDownAggregateRule{Kind: universe.MaxKind},
+		influxdb.PushDownAggregateRule{Kind: universe.MeanKind},
+		influxdb.PushDownAggregateRule{Kind: universe.SumKind},
+		influxdb.PushDownAggregateRule{Kind: universe.CountKind},
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name: "selector",
+			// ReadRange -> max => ReadAggregate
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("max", &universe.MaxProcedureSpec{
+						SelectorConfig: execute.DefaultSelectorConfig,
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadAggregate", &influxdb.ReadAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						AggregateMethod:   "max",
+					}),
+				},
+			},
+		},
+		{
+			Name: "aggregate with successor",
+			// ReadRange -> mean -> count => ReadAggregate -> count
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadAggregate", &influxdb.ReadAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						AggregateMethod:   "mean",
+					}),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+		},
+		{
+			Name: "sum",
+			// ReadRange -> sum => ReadAggregate
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("sum", &universe.SumProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadAggregate", &influxdb.ReadAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						AggregateMethod:   "sum",
+					}),
+				},
+			},
+		},
+		{
+			Name: "count",
+			// ReadRange -> count => ReadAggregate
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadAggregate", &influxdb.ReadAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						AggregateMethod:   "count",
+					}),
+				},
+			},
+		},
+		{
+			Name: "count of several columns",
+			// ReadRange -> count(columns: ["_value", "host"]) => no change
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{
+						AggregateConfig: execute.AggregateConfig{Columns: []string{"_value", "host"}},
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name: "other column",
+			// ReadRange -> max(column: "host") => ReadRange -> max(column: "host")
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("max", &universe.MaxProcedureSpec{
+						SelectorConfig: execute.SelectorConfig{Column: "host"},
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name: "multiple successors",
+			// ReadRange -> max, ReadRange -> mean => no change
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("max", &universe.MaxProcedureSpec{
+						SelectorConfig: execute.DefaultSelectorConfig,
+					}),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{0, 2},
+				},
+			},
+			NoChange: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestPushDownWindowAggregateRule(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		},
+	}
+
+	window := func(every, period, offset values.Duration) *universe.WindowProcedureSpec {
+		return &universe.WindowProcedureSpec{
+			Window: plan.WindowSpec{
+				Every:  every,
+				Period: period,
+				Offset: offset,
+			},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+		}
+	}
+	minute := values.ConvertDuration(time.Minute)
+	second := values.ConvertDuration(time.Second)
+	month, err := values.ParseDuration("1mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []plan.Rule{
+		influxdb.PushDownWindowAggregateRule{Kind: universe.MinKind},
+		influxdb.PushDownWindowAggregateRule{Kind: universe.SumKind},
+		influxdb.PushDownWindowAggregateRule{Kind: universe.CountKind},
+	}
+
+	// windowPlan returns the plan ReadRange -> window -> aggregate. The cases
+	// which do not change the plan build it twice rather than setting NoChange,
+	// since copying a window spec drops its columns.
+	windowPlan := func(window *universe.WindowProcedureSpec, kind plan.NodeID, aggregate plan.PhysicalProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("ReadRange", &readRange),
+				plan.CreatePhysicalNode("window", window),
+				plan.CreatePhysicalNode(kind, aggregate),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name: "selector",
+			// ReadRange -> window -> min => ReadWindowAggregate
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(minute, minute, second)),
+					plan.CreatePhysicalNode("min", &universe.MinProcedureSpec{
+						SelectorConfig: execute.DefaultSelectorConfig,
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						WindowEvery:       minute,
+						Offset:            second,
+						AggregateMethod:   "min",
+					}),
+				},
+			},
+		},
+		{
+			Name: "sum",
+			// ReadRange -> window -> sum => ReadWindowAggregate
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(minute, minute, values.Duration{})),
+					plan.CreatePhysicalNode("sum", &universe.SumProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						WindowEvery:       minute,
+						AggregateMethod:   "sum",
+					}),
+				},
+			},
+		},
+		{
+			Name: "create empty",
+			// ReadRange -> window(createEmpty: true) -> count => ReadWindowAggregate
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", func() *universe.WindowProcedureSpec {
+						spec := window(minute, minute, values.Duration{})
+						spec.CreateEmpty = true
+						return spec
+					}()),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						WindowEvery:       minute,
+						CreateEmpty:       true,
+						AggregateMethod:   "count",
+					}),
+				},
+			},
+		},
+		{
+			Name: "period not equal to every",
+			// ReadRange -> window(every: 1m, period: 2m) -> min => no change
+			Rules: rules,
+			Before: windowPlan(window(minute, values.ConvertDuration(2*time.Minute), values.Duration{}), "min", &universe.MinProcedureSpec{
+				SelectorConfig: execute.DefaultSelectorConfig,
+			}),
+			After: windowPlan(window(minute, values.ConvertDuration(2*time.Minute), values.Duration{}), "min", &universe.MinProcedureSpec{
+				SelectorConfig: execute.DefaultSelectorConfig,
+			}),
+		},
+		{
+			Name: "months",
+			// ReadRange -> window(every: 1mo) -> min => no change
+			Rules: rules,
+			Before: windowPlan(window(month, month, values.Duration{}), "min", &universe.MinProcedureSpec{
+				SelectorConfig: execute.DefaultSelectorConfig,
+			}),
+			After: windowPlan(window(month, month, values.Duration{}), "min", &universe.MinProcedureSpec{
+				SelectorConfig: execute.DefaultSelectorConfig,
+			}),
+		},
+		{
+			Name: "infinite window",
+			// ReadRange -> window(every: inf) -> count => no change
+			Rules: rules,
+			Before: windowPlan(window(values.ConvertDuration(math.MaxInt64), values.ConvertDuration(math.MaxInt64), values.Duration{}), "count", &universe.CountProcedureSpec{
+				AggregateConfig: execute.DefaultAggregateConfig,
+			}),
+			After: windowPlan(window(values.ConvertDuration(math.MaxInt64), values.ConvertDuration(math.MaxInt64), values.Duration{}), "count", &universe.CountProcedureSpec{
+				AggregateConfig: execute.DefaultAggregateConfig,
+			}),
+		},
+		{
+			Name: "other column",
+			// ReadRange -> window -> min(column: "host") => no change
+			Rules: rules,
+			Before: windowPlan(window(minute, minute, values.Duration{}), "min", &universe.MinProcedureSpec{
+				SelectorConfig: execute.SelectorConfig{Column: "host"},
+			}),
+			After: windowPlan(window(minute, minute, values.Duration{}), "min", &universe.MinProcedureSpec{
+				SelectorConfig: execute.SelectorConfig{Column: "host"},
+			}),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
 func TestReadTagKeysRule(t *testing.T) {
 	fromSpec := influxdb.FromProcedureSpec{
 		Bucket: "my-bucket",
@@ -1284,3 +1654,48 @@ func TestReadTagValuesRule(t *testing.T) {
 		})
 	}
 }
+
+func TestPushDownRules_Recorded(t *testing.T) {
+	// from -> range -> group  =>  ReadGroup
+	spec := plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreateLogicalNode("from", &influxdb.FromProcedureSpec{
+				Bucket: "my-bucket",
+			}),
+			plan.CreateLogicalNode("range", &universe.RangeProcedureSpec{
+				Bounds: flux.Bounds{
+					Start: fluxTime(5),
+					Stop:  fluxTime(10),
+				},
+			}),
+			plan.CreateLogicalNode("group", &universe.GroupProcedureSpec{
+				GroupMode: flux.GroupModeBy,
+				GroupKeys: []string{"_measurement", "tag0"},
+			}),
+		},
+		Edges: [][2]int{
+			{0, 1},
+			{1, 2},
+		},
+	}
+
+	// The registered rules record that they fired into the spec of the read.
+	pp := plan.NewPhysicalPlanner()
+	ps, err := pp.Plan(plantest.CreatePlanSpec(&spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps.Roots) != 1 {
+		t.Fatalf("unexpected number of roots: %d", len(ps.Roots))
+	}
+	for root := range ps.Roots {
+		read, ok := root.ProcedureSpec().(*influxdb.ReadGroupPhysSpec)
+		if !ok {
+			t.Fatalf("unexpected spec %T", root.ProcedureSpec())
+		}
+		want := []string{"PushDownRangeRule", "PushDownGroupRule"}
+		if got := read.PlanRules(); !cmp.Equal(want, got) {
+			t.Errorf("unexpected rules -want/+got:\n%s", cmp.Diff(want, got))
+		}
+	}
+}
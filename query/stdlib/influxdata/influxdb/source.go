@@ -20,6 +20,8 @@ import (
 func init() {
 	execute.RegisterSource(ReadRangePhysKind, createReadFilterSource)
 	execute.RegisterSource(ReadGroupPhysKind, createReadGroupSource)
+	execute.RegisterSource(ReadAggregatePhysKind, createReadAggregateSource)
+	execute.RegisterSource(ReadWindowAggregatePhysKind, createReadWindowAggregateSource)
 	execute.RegisterSource(ReadTagKeysPhysKind, createReadTagKeysSource)
 	execute.RegisterSource(ReadTagValuesPhysKind, createReadTagValuesSource)
 }
@@ -34,6 +36,7 @@ type Source struct {
 
 	alloc *memory.Allocator
 	stats cursors.CursorStats
+	dur   time.Duration
 
 	runner runner
 
@@ -53,6 +56,7 @@ func (s *Source) Run(ctx context.Context) {
 	} else {
 		err = s.runner.run(ctx)
 	}
+	s.dur = time.Since(start)
 	s.m.recordMetrics(labelValues, start)
 	for _, t := range s.ts {
 		t.Finish(s.id, err)
@@ -63,10 +67,18 @@ func (s *Source) AddTransformation(t execute.Transformation) {
 	s.ts = append(s.ts, t)
 }
 
+// Metadata returns the stats of the read of the source. The source key
+// identifies the dataset of the source, so that the stats of each source
+// can be told apart in the metadata of a query.
 func (s *Source) Metadata() flux.Metadata {
 	return flux.Metadata{
+		"influxdb/source":         []interface{}{s.id.String()},
+		"influxdb/read-duration":  []interface{}{s.dur},
 		"influxdb/scanned-bytes":  []interface{}{s.stats.ScannedBytes},
 		"influxdb/scanned-values": []interface{}{s.stats.ScannedValues},
+		"influxdb/scanned-series": []interface{}{s.stats.ScannedSeries},
+		"influxdb/cache-values":   []interface{}{s.stats.CacheValues},
+		"influxdb/decoded-blocks": []interface{}{s.stats.DecodedBlocks},
 	}
 }
 
@@ -78,10 +90,8 @@ func (s *Source) processTables(ctx context.Context, tables TableIterator, waterm
 		return err
 	}
 
-	// Track the number of bytes and values scanned.
-	stats := tables.Statistics()
-	s.stats.ScannedValues += stats.ScannedValues
-	s.stats.ScannedBytes += stats.ScannedBytes
+	// Track the number of series, bytes and values scanned.
+	s.stats.Add(tables.Statistics())
 
 	for _, t := range s.ts {
 		if err := t.UpdateWatermark(s.id, watermark); err != nil {
@@ -280,6 +290,181 @@ func createReadGroupSource(s plan.ProcedureSpec, id execute.DatasetID, a execute
 	), nil
 }
 
+type readAggregateSource struct {
+	Source
+	reader   Reader
+	readSpec ReadAggregateSpec
+}
+
+func ReadAggregateSource(id execute.DatasetID, r Reader, readSpec ReadAggregateSpec, a execute.Administration) execute.Source {
+	src := new(readAggregateSource)
+
+	src.id = id
+	src.alloc = a.Allocator()
+
+	src.reader = r
+	src.readSpec = readSpec
+
+	src.m = GetStorageDependencies(a.Context()).FromDeps.Metrics
+	src.orgID = readSpec.OrganizationID
+	src.op = "readAggregate"
+
+	src.runner = src
+	return src
+}
+
+func (s *readAggregateSource) run(ctx context.Context) error {
+	stop := s.readSpec.Bounds.Stop
+	tables, err := s.reader.ReadAggregate(
+		ctx,
+		s.readSpec,
+		s.alloc,
+	)
+	if err != nil {
+		return err
+	}
+	return s.processTables(ctx, tables, stop)
+}
+
+func createReadAggregateSource(s plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	span, ctx := tracing.StartSpanFromContext(a.Context())
+	defer span.Finish()
+
+	spec := s.(*ReadAggregatePhysSpec)
+
+	bounds := a.StreamContext().Bounds()
+	if bounds == nil {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "nil bounds passed to from",
+		}
+	}
+
+	deps := GetStorageDependencies(a.Context()).FromDeps
+
+	req := query.RequestFromContext(a.Context())
+	if req == nil {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "missing request on context",
+		}
+	}
+
+	orgID := req.OrganizationID
+	bucketID, err := spec.LookupBucketID(ctx, orgID, deps.BucketLookup)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter *semantic.FunctionExpression
+	if spec.FilterSet {
+		filter = spec.Filter
+	}
+	return ReadAggregateSource(
+		id,
+		deps.Reader,
+		ReadAggregateSpec{
+			ReadFilterSpec: ReadFilterSpec{
+				OrganizationID: orgID,
+				BucketID:       bucketID,
+				Bounds:         *bounds,
+				Predicate:      filter,
+			},
+			AggregateMethod: spec.AggregateMethod,
+		},
+		a,
+	), nil
+}
+
+type readWindowAggregateSource struct {
+	Source
+	reader   Reader
+	readSpec ReadWindowAggregateSpec
+}
+
+func ReadWindowAggregateSource(id execute.DatasetID, r Reader, readSpec ReadWindowAggregateSpec, a execute.Administration) execute.Source {
+	src := new(readWindowAggregateSource)
+
+	src.id = id
+	src.alloc = a.Allocator()
+
+	src.reader = r
+	src.readSpec = readSpec
+
+	src.m = GetStorageDependencies(a.Context()).FromDeps.Metrics
+	src.orgID = readSpec.OrganizationID
+	src.op = "readWindowAggregate"
+
+	src.runner = src
+	return src
+}
+
+func (s *readWindowAggregateSource) run(ctx context.Context) error {
+	stop := s.readSpec.Bounds.Stop
+	tables, err := s.reader.ReadWindowAggregate(
+		ctx,
+		s.readSpec,
+		s.alloc,
+	)
+	if err != nil {
+		return err
+	}
+	return s.processTables(ctx, tables, stop)
+}
+
+func createReadWindowAggregateSource(s plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	span, ctx := tracing.StartSpanFromContext(a.Context())
+	defer span.Finish()
+
+	spec := s.(*ReadWindowAggregatePhysSpec)
+
+	bounds := a.StreamContext().Bounds()
+	if bounds == nil {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "nil bounds passed to from",
+		}
+	}
+
+	deps := GetStorageDependencies(a.Context()).FromDeps
+
+	req := query.RequestFromContext(a.Context())
+	if req == nil {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "missing request on context",
+		}
+	}
+
+	orgID := req.OrganizationID
+	bucketID, err := spec.LookupBucketID(ctx, orgID, deps.BucketLookup)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter *semantic.FunctionExpression
+	if spec.FilterSet {
+		filter = spec.Filter
+	}
+	return ReadWindowAggregateSource(
+		id,
+		deps.Reader,
+		ReadWindowAggregateSpec{
+			ReadFilterSpec: ReadFilterSpec{
+				OrganizationID: orgID,
+				BucketID:       bucketID,
+				Bounds:         *bounds,
+				Predicate:      filter,
+			},
+			WindowEvery:     spec.WindowEvery,
+			Offset:          spec.Offset,
+			CreateEmpty:     spec.CreateEmpty,
+			AggregateMethod: spec.AggregateMethod,
+		},
+		a,
+	), nil
+}
+
 func createReadTagKeysSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	span, ctx := tracing.StartSpanFromContext(a.Context())
 	defer span.Finish()
@@ -40,6 +40,14 @@ func (mockReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec, al
 	return &mockTableIterator{}, nil
 }
 
+func (mockReader) ReadAggregate(ctx context.Context, spec influxdb.ReadAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &mockTableIterator{}, nil
+}
+
+func (mockReader) ReadWindowAggregate(ctx context.Context, spec influxdb.ReadWindowAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &mockTableIterator{}, nil
+}
+
 func (mockReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	return &mockTableIterator{}, nil
 }
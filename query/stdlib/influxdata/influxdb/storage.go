@@ -119,6 +119,29 @@ type ReadGroupSpec struct {
 	AggregateMethod string
 }
 
+// ReadAggregateSpec reads the aggregate of each series,
+// with the method of the Aggregate message of the storage protocol.
+type ReadAggregateSpec struct {
+	ReadFilterSpec
+
+	AggregateMethod string
+}
+
+// ReadWindowAggregateSpec reads the aggregate of each window of each series,
+// with the method of the Aggregate message of the storage protocol. The windows
+// are those of the window function of Flux with a period of WindowEvery, and
+// each is read as a table. When CreateEmpty is set, the windows of a series
+// without points are read as the aggregate of an empty table.
+type ReadWindowAggregateSpec struct {
+	ReadFilterSpec
+
+	WindowEvery flux.Duration
+	Offset      flux.Duration
+	CreateEmpty bool
+
+	AggregateMethod string
+}
+
 type ReadTagKeysSpec struct {
 	ReadFilterSpec
 }
@@ -131,6 +154,8 @@ type ReadTagValuesSpec struct {
 type Reader interface {
 	ReadFilter(ctx context.Context, spec ReadFilterSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadGroup(ctx context.Context, spec ReadGroupSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadAggregate(ctx context.Context, spec ReadAggregateSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadWindowAggregate(ctx context.Context, spec ReadWindowAggregateSpec, alloc *memory.Allocator) (TableIterator, error)
 
 	ReadTagKeys(ctx context.Context, spec ReadTagKeysSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadTagValues(ctx context.Context, spec ReadTagValuesSpec, alloc *memory.Allocator) (TableIterator, error)
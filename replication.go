@@ -0,0 +1,157 @@
+package influxdb
+
+import (
+	"context"
+	"net/url"
+)
+
+// DefaultReplicationMaxQueueSizeBytes is the default size of the on-disk queue of a replication.
+const DefaultReplicationMaxQueueSizeBytes = 64 * 1024 * 1024
+
+// MinReplicationMaxQueueSizeBytes is the smallest queue size a replication may be configured with.
+const MinReplicationMaxQueueSizeBytes = 32 * 1024
+
+// Replication forwards the points written to a local bucket to a bucket of a
+// remote InfluxDB instance. Points are appended to a durable queue when they
+// are written, and sent to the remote write API in the background.
+type Replication struct {
+	ID                ID     `json:"id,omitempty"`
+	OrgID             ID     `json:"orgID"`
+	Name              string `json:"name"`
+	Description       string `json:"description,omitempty"`
+	LocalBucketID     ID     `json:"localBucketID"`
+	RemoteURL         string `json:"remoteURL"`
+	RemoteToken       string `json:"remoteToken,omitempty"`
+	RemoteOrgID       ID     `json:"remoteOrgID"`
+	RemoteBucketID    ID     `json:"remoteBucketID"`
+	MaxQueueSizeBytes int64  `json:"maxQueueSizeBytes"`
+	CRUDLog
+
+	// The status of the replication is reported by the service running its
+	// queue, and is not stored with the replication.
+	CurrentQueueSizeBytes int64  `json:"currentQueueSizeBytes"`
+	LatestResponseCode    int    `json:"latestResponseCode,omitempty"`
+	LatestErrorMessage    string `json:"latestErrorMessage,omitempty"`
+}
+
+// Validate reports any validation errors for the replication.
+func (r *Replication) Validate() error {
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication name is required",
+		}
+	}
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication orgID is required",
+		}
+	}
+	if !r.LocalBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication localBucketID is required",
+		}
+	}
+	if !r.RemoteOrgID.Valid() || !r.RemoteBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication remoteOrgID and remoteBucketID are required",
+		}
+	}
+	if err := validateReplicationURL(r.RemoteURL); err != nil {
+		return err
+	}
+	if r.MaxQueueSizeBytes < MinReplicationMaxQueueSizeBytes {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication maxQueueSizeBytes must be at least 32768",
+		}
+	}
+	return nil
+}
+
+func validateReplicationURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication remoteURL must be an absolute http or https URL",
+		}
+	}
+	return nil
+}
+
+// ReplicationUpdate represents updates to a replication.
+// Only fields which are set are updated.
+type ReplicationUpdate struct {
+	Name              *string `json:"name,omitempty"`
+	Description       *string `json:"description,omitempty"`
+	RemoteURL         *string `json:"remoteURL,omitempty"`
+	RemoteToken       *string `json:"remoteToken,omitempty"`
+	RemoteOrgID       *ID     `json:"remoteOrgID,omitempty"`
+	RemoteBucketID    *ID     `json:"remoteBucketID,omitempty"`
+	MaxQueueSizeBytes *int64  `json:"maxQueueSizeBytes,omitempty"`
+}
+
+// Apply applies an update to a replication.
+func (u ReplicationUpdate) Apply(r *Replication) error {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.RemoteURL != nil {
+		r.RemoteURL = *u.RemoteURL
+	}
+	if u.RemoteToken != nil {
+		r.RemoteToken = *u.RemoteToken
+	}
+	if u.RemoteOrgID != nil {
+		r.RemoteOrgID = *u.RemoteOrgID
+	}
+	if u.RemoteBucketID != nil {
+		r.RemoteBucketID = *u.RemoteBucketID
+	}
+	if u.MaxQueueSizeBytes != nil {
+		r.MaxQueueSizeBytes = *u.MaxQueueSizeBytes
+	}
+	return r.Validate()
+}
+
+// ReplicationFilter represents a set of filters that restrict the returned replications.
+type ReplicationFilter struct {
+	OrgID         *ID
+	Name          *string
+	LocalBucketID *ID
+}
+
+// ops for replication errors.
+var (
+	OpFindReplicationByID = "FindReplicationByID"
+	OpFindReplications    = "FindReplications"
+	OpCreateReplication   = "CreateReplication"
+	OpUpdateReplication   = "UpdateReplication"
+	OpDeleteReplication   = "DeleteReplication"
+)
+
+// ReplicationService manages the replications of local buckets to remote instances.
+type ReplicationService interface {
+	// FindReplicationByID returns a single replication by ID.
+	FindReplicationByID(ctx context.Context, id ID) (*Replication, error)
+
+	// FindReplications returns the replications that match filter.
+	FindReplications(ctx context.Context, filter ReplicationFilter, opt ...FindOptions) ([]*Replication, int, error)
+
+	// CreateReplication creates a new replication and sets r.ID with the new identifier.
+	CreateReplication(ctx context.Context, r *Replication) error
+
+	// UpdateReplication updates a single replication with changeset.
+	// Returns the new replication after update.
+	UpdateReplication(ctx context.Context, id ID, upd ReplicationUpdate) (*Replication, error)
+
+	// DeleteReplication removes a replication by ID, and discards its queue.
+	DeleteReplication(ctx context.Context, id ID) error
+}
@@ -0,0 +1,81 @@
+package replications
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// namespace is the leading part of all published metrics for replications.
+const namespace = "replications"
+
+// metrics is a set of metrics concerned with the queues of replications and
+// the writes sent to remote instances.
+type metrics struct {
+	QueueSizeBytes *prometheus.GaugeVec
+	QueueLag       *prometheus.GaugeVec
+	PointsQueued   *prometheus.CounterVec
+	PointsDropped  *prometheus.CounterVec
+	PointsSent     *prometheus.CounterVec
+	BytesSent      *prometheus.CounterVec
+	RemoteErrors   *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	labels := []string{"replication_id"}
+	return &metrics{
+		QueueSizeBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_size_bytes",
+			Help:      "Size of the queue of points waiting to be sent.",
+		}, labels),
+		QueueLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_lag_seconds",
+			Help:      "Time since the oldest point in the queue was written.",
+		}, labels),
+		PointsQueued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "points_queued_total",
+			Help:      "Number of points appended to the queue.",
+		}, labels),
+		PointsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "points_dropped_total",
+			Help:      "Number of points dropped because the queue was full or the remote rejected them.",
+		}, append(labels, "reason")),
+		PointsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "points_sent_total",
+			Help:      "Number of points written to the remote instance.",
+		}, labels),
+		BytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bytes_sent_total",
+			Help:      "Number of bytes of line protocol written to the remote instance.",
+		}, labels),
+		RemoteErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "remote_write_errors_total",
+			Help:      "Number of failed writes to the remote instance.",
+		}, append(labels, "code")),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *metrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.QueueSizeBytes,
+		m.QueueLag,
+		m.PointsQueued,
+		m.PointsDropped,
+		m.PointsSent,
+		m.BytesSent,
+		m.RemoteErrors,
+	}
+}
+
+// remove deletes the metrics of a replication.
+func (m *metrics) remove(id string) {
+	m.QueueSizeBytes.DeleteLabelValues(id)
+	m.QueueLag.DeleteLabelValues(id)
+	m.PointsQueued.DeleteLabelValues(id)
+	m.PointsSent.DeleteLabelValues(id)
+	m.BytesSent.DeleteLabelValues(id)
+}
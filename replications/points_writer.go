@@ -0,0 +1,82 @@
+package replications
+
+import (
+	"context"
+	"errors"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// PointsWriter is a storage.PointsWriter which appends the points written
+// to storage to the queues of the replications of their buckets.
+type PointsWriter struct {
+	PointsWriter storage.PointsWriter
+	Service      *Service
+}
+
+// NewPointsWriter returns a PointsWriter which writes points to pw and
+// queues them for the replications managed by s.
+func NewPointsWriter(pw storage.PointsWriter, s *Service) *PointsWriter {
+	return &PointsWriter{
+		PointsWriter: pw,
+		Service:      s,
+	}
+}
+
+// WritePoints writes points to storage, and queues them for replication if
+// they were written. When only some of the points were written, such as when
+// storage drops the points of series which conflict with the stored field
+// types or exceed a series limit, the points which were written are queued.
+func (w *PointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	err := w.PointsWriter.WritePoints(ctx, points)
+	if err == nil {
+		w.Service.EnqueuePoints(points)
+		return nil
+	}
+
+	dropped, ok := droppedSeriesKeys(err)
+	if !ok {
+		return err
+	}
+	keys := make(map[string]struct{}, len(dropped))
+	for _, k := range dropped {
+		keys[string(k)] = struct{}{}
+	}
+	written := make([]models.Point, 0, len(points))
+	for _, p := range points {
+		if _, ok := keys[string(p.Key())]; !ok {
+			written = append(written, p)
+		}
+	}
+	if len(written) > 0 {
+		w.Service.EnqueuePoints(written)
+	}
+	return err
+}
+
+// droppedSeriesKeys returns the series keys of the points dropped by a write
+// which failed with err, and false if err does not describe a partial write.
+// A write may drop the points of different series for different reasons.
+func droppedSeriesKeys(err error) ([][]byte, bool) {
+	var keys [][]byte
+	for _, err := range tsdb.WriteErrors(err) {
+		var (
+			conflictErr *tsdb.FieldTypeConflictError
+			limitErr    *tsdb.SeriesLimitError
+			partialErr  tsdb.PartialWriteError
+		)
+		switch {
+		case errors.As(err, &conflictErr):
+			keys = append(keys, conflictErr.SeriesKeys...)
+		case errors.As(err, &limitErr):
+			keys = append(keys, limitErr.SeriesKeys...)
+		case errors.As(err, &partialErr):
+			keys = append(keys, partialErr.DroppedKeys...)
+		default:
+			return nil, false
+		}
+	}
+	return keys, true
+}
@@ -0,0 +1,135 @@
+package replications
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPointsWriter_PartialWrite(t *testing.T) {
+	writes := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, err := ioutil.ReadAll(gr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		writes <- string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "replications")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := mock.NewReplicationService()
+	store.CreateReplicationFn = func(ctx context.Context, r *influxdb.Replication) error {
+		r.ID = 1
+		r.MaxQueueSizeBytes = influxdb.DefaultReplicationMaxQueueSizeBytes
+		return nil
+	}
+
+	svc := NewService(zaptest.NewLogger(t), store, dir)
+	svc.initialBackoff = time.Millisecond
+	if err := svc.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	err = svc.CreateReplication(context.Background(), &influxdb.Replication{
+		OrgID:          10,
+		Name:           "edge",
+		LocalBucketID:  20,
+		RemoteURL:      server.URL,
+		RemoteToken:    "secret",
+		RemoteOrgID:    30,
+		RemoteBucketID: 40,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	point := func(host string, v float64) models.Point {
+		tags := models.NewTags(map[string]string{
+			models.MeasurementTagKey: "cpu",
+			models.FieldKeyTagKey:    "v",
+			"host":                   host,
+		})
+		p, err := models.NewPoint(tsdb.EncodeNameString(10, 20), tags, models.Fields{"v": v}, time.Unix(0, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	written, dropped := point("a", 1), point("b", 2)
+
+	// the writer drops the points of a new series over the series limit.
+	limitErr := &tsdb.SeriesLimitError{Reason: "bucket limit", SeriesKeys: [][]byte{dropped.Key()}}
+	pw := &mock.PointsWriter{Err: limitErr}
+
+	w := NewPointsWriter(pw, svc)
+	if err := w.WritePoints(context.Background(), []models.Point{written, dropped}); !errors.Is(err, limitErr) {
+		t.Fatalf("expected the series limit error, got %v", err)
+	}
+
+	select {
+	case body := <-writes:
+		if got, want := body, "cpu,host=a v=1 1\n"; got != want {
+			t.Errorf("unexpected body: got %q want %q", got, want)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for replicated points")
+	}
+
+	// the write drops the points of an invalid series and of a new series
+	// over the series limit, for which the engine returns both errors.
+	invalid := point("c", 3)
+	multiErr := &tsdb.MultiWriteError{Errs: []error{
+		tsdb.PartialWriteError{Reason: "invalid", Dropped: 1, DroppedKeys: [][]byte{invalid.Key()}},
+		&tsdb.SeriesLimitError{Reason: "bucket limit", SeriesKeys: [][]byte{dropped.Key()}},
+	}}
+	pw.ForceError(multiErr)
+	if err := w.WritePoints(context.Background(), []models.Point{written, dropped, invalid}); err != multiErr {
+		t.Fatalf("expected the multi write error, got %v", err)
+	}
+
+	select {
+	case body := <-writes:
+		if got, want := body, "cpu,host=a v=1 1\n"; got != want {
+			t.Errorf("unexpected body: got %q want %q", got, want)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for replicated points")
+	}
+
+	// a failed write queues no point.
+	pw.ForceError(errors.New("write failed"))
+	if err := w.WritePoints(context.Background(), []models.Point{written}); err == nil {
+		t.Fatal("expected an error")
+	}
+	select {
+	case body := <-writes:
+		t.Errorf("unexpected replicated points %q", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,528 @@
+package replications
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	// DefaultSegmentSize is the size at which the queue starts a new segment file.
+	DefaultSegmentSize = 10 * 1024 * 1024
+
+	// queueEntryType is the type of the entries of a segment, which are framed
+	// like the entries of a WAL segment.
+	queueEntryType byte = 0x01
+
+	// entryHeaderSize is the size of the type and length of an entry.
+	entryHeaderSize = 5
+
+	segmentExt   = "queue"
+	positionFile = "position"
+)
+
+var (
+	// ErrQueueFull is returned when appending to a queue would exceed its maximum size.
+	ErrQueueFull = errors.New("replication queue is full")
+
+	// ErrQueueClosed is returned when using a closed queue.
+	ErrQueueClosed = errors.New("replication queue is closed")
+)
+
+// Queue is a durable FIFO queue of line protocol, stored in a directory of
+// segment files. Entries are framed like the entries of a WAL segment: a
+// one byte type, the four byte length of the entry and the snappy compressed
+// entry, which holds the time the entry was appended and the line protocol.
+//
+// Entries are read from the head of the queue with Peek, and removed with
+// Ack. The position of the head is stored in the directory, so that the
+// entries which were not acknowledged are read again when the queue is
+// reopened.
+//
+// Appended entries are synced to disk before Append returns, but outside of
+// the lock of the queue: a single sync covers the entries of every append
+// which is waiting for it, so that concurrent appends do not each wait for
+// their own sync, and readers are not held up by them.
+type Queue struct {
+	dir         string
+	segmentSize int64
+
+	mu       sync.Mutex
+	maxSize  int64
+	segments []*segment
+	w        *os.File
+	head     position
+	size     int64
+	appended uint64 // number of entries appended
+	notify   chan struct{}
+	closed   bool
+
+	syncMu sync.Mutex
+	synced uint64 // number of entries synced, guarded by syncMu
+}
+
+// segment is a segment file of a queue.
+type segment struct {
+	id   uint64
+	path string
+	size int64
+}
+
+// position is the position of an entry in the segments of a queue.
+type position struct {
+	segment uint64
+	offset  int64
+}
+
+// Batch is a set of consecutive entries read from the head of a queue.
+type Batch struct {
+	// Data is the line protocol of the entries.
+	Data []byte
+	// Oldest is the time the first entry of the batch was appended.
+	Oldest time.Time
+
+	end  position
+	size int64
+}
+
+// OpenQueue opens the queue stored in dir, creating it if it does not exist.
+// A partially written entry at the end of the queue is discarded.
+func OpenQueue(dir string, maxSize int64) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	q := &Queue{
+		dir:         dir,
+		segmentSize: DefaultSegmentSize,
+		maxSize:     maxSize,
+		notify:      make(chan struct{}, 1),
+	}
+	if err := q.open(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *Queue) open() error {
+	segments, err := q.listSegments()
+	if err != nil {
+		return err
+	}
+
+	head, err := q.readPosition()
+	if err != nil {
+		return err
+	}
+
+	// segments before the head were consumed before the queue was closed.
+	for len(segments) > 0 && segments[0].id < head.segment {
+		if err := os.Remove(segments[0].path); err != nil {
+			return err
+		}
+		segments = segments[1:]
+	}
+	if len(segments) == 0 || segments[0].id != head.segment {
+		head = position{}
+	}
+
+	if len(segments) == 0 {
+		s, err := q.createSegment(head.segment + 1)
+		if err != nil {
+			return err
+		}
+		segments = append(segments, s)
+		head = position{segment: s.id}
+	} else {
+		if head.segment == 0 {
+			head.segment = segments[0].id
+		}
+		if err := repairSegment(segments[len(segments)-1]); err != nil {
+			return err
+		}
+		if head.offset > segments[0].size {
+			head.offset = segments[0].size
+		}
+	}
+
+	tail := segments[len(segments)-1]
+	w, err := os.OpenFile(tail.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	q.segments = segments
+	q.w = w
+	q.head = head
+	for _, s := range segments {
+		q.size += s.size
+	}
+	q.size -= head.offset
+	return nil
+}
+
+func (q *Queue) listSegments() ([]*segment, error) {
+	names, err := filepath.Glob(filepath.Join(q.dir, "*."+segmentExt))
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]*segment, 0, len(names))
+	for _, name := range names {
+		id, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(name), "."+segmentExt), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queue segment %s has an invalid name", name)
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, &segment{id: id, path: name, size: fi.Size()})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].id < segments[j].id })
+	return segments, nil
+}
+
+func (q *Queue) createSegment(id uint64) (*segment, error) {
+	path := filepath.Join(q.dir, fmt.Sprintf("%020d.%s", id, segmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &segment{id: id, path: path}, f.Close()
+}
+
+// repairSegment truncates a segment after its last complete entry.
+func repairSegment(s *segment) error {
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var valid int64
+	for {
+		_, n, err := readEntry(r)
+		if err != nil {
+			break
+		}
+		valid += n
+	}
+
+	if valid != s.size {
+		if err := f.Truncate(valid); err != nil {
+			return err
+		}
+		s.size = valid
+	}
+	return nil
+}
+
+func (q *Queue) readPosition() (position, error) {
+	b, err := ioutil.ReadFile(filepath.Join(q.dir, positionFile))
+	if os.IsNotExist(err) {
+		return position{}, nil
+	} else if err != nil {
+		return position{}, err
+	}
+	if len(b) != 16 {
+		return position{}, fmt.Errorf("queue position file %s is corrupt", filepath.Join(q.dir, positionFile))
+	}
+	return position{
+		segment: binary.BigEndian.Uint64(b[:8]),
+		offset:  int64(binary.BigEndian.Uint64(b[8:])),
+	}, nil
+}
+
+// writePosition atomically replaces the position file with the head of the queue.
+func (q *Queue) writePosition() error {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], q.head.segment)
+	binary.BigEndian.PutUint64(b[8:], uint64(q.head.offset))
+
+	path := filepath.Join(q.dir, positionFile)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b[:], 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Append appends line protocol to the queue. It returns ErrQueueFull if the
+// queue does not have room for the entry.
+func (q *Queue) Append(data []byte, t time.Time) error {
+	payload := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(payload[:8], uint64(t.UnixNano()))
+	copy(payload[8:], data)
+	compressed := snappy.Encode(nil, payload)
+
+	entry := make([]byte, entryHeaderSize+len(compressed))
+	entry[0] = queueEntryType
+	binary.BigEndian.PutUint32(entry[1:entryHeaderSize], uint32(len(compressed)))
+	copy(entry[entryHeaderSize:], compressed)
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrQueueClosed
+	}
+	if q.size+int64(len(entry)) > q.maxSize {
+		q.mu.Unlock()
+		return ErrQueueFull
+	}
+
+	tail := q.segments[len(q.segments)-1]
+	if tail.size >= q.segmentSize {
+		if err := q.roll(); err != nil {
+			q.mu.Unlock()
+			return err
+		}
+		tail = q.segments[len(q.segments)-1]
+	}
+
+	if _, err := q.w.Write(entry); err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	tail.size += int64(len(entry))
+	q.size += int64(len(entry))
+	q.appended++
+	n := q.appended
+	q.mu.Unlock()
+
+	if err := q.sync(n); err != nil {
+		return err
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// sync syncs the tail segment to disk, unless the first n entries appended
+// were synced already by another append.
+func (q *Queue) sync(n uint64) error {
+	q.syncMu.Lock()
+	defer q.syncMu.Unlock()
+	if q.synced >= n {
+		return nil
+	}
+
+	q.mu.Lock()
+	w, appended := q.w, q.appended
+	q.mu.Unlock()
+
+	if err := w.Sync(); err != nil {
+		// a segment is synced when the queue rolls over to the next one,
+		// or when the queue is closed.
+		q.mu.Lock()
+		rolled := q.w != w || q.closed
+		q.mu.Unlock()
+		if !rolled {
+			return err
+		}
+	}
+	q.synced = appended
+	return nil
+}
+
+// roll syncs and closes the tail segment and starts a new one.
+func (q *Queue) roll() error {
+	if err := q.w.Sync(); err != nil {
+		return err
+	}
+	if err := q.w.Close(); err != nil {
+		return err
+	}
+
+	s, err := q.createSegment(q.segments[len(q.segments)-1].id + 1)
+	if err != nil {
+		return err
+	}
+	w, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	q.segments = append(q.segments, s)
+	q.w = w
+	return nil
+}
+
+// Peek reads entries from the head of the queue, until the batch holds at
+// least maxBytes of line protocol or the queue has no more entries. The
+// entries remain in the queue until the batch is acknowledged. It returns
+// io.EOF if the queue is empty.
+func (q *Queue) Peek(maxBytes int) (*Batch, error) {
+	// the segments are read without the lock, so that appends are not held
+	// up by reads. The entries up to the sizes of the segments at this point
+	// are complete, and are only removed by Ack.
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil, ErrQueueClosed
+	}
+	b := &Batch{end: q.head}
+	segments := make([]segment, len(q.segments))
+	for i, s := range q.segments {
+		segments[i] = *s
+	}
+	q.mu.Unlock()
+
+	for i := range segments {
+		s := &segments[i]
+		if s.id < b.end.segment {
+			continue
+		}
+		if err := q.readSegment(s, b, maxBytes); err != nil {
+			return nil, err
+		}
+		if len(b.Data) >= maxBytes || i == len(segments)-1 {
+			break
+		}
+		// the next entry is at the start of the next segment.
+		b.end = position{segment: segments[i+1].id}
+	}
+
+	if len(b.Data) == 0 {
+		return nil, io.EOF
+	}
+	return b, nil
+}
+
+// readSegment reads the entries of s from the end of the batch.
+func (q *Queue) readSegment(s *segment, b *Batch, maxBytes int) error {
+	if b.end.offset >= s.size {
+		return nil
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(b.end.offset, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(io.LimitReader(f, s.size-b.end.offset))
+	for len(b.Data) < maxBytes && b.end.offset < s.size {
+		payload, n, err := readEntry(r)
+		if err != nil {
+			return fmt.Errorf("failed to read queue segment %s at offset %d: %v", s.path, b.end.offset, err)
+		}
+		if len(b.Data) == 0 {
+			b.Oldest = time.Unix(0, int64(binary.BigEndian.Uint64(payload[:8])))
+		}
+		b.Data = append(b.Data, payload[8:]...)
+		b.end.offset += n
+		b.size += n
+	}
+	return nil
+}
+
+// readEntry reads and decompresses an entry. It returns the payload of the
+// entry and the number of bytes read.
+func readEntry(r io.Reader) ([]byte, int64, error) {
+	var hdr [entryHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, 0, err
+	}
+	if hdr[0] != queueEntryType {
+		return nil, 0, fmt.Errorf("unknown entry type %d", hdr[0])
+	}
+
+	compressed := make([]byte, binary.BigEndian.Uint32(hdr[1:]))
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		return nil, 0, err
+	}
+	payload, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(payload) < 8 {
+		return nil, 0, errors.New("entry is too short")
+	}
+	return payload, int64(entryHeaderSize + len(compressed)), nil
+}
+
+// Ack removes the entries of a batch returned by Peek from the queue.
+func (q *Queue) Ack(b *Batch) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	q.head = b.end
+	q.size -= b.size
+
+	// segments before the head, other than the tail, are no longer needed.
+	for len(q.segments) > 1 && q.segments[0].id < q.head.segment {
+		if err := os.Remove(q.segments[0].path); err != nil {
+			return err
+		}
+		q.segments = q.segments[1:]
+	}
+	return q.writePosition()
+}
+
+// Notify returns a channel which receives a value when entries are appended
+// to the queue.
+func (q *Queue) Notify() <-chan struct{} {
+	return q.notify
+}
+
+// Size returns the number of bytes of the entries in the queue.
+func (q *Queue) Size() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// SetMaxSize sets the maximum size of the queue. Entries already in the queue
+// are kept if it is larger than the new maximum size.
+func (q *Queue) SetMaxSize(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.maxSize = n
+}
+
+// Close closes the queue.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	if err := q.w.Sync(); err != nil {
+		q.w.Close()
+		return err
+	}
+	return q.w.Close()
+}
+
+// Remove closes the queue and removes its directory.
+func (q *Queue) Remove() error {
+	if err := q.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(q.dir)
+}
@@ -0,0 +1,252 @@
+package replications
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func mustOpenQueue(t *testing.T, dir string, maxSize int64) *Queue {
+	t.Helper()
+	q, err := OpenQueue(dir, maxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestQueue_AppendPeekAck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 1024*1024)
+	defer q.Close()
+
+	if _, err := q.Peek(1024); err != io.EOF {
+		t.Fatalf("expected io.EOF from empty queue, got %v", err)
+	}
+
+	now := time.Unix(0, 100)
+	for _, lp := range []string{"cpu v=1 1\n", "cpu v=2 2\n", "cpu v=3 3\n"} {
+		if err := q.Append([]byte(lp), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the batch ends once it holds at least maxBytes.
+	b, err := q.Peek(15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b.Data), "cpu v=1 1\ncpu v=2 2\n"; got != want {
+		t.Fatalf("unexpected batch: got %q want %q", got, want)
+	}
+	if !b.Oldest.Equal(now) {
+		t.Errorf("unexpected oldest time: got %v want %v", b.Oldest, now)
+	}
+
+	// entries remain in the queue until they are acknowledged.
+	again, err := q.Peek(15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again.Data) != string(b.Data) {
+		t.Fatalf("unexpected batch before ack: got %q", again.Data)
+	}
+
+	if err := q.Ack(b); err != nil {
+		t.Fatal(err)
+	}
+	b, err = q.Peek(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b.Data), "cpu v=3 3\n"; got != want {
+		t.Fatalf("unexpected batch after ack: got %q want %q", got, want)
+	}
+	if err := q.Ack(b); err != nil {
+		t.Fatal(err)
+	}
+	if q.Size() != 0 {
+		t.Errorf("expected empty queue, got size %d", q.Size())
+	}
+}
+
+func TestQueue_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 1024*1024)
+	q.segmentSize = 32
+	for _, lp := range []string{"cpu v=1 1\n", "cpu v=2 2\n", "cpu v=3 3\n", "cpu v=4 4\n"} {
+		if err := q.Append([]byte(lp), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(q.segments) < 2 {
+		t.Fatalf("expected the queue to roll segments, got %d", len(q.segments))
+	}
+
+	b, err := q.Peek(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Ack(b); err != nil {
+		t.Fatal(err)
+	}
+	size := q.Size()
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("cpu v=5 5\n"), time.Now()); err != ErrQueueClosed {
+		t.Fatalf("expected ErrQueueClosed, got %v", err)
+	}
+
+	q = mustOpenQueue(t, dir, 1024*1024)
+	defer q.Close()
+	if q.Size() != size {
+		t.Errorf("unexpected size after reopening: got %d want %d", q.Size(), size)
+	}
+	b, err = q.Peek(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b.Data), "cpu v=2 2\ncpu v=3 3\ncpu v=4 4\n"; got != want {
+		t.Fatalf("unexpected batch after reopening: got %q want %q", got, want)
+	}
+}
+
+func TestQueue_Full(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 60)
+	defer q.Close()
+
+	if err := q.Append([]byte("cpu v=1 1\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("cpu v=2 2\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("cpu v=3 3\n"), time.Now()); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	b, err := q.Peek(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Ack(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("cpu v=3 3\n"), time.Now()); err != nil {
+		t.Fatalf("expected room in the queue after ack, got %v", err)
+	}
+}
+
+func TestQueue_ConcurrentAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 1<<20)
+	defer q.Close()
+	// small segments make the appends roll over to new segments.
+	q.segmentSize = 256
+
+	const writers, appends = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < appends; j++ {
+				if err := q.Append([]byte(fmt.Sprintf("cpu,w=%d v=%d\n", i, j)), time.Now()); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if q.synced != writers*appends {
+		t.Fatalf("got %d entries synced, expected %d", q.synced, writers*appends)
+	}
+
+	b, err := q.Peek(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b.Data), "\n"), "\n")
+	if got, want := len(lines), writers*appends; got != want {
+		t.Fatalf("got %d entries, expected %d", got, want)
+	}
+	sort.Strings(lines)
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == lines[i-1] {
+			t.Fatalf("duplicate entry %q", lines[i])
+		}
+	}
+}
+
+func TestQueue_RepairTornEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 1024*1024)
+	if err := q.Append([]byte("cpu v=1 1\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a crash part way through writing an entry.
+	paths, err := filepath.Glob(filepath.Join(dir, "*."+segmentExt))
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("expected a single segment, got %v: %v", paths, err)
+	}
+	f, err := os.OpenFile(paths[0], os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{queueEntryType, 0, 0, 0, 42, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	q = mustOpenQueue(t, dir, 1024*1024)
+	defer q.Close()
+	if err := q.Append([]byte("cpu v=2 2\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := q.Peek(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b.Data), "cpu v=1 1\ncpu v=2 2\n"; got != want {
+		t.Fatalf("unexpected batch after repair: got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,328 @@
+// Package replications replicates the points written to local buckets to
+// buckets of remote InfluxDB instances.
+//
+// Every replication has a durable queue on disk. The points written to the
+// local bucket of a replication are appended to its queue as line protocol
+// once they are written to storage, and a background stream sends them to
+// the write API of the remote instance, retrying with a backoff while the
+// remote is unavailable. Points are dropped when the queue is full, so that
+// local writes never fail because a remote is unavailable.
+package replications
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DefaultRemoteTimeout is the timeout of a write to a remote instance.
+const DefaultRemoteTimeout = 30 * time.Second
+
+var _ influxdb.ReplicationService = (*Service)(nil)
+
+// Service manages the queues and streams of replications. It wraps the
+// service which stores the replications, so that streams are started,
+// updated and stopped when replications are created, updated and deleted.
+type Service struct {
+	store   influxdb.ReplicationService
+	dir     string
+	client  *http.Client
+	metrics *metrics
+	log     *zap.Logger
+
+	maxBatchBytes  int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	mu      sync.RWMutex
+	streams map[influxdb.ID]*stream
+	buckets map[influxdb.ID][]*stream
+}
+
+// NewService returns a Service which stores the queues of the replications
+// in store in dir.
+func NewService(log *zap.Logger, store influxdb.ReplicationService, dir string) *Service {
+	return &Service{
+		store:          store,
+		dir:            dir,
+		client:         &http.Client{Timeout: DefaultRemoteTimeout},
+		metrics:        newMetrics(),
+		log:            log,
+		maxBatchBytes:  DefaultMaxBatchBytes,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		streams:        make(map[influxdb.ID]*stream),
+		buckets:        make(map[influxdb.ID][]*stream),
+	}
+}
+
+// Open opens the queues of the stored replications and starts sending the
+// points in them.
+func (s *Service) Open(ctx context.Context) error {
+	rs, _, err := s.store.FindReplications(ctx, influxdb.ReplicationFilter{})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range rs {
+		if err := s.addStream(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops every stream and closes the queues.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for id, st := range s.streams {
+		st.stop()
+		if err := st.queue.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.streams, id)
+	}
+	s.buckets = make(map[influxdb.ID][]*stream)
+	return firstErr
+}
+
+// PrometheusCollectors returns the metrics of the replications.
+func (s *Service) PrometheusCollectors() []prometheus.Collector {
+	return s.metrics.PrometheusCollectors()
+}
+
+// addStream opens the queue of a replication and starts its stream.
+func (s *Service) addStream(r *influxdb.Replication) error {
+	q, err := OpenQueue(filepath.Join(s.dir, r.ID.String()), r.MaxQueueSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	st := &stream{
+		id:             r.ID,
+		bucketID:       r.LocalBucketID,
+		queue:          q,
+		client:         s.client,
+		metrics:        s.metrics,
+		log:            s.log.With(zap.String("replication_id", r.ID.String())),
+		maxBatchBytes:  s.maxBatchBytes,
+		initialBackoff: s.initialBackoff,
+		maxBackoff:     s.maxBackoff,
+		replication:    *r,
+	}
+	st.start()
+
+	s.streams[r.ID] = st
+	s.buckets[r.LocalBucketID] = append(s.buckets[r.LocalBucketID], st)
+	return nil
+}
+
+// removeStream stops the stream of a replication and removes its queue.
+func (s *Service) removeStream(id influxdb.ID) error {
+	st, ok := s.streams[id]
+	if !ok {
+		return nil
+	}
+	st.stop()
+	delete(s.streams, id)
+
+	bucketID := st.bucketID
+	streams := s.buckets[bucketID][:0]
+	for _, other := range s.buckets[bucketID] {
+		if other != st {
+			streams = append(streams, other)
+		}
+	}
+	if len(streams) == 0 {
+		delete(s.buckets, bucketID)
+	} else {
+		s.buckets[bucketID] = streams
+	}
+
+	s.metrics.remove(id.String())
+	return st.queue.Remove()
+}
+
+// setStatus sets the status of the replications which have a stream.
+func (s *Service) setStatus(rs ...*influxdb.Replication) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range rs {
+		if st, ok := s.streams[r.ID]; ok {
+			st.status(r)
+		}
+	}
+}
+
+// FindReplicationByID returns a single replication by ID, with the status of its queue.
+func (s *Service) FindReplicationByID(ctx context.Context, id influxdb.ID) (*influxdb.Replication, error) {
+	r, err := s.store.FindReplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.setStatus(r)
+	return r, nil
+}
+
+// FindReplications returns the replications that match filter, with the status of their queues.
+func (s *Service) FindReplications(ctx context.Context, filter influxdb.ReplicationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Replication, int, error) {
+	rs, n, err := s.store.FindReplications(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.setStatus(rs...)
+	return rs, n, nil
+}
+
+// CreateReplication creates a new replication and starts sending the points
+// written to its local bucket.
+func (s *Service) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	if err := s.store.CreateReplication(ctx, r); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	err := s.addStream(r)
+	s.mu.Unlock()
+	if err != nil {
+		if derr := s.store.DeleteReplication(ctx, r.ID); derr != nil {
+			s.log.Error("Failed to delete replication without a queue", zap.Stringer("replication_id", r.ID), zap.Error(derr))
+		}
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "failed to create replication queue",
+			Op:   influxdb.OpCreateReplication,
+			Err:  err,
+		}
+	}
+	s.setStatus(r)
+	return nil
+}
+
+// UpdateReplication updates a replication, and the remote its stream sends
+// points to.
+func (s *Service) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	r, err := s.store.UpdateReplication(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	if st, ok := s.streams[id]; ok {
+		st.setReplication(*r)
+	}
+	s.mu.RUnlock()
+
+	s.setStatus(r)
+	return r, nil
+}
+
+// DeleteReplication removes a replication, and discards the points in its queue.
+func (s *Service) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	if err := s.store.DeleteReplication(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.removeStream(id); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "failed to remove replication queue",
+			Op:   influxdb.OpDeleteReplication,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// EnqueuePoints appends points written to storage to the queues of the
+// replications of their buckets. Points which cannot be queued are dropped,
+// which is logged once when a queue fills up and counted by the metrics.
+func (s *Service) EnqueuePoints(points []models.Point) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.buckets) == 0 {
+		return
+	}
+
+	batches := make(map[influxdb.ID][]byte)
+	for _, pt := range points {
+		_, bucketID := tsdb.DecodeNameSlice(pt.Name())
+		if _, ok := s.buckets[bucketID]; !ok {
+			continue
+		}
+
+		buf, err := appendLineProtocol(batches[bucketID], pt)
+		if err != nil {
+			s.log.Info("Unable to replicate point", zap.Stringer("bucket_id", bucketID), zap.Error(err))
+			continue
+		}
+		batches[bucketID] = buf
+	}
+
+	now := time.Now()
+	for bucketID, data := range batches {
+		n := float64(bytes.Count(data, []byte("\n")))
+		for _, st := range s.buckets[bucketID] {
+			label := st.id.String()
+			if err := st.queue.Append(data, now); err != nil {
+				reason := "queue_full"
+				if err != ErrQueueFull {
+					reason = "queue_error"
+					st.log.Error("Failed to append points to replication queue", zap.Error(err))
+				} else {
+					st.setQueueFull(true)
+				}
+				s.metrics.PointsDropped.WithLabelValues(label, reason).Add(n)
+				continue
+			}
+			st.setQueueFull(false)
+			s.metrics.PointsQueued.WithLabelValues(label).Add(n)
+			s.metrics.QueueSizeBytes.WithLabelValues(label).Set(float64(st.queue.Size()))
+		}
+	}
+}
+
+// appendLineProtocol appends the line protocol of a point in the storage
+// format, which holds its measurement and field key in tags, to buf.
+func appendLineProtocol(buf []byte, pt models.Point) ([]byte, error) {
+	tags := pt.Tags()
+	measurement := tags.Get(models.MeasurementTagKeyBytes)
+
+	userTags := make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		if bytes.Equal(t.Key, models.MeasurementTagKeyBytes) || bytes.Equal(t.Key, models.FieldKeyTagKeyBytes) {
+			continue
+		}
+		userTags = append(userTags, t)
+	}
+
+	fields, err := pt.Fields()
+	if err != nil {
+		return buf, err
+	}
+
+	p, err := models.NewPoint(string(measurement), userTags, fields, pt.Time())
+	if err != nil {
+		return buf, err
+	}
+	buf = p.AppendString(buf)
+	return append(buf, '\n'), nil
+}
@@ -0,0 +1,172 @@
+package replications
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type remoteWrite struct {
+	query         string
+	authorization string
+	body          string
+}
+
+func TestService_ReplicatesPoints(t *testing.T) {
+	writes := make(chan remoteWrite, 10)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, err := ioutil.ReadAll(gr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		writes <- remoteWrite{
+			query:         r.URL.RawQuery,
+			authorization: r.Header.Get("Authorization"),
+			body:          string(body),
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "replications")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := mock.NewReplicationService()
+	store.CreateReplicationFn = func(ctx context.Context, r *influxdb.Replication) error {
+		r.ID = 1
+		r.MaxQueueSizeBytes = influxdb.DefaultReplicationMaxQueueSizeBytes
+		return nil
+	}
+
+	svc := NewService(zaptest.NewLogger(t), store, dir)
+	svc.initialBackoff = time.Millisecond
+	if err := svc.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	r := &influxdb.Replication{
+		OrgID:          10,
+		Name:           "edge",
+		LocalBucketID:  20,
+		RemoteURL:      server.URL,
+		RemoteToken:    "secret",
+		RemoteOrgID:    30,
+		RemoteBucketID: 40,
+	}
+	if err := svc.CreateReplication(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := models.NewTags(map[string]string{
+		models.MeasurementTagKey: "cpu",
+		models.FieldKeyTagKey:    "v",
+		"host":                   "a",
+	})
+	replicated, err := models.NewPoint(tsdb.EncodeNameString(10, 20), tags, models.Fields{"v": 1.0}, time.Unix(0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := models.NewPoint(tsdb.EncodeNameString(10, 21), tags, models.Fields{"v": 2.0}, time.Unix(0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.EnqueuePoints([]models.Point{replicated, other})
+
+	select {
+	case w := <-writes:
+		if got, want := w.body, "cpu,host=a v=1 1\n"; got != want {
+			t.Errorf("unexpected body: got %q want %q", got, want)
+		}
+		if got, want := w.query, "bucket=0000000000000028&orgID=000000000000001e&precision=ns"; got != want {
+			t.Errorf("unexpected query: got %q want %q", got, want)
+		}
+		if got, want := w.authorization, "Token secret"; got != want {
+			t.Errorf("unexpected authorization: got %q want %q", got, want)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for replicated points")
+	}
+
+	// the queue is emptied once the write is acknowledged.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var status influxdb.Replication
+		status.ID = r.ID
+		svc.setStatus(&status)
+		if status.CurrentQueueSizeBytes == 0 && status.LatestResponseCode == http.StatusNoContent {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected replication status: %+v", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestService_DeleteReplicationRemovesQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replications")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := mock.NewReplicationService()
+	store.FindReplicationsFn = func(context.Context, influxdb.ReplicationFilter, ...influxdb.FindOptions) ([]*influxdb.Replication, int, error) {
+		return []*influxdb.Replication{{
+			ID:                1,
+			LocalBucketID:     20,
+			RemoteURL:         "http://127.0.0.1:1",
+			MaxQueueSizeBytes: influxdb.DefaultReplicationMaxQueueSizeBytes,
+		}}, 1, nil
+	}
+
+	svc := NewService(zaptest.NewLogger(t), store, dir)
+	if err := svc.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	queueDir := filepath.Join(dir, influxdb.ID(1).String())
+	if _, err := os.Stat(queueDir); err != nil {
+		t.Fatalf("expected queue directory to exist: %v", err)
+	}
+
+	if err := svc.DeleteReplication(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(queueDir); !os.IsNotExist(err) {
+		t.Fatalf("expected queue directory to be removed, got %v", err)
+	}
+	if len(svc.buckets) != 0 {
+		t.Errorf("unexpected streams for buckets: %v", svc.buckets)
+	}
+}
@@ -0,0 +1,301 @@
+package replications
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxBatchBytes is the largest amount of line protocol sent in a
+	// single request, unless a single queue entry is larger.
+	DefaultMaxBatchBytes = 500 * 1024
+
+	// DefaultInitialBackoff is the time waited before retrying a failed write.
+	DefaultInitialBackoff = time.Second
+
+	// DefaultMaxBackoff is the longest time waited before retrying a failed write.
+	DefaultMaxBackoff = 5 * time.Minute
+
+	// maxErrorMessageSize limits the response body recorded as the latest error.
+	maxErrorMessageSize = 1024
+)
+
+// stream sends the points queued for a replication to its remote instance.
+type stream struct {
+	id       influxdb.ID
+	bucketID influxdb.ID
+	queue    *Queue
+	client   *http.Client
+	metrics  *metrics
+	log      *zap.Logger
+
+	maxBatchBytes  int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	mu           sync.Mutex
+	replication  influxdb.Replication
+	responseCode int
+	errorMessage string
+	// queueFull is set while points are dropped because the queue is full.
+	queueFull bool
+
+	// update is signalled when the remote of the replication changes, so that
+	// a write which is waiting to be retried is retried immediately.
+	update chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// setQueueFull records whether the queue is full, and logs when points start
+// to be dropped and when they stop being dropped.
+func (s *stream) setQueueFull(full bool) {
+	s.mu.Lock()
+	changed := s.queueFull != full
+	s.queueFull = full
+	s.mu.Unlock()
+
+	switch {
+	case changed && full:
+		s.log.Warn("Replication queue is full, dropping points until the remote catches up", zap.Int64("queue_size_bytes", s.queue.Size()))
+	case changed:
+		s.log.Info("Replication queue has room again, no longer dropping points")
+	}
+}
+
+func (s *stream) start() {
+	s.update = make(chan struct{}, 1)
+	s.done = make(chan struct{})
+	s.wg.Add(1)
+	go s.run()
+}
+
+// stop stops sending points. A write in progress is cancelled and retried
+// when the stream is started again.
+func (s *stream) stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// setReplication updates the remote and queue size of the replication.
+func (s *stream) setReplication(r influxdb.Replication) {
+	s.mu.Lock()
+	s.replication = r
+	s.mu.Unlock()
+
+	s.queue.SetMaxSize(r.MaxQueueSizeBytes)
+	select {
+	case s.update <- struct{}{}:
+	default:
+	}
+}
+
+// status sets the status of the replication on r.
+func (s *stream) status(r *influxdb.Replication) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.CurrentQueueSizeBytes = s.queue.Size()
+	r.LatestResponseCode = s.responseCode
+	r.LatestErrorMessage = s.errorMessage
+}
+
+func (s *stream) run() {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.done
+		cancel()
+	}()
+
+	label := s.id.String()
+	for {
+		s.metrics.QueueSizeBytes.WithLabelValues(label).Set(float64(s.queue.Size()))
+
+		b, err := s.queue.Peek(s.maxBatchBytes)
+		if err == io.EOF {
+			s.metrics.QueueLag.WithLabelValues(label).Set(0)
+			select {
+			case <-s.queue.Notify():
+				continue
+			case <-s.done:
+				return
+			}
+		} else if err != nil {
+			s.log.Error("Failed to read replication queue", zap.Error(err))
+			if !s.wait(s.maxBackoff) {
+				return
+			}
+			continue
+		}
+
+		if !s.send(ctx, b) {
+			return
+		}
+		if err := s.queue.Ack(b); err != nil {
+			s.log.Error("Failed to remove sent points from replication queue", zap.Error(err))
+		}
+	}
+}
+
+// send writes a batch to the remote, retrying with an exponential backoff
+// until it is written or rejected. It returns false if the stream is stopped
+// before the batch is written.
+func (s *stream) send(ctx context.Context, b *Batch) bool {
+	label := s.id.String()
+	points := float64(bytes.Count(b.Data, []byte("\n")))
+
+	backoff := s.initialBackoff
+	for {
+		s.metrics.QueueLag.WithLabelValues(label).Set(time.Since(b.Oldest).Seconds())
+
+		code, retryAfter, err := s.write(ctx, b.Data)
+		if err == nil {
+			s.setStatus(code, nil)
+			s.metrics.PointsSent.WithLabelValues(label).Add(points)
+			s.metrics.BytesSent.WithLabelValues(label).Add(float64(len(b.Data)))
+			return true
+		}
+
+		select {
+		case <-s.done:
+			return false
+		default:
+		}
+
+		s.setStatus(code, err)
+		s.metrics.RemoteErrors.WithLabelValues(label, strconv.Itoa(code)).Inc()
+		if !retryable(code) {
+			s.log.Error("Remote rejected replicated points, dropping them",
+				zap.Int("status_code", code), zap.Float64("points", points), zap.Error(err))
+			s.metrics.PointsDropped.WithLabelValues(label, "rejected").Add(points)
+			return true
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		s.log.Info("Failed to write replicated points, retrying",
+			zap.Int("status_code", code), zap.Duration("retry_in", wait), zap.Error(err))
+		if !s.wait(wait) {
+			return false
+		}
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// wait waits for d, or until the replication is updated. It returns false if
+// the stream is stopped.
+func (s *stream) wait(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-s.update:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (s *stream) setStatus(code int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responseCode = code
+	s.errorMessage = ""
+	if err != nil {
+		s.errorMessage = err.Error()
+	}
+}
+
+// write sends line protocol to the write API of the remote. It returns the
+// status code of the response, which is zero if no response was received,
+// and the time to wait before retrying requested by the remote.
+func (s *stream) write(ctx context.Context, data []byte) (int, time.Duration, error) {
+	s.mu.Lock()
+	r := s.replication
+	s.mu.Unlock()
+
+	u, err := url.Parse(strings.TrimSuffix(r.RemoteURL, "/") + "/api/v2/write")
+	if err != nil {
+		return 0, 0, err
+	}
+	u.RawQuery = url.Values{
+		"orgID":     []string{r.RemoteOrgID.String()},
+		"bucket":    []string{r.RemoteBucketID.String()},
+		"precision": []string{"ns"},
+	}.Encode()
+
+	var body bytes.Buffer
+	gw := gzip.NewWriter(&body)
+	if _, err := gw.Write(data); err != nil {
+		return 0, 0, err
+	}
+	if err := gw.Close(); err != nil {
+		return 0, 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), &body)
+	if err != nil {
+		return 0, 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+	if r.RemoteToken != "" {
+		req.Header.Set("Authorization", "Token "+r.RemoteToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		io.Copy(ioutil.Discard, resp.Body)
+		return resp.StatusCode, 0, nil
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	return resp.StatusCode, retryAfter, fmt.Errorf("remote write failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// retryable reports whether a write which failed with the status code may
+// succeed if it is retried. Writes which the remote could not parse, or
+// which are too large, are never retried.
+func retryable(code int) bool {
+	switch code {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,126 @@
+package influxdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// RestoreBucketRequest identifies the bucket of a backup to restore, and the
+// bucket its data is restored into.
+type RestoreBucketRequest struct {
+	// SourceOrgID and SourceBucketID are the organization and bucket the
+	// data belonged to when the backup was taken.
+	SourceOrgID    ID `json:"sourceOrgID"`
+	SourceBucketID ID `json:"sourceBucketID"`
+	// OrgID and BucketID are the organization and bucket the data is
+	// restored into. They may differ from the source, to restore a bucket
+	// next to its current version or into another organization.
+	OrgID    ID `json:"orgID"`
+	BucketID ID `json:"bucketID"`
+}
+
+// RestoreService represents the data restore functions of InfluxDB.
+type RestoreService interface {
+	// RestoreBucket writes the points of the source bucket found in a TSM
+	// file of a backup to the bucket of the request, and returns the number
+	// of points written. The points deleted by the tombstone file of the TSM
+	// file, if not nil, are not restored.
+	RestoreBucket(ctx context.Context, req RestoreBucketRequest, tsm, tombstone io.Reader) (int, error)
+	// RestoreEngine replaces all of the data of the engine with the engine
+	// files of the last backup of a chain of backups, keyed by file name.
+	// The files are verified against the manifest of the backup before any
+	// data is replaced. They are the decoded files, whatever the compression
+	// and encryption of the backup where it is stored.
+	RestoreEngine(ctx context.Context, chain []*BackupManifest, files map[string]io.Reader) error
+}
+
+// KVRestoreService represents the meta data restore functions of InfluxDB.
+type KVRestoreService interface {
+	// RestoreKVStore replaces the meta data with the meta data of a chain of
+	// backups: kv is the bolt file of the full backup the chain starts with,
+	// and changes are the meta data changes of each incremental backup of the
+	// chain, in order. Like the files of RestoreEngine, they are decoded and
+	// verified against the manifests of the backups before any meta data is
+	// replaced.
+	RestoreKVStore(ctx context.Context, chain []*BackupManifest, kv io.Reader, changes []io.Reader) error
+}
+
+// ValidateBackupChain returns an error unless chain starts with a full backup,
+// and every backup after it is an incremental backup taken from the backup
+// before it.
+func ValidateBackupChain(chain []*BackupManifest) error {
+	if len(chain) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "no backup manifest given",
+		}
+	}
+	if chain[0].Incremental() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("backup %s is incremental; the full backup it was taken from is required", chain[0].ID),
+		}
+	}
+	for i, m := range chain[1:] {
+		parent := chain[i]
+		if !m.Incremental() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("backup %s is not incremental", m.ID),
+			}
+		}
+		if *m.ParentID != parent.ID {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("backup %s was taken from backup %s, not from backup %s", m.ID, *m.ParentID, parent.ID),
+			}
+		}
+	}
+	return nil
+}
+
+// NewBackupFileReader returns a reader of the backup file f read from r. Once
+// r is read to the end, the reader returns an error instead of io.EOF if the
+// size or the checksum of what was read differs from the manifest.
+func NewBackupFileReader(r io.Reader, f BackupFile) io.Reader {
+	return &backupFileReader{r: r, f: f, h: sha256.New()}
+}
+
+type backupFileReader struct {
+	r io.Reader
+	f BackupFile
+	h hash.Hash
+	n int64
+}
+
+func (r *backupFileReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	r.h.Write(p[:n])
+	if err == io.EOF {
+		if verr := r.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+func (r *backupFileReader) verify() error {
+	if r.n != r.f.Size {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("backup file %s is %d bytes, the manifest expects %d bytes", r.f.Name, r.n, r.f.Size),
+		}
+	}
+	if sum := hex.EncodeToString(r.h.Sum(nil)); r.f.SHA256 != "" && sum != r.f.SHA256 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("checksum of backup file %s does not match the manifest", r.f.Name),
+		}
+	}
+	return nil
+}
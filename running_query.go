@@ -0,0 +1,51 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// RunningQuery is a query which is being compiled, is waiting in the queue to
+// be executed, or is executing.
+type RunningQuery struct {
+	ID     ID     `json:"id"`
+	OrgID  ID     `json:"orgID"`
+	UserID ID     `json:"userID,omitempty"`
+	State  string `json:"state"`
+	Query  string `json:"query,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	// The time spent in each state, including the time spent so far in
+	// the current state.
+	CompileDuration time.Duration `json:"compileDuration"`
+	QueueDuration   time.Duration `json:"queueDuration"`
+	ExecuteDuration time.Duration `json:"executeDuration"`
+
+	// MemoryBytes is the memory allocated by the query.
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// RunningQueryFilter represents a set of filters that restrict the returned running queries.
+type RunningQueryFilter struct {
+	OrgID *ID
+}
+
+// ops for running query errors.
+var (
+	OpFindRunningQueryByID = "FindRunningQueryByID"
+	OpFindRunningQueries   = "FindRunningQueries"
+	OpCancelRunningQuery   = "CancelRunningQuery"
+)
+
+// RunningQueryService lists and cancels the queries running on a server.
+type RunningQueryService interface {
+	// FindRunningQueryByID returns a single running query by ID.
+	FindRunningQueryByID(ctx context.Context, id ID) (*RunningQuery, error)
+
+	// FindRunningQueries returns the running queries that match filter.
+	FindRunningQueries(ctx context.Context, filter RunningQueryFilter) ([]*RunningQuery, error)
+
+	// CancelRunningQuery stops the execution of a running query.
+	CancelRunningQuery(ctx context.Context, id ID) error
+}
@@ -0,0 +1,97 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// Script is a named Flux script which is stored by the server and invoked by
+// its ID, so that clients do not have to send the script with every query.
+// The parameters of an invocation are bound to the params record of the script,
+// such as params.bucket.
+type Script struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Script      string `json:"script"`
+	CRUDLog
+}
+
+// Validate reports any validation errors for the script.
+func (s *Script) Validate() error {
+	if s.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "script name is required",
+		}
+	}
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "script orgID is required",
+		}
+	}
+	if s.Script == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "script must not be empty",
+		}
+	}
+	return nil
+}
+
+// ScriptUpdate represents updates to a script.
+// Only fields which are set are updated.
+type ScriptUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Script      *string `json:"script,omitempty"`
+}
+
+// Apply applies an update to a script.
+func (u ScriptUpdate) Apply(s *Script) error {
+	if u.Name != nil {
+		s.Name = *u.Name
+	}
+	if u.Description != nil {
+		s.Description = *u.Description
+	}
+	if u.Script != nil {
+		s.Script = *u.Script
+	}
+	return s.Validate()
+}
+
+// ScriptFilter represents a set of filters that restrict the returned scripts.
+type ScriptFilter struct {
+	OrgID *ID
+	Name  *string
+}
+
+// ops for script errors.
+var (
+	OpFindScriptByID = "FindScriptByID"
+	OpFindScripts    = "FindScripts"
+	OpCreateScript   = "CreateScript"
+	OpUpdateScript   = "UpdateScript"
+	OpDeleteScript   = "DeleteScript"
+)
+
+// ScriptService manages the saved scripts of organizations.
+type ScriptService interface {
+	// FindScriptByID returns a single script by ID.
+	FindScriptByID(ctx context.Context, id ID) (*Script, error)
+
+	// FindScripts returns the scripts that match filter.
+	FindScripts(ctx context.Context, filter ScriptFilter, opt ...FindOptions) ([]*Script, int, error)
+
+	// CreateScript creates a new script and sets s.ID with the new identifier.
+	CreateScript(ctx context.Context, s *Script) error
+
+	// UpdateScript updates a single script with changeset.
+	// Returns the new script after update.
+	UpdateScript(ctx context.Context, id ID, upd ScriptUpdate) (*Script, error)
+
+	// DeleteScript removes a script by ID.
+	DeleteScript(ctx context.Context, id ID) error
+}
@@ -41,6 +41,12 @@ type Config struct {
 	// Index config.
 	Index     tsi1.Config `toml:"index"`
 	IndexPath string      `toml:"index-path"` // Overrides the default path.
+
+	// Maximum number of series in a bucket, and in all of the buckets of an
+	// organization. Writes which would create new series beyond the limits are
+	// rejected. Zero disables the limit.
+	MaxSeriesPerBucket int `toml:"max-series-per-bucket"`
+	MaxSeriesPerOrg    int `toml:"max-series-per-org"`
 }
 
 // NewConfig initialises a new config for an Engine.
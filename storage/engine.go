@@ -17,6 +17,7 @@ import (
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
@@ -61,6 +62,8 @@ type Engine struct {
 	retentionEnforcer        runner
 	retentionEnforcerLimiter runnable
 
+	seriesLimiter *seriesLimiter
+
 	defaultMetricLabels prometheus.Labels
 
 	// Tracks all goroutines started by the Engine.
@@ -181,6 +184,8 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	// Initialise Engine
 	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine, tsm1.WithSnapshotter(e))
 
+	e.seriesLimiter = newSeriesLimiter(c.MaxSeriesPerBucket, c.MaxSeriesPerOrg, e.index)
+
 	// Apply options.
 	for _, option := range options {
 		option(e)
@@ -520,6 +525,18 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Drop the points of new series which would exceed a series limit. The
+	// points of existing series are still written.
+	// The new series admitted are only counted once they are written.
+	admitted, limitErr, err := e.limitSeries(collection)
+	var written map[string]bool
+	defer func() { e.seriesLimiter.done(admitted, written) }()
+	if err != nil {
+		return err
+	} else if limitErr != nil && collection.Length() == 0 {
+		return limitErr
+	}
+
 	// Convert the collection to values for adding to the WAL/Cache.
 	values, err := tsm1.CollectionToValues(collection)
 	if err != nil {
@@ -531,7 +548,118 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return err
 	}
 
-	return e.writePointsLocked(ctx, collection, values)
+	// The series left in the collection after a partial write were created,
+	// even if some of their values were dropped.
+	err = e.writePointsLocked(ctx, collection, values)
+	if err != nil && !isDroppedValuesError(err) {
+		return err
+	}
+	written = writtenSeries(collection, admitted)
+
+	errs := tsdb.WriteErrors(err)
+	if limitErr != nil {
+		errs = append(errs, limitErr)
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return &tsdb.MultiWriteError{Errs: errs}
+	}
+}
+
+// isDroppedValuesError returns true if the error of a write describes values
+// which were dropped, while the other values of the write were written.
+func isDroppedValuesError(err error) bool {
+	switch err.(type) {
+	case tsdb.PartialWriteError, *tsdb.FieldTypeConflictError, *tsdb.MultiWriteError:
+		return true
+	}
+	return false
+}
+
+// writtenSeries returns the keys of the admitted series which are in the
+// collection.
+func writtenSeries(collection *tsdb.SeriesCollection, admitted seriesAdmissions) map[string]bool {
+	if len(admitted) == 0 {
+		return nil
+	}
+	written := make(map[string]bool, len(admitted))
+	for _, key := range admitted {
+		written[key] = false
+	}
+
+	var buf []byte
+	for iter := collection.Iterator(); iter.Next(); {
+		buf = tsdb.AppendSeriesKey(buf[:0], iter.Name(), iter.Tags())
+		if _, ok := written[string(buf)]; ok {
+			written[string(buf)] = true
+		}
+	}
+	return written
+}
+
+// limitSeries removes the entries of new series from the collection which
+// would exceed the series limit of their bucket or organization. If any
+// entries were removed, a *tsdb.SeriesLimitError is returned describing them.
+// The new series admitted are returned, even with an error, to be passed to
+// seriesLimiter.done. It must be called under some sort of lock.
+func (e *Engine) limitSeries(collection *tsdb.SeriesCollection) (seriesAdmissions, *tsdb.SeriesLimitError, error) {
+	if !e.seriesLimiter.enabled() {
+		return nil, nil, nil
+	}
+
+	var (
+		buf      []byte
+		j        int
+		dropped  [][]byte
+		reason   string
+		admitted = make(map[string]bool) // new series seen in this write
+		pending  seriesAdmissions
+	)
+	for iter := collection.Iterator(); iter.Next(); {
+		buf = tsdb.AppendSeriesKey(buf[:0], iter.Name(), iter.Tags())
+
+		ok, seen := admitted[string(buf)]
+		if !seen {
+			// series which were deleted from the index are new series.
+			id := e.sfile.SeriesIDTypedBySeriesKey(buf)
+			if !id.IsZero() && e.index.HasSeriesID(iter.Key(), id.SeriesID()) {
+				ok = true
+			} else {
+				var why string
+				var err error
+				if ok, why, err = e.seriesLimiter.admit(iter.Name(), buf); err != nil {
+					return pending, nil, err
+				}
+				if ok {
+					pending = append(pending, string(buf))
+				}
+				if reason == "" {
+					reason = why
+				}
+			}
+			admitted[string(buf)] = ok
+		}
+
+		if !ok {
+			dropped = append(dropped, iter.Key())
+			continue
+		}
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+
+	if len(dropped) == 0 {
+		return pending, nil, nil
+	}
+	return pending, &tsdb.SeriesLimitError{
+		Reason:     reason,
+		SeriesKeys: bytesutil.SortDedup(dropped),
+	}, nil
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
@@ -558,8 +686,14 @@ func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesC
 		}
 	}
 
-	// Write the values to the engine.
+	// Write the values to the engine. The values of a field type conflict
+	// are dropped as well as those of the partial write, if any.
 	if err := e.engine.WriteValues(values); err != nil {
+		if _, ok := err.(*tsdb.FieldTypeConflictError); ok {
+			if partialErr := collection.PartialWriteError(); partialErr != nil {
+				return &tsdb.MultiWriteError{Errs: []error{partialErr, err}}
+			}
+		}
 		return err
 	}
 
@@ -664,6 +798,9 @@ func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID pl
 	encoded := tsdb.EncodeName(orgID, bucketID)
 	name := models.EscapeMeasurement(encoded[:])
 
+	// series may be deleted along with their data, so they are counted again.
+	defer e.seriesLimiter.invalidate(encoded)
+
 	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
 }
 
@@ -780,6 +917,21 @@ func (e *Engine) SeriesCardinality() int64 {
 	return e.index.SeriesN()
 }
 
+// BucketSeriesCardinality returns the number of series in a bucket.
+func (e *Engine) BucketSeriesCardinality(ctx context.Context, orgID, bucketID platform.ID) (int64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, ErrEngineClosed
+	}
+
+	n, err := e.seriesLimiter.cardinality(tsdb.EncodeName(orgID, bucketID))
+	return int64(n), err
+}
+
 // Path returns the path of the engine's base directory.
 func (e *Engine) Path() string {
 	return e.path
@@ -2,6 +2,7 @@ package storage_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -490,6 +491,116 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 	}
 }
 
+func TestEngine_WriteSeriesLimit(t *testing.T) {
+	c := storage.NewConfig()
+	c.MaxSeriesPerBucket = 2
+	engine := NewEngine(c, rand.Int(), rand.Int())
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(host string) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a"), point("b"), point("c")})
+	limitErr, ok := err.(*tsdb.SeriesLimitError)
+	if !ok {
+		t.Fatal("expected series limit error. got:", err)
+	}
+	if got, exp := len(limitErr.SeriesKeys), 1; got != exp {
+		t.Fatalf("got %d dropped series, expected %d", got, exp)
+	}
+
+	// points of existing series are always written.
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a"), point("b")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("d")}); !errors.Is(err, tsdb.ErrSeriesLimitExceeded) {
+		t.Fatal("expected series limit error. got:", err)
+	}
+
+	n, err := engine.BucketSeriesCardinality(context.TODO(), engine.org, engine.bucket)
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatalf("got %d series, expected 2", n)
+	}
+
+	// deleting the series of the bucket makes room for new ones.
+	if err := engine.DeleteBucket(context.TODO(), engine.org, engine.bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("c"), point("d")}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngine_WriteSeriesLimitPartial(t *testing.T) {
+	c := storage.NewConfig()
+	c.MaxSeriesPerBucket = 2
+	engine := NewEngine(c, rand.Int(), rand.Int())
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(host string) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+	invalid := models.MustNewPoint(
+		name,
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+
+	// the write drops the invalid point and the point of the third series.
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a"), point("b"), point("c"), invalid})
+	multiErr, ok := err.(*tsdb.MultiWriteError)
+	if !ok {
+		t.Fatal("expected multi write error. got:", err)
+	}
+	var (
+		partialErr tsdb.PartialWriteError
+		limitErr   *tsdb.SeriesLimitError
+	)
+	for _, err := range multiErr.Errs {
+		switch err := err.(type) {
+		case tsdb.PartialWriteError:
+			partialErr = err
+		case *tsdb.SeriesLimitError:
+			limitErr = err
+		}
+	}
+	if got, exp := partialErr.Dropped, 1; got != exp {
+		t.Fatalf("got %d invalid points, expected %d", got, exp)
+	}
+	if limitErr == nil || len(limitErr.SeriesKeys) != 1 {
+		t.Fatal("expected one series dropped by the series limit. got:", err)
+	}
+
+	// the series created by the partial write are counted.
+	n, err := engine.BucketSeriesCardinality(context.TODO(), engine.org, engine.bucket)
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatalf("got %d series, expected 2", n)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("c")}); !errors.Is(err, tsdb.ErrSeriesLimitExceeded) {
+		t.Fatal("expected series limit error. got:", err)
+	}
+}
+
 // BenchmarkWritePoints_100K demonstrates the impact that batch size has on
 // writing a fixed number of points into storage. In this case 100K points are
 // written according to varying batch sizes.
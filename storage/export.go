@@ -0,0 +1,355 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/multierr"
+)
+
+var _ influxdb.ExportService = (*Engine)(nil)
+
+// exportCheckInterval is the number of series keys exported between checks
+// for the cancellation of an export.
+const exportCheckInterval = 1000
+
+// ExportLineProtocol writes the points of the bucket of req to w as line
+// protocol. The points are read from the TSM files and the cache of the
+// engine, and the points deleted from either are not exported.
+//
+// Writes and deletes are not blocked by an export, so the points written or
+// deleted while the bucket is exported may or may not be exported.
+func (e *Engine) ExportLineProtocol(ctx context.Context, req influxdb.ExportRequest, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Valid(); err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	ex := newLineProtocolExporter(req)
+
+	// the cache is copied before the files are referenced, so that the points
+	// snapshotted from the cache in between are read from their new file.
+	for _, key := range e.engine.Cache.Keys() {
+		if bytes.HasPrefix(key, ex.prefix) {
+			ex.addCacheValues(key, e.engine.Cache.Values(key))
+		}
+	}
+
+	var (
+		files []tsm1.TSMFile
+		err   error
+	)
+	e.engine.FileStore.ForEachFile(func(f tsm1.TSMFile) bool {
+		r, ok := f.(exportFile)
+		if !ok {
+			err = fmt.Errorf("TSM file %s can not be exported", f.Path())
+			return false
+		}
+		f.Ref()
+		files = append(files, f)
+		ex.files = append(ex.files, r)
+		return true
+	})
+	defer func() {
+		for _, f := range files {
+			f.Unref()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	return ex.export(ctx, w)
+}
+
+// ExportLineProtocolFiles writes the points of the bucket of req to w as
+// line protocol, like ExportLineProtocol, reading them from the TSM files in
+// dataDir and the WAL segments in walDir of an engine which is not running.
+//
+// The deletes in the WAL are applied to the points of the TSM files as well,
+// just as they are once the engine replays the WAL.
+func ExportLineProtocolFiles(ctx context.Context, dataDir, walDir string, req influxdb.ExportRequest, w io.Writer) error {
+	if err := req.Valid(); err != nil {
+		return err
+	}
+
+	ex := newLineProtocolExporter(req)
+
+	paths, err := filepath.Glob(filepath.Join(dataDir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	// the files are read in the order of their generations, so that the
+	// points of newer files take precedence.
+	gens := make(map[string][2]int, len(paths))
+	for _, path := range paths {
+		gen, seq, err := tsm1.DefaultParseFileName(path)
+		if err != nil {
+			return err
+		}
+		gens[path] = [2]int{gen, seq}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		a, b := gens[paths[i]], gens[paths[j]]
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+
+	var readers []*tsm1.TSMReader
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		r, err := tsm1.NewTSMReader(f)
+		if err != nil {
+			return multierr.Append(fmt.Errorf("failed to read TSM file %s: %v", path, err), f.Close())
+		}
+		readers = append(readers, r)
+		ex.files = append(ex.files, r)
+	}
+
+	if err := ex.loadWAL(walDir); err != nil {
+		return err
+	}
+
+	return ex.export(ctx, w)
+}
+
+// exportFile is a TSM file the points of a bucket are exported from.
+type exportFile interface {
+	Iterator(key []byte) tsm1.TSMIterator
+	ReadAll(key []byte) ([]tsm1.Value, error)
+}
+
+// exportDelete is a delete of the WAL applied to the points of the TSM files.
+type exportDelete struct {
+	min, max int64
+	pred     tsm1.Predicate
+}
+
+// lineProtocolExporter merges the series keys of the TSM files and the cache
+// of a bucket in order, and writes the points of every series key as line
+// protocol.
+type lineProtocolExporter struct {
+	req    influxdb.ExportRequest
+	prefix []byte
+
+	// files are ordered from the oldest to the newest file.
+	files     []exportFile
+	cacheKeys [][]byte
+	cache     map[string]tsm1.Values
+	deletes   []exportDelete
+
+	buf []byte
+}
+
+func newLineProtocolExporter(req influxdb.ExportRequest) *lineProtocolExporter {
+	// composite keys are the escaped series key, followed by the field key.
+	encoded := tsdb.EncodeName(req.OrgID, req.BucketID)
+	return &lineProtocolExporter{
+		req:    req,
+		prefix: append(models.EscapeMeasurement(encoded[:]), ','),
+		cache:  make(map[string]tsm1.Values),
+	}
+}
+
+func (ex *lineProtocolExporter) addCacheValues(key []byte, values tsm1.Values) {
+	if len(values) == 0 {
+		return
+	}
+	if _, ok := ex.cache[string(key)]; !ok {
+		ex.cacheKeys = append(ex.cacheKeys, key)
+	}
+	ex.cache[string(key)] = values
+}
+
+// loadWAL replays the WAL segments in dir into the cache of the exporter, and
+// records the deletes of the bucket for the points of the TSM files. A
+// missing WAL is empty.
+func (ex *lineProtocolExporter) loadWAL(dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	segments, err := wal.SegmentFileNames(dir)
+	if err != nil {
+		return err
+	}
+
+	cache := tsm1.NewCache(0)
+	name := string(ex.prefix[:len(ex.prefix)-1])
+	err = wal.NewWALReader(segments).Read(func(entry wal.WALEntry) error {
+		switch en := entry.(type) {
+		case *wal.WriteWALEntry:
+			values := make(map[string][]tsm1.Value)
+			for key, vs := range en.Values {
+				if bytes.HasPrefix([]byte(key), ex.prefix) {
+					values[key] = vs
+				}
+			}
+			return cache.WriteMulti(values)
+
+		case *wal.DeleteBucketRangeWALEntry:
+			if en.OrgID != ex.req.OrgID || en.BucketID != ex.req.BucketID {
+				return nil
+			}
+			pred, err := tsm1.UnmarshalPredicate(en.Predicate)
+			if err != nil {
+				return err
+			}
+			cache.DeleteBucketRange(context.Background(), name, en.Min, en.Max, pred)
+			ex.deletes = append(ex.deletes, exportDelete{min: en.Min, max: en.Max, pred: pred})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range cache.Keys() {
+		ex.addCacheValues(key, cache.Values(key))
+	}
+	return nil
+}
+
+func (ex *lineProtocolExporter) export(ctx context.Context, w io.Writer) error {
+	sort.Slice(ex.cacheKeys, func(i, j int) bool {
+		return bytes.Compare(ex.cacheKeys[i], ex.cacheKeys[j]) < 0
+	})
+
+	iters := make([]tsm1.TSMIterator, len(ex.files))
+	heads := make([][]byte, len(ex.files))
+	next := func(i int) error {
+		heads[i] = nil
+		if iters[i].Next() {
+			if key := iters[i].Key(); bytes.HasPrefix(key, ex.prefix) {
+				heads[i] = append([]byte(nil), key...)
+				return nil
+			}
+		}
+		return iters[i].Err()
+	}
+	for i, f := range ex.files {
+		iters[i] = f.Iterator(ex.prefix)
+		if err := next(i); err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for n := 0; ; n++ {
+		if n%exportCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		// the smallest key of any file or the cache is exported next.
+		var key []byte
+		for _, head := range heads {
+			if head != nil && (key == nil || bytes.Compare(head, key) < 0) {
+				key = head
+			}
+		}
+		if len(ex.cacheKeys) > 0 && (key == nil || bytes.Compare(ex.cacheKeys[0], key) < 0) {
+			key = ex.cacheKeys[0]
+		}
+		if key == nil {
+			break
+		}
+
+		var values tsm1.Values
+		for i, head := range heads {
+			if !bytes.Equal(head, key) {
+				continue
+			}
+			if ex.exported(key) {
+				vs, err := ex.files[i].ReadAll(key)
+				if err != nil {
+					return err
+				}
+				values = values.Merge(vs)
+			}
+			if err := next(i); err != nil {
+				return err
+			}
+		}
+		for _, d := range ex.deletes {
+			if d.pred == nil || d.pred.Matches(key) {
+				values = values.Exclude(d.min, d.max)
+			}
+		}
+		if len(ex.cacheKeys) > 0 && bytes.Equal(ex.cacheKeys[0], key) {
+			values = values.Merge(ex.cache[string(key)])
+			delete(ex.cache, string(key))
+			ex.cacheKeys = ex.cacheKeys[1:]
+		}
+
+		if !ex.exported(key) {
+			continue
+		}
+		if err := ex.write(bw, key, values.Include(ex.req.Start, ex.req.Stop)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// exported returns true if the points of the composite key are exported.
+func (ex *lineProtocolExporter) exported(key []byte) bool {
+	if ex.req.Measurement == "" {
+		return true
+	}
+	seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+	_, tags := models.ParseKeyBytes(seriesKey)
+	return string(tags.Get(models.MeasurementTagKeyBytes)) == ex.req.Measurement
+}
+
+func (ex *lineProtocolExporter) write(w io.Writer, key []byte, values tsm1.Values) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	_, tags := models.ParseKeyBytes(seriesKey)
+	measurement := string(tags.Get(models.MeasurementTagKeyBytes))
+	tags.Delete(models.MeasurementTagKeyBytes)
+	tags.Delete(models.FieldKeyTagKeyBytes)
+
+	for _, v := range values {
+		pt, err := models.NewPoint(measurement, tags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+		if err != nil {
+			return err
+		}
+		ex.buf = append(pt.AppendString(ex.buf[:0]), '\n')
+		if _, err := w.Write(ex.buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestEngine_ExportLineProtocol(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(name, measurement, host, field string, v interface{}, ts int64) models.Point {
+		return models.MustNewPoint(name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: field, models.MeasurementTagKey: measurement, "host": host}),
+			map[string]interface{}{field: v},
+			time.Unix(0, ts))
+	}
+
+	err := engine.Engine.WritePoints(ctx, []models.Point{
+		point(name, "cpu", "a", "value", 1.0, 1),
+		point(name, "cpu", "a", "value", 2.0, 2),
+		point(name, "mem", "b", "count", int64(3), 3),
+		// another bucket of the same org is not exported.
+		point(tsdb.EncodeNameString(engine.org, influxdb.ID(0x3333333333333333)), "cpu", "z", "value", 1.0, 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the backup writes the cache to TSM files, so the points below are
+	// merged from the TSM files and the cache.
+	if _, _, err := engine.CreateBackup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	err = engine.Engine.WritePoints(ctx, []models.Point{
+		point(name, "cpu", "a", "value", 20.0, 2),
+		point(name, "cpu", "a", "value", 4.0, 4),
+		point(name, "disk", "c", "free", "x y", 5),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.DeleteBucketRange(ctx, engine.org, engine.bucket, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.WritePoints(ctx, []models.Point{point(name, "mem", "b", "count", int64(10), 1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	all := `cpu,host=a value=20 2
+cpu,host=a value=4 4
+disk,host=c free="x y" 5
+mem,host=b count=10i 1
+mem,host=b count=3i 3
+`
+
+	tests := []struct {
+		name string
+		req  func(req *influxdb.ExportRequest)
+		exp  string
+	}{
+		{
+			name: "all points",
+			req:  func(req *influxdb.ExportRequest) {},
+			exp:  all,
+		},
+		{
+			name: "time range",
+			req: func(req *influxdb.ExportRequest) {
+				req.Start, req.Stop = 2, 3
+			},
+			exp: "cpu,host=a value=20 2\nmem,host=b count=3i 3\n",
+		},
+		{
+			name: "measurement",
+			req: func(req *influxdb.ExportRequest) {
+				req.Measurement = "mem"
+			},
+			exp: "mem,host=b count=10i 1\nmem,host=b count=3i 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := influxdb.NewExportRequest(engine.org, engine.bucket)
+			tt.req(&req)
+
+			var buf bytes.Buffer
+			if err := engine.ExportLineProtocol(ctx, req, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.exp {
+				t.Errorf("unexpected line protocol:\ngot:\n%s\nexp:\n%s", got, tt.exp)
+			}
+		})
+	}
+
+	t.Run("invalid time range", func(t *testing.T) {
+		req := influxdb.NewExportRequest(engine.org, engine.bucket)
+		req.Start, req.Stop = 2, 1
+		if err := engine.ExportLineProtocol(ctx, req, &bytes.Buffer{}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+
+	t.Run("files of a closed engine", func(t *testing.T) {
+		if err := engine.Engine.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		req := influxdb.NewExportRequest(engine.org, engine.bucket)
+		dataDir := filepath.Join(engine.path, storage.DefaultEngineDirectoryName)
+		walDir := filepath.Join(engine.path, storage.DefaultWALDirectoryName)
+		if err := storage.ExportLineProtocolFiles(ctx, dataDir, walDir, req, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != all {
+			t.Errorf("unexpected line protocol:\ngot:\n%s\nexp:\n%s", got, all)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// PointBatcher accepts points and groups them into batches. A batch is emitted
+// when it holds size points, when duration has passed since the first point of
+// the batch was received, or when the batcher is flushed.
+type PointBatcher struct {
+	size     int
+	duration time.Duration
+
+	stop  chan struct{}
+	in    chan models.Point
+	out   chan []models.Point
+	flush chan struct{}
+
+	wg *sync.WaitGroup
+}
+
+// NewPointBatcher returns a new PointBatcher. size is the maximum number of
+// points in a batch, bp is the number of points which can be buffered before
+// the batcher blocks its callers and d is the maximum time a point waits in a
+// partial batch. A size of zero disables batching by size and a duration of
+// zero disables batching by time.
+func NewPointBatcher(size int, bp int, d time.Duration) *PointBatcher {
+	return &PointBatcher{
+		size:     size,
+		duration: d,
+		stop:     make(chan struct{}),
+		in:       make(chan models.Point, bp),
+		out:      make(chan []models.Point),
+		flush:    make(chan struct{}),
+	}
+}
+
+// Start starts the batching process. Start has no effect on a batcher that is
+// already running.
+func (b *PointBatcher) Start() {
+	if b.wg != nil {
+		return
+	}
+
+	var timer *time.Timer
+	var batch []models.Point
+	var timerCh <-chan time.Time
+
+	emit := func() {
+		timerCh = nil
+		if timer != nil {
+			timer.Stop()
+		}
+		if len(batch) == 0 {
+			return
+		}
+		b.out <- batch
+		batch = nil
+	}
+	add := func(p models.Point) {
+		if batch == nil {
+			batch = make([]models.Point, 0, b.size)
+			if b.duration > 0 {
+				timer = time.NewTimer(b.duration)
+				timerCh = timer.C
+			}
+		}
+
+		batch = append(batch, p)
+		if b.size > 0 && len(batch) >= b.size {
+			emit()
+		}
+	}
+
+	b.wg = &sync.WaitGroup{}
+	b.wg.Add(1)
+
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case <-b.stop:
+				// the points buffered in the channel are emitted as well.
+				for {
+					select {
+					case p := <-b.in:
+						add(p)
+						continue
+					default:
+					}
+					break
+				}
+				emit()
+				return
+			case p := <-b.in:
+				add(p)
+			case <-b.flush:
+				emit()
+			case <-timerCh:
+				emit()
+			}
+		}
+	}()
+}
+
+// Stop stops the batching process and emits the points buffered in In along
+// with any partial batch. The caller must keep reading from Out until Stop
+// returns.
+func (b *PointBatcher) Stop() {
+	if b.wg == nil {
+		return
+	}
+
+	close(b.stop)
+	b.wg.Wait()
+}
+
+// In returns the channel to which points should be written.
+func (b *PointBatcher) In() chan<- models.Point {
+	return b.in
+}
+
+// Out returns the channel from which batches should be read.
+func (b *PointBatcher) Out() <-chan []models.Point {
+	return b.out
+}
+
+// Flush instructs the batcher to emit any pending points in a batch, regardless
+// of batch size or duration.
+func (b *PointBatcher) Flush() {
+	b.flush <- struct{}{}
+}
@@ -0,0 +1,105 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+)
+
+func TestPointBatcher(t *testing.T) {
+	pt := models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, time.Unix(0, 0))
+
+	t.Run("emits a batch when it is full", func(t *testing.T) {
+		b := storage.NewPointBatcher(2, 0, time.Hour)
+		b.Start()
+
+		sent := make(chan struct{})
+		go func() {
+			defer close(sent)
+			for i := 0; i < 3; i++ {
+				b.In() <- pt
+			}
+		}()
+
+		select {
+		case batch := <-b.Out():
+			if got, want := len(batch), 2; got != want {
+				t.Errorf("unexpected batch size; got %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a batch")
+		}
+
+		// the remaining point is emitted when the batcher is stopped.
+		<-sent
+		go b.Stop()
+		if got, want := len(<-b.Out()), 1; got != want {
+			t.Errorf("unexpected batch size; got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("emits the buffered points when it is stopped", func(t *testing.T) {
+		b := storage.NewPointBatcher(2, 10, time.Hour)
+		b.Start()
+
+		for i := 0; i < 5; i++ {
+			b.In() <- pt
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			b.Stop()
+		}()
+
+		var n int
+		for {
+			select {
+			case batch := <-b.Out():
+				n += len(batch)
+				continue
+			case <-done:
+			}
+			break
+		}
+		if got, want := n, 5; got != want {
+			t.Errorf("unexpected number of points emitted; got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("emits a partial batch after the duration", func(t *testing.T) {
+		b := storage.NewPointBatcher(100, 0, 10*time.Millisecond)
+		b.Start()
+		defer b.Stop()
+
+		b.In() <- pt
+		select {
+		case batch := <-b.Out():
+			if got, want := len(batch), 1; got != want {
+				t.Errorf("unexpected batch size; got %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a batch")
+		}
+	})
+
+	t.Run("emits a partial batch when flushed", func(t *testing.T) {
+		b := storage.NewPointBatcher(100, 0, time.Hour)
+		b.Start()
+		defer b.Stop()
+
+		b.In() <- pt
+		b.In() <- pt
+		go b.Flush()
+		select {
+		case batch := <-b.Out():
+			if got, want := len(batch), 2; got != want {
+				t.Errorf("unexpected batch size; got %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a batch")
+		}
+	})
+}
@@ -8,6 +8,8 @@ package reads
 
 import (
 	"errors"
+	"math"
+	"sort"
 
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
@@ -209,6 +211,269 @@ func (c floatArraySumCursor) Next() *cursors.FloatArray {
 	}
 }
 
+type floatArrayMinCursor struct {
+	cursors.FloatArrayCursor
+	res *cursors.FloatArray
+}
+
+func newFloatArrayMinCursor(cur cursors.FloatArrayCursor) *floatArrayMinCursor {
+	return &floatArrayMinCursor{
+		FloatArrayCursor: cur,
+		res:              cursors.NewFloatArrayLen(1),
+	}
+}
+
+func (c *floatArrayMinCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatArrayMinCursor) Next() *cursors.FloatArray {
+	a := c.FloatArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts, min := a.Timestamps[0], a.Values[0]
+	for {
+		for i, v := range a.Values {
+			if v < min {
+				ts, min = a.Timestamps[i], v
+			}
+		}
+		a = c.FloatArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.res.Timestamps[0] = ts
+			c.res.Values[0] = min
+			return c.res
+		}
+	}
+}
+
+type floatArrayMaxCursor struct {
+	cursors.FloatArrayCursor
+	res *cursors.FloatArray
+}
+
+func newFloatArrayMaxCursor(cur cursors.FloatArrayCursor) *floatArrayMaxCursor {
+	return &floatArrayMaxCursor{
+		FloatArrayCursor: cur,
+		res:              cursors.NewFloatArrayLen(1),
+	}
+}
+
+func (c *floatArrayMaxCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatArrayMaxCursor) Next() *cursors.FloatArray {
+	a := c.FloatArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts, max := a.Timestamps[0], a.Values[0]
+	for {
+		for i, v := range a.Values {
+			if v > max {
+				ts, max = a.Timestamps[i], v
+			}
+		}
+		a = c.FloatArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.res.Timestamps[0] = ts
+			c.res.Values[0] = max
+			return c.res
+		}
+	}
+}
+
+type floatFloatMeanArrayCursor struct {
+	cursors.FloatArrayCursor
+}
+
+func (c *floatFloatMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatFloatMeanArrayCursor) Next() *cursors.FloatArray {
+	a := c.FloatArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return &cursors.FloatArray{}
+	}
+
+	ts := a.Timestamps[0]
+	var sum float64
+	var count int64
+	for {
+		for _, v := range a.Values {
+			sum += float64(v)
+		}
+		count += int64(len(a.Timestamps))
+		a = c.FloatArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			res := cursors.NewFloatArrayLen(1)
+			res.Timestamps[0] = ts
+			res.Values[0] = sum / float64(count)
+			return res
+		}
+	}
+}
+
+// floatArrayFirstCursor returns the first point read from the underlying cursor,
+// without reading the remaining points.
+type floatArrayFirstCursor struct {
+	cursors.FloatArrayCursor
+	res  *cursors.FloatArray
+	done bool
+}
+
+func newFloatArrayFirstCursor(cur cursors.FloatArrayCursor) *floatArrayFirstCursor {
+	return &floatArrayFirstCursor{
+		FloatArrayCursor: cur,
+		res:              cursors.NewFloatArrayLen(1),
+	}
+}
+
+func (c *floatArrayFirstCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatArrayFirstCursor) Next() *cursors.FloatArray {
+	if c.done {
+		return &cursors.FloatArray{}
+	}
+	c.done = true
+
+	a := c.FloatArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+	c.res.Timestamps[0] = a.Timestamps[0]
+	c.res.Values[0] = a.Values[0]
+	return c.res
+}
+
+// floatArrayLastCursor returns the last point read from the underlying cursor.
+type floatArrayLastCursor struct {
+	cursors.FloatArrayCursor
+	res *cursors.FloatArray
+}
+
+func newFloatArrayLastCursor(cur cursors.FloatArrayCursor) *floatArrayLastCursor {
+	return &floatArrayLastCursor{
+		FloatArrayCursor: cur,
+		res:              cursors.NewFloatArrayLen(1),
+	}
+}
+
+func (c *floatArrayLastCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatArrayLastCursor) Next() *cursors.FloatArray {
+	a := c.FloatArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	for {
+		n := len(a.Timestamps) - 1
+		c.res.Timestamps[0] = a.Timestamps[n]
+		c.res.Values[0] = a.Values[n]
+		a = c.FloatArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			return c.res
+		}
+	}
+}
+
+// floatWindowArrayCursor reads the points of the underlying cursor one window
+// at a time. Next returns the points of the current window, and nextWindow
+// advances to the window of the next point which has not been read.
+type floatWindowArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   window
+	end int64
+	tmp *cursors.FloatArray
+	res *cursors.FloatArray
+}
+
+func newFloatWindowArrayCursor(w window, cur cursors.FloatArrayCursor) *floatWindowArrayCursor {
+	return &floatWindowArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		end:              math.MinInt64,
+		tmp:              &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowArrayCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatWindowArrayCursor) nextWindow() bool {
+	for {
+		// Skip the points of the current window which have not been read.
+		i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+		c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+		if len(c.tmp.Timestamps) == 0 {
+			a := c.FloatArrayCursor.Next()
+			if len(a.Timestamps) == 0 {
+				return false
+			}
+			c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+			continue
+		}
+
+		var ok bool
+		if c.end, ok = c.w.end(c.tmp.Timestamps[0]); ok {
+			return true
+		}
+	}
+}
+
+func (c *floatWindowArrayCursor) Next() *cursors.FloatArray {
+	if len(c.tmp.Timestamps) == 0 {
+		a := c.FloatArrayCursor.Next()
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+	}
+	i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+	c.res.Timestamps, c.res.Values = c.tmp.Timestamps[:i], c.tmp.Values[:i]
+	c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+	return c.res
+}
+
+// floatWindowAggregateArrayCursor returns a point with the aggregate of each window
+// of a series, which is the first point returned by the aggregate cursor
+// of the window.
+type floatWindowAggregateArrayCursor struct {
+	cursors.FloatArrayCursor
+	windows windowCursor
+	res     *cursors.FloatArray
+}
+
+func newFloatWindowAggregateArrayCursor(windows windowCursor, agg cursors.FloatArrayCursor) *floatWindowAggregateArrayCursor {
+	return &floatWindowAggregateArrayCursor{
+		FloatArrayCursor: agg,
+		windows:          windows,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *floatWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowAggregateArrayCursor) Next() *cursors.FloatArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	for pos < MaxPointsPerBlock && c.windows.nextWindow() {
+		a := c.FloatArrayCursor.Next()
+		if len(a.Timestamps) > 0 {
+			c.res.Timestamps[pos] = a.Timestamps[0]
+			c.res.Values[pos] = a.Values[0]
+			pos++
+		}
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+	return c.res
+}
+
 type integerFloatCountArrayCursor struct {
 	cursors.FloatArrayCursor
 }
@@ -438,6 +703,269 @@ func (c integerArraySumCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+type integerArrayMinCursor struct {
+	cursors.IntegerArrayCursor
+	res *cursors.IntegerArray
+}
+
+func newIntegerArrayMinCursor(cur cursors.IntegerArrayCursor) *integerArrayMinCursor {
+	return &integerArrayMinCursor{
+		IntegerArrayCursor: cur,
+		res:                cursors.NewIntegerArrayLen(1),
+	}
+}
+
+func (c *integerArrayMinCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
+
+func (c *integerArrayMinCursor) Next() *cursors.IntegerArray {
+	a := c.IntegerArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts, min := a.Timestamps[0], a.Values[0]
+	for {
+		for i, v := range a.Values {
+			if v < min {
+				ts, min = a.Timestamps[i], v
+			}
+		}
+		a = c.IntegerArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.res.Timestamps[0] = ts
+			c.res.Values[0] = min
+			return c.res
+		}
+	}
+}
+
+type integerArrayMaxCursor struct {
+	cursors.IntegerArrayCursor
+	res *cursors.IntegerArray
+}
+
+func newIntegerArrayMaxCursor(cur cursors.IntegerArrayCursor) *integerArrayMaxCursor {
+	return &integerArrayMaxCursor{
+		IntegerArrayCursor: cur,
+		res:                cursors.NewIntegerArrayLen(1),
+	}
+}
+
+func (c *integerArrayMaxCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
+
+func (c *integerArrayMaxCursor) Next() *cursors.IntegerArray {
+	a := c.IntegerArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts, max := a.Timestamps[0], a.Values[0]
+	for {
+		for i, v := range a.Values {
+			if v > max {
+				ts, max = a.Timestamps[i], v
+			}
+		}
+		a = c.IntegerArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.res.Timestamps[0] = ts
+			c.res.Values[0] = max
+			return c.res
+		}
+	}
+}
+
+type floatIntegerMeanArrayCursor struct {
+	cursors.IntegerArrayCursor
+}
+
+func (c *floatIntegerMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *floatIntegerMeanArrayCursor) Next() *cursors.FloatArray {
+	a := c.IntegerArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return &cursors.FloatArray{}
+	}
+
+	ts := a.Timestamps[0]
+	var sum float64
+	var count int64
+	for {
+		for _, v := range a.Values {
+			sum += float64(v)
+		}
+		count += int64(len(a.Timestamps))
+		a = c.IntegerArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			res := cursors.NewFloatArrayLen(1)
+			res.Timestamps[0] = ts
+			res.Values[0] = sum / float64(count)
+			return res
+		}
+	}
+}
+
+// integerArrayFirstCursor returns the first point read from the underlying cursor,
+// without reading the remaining points.
+type integerArrayFirstCursor struct {
+	cursors.IntegerArrayCursor
+	res  *cursors.IntegerArray
+	done bool
+}
+
+func newIntegerArrayFirstCursor(cur cursors.IntegerArrayCursor) *integerArrayFirstCursor {
+	return &integerArrayFirstCursor{
+		IntegerArrayCursor: cur,
+		res:                cursors.NewIntegerArrayLen(1),
+	}
+}
+
+func (c *integerArrayFirstCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
+
+func (c *integerArrayFirstCursor) Next() *cursors.IntegerArray {
+	if c.done {
+		return &cursors.IntegerArray{}
+	}
+	c.done = true
+
+	a := c.IntegerArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+	c.res.Timestamps[0] = a.Timestamps[0]
+	c.res.Values[0] = a.Values[0]
+	return c.res
+}
+
+// integerArrayLastCursor returns the last point read from the underlying cursor.
+type integerArrayLastCursor struct {
+	cursors.IntegerArrayCursor
+	res *cursors.IntegerArray
+}
+
+func newIntegerArrayLastCursor(cur cursors.IntegerArrayCursor) *integerArrayLastCursor {
+	return &integerArrayLastCursor{
+		IntegerArrayCursor: cur,
+		res:                cursors.NewIntegerArrayLen(1),
+	}
+}
+
+func (c *integerArrayLastCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
+
+func (c *integerArrayLastCursor) Next() *cursors.IntegerArray {
+	a := c.IntegerArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	for {
+		n := len(a.Timestamps) - 1
+		c.res.Timestamps[0] = a.Timestamps[n]
+		c.res.Values[0] = a.Values[n]
+		a = c.IntegerArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			return c.res
+		}
+	}
+}
+
+// integerWindowArrayCursor reads the points of the underlying cursor one window
+// at a time. Next returns the points of the current window, and nextWindow
+// advances to the window of the next point which has not been read.
+type integerWindowArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   window
+	end int64
+	tmp *cursors.IntegerArray
+	res *cursors.IntegerArray
+}
+
+func newIntegerWindowArrayCursor(w window, cur cursors.IntegerArrayCursor) *integerWindowArrayCursor {
+	return &integerWindowArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		end:                math.MinInt64,
+		tmp:                &cursors.IntegerArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowArrayCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
+
+func (c *integerWindowArrayCursor) nextWindow() bool {
+	for {
+		// Skip the points of the current window which have not been read.
+		i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+		c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+		if len(c.tmp.Timestamps) == 0 {
+			a := c.IntegerArrayCursor.Next()
+			if len(a.Timestamps) == 0 {
+				return false
+			}
+			c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+			continue
+		}
+
+		var ok bool
+		if c.end, ok = c.w.end(c.tmp.Timestamps[0]); ok {
+			return true
+		}
+	}
+}
+
+func (c *integerWindowArrayCursor) Next() *cursors.IntegerArray {
+	if len(c.tmp.Timestamps) == 0 {
+		a := c.IntegerArrayCursor.Next()
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+	}
+	i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+	c.res.Timestamps, c.res.Values = c.tmp.Timestamps[:i], c.tmp.Values[:i]
+	c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+	return c.res
+}
+
+// integerWindowAggregateArrayCursor returns a point with the aggregate of each window
+// of a series, which is the first point returned by the aggregate cursor
+// of the window.
+type integerWindowAggregateArrayCursor struct {
+	cursors.IntegerArrayCursor
+	windows windowCursor
+	res     *cursors.IntegerArray
+}
+
+func newIntegerWindowAggregateArrayCursor(windows windowCursor, agg cursors.IntegerArrayCursor) *integerWindowAggregateArrayCursor {
+	return &integerWindowAggregateArrayCursor{
+		IntegerArrayCursor: agg,
+		windows:            windows,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *integerWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowAggregateArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	for pos < MaxPointsPerBlock && c.windows.nextWindow() {
+		a := c.IntegerArrayCursor.Next()
+		if len(a.Timestamps) > 0 {
+			c.res.Timestamps[pos] = a.Timestamps[0]
+			c.res.Values[pos] = a.Values[0]
+			pos++
+		}
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+	return c.res
+}
+
 type integerIntegerCountArrayCursor struct {
 	cursors.IntegerArrayCursor
 }
@@ -590,83 +1118,346 @@ func (c *unsignedMultiShardArrayCursor) Next() *cursors.UnsignedArray {
 	}
 }
 
-func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
-	if len(c.itrs) == 0 {
-		return false
+func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
+	if len(c.itrs) == 0 {
+		return false
+	}
+
+	c.UnsignedArrayCursor.Close()
+
+	var itr cursors.CursorIterator
+	var cur cursors.Cursor
+	for cur == nil && len(c.itrs) > 0 {
+		itr, c.itrs = c.itrs[0], c.itrs[1:]
+		cur, _ = itr.Next(c.ctx, c.req)
+	}
+
+	var ok bool
+	if cur != nil {
+		var next cursors.UnsignedArrayCursor
+		next, ok = cur.(cursors.UnsignedArrayCursor)
+		if !ok {
+			cur.Close()
+			next = UnsignedEmptyArrayCursor
+			c.itrs = nil
+			c.err = errors.New("expected unsigned cursor")
+		} else {
+			if c.filter != nil {
+				c.filter.reset(next)
+				next = c.filter
+			}
+		}
+		c.UnsignedArrayCursor = next
+	} else {
+		c.UnsignedArrayCursor = UnsignedEmptyArrayCursor
+	}
+
+	return ok
+}
+
+type unsignedArraySumCursor struct {
+	cursors.UnsignedArrayCursor
+	ts  [1]int64
+	vs  [1]uint64
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedArraySumCursor(cur cursors.UnsignedArrayCursor) *unsignedArraySumCursor {
+	return &unsignedArraySumCursor{
+		UnsignedArrayCursor: cur,
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c unsignedArraySumCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+
+func (c unsignedArraySumCursor) Next() *cursors.UnsignedArray {
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts := a.Timestamps[0]
+	var acc uint64
+
+	for {
+		for _, v := range a.Values {
+			acc += v
+		}
+		a = c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.ts[0] = ts
+			c.vs[0] = acc
+			c.res.Timestamps = c.ts[:]
+			c.res.Values = c.vs[:]
+			return c.res
+		}
+	}
+}
+
+type unsignedArrayMinCursor struct {
+	cursors.UnsignedArrayCursor
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedArrayMinCursor(cur cursors.UnsignedArrayCursor) *unsignedArrayMinCursor {
+	return &unsignedArrayMinCursor{
+		UnsignedArrayCursor: cur,
+		res:                 cursors.NewUnsignedArrayLen(1),
+	}
+}
+
+func (c *unsignedArrayMinCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+
+func (c *unsignedArrayMinCursor) Next() *cursors.UnsignedArray {
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts, min := a.Timestamps[0], a.Values[0]
+	for {
+		for i, v := range a.Values {
+			if v < min {
+				ts, min = a.Timestamps[i], v
+			}
+		}
+		a = c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.res.Timestamps[0] = ts
+			c.res.Values[0] = min
+			return c.res
+		}
+	}
+}
+
+type unsignedArrayMaxCursor struct {
+	cursors.UnsignedArrayCursor
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedArrayMaxCursor(cur cursors.UnsignedArrayCursor) *unsignedArrayMaxCursor {
+	return &unsignedArrayMaxCursor{
+		UnsignedArrayCursor: cur,
+		res:                 cursors.NewUnsignedArrayLen(1),
+	}
+}
+
+func (c *unsignedArrayMaxCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+
+func (c *unsignedArrayMaxCursor) Next() *cursors.UnsignedArray {
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts, max := a.Timestamps[0], a.Values[0]
+	for {
+		for i, v := range a.Values {
+			if v > max {
+				ts, max = a.Timestamps[i], v
+			}
+		}
+		a = c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.res.Timestamps[0] = ts
+			c.res.Values[0] = max
+			return c.res
+		}
+	}
+}
+
+type floatUnsignedMeanArrayCursor struct {
+	cursors.UnsignedArrayCursor
+}
+
+func (c *floatUnsignedMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *floatUnsignedMeanArrayCursor) Next() *cursors.FloatArray {
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return &cursors.FloatArray{}
+	}
+
+	ts := a.Timestamps[0]
+	var sum float64
+	var count int64
+	for {
+		for _, v := range a.Values {
+			sum += float64(v)
+		}
+		count += int64(len(a.Timestamps))
+		a = c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			res := cursors.NewFloatArrayLen(1)
+			res.Timestamps[0] = ts
+			res.Values[0] = sum / float64(count)
+			return res
+		}
+	}
+}
+
+// unsignedArrayFirstCursor returns the first point read from the underlying cursor,
+// without reading the remaining points.
+type unsignedArrayFirstCursor struct {
+	cursors.UnsignedArrayCursor
+	res  *cursors.UnsignedArray
+	done bool
+}
+
+func newUnsignedArrayFirstCursor(cur cursors.UnsignedArrayCursor) *unsignedArrayFirstCursor {
+	return &unsignedArrayFirstCursor{
+		UnsignedArrayCursor: cur,
+		res:                 cursors.NewUnsignedArrayLen(1),
 	}
+}
 
-	c.UnsignedArrayCursor.Close()
+func (c *unsignedArrayFirstCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
 
-	var itr cursors.CursorIterator
-	var cur cursors.Cursor
-	for cur == nil && len(c.itrs) > 0 {
-		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+func (c *unsignedArrayFirstCursor) Next() *cursors.UnsignedArray {
+	if c.done {
+		return &cursors.UnsignedArray{}
 	}
+	c.done = true
 
-	var ok bool
-	if cur != nil {
-		var next cursors.UnsignedArrayCursor
-		next, ok = cur.(cursors.UnsignedArrayCursor)
-		if !ok {
-			cur.Close()
-			next = UnsignedEmptyArrayCursor
-			c.itrs = nil
-			c.err = errors.New("expected unsigned cursor")
-		} else {
-			if c.filter != nil {
-				c.filter.reset(next)
-				next = c.filter
-			}
-		}
-		c.UnsignedArrayCursor = next
-	} else {
-		c.UnsignedArrayCursor = UnsignedEmptyArrayCursor
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
 	}
-
-	return ok
+	c.res.Timestamps[0] = a.Timestamps[0]
+	c.res.Values[0] = a.Values[0]
+	return c.res
 }
 
-type unsignedArraySumCursor struct {
+// unsignedArrayLastCursor returns the last point read from the underlying cursor.
+type unsignedArrayLastCursor struct {
 	cursors.UnsignedArrayCursor
-	ts  [1]int64
-	vs  [1]uint64
 	res *cursors.UnsignedArray
 }
 
-func newUnsignedArraySumCursor(cur cursors.UnsignedArrayCursor) *unsignedArraySumCursor {
-	return &unsignedArraySumCursor{
+func newUnsignedArrayLastCursor(cur cursors.UnsignedArrayCursor) *unsignedArrayLastCursor {
+	return &unsignedArrayLastCursor{
 		UnsignedArrayCursor: cur,
-		res:                 &cursors.UnsignedArray{},
+		res:                 cursors.NewUnsignedArrayLen(1),
 	}
 }
 
-func (c unsignedArraySumCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+func (c *unsignedArrayLastCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
 
-func (c unsignedArraySumCursor) Next() *cursors.UnsignedArray {
+func (c *unsignedArrayLastCursor) Next() *cursors.UnsignedArray {
 	a := c.UnsignedArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return a
 	}
 
-	ts := a.Timestamps[0]
-	var acc uint64
-
 	for {
-		for _, v := range a.Values {
-			acc += v
-		}
+		n := len(a.Timestamps) - 1
+		c.res.Timestamps[0] = a.Timestamps[n]
+		c.res.Values[0] = a.Values[n]
 		a = c.UnsignedArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
-			c.ts[0] = ts
-			c.vs[0] = acc
-			c.res.Timestamps = c.ts[:]
-			c.res.Values = c.vs[:]
 			return c.res
 		}
 	}
 }
 
+// unsignedWindowArrayCursor reads the points of the underlying cursor one window
+// at a time. Next returns the points of the current window, and nextWindow
+// advances to the window of the next point which has not been read.
+type unsignedWindowArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   window
+	end int64
+	tmp *cursors.UnsignedArray
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedWindowArrayCursor(w window, cur cursors.UnsignedArrayCursor) *unsignedWindowArrayCursor {
+	return &unsignedWindowArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		end:                 math.MinInt64,
+		tmp:                 &cursors.UnsignedArray{},
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowArrayCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+
+func (c *unsignedWindowArrayCursor) nextWindow() bool {
+	for {
+		// Skip the points of the current window which have not been read.
+		i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+		c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+		if len(c.tmp.Timestamps) == 0 {
+			a := c.UnsignedArrayCursor.Next()
+			if len(a.Timestamps) == 0 {
+				return false
+			}
+			c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+			continue
+		}
+
+		var ok bool
+		if c.end, ok = c.w.end(c.tmp.Timestamps[0]); ok {
+			return true
+		}
+	}
+}
+
+func (c *unsignedWindowArrayCursor) Next() *cursors.UnsignedArray {
+	if len(c.tmp.Timestamps) == 0 {
+		a := c.UnsignedArrayCursor.Next()
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+	}
+	i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+	c.res.Timestamps, c.res.Values = c.tmp.Timestamps[:i], c.tmp.Values[:i]
+	c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+	return c.res
+}
+
+// unsignedWindowAggregateArrayCursor returns a point with the aggregate of each window
+// of a series, which is the first point returned by the aggregate cursor
+// of the window.
+type unsignedWindowAggregateArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	windows windowCursor
+	res     *cursors.UnsignedArray
+}
+
+func newUnsignedWindowAggregateArrayCursor(windows windowCursor, agg cursors.UnsignedArrayCursor) *unsignedWindowAggregateArrayCursor {
+	return &unsignedWindowAggregateArrayCursor{
+		UnsignedArrayCursor: agg,
+		windows:             windows,
+		res:                 cursors.NewUnsignedArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *unsignedWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowAggregateArrayCursor) Next() *cursors.UnsignedArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	for pos < MaxPointsPerBlock && c.windows.nextWindow() {
+		a := c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) > 0 {
+			c.res.Timestamps[pos] = a.Timestamps[0]
+			c.res.Values[pos] = a.Values[0]
+			pos++
+		}
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+	return c.res
+}
+
 type integerUnsignedCountArrayCursor struct {
 	cursors.UnsignedArrayCursor
 }
@@ -856,6 +1647,165 @@ func (c *stringMultiShardArrayCursor) nextArrayCursor() bool {
 	return ok
 }
 
+// stringArrayFirstCursor returns the first point read from the underlying cursor,
+// without reading the remaining points.
+type stringArrayFirstCursor struct {
+	cursors.StringArrayCursor
+	res  *cursors.StringArray
+	done bool
+}
+
+func newStringArrayFirstCursor(cur cursors.StringArrayCursor) *stringArrayFirstCursor {
+	return &stringArrayFirstCursor{
+		StringArrayCursor: cur,
+		res:               cursors.NewStringArrayLen(1),
+	}
+}
+
+func (c *stringArrayFirstCursor) Stats() cursors.CursorStats { return c.StringArrayCursor.Stats() }
+
+func (c *stringArrayFirstCursor) Next() *cursors.StringArray {
+	if c.done {
+		return &cursors.StringArray{}
+	}
+	c.done = true
+
+	a := c.StringArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+	c.res.Timestamps[0] = a.Timestamps[0]
+	c.res.Values[0] = a.Values[0]
+	return c.res
+}
+
+// stringArrayLastCursor returns the last point read from the underlying cursor.
+type stringArrayLastCursor struct {
+	cursors.StringArrayCursor
+	res *cursors.StringArray
+}
+
+func newStringArrayLastCursor(cur cursors.StringArrayCursor) *stringArrayLastCursor {
+	return &stringArrayLastCursor{
+		StringArrayCursor: cur,
+		res:               cursors.NewStringArrayLen(1),
+	}
+}
+
+func (c *stringArrayLastCursor) Stats() cursors.CursorStats { return c.StringArrayCursor.Stats() }
+
+func (c *stringArrayLastCursor) Next() *cursors.StringArray {
+	a := c.StringArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	for {
+		n := len(a.Timestamps) - 1
+		c.res.Timestamps[0] = a.Timestamps[n]
+		c.res.Values[0] = a.Values[n]
+		a = c.StringArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			return c.res
+		}
+	}
+}
+
+// stringWindowArrayCursor reads the points of the underlying cursor one window
+// at a time. Next returns the points of the current window, and nextWindow
+// advances to the window of the next point which has not been read.
+type stringWindowArrayCursor struct {
+	cursors.StringArrayCursor
+	w   window
+	end int64
+	tmp *cursors.StringArray
+	res *cursors.StringArray
+}
+
+func newStringWindowArrayCursor(w window, cur cursors.StringArrayCursor) *stringWindowArrayCursor {
+	return &stringWindowArrayCursor{
+		StringArrayCursor: cur,
+		w:                 w,
+		end:               math.MinInt64,
+		tmp:               &cursors.StringArray{},
+		res:               &cursors.StringArray{},
+	}
+}
+
+func (c *stringWindowArrayCursor) Stats() cursors.CursorStats { return c.StringArrayCursor.Stats() }
+
+func (c *stringWindowArrayCursor) nextWindow() bool {
+	for {
+		// Skip the points of the current window which have not been read.
+		i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+		c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+		if len(c.tmp.Timestamps) == 0 {
+			a := c.StringArrayCursor.Next()
+			if len(a.Timestamps) == 0 {
+				return false
+			}
+			c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+			continue
+		}
+
+		var ok bool
+		if c.end, ok = c.w.end(c.tmp.Timestamps[0]); ok {
+			return true
+		}
+	}
+}
+
+func (c *stringWindowArrayCursor) Next() *cursors.StringArray {
+	if len(c.tmp.Timestamps) == 0 {
+		a := c.StringArrayCursor.Next()
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+	}
+	i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+	c.res.Timestamps, c.res.Values = c.tmp.Timestamps[:i], c.tmp.Values[:i]
+	c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+	return c.res
+}
+
+// stringWindowAggregateArrayCursor returns a point with the aggregate of each window
+// of a series, which is the first point returned by the aggregate cursor
+// of the window.
+type stringWindowAggregateArrayCursor struct {
+	cursors.StringArrayCursor
+	windows windowCursor
+	res     *cursors.StringArray
+}
+
+func newStringWindowAggregateArrayCursor(windows windowCursor, agg cursors.StringArrayCursor) *stringWindowAggregateArrayCursor {
+	return &stringWindowAggregateArrayCursor{
+		StringArrayCursor: agg,
+		windows:           windows,
+		res:               cursors.NewStringArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *stringWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowAggregateArrayCursor) Next() *cursors.StringArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	for pos < MaxPointsPerBlock && c.windows.nextWindow() {
+		a := c.StringArrayCursor.Next()
+		if len(a.Timestamps) > 0 {
+			c.res.Timestamps[pos] = a.Timestamps[0]
+			c.res.Values[pos] = a.Values[0]
+			pos++
+		}
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+	return c.res
+}
+
 type integerStringCountArrayCursor struct {
 	cursors.StringArrayCursor
 }
@@ -1045,6 +1995,165 @@ func (c *booleanMultiShardArrayCursor) nextArrayCursor() bool {
 	return ok
 }
 
+// booleanArrayFirstCursor returns the first point read from the underlying cursor,
+// without reading the remaining points.
+type booleanArrayFirstCursor struct {
+	cursors.BooleanArrayCursor
+	res  *cursors.BooleanArray
+	done bool
+}
+
+func newBooleanArrayFirstCursor(cur cursors.BooleanArrayCursor) *booleanArrayFirstCursor {
+	return &booleanArrayFirstCursor{
+		BooleanArrayCursor: cur,
+		res:                cursors.NewBooleanArrayLen(1),
+	}
+}
+
+func (c *booleanArrayFirstCursor) Stats() cursors.CursorStats { return c.BooleanArrayCursor.Stats() }
+
+func (c *booleanArrayFirstCursor) Next() *cursors.BooleanArray {
+	if c.done {
+		return &cursors.BooleanArray{}
+	}
+	c.done = true
+
+	a := c.BooleanArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+	c.res.Timestamps[0] = a.Timestamps[0]
+	c.res.Values[0] = a.Values[0]
+	return c.res
+}
+
+// booleanArrayLastCursor returns the last point read from the underlying cursor.
+type booleanArrayLastCursor struct {
+	cursors.BooleanArrayCursor
+	res *cursors.BooleanArray
+}
+
+func newBooleanArrayLastCursor(cur cursors.BooleanArrayCursor) *booleanArrayLastCursor {
+	return &booleanArrayLastCursor{
+		BooleanArrayCursor: cur,
+		res:                cursors.NewBooleanArrayLen(1),
+	}
+}
+
+func (c *booleanArrayLastCursor) Stats() cursors.CursorStats { return c.BooleanArrayCursor.Stats() }
+
+func (c *booleanArrayLastCursor) Next() *cursors.BooleanArray {
+	a := c.BooleanArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	for {
+		n := len(a.Timestamps) - 1
+		c.res.Timestamps[0] = a.Timestamps[n]
+		c.res.Values[0] = a.Values[n]
+		a = c.BooleanArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			return c.res
+		}
+	}
+}
+
+// booleanWindowArrayCursor reads the points of the underlying cursor one window
+// at a time. Next returns the points of the current window, and nextWindow
+// advances to the window of the next point which has not been read.
+type booleanWindowArrayCursor struct {
+	cursors.BooleanArrayCursor
+	w   window
+	end int64
+	tmp *cursors.BooleanArray
+	res *cursors.BooleanArray
+}
+
+func newBooleanWindowArrayCursor(w window, cur cursors.BooleanArrayCursor) *booleanWindowArrayCursor {
+	return &booleanWindowArrayCursor{
+		BooleanArrayCursor: cur,
+		w:                  w,
+		end:                math.MinInt64,
+		tmp:                &cursors.BooleanArray{},
+		res:                &cursors.BooleanArray{},
+	}
+}
+
+func (c *booleanWindowArrayCursor) Stats() cursors.CursorStats { return c.BooleanArrayCursor.Stats() }
+
+func (c *booleanWindowArrayCursor) nextWindow() bool {
+	for {
+		// Skip the points of the current window which have not been read.
+		i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+		c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+		if len(c.tmp.Timestamps) == 0 {
+			a := c.BooleanArrayCursor.Next()
+			if len(a.Timestamps) == 0 {
+				return false
+			}
+			c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+			continue
+		}
+
+		var ok bool
+		if c.end, ok = c.w.end(c.tmp.Timestamps[0]); ok {
+			return true
+		}
+	}
+}
+
+func (c *booleanWindowArrayCursor) Next() *cursors.BooleanArray {
+	if len(c.tmp.Timestamps) == 0 {
+		a := c.BooleanArrayCursor.Next()
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps, a.Values
+	}
+	i := sort.Search(len(c.tmp.Timestamps), func(i int) bool { return c.tmp.Timestamps[i] >= c.end })
+	c.res.Timestamps, c.res.Values = c.tmp.Timestamps[:i], c.tmp.Values[:i]
+	c.tmp.Timestamps, c.tmp.Values = c.tmp.Timestamps[i:], c.tmp.Values[i:]
+	return c.res
+}
+
+// booleanWindowAggregateArrayCursor returns a point with the aggregate of each window
+// of a series, which is the first point returned by the aggregate cursor
+// of the window.
+type booleanWindowAggregateArrayCursor struct {
+	cursors.BooleanArrayCursor
+	windows windowCursor
+	res     *cursors.BooleanArray
+}
+
+func newBooleanWindowAggregateArrayCursor(windows windowCursor, agg cursors.BooleanArrayCursor) *booleanWindowAggregateArrayCursor {
+	return &booleanWindowAggregateArrayCursor{
+		BooleanArrayCursor: agg,
+		windows:            windows,
+		res:                cursors.NewBooleanArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *booleanWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowAggregateArrayCursor) Next() *cursors.BooleanArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	for pos < MaxPointsPerBlock && c.windows.nextWindow() {
+		a := c.BooleanArrayCursor.Next()
+		if len(a.Timestamps) > 0 {
+			c.res.Timestamps[pos] = a.Timestamps[0]
+			c.res.Values[pos] = a.Values[0]
+			pos++
+		}
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+	return c.res
+}
+
 type integerBooleanCountArrayCursor struct {
 	cursors.BooleanArrayCursor
 }
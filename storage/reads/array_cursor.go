@@ -3,6 +3,8 @@ package reads
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
@@ -26,12 +28,43 @@ func newAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, curs
 		return newSumArrayCursor(cursor)
 	case datatypes.AggregateTypeCount:
 		return newCountArrayCursor(cursor)
+	case datatypes.AggregateTypeMin:
+		return newMinArrayCursor(cursor)
+	case datatypes.AggregateTypeMax:
+		return newMaxArrayCursor(cursor)
+	case datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+		// The cursor of a last aggregate reads the series in descending
+		// order, so both return the first point read from the cursor.
+		return newFirstArrayCursor(cursor)
+	case datatypes.AggregateTypeMean:
+		return newMeanArrayCursor(cursor)
 	default:
 		// TODO(sgc): should be validated higher up
 		panic("invalid aggregate")
 	}
 }
 
+// errUnsupportedAggregate returns the error of an aggregate which is not
+// supported for the type of the values of cur, such as the mean of strings.
+func errUnsupportedAggregate(agg *datatypes.Aggregate, cur cursors.Cursor) error {
+	var typ string
+	switch cur.(type) {
+	case cursors.FloatArrayCursor:
+		typ = "float"
+	case cursors.IntegerArrayCursor:
+		typ = "integer"
+	case cursors.UnsignedArrayCursor:
+		typ = "unsigned"
+	case cursors.StringArrayCursor:
+		typ = "string"
+	case cursors.BooleanArrayCursor:
+		typ = "boolean"
+	default:
+		typ = fmt.Sprintf("%T", cur)
+	}
+	return fmt.Errorf("unsupported aggregate %s of %s values", strings.ToLower(agg.Type.String()), typ)
+}
+
 func newSumArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	switch cur := cur.(type) {
 	case cursors.FloatArrayCursor:
@@ -46,6 +79,185 @@ func newSumArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	}
 }
 
+func newMinArrayCursor(cur cursors.Cursor) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatArrayMinCursor(cur)
+	case cursors.IntegerArrayCursor:
+		return newIntegerArrayMinCursor(cur)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedArrayMinCursor(cur)
+	default:
+		return nil
+	}
+}
+
+func newMaxArrayCursor(cur cursors.Cursor) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatArrayMaxCursor(cur)
+	case cursors.IntegerArrayCursor:
+		return newIntegerArrayMaxCursor(cur)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedArrayMaxCursor(cur)
+	default:
+		return nil
+	}
+}
+
+func newMeanArrayCursor(cur cursors.Cursor) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return &floatFloatMeanArrayCursor{FloatArrayCursor: cur}
+	case cursors.IntegerArrayCursor:
+		return &floatIntegerMeanArrayCursor{IntegerArrayCursor: cur}
+	case cursors.UnsignedArrayCursor:
+		return &floatUnsignedMeanArrayCursor{UnsignedArrayCursor: cur}
+	default:
+		return nil
+	}
+}
+
+func newFirstArrayCursor(cur cursors.Cursor) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatArrayFirstCursor(cur)
+	case cursors.IntegerArrayCursor:
+		return newIntegerArrayFirstCursor(cur)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedArrayFirstCursor(cur)
+	case cursors.StringArrayCursor:
+		return newStringArrayFirstCursor(cur)
+	case cursors.BooleanArrayCursor:
+		return newBooleanArrayFirstCursor(cur)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newLastArrayCursor(cur cursors.Cursor) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatArrayLastCursor(cur)
+	case cursors.IntegerArrayCursor:
+		return newIntegerArrayLastCursor(cur)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedArrayLastCursor(cur)
+	case cursors.StringArrayCursor:
+		return newStringArrayLastCursor(cur)
+	case cursors.BooleanArrayCursor:
+		return newBooleanArrayLastCursor(cur)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+// window partitions the points of a series into the windows of a window
+// aggregate in the same way as the window function of Flux. Flux truncates
+// a time before the offset toward the zero time, so such a point is only in
+// a window when it is at the start of the window, and it is then the only
+// point of the window.
+type window struct {
+	every  int64
+	offset int64
+}
+
+// bounds returns the bounds of the window of t, and false if t is in no window.
+func (w window) bounds(t int64) (start, stop int64, ok bool) {
+	t0 := t - w.offset
+	start = t0 - t0%w.every + w.offset
+	return start, start + w.every, start <= t
+}
+
+// end returns the end of the points which are in the window of t, starting
+// from t. If t is in no window, it returns false and the time after t.
+func (w window) end(t int64) (int64, bool) {
+	_, stop, ok := w.bounds(t)
+	if !ok || t < w.offset {
+		return t + 1, ok
+	}
+	return stop, true
+}
+
+// windowCursor is a cursor which reads a series one window at a time.
+type windowCursor interface {
+	nextWindow() bool
+}
+
+// newWindowAggregateArrayCursor returns a cursor with a point for the aggregate
+// of each window of the points of cursor. The aggregate of a window is computed
+// by an aggregate cursor reading the points of the window.
+func newWindowAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, w window, cursor cursors.Cursor) cursors.Cursor {
+	if cursor == nil {
+		return nil
+	}
+
+	var wc windowCursor
+	switch cur := cursor.(type) {
+	case cursors.FloatArrayCursor:
+		c := newFloatWindowArrayCursor(w, cur)
+		wc, cursor = c, c
+	case cursors.IntegerArrayCursor:
+		c := newIntegerWindowArrayCursor(w, cur)
+		wc, cursor = c, c
+	case cursors.UnsignedArrayCursor:
+		c := newUnsignedWindowArrayCursor(w, cur)
+		wc, cursor = c, c
+	case cursors.StringArrayCursor:
+		c := newStringWindowArrayCursor(w, cur)
+		wc, cursor = c, c
+	case cursors.BooleanArrayCursor:
+		c := newBooleanWindowArrayCursor(w, cur)
+		wc, cursor = c, c
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+
+	switch agg.Type {
+	case datatypes.AggregateTypeFirst:
+		// The first point read from a window is its first point.
+	case datatypes.AggregateTypeLast:
+		cursor = newLastArrayCursor(cursor)
+	default:
+		cursor = newAggregateArrayCursor(ctx, agg, cursor)
+	}
+
+	switch cur := cursor.(type) {
+	case nil:
+		return nil
+	case cursors.FloatArrayCursor:
+		return newFloatWindowAggregateArrayCursor(wc, cur)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowAggregateArrayCursor(wc, cur)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowAggregateArrayCursor(wc, cur)
+	case cursors.StringArrayCursor:
+		return newStringWindowAggregateArrayCursor(wc, cur)
+	case cursors.BooleanArrayCursor:
+		return newBooleanWindowAggregateArrayCursor(wc, cur)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+// isAscending reports whether the series of a read with the
+// aggregate are read in ascending order. The last point is read
+// from a descending cursor, which starts from the last block of
+// the series in the index instead of decoding every block.
+func isAscending(agg *datatypes.Aggregate) bool {
+	return agg == nil || agg.Type != datatypes.AggregateTypeLast
+}
+
+// aggregateLimit returns the number of points of each series read by the
+// aggregate. The first and the last aggregates only read the first point
+// of their cursor, which the engine then locates from its index.
+func aggregateLimit(agg *datatypes.Aggregate) int64 {
+	if agg != nil && (agg.Type == datatypes.AggregateTypeFirst || agg.Type == datatypes.AggregateTypeLast) {
+		return 1
+	}
+	return math.MaxInt64
+}
+
 func newCountArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	switch cur := cur.(type) {
 	case cursors.FloatArrayCursor:
@@ -126,6 +338,22 @@ func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
 		cond = &astExpr{row.ValueCond}
 	}
 
+	// The points filtered by their values are not counted by the limit,
+	// so the cursors of the shards only read up to the limit without a filter.
+	m.req.Limit = 0
+	if cond == nil && m.limit < math.MaxInt64 {
+		m.req.Limit = m.limit
+	}
+
+	if !m.req.Ascending {
+		// Read the shards from the latest to the earliest.
+		itrs := make(cursors.CursorIterators, len(row.Query))
+		for i, itr := range row.Query {
+			itrs[len(itrs)-1-i] = itr
+		}
+		row.Query = itrs
+	}
+
 	var shard cursors.CursorIterator
 	var cur cursors.Cursor
 	for cur == nil && len(row.Query) > 0 {
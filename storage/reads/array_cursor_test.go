@@ -0,0 +1,328 @@
+package reads_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// floatBlocksCursorIterator returns cursors over the blocks of a shard,
+// in the order of the request, and counts the blocks which were read.
+// The limit of the last request is set to limit.
+type floatBlocksCursorIterator struct {
+	blocks []*cursors.FloatArray
+	read   *int
+	limit  *int64
+}
+
+func (itr *floatBlocksCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest) (cursors.Cursor, error) {
+	if itr.limit != nil {
+		*itr.limit = r.Limit
+	}
+
+	var blocks []*cursors.FloatArray
+	for _, b := range itr.blocks {
+		if !r.Ascending {
+			rb := &cursors.FloatArray{}
+			for i := b.Len() - 1; i >= 0; i-- {
+				rb.Timestamps = append(rb.Timestamps, b.Timestamps[i])
+				rb.Values = append(rb.Values, b.Values[i])
+			}
+			b = rb
+		}
+		blocks = append(blocks, b)
+	}
+	if !r.Ascending {
+		for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+			blocks[i], blocks[j] = blocks[j], blocks[i]
+		}
+	}
+	return &floatBlocksCursor{blocks: blocks, read: itr.read}, nil
+}
+
+func (itr *floatBlocksCursorIterator) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+type floatBlocksCursor struct {
+	blocks []*cursors.FloatArray
+	read   *int
+}
+
+func (c *floatBlocksCursor) Close()                     {}
+func (c *floatBlocksCursor) Err() error                 { return nil }
+func (c *floatBlocksCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *floatBlocksCursor) Next() *cursors.FloatArray {
+	if len(c.blocks) == 0 {
+		return &cursors.FloatArray{}
+	}
+	b := c.blocks[0]
+	c.blocks = c.blocks[1:]
+	*c.read++
+	return b
+}
+
+// stringCursorIterator returns a cursor over the strings of a shard.
+type stringCursorIterator struct {
+	values []string
+}
+
+func (itr *stringCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest) (cursors.Cursor, error) {
+	a := &cursors.StringArray{Values: itr.values}
+	for i := range itr.values {
+		a.Timestamps = append(a.Timestamps, int64(i+1))
+	}
+	return &stringCursor{a: a}, nil
+}
+
+func (itr *stringCursorIterator) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+type stringCursor struct {
+	a *cursors.StringArray
+}
+
+func (c *stringCursor) Close()                     {}
+func (c *stringCursor) Err() error                 { return nil }
+func (c *stringCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *stringCursor) Next() *cursors.StringArray {
+	a := c.a
+	c.a = &cursors.StringArray{}
+	return a
+}
+
+func TestNewFilteredResultSet_Aggregate(t *testing.T) {
+	type point struct {
+		T int64
+		V interface{}
+	}
+
+	tests := []struct {
+		agg   datatypes.Aggregate_AggregateType
+		exp   []point
+		read  int
+		limit int64
+	}{
+		{agg: datatypes.AggregateTypeMin, exp: []point{{T: 2, V: 1.0}}, read: 3},
+		{agg: datatypes.AggregateTypeMax, exp: []point{{T: 5, V: 5.0}}, read: 3},
+		{agg: datatypes.AggregateTypeFirst, exp: []point{{T: 1, V: 3.0}}, read: 1, limit: 1},
+		{agg: datatypes.AggregateTypeLast, exp: []point{{T: 5, V: 5.0}}, read: 1, limit: 1},
+		{agg: datatypes.AggregateTypeMean, exp: []point{{T: 1, V: 2.8}}, read: 3},
+		{agg: datatypes.AggregateTypeSum, exp: []point{{T: 1, V: 14.0}}, read: 3},
+		{agg: datatypes.AggregateTypeCount, exp: []point{{T: 1, V: int64(5)}}, read: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agg.String(), func(t *testing.T) {
+			// The points of the series are in two shards, the latter of which has two blocks.
+			var (
+				read  int
+				limit int64
+			)
+			shards := cursors.CursorIterators{
+				&floatBlocksCursorIterator{
+					blocks: []*cursors.FloatArray{
+						{Timestamps: []int64{1, 2}, Values: []float64{3, 1}},
+					},
+					read:  &read,
+					limit: &limit,
+				},
+				&floatBlocksCursorIterator{
+					blocks: []*cursors.FloatArray{
+						{Timestamps: []int64{3, 4}, Values: []float64{4, 1}},
+						{Timestamps: []int64{5}, Values: []float64{5}},
+					},
+					read:  &read,
+					limit: &limit,
+				},
+			}
+
+			req := &datatypes.ReadFilterRequest{
+				Range:     datatypes.TimestampRange{Start: 0, End: 10},
+				Aggregate: &datatypes.Aggregate{Type: tt.agg},
+			}
+			rs := reads.NewFilteredResultSet(context.Background(), req, &sliceSeriesCursor{
+				rows: []reads.SeriesRow{{Field: "f0", Query: shards}},
+			})
+			defer rs.Close()
+
+			if !rs.Next() {
+				t.Fatal("expected a series")
+			}
+
+			var got []point
+			switch cur := rs.Cursor().(type) {
+			case cursors.FloatArrayCursor:
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					for i := range a.Timestamps {
+						got = append(got, point{T: a.Timestamps[i], V: a.Values[i]})
+					}
+				}
+			case cursors.IntegerArrayCursor:
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					for i := range a.Timestamps {
+						got = append(got, point{T: a.Timestamps[i], V: a.Values[i]})
+					}
+				}
+			default:
+				t.Fatalf("unexpected cursor %T", cur)
+			}
+
+			if !cmp.Equal(tt.exp, got) {
+				t.Errorf("unexpected points -want/+got:\n%s", cmp.Diff(tt.exp, got))
+			}
+			if read != tt.read {
+				t.Errorf("unexpected number of blocks read: got %d want %d", read, tt.read)
+			}
+			if limit != tt.limit {
+				t.Errorf("unexpected limit of the cursor request: got %d want %d", limit, tt.limit)
+			}
+		})
+	}
+}
+
+func TestNewFilteredResultSet_UnsupportedAggregate(t *testing.T) {
+	for _, agg := range []datatypes.Aggregate_AggregateType{
+		datatypes.AggregateTypeMin,
+		datatypes.AggregateTypeMax,
+		datatypes.AggregateTypeMean,
+		datatypes.AggregateTypeSum,
+	} {
+		t.Run(agg.String(), func(t *testing.T) {
+			req := &datatypes.ReadFilterRequest{
+				Range:     datatypes.TimestampRange{Start: 0, End: 10},
+				Aggregate: &datatypes.Aggregate{Type: agg},
+			}
+			rs := reads.NewFilteredResultSet(context.Background(), req, &sliceSeriesCursor{
+				rows: []reads.SeriesRow{
+					{Field: "f0", Query: cursors.CursorIterators{&stringCursorIterator{values: []string{"a", "b"}}}},
+					{Field: "f1", Query: cursors.CursorIterators{&stringCursorIterator{values: []string{"c"}}}},
+				},
+			})
+			defer rs.Close()
+
+			// the read ends with an error instead of skipping the series.
+			if !rs.Next() {
+				t.Fatal("expected a series")
+			}
+			if cur := rs.Cursor(); cur != nil {
+				t.Fatalf("unexpected cursor %T", cur)
+			}
+			if rs.Next() {
+				t.Fatal("expected the read to end")
+			}
+			if err := rs.Err(); err == nil {
+				t.Fatal("expected an error about the unsupported aggregate")
+			}
+		})
+	}
+}
+
+func TestNewWindowAggregateResultSet(t *testing.T) {
+	type point struct {
+		T int64
+		V interface{}
+	}
+
+	tests := []struct {
+		name   string
+		agg    datatypes.Aggregate_AggregateType
+		offset int64
+		exp    []point
+	}{
+		{agg: datatypes.AggregateTypeMin, exp: []point{{T: 1, V: 3.0}, {T: 2, V: 1.0}, {T: 4, V: 1.0}}},
+		{agg: datatypes.AggregateTypeMax, exp: []point{{T: 1, V: 3.0}, {T: 3, V: 4.0}, {T: 5, V: 5.0}}},
+		{agg: datatypes.AggregateTypeFirst, exp: []point{{T: 1, V: 3.0}, {T: 2, V: 1.0}, {T: 4, V: 1.0}}},
+		{agg: datatypes.AggregateTypeLast, exp: []point{{T: 1, V: 3.0}, {T: 3, V: 4.0}, {T: 5, V: 5.0}}},
+		{agg: datatypes.AggregateTypeMean, exp: []point{{T: 1, V: 3.0}, {T: 2, V: 2.5}, {T: 4, V: 3.0}}},
+		{agg: datatypes.AggregateTypeSum, exp: []point{{T: 1, V: 3.0}, {T: 2, V: 5.0}, {T: 4, V: 6.0}}},
+		{agg: datatypes.AggregateTypeCount, exp: []point{{T: 1, V: int64(1)}, {T: 2, V: int64(2)}, {T: 4, V: int64(2)}}},
+		{
+			name:   "offset",
+			agg:    datatypes.AggregateTypeCount,
+			offset: 1,
+			exp:    []point{{T: 1, V: int64(2)}, {T: 3, V: int64(2)}, {T: 5, V: int64(1)}},
+		},
+	}
+
+	for _, tt := range tests {
+		name := tt.name
+		if name == "" {
+			name = tt.agg.String()
+		}
+		t.Run(name, func(t *testing.T) {
+			// The windows of two points are across the blocks and the shards of the series.
+			var read int
+			shards := cursors.CursorIterators{
+				&floatBlocksCursorIterator{
+					blocks: []*cursors.FloatArray{
+						{Timestamps: []int64{1, 2}, Values: []float64{3, 1}},
+					},
+					read: &read,
+				},
+				&floatBlocksCursorIterator{
+					blocks: []*cursors.FloatArray{
+						{Timestamps: []int64{3, 4}, Values: []float64{4, 1}},
+						{Timestamps: []int64{5}, Values: []float64{5}},
+					},
+					read: &read,
+				},
+			}
+
+			req := &datatypes.ReadWindowAggregateRequest{
+				Range:       datatypes.TimestampRange{Start: 0, End: 10},
+				WindowEvery: 2,
+				Offset:      tt.offset,
+				Aggregate:   &datatypes.Aggregate{Type: tt.agg},
+			}
+			rs, err := reads.NewWindowAggregateResultSet(context.Background(), req, &sliceSeriesCursor{
+				rows: []reads.SeriesRow{{Field: "f0", Query: shards}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rs.Close()
+
+			if !rs.Next() {
+				t.Fatal("expected a series")
+			}
+
+			var got []point
+			switch cur := rs.Cursor().(type) {
+			case cursors.FloatArrayCursor:
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					for i := range a.Timestamps {
+						got = append(got, point{T: a.Timestamps[i], V: a.Values[i]})
+					}
+				}
+			case cursors.IntegerArrayCursor:
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					for i := range a.Timestamps {
+						got = append(got, point{T: a.Timestamps[i], V: a.Values[i]})
+					}
+				}
+			default:
+				t.Fatalf("unexpected cursor %T", cur)
+			}
+
+			if !cmp.Equal(tt.exp, got) {
+				t.Errorf("unexpected points -want/+got:\n%s", cmp.Diff(tt.exp, got))
+			}
+		})
+	}
+}
+
+func TestNewWindowAggregateResultSet_InvalidWindow(t *testing.T) {
+	for _, req := range []*datatypes.ReadWindowAggregateRequest{
+		{WindowEvery: 0, Aggregate: &datatypes.Aggregate{Type: datatypes.AggregateTypeCount}},
+		{WindowEvery: 2, Offset: 3, Aggregate: &datatypes.Aggregate{Type: datatypes.AggregateTypeCount}},
+		{WindowEvery: 2},
+	} {
+		if _, err := reads.NewWindowAggregateResultSet(context.Background(), req, &sliceSeriesCursor{}); err == nil {
+			t.Errorf("expected an error for window every %d, offset %d and aggregate %v", req.WindowEvery, req.Offset, req.Aggregate)
+		}
+	}
+}
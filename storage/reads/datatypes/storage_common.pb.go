@@ -96,18 +96,33 @@ const (
 	AggregateTypeNone  Aggregate_AggregateType = 0
 	AggregateTypeSum   Aggregate_AggregateType = 1
 	AggregateTypeCount Aggregate_AggregateType = 2
+	AggregateTypeMin   Aggregate_AggregateType = 3
+	AggregateTypeMax   Aggregate_AggregateType = 4
+	AggregateTypeFirst Aggregate_AggregateType = 5
+	AggregateTypeLast  Aggregate_AggregateType = 6
+	AggregateTypeMean  Aggregate_AggregateType = 7
 )
 
 var Aggregate_AggregateType_name = map[int32]string{
 	0: "NONE",
 	1: "SUM",
 	2: "COUNT",
+	3: "MIN",
+	4: "MAX",
+	5: "FIRST",
+	6: "LAST",
+	7: "MEAN",
 }
 
 var Aggregate_AggregateType_value = map[string]int32{
 	"NONE":  0,
 	"SUM":   1,
 	"COUNT": 2,
+	"MIN":   3,
+	"MAX":   4,
+	"FIRST": 5,
+	"LAST":  6,
+	"MEAN":  7,
 }
 
 func (x Aggregate_AggregateType) String() string {
@@ -181,6 +196,7 @@ type ReadFilterRequest struct {
 	ReadSource *types.Any     `protobuf:"bytes,1,opt,name=read_source,json=readSource,proto3" json:"read_source,omitempty"`
 	Range      TimestampRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range"`
 	Predicate  *Predicate     `protobuf:"bytes,3,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	Aggregate  *Aggregate     `protobuf:"bytes,4,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
 }
 
 func (m *ReadFilterRequest) Reset()         { *m = ReadFilterRequest{} }
@@ -1145,6 +1161,56 @@ func (m *StringValuesResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StringValuesResponse proto.InternalMessageInfo
 
+// ReadWindowAggregateRequest is the request message for Storage.ReadWindowAggregate.
+//
+// The points of each series in the range are partitioned into windows of
+// window_every nanoseconds, shifted from the zero time by offset nanoseconds,
+// and the response has a point with the aggregate of every window which has
+// points. Windows are clipped to the range. The point of a selector (min,
+// max, first and last) is the selected point, and the point of any other
+// aggregate has the time of the first point of its window.
+type ReadWindowAggregateRequest struct {
+	ReadSource  *types.Any     `protobuf:"bytes,1,opt,name=read_source,json=readSource,proto3" json:"read_source,omitempty"`
+	Range       TimestampRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range"`
+	Predicate   *Predicate     `protobuf:"bytes,3,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	WindowEvery int64          `protobuf:"varint,4,opt,name=window_every,json=windowEvery,proto3" json:"window_every,omitempty"`
+	Offset      int64          `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	Aggregate   *Aggregate     `protobuf:"bytes,6,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+}
+
+func (m *ReadWindowAggregateRequest) Reset()         { *m = ReadWindowAggregateRequest{} }
+func (m *ReadWindowAggregateRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadWindowAggregateRequest) ProtoMessage()    {}
+func (*ReadWindowAggregateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_715e4bf4cdf1f73d, []int{10}
+}
+func (m *ReadWindowAggregateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReadWindowAggregateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReadWindowAggregateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReadWindowAggregateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadWindowAggregateRequest.Merge(m, src)
+}
+func (m *ReadWindowAggregateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReadWindowAggregateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadWindowAggregateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadWindowAggregateRequest proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("influxdata.platform.storage.ReadGroupRequest_Group", ReadGroupRequest_Group_name, ReadGroupRequest_Group_value)
 	proto.RegisterEnum("influxdata.platform.storage.ReadGroupRequest_HintFlags", ReadGroupRequest_HintFlags_name, ReadGroupRequest_HintFlags_value)
@@ -1170,106 +1236,116 @@ func init() {
 	proto.RegisterType((*TagKeysRequest)(nil), "influxdata.platform.storage.TagKeysRequest")
 	proto.RegisterType((*TagValuesRequest)(nil), "influxdata.platform.storage.TagValuesRequest")
 	proto.RegisterType((*StringValuesResponse)(nil), "influxdata.platform.storage.StringValuesResponse")
+	proto.RegisterType((*ReadWindowAggregateRequest)(nil), "influxdata.platform.storage.ReadWindowAggregateRequest")
 }
 
 func init() { proto.RegisterFile("storage_common.proto", fileDescriptor_715e4bf4cdf1f73d) }
 
 var fileDescriptor_715e4bf4cdf1f73d = []byte{
-	// 1498 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x57, 0xcb, 0x6f, 0xdb, 0xc8,
-	0x19, 0x17, 0xf5, 0x34, 0x3f, 0xc9, 0x32, 0x3d, 0x51, 0x5d, 0x87, 0x69, 0x24, 0x56, 0x28, 0x52,
-	0x17, 0x49, 0xe4, 0xd4, 0x49, 0xd1, 0x20, 0x6d, 0x0f, 0x96, 0x23, 0x5b, 0x6a, 0x6c, 0xc9, 0xa0,
-	0xe4, 0x00, 0xe9, 0x45, 0x18, 0xdb, 0x63, 0x86, 0x88, 0x44, 0xb2, 0x24, 0x15, 0x58, 0x40, 0x2f,
-	0xbd, 0x05, 0x3a, 0xb5, 0x97, 0x1e, 0x0a, 0x08, 0x58, 0x60, 0x8f, 0x7b, 0xdf, 0xbf, 0x21, 0x87,
-	0x05, 0x36, 0xc7, 0x3d, 0x09, 0xbb, 0x0a, 0xb0, 0xc0, 0xfe, 0x0b, 0x7b, 0x5a, 0xcc, 0x0c, 0x47,
-	0xa2, 0x6c, 0xc3, 0x96, 0xf6, 0xb4, 0xc8, 0x6d, 0xe6, 0x7b, 0xfc, 0xbe, 0x07, 0xbf, 0xc7, 0x10,
-	0x72, 0x9e, 0x6f, 0xbb, 0xd8, 0x20, 0xed, 0x13, 0xbb, 0xdb, 0xb5, 0xad, 0x92, 0xe3, 0xda, 0xbe,
-	0x8d, 0xee, 0x98, 0xd6, 0x59, 0xa7, 0x77, 0x7e, 0x8a, 0x7d, 0x5c, 0x72, 0x3a, 0xd8, 0x3f, 0xb3,
-	0xdd, 0x6e, 0x29, 0x90, 0x54, 0x73, 0x86, 0x6d, 0xd8, 0x4c, 0x6e, 0x93, 0x9e, 0xb8, 0x8a, 0x7a,
-	0xc7, 0xb0, 0x6d, 0xa3, 0x43, 0x36, 0xd9, 0xed, 0xb8, 0x77, 0xb6, 0x49, 0xba, 0x8e, 0xdf, 0x0f,
-	0x98, 0xb7, 0x2f, 0x32, 0xb1, 0x25, 0x58, 0x2b, 0x8e, 0x4b, 0x4e, 0xcd, 0x13, 0xec, 0x13, 0x4e,
-	0x28, 0xfe, 0x20, 0xc1, 0xaa, 0x4e, 0xf0, 0xe9, 0xae, 0xd9, 0xf1, 0x89, 0xab, 0x93, 0x7f, 0xf6,
-	0x88, 0xe7, 0xa3, 0x0a, 0xa4, 0x5d, 0x82, 0x4f, 0xdb, 0x9e, 0xdd, 0x73, 0x4f, 0xc8, 0xba, 0xa4,
-	0x49, 0x1b, 0xe9, 0xad, 0x5c, 0x89, 0xe3, 0x96, 0x04, 0x6e, 0x69, 0xdb, 0xea, 0x97, 0xb3, 0xe3,
-	0x51, 0x01, 0x28, 0x42, 0x93, 0xc9, 0xea, 0xe0, 0x4e, 0xce, 0x68, 0x0f, 0x12, 0x2e, 0xb6, 0x0c,
-	0xb2, 0x1e, 0x65, 0x00, 0xf7, 0x4b, 0xd7, 0x04, 0x5a, 0x6a, 0x99, 0x5d, 0xe2, 0xf9, 0xb8, 0xeb,
-	0xe8, 0x54, 0xa5, 0x1c, 0x7f, 0x3f, 0x2a, 0x44, 0x74, 0xae, 0x8f, 0x9e, 0x83, 0x3c, 0x71, 0x7c,
-	0x3d, 0xc6, 0xc0, 0xee, 0x5d, 0x0b, 0x76, 0x28, 0xa4, 0xf5, 0xa9, 0x62, 0xf1, 0xab, 0x04, 0x28,
-	0xd4, 0xd3, 0x3d, 0xd7, 0xee, 0x39, 0x9f, 0x74, 0xa8, 0xe8, 0x01, 0x80, 0x41, 0xa3, 0x6c, 0xbf,
-	0x21, 0x7d, 0x6f, 0x3d, 0xae, 0xc5, 0x36, 0xe4, 0xf2, 0xf2, 0x78, 0x54, 0x90, 0x59, 0xec, 0x2f,
-	0x48, 0xdf, 0xd3, 0x65, 0x43, 0x1c, 0x51, 0x0d, 0x12, 0xec, 0xb2, 0x9e, 0xd0, 0xa4, 0x8d, 0xec,
-	0xd6, 0xe3, 0x6b, 0xed, 0x5d, 0xcc, 0x60, 0x89, 0x5f, 0x38, 0x02, 0x75, 0x1f, 0x1b, 0x86, 0x4b,
-	0x0c, 0xea, 0x7e, 0x72, 0x0e, 0xf7, 0xb7, 0x85, 0xb4, 0x3e, 0x55, 0x44, 0x0f, 0x20, 0xf1, 0xda,
-	0xb4, 0x7c, 0x6f, 0x3d, 0xa5, 0x49, 0x1b, 0xa9, 0xf2, 0xda, 0x78, 0x54, 0x48, 0x54, 0x29, 0xe1,
-	0xc7, 0x51, 0x41, 0xa6, 0x87, 0xdd, 0x0e, 0x36, 0x3c, 0x9d, 0x0b, 0x15, 0xf7, 0x20, 0xc1, 0x7c,
-	0x40, 0x77, 0x01, 0xf6, 0xf4, 0xc6, 0xd1, 0x61, 0xbb, 0xde, 0xa8, 0x57, 0x94, 0x88, 0xba, 0x3c,
-	0x18, 0x6a, 0x3c, 0xe2, 0xba, 0x6d, 0x11, 0x74, 0x1b, 0x96, 0x38, 0xbb, 0xfc, 0x4a, 0x89, 0xaa,
-	0xe9, 0xc1, 0x50, 0x4b, 0x31, 0x66, 0xb9, 0xaf, 0xc6, 0xdf, 0x7d, 0x9e, 0x8f, 0x14, 0xbf, 0x90,
-	0x60, 0x8a, 0x8e, 0xee, 0x80, 0x5c, 0xad, 0xd5, 0x5b, 0x02, 0x2c, 0x33, 0x18, 0x6a, 0x4b, 0x94,
-	0xcb, 0xb0, 0x7e, 0x07, 0xd9, 0x80, 0xd9, 0x3e, 0x6c, 0xd4, 0xea, 0xad, 0xa6, 0x22, 0xa9, 0xca,
-	0x60, 0xa8, 0x65, 0xb8, 0xc4, 0xa1, 0x4d, 0x3d, 0x0b, 0x4b, 0x35, 0x2b, 0x7a, 0xad, 0xd2, 0x54,
-	0xa2, 0x61, 0xa9, 0x26, 0x71, 0x4d, 0xe2, 0xa1, 0x4d, 0xc8, 0x31, 0xa9, 0xe6, 0x4e, 0xb5, 0x72,
-	0xb0, 0xdd, 0xde, 0xde, 0xdf, 0x6f, 0xb7, 0x6a, 0x07, 0x15, 0x25, 0xae, 0xfe, 0x6a, 0x30, 0xd4,
-	0x56, 0xa9, 0x6c, 0xf3, 0xe4, 0x35, 0xe9, 0xe2, 0xed, 0x4e, 0x87, 0x96, 0x4e, 0xe0, 0xed, 0xd7,
-	0x12, 0xc8, 0x93, 0xec, 0xa1, 0x2a, 0xc4, 0xfd, 0xbe, 0xc3, 0x0b, 0x38, 0xbb, 0xf5, 0x64, 0xbe,
-	0x9c, 0x4f, 0x4f, 0xad, 0xbe, 0x43, 0x74, 0x86, 0x50, 0x3c, 0x87, 0xe5, 0x19, 0x32, 0x2a, 0x40,
-	0x3c, 0xc8, 0x01, 0xf3, 0x67, 0x86, 0xc9, 0x92, 0x71, 0x17, 0x62, 0xcd, 0xa3, 0x03, 0x45, 0x52,
-	0x73, 0x83, 0xa1, 0xa6, 0xcc, 0xf0, 0x9b, 0xbd, 0x2e, 0xfa, 0x2d, 0x24, 0x76, 0x1a, 0x47, 0xf5,
-	0x96, 0x12, 0x55, 0xd7, 0x06, 0x43, 0x0d, 0xcd, 0x08, 0xec, 0xd8, 0x3d, 0xcb, 0x0f, 0x22, 0x7a,
-	0x08, 0xb1, 0x16, 0x36, 0x90, 0x02, 0xb1, 0x37, 0xa4, 0xcf, 0x22, 0xc9, 0xe8, 0xf4, 0x88, 0x72,
-	0x90, 0x78, 0x8b, 0x3b, 0x3d, 0xde, 0x5d, 0x19, 0x9d, 0x5f, 0x8a, 0xff, 0xcd, 0x42, 0x86, 0x56,
-	0xa3, 0x4e, 0x3c, 0xc7, 0xb6, 0x3c, 0x82, 0x0e, 0x20, 0x79, 0xe6, 0xe2, 0x2e, 0xf1, 0xd6, 0x25,
-	0x2d, 0xb6, 0x91, 0xde, 0xda, 0xbc, 0xb1, 0x90, 0x85, 0x6a, 0x69, 0x97, 0xea, 0x05, 0x9d, 0x18,
-	0x80, 0xa8, 0xef, 0x92, 0x90, 0x60, 0x74, 0xb4, 0x2f, 0x1a, 0x24, 0xc5, 0x2a, 0xfa, 0xc9, 0xfc,
-	0xb8, 0xac, 0xc0, 0x18, 0x48, 0x35, 0x22, 0x7a, 0xa4, 0x01, 0x49, 0x8f, 0x7d, 0xf9, 0x60, 0xda,
-	0xfc, 0x69, 0x7e, 0x38, 0x5e, 0x31, 0x02, 0x2f, 0x80, 0x41, 0x0e, 0x64, 0xce, 0x3a, 0x36, 0xf6,
-	0xdb, 0x0e, 0x2b, 0xbb, 0x60, 0x06, 0x3d, 0x5b, 0x20, 0x7a, 0xaa, 0xcd, 0x6b, 0x96, 0x27, 0x62,
-	0x65, 0x3c, 0x2a, 0xa4, 0x43, 0xd4, 0x6a, 0x44, 0x4f, 0x9f, 0x4d, 0xaf, 0xe8, 0x1c, 0xb2, 0xa6,
-	0xe5, 0x13, 0x83, 0xb8, 0xc2, 0x26, 0x1f, 0x55, 0x7f, 0x9d, 0xdf, 0x66, 0x8d, 0xeb, 0x87, 0xad,
-	0xae, 0x8e, 0x47, 0x85, 0xe5, 0x19, 0x7a, 0x35, 0xa2, 0x2f, 0x9b, 0x61, 0x02, 0xfa, 0x17, 0xac,
-	0xf4, 0x2c, 0xcf, 0x34, 0x2c, 0x72, 0x2a, 0x4c, 0xc7, 0x99, 0xe9, 0xbf, 0xcd, 0x6f, 0xfa, 0x28,
-	0x00, 0x08, 0xdb, 0x46, 0xe3, 0x51, 0x21, 0x3b, 0xcb, 0xa8, 0x46, 0xf4, 0x6c, 0x6f, 0x86, 0x42,
-	0xe3, 0x3e, 0xb6, 0xed, 0x0e, 0xc1, 0x96, 0x30, 0x9e, 0x58, 0x34, 0xee, 0x32, 0xd7, 0xbf, 0x14,
-	0xf7, 0x0c, 0x9d, 0xc6, 0x7d, 0x1c, 0x26, 0x20, 0x1f, 0x96, 0x3d, 0xdf, 0x35, 0x2d, 0x43, 0x18,
-	0xe6, 0xc3, 0xf5, 0x2f, 0x0b, 0xd4, 0x0e, 0x53, 0x0f, 0xdb, 0x55, 0xc6, 0xa3, 0x42, 0x26, 0x4c,
-	0xae, 0x46, 0xf4, 0x8c, 0x17, 0xba, 0x97, 0x93, 0x10, 0xa7, 0xc8, 0xea, 0x39, 0xc0, 0xb4, 0x92,
-	0xd1, 0x3d, 0x58, 0xf2, 0xb1, 0xc1, 0x77, 0x0b, 0xed, 0xb4, 0x4c, 0x39, 0x3d, 0x1e, 0x15, 0x52,
-	0x2d, 0x6c, 0xb0, 0xcd, 0x92, 0xf2, 0xf9, 0x01, 0x95, 0x01, 0x39, 0xd8, 0xf5, 0x4d, 0xdf, 0xb4,
-	0x2d, 0x2a, 0xdd, 0x7e, 0x8b, 0x3b, 0xb4, 0x3a, 0xa9, 0x46, 0x6e, 0x3c, 0x2a, 0x28, 0x87, 0x82,
-	0xfb, 0x82, 0xf4, 0x5f, 0xe2, 0x8e, 0xa7, 0x2b, 0xce, 0x05, 0x8a, 0xfa, 0x7f, 0x09, 0xd2, 0xa1,
-	0xaa, 0x47, 0xcf, 0x20, 0xee, 0x63, 0x43, 0x74, 0xb8, 0x76, 0xfd, 0x9e, 0xc5, 0x46, 0xd0, 0xd2,
-	0x4c, 0x07, 0x35, 0x40, 0xa6, 0x82, 0x6d, 0x36, 0x28, 0xa3, 0x6c, 0x50, 0x6e, 0xcd, 0x9f, 0xbf,
-	0xe7, 0xd8, 0xc7, 0x6c, 0x4c, 0x2e, 0x9d, 0x06, 0x27, 0xf5, 0xef, 0xa0, 0x5c, 0x6c, 0x1d, 0x94,
-	0x07, 0xf0, 0xc5, 0x7e, 0xe7, 0x6e, 0x2a, 0x7a, 0x88, 0x82, 0xd6, 0x20, 0xc9, 0xc6, 0x17, 0x4f,
-	0x84, 0xa4, 0x07, 0x37, 0x75, 0x1f, 0xd0, 0xe5, 0x96, 0x58, 0x10, 0x2d, 0x36, 0x41, 0x3b, 0x80,
-	0x5b, 0x57, 0x54, 0xf9, 0x82, 0x70, 0xf1, 0xb0, 0x73, 0x97, 0xeb, 0x76, 0x41, 0xb4, 0xa5, 0x09,
-	0xda, 0x0b, 0x58, 0xbd, 0x54, 0x8c, 0x0b, 0x82, 0xc9, 0x02, 0xac, 0xd8, 0x04, 0x99, 0x01, 0x04,
-	0xab, 0x2a, 0x19, 0x2c, 0xda, 0x88, 0x7a, 0x6b, 0x30, 0xd4, 0x56, 0x26, 0xac, 0x60, 0xd7, 0x16,
-	0x20, 0x39, 0xd9, 0xd7, 0xb3, 0x02, 0xdc, 0x97, 0x60, 0x13, 0x7d, 0x29, 0xc1, 0x92, 0xf8, 0xde,
-	0xe8, 0x37, 0x90, 0xd8, 0xdd, 0x6f, 0x6c, 0xb7, 0x94, 0x88, 0xba, 0x3a, 0x18, 0x6a, 0xcb, 0x82,
-	0xc1, 0x3e, 0x3d, 0xd2, 0x20, 0x55, 0xab, 0xb7, 0x2a, 0x7b, 0x15, 0x5d, 0x40, 0x0a, 0x7e, 0xf0,
-	0x39, 0x51, 0x11, 0x96, 0x8e, 0xea, 0xcd, 0xda, 0x5e, 0xbd, 0xf2, 0x5c, 0x89, 0xf2, 0x1d, 0x29,
-	0x44, 0xc4, 0x37, 0xa2, 0x28, 0xe5, 0x46, 0x63, 0xbf, 0xb2, 0x5d, 0x57, 0x62, 0xb3, 0x28, 0x41,
-	0xde, 0x51, 0x1e, 0x92, 0xcd, 0x96, 0x5e, 0xab, 0xef, 0x29, 0x71, 0x15, 0x0d, 0x86, 0x5a, 0x56,
-	0x08, 0xf0, 0x54, 0x06, 0x8e, 0x7f, 0x26, 0x41, 0x6e, 0x07, 0x3b, 0xf8, 0xd8, 0xec, 0x98, 0xbe,
-	0x49, 0xbc, 0xc9, 0x6e, 0x6c, 0x40, 0xfc, 0x04, 0x3b, 0xa2, 0x6f, 0xae, 0x1f, 0x1b, 0x57, 0x01,
-	0x50, 0xa2, 0x57, 0xb1, 0x7c, 0xb7, 0xaf, 0x33, 0x20, 0xf5, 0xcf, 0x20, 0x4f, 0x48, 0xe1, 0x95,
-	0x2d, 0x5f, 0xb1, 0xb2, 0xe5, 0x60, 0x65, 0x3f, 0x8b, 0x3e, 0x95, 0x8a, 0x4f, 0x21, 0x3b, 0xfb,
-	0x00, 0xa6, 0xb2, 0x9e, 0x8f, 0x5d, 0x9f, 0xe9, 0xc7, 0x74, 0x7e, 0xa1, 0x98, 0xc4, 0x3a, 0x65,
-	0xfa, 0x31, 0x9d, 0x1e, 0x8b, 0xdf, 0x4b, 0x90, 0x15, 0x43, 0x66, 0xfa, 0x7c, 0xa7, 0xad, 0x3d,
-	0xf7, 0xf3, 0xbd, 0x85, 0x0d, 0x4f, 0x3c, 0xdf, 0xfd, 0xc9, 0xf9, 0x97, 0xf6, 0xa7, 0xf2, 0xef,
-	0x28, 0x28, 0x2d, 0x6c, 0xbc, 0x64, 0x15, 0xfe, 0x49, 0x87, 0x8a, 0x7e, 0x0d, 0xa9, 0x60, 0x97,
-	0xb0, 0x3d, 0x2e, 0xeb, 0x49, 0xbe, 0x3d, 0x8a, 0x25, 0xc8, 0xf1, 0xca, 0x16, 0x59, 0x08, 0x0a,
-	0x79, 0x3a, 0x07, 0xd8, 0xea, 0x11, 0x73, 0x60, 0xeb, 0x7f, 0x71, 0x48, 0x35, 0xb9, 0x25, 0x64,
-	0x02, 0x4c, 0x7f, 0x6a, 0x51, 0xe9, 0xc6, 0x19, 0x3f, 0xf3, 0xf7, 0xab, 0xfe, 0x61, 0xee, 0x9d,
-	0xf0, 0x48, 0x42, 0x06, 0xc8, 0x93, 0x3f, 0x22, 0xf4, 0x70, 0xa1, 0x3f, 0xa7, 0xc5, 0x0c, 0xbd,
-	0x01, 0xb1, 0x60, 0xd1, 0xfd, 0x9b, 0xb6, 0x5e, 0xa8, 0x43, 0xd4, 0x3f, 0x5e, 0x2b, 0x7c, 0x55,
-	0x8a, 0x1f, 0x49, 0xc8, 0x06, 0x79, 0x52, 0x7f, 0x37, 0x44, 0x75, 0xb1, 0x4e, 0x7f, 0x9e, 0xc1,
-	0x57, 0x90, 0x09, 0x4f, 0x1d, 0xb4, 0x76, 0xa9, 0xae, 0x2b, 0x5d, 0xc7, 0xef, 0xdf, 0x00, 0x7e,
-	0xd5, 0xe0, 0x2a, 0xff, 0xfe, 0xfd, 0x77, 0xf9, 0xc8, 0xfb, 0x71, 0x5e, 0xfa, 0x30, 0xce, 0x4b,
-	0xdf, 0x8e, 0xf3, 0xd2, 0x7f, 0x3e, 0xe6, 0x23, 0x1f, 0x3e, 0xe6, 0x23, 0xdf, 0x7c, 0xcc, 0x47,
-	0xfe, 0xc1, 0x5e, 0x04, 0xf4, 0x41, 0xe0, 0x1d, 0x27, 0x99, 0xad, 0xc7, 0x3f, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0x7f, 0x0e, 0xbb, 0xbb, 0xa6, 0x11, 0x00, 0x00,
+	// 1636 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcf, 0x6f, 0x1b, 0x4f,
+	0x15, 0xf7, 0xfa, 0x67, 0xf6, 0xd9, 0x71, 0x36, 0x53, 0x13, 0xf2, 0xdd, 0x52, 0x7b, 0xb1, 0x50,
+	0x09, 0x6a, 0xeb, 0x94, 0xb4, 0xa8, 0x55, 0x81, 0x83, 0x9d, 0x3a, 0xb1, 0xa9, 0x7f, 0x44, 0x6b,
+	0xa7, 0x50, 0x2e, 0xd6, 0x24, 0x1e, 0x6f, 0x57, 0xb5, 0x77, 0xcd, 0xee, 0xba, 0x8d, 0x05, 0x17,
+	0x24, 0x0e, 0x95, 0x4f, 0x20, 0x6e, 0x20, 0x4b, 0x48, 0x1c, 0x39, 0x70, 0xe3, 0x6f, 0xe8, 0x81,
+	0x43, 0x8f, 0x9c, 0x2c, 0x70, 0x25, 0xfe, 0x01, 0x6e, 0x9c, 0xd0, 0xcc, 0xec, 0xda, 0xeb, 0xc4,
+	0x4a, 0xec, 0x8a, 0xc3, 0x57, 0xbd, 0xcd, 0xbc, 0x1f, 0x9f, 0x37, 0xef, 0xed, 0xfb, 0x31, 0xb3,
+	0x90, 0xb2, 0x1d, 0xd3, 0xc2, 0x1a, 0x69, 0x9d, 0x9b, 0xbd, 0x9e, 0x69, 0xe4, 0xfa, 0x96, 0xe9,
+	0x98, 0xe8, 0xb6, 0x6e, 0x74, 0xba, 0x83, 0x8b, 0x36, 0x76, 0x70, 0xae, 0xdf, 0xc5, 0x4e, 0xc7,
+	0xb4, 0x7a, 0x39, 0x57, 0x52, 0x4e, 0x69, 0xa6, 0x66, 0x32, 0xb9, 0x7d, 0xba, 0xe2, 0x2a, 0xf2,
+	0x6d, 0xcd, 0x34, 0xb5, 0x2e, 0xd9, 0x67, 0xbb, 0xb3, 0x41, 0x67, 0x9f, 0xf4, 0xfa, 0xce, 0xd0,
+	0x65, 0x7e, 0x75, 0x99, 0x89, 0x0d, 0x8f, 0xb5, 0xd5, 0xb7, 0x48, 0x5b, 0x3f, 0xc7, 0x0e, 0xe1,
+	0x84, 0xec, 0x5f, 0x83, 0xb0, 0xad, 0x12, 0xdc, 0x3e, 0xd2, 0xbb, 0x0e, 0xb1, 0x54, 0xf2, 0x8b,
+	0x01, 0xb1, 0x1d, 0x54, 0x84, 0xb8, 0x45, 0x70, 0xbb, 0x65, 0x9b, 0x03, 0xeb, 0x9c, 0xec, 0x0a,
+	0x8a, 0xb0, 0x17, 0x3f, 0x48, 0xe5, 0x38, 0x6e, 0xce, 0xc3, 0xcd, 0xe5, 0x8d, 0x61, 0x21, 0x39,
+	0x9d, 0x64, 0x80, 0x22, 0x34, 0x98, 0xac, 0x0a, 0xd6, 0x6c, 0x8d, 0x8e, 0x21, 0x62, 0x61, 0x43,
+	0x23, 0xbb, 0x41, 0x06, 0x70, 0x2f, 0x77, 0x8d, 0xa3, 0xb9, 0xa6, 0xde, 0x23, 0xb6, 0x83, 0x7b,
+	0x7d, 0x95, 0xaa, 0x14, 0xc2, 0x1f, 0x26, 0x99, 0x80, 0xca, 0xf5, 0xd1, 0x73, 0x10, 0x67, 0x07,
+	0xdf, 0x0d, 0x31, 0xb0, 0xbb, 0xd7, 0x82, 0x9d, 0x78, 0xd2, 0xea, 0x5c, 0x91, 0xa2, 0x60, 0x4d,
+	0xb3, 0x88, 0x46, 0x51, 0xc2, 0x2b, 0xa0, 0xe4, 0x3d, 0x69, 0x75, 0xae, 0x98, 0xfd, 0x7b, 0x04,
+	0x24, 0xea, 0xef, 0xb1, 0x65, 0x0e, 0xfa, 0x5f, 0x76, 0xc0, 0xee, 0x03, 0x68, 0xd4, 0xcb, 0xd6,
+	0x1b, 0x32, 0xb4, 0x77, 0xc3, 0x4a, 0x68, 0x4f, 0x2c, 0x6c, 0x4e, 0x27, 0x19, 0x91, 0xf9, 0xfe,
+	0x82, 0x0c, 0x6d, 0x55, 0xd4, 0xbc, 0x25, 0x2a, 0x43, 0x84, 0x6d, 0x76, 0x23, 0x8a, 0xb0, 0x97,
+	0x3c, 0x78, 0x74, 0xad, 0xbd, 0xcb, 0x11, 0xcc, 0xf1, 0x0d, 0x47, 0x58, 0xfc, 0x52, 0xd1, 0xcf,
+	0xfc, 0x52, 0xe8, 0x3e, 0x44, 0x5e, 0xeb, 0x86, 0x63, 0xef, 0xc6, 0x14, 0x61, 0x2f, 0x56, 0xd8,
+	0x99, 0x4e, 0x32, 0x91, 0x12, 0x25, 0xfc, 0x77, 0x92, 0x11, 0xe9, 0xe2, 0xa8, 0x8b, 0x35, 0x5b,
+	0xe5, 0x42, 0xd9, 0x63, 0x88, 0xb0, 0x33, 0xa0, 0x3b, 0x00, 0xc7, 0x6a, 0xfd, 0xf4, 0xa4, 0x55,
+	0xab, 0xd7, 0x8a, 0x52, 0x40, 0xde, 0x1c, 0x8d, 0x15, 0xee, 0x71, 0xcd, 0x34, 0x08, 0xfa, 0x0a,
+	0x36, 0x38, 0xbb, 0xf0, 0x4a, 0x0a, 0xca, 0xf1, 0xd1, 0x58, 0x89, 0x31, 0x66, 0x61, 0x28, 0x87,
+	0xdf, 0xff, 0x39, 0x1d, 0xc8, 0xfe, 0x45, 0x80, 0x39, 0x3a, 0xba, 0x0d, 0x62, 0xa9, 0x5c, 0x6b,
+	0x7a, 0x60, 0x89, 0xd1, 0x58, 0xd9, 0xa0, 0x5c, 0x86, 0xf5, 0x1d, 0x48, 0xba, 0xcc, 0xd6, 0x49,
+	0xbd, 0x5c, 0x6b, 0x36, 0x24, 0x41, 0x96, 0x46, 0x63, 0x25, 0xc1, 0x25, 0x4e, 0x4c, 0x7a, 0x32,
+	0xbf, 0x54, 0xa3, 0xa8, 0x96, 0x8b, 0x0d, 0x29, 0xe8, 0x97, 0x6a, 0x10, 0x4b, 0x27, 0x36, 0xda,
+	0x87, 0x14, 0x93, 0x6a, 0x1c, 0x96, 0x8a, 0xd5, 0x7c, 0x2b, 0x5f, 0xa9, 0xb4, 0x9a, 0xe5, 0x6a,
+	0x51, 0x0a, 0xcb, 0xdf, 0x18, 0x8d, 0x95, 0x6d, 0x2a, 0xdb, 0x38, 0x7f, 0x4d, 0x7a, 0x38, 0xdf,
+	0xed, 0xd2, 0xd4, 0x71, 0x4f, 0xfb, 0x9f, 0x20, 0x88, 0xb3, 0xe8, 0xa1, 0x12, 0x84, 0x9d, 0x61,
+	0x9f, 0x27, 0x70, 0xf2, 0xe0, 0xf1, 0x6a, 0x31, 0x9f, 0xaf, 0x9a, 0xc3, 0x3e, 0x51, 0x19, 0x42,
+	0xf6, 0x8f, 0x41, 0xd8, 0x5c, 0xa0, 0xa3, 0x0c, 0x84, 0xdd, 0x20, 0xb0, 0x03, 0x2d, 0x30, 0x59,
+	0x34, 0xee, 0x40, 0xa8, 0x71, 0x5a, 0x95, 0x04, 0x39, 0x35, 0x1a, 0x2b, 0xd2, 0x02, 0xbf, 0x31,
+	0xe8, 0xa1, 0x6f, 0x43, 0xe4, 0xb0, 0x7e, 0x5a, 0x6b, 0x4a, 0x41, 0x79, 0x67, 0x34, 0x56, 0xd0,
+	0x82, 0xc0, 0xa1, 0x39, 0x30, 0x1c, 0x8a, 0x50, 0x2d, 0xd7, 0xa4, 0xd0, 0x12, 0x84, 0xaa, 0x6e,
+	0x30, 0x76, 0xfe, 0x67, 0x52, 0x78, 0x19, 0x1b, 0x5f, 0x50, 0x03, 0x47, 0x65, 0xb5, 0xd1, 0x94,
+	0x22, 0x4b, 0x0c, 0x1c, 0xe9, 0x96, 0xed, 0x50, 0x1f, 0x2a, 0xf9, 0x46, 0x53, 0x8a, 0x2e, 0xf1,
+	0xa1, 0x82, 0xb9, 0x40, 0xb5, 0x98, 0xaf, 0x49, 0xb1, 0x25, 0x02, 0x55, 0x82, 0x0d, 0x37, 0xea,
+	0x0f, 0x20, 0xd4, 0xc4, 0x1a, 0x92, 0x20, 0xf4, 0x86, 0x0c, 0x59, 0xb4, 0x13, 0x2a, 0x5d, 0xa2,
+	0x14, 0x44, 0xde, 0xe2, 0xee, 0x80, 0x77, 0x80, 0x84, 0xca, 0x37, 0xd9, 0xdf, 0x25, 0x21, 0x41,
+	0x2b, 0x46, 0x25, 0x76, 0xdf, 0x34, 0x6c, 0x82, 0xaa, 0x10, 0xed, 0x58, 0xb8, 0x47, 0xec, 0x5d,
+	0x41, 0x09, 0xed, 0xc5, 0x0f, 0xf6, 0x6f, 0x2c, 0x36, 0x4f, 0x35, 0x77, 0x44, 0xf5, 0xdc, 0x6e,
+	0xe1, 0x82, 0xc8, 0xef, 0xa3, 0x10, 0x61, 0x74, 0x54, 0xf1, 0x8a, 0x38, 0xc6, 0xaa, 0xee, 0xf1,
+	0xea, 0xb8, 0xac, 0x08, 0x18, 0x48, 0x29, 0xe0, 0xd5, 0x71, 0x1d, 0xa2, 0x36, 0xcb, 0x4e, 0xb7,
+	0x23, 0xfe, 0x60, 0x75, 0x38, 0x9e, 0xd5, 0x1e, 0x9e, 0x0b, 0x83, 0xfa, 0x90, 0xe8, 0x74, 0x4d,
+	0xec, 0xb4, 0xfa, 0xac, 0x34, 0xdc, 0x3e, 0xf9, 0x6c, 0x0d, 0xef, 0xa9, 0x36, 0xaf, 0x2b, 0x1e,
+	0x88, 0xad, 0xe9, 0x24, 0x13, 0xf7, 0x51, 0x4b, 0x01, 0x35, 0xde, 0x99, 0x6f, 0xd1, 0x05, 0x24,
+	0x75, 0xc3, 0x21, 0x1a, 0xb1, 0x3c, 0x9b, 0xbc, 0x9d, 0xfe, 0x68, 0x75, 0x9b, 0x65, 0xae, 0xef,
+	0xb7, 0xba, 0x3d, 0x9d, 0x64, 0x36, 0x17, 0xe8, 0xa5, 0x80, 0xba, 0xa9, 0xfb, 0x09, 0xe8, 0x57,
+	0xb0, 0x35, 0x30, 0x6c, 0x5d, 0x33, 0x48, 0xdb, 0x33, 0xcd, 0x87, 0xd6, 0x8f, 0x57, 0x37, 0x7d,
+	0xea, 0x02, 0xf8, 0x6d, 0xa3, 0xe9, 0x24, 0x93, 0x5c, 0x64, 0x94, 0x02, 0x6a, 0x72, 0xb0, 0x40,
+	0xa1, 0x7e, 0x9f, 0x99, 0x66, 0x97, 0x60, 0xc3, 0x33, 0x1e, 0x59, 0xd7, 0xef, 0x02, 0xd7, 0xbf,
+	0xe2, 0xf7, 0x02, 0x9d, 0xfa, 0x7d, 0xe6, 0x27, 0x20, 0x07, 0x36, 0x6d, 0xc7, 0xd2, 0x0d, 0xcd,
+	0x33, 0xcc, 0x07, 0xc0, 0x0f, 0xd7, 0xc8, 0x1d, 0xa6, 0xee, 0xb7, 0x2b, 0x4d, 0x27, 0x99, 0x84,
+	0x9f, 0x5c, 0x0a, 0xa8, 0x09, 0xdb, 0xb7, 0x2f, 0x44, 0x21, 0x4c, 0x91, 0xe5, 0x0b, 0x80, 0x79,
+	0x26, 0xa3, 0xbb, 0xb0, 0xe1, 0x60, 0x8d, 0xcf, 0x3f, 0x5a, 0x69, 0x89, 0x42, 0x7c, 0x3a, 0xc9,
+	0xc4, 0x9a, 0x58, 0x63, 0xd3, 0x2f, 0xe6, 0xf0, 0x05, 0x2a, 0x00, 0xea, 0x63, 0xcb, 0xd1, 0x1d,
+	0xdd, 0x34, 0xa8, 0x74, 0xeb, 0x2d, 0xee, 0xd2, 0xec, 0xa4, 0x1a, 0xa9, 0xe9, 0x24, 0x23, 0x9d,
+	0x78, 0xdc, 0x17, 0x64, 0xf8, 0x12, 0x77, 0x6d, 0x55, 0xea, 0x5f, 0xa2, 0xc8, 0x7f, 0x10, 0x20,
+	0xee, 0xcb, 0x7a, 0xf4, 0x0c, 0xc2, 0x0e, 0xd6, 0xbc, 0x0a, 0x57, 0xae, 0xbf, 0x0b, 0x60, 0xcd,
+	0x2d, 0x69, 0xa6, 0x83, 0xea, 0x20, 0x52, 0xc1, 0x16, 0x6b, 0xe6, 0x41, 0xd6, 0xcc, 0x0f, 0x56,
+	0x8f, 0xdf, 0x73, 0xec, 0x60, 0xd6, 0xca, 0x37, 0xda, 0xee, 0x4a, 0xfe, 0x09, 0x48, 0x97, 0x4b,
+	0x07, 0xa5, 0x01, 0x1c, 0xef, 0x0e, 0xc2, 0x8f, 0x29, 0xa9, 0x3e, 0x0a, 0xda, 0x81, 0x28, 0x6b,
+	0x5f, 0x3c, 0x10, 0x82, 0xea, 0xee, 0xe4, 0x0a, 0xa0, 0xab, 0x25, 0xb1, 0x26, 0x5a, 0x68, 0x86,
+	0x56, 0x85, 0x5b, 0x4b, 0xb2, 0x7c, 0x4d, 0xb8, 0xb0, 0xff, 0x70, 0x57, 0xf3, 0x76, 0x4d, 0xb4,
+	0x8d, 0x19, 0xda, 0x0b, 0xd8, 0xbe, 0x92, 0x8c, 0x6b, 0x82, 0x89, 0x1e, 0x58, 0xb6, 0x01, 0x22,
+	0x03, 0x70, 0xa7, 0x69, 0xd4, 0xbd, 0x0c, 0x04, 0xe4, 0x5b, 0xa3, 0xb1, 0xb2, 0x35, 0x63, 0xb9,
+	0xf7, 0x81, 0x0c, 0x44, 0x67, 0x77, 0x8a, 0x45, 0x01, 0x7e, 0x16, 0x77, 0x12, 0xfd, 0x4d, 0x80,
+	0x0d, 0xef, 0x7b, 0xa3, 0x6f, 0x41, 0xe4, 0xa8, 0x52, 0xcf, 0x37, 0xa5, 0x80, 0xbc, 0x3d, 0x1a,
+	0x2b, 0x9b, 0x1e, 0x83, 0x7d, 0x7a, 0xa4, 0x40, 0xac, 0x5c, 0x6b, 0x16, 0x8f, 0x8b, 0xaa, 0x07,
+	0xe9, 0xf1, 0xdd, 0xcf, 0x89, 0xb2, 0xb0, 0x71, 0x5a, 0x6b, 0x94, 0x8f, 0x6b, 0xc5, 0xe7, 0x52,
+	0x90, 0x4f, 0x59, 0x4f, 0xc4, 0xfb, 0x46, 0x14, 0xa5, 0x50, 0xaf, 0x57, 0xe8, 0x90, 0x0c, 0x2d,
+	0xa2, 0xb8, 0x71, 0x47, 0x69, 0x88, 0x36, 0x9a, 0x6a, 0xb9, 0x76, 0x2c, 0x85, 0x65, 0x34, 0x1a,
+	0x2b, 0x49, 0x4f, 0x80, 0x87, 0xd2, 0x3d, 0xf8, 0x9f, 0x04, 0x48, 0x1d, 0xe2, 0x3e, 0x3e, 0xd3,
+	0xbb, 0xba, 0xa3, 0x13, 0x7b, 0x36, 0x1b, 0xeb, 0x10, 0x3e, 0xc7, 0x7d, 0xaf, 0x6e, 0xae, 0x6f,
+	0x1b, 0xcb, 0x00, 0x28, 0xd1, 0x2e, 0x1a, 0x8e, 0x35, 0x54, 0x19, 0x90, 0xfc, 0x04, 0xc4, 0x19,
+	0xc9, 0x3f, 0xb2, 0xc5, 0x25, 0x23, 0x5b, 0x74, 0x47, 0xf6, 0xb3, 0xe0, 0x53, 0x21, 0xfb, 0x14,
+	0x92, 0x8b, 0x97, 0x74, 0x2a, 0x6b, 0x3b, 0xd8, 0x72, 0x98, 0x7e, 0x48, 0xe5, 0x1b, 0x8a, 0x49,
+	0x8c, 0x36, 0xd3, 0x0f, 0xa9, 0x74, 0x99, 0xfd, 0xb7, 0x00, 0x49, 0xaf, 0xc9, 0xcc, 0x9f, 0x18,
+	0xb4, 0xb4, 0x57, 0x7e, 0x62, 0x34, 0xb1, 0x66, 0x7b, 0x4f, 0x0c, 0x67, 0xb6, 0xfe, 0x9a, 0x3d,
+	0x31, 0xb2, 0xbf, 0x0e, 0x82, 0xd4, 0xc4, 0xda, 0x4b, 0x96, 0xe1, 0x5f, 0xb4, 0xab, 0xe8, 0x9b,
+	0x10, 0x73, 0x67, 0x09, 0x9b, 0xe3, 0xa2, 0x1a, 0xe5, 0xd3, 0x23, 0x9b, 0x83, 0x14, 0xcf, 0x6c,
+	0x2f, 0x0a, 0x6e, 0x22, 0xcf, 0xfb, 0x00, 0x1b, 0x3d, 0xb3, 0x3e, 0xf0, 0x9b, 0x10, 0xc8, 0xb4,
+	0x5f, 0xff, 0x54, 0x37, 0xda, 0xe6, 0xbb, 0xf9, 0xd3, 0xe7, 0x8b, 0x7e, 0x8b, 0x1e, 0x40, 0xe2,
+	0x1d, 0xf3, 0xb7, 0x45, 0xde, 0x12, 0x8b, 0x87, 0x30, 0xc4, 0x6f, 0x6f, 0x3c, 0x0e, 0x45, 0x4a,
+	0x56, 0xe3, 0xef, 0xe6, 0x1b, 0x1a, 0x40, 0xb3, 0xd3, 0xb1, 0x89, 0xc3, 0xee, 0x2e, 0x21, 0xd5,
+	0xdd, 0xfd, 0x7f, 0x9e, 0x97, 0x07, 0xbf, 0x8f, 0x40, 0xac, 0xc1, 0x05, 0x90, 0x0e, 0x30, 0xff,
+	0x8b, 0x82, 0x72, 0x37, 0x8e, 0xda, 0x85, 0xdf, 0x2d, 0xf2, 0xf7, 0x56, 0x1e, 0xcd, 0x0f, 0x05,
+	0xa4, 0x81, 0x38, 0x7b, 0x3c, 0xa3, 0x07, 0x6b, 0x3d, 0xb2, 0xd7, 0x33, 0xf4, 0x4b, 0xb8, 0xb5,
+	0x24, 0xcb, 0xd0, 0x93, 0x1b, 0x31, 0x96, 0xe7, 0xe5, 0x7a, 0xc6, 0xdf, 0x80, 0x77, 0xc9, 0x42,
+	0xf7, 0x6e, 0xba, 0xf9, 0xf8, 0xba, 0xa4, 0xfc, 0xfd, 0x6b, 0x85, 0x97, 0x95, 0xd9, 0x43, 0x01,
+	0x99, 0x20, 0xce, 0x7a, 0xd0, 0x0d, 0x21, 0xbd, 0xdc, 0xab, 0x3e, 0xcf, 0xe0, 0x2b, 0x48, 0xf8,
+	0x27, 0x0f, 0xda, 0xb9, 0x52, 0x9d, 0x45, 0xfa, 0x3f, 0xef, 0x06, 0xf0, 0x65, 0xc3, 0xab, 0xf0,
+	0xdd, 0x0f, 0xff, 0x4a, 0x07, 0x3e, 0x4c, 0xd3, 0xc2, 0xc7, 0x69, 0x5a, 0xf8, 0xe7, 0x34, 0x2d,
+	0xfc, 0xf6, 0x53, 0x3a, 0xf0, 0xf1, 0x53, 0x3a, 0xf0, 0x8f, 0x4f, 0xe9, 0xc0, 0xcf, 0xd9, 0xad,
+	0x90, 0x5e, 0x0a, 0xed, 0xb3, 0x28, 0xb3, 0xf5, 0xe8, 0x7f, 0x03, 0x00, 0x3c, 0xc2, 0x3e, 0x02,
+	0x94, 0x14, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1288,6 +1364,8 @@ type StorageClient interface {
 	ReadFilter(ctx context.Context, in *ReadFilterRequest, opts ...grpc.CallOption) (Storage_ReadFilterClient, error)
 	// ReadGroup performs a group operation at storage
 	ReadGroup(ctx context.Context, in *ReadGroupRequest, opts ...grpc.CallOption) (Storage_ReadGroupClient, error)
+	// ReadWindowAggregate performs a windowed aggregate operation at storage
+	ReadWindowAggregate(ctx context.Context, in *ReadWindowAggregateRequest, opts ...grpc.CallOption) (Storage_ReadWindowAggregateClient, error)
 	// TagKeys performs a read operation for tag keys
 	TagKeys(ctx context.Context, in *TagKeysRequest, opts ...grpc.CallOption) (Storage_TagKeysClient, error)
 	// TagValues performs a read operation for tag values
@@ -1368,8 +1446,40 @@ func (x *storageReadGroupClient) Recv() (*ReadResponse, error) {
 	return m, nil
 }
 
+func (c *storageClient) ReadWindowAggregate(ctx context.Context, in *ReadWindowAggregateRequest, opts ...grpc.CallOption) (Storage_ReadWindowAggregateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/influxdata.platform.storage.Storage/ReadWindowAggregate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageReadWindowAggregateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ReadWindowAggregateClient interface {
+	Recv() (*ReadResponse, error)
+	grpc.ClientStream
+}
+
+type storageReadWindowAggregateClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageReadWindowAggregateClient) Recv() (*ReadResponse, error) {
+	m := new(ReadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *storageClient) TagKeys(ctx context.Context, in *TagKeysRequest, opts ...grpc.CallOption) (Storage_TagKeysClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/influxdata.platform.storage.Storage/TagKeys", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[3], "/influxdata.platform.storage.Storage/TagKeys", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1401,7 +1511,7 @@ func (x *storageTagKeysClient) Recv() (*StringValuesResponse, error) {
 }
 
 func (c *storageClient) TagValues(ctx context.Context, in *TagValuesRequest, opts ...grpc.CallOption) (Storage_TagValuesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[3], "/influxdata.platform.storage.Storage/TagValues", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[4], "/influxdata.platform.storage.Storage/TagValues", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1447,6 +1557,8 @@ type StorageServer interface {
 	ReadFilter(*ReadFilterRequest, Storage_ReadFilterServer) error
 	// ReadGroup performs a group operation at storage
 	ReadGroup(*ReadGroupRequest, Storage_ReadGroupServer) error
+	// ReadWindowAggregate performs a windowed aggregate operation at storage
+	ReadWindowAggregate(*ReadWindowAggregateRequest, Storage_ReadWindowAggregateServer) error
 	// TagKeys performs a read operation for tag keys
 	TagKeys(*TagKeysRequest, Storage_TagKeysServer) error
 	// TagValues performs a read operation for tag values
@@ -1501,6 +1613,27 @@ func (x *storageReadGroupServer) Send(m *ReadResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Storage_ReadWindowAggregate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadWindowAggregateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ReadWindowAggregate(m, &storageReadWindowAggregateServer{stream})
+}
+
+type Storage_ReadWindowAggregateServer interface {
+	Send(*ReadResponse) error
+	grpc.ServerStream
+}
+
+type storageReadWindowAggregateServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageReadWindowAggregateServer) Send(m *ReadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Storage_TagKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(TagKeysRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1581,6 +1714,11 @@ var _Storage_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Storage_ReadGroup_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ReadWindowAggregate",
+			Handler:       _Storage_ReadWindowAggregate_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TagKeys",
 			Handler:       _Storage_TagKeys_Handler,
@@ -1638,6 +1776,16 @@ func (m *ReadFilterRequest) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n3
 	}
+	if m.Aggregate != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Aggregate.Size()))
+		n4, err := m.Aggregate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
 	return i, nil
 }
 
@@ -2393,6 +2541,72 @@ func (m *StringValuesResponse) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *ReadWindowAggregateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadWindowAggregateRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ReadSource != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.ReadSource.Size()))
+		n27, err := m.ReadSource.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
+	n28, err := m.Range.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n28
+	if m.Predicate != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
+		n29, err := m.Predicate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	if m.WindowEvery != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.WindowEvery))
+	}
+	if m.Offset != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Offset))
+	}
+	if m.Aggregate != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Aggregate.Size()))
+		n30, err := m.Aggregate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n30
+	}
+	return i, nil
+}
+
 func encodeVarintStorageCommon(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -2418,6 +2632,10 @@ func (m *ReadFilterRequest) Size() (n int) {
 		l = m.Predicate.Size()
 		n += 1 + l + sovStorageCommon(uint64(l))
 	}
+	if m.Aggregate != nil {
+		l = m.Aggregate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
 	return n
 }
 
@@ -2810,6 +3028,35 @@ func (m *StringValuesResponse) Size() (n int) {
 	return n
 }
 
+func (m *ReadWindowAggregateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReadSource != nil {
+		l = m.ReadSource.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	l = m.Range.Size()
+	n += 1 + l + sovStorageCommon(uint64(l))
+	if m.Predicate != nil {
+		l = m.Predicate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	if m.WindowEvery != 0 {
+		n += 1 + sovStorageCommon(uint64(m.WindowEvery))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovStorageCommon(uint64(m.Offset))
+	}
+	if m.Aggregate != nil {
+		l = m.Aggregate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	return n
+}
+
 func sovStorageCommon(x uint64) (n int) {
 	for {
 		n++
@@ -2957,6 +3204,42 @@ func (m *ReadFilterRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Aggregate == nil {
+				m.Aggregate = &Aggregate{}
+			}
+			if err := m.Aggregate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorageCommon(dAtA[iNdEx:])
@@ -5574,6 +5857,238 @@ func (m *StringValuesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ReadWindowAggregateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorageCommon
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReadWindowAggregateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReadWindowAggregateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadSource", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReadSource == nil {
+				m.ReadSource = &types.Any{}
+			}
+			if err := m.ReadSource.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Range.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Predicate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Predicate == nil {
+				m.Predicate = &Predicate{}
+			}
+			if err := m.Predicate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowEvery", wireType)
+			}
+			m.WindowEvery = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowEvery |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Aggregate == nil {
+				m.Aggregate = &Aggregate{}
+			}
+			if err := m.Aggregate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorageCommon(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipStorageCommon(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
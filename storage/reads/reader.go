@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/apache/arrow/go/arrow/array"
 	"github.com/gogo/protobuf/types"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/values"
@@ -41,6 +43,36 @@ func (r *storeReader) ReadFilter(ctx context.Context, spec influxdb.ReadFilterSp
 	}, nil
 }
 
+func (r *storeReader) ReadAggregate(ctx context.Context, spec influxdb.ReadAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	agg, err := determineAggregateMethod(spec.AggregateMethod)
+	if err != nil {
+		return nil, err
+	}
+	return &filterIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec.ReadFilterSpec,
+		agg:   agg,
+		cache: newTagsCache(0),
+		alloc: alloc,
+	}, nil
+}
+
+func (r *storeReader) ReadWindowAggregate(ctx context.Context, spec influxdb.ReadWindowAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	agg, err := determineAggregateMethod(spec.AggregateMethod)
+	if err != nil {
+		return nil, err
+	}
+	return &windowAggregateIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec,
+		agg:   agg,
+		cache: newTagsCache(0),
+		alloc: alloc,
+	}, nil
+}
+
 func (r *storeReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	return &groupIterator{
 		ctx:   ctx,
@@ -97,6 +129,7 @@ type filterIterator struct {
 	ctx   context.Context
 	s     Store
 	spec  influxdb.ReadFilterSpec
+	agg   datatypes.Aggregate_AggregateType
 	stats cursors.CursorStats
 	cache *tagsCache
 	alloc *memory.Allocator
@@ -131,6 +164,10 @@ func (fi *filterIterator) Do(f func(flux.Table) error) error {
 	req.Range.Start = int64(fi.spec.Bounds.Start)
 	req.Range.End = int64(fi.spec.Bounds.Stop)
 
+	if fi.agg != datatypes.AggregateTypeNone {
+		req.Aggregate = &datatypes.Aggregate{Type: fi.agg}
+	}
+
 	rs, err := fi.s.ReadFilter(fi.ctx, &req)
 	if err != nil {
 		return err
@@ -174,19 +211,19 @@ READ:
 		done := make(chan struct{})
 		switch typedCur := cur.(type) {
 		case cursors.IntegerArrayCursor:
-			cols, defs := determineTableColsForSeries(rs.Tags(), flux.TInt)
+			cols, defs := fi.determineTableCols(rs.Tags(), flux.TInt)
 			table = newIntegerTable(done, typedCur, bnds, key, cols, rs.Tags(), defs, fi.cache, fi.alloc)
 		case cursors.FloatArrayCursor:
-			cols, defs := determineTableColsForSeries(rs.Tags(), flux.TFloat)
+			cols, defs := fi.determineTableCols(rs.Tags(), flux.TFloat)
 			table = newFloatTable(done, typedCur, bnds, key, cols, rs.Tags(), defs, fi.cache, fi.alloc)
 		case cursors.UnsignedArrayCursor:
-			cols, defs := determineTableColsForSeries(rs.Tags(), flux.TUInt)
+			cols, defs := fi.determineTableCols(rs.Tags(), flux.TUInt)
 			table = newUnsignedTable(done, typedCur, bnds, key, cols, rs.Tags(), defs, fi.cache, fi.alloc)
 		case cursors.BooleanArrayCursor:
-			cols, defs := determineTableColsForSeries(rs.Tags(), flux.TBool)
+			cols, defs := fi.determineTableCols(rs.Tags(), flux.TBool)
 			table = newBooleanTable(done, typedCur, bnds, key, cols, rs.Tags(), defs, fi.cache, fi.alloc)
 		case cursors.StringArrayCursor:
-			cols, defs := determineTableColsForSeries(rs.Tags(), flux.TString)
+			cols, defs := fi.determineTableCols(rs.Tags(), flux.TString)
 			table = newStringTable(done, typedCur, bnds, key, cols, rs.Tags(), defs, fi.cache, fi.alloc)
 		default:
 			panic(fmt.Sprintf("unreachable: %T", typedCur))
@@ -208,15 +245,278 @@ READ:
 			}
 		}
 
-		stats := table.Statistics()
-		fi.stats.ScannedValues += stats.ScannedValues
-		fi.stats.ScannedBytes += stats.ScannedBytes
+		fi.stats.Add(table.Statistics())
 		table.Close()
 		table = nil
 	}
 	return rs.Err()
 }
 
+// determineTableCols returns the columns of the table of a series,
+// which has no _time column when the values are aggregated.
+func (fi *filterIterator) determineTableCols(tags models.Tags, typ flux.ColType) ([]flux.ColMeta, [][]byte) {
+	switch fi.agg {
+	case datatypes.AggregateTypeSum, datatypes.AggregateTypeCount, datatypes.AggregateTypeMean:
+		return determineAggregateTableColsForSeries(tags, typ)
+	default:
+		return determineTableColsForSeries(tags, typ)
+	}
+}
+
+// windowAggregateIterator reads the aggregates of the windows of each
+// series, which are computed by storage. It returns a table for each
+// window, as the aggregate of the tables of the window function of Flux.
+type windowAggregateIterator struct {
+	ctx   context.Context
+	s     Store
+	spec  influxdb.ReadWindowAggregateSpec
+	agg   datatypes.Aggregate_AggregateType
+	stats cursors.CursorStats
+	cache *tagsCache
+	alloc *memory.Allocator
+}
+
+func (wi *windowAggregateIterator) Statistics() cursors.CursorStats { return wi.stats }
+
+func (wi *windowAggregateIterator) Do(f func(flux.Table) error) error {
+	src := wi.s.GetSource(
+		uint64(wi.spec.OrganizationID),
+		uint64(wi.spec.BucketID),
+	)
+
+	// Setup read request
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return err
+	}
+
+	var predicate *datatypes.Predicate
+	if wi.spec.Predicate != nil {
+		p, err := toStoragePredicate(wi.spec.Predicate)
+		if err != nil {
+			return err
+		}
+		predicate = p
+	}
+
+	// The window normalizes the offset as storage expects it.
+	w, err := execute.NewWindow(wi.spec.WindowEvery, wi.spec.WindowEvery, wi.spec.Offset)
+	if err != nil {
+		return err
+	}
+
+	var req datatypes.ReadWindowAggregateRequest
+	req.ReadSource = any
+	req.Predicate = predicate
+	req.Range.Start = int64(wi.spec.Bounds.Start)
+	req.Range.End = int64(wi.spec.Bounds.Stop)
+	req.WindowEvery = int64(w.Every.Duration())
+	req.Offset = int64(w.Offset.Duration())
+	req.Aggregate = &datatypes.Aggregate{Type: wi.agg}
+
+	rs, err := wi.s.ReadWindowAggregate(wi.ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+
+	// The windows without points are created within
+	// the bounds as by the window function of Flux.
+	var empty []execute.Bounds
+	if wi.spec.CreateEmpty {
+		empty = w.GetOverlappingBounds(wi.spec.Bounds)
+		for i := range empty {
+			empty[i] = wi.spec.Bounds.Intersect(empty[i])
+		}
+	}
+	return wi.handleRead(f, rs, window{every: req.WindowEvery, offset: req.Offset}, empty)
+}
+
+func (wi *windowAggregateIterator) handleRead(f func(flux.Table) error, rs ResultSet, w window, empty []execute.Bounds) error {
+	defer func() {
+		rs.Close()
+		wi.cache.Release()
+	}()
+
+READ:
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		typ, aggs := readWindowAggregates(cur, wi.alloc)
+		wi.stats.Add(cur.Stats())
+		wi.stats.ScannedSeries++
+		cur.Close()
+
+		// As the tables of a range, a series without points has no windows.
+		ts := aggs.timestamps()
+		if len(ts) == 0 {
+			continue
+		}
+
+		// Merge the windows of the points and the windows without points,
+		// which are both in ascending order.
+		tags := rs.Tags()
+		for i, j := 0, 0; i < len(ts) || j < len(empty); {
+			var tbl flux.Table
+			if i < len(ts) {
+				bnds := wi.windowBounds(w, ts[i])
+				for j < len(empty) && empty[j].Start < bnds.Start {
+					if err := f(wi.newTable(tags, empty[j], typ, aggs, -1)); err != nil {
+						return err
+					}
+					j++
+				}
+				if j < len(empty) && empty[j] == bnds {
+					j++
+				}
+				tbl = wi.newTable(tags, bnds, typ, aggs, i)
+				i++
+			} else {
+				tbl = wi.newTable(tags, empty[j], typ, aggs, -1)
+				j++
+			}
+			if err := f(tbl); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-wi.ctx.Done():
+			break READ
+		default:
+		}
+	}
+	return rs.Err()
+}
+
+// windowBounds returns the bounds of the window of a point
+// read from storage, which are clipped to the bounds of the read.
+func (wi *windowAggregateIterator) windowBounds(w window, t int64) execute.Bounds {
+	start, stop, _ := w.bounds(t)
+	return wi.spec.Bounds.Intersect(execute.Bounds{
+		Start: execute.Time(start),
+		Stop:  execute.Time(stop),
+	})
+}
+
+// newTable returns the table of the aggregate of a window of a series, with
+// the point i of aggs or, if i is negative, the aggregate of an empty window:
+// a selector has no rows, count is zero and any other aggregate is null.
+//
+// The columns of the table are built from the arrays read from the cursor
+// of the series, and its tag columns are shared through the tags cache.
+func (wi *windowAggregateIterator) newTable(tags models.Tags, bnds execute.Bounds, typ flux.ColType, aggs windowAggregates, i int) flux.Table {
+	var (
+		cols     []flux.ColMeta
+		defs     [][]byte
+		selector = isSelector(wi.agg)
+	)
+	if selector {
+		cols, defs = determineTableColsForSeries(tags, typ)
+	} else {
+		cols, defs = determineAggregateTableColsForSeries(tags, typ)
+	}
+
+	t := &windowTable{table: newTable(nil, bnds, defaultGroupKeyForSeries(tags, bnds), cols, defs, wi.cache, wi.alloc)}
+	t.readTags(tags)
+	if i < 0 && selector {
+		return t
+	}
+
+	cr := t.allocateBuffer(1)
+	switch {
+	case i >= 0:
+		if t.timeIdx >= 0 {
+			cr.cols[t.timeIdx] = arrow.NewInt(aggs.timestamps()[i:i+1], wi.alloc)
+		}
+		cr.cols[t.valueIdx] = aggs.values(i, i+1)
+	case wi.agg == datatypes.AggregateTypeCount:
+		cr.cols[t.valueIdx] = arrow.NewInt([]int64{0}, wi.alloc)
+	default:
+		cr.cols[t.valueIdx] = newNullArray(typ, wi.alloc)
+	}
+	t.appendTags(cr)
+	// the bounds of the windows differ, so they are not shared through the
+	// tags cache as the bounds of the tables of a range are.
+	cr.cols[startColIdx] = arrow.NewInt([]int64{int64(bnds.Start)}, wi.alloc)
+	cr.cols[stopColIdx] = arrow.NewInt([]int64{int64(bnds.Stop)}, wi.alloc)
+	return t
+}
+
+// windowTable is the table of the aggregate of a window of a series, which
+// has at most one row.
+type windowTable struct {
+	table
+}
+
+func (t *windowTable) Do(f func(flux.ColReader) error) error {
+	return t.do(f, func() bool { return false })
+}
+
+// windowAggregates are the points of the aggregates of the windows of a series.
+type windowAggregates interface {
+	timestamps() []int64
+	// values returns an array of the values of the points i to j.
+	values(i, j int) array.Interface
+}
+
+// readWindowAggregates reads the points of the aggregates of the windows of a series.
+func readWindowAggregates(cur cursors.Cursor, alloc *memory.Allocator) (flux.ColType, windowAggregates) {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return flux.TFloat, readFloatWindowAggregates(cur, alloc)
+	case cursors.IntegerArrayCursor:
+		return flux.TInt, readIntegerWindowAggregates(cur, alloc)
+	case cursors.UnsignedArrayCursor:
+		return flux.TUInt, readUnsignedWindowAggregates(cur, alloc)
+	case cursors.BooleanArrayCursor:
+		return flux.TBool, readBooleanWindowAggregates(cur, alloc)
+	case cursors.StringArrayCursor:
+		return flux.TString, readStringWindowAggregates(cur, alloc)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+// newNullArray returns an array of a single null value of the type.
+func newNullArray(typ flux.ColType, alloc *memory.Allocator) array.Interface {
+	var b array.Builder
+	switch typ {
+	case flux.TFloat:
+		b = arrow.NewFloatBuilder(alloc)
+	case flux.TInt:
+		b = arrow.NewIntBuilder(alloc)
+	case flux.TUInt:
+		b = arrow.NewUintBuilder(alloc)
+	case flux.TBool:
+		b = arrow.NewBoolBuilder(alloc)
+	case flux.TString:
+		b = arrow.NewStringBuilder(alloc)
+	default:
+		panic(fmt.Sprintf("unreachable: %v", typ))
+	}
+	defer b.Release()
+	b.AppendNull()
+	return b.NewArray()
+}
+
+// isSelector reports whether the aggregate selects a point of the series.
+func isSelector(agg datatypes.Aggregate_AggregateType) bool {
+	switch agg {
+	case datatypes.AggregateTypeMin, datatypes.AggregateTypeMax, datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+		return true
+	default:
+		return false
+	}
+}
+
 type groupIterator struct {
 	ctx   context.Context
 	s     Store
@@ -352,9 +652,7 @@ READ:
 			break READ
 		}
 
-		stats := table.Statistics()
-		gi.stats.ScannedValues += stats.ScannedValues
-		gi.stats.ScannedBytes += stats.ScannedBytes
+		gi.stats.Add(table.Statistics())
 		table.Close()
 		table = nil
 
@@ -420,6 +718,34 @@ func determineTableColsForSeries(tags models.Tags, typ flux.ColType) ([]flux.Col
 	return cols, defs
 }
 
+// determineAggregateTableColsForSeries returns the columns of the aggregate
+// of a series, which are the columns of its group key followed by the _value
+// column as in the tables produced by the aggregates of Flux.
+func determineAggregateTableColsForSeries(tags models.Tags, typ flux.ColType) ([]flux.ColMeta, [][]byte) {
+	cols := make([]flux.ColMeta, 3+len(tags))
+	defs := make([][]byte, 3+len(tags))
+	cols[startColIdx] = flux.ColMeta{
+		Label: execute.DefaultStartColLabel,
+		Type:  flux.TTime,
+	}
+	cols[stopColIdx] = flux.ColMeta{
+		Label: execute.DefaultStopColLabel,
+		Type:  flux.TTime,
+	}
+	for j, tag := range tags {
+		cols[2+j] = flux.ColMeta{
+			Label: string(tag.Key),
+			Type:  flux.TString,
+		}
+		defs[2+j] = []byte("")
+	}
+	cols[len(cols)-1] = flux.ColMeta{
+		Label: execute.DefaultValueColLabel,
+		Type:  typ,
+	}
+	return cols, defs
+}
+
 func defaultGroupKeyForSeries(tags models.Tags, bnds execute.Bounds) flux.GroupKey {
 	cols := make([]flux.ColMeta, 2, len(tags)+2)
 	vs := make([]values.Value, 2, len(tags)+2)
@@ -65,6 +65,7 @@ func NewResponseWriter(stream ResponseStream, hints datatypes.HintFlags) *Respon
 func (w *ResponseWriter) WrittenN() int { return w.vc }
 
 func (w *ResponseWriter) WriteResultSet(rs ResultSet) error {
+	var series int
 	for rs.Next() {
 		cur := rs.Cursor()
 		if cur == nil {
@@ -78,12 +79,12 @@ func (w *ResponseWriter) WriteResultSet(rs ResultSet) error {
 			cur.Close()
 			return w.err
 		}
+		series++
 	}
 
 	stats := rs.Stats()
-	w.stream.SetTrailer(metadata.Pairs(
-		"scanned-bytes", fmt.Sprint(stats.ScannedBytes),
-		"scanned-values", fmt.Sprint(stats.ScannedValues)))
+	stats.ScannedSeries = series
+	w.stream.SetTrailer(statsTrailer(stats))
 
 	return nil
 }
@@ -107,18 +108,28 @@ func (w *ResponseWriter) WriteGroupResultSet(rs GroupResultSet) error {
 				return w.err
 			}
 			stats.Add(gc.Stats())
+			stats.ScannedSeries++
 		}
 		gc.Close()
 		gc = rs.Next()
 	}
 
-	w.stream.SetTrailer(metadata.Pairs(
-		"scanned-bytes", fmt.Sprint(stats.ScannedBytes),
-		"scanned-values", fmt.Sprint(stats.ScannedValues)))
+	w.stream.SetTrailer(statsTrailer(stats))
 
 	return nil
 }
 
+// statsTrailer returns the trailer metadata of the stats of a response,
+// which are read by StorageReadClient.
+func statsTrailer(stats cursors.CursorStats) metadata.MD {
+	return metadata.Pairs(
+		"scanned-bytes", fmt.Sprint(stats.ScannedBytes),
+		"scanned-values", fmt.Sprint(stats.ScannedValues),
+		"scanned-series", fmt.Sprint(stats.ScannedSeries),
+		"cache-values", fmt.Sprint(stats.CacheValues),
+		"decoded-blocks", fmt.Sprint(stats.DecodedBlocks))
+}
+
 func (w *ResponseWriter) Err() error { return w.err }
 
 func (w *ResponseWriter) getGroupFrame(keys, partitionKey [][]byte) *datatypes.ReadResponse_Frame_Group {
@@ -72,6 +72,9 @@ func TestResponseWriter_WriteResultSet_Stats(t *testing.T) {
 	if !reflect.DeepEqual(gotTrailer.Get("scanned-bytes"), []string{fmt.Sprint(scannedBytes)}) {
 		t.Errorf("expected scanned-bytes '%v' but got '%v'", []string{fmt.Sprint(scannedBytes)}, gotTrailer.Get("scanned-bytes"))
 	}
+	if !reflect.DeepEqual(gotTrailer.Get("scanned-series"), []string{"1"}) {
+		t.Errorf("expected scanned-series '%v' but got '%v'", []string{"1"}, gotTrailer.Get("scanned-series"))
+	}
 }
 
 func TestResponseWriter_WriteGroupResultSet_Stats(t *testing.T) {
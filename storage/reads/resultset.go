@@ -2,6 +2,7 @@ package reads
 
 import (
 	"context"
+	"errors"
 	"math"
 
 	"github.com/influxdata/influxdb/models"
@@ -15,22 +16,51 @@ type multiShardCursors interface {
 }
 
 type resultSet struct {
-	ctx context.Context
-	agg *datatypes.Aggregate
-	cur SeriesCursor
-	row SeriesRow
-	mb  multiShardCursors
+	ctx    context.Context
+	agg    *datatypes.Aggregate
+	window *window
+	cur    SeriesCursor
+	row    SeriesRow
+	mb     multiShardCursors
+	err    error
 }
 
 func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest, cur SeriesCursor) ResultSet {
 	return &resultSet{
 		ctx: ctx,
+		agg: req.Aggregate,
 		cur: cur,
-		mb:  newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, true, math.MaxInt64),
+		mb:  newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, isAscending(req.Aggregate), aggregateLimit(req.Aggregate)),
 	}
 }
 
-func (r *resultSet) Err() error { return nil }
+// NewWindowAggregateResultSet returns a result set with the aggregates
+// of the windows of each series, as described by the request.
+func NewWindowAggregateResultSet(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, cur SeriesCursor) (ResultSet, error) {
+	if req.WindowEvery <= 0 {
+		return nil, errors.New("window every must be positive")
+	}
+	if req.Offset < 0 || req.Offset > req.WindowEvery {
+		return nil, errors.New("window offset must be between zero and window every")
+	}
+	if req.Aggregate == nil || req.Aggregate.Type == datatypes.AggregateTypeNone {
+		return nil, errors.New("missing window aggregate")
+	}
+	return &resultSet{
+		ctx:    ctx,
+		agg:    req.Aggregate,
+		window: &window{every: req.WindowEvery, offset: req.Offset},
+		cur:    cur,
+		mb:     newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, true, math.MaxInt64),
+	}, nil
+}
+
+func (r *resultSet) Err() error {
+	if r == nil {
+		return nil
+	}
+	return r.err
+}
 
 // Close closes the result set. Close is idempotent.
 func (r *resultSet) Close() {
@@ -43,7 +73,7 @@ func (r *resultSet) Close() {
 
 // Next returns true if there are more results available.
 func (r *resultSet) Next() bool {
-	if r == nil {
+	if r == nil || r.err != nil {
 		return false
 	}
 
@@ -57,12 +87,26 @@ func (r *resultSet) Next() bool {
 	return true
 }
 
+// Cursor returns the cursor of the current series, or nil if the series has
+// no points. If the aggregate of the result set does not support the type of
+// the series, nil is returned and the result set ends with an error.
 func (r *resultSet) Cursor() cursors.Cursor {
 	cur := r.mb.createCursor(r.row)
-	if r.agg != nil {
-		cur = r.mb.newAggregateCursor(r.ctx, r.agg, cur)
+	if cur == nil || r.agg == nil {
+		return cur
+	}
+
+	var agg cursors.Cursor
+	if r.window != nil {
+		agg = newWindowAggregateArrayCursor(r.ctx, r.agg, *r.window, cur)
+	} else {
+		agg = r.mb.newAggregateCursor(r.ctx, r.agg, cur)
+	}
+	if agg == nil {
+		r.err = errUnsupportedAggregate(r.agg, cur)
+		cur.Close()
 	}
-	return cur
+	return agg
 }
 
 func (r *resultSet) Tags() models.Tags {
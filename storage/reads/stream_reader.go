@@ -72,19 +72,20 @@ func (rc *StorageReadClient) Recv() (res *datatypes.ReadResponse, err error) {
 }
 
 func (rc *StorageReadClient) Stats() (stats cursors.CursorStats) {
-	for _, s := range rc.trailer.Get("scanned-bytes") {
-		v, err := strconv.Atoi(s)
-		if err != nil {
-			continue
+	for key, v := range map[string]*int{
+		"scanned-bytes":  &stats.ScannedBytes,
+		"scanned-values": &stats.ScannedValues,
+		"scanned-series": &stats.ScannedSeries,
+		"cache-values":   &stats.CacheValues,
+		"decoded-blocks": &stats.DecodedBlocks,
+	} {
+		for _, s := range rc.trailer.Get(key) {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				continue
+			}
+			*v += n
 		}
-		stats.ScannedBytes += v
-	}
-	for _, s := range rc.trailer.Get("scanned-values") {
-		v, err := strconv.Atoi(s)
-		if err != nil {
-			continue
-		}
-		stats.ScannedValues += v
 	}
 	return stats
 }
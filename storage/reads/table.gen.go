@@ -9,6 +9,7 @@ package reads
 import (
 	"sync"
 
+	"github.com/apache/arrow/go/arrow/array"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/execute"
@@ -70,6 +71,9 @@ func (t *floatTable) Statistics() cursors.CursorStats {
 	return cursors.CursorStats{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
+		CacheValues:   cs.CacheValues,
+		DecodedBlocks: cs.DecodedBlocks,
+		ScannedSeries: 1,
 	}
 }
 
@@ -89,8 +93,10 @@ func (t *floatTable) advance() bool {
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
@@ -103,6 +109,9 @@ type floatGroupTable struct {
 	mu  sync.Mutex
 	gc  GroupCursor
 	cur cursors.FloatArrayCursor
+
+	// stats of the cursors of the series which have been read.
+	stats cursors.CursorStats
 }
 
 func newFloatGroupTable(
@@ -162,14 +171,20 @@ RETRY:
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
 }
 
 func (t *floatGroupTable) advanceCursor() bool {
+	t.mu.Lock()
+	t.stats.Add(t.cur.Stats())
+	t.stats.ScannedSeries++
+	t.mu.Unlock()
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -193,14 +208,38 @@ func (t *floatGroupTable) advanceCursor() bool {
 }
 
 func (t *floatGroupTable) Statistics() cursors.CursorStats {
-	if t.cur == nil {
-		return cursors.CursorStats{}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.stats
+	if t.cur != nil {
+		cs.Add(t.cur.Stats())
+		cs.ScannedSeries++
 	}
-	cs := t.cur.Stats()
-	return cursors.CursorStats{
-		ScannedValues: cs.ScannedValues,
-		ScannedBytes:  cs.ScannedBytes,
+	return cs
+}
+
+// window aggregates
+
+// floatWindowAggregates are the points of the aggregates of the windows
+// of a series, as read from its cursor.
+type floatWindowAggregates struct {
+	cursors.FloatArray
+	alloc *memory.Allocator
+}
+
+func readFloatWindowAggregates(cur cursors.FloatArrayCursor, alloc *memory.Allocator) *floatWindowAggregates {
+	w := &floatWindowAggregates{alloc: alloc}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		w.Timestamps = append(w.Timestamps, a.Timestamps...)
+		w.Values = append(w.Values, a.Values...)
 	}
+	return w
+}
+
+func (w *floatWindowAggregates) timestamps() []int64 { return w.Timestamps }
+
+func (w *floatWindowAggregates) values(i, j int) array.Interface {
+	return w.toArrowBuffer(w.Values[i:j])
 }
 
 //
@@ -255,6 +294,9 @@ func (t *integerTable) Statistics() cursors.CursorStats {
 	return cursors.CursorStats{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
+		CacheValues:   cs.CacheValues,
+		DecodedBlocks: cs.DecodedBlocks,
+		ScannedSeries: 1,
 	}
 }
 
@@ -274,8 +316,10 @@ func (t *integerTable) advance() bool {
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
@@ -288,6 +332,9 @@ type integerGroupTable struct {
 	mu  sync.Mutex
 	gc  GroupCursor
 	cur cursors.IntegerArrayCursor
+
+	// stats of the cursors of the series which have been read.
+	stats cursors.CursorStats
 }
 
 func newIntegerGroupTable(
@@ -347,14 +394,20 @@ RETRY:
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
 }
 
 func (t *integerGroupTable) advanceCursor() bool {
+	t.mu.Lock()
+	t.stats.Add(t.cur.Stats())
+	t.stats.ScannedSeries++
+	t.mu.Unlock()
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -378,14 +431,38 @@ func (t *integerGroupTable) advanceCursor() bool {
 }
 
 func (t *integerGroupTable) Statistics() cursors.CursorStats {
-	if t.cur == nil {
-		return cursors.CursorStats{}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.stats
+	if t.cur != nil {
+		cs.Add(t.cur.Stats())
+		cs.ScannedSeries++
 	}
-	cs := t.cur.Stats()
-	return cursors.CursorStats{
-		ScannedValues: cs.ScannedValues,
-		ScannedBytes:  cs.ScannedBytes,
+	return cs
+}
+
+// window aggregates
+
+// integerWindowAggregates are the points of the aggregates of the windows
+// of a series, as read from its cursor.
+type integerWindowAggregates struct {
+	cursors.IntegerArray
+	alloc *memory.Allocator
+}
+
+func readIntegerWindowAggregates(cur cursors.IntegerArrayCursor, alloc *memory.Allocator) *integerWindowAggregates {
+	w := &integerWindowAggregates{alloc: alloc}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		w.Timestamps = append(w.Timestamps, a.Timestamps...)
+		w.Values = append(w.Values, a.Values...)
 	}
+	return w
+}
+
+func (w *integerWindowAggregates) timestamps() []int64 { return w.Timestamps }
+
+func (w *integerWindowAggregates) values(i, j int) array.Interface {
+	return w.toArrowBuffer(w.Values[i:j])
 }
 
 //
@@ -440,6 +517,9 @@ func (t *unsignedTable) Statistics() cursors.CursorStats {
 	return cursors.CursorStats{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
+		CacheValues:   cs.CacheValues,
+		DecodedBlocks: cs.DecodedBlocks,
+		ScannedSeries: 1,
 	}
 }
 
@@ -459,8 +539,10 @@ func (t *unsignedTable) advance() bool {
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
@@ -473,6 +555,9 @@ type unsignedGroupTable struct {
 	mu  sync.Mutex
 	gc  GroupCursor
 	cur cursors.UnsignedArrayCursor
+
+	// stats of the cursors of the series which have been read.
+	stats cursors.CursorStats
 }
 
 func newUnsignedGroupTable(
@@ -532,14 +617,20 @@ RETRY:
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
 }
 
 func (t *unsignedGroupTable) advanceCursor() bool {
+	t.mu.Lock()
+	t.stats.Add(t.cur.Stats())
+	t.stats.ScannedSeries++
+	t.mu.Unlock()
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -563,14 +654,38 @@ func (t *unsignedGroupTable) advanceCursor() bool {
 }
 
 func (t *unsignedGroupTable) Statistics() cursors.CursorStats {
-	if t.cur == nil {
-		return cursors.CursorStats{}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.stats
+	if t.cur != nil {
+		cs.Add(t.cur.Stats())
+		cs.ScannedSeries++
 	}
-	cs := t.cur.Stats()
-	return cursors.CursorStats{
-		ScannedValues: cs.ScannedValues,
-		ScannedBytes:  cs.ScannedBytes,
+	return cs
+}
+
+// window aggregates
+
+// unsignedWindowAggregates are the points of the aggregates of the windows
+// of a series, as read from its cursor.
+type unsignedWindowAggregates struct {
+	cursors.UnsignedArray
+	alloc *memory.Allocator
+}
+
+func readUnsignedWindowAggregates(cur cursors.UnsignedArrayCursor, alloc *memory.Allocator) *unsignedWindowAggregates {
+	w := &unsignedWindowAggregates{alloc: alloc}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		w.Timestamps = append(w.Timestamps, a.Timestamps...)
+		w.Values = append(w.Values, a.Values...)
 	}
+	return w
+}
+
+func (w *unsignedWindowAggregates) timestamps() []int64 { return w.Timestamps }
+
+func (w *unsignedWindowAggregates) values(i, j int) array.Interface {
+	return w.toArrowBuffer(w.Values[i:j])
 }
 
 //
@@ -625,6 +740,9 @@ func (t *stringTable) Statistics() cursors.CursorStats {
 	return cursors.CursorStats{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
+		CacheValues:   cs.CacheValues,
+		DecodedBlocks: cs.DecodedBlocks,
+		ScannedSeries: 1,
 	}
 }
 
@@ -644,8 +762,10 @@ func (t *stringTable) advance() bool {
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
@@ -658,6 +778,9 @@ type stringGroupTable struct {
 	mu  sync.Mutex
 	gc  GroupCursor
 	cur cursors.StringArrayCursor
+
+	// stats of the cursors of the series which have been read.
+	stats cursors.CursorStats
 }
 
 func newStringGroupTable(
@@ -717,14 +840,20 @@ RETRY:
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
 }
 
 func (t *stringGroupTable) advanceCursor() bool {
+	t.mu.Lock()
+	t.stats.Add(t.cur.Stats())
+	t.stats.ScannedSeries++
+	t.mu.Unlock()
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -748,14 +877,38 @@ func (t *stringGroupTable) advanceCursor() bool {
 }
 
 func (t *stringGroupTable) Statistics() cursors.CursorStats {
-	if t.cur == nil {
-		return cursors.CursorStats{}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.stats
+	if t.cur != nil {
+		cs.Add(t.cur.Stats())
+		cs.ScannedSeries++
 	}
-	cs := t.cur.Stats()
-	return cursors.CursorStats{
-		ScannedValues: cs.ScannedValues,
-		ScannedBytes:  cs.ScannedBytes,
+	return cs
+}
+
+// window aggregates
+
+// stringWindowAggregates are the points of the aggregates of the windows
+// of a series, as read from its cursor.
+type stringWindowAggregates struct {
+	cursors.StringArray
+	alloc *memory.Allocator
+}
+
+func readStringWindowAggregates(cur cursors.StringArrayCursor, alloc *memory.Allocator) *stringWindowAggregates {
+	w := &stringWindowAggregates{alloc: alloc}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		w.Timestamps = append(w.Timestamps, a.Timestamps...)
+		w.Values = append(w.Values, a.Values...)
 	}
+	return w
+}
+
+func (w *stringWindowAggregates) timestamps() []int64 { return w.Timestamps }
+
+func (w *stringWindowAggregates) values(i, j int) array.Interface {
+	return w.toArrowBuffer(w.Values[i:j])
 }
 
 //
@@ -810,6 +963,9 @@ func (t *booleanTable) Statistics() cursors.CursorStats {
 	return cursors.CursorStats{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
+		CacheValues:   cs.CacheValues,
+		DecodedBlocks: cs.DecodedBlocks,
+		ScannedSeries: 1,
 	}
 }
 
@@ -829,8 +985,10 @@ func (t *booleanTable) advance() bool {
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
@@ -843,6 +1001,9 @@ type booleanGroupTable struct {
 	mu  sync.Mutex
 	gc  GroupCursor
 	cur cursors.BooleanArrayCursor
+
+	// stats of the cursors of the series which have been read.
+	stats cursors.CursorStats
 }
 
 func newBooleanGroupTable(
@@ -902,14 +1063,20 @@ RETRY:
 	// because the references were retained, then we will
 	// allocate a new buffer.
 	cr := t.allocateBuffer(l)
-	cr.cols[timeColIdx] = arrow.NewInt(a.Timestamps, t.alloc)
-	cr.cols[valueColIdx] = t.toArrowBuffer(a.Values)
+	if t.timeIdx >= 0 {
+		cr.cols[t.timeIdx] = arrow.NewInt(a.Timestamps, t.alloc)
+	}
+	cr.cols[t.valueIdx] = t.toArrowBuffer(a.Values)
 	t.appendTags(cr)
 	t.appendBounds(cr)
 	return true
 }
 
 func (t *booleanGroupTable) advanceCursor() bool {
+	t.mu.Lock()
+	t.stats.Add(t.cur.Stats())
+	t.stats.ScannedSeries++
+	t.mu.Unlock()
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -933,12 +1100,36 @@ func (t *booleanGroupTable) advanceCursor() bool {
 }
 
 func (t *booleanGroupTable) Statistics() cursors.CursorStats {
-	if t.cur == nil {
-		return cursors.CursorStats{}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.stats
+	if t.cur != nil {
+		cs.Add(t.cur.Stats())
+		cs.ScannedSeries++
 	}
-	cs := t.cur.Stats()
-	return cursors.CursorStats{
-		ScannedValues: cs.ScannedValues,
-		ScannedBytes:  cs.ScannedBytes,
+	return cs
+}
+
+// window aggregates
+
+// booleanWindowAggregates are the points of the aggregates of the windows
+// of a series, as read from its cursor.
+type booleanWindowAggregates struct {
+	cursors.BooleanArray
+	alloc *memory.Allocator
+}
+
+func readBooleanWindowAggregates(cur cursors.BooleanArrayCursor, alloc *memory.Allocator) *booleanWindowAggregates {
+	w := &booleanWindowAggregates{alloc: alloc}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		w.Timestamps = append(w.Timestamps, a.Timestamps...)
+		w.Values = append(w.Values, a.Values...)
 	}
+	return w
+}
+
+func (w *booleanWindowAggregates) timestamps() []int64 { return w.Timestamps }
+
+func (w *booleanWindowAggregates) values(i, j int) array.Interface {
+	return w.toArrowBuffer(w.Values[i:j])
 }
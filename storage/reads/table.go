@@ -19,6 +19,10 @@ type table struct {
 	key    flux.GroupKey
 	cols   []flux.ColMeta
 
+	// timeIdx and valueIdx are the indexes of the _time and _value
+	// columns. The _time column is absent from the tables of aggregates.
+	timeIdx, valueIdx int
+
 	// cache of the tags on the current series.
 	// len(tags) == len(colMeta)
 	tags [][]byte
@@ -53,6 +57,9 @@ func newTable(
 		cols:   cols,
 		cache:  cache,
 		alloc:  alloc,
+
+		timeIdx:  execute.ColIdx(execute.DefaultTimeColLabel, cols),
+		valueIdx: execute.ColIdx(execute.DefaultValueColLabel, cols),
 	}
 }
 
@@ -224,27 +231,42 @@ func (t *floatTable) toArrowBuffer(vs []float64) *array.Float64 {
 func (t *floatGroupTable) toArrowBuffer(vs []float64) *array.Float64 {
 	return arrow.NewFloat(vs, t.alloc)
 }
+func (w *floatWindowAggregates) toArrowBuffer(vs []float64) *array.Float64 {
+	return arrow.NewFloat(vs, w.alloc)
+}
 func (t *integerTable) toArrowBuffer(vs []int64) *array.Int64 {
 	return arrow.NewInt(vs, t.alloc)
 }
 func (t *integerGroupTable) toArrowBuffer(vs []int64) *array.Int64 {
 	return arrow.NewInt(vs, t.alloc)
 }
+func (w *integerWindowAggregates) toArrowBuffer(vs []int64) *array.Int64 {
+	return arrow.NewInt(vs, w.alloc)
+}
 func (t *unsignedTable) toArrowBuffer(vs []uint64) *array.Uint64 {
 	return arrow.NewUint(vs, t.alloc)
 }
 func (t *unsignedGroupTable) toArrowBuffer(vs []uint64) *array.Uint64 {
 	return arrow.NewUint(vs, t.alloc)
 }
+func (w *unsignedWindowAggregates) toArrowBuffer(vs []uint64) *array.Uint64 {
+	return arrow.NewUint(vs, w.alloc)
+}
 func (t *stringTable) toArrowBuffer(vs []string) *array.Binary {
 	return arrow.NewString(vs, t.alloc)
 }
 func (t *stringGroupTable) toArrowBuffer(vs []string) *array.Binary {
 	return arrow.NewString(vs, t.alloc)
 }
+func (w *stringWindowAggregates) toArrowBuffer(vs []string) *array.Binary {
+	return arrow.NewString(vs, w.alloc)
+}
 func (t *booleanTable) toArrowBuffer(vs []bool) *array.Boolean {
 	return arrow.NewBool(vs, t.alloc)
 }
 func (t *booleanGroupTable) toArrowBuffer(vs []bool) *array.Boolean {
 	return arrow.NewBool(vs, t.alloc)
 }
+func (w *booleanWindowAggregates) toArrowBuffer(vs []bool) *array.Boolean {
+	return arrow.NewBool(vs, w.alloc)
+}
@@ -0,0 +1,232 @@
+package readservice
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	queryLogMeasurement = "queries"
+
+	queryField           = "query"
+	userIDField          = "userID"
+	authorizationIDField = "authorizationID"
+	durationField        = "duration"
+	responseSizeField    = "responseSize"
+	errorField           = "error"
+	remoteIPField        = "remoteIP"
+	sourceField          = "source"
+	traceIDField         = "traceID"
+
+	statusTag = "status"
+)
+
+// The query logs are written in batches of queryLogBatchSize points, or
+// after queryLogBatchTimeout. Up to queryLogBatchPending points are buffered
+// before the logs of further queries are dropped.
+const (
+	queryLogBatchSize    = 1000
+	queryLogBatchPending = 10000
+	queryLogBatchTimeout = time.Second
+)
+
+var _ query.Logger = (*QueryLogger)(nil)
+
+// QueryLogger is a query.Logger which writes the log of each query as a point
+// into the queries system bucket of the organization of the query, so that
+// the query history of an organization can itself be queried.
+//
+// The points are buffered and written in batches in the background, so that
+// the log of a query does not wait on a write. The queries system bucket of
+// each organization is looked up once.
+type QueryLogger struct {
+	pw      storage.PointsWriter
+	buckets influxdb.BucketService
+	batcher *storage.PointBatcher
+
+	mu        sync.Mutex
+	bucketIDs map[influxdb.ID]influxdb.ID // queries system bucket of each organization
+	closing   chan struct{}
+	wg        sync.WaitGroup
+
+	log *zap.Logger
+}
+
+// NewQueryLogger returns a QueryLogger which writes the query logs via pw
+// once it is opened.
+func NewQueryLogger(log *zap.Logger, pw storage.PointsWriter, buckets influxdb.BucketService) *QueryLogger {
+	return &QueryLogger{
+		pw:        pw,
+		buckets:   buckets,
+		batcher:   storage.NewPointBatcher(queryLogBatchSize, queryLogBatchPending, queryLogBatchTimeout),
+		bucketIDs: make(map[influxdb.ID]influxdb.ID),
+		log:       log,
+	}
+}
+
+// Open starts writing the query logs in the background.
+func (l *QueryLogger) Open() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closing != nil {
+		return errors.New("query logger already open")
+	}
+
+	l.closing = make(chan struct{})
+	l.batcher.Start()
+	l.wg.Add(1)
+	go l.processBatches(l.closing)
+	return nil
+}
+
+// Close writes the query logs which are still buffered and stops writing
+// them.
+func (l *QueryLogger) Close() error {
+	l.mu.Lock()
+	closing := l.closing
+	l.closing = nil
+	l.mu.Unlock()
+	if closing == nil {
+		return nil
+	}
+
+	// the batch writer consumes the last batch emitted by the batcher.
+	l.batcher.Stop()
+	close(closing)
+	l.wg.Wait()
+	return nil
+}
+
+// Log formats the query log as a models.Point and buffers it to be written
+// to the queries system bucket of the organization of the query. The log is
+// dropped if too many points are already buffered.
+func (l *QueryLogger) Log(q query.Log) error {
+	if err := l.buffer(context.Background(), q); err != nil {
+		l.log.Info("Failed to write query log",
+			zap.String("org_id", q.OrganizationID.String()),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (l *QueryLogger) buffer(ctx context.Context, q query.Log) error {
+	points, err := l.points(ctx, q)
+	if err != nil {
+		return err
+	}
+	for _, p := range points {
+		select {
+		case l.batcher.In() <- p:
+		default:
+			return errors.New("too many query logs are buffered")
+		}
+	}
+	return nil
+}
+
+// processBatches writes the batches emitted by the batcher until the logger
+// is closed.
+func (l *QueryLogger) processBatches(closing <-chan struct{}) {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case batch := <-l.batcher.Out():
+			if err := l.pw.WritePoints(context.Background(), batch); err != nil {
+				l.log.Info("Failed to write query logs", zap.Int("points", len(batch)), zap.Error(err))
+			}
+		case <-closing:
+			return
+		}
+	}
+}
+
+// bucketID returns the ID of the queries system bucket of the organization.
+func (l *QueryLogger) bucketID(ctx context.Context, orgID influxdb.ID) (influxdb.ID, error) {
+	l.mu.Lock()
+	id, ok := l.bucketIDs[orgID]
+	l.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	b, err := l.buckets.FindBucketByName(ctx, orgID, influxdb.QueriesSystemBucketName)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	l.bucketIDs[orgID] = b.ID
+	l.mu.Unlock()
+	return b.ID, nil
+}
+
+// points returns the points of the query log, exploded for the queries
+// system bucket of the organization of the query.
+func (l *QueryLogger) points(ctx context.Context, q query.Log) ([]models.Point, error) {
+	bucketID, err := l.bucketID(ctx, q.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The authorization token is never written.
+	q.Redact()
+
+	status := "success"
+	fields := map[string]interface{}{
+		durationField:     q.Duration.Nanoseconds(),
+		responseSizeField: q.ResponseSize,
+	}
+	if q.Error != nil {
+		status = "failed"
+		fields[errorField] = q.Error.Error()
+	}
+	if q.TraceID != "" {
+		fields[traceIDField] = q.TraceID
+	}
+	if req := q.ProxyRequest; req != nil {
+		if text := control.QueryText(req.Request.Compiler); text != "" {
+			fields[queryField] = text
+		}
+		if a := req.Request.Authorization; a != nil {
+			fields[userIDField] = a.UserID.String()
+			fields[authorizationIDField] = a.ID.String()
+		}
+		if ip := remoteIP(req.Request.RemoteAddr); ip != "" {
+			fields[remoteIPField] = ip
+		}
+		if req.Request.Source != "" {
+			fields[sourceField] = req.Request.Source
+		}
+	}
+
+	tags := models.NewTags(map[string]string{
+		statusTag: status,
+	})
+	point, err := models.NewPoint(queryLogMeasurement, tags, fields, q.Time)
+	if err != nil {
+		return nil, err
+	}
+	return tsdb.ExplodePoints(q.OrganizationID, bucketID, models.Points{point})
+}
+
+// remoteIP returns the IP of the remote address of a request.
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
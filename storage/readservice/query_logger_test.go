@@ -0,0 +1,143 @@
+package readservice_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage/readservice"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestQueryLogger_Log(t *testing.T) {
+	const (
+		orgID    = influxdb.ID(1)
+		bucketID = influxdb.ID(2)
+	)
+
+	var lookups int
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		lookups++
+		if id != orgID || name != influxdb.QueriesSystemBucketName {
+			t.Fatalf("unexpected bucket %q of org %s", name, id)
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+	}
+	pw := &mock.PointsWriter{}
+
+	now := time.Unix(0, 100)
+	logger := readservice.NewQueryLogger(zaptest.NewLogger(t), pw, buckets)
+	if err := logger.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log(query.Log{
+		Time:           now,
+		Duration:       time.Second,
+		OrganizationID: orgID,
+		Error:          errors.New("expected error"),
+		ProxyRequest: &query.ProxyRequest{
+			Request: query.Request{
+				Authorization: &influxdb.Authorization{
+					ID:     influxdb.ID(3),
+					UserID: influxdb.ID(4),
+					Token:  "secret",
+				},
+				OrganizationID: orgID,
+				Compiler:       lang.FluxCompiler{Query: `from(bucket: "b")`},
+				RemoteAddr:     "10.0.0.1:52431",
+			},
+		},
+		ResponseSize: 10,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the points are written in the background until the logger is closed.
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if lookups != 1 {
+		t.Errorf("unexpected number of bucket lookups: %d", lookups)
+	}
+
+	// The fields of the point are exploded into a point each.
+	got := make(map[string]interface{})
+	for _, p := range pw.Points {
+		if name, want := string(p.Name()), tsdb.EncodeNameString(orgID, bucketID); name != want {
+			t.Errorf("unexpected name: got %q want %q", name, want)
+		}
+		if !p.Time().Equal(now) {
+			t.Errorf("unexpected time: got %s want %s", p.Time(), now)
+		}
+		tags := p.Tags()
+		if m := string(tags.Get(models.MeasurementTagKeyBytes)); m != "queries" {
+			t.Errorf("unexpected measurement %q", m)
+		}
+		if status := string(tags.Get([]byte("status"))); status != "failed" {
+			t.Errorf("unexpected status %q", status)
+		}
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range fields {
+			got[k] = v
+		}
+	}
+
+	want := map[string]interface{}{
+		"query":           `from(bucket: "b")`,
+		"userID":          influxdb.ID(4).String(),
+		"authorizationID": influxdb.ID(3).String(),
+		"duration":        time.Second.Nanoseconds(),
+		"responseSize":    int64(10),
+		"error":           "expected error",
+		"remoteIP":        "10.0.0.1",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected fields -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestQueryLogger_LogMany(t *testing.T) {
+	const orgID = influxdb.ID(1)
+
+	var lookups int
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		lookups++
+		return &influxdb.Bucket{ID: influxdb.ID(2), OrgID: id, Name: name}, nil
+	}
+	pw := &mock.PointsWriter{}
+
+	logger := readservice.NewQueryLogger(zaptest.NewLogger(t), pw, buckets)
+	if err := logger.Open(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := logger.Log(query.Log{Time: time.Unix(0, int64(i)), OrganizationID: orgID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the bucket of the organization is only looked up once, and every log
+	// has a duration and a response size point.
+	if lookups != 1 {
+		t.Errorf("unexpected number of bucket lookups: %d", lookups)
+	}
+	if got := len(pw.Points); got != 6 {
+		t.Errorf("unexpected number of points: %d", got)
+	}
+}
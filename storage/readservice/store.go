@@ -56,6 +56,28 @@ func (s *store) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest
 	return reads.NewFilteredResultSet(ctx, req, cur), nil
 }
 
+func (s *store) ReadWindowAggregate(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
+	if req.ReadSource == nil {
+		return nil, errors.New("missing read source")
+	}
+
+	source, err := getReadSource(*req.ReadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	var cur reads.SeriesCursor
+	if ic, err := newIndexSeriesCursor(ctx, &source, req.Predicate, s.viewer); err != nil {
+		return nil, err
+	} else if ic == nil {
+		return nil, nil
+	} else {
+		cur = ic
+	}
+
+	return reads.NewWindowAggregateResultSet(ctx, req, cur)
+}
+
 func (s *store) ReadGroup(ctx context.Context, req *datatypes.ReadGroupRequest) (reads.GroupResultSet, error) {
 	if req.ReadSource == nil {
 		return nil, errors.New("missing read source")
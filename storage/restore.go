@@ -0,0 +1,338 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsi1/buildtsi"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.RestoreService = (*Engine)(nil)
+
+// restoreBatchSize is the number of points written to the engine at once
+// when restoring a bucket.
+const restoreBatchSize = 5000
+
+// RestoreBucket writes the points of the source bucket of req found in the
+// TSM file tsm to the bucket of req. The measurement names of the points are
+// rewritten to the organization and bucket of req, and the points are
+// written like any other points, so their series are indexed and counted
+// against the series limits of the bucket.
+//
+// The points deleted by the tombstone file tombstone are not restored.
+func (e *Engine) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) (int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	closing := e.closing
+	e.mu.RUnlock()
+	if closing == nil {
+		return 0, ErrEngineClosed
+	}
+
+	// the TSM reader applies the tombstones of the file next to it, so both
+	// are copied into a directory of the engine.
+	dir, err := ioutil.TempDir(e.path, "restore")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	tsmPath := filepath.Join(dir, "restore."+tsm1.TSMFileExtension)
+	if err := copyRestoreFile(tsmPath, tsm); err != nil {
+		return 0, err
+	}
+	if tombstone != nil {
+		if err := copyRestoreFile(filepath.Join(dir, "restore.tombstone"), tombstone); err != nil {
+			return 0, err
+		}
+	}
+
+	f, err := os.Open(tsmPath)
+	if err != nil {
+		return 0, err
+	}
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderLogger(e.logger))
+	if err != nil {
+		f.Close()
+		return 0, errors.WithMessage(err, "failed to read TSM file")
+	}
+	defer r.Close()
+
+	n, err := e.restoreBucket(ctx, req, r)
+	if err != nil {
+		return n, err
+	}
+
+	e.logger.Info("Restored bucket",
+		zap.String("source_org_id", req.SourceOrgID.String()),
+		zap.String("source_bucket_id", req.SourceBucketID.String()),
+		zap.String("org_id", req.OrgID.String()),
+		zap.String("bucket_id", req.BucketID.String()),
+		zap.Int("points", n))
+	return n, nil
+}
+
+func (e *Engine) restoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, r *tsm1.TSMReader) (int, error) {
+	// composite keys are the escaped series key, followed by the field key.
+	encoded := tsdb.EncodeName(req.SourceOrgID, req.SourceBucketID)
+	prefix := append(models.EscapeMeasurement(encoded[:]), ',')
+	name := tsdb.EncodeNameString(req.OrgID, req.BucketID)
+
+	var (
+		n      int
+		points = make([]models.Point, 0, restoreBatchSize)
+	)
+	flush := func() error {
+		if len(points) == 0 {
+			return nil
+		}
+		if err := e.WritePoints(ctx, points); err != nil {
+			return err
+		}
+		n += len(points)
+		points = points[:0]
+		return nil
+	}
+
+	iter := r.Iterator(prefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+
+		seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+		_, tags := models.ParseKeyBytes(seriesKey)
+
+		values, err := r.ReadAll(key)
+		if err != nil {
+			return n, err
+		}
+		for _, v := range values {
+			pt, err := models.NewPoint(name, tags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+			if err != nil {
+				return n, err
+			}
+			points = append(points, pt)
+			if len(points) == restoreBatchSize {
+				if err := flush(); err != nil {
+					return n, err
+				}
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return n, err
+	}
+	if err := flush(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// RestoreEngine replaces all of the data of the engine with the engine files
+// of the last backup of chain.
+//
+// The files are verified against the manifest of the backup as they are
+// copied into a directory of the engine, and the index and the series file
+// are rebuilt from them, before the engine is closed to swap the data in.
+// While the data is swapped, reads and writes fail with ErrEngineClosed.
+// Data written since the backup, including the WAL, is discarded.
+func (e *Engine) RestoreEngine(ctx context.Context, chain []*influxdb.BackupManifest, files map[string]io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := influxdb.ValidateBackupChain(chain); err != nil {
+		return err
+	}
+	manifest := chain[len(chain)-1]
+
+	e.mu.RLock()
+	closing := e.closing
+	e.mu.RUnlock()
+	if closing == nil {
+		return ErrEngineClosed
+	}
+
+	dir, err := ioutil.TempDir(e.path, "restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := e.stageRestore(dir, manifest, files); err != nil {
+		return err
+	}
+
+	if err := e.Close(); err != nil {
+		return err
+	}
+	if err := e.swapRestore(dir); err != nil {
+		return multierr.Append(err, e.Open(ctx))
+	}
+	if err := e.Open(ctx); err != nil {
+		return err
+	}
+
+	e.logger.Info("Restored engine",
+		zap.String("backup_id", manifest.ID.String()),
+		zap.Int("files", len(manifest.Files)))
+	return nil
+}
+
+// livePaths returns the directories of the engine which are replaced by a
+// restore.
+func (e *Engine) livePaths() []string {
+	return []string{
+		e.config.GetEnginePath(e.path),
+		e.config.GetIndexPath(e.path),
+		e.config.GetSeriesFilePath(e.path),
+		e.config.GetWALPath(e.path),
+	}
+}
+
+// stagedPaths returns the directories of a restore staged in dir, in the
+// order of livePaths. Unlike the directories of the engine, they are never
+// configured elsewhere.
+func stagedPaths(dir string) []string {
+	return []string{
+		filepath.Join(dir, DefaultEngineDirectoryName),
+		filepath.Join(dir, DefaultIndexDirectoryName),
+		filepath.Join(dir, DefaultSeriesFileDirectoryName),
+		filepath.Join(dir, DefaultWALDirectoryName),
+	}
+}
+
+// stageRestore copies the engine files of the backup of manifest into dir, and
+// builds the index and the series file of the engine from them.
+func (e *Engine) stageRestore(dir string, manifest *influxdb.BackupManifest, files map[string]io.Reader) error {
+	want := make(map[string]bool, len(manifest.Files))
+	for _, f := range manifest.Files {
+		want[f.Name] = true
+	}
+	for name := range files {
+		if !want[name] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("file %s is not part of backup %s", name, manifest.ID),
+			}
+		}
+	}
+
+	staged := stagedPaths(dir)
+	dataDir, indexDir, sfileDir, walDir := staged[0], staged[1], staged[2], staged[3]
+	if err := os.MkdirAll(dataDir, 0777); err != nil {
+		return err
+	}
+	for _, f := range manifest.Files {
+		r, ok := files[f.Name]
+		if !ok {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("file %s of backup %s is missing", f.Name, manifest.ID),
+			}
+		}
+		// the file name is only used within the data directory.
+		if filepath.Base(f.Name) != f.Name {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid file name %q in backup %s", f.Name, manifest.ID),
+			}
+		}
+		if err := copyRestoreFile(filepath.Join(dataDir, f.Name), influxdb.NewBackupFileReader(r, f)); err != nil {
+			return err
+		}
+	}
+
+	sfile := tsdb.NewSeriesFile(sfileDir)
+	sfile.Logger = e.logger
+	if err := sfile.Open(context.Background()); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	return buildtsi.IndexShard(sfile, indexDir, dataDir, walDir,
+		tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), restoreBatchSize,
+		e.logger, false)
+}
+
+// swapRestore replaces the directories of the closed engine with the
+// directories restored in dir. If any directory can not be replaced, the
+// directories of the engine are moved back.
+func (e *Engine) swapRestore(dir string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// the cache is not cleared by closing the engine, and the restored data
+	// must not be mixed with the data written since the backup.
+	if _, err := e.engine.Cache.Snapshot(); err != nil {
+		return err
+	}
+	e.engine.Cache.ClearSnapshot(true)
+	e.seriesLimiter.reset()
+
+	oldDir := filepath.Join(dir, "old")
+	if err := os.MkdirAll(oldDir, 0777); err != nil {
+		return err
+	}
+
+	live, restored := e.livePaths(), stagedPaths(dir)
+	var moved []int
+	undo := func(err error) error {
+		for _, i := range moved {
+			old := filepath.Join(oldDir, fmt.Sprint(i))
+			if rerr := os.RemoveAll(live[i]); rerr != nil {
+				err = multierr.Append(err, rerr)
+				continue
+			}
+			if _, serr := os.Stat(old); os.IsNotExist(serr) {
+				continue
+			}
+			err = multierr.Append(err, os.Rename(old, live[i]))
+		}
+		return err
+	}
+
+	for i := range live {
+		moved = append(moved, i)
+		if err := os.Rename(live[i], filepath.Join(oldDir, fmt.Sprint(i))); err != nil && !os.IsNotExist(err) {
+			return undo(err)
+		}
+		if _, err := os.Stat(restored[i]); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Rename(restored[i], live[i]); err != nil {
+			return undo(err)
+		}
+	}
+	return nil
+}
+
+func copyRestoreFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
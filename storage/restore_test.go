@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestEngine_RestoreBucket(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	points := []models.Point{
+		models.MustNewPoint(name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "a"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(0, 1)),
+		models.MustNewPoint(name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "a"}),
+			map[string]interface{}{"value": 2.0},
+			time.Unix(0, 2)),
+		models.MustNewPoint(name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "count", models.MeasurementTagKey: "mem", "host": "b, c"}),
+			map[string]interface{}{"count": int64(3)},
+			time.Unix(0, 3)),
+	}
+	if err := engine.Engine.WritePoints(ctx, points); err != nil {
+		t.Fatal(err)
+	}
+
+	// another bucket of the same org is not restored.
+	otherBucketID, _ := influxdb.IDFromString("3333333333333333")
+	err := engine.Engine.WritePoints(ctx, []models.Point{models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, *otherBucketID),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "z"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(0, 1),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first backup writes the cache to TSM files, so the delete is
+	// written to a tombstone file of the second backup.
+	if _, _, err := engine.CreateBackup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.DeleteBucketRange(ctx, engine.org, engine.bucket, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	backupID, files, err := engine.CreateBackup(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backupPath := engine.InternalBackupPath(backupID)
+
+	orgID, _ := influxdb.IDFromString("4141414141414141")
+	bucketID, _ := influxdb.IDFromString("4242424242424242")
+	req := influxdb.RestoreBucketRequest{
+		SourceOrgID:    engine.org,
+		SourceBucketID: engine.bucket,
+		OrgID:          *orgID,
+		BucketID:       *bucketID,
+	}
+
+	var restored int
+	for _, file := range files {
+		if filepath.Ext(file) != ".tsm" {
+			continue
+		}
+		tsm, err := os.Open(filepath.Join(backupPath, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tsm.Close()
+
+		var n int
+		tombstone, err := os.Open(filepath.Join(backupPath, strings.TrimSuffix(file, ".tsm")+".tombstone"))
+		if os.IsNotExist(err) {
+			n, err = engine.RestoreBucket(ctx, req, tsm, nil)
+		} else if err == nil {
+			defer tombstone.Close()
+			n, err = engine.RestoreBucket(ctx, req, tsm, tombstone)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		restored += n
+	}
+
+	// the deleted point is not restored.
+	if got, exp := restored, 2; got != exp {
+		t.Fatalf("got %d restored points, exp %d", got, exp)
+	}
+
+	if got, err := engine.BucketSeriesCardinality(ctx, *orgID, *bucketID); err != nil {
+		t.Fatal(err)
+	} else if exp := int64(2); got != exp {
+		t.Fatalf("got %d series in restored bucket, exp %d", got, exp)
+	}
+
+	// the source bucket and the other bucket are left as they were.
+	if got, exp := engine.SeriesCardinality(), int64(5); got != exp {
+		t.Fatalf("got %d series in index, exp %d", got, exp)
+	}
+}
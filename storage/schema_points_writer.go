@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/bytesutil"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// SchemaPointsWriter is a PointsWriter that rejects points written to buckets
+// with an explicit schema when they do not conform to the measurement schemas
+// of the bucket. Points written to buckets with an implicit schema are passed
+// through unchanged.
+//
+// The schemas of the buckets are cached, so the writer is also the
+// BucketSchemaService through which the schemas must be changed: changing the
+// schemas of a bucket drops its cached schemas. The schema type of a bucket
+// never changes, so buckets with an implicit schema are cached as such.
+type SchemaPointsWriter struct {
+	PointsWriter  PointsWriter
+	BucketService influxdb.BucketService
+	SchemaService influxdb.BucketSchemaService
+
+	mu sync.RWMutex
+	// schemas holds the measurement schemas of the buckets by measurement
+	// name, which are nil for the buckets with an implicit schema.
+	schemas map[influxdb.ID]map[string]*influxdb.MeasurementSchema
+	// gen is incremented whenever cached schemas are dropped, so that the
+	// schemas found before are not cached after they were changed.
+	gen uint64
+}
+
+var _ influxdb.BucketSchemaService = (*SchemaPointsWriter)(nil)
+
+// NewSchemaPointsWriter returns a SchemaPointsWriter that writes conforming
+// points to pw.
+func NewSchemaPointsWriter(pw PointsWriter, bs influxdb.BucketService, ss influxdb.BucketSchemaService) *SchemaPointsWriter {
+	return &SchemaPointsWriter{
+		PointsWriter:  pw,
+		BucketService: bs,
+		SchemaService: ss,
+		schemas:       make(map[influxdb.ID]map[string]*influxdb.MeasurementSchema),
+	}
+}
+
+// WritePoints writes the points which conform to the schema of their bucket.
+// If any points were rejected, a tsdb.PartialWriteError describing the first
+// violation is returned after the remaining points are written, combined in a
+// tsdb.MultiWriteError with the error of the values dropped by the write of
+// the remaining points, if any.
+func (w *SchemaPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	schemas := make(map[influxdb.ID]map[string]*influxdb.MeasurementSchema)
+	var (
+		accepted = make([]models.Point, 0, len(points))
+		dropped  [][]byte
+		reason   string
+	)
+	for _, p := range points {
+		_, bucketID := tsdb.DecodeNameSlice(p.Name())
+		bs, ok := schemas[bucketID]
+		if !ok {
+			var err error
+			if bs, err = w.findSchemas(ctx, bucketID); err != nil {
+				return err
+			}
+			schemas[bucketID] = bs
+		}
+
+		if bs != nil {
+			if err := checkPointSchema(p, bs); err != nil {
+				if reason == "" {
+					reason = err.Error()
+				}
+				dropped = append(dropped, p.Key())
+				continue
+			}
+		}
+		accepted = append(accepted, p)
+	}
+
+	if len(dropped) == 0 {
+		return w.PointsWriter.WritePoints(ctx, points)
+	}
+
+	schemaErr := tsdb.PartialWriteError{
+		Reason:      "schema violation: " + reason,
+		Dropped:     len(dropped),
+		DroppedKeys: bytesutil.SortDedup(dropped),
+	}
+	if len(accepted) == 0 {
+		return schemaErr
+	}
+
+	// an error which is not about dropped values fails the write, so it is
+	// returned as it is.
+	err := w.PointsWriter.WritePoints(ctx, accepted)
+	if err == nil {
+		return schemaErr
+	} else if !isDroppedValuesError(err) {
+		return err
+	}
+	return &tsdb.MultiWriteError{Errs: append(tsdb.WriteErrors(err), schemaErr)}
+}
+
+// findSchemas returns the measurement schemas of a bucket keyed by measurement
+// name, or nil if the bucket does not have an explicit schema.
+func (w *SchemaPointsWriter) findSchemas(ctx context.Context, bucketID influxdb.ID) (map[string]*influxdb.MeasurementSchema, error) {
+	w.mu.RLock()
+	schemas, ok := w.schemas[bucketID]
+	gen := w.gen
+	w.mu.RUnlock()
+	if ok {
+		return schemas, nil
+	}
+
+	b, err := w.BucketService.FindBucketByID(ctx, bucketID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		// the points writer reports writes to unknown buckets.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if b.SchemaType == influxdb.SchemaTypeExplicit {
+		ms, _, err := w.SchemaService.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucketID})
+		if err != nil {
+			return nil, err
+		}
+		schemas = make(map[string]*influxdb.MeasurementSchema, len(ms))
+		for _, m := range ms {
+			schemas[m.Name] = m
+		}
+	}
+
+	w.mu.Lock()
+	if w.gen == gen {
+		w.schemas[bucketID] = schemas
+	}
+	w.mu.Unlock()
+	return schemas, nil
+}
+
+// invalidate drops the cached schemas of a bucket.
+func (w *SchemaPointsWriter) invalidate(bucketID influxdb.ID) {
+	w.mu.Lock()
+	delete(w.schemas, bucketID)
+	w.gen++
+	w.mu.Unlock()
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (w *SchemaPointsWriter) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	return w.SchemaService.FindMeasurementSchemaByID(ctx, bucketID, id)
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+func (w *SchemaPointsWriter) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+	return w.SchemaService.FindMeasurementSchemas(ctx, filter, opt...)
+}
+
+// CreateMeasurementSchema creates a measurement schema and drops the cached schemas of its bucket.
+func (w *SchemaPointsWriter) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	defer w.invalidate(m.BucketID)
+	return w.SchemaService.CreateMeasurementSchema(ctx, m)
+}
+
+// UpdateMeasurementSchema updates a measurement schema and drops the cached schemas of its bucket.
+func (w *SchemaPointsWriter) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	defer w.invalidate(bucketID)
+	return w.SchemaService.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+}
+
+// DeleteMeasurementSchema deletes a measurement schema and drops the cached schemas of its bucket.
+func (w *SchemaPointsWriter) DeleteMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID) error {
+	defer w.invalidate(bucketID)
+	return w.SchemaService.DeleteMeasurementSchema(ctx, bucketID, id)
+}
+
+// checkPointSchema returns an error if the exploded point p does not conform
+// to the measurement schemas of its bucket.
+func checkPointSchema(p models.Point, schemas map[string]*influxdb.MeasurementSchema) error {
+	var measurement, field []byte
+	tags := p.Tags()
+	for _, t := range tags {
+		switch string(t.Key) {
+		case models.MeasurementTagKey:
+			measurement = t.Value
+		case models.FieldKeyTagKey:
+			field = t.Value
+		}
+	}
+
+	m, ok := schemas[string(measurement)]
+	if !ok {
+		return fmt.Errorf("measurement %q has no schema", measurement)
+	}
+
+	columns := make(map[string]influxdb.MeasurementSchemaColumn, len(m.Columns))
+	for _, c := range m.Columns {
+		columns[c.Name] = c
+	}
+
+	for _, t := range tags {
+		key := string(t.Key)
+		if key == models.MeasurementTagKey || key == models.FieldKeyTagKey {
+			continue
+		}
+		if c, ok := columns[key]; !ok || c.Type != influxdb.SemanticColumnTypeTag {
+			return fmt.Errorf("tag %q is not declared by the schema of measurement %q", key, measurement)
+		}
+	}
+
+	c, ok := columns[string(field)]
+	if !ok || c.Type != influxdb.SemanticColumnTypeField {
+		return fmt.Errorf("field %q is not declared by the schema of measurement %q", field, measurement)
+	}
+
+	iter := p.FieldIterator()
+	for iter.Next() {
+		if dt := schemaDataType(iter.Type()); dt != c.DataType {
+			return fmt.Errorf("field %q of measurement %q has type %s; schema requires %s", field, measurement, dt, c.DataType)
+		}
+	}
+	return nil
+}
+
+func schemaDataType(typ models.FieldType) influxdb.SchemaColumnDataType {
+	switch typ {
+	case models.Float:
+		return influxdb.SchemaColumnDataTypeFloat
+	case models.Integer:
+		return influxdb.SchemaColumnDataTypeInteger
+	case models.Unsigned:
+		return influxdb.SchemaColumnDataTypeUnsigned
+	case models.String:
+		return influxdb.SchemaColumnDataTypeString
+	case models.Boolean:
+		return influxdb.SchemaColumnDataTypeBoolean
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,200 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestSchemaPointsWriter_WritePoints(t *testing.T) {
+	const (
+		orgID      = influxdb.ID(1)
+		explicitID = influxdb.ID(2)
+		implicitID = influxdb.ID(3)
+	)
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		b := &influxdb.Bucket{ID: id, OrgID: orgID}
+		if id == explicitID {
+			b.SchemaType = influxdb.SchemaTypeExplicit
+		}
+		return b, nil
+	}
+	schemas := mock.NewBucketSchemaService()
+	schemas.FindMeasurementSchemasFn = func(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+		if filter.BucketID != explicitID {
+			t.Errorf("unexpected bucket: %s", filter.BucketID)
+		}
+		return []*influxdb.MeasurementSchema{
+			{
+				BucketID: explicitID,
+				Name:     "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+					{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+					{Name: "usage", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+				},
+			},
+		}, 1, nil
+	}
+
+	t.Run("conforming points", func(t *testing.T) {
+		pw := &mock.PointsWriter{}
+		w := storage.NewSchemaPointsWriter(pw, buckets, schemas)
+		err := w.WritePoints(context.Background(), mockPoints(orgID, explicitID, `cpu,host=a usage=1 1
+cpu usage=2 2
+`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pw.Points) != 2 {
+			t.Errorf("expected 2 points to be written, got %d", len(pw.Points))
+		}
+	})
+
+	t.Run("implicit schema", func(t *testing.T) {
+		pw := &mock.PointsWriter{}
+		w := storage.NewSchemaPointsWriter(pw, buckets, schemas)
+		err := w.WritePoints(context.Background(), mockPoints(orgID, implicitID, `mem,host=a free=1i 1
+`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pw.Points) != 1 {
+			t.Errorf("expected 1 point to be written, got %d", len(pw.Points))
+		}
+	})
+
+	t.Run("non-conforming points", func(t *testing.T) {
+		pw := &mock.PointsWriter{}
+		w := storage.NewSchemaPointsWriter(pw, buckets, schemas)
+		err := w.WritePoints(context.Background(), mockPoints(orgID, explicitID, `cpu,host=a usage=1 1
+cpu,host=a usage=2i 2
+cpu,region=west usage=3 3
+cpu,host=a idle=4 4
+mem free=1i 5
+`))
+
+		var partialErr tsdb.PartialWriteError
+		if !errors.As(err, &partialErr) {
+			t.Fatalf("expected partial write error, got %v", err)
+		}
+		if got, want := partialErr.Dropped, 4; got != want {
+			t.Errorf("unexpected dropped count: got %d want %d", got, want)
+		}
+		if got, want := partialErr.Reason, `schema violation: field "usage" of measurement "cpu" has type integer; schema requires float`; got != want {
+			t.Errorf("unexpected reason: got %s want %s", got, want)
+		}
+		if len(pw.Points) != 1 {
+			t.Errorf("expected 1 point to be written, got %d", len(pw.Points))
+		}
+	})
+
+	t.Run("dropped values of the write", func(t *testing.T) {
+		pw := &mock.PointsWriter{}
+		conflictErr := &tsdb.FieldTypeConflictError{SeriesKeys: [][]byte{[]byte("cpu,host=a")}}
+		pw.ForceError(conflictErr)
+		w := storage.NewSchemaPointsWriter(pw, buckets, schemas)
+		err := w.WritePoints(context.Background(), mockPoints(orgID, explicitID, `cpu,host=a usage=1 1
+cpu,host=a usage=2i 2
+`))
+
+		var multiErr *tsdb.MultiWriteError
+		if !errors.As(err, &multiErr) {
+			t.Fatalf("expected multi write error, got %v", err)
+		}
+		if len(multiErr.Errs) != 2 {
+			t.Fatalf("expected 2 errors, got %v", multiErr.Errs)
+		}
+		if multiErr.Errs[0] != conflictErr {
+			t.Errorf("expected the field type conflict of the write, got %v", multiErr.Errs[0])
+		}
+		if partialErr, ok := multiErr.Errs[1].(tsdb.PartialWriteError); !ok || partialErr.Dropped != 1 {
+			t.Errorf("expected the schema violation, got %v", multiErr.Errs[1])
+		}
+	})
+
+	t.Run("failed write", func(t *testing.T) {
+		pw := &mock.PointsWriter{}
+		pw.ForceError(errors.New("boom"))
+		w := storage.NewSchemaPointsWriter(pw, buckets, schemas)
+		err := w.WritePoints(context.Background(), mockPoints(orgID, explicitID, `cpu,host=a usage=1 1
+cpu,host=a usage=2i 2
+`))
+		if err == nil || err.Error() != "boom" {
+			t.Errorf("expected the error of the write, got %v", err)
+		}
+	})
+}
+
+func TestSchemaPointsWriter_Cache(t *testing.T) {
+	const (
+		orgID      = influxdb.ID(1)
+		explicitID = influxdb.ID(2)
+		implicitID = influxdb.ID(3)
+	)
+
+	var findBuckets, findSchemas int
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		findBuckets++
+		b := &influxdb.Bucket{ID: id, OrgID: orgID}
+		if id == explicitID {
+			b.SchemaType = influxdb.SchemaTypeExplicit
+		}
+		return b, nil
+	}
+	field := influxdb.MeasurementSchemaColumn{Name: "usage", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat}
+	schemas := mock.NewBucketSchemaService()
+	schemas.FindMeasurementSchemasFn = func(ctx context.Context, filter influxdb.MeasurementSchemaFilter, opt ...influxdb.FindOptions) ([]*influxdb.MeasurementSchema, int, error) {
+		findSchemas++
+		return []*influxdb.MeasurementSchema{
+			{
+				BucketID: explicitID,
+				Name:     "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+					field,
+				},
+			},
+		}, 1, nil
+	}
+
+	w := storage.NewSchemaPointsWriter(&mock.PointsWriter{}, buckets, schemas)
+	write := func(bucketID influxdb.ID, data string) error {
+		return w.WritePoints(context.Background(), mockPoints(orgID, bucketID, data))
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := write(explicitID, "cpu usage=1 1\n"); err != nil {
+			t.Fatal(err)
+		}
+		if err := write(implicitID, "mem free=1i 1\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if findBuckets != 2 || findSchemas != 1 {
+		t.Errorf("expected the schemas to be found once, found %d buckets and %d schemas", findBuckets, findSchemas)
+	}
+
+	// the schemas changed through the writer are found again.
+	field.DataType = influxdb.SchemaColumnDataTypeInteger
+	if _, err := w.UpdateMeasurementSchema(context.Background(), explicitID, 1, influxdb.MeasurementSchemaUpdate{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(explicitID, "cpu usage=1i 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(explicitID, "cpu usage=1 1\n"); err == nil {
+		t.Error("expected the point to be rejected by the updated schema")
+	}
+	if findBuckets != 3 || findSchemas != 2 {
+		t.Errorf("expected the schemas to be found again, found %d buckets and %d schemas", findBuckets, findSchemas)
+	}
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// seriesIndex is the part of the index used to count the series of buckets.
+// The name of a measurement in the index is the encoded organization and
+// bucket of its series.
+type seriesIndex interface {
+	ForEachMeasurementName(fn func(name []byte) error) error
+	MeasurementSeriesIDIterator(name []byte) (tsdb.SeriesIDIterator, error)
+}
+
+// defaultCardinalityTTL is how long the count of a bucket is used for its
+// cardinality when no series limit is set.
+const defaultCardinalityTTL = time.Minute
+
+// seriesCount is the number of series in a bucket or organization.
+type seriesCount struct {
+	n       int       // series in the index when they were counted
+	added   int       // new series written since they were counted
+	pending int       // new series admitted to writes which are not done yet
+	at      time.Time // when the series were counted
+}
+
+func (c *seriesCount) total() int { return c.n + c.added }
+
+// seriesAdmissions are the keys of the new series admitted to a write.
+type seriesAdmissions []string
+
+// pendingSeries is a new series admitted to writes which are not done yet.
+type pendingSeries struct {
+	name    [16]byte // encoded name of the bucket of the series
+	refs    int      // writes the series is admitted to
+	written bool     // whether the series was counted by one of the writes
+}
+
+// seriesLimiter enforces limits on the number of series in each bucket and
+// in all of the buckets of each organization.
+//
+// Series are counted in the index the first time a bucket or organization
+// is written to, and the count is incremented as new series are written, so
+// that the index is not scanned on every write. The new series admitted to
+// a write are pending until the write is done, so that they count towards
+// the limits of concurrent writes but not once the write fails. A new series
+// is pending once, however many concurrent writes it is admitted to, and is
+// counted by the first of them which writes it. The count of
+// a bucket is discarded when series are deleted from it, and counted again
+// when it is next used.
+//
+// When no limit is set, new series are not counted by writes, so the count
+// of a bucket is only used for the cardinality of the bucket until it is
+// older than cardinalityTTL.
+type seriesLimiter struct {
+	maxSeriesPerBucket int
+	maxSeriesPerOrg    int
+	cardinalityTTL     time.Duration
+	index              seriesIndex
+
+	mu      sync.Mutex
+	buckets map[[16]byte]*seriesCount
+	orgs    map[influxdb.ID]*seriesCount
+	pending map[string]*pendingSeries // by series key
+}
+
+func newSeriesLimiter(maxSeriesPerBucket, maxSeriesPerOrg int, index seriesIndex) *seriesLimiter {
+	return &seriesLimiter{
+		maxSeriesPerBucket: maxSeriesPerBucket,
+		maxSeriesPerOrg:    maxSeriesPerOrg,
+		cardinalityTTL:     defaultCardinalityTTL,
+		index:              index,
+		buckets:            make(map[[16]byte]*seriesCount),
+		orgs:               make(map[influxdb.ID]*seriesCount),
+		pending:            make(map[string]*pendingSeries),
+	}
+}
+
+// enabled returns true if any series limit is set.
+func (l *seriesLimiter) enabled() bool {
+	return l.maxSeriesPerBucket > 0 || l.maxSeriesPerOrg > 0
+}
+
+// admit reports whether the new series with the key may be created in the
+// bucket with the encoded name, and holds it as pending if it may. If it may
+// not, the reason is returned. A series which is already pending for another
+// write is admitted again without counting towards the limits. The series
+// admitted must be passed to done once the write is done.
+func (l *seriesLimiter) admit(name, seriesKey []byte) (bool, string, error) {
+	var key [16]byte
+	copy(key[:], name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.pending[string(seriesKey)]; ok {
+		p.refs++
+		return true, "", nil
+	}
+
+	b, err := l.bucketLocked(key)
+	if err != nil {
+		return false, "", err
+	}
+	if l.maxSeriesPerBucket > 0 && b.total()+b.pending >= l.maxSeriesPerBucket {
+		return false, fmt.Sprintf("max series per bucket limit of %d reached", l.maxSeriesPerBucket), nil
+	}
+
+	var o *seriesCount
+	if l.maxSeriesPerOrg > 0 {
+		orgID, _ := tsdb.DecodeName(key)
+		if o, err = l.orgLocked(orgID); err != nil {
+			return false, "", err
+		}
+		if o.total()+o.pending >= l.maxSeriesPerOrg {
+			return false, fmt.Sprintf("max series per organization limit of %d reached", l.maxSeriesPerOrg), nil
+		}
+		o.pending++
+	}
+	b.pending++
+	l.pending[string(seriesKey)] = &pendingSeries{name: key, refs: 1}
+	return true, "", nil
+}
+
+// done releases the pending series admitted to a write, and counts those
+// which were written, unless another write counted them already. The pending
+// series of a count which was discarded in the meantime are counted in the
+// index when it is next used.
+func (l *seriesLimiter) done(admitted seriesAdmissions, written map[string]bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range admitted {
+		p, ok := l.pending[key]
+		if !ok {
+			continue
+		}
+		p.refs--
+		if p.refs == 0 {
+			delete(l.pending, key)
+		}
+
+		// a series stops being pending once it is counted, or once it is
+		// released by every write without being written.
+		counted := written[key] && !p.written
+		if !counted && (p.written || p.refs > 0) {
+			continue
+		}
+		p.written = p.written || counted
+
+		orgID, _ := tsdb.DecodeName(p.name)
+		for _, c := range []*seriesCount{l.buckets[p.name], l.orgs[orgID]} {
+			if c == nil {
+				continue
+			}
+			c.pending--
+			if c.pending < 0 {
+				c.pending = 0
+			}
+			if counted {
+				c.added++
+			}
+		}
+	}
+}
+
+// cardinality returns the number of series in the bucket with the encoded name.
+func (l *seriesLimiter) cardinality(name [16]byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// counts are only kept up to date by writes when a limit is enforced.
+	if b, ok := l.buckets[name]; ok && !l.enabled() && time.Since(b.at) >= l.cardinalityTTL {
+		delete(l.buckets, name)
+	}
+
+	b, err := l.bucketLocked(name)
+	if err != nil {
+		return 0, err
+	}
+	return b.total(), nil
+}
+
+// invalidate discards the count of the bucket with the encoded name, and of
+// its organization, after series are deleted from it.
+func (l *seriesLimiter) invalidate(name [16]byte) {
+	orgID, _ := tsdb.DecodeName(name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, name)
+	delete(l.orgs, orgID)
+}
+
+// reset discards the counts of all buckets and organizations, after the
+// series of the engine are replaced.
+func (l *seriesLimiter) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets = make(map[[16]byte]*seriesCount)
+	l.orgs = make(map[influxdb.ID]*seriesCount)
+	l.pending = make(map[string]*pendingSeries)
+}
+
+func (l *seriesLimiter) bucketLocked(name [16]byte) (*seriesCount, error) {
+	if b, ok := l.buckets[name]; ok {
+		return b, nil
+	}
+
+	n, err := l.count(name[:])
+	if err != nil {
+		return nil, err
+	}
+	b := &seriesCount{n: n, at: time.Now()}
+	l.buckets[name] = b
+	return b, nil
+}
+
+func (l *seriesLimiter) orgLocked(orgID influxdb.ID) (*seriesCount, error) {
+	if o, ok := l.orgs[orgID]; ok {
+		return o, nil
+	}
+
+	prefix := tsdb.EncodeName(orgID, 0)
+	var names [][16]byte
+	err := l.index.ForEachMeasurementName(func(name []byte) error {
+		if len(name) == 16 && bytes.HasPrefix(name, prefix[:8]) {
+			var key [16]byte
+			copy(key[:], name)
+			names = append(names, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o := &seriesCount{}
+	for _, name := range names {
+		b, err := l.bucketLocked(name)
+		if err != nil {
+			return nil, err
+		}
+		o.n += b.total()
+	}
+	l.orgs[orgID] = o
+	return o, nil
+}
+
+// count counts the series of the bucket with the encoded name in the index.
+func (l *seriesLimiter) count(name []byte) (int, error) {
+	itr, err := l.index.MeasurementSeriesIDIterator(name)
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	var n int
+	for {
+		e, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if e.SeriesID.IsZero() {
+			return n, nil
+		}
+		n++
+	}
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestSeriesLimiter_Admit(t *testing.T) {
+	const org = influxdb.ID(0x1000)
+	bucketA := tsdb.EncodeName(org, 0x2000)
+	bucketB := tsdb.EncodeName(org, 0x3000)
+	other := tsdb.EncodeName(0x4000, 0x5000)
+
+	index := &mockSeriesIndex{series: map[string]int{
+		string(bucketA[:]): 2,
+		string(bucketB[:]): 1,
+		string(other[:]):   10,
+	}}
+	l := newSeriesLimiter(3, 4, index)
+
+	admit := func(name [16]byte, key string, exp bool) {
+		t.Helper()
+		ok, reason, err := l.admit(name[:], []byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != exp {
+			t.Fatalf("got admitted %t, expected %t (%s)", ok, exp, reason)
+		}
+	}
+	cardinality := func(name [16]byte, exp int) {
+		t.Helper()
+		if n, err := l.cardinality(name); err != nil {
+			t.Fatal(err)
+		} else if n != exp {
+			t.Fatalf("got %d series, expected %d", n, exp)
+		}
+	}
+
+	// the series admitted to a write which fails are not counted.
+	admit(bucketA, "a1", true)
+	admit(bucketA, "a2", false)
+	l.done(seriesAdmissions{"a1"}, nil)
+	cardinality(bucketA, 2)
+
+	// bucket A reaches its limit before the organization does.
+	admit(bucketA, "a1", true)
+	l.done(seriesAdmissions{"a1"}, map[string]bool{"a1": true})
+	admit(bucketA, "a2", false)
+	cardinality(bucketA, 3)
+
+	// bucket B reaches the limit of the organization.
+	admit(bucketB, "b1", false)
+
+	// the series of other organizations are counted separately.
+	admit(other, "o1", false)
+
+	// the count is discarded when series are deleted.
+	index.series[string(bucketA[:])] = 0
+	l.invalidate(bucketA)
+	admit(bucketB, "b1", true)
+	admit(bucketB, "b2", true)
+	admit(bucketB, "b3", false)
+}
+
+func TestSeriesLimiter_AdmitConcurrent(t *testing.T) {
+	bucket := tsdb.EncodeName(0x1000, 0x2000)
+	index := &mockSeriesIndex{series: map[string]int{string(bucket[:]): 1}}
+	l := newSeriesLimiter(3, 0, index)
+
+	admit := func(key string, exp bool) {
+		t.Helper()
+		ok, reason, err := l.admit(bucket[:], []byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != exp {
+			t.Fatalf("got admitted %t, expected %t (%s)", ok, exp, reason)
+		}
+	}
+	cardinality := func(exp int) {
+		t.Helper()
+		if n, err := l.cardinality(bucket); err != nil {
+			t.Fatal(err)
+		} else if n != exp {
+			t.Fatalf("got %d series, expected %d", n, exp)
+		}
+	}
+
+	// a new series admitted to concurrent writes is pending once.
+	admit("s1", true)
+	admit("s1", true)
+	admit("s2", true)
+	admit("s3", false)
+
+	// it is counted once, by the first write which writes it.
+	l.done(seriesAdmissions{"s1"}, map[string]bool{"s1": true})
+	cardinality(2)
+	admit("s3", false)
+	l.done(seriesAdmissions{"s1"}, map[string]bool{"s1": true})
+	cardinality(2)
+
+	// a series released by every write without being written is not counted.
+	admit("s2", true)
+	l.done(seriesAdmissions{"s2"}, nil)
+	l.done(seriesAdmissions{"s2"}, nil)
+	cardinality(2)
+	admit("s3", true)
+}
+
+func TestSeriesLimiter_Cardinality(t *testing.T) {
+	bucket := tsdb.EncodeName(0x1000, 0x2000)
+	index := &mockSeriesIndex{series: map[string]int{string(bucket[:]): 2}}
+	l := newSeriesLimiter(0, 0, index)
+
+	cardinality := func(exp int) {
+		t.Helper()
+		if n, err := l.cardinality(bucket); err != nil {
+			t.Fatal(err)
+		} else if n != exp {
+			t.Fatalf("got %d series, expected %d", n, exp)
+		}
+	}
+
+	// without limits, the count is used until it is older than the TTL.
+	cardinality(2)
+	index.series[string(bucket[:])] = 5
+	cardinality(2)
+
+	l.cardinalityTTL = 0
+	cardinality(5)
+}
+
+// mockSeriesIndex is an index with a number of series in each measurement.
+type mockSeriesIndex struct {
+	series map[string]int
+}
+
+func (m *mockSeriesIndex) ForEachMeasurementName(fn func(name []byte) error) error {
+	for name := range m.series {
+		if err := fn([]byte(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockSeriesIndex) MeasurementSeriesIDIterator(name []byte) (tsdb.SeriesIDIterator, error) {
+	ss := tsdb.NewSeriesIDSet()
+	for i := 0; i < m.series[string(name)]; i++ {
+		ss.Add(tsdb.NewSeriesID(uint64(i + 1)))
+	}
+	return tsdb.NewSeriesIDSetIterator(ss), nil
+}
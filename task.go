@@ -64,6 +64,11 @@ type Task struct {
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// Params are the parameters of the query of the task, which are bound
+	// to its params record when it runs. They are encoded as the params of
+	// a query request.
+	Params json.RawMessage `json:"params,omitempty"`
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -154,6 +159,7 @@ type TaskCreate struct {
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        ID                     `json:"-"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
+	Params         json.RawMessage        `json:"params,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
@@ -174,6 +180,9 @@ type TaskUpdate struct {
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
 
+	// Params replaces the params of the task when it is not nil.
+	Params json.RawMessage `json:"params,omitempty"`
+
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
 	LatestScheduled *time.Time             `json:"-"`
@@ -193,6 +202,8 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Name        string  `json:"name,omitempty"`
 		Description *string `json:"description,omitempty"`
 
+		Params json.RawMessage `json:"params,omitempty"`
+
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
@@ -214,6 +225,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Name = jo.Name
 	t.Description = jo.Description
+	t.Params = jo.Params
 	t.Options.Cron = jo.Cron
 	t.Options.Every = jo.Every
 	if jo.Offset != nil {
@@ -234,6 +246,8 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 		Name        string  `json:"name,omitempty"`
 		Description *string `json:"description,omitempty"`
 
+		Params json.RawMessage `json:"params,omitempty"`
+
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
@@ -251,6 +265,7 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo.Cron = t.Options.Cron
 	jo.Every = t.Options.Every
 	jo.Description = t.Description
+	jo.Params = t.Params
 	if t.Options.Offset != nil {
 		offset := *t.Options.Offset
 		jo.Offset = &offset
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -369,6 +370,14 @@ func (w *worker) executeQuery(p *promise) {
 		return
 	}
 
+	var params query.Params
+	if len(p.task.Params) > 0 {
+		if err := json.Unmarshal(p.task.Params, &params); err != nil {
+			w.finish(p, backend.RunFail, influxdb.ErrTaskParamsParse(err))
+			return
+		}
+	}
+
 	sf := p.run.ScheduledFor
 
 	req := &query.Request{
@@ -378,6 +387,7 @@ func (w *worker) executeQuery(p *promise) {
 			AST: pkg,
 			Now: sf,
 		},
+		Params: params,
 	}
 	req.WithReturnNoContent(true)
 	ctx = icontext.SetAuthorizer(ctx, p.task.Authorization)
@@ -2,9 +2,11 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -69,6 +71,8 @@ func taskExecutorSystem(t *testing.T) tes {
 func TestTaskExecutor(t *testing.T) {
 	t.Run("QuerySuccess", testQuerySuccess)
 	t.Run("QueryFailure", testQueryFailure)
+	t.Run("QueryParams", testQueryParams)
+	t.Run("InvalidParams", testInvalidParams)
 	t.Run("ManualRun", testManualRun)
 	t.Run("ResumeRun", testResumingRun)
 	t.Run("WorkerLimit", testWorkerLimit)
@@ -182,6 +186,73 @@ func testQueryFailure(t *testing.T) {
 	}
 }
 
+func testQueryParams(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: tes.tc.OrgID,
+		OwnerID:        tes.tc.Auth.GetUserID(),
+		Flux:           script,
+		Params:         json.RawMessage(`{"n":5,"window":{"type":"duration","value":"1h"}}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+
+	tes.svc.mu.Lock()
+	params := tes.svc.mostRecentParams
+	tes.svc.mu.Unlock()
+	want := query.Params{"n": int64(5), "window": query.Duration("1h")}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("unexpected params: got %v, want %v", params, want)
+	}
+
+	tes.svc.SucceedQuery(script)
+	<-promise.Done()
+
+	if got := promise.Error(); got != nil {
+		t.Fatal(got)
+	}
+}
+
+func testInvalidParams(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: tes.tc.OrgID,
+		OwnerID:        tes.tc.Auth.GetUserID(),
+		Flux:           script,
+		Params:         json.RawMessage(`{"n":null}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-promise.Done()
+
+	if got := promise.Error(); got == nil {
+		t.Fatal("got no error when I should have")
+	}
+}
+
 func testManualRun(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -26,6 +26,8 @@ type fakeQueryService struct {
 	// The most recent ctx received in the Query method.
 	// Used to validate that the executor applied the correct authorizer.
 	mostRecentCtx context.Context
+	// The params of the most recent request received in the Query method.
+	mostRecentParams query.Params
 }
 
 var _ query.AsyncQueryService = (*fakeQueryService)(nil)
@@ -61,6 +63,7 @@ func (s *fakeQueryService) Query(ctx context.Context, req *query.Request) (flux.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.mostRecentCtx = ctx
+	s.mostRecentParams = req.Params
 	if s.queryErr != nil {
 		err := s.queryErr
 		s.queryErr = nil
@@ -153,6 +153,15 @@ func ErrTaskOptionParse(err error) *Error {
 	}
 }
 
+func ErrTaskParamsParse(err error) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid params; Err: %v", err),
+		Op:   "taskParams",
+		Err:  err,
+	}
+}
+
 func ErrJsonMarshalError(err error) *Error {
 	return &Error{
 		Code: EInvalid,
@@ -46,6 +46,10 @@ type CursorRequest struct {
 	Ascending bool
 	StartTime int64
 	EndTime   int64
+
+	// Limit is the number of points which are read from the cursor when it
+	// is not zero, so that the engine may read no more points than those.
+	Limit int64
 }
 
 type CursorIterator interface {
@@ -68,10 +72,16 @@ func (a CursorIterators) Stats() CursorStats {
 type CursorStats struct {
 	ScannedValues int // number of values scanned
 	ScannedBytes  int // number of uncompressed bytes scanned
+	CacheValues   int // number of values read from the cache
+	DecodedBlocks int // number of blocks of values decoded from TSM files
+	ScannedSeries int // number of series scanned, counted by the readers of result sets
 }
 
 // Add adds other to s and updates s.
 func (s *CursorStats) Add(other CursorStats) {
 	s.ScannedValues += other.ScannedValues
 	s.ScannedBytes += other.ScannedBytes
+	s.CacheValues += other.CacheValues
+	s.DecodedBlocks += other.DecodedBlocks
+	s.ScannedSeries += other.ScannedSeries
 }
@@ -3,6 +3,7 @@ package tsdb
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -11,6 +12,10 @@ var (
 
 	// ErrUnknownFieldType is returned when the type of a field cannot be determined.
 	ErrUnknownFieldType = errors.New("unknown field type")
+
+	// ErrSeriesLimitExceeded is returned when creating a series would exceed the
+	// series limit of its bucket or organization.
+	ErrSeriesLimitExceeded = errors.New("series limit exceeded")
 )
 
 // PartialWriteError indicates a write request could only write a portion of the
@@ -26,3 +31,61 @@ type PartialWriteError struct {
 func (e PartialWriteError) Error() string {
 	return fmt.Sprintf("partial write: %s dropped=%d", e.Reason, e.Dropped)
 }
+
+// FieldTypeConflictError indicates the values for one or more series in a write
+// conflicted with the type already stored for that series and were dropped.
+type FieldTypeConflictError struct {
+	// A sorted slice of series keys whose values were dropped.
+	SeriesKeys [][]byte
+}
+
+func (e *FieldTypeConflictError) Error() string {
+	return fmt.Sprintf("%s: dropped=%d", ErrFieldTypeConflict, len(e.SeriesKeys))
+}
+
+// Unwrap returns ErrFieldTypeConflict so that errors.Is continues to match it.
+func (e *FieldTypeConflictError) Unwrap() error { return ErrFieldTypeConflict }
+
+// SeriesLimitError indicates that the values for one or more new series in a
+// write were dropped, because creating the series would exceed the series limit
+// of their bucket or organization. The values for existing series were written.
+type SeriesLimitError struct {
+	Reason string
+
+	// A sorted slice of series keys whose values were dropped.
+	SeriesKeys [][]byte
+}
+
+func (e *SeriesLimitError) Error() string {
+	return fmt.Sprintf("%s: %s dropped=%d", ErrSeriesLimitExceeded, e.Reason, len(e.SeriesKeys))
+}
+
+// Unwrap returns ErrSeriesLimitExceeded so that errors.Is matches it.
+func (e *SeriesLimitError) Unwrap() error { return ErrSeriesLimitExceeded }
+
+// MultiWriteError indicates that the values of different series in a write
+// were dropped for different reasons, such as a series limit and a field type
+// conflict. The values of the series not described by any of Errs were written.
+type MultiWriteError struct {
+	Errs []error
+}
+
+func (e *MultiWriteError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// WriteErrors returns the errors of a write: those combined by err if it is a
+// *MultiWriteError, or err alone otherwise.
+func WriteErrors(err error) []error {
+	var multiErr *MultiWriteError
+	if errors.As(err, &multiErr) {
+		return multiErr.Errs
+	} else if err == nil {
+		return nil
+	}
+	return []error{err}
+}
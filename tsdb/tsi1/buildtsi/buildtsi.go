@@ -1,4 +1,5 @@
-// Package buildtsi reads an in-memory index and exports it as a TSI index.
+// Package buildtsi builds the TSI index of a shard from its TSM and WAL files.
+// It is used by influxd inspect buildtsi, and to index restored shards.
 package buildtsi
 
 import (
@@ -18,6 +19,8 @@ import (
 	"go.uber.org/zap"
 )
 
+// IndexShard builds the TSI index of the shard with the TSM files in dataDir and
+// the WAL files in walDir at indexPath, unless there is an index there already.
 func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLogFileSize int64, maxCacheSize uint64, batchSize int, log *zap.Logger, verboseLogging bool) error {
 	log.Info("Rebuilding shard")
 
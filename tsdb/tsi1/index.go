@@ -795,6 +795,13 @@ func (i *Index) SeriesN() int64 {
 	return total
 }
 
+// HasSeriesID returns true if the series with the provided id exists in the
+// index. The key is the key of the series in points, which determines the
+// partition holding the series.
+func (i *Index) HasSeriesID(key []byte, id tsdb.SeriesID) bool {
+	return i.partition(key).seriesIDSet.Contains(id)
+}
+
 // HasTagKey returns true if tag key exists. It returns the first error
 // encountered if any.
 func (i *Index) HasTagKey(name, key []byte) (bool, error) {
@@ -72,6 +72,7 @@ func (c *floatArrayAscendingCursor) Stats() cursors.CursorStats { return c.stats
 // Next returns the next key/value for the cursor.
 func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -146,6 +147,7 @@ func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += c.cache.pos - cpos
 	c.stats.ScannedValues += len(c.res.Values)
 
 	c.stats.ScannedBytes += len(c.res.Values) * 8
@@ -162,6 +164,9 @@ func (c *floatArrayAscendingCursor) nextTSM() *tsdb.FloatArray {
 
 func (c *floatArrayAscendingCursor) readArrayBlock() *tsdb.FloatArray {
 	values, _ := c.tsm.keyCursor.ReadFloatArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 	return values
 }
 
@@ -238,6 +243,7 @@ func (c *floatArrayDescendingCursor) Stats() cursors.CursorStats { return c.stat
 
 func (c *floatArrayDescendingCursor) Next() *tsdb.FloatArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -305,6 +311,8 @@ func (c *floatArrayDescendingCursor) Next() *tsdb.FloatArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += cpos - c.cache.pos
+
 	return c.res
 }
 
@@ -317,6 +325,9 @@ func (c *floatArrayDescendingCursor) nextTSM() *tsdb.FloatArray {
 
 func (c *floatArrayDescendingCursor) readArrayBlock() *tsdb.FloatArray {
 	values, _ := c.tsm.keyCursor.ReadFloatArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 
 	c.stats.ScannedValues += len(values.Values)
 
@@ -325,6 +336,155 @@ func (c *floatArrayDescendingCursor) readArrayBlock() *tsdb.FloatArray {
 	return values
 }
 
+// floatArrayPointCursor reads only the first point of a series in the direction
+// of the cursor, which is the point of a first or a last aggregate.
+//
+// The index entries of the blocks of the series bound the times of their
+// points, so the point is read from the cache without decoding any block
+// when it is before all of the blocks, and otherwise only the blocks which
+// hold the point are decoded.
+type floatArrayPointCursor struct {
+	cache     Values
+	keyCursor *KeyCursor
+	buf       *tsdb.FloatArray
+
+	seek, end int64
+	ascending bool
+	done      bool
+
+	res   *tsdb.FloatArray
+	stats cursors.CursorStats
+}
+
+func newFloatArrayPointCursor() *floatArrayPointCursor {
+	return &floatArrayPointCursor{
+		buf: tsdb.NewFloatArrayLen(MaxPointsPerBlock),
+		res: tsdb.NewFloatArrayLen(1),
+	}
+}
+
+func (c *floatArrayPointCursor) reset(seek, end int64, ascending bool, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.cache = cacheValues
+	c.keyCursor = tsmKeyCursor
+	c.seek, c.end = seek, end
+	c.ascending = ascending
+	c.done = false
+}
+
+func (c *floatArrayPointCursor) Err() error { return nil }
+
+func (c *floatArrayPointCursor) Close() {
+	if c.keyCursor != nil {
+		c.keyCursor.Close()
+		c.keyCursor = nil
+	}
+	c.cache = nil
+}
+
+func (c *floatArrayPointCursor) Stats() cursors.CursorStats { return c.stats }
+
+func (c *floatArrayPointCursor) Next() *tsdb.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+	if c.done {
+		return c.res
+	}
+	c.done = true
+
+	// The point of the cache, which is replaced by a point of the
+	// blocks only when the point of the blocks is before it.
+	ci := c.cachePoint()
+	if ci >= 0 {
+		c.res.Timestamps = append(c.res.Timestamps, c.cache[ci].UnixNano())
+		c.res.Values = append(c.res.Values, c.cache[ci].(FloatValue).RawValue())
+	}
+
+	bound, ok := c.keyCursor.peekTime(c.seek)
+	if !ok || !c.inRange(bound) || (ci >= 0 && !c.before(bound, c.res.Timestamps[0])) {
+		// The blocks have no point in the range or before the point of the cache.
+		return c.cacheResult()
+	}
+
+	for {
+		values, _ := c.keyCursor.ReadFloatArrayBlock(c.buf)
+		if values.Len() == 0 {
+			break
+		}
+		c.stats.DecodedBlocks++
+		c.stats.ScannedValues += len(values.Values)
+
+		c.stats.ScannedBytes += len(values.Values) * 8
+
+		// The values of a block are in ascending order.
+		var j int
+		if c.ascending {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] >= c.seek
+			})
+		} else {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] > c.seek
+			}) - 1
+		}
+		if j < 0 || j >= values.Len() {
+			// The points of the block are all before the seek time.
+			c.keyCursor.Next()
+			continue
+		}
+
+		if t := values.Timestamps[j]; c.inRange(t) && (ci < 0 || c.before(t, c.res.Timestamps[0])) {
+			c.res.Timestamps = append(c.res.Timestamps[:0], t)
+			c.res.Values = append(c.res.Values[:0], values.Values[j])
+			return c.res
+		}
+		break
+	}
+	return c.cacheResult()
+}
+
+// cacheResult returns the point of the cache, if any, as the result.
+func (c *floatArrayPointCursor) cacheResult() *tsdb.FloatArray {
+	if c.res.Len() > 0 {
+		c.stats.CacheValues++
+		c.stats.ScannedValues++
+
+		c.stats.ScannedBytes += 8
+
+	}
+	return c.res
+}
+
+// cachePoint returns the index of the first point of the cache from the seek
+// time in the direction of the cursor, or -1 if the cache has no point in the range.
+func (c *floatArrayPointCursor) cachePoint() int {
+	i := sort.Search(len(c.cache), func(i int) bool {
+		return c.cache[i].UnixNano() >= c.seek
+	})
+	if !c.ascending && (i == len(c.cache) || c.cache[i].UnixNano() != c.seek) {
+		i--
+	}
+	if i < 0 || i >= len(c.cache) || !c.inRange(c.cache[i].UnixNano()) {
+		return -1
+	}
+	return i
+}
+
+// inRange reports whether t is before the end of the cursor.
+func (c *floatArrayPointCursor) inRange(t int64) bool {
+	if c.ascending {
+		return t < c.end
+	}
+	return t > c.end
+}
+
+// before reports whether t is strictly before u in the direction of the cursor.
+func (c *floatArrayPointCursor) before(t, u int64) bool {
+	if c.ascending {
+		return t < u
+	}
+	return t > u
+}
+
 type integerArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -382,6 +542,7 @@ func (c *integerArrayAscendingCursor) Stats() cursors.CursorStats { return c.sta
 // Next returns the next key/value for the cursor.
 func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -456,6 +617,7 @@ func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += c.cache.pos - cpos
 	c.stats.ScannedValues += len(c.res.Values)
 
 	c.stats.ScannedBytes += len(c.res.Values) * 8
@@ -472,6 +634,9 @@ func (c *integerArrayAscendingCursor) nextTSM() *tsdb.IntegerArray {
 
 func (c *integerArrayAscendingCursor) readArrayBlock() *tsdb.IntegerArray {
 	values, _ := c.tsm.keyCursor.ReadIntegerArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 	return values
 }
 
@@ -548,6 +713,7 @@ func (c *integerArrayDescendingCursor) Stats() cursors.CursorStats { return c.st
 
 func (c *integerArrayDescendingCursor) Next() *tsdb.IntegerArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -615,6 +781,8 @@ func (c *integerArrayDescendingCursor) Next() *tsdb.IntegerArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += cpos - c.cache.pos
+
 	return c.res
 }
 
@@ -627,6 +795,9 @@ func (c *integerArrayDescendingCursor) nextTSM() *tsdb.IntegerArray {
 
 func (c *integerArrayDescendingCursor) readArrayBlock() *tsdb.IntegerArray {
 	values, _ := c.tsm.keyCursor.ReadIntegerArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 
 	c.stats.ScannedValues += len(values.Values)
 
@@ -635,6 +806,155 @@ func (c *integerArrayDescendingCursor) readArrayBlock() *tsdb.IntegerArray {
 	return values
 }
 
+// integerArrayPointCursor reads only the first point of a series in the direction
+// of the cursor, which is the point of a first or a last aggregate.
+//
+// The index entries of the blocks of the series bound the times of their
+// points, so the point is read from the cache without decoding any block
+// when it is before all of the blocks, and otherwise only the blocks which
+// hold the point are decoded.
+type integerArrayPointCursor struct {
+	cache     Values
+	keyCursor *KeyCursor
+	buf       *tsdb.IntegerArray
+
+	seek, end int64
+	ascending bool
+	done      bool
+
+	res   *tsdb.IntegerArray
+	stats cursors.CursorStats
+}
+
+func newIntegerArrayPointCursor() *integerArrayPointCursor {
+	return &integerArrayPointCursor{
+		buf: tsdb.NewIntegerArrayLen(MaxPointsPerBlock),
+		res: tsdb.NewIntegerArrayLen(1),
+	}
+}
+
+func (c *integerArrayPointCursor) reset(seek, end int64, ascending bool, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.cache = cacheValues
+	c.keyCursor = tsmKeyCursor
+	c.seek, c.end = seek, end
+	c.ascending = ascending
+	c.done = false
+}
+
+func (c *integerArrayPointCursor) Err() error { return nil }
+
+func (c *integerArrayPointCursor) Close() {
+	if c.keyCursor != nil {
+		c.keyCursor.Close()
+		c.keyCursor = nil
+	}
+	c.cache = nil
+}
+
+func (c *integerArrayPointCursor) Stats() cursors.CursorStats { return c.stats }
+
+func (c *integerArrayPointCursor) Next() *tsdb.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+	if c.done {
+		return c.res
+	}
+	c.done = true
+
+	// The point of the cache, which is replaced by a point of the
+	// blocks only when the point of the blocks is before it.
+	ci := c.cachePoint()
+	if ci >= 0 {
+		c.res.Timestamps = append(c.res.Timestamps, c.cache[ci].UnixNano())
+		c.res.Values = append(c.res.Values, c.cache[ci].(IntegerValue).RawValue())
+	}
+
+	bound, ok := c.keyCursor.peekTime(c.seek)
+	if !ok || !c.inRange(bound) || (ci >= 0 && !c.before(bound, c.res.Timestamps[0])) {
+		// The blocks have no point in the range or before the point of the cache.
+		return c.cacheResult()
+	}
+
+	for {
+		values, _ := c.keyCursor.ReadIntegerArrayBlock(c.buf)
+		if values.Len() == 0 {
+			break
+		}
+		c.stats.DecodedBlocks++
+		c.stats.ScannedValues += len(values.Values)
+
+		c.stats.ScannedBytes += len(values.Values) * 8
+
+		// The values of a block are in ascending order.
+		var j int
+		if c.ascending {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] >= c.seek
+			})
+		} else {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] > c.seek
+			}) - 1
+		}
+		if j < 0 || j >= values.Len() {
+			// The points of the block are all before the seek time.
+			c.keyCursor.Next()
+			continue
+		}
+
+		if t := values.Timestamps[j]; c.inRange(t) && (ci < 0 || c.before(t, c.res.Timestamps[0])) {
+			c.res.Timestamps = append(c.res.Timestamps[:0], t)
+			c.res.Values = append(c.res.Values[:0], values.Values[j])
+			return c.res
+		}
+		break
+	}
+	return c.cacheResult()
+}
+
+// cacheResult returns the point of the cache, if any, as the result.
+func (c *integerArrayPointCursor) cacheResult() *tsdb.IntegerArray {
+	if c.res.Len() > 0 {
+		c.stats.CacheValues++
+		c.stats.ScannedValues++
+
+		c.stats.ScannedBytes += 8
+
+	}
+	return c.res
+}
+
+// cachePoint returns the index of the first point of the cache from the seek
+// time in the direction of the cursor, or -1 if the cache has no point in the range.
+func (c *integerArrayPointCursor) cachePoint() int {
+	i := sort.Search(len(c.cache), func(i int) bool {
+		return c.cache[i].UnixNano() >= c.seek
+	})
+	if !c.ascending && (i == len(c.cache) || c.cache[i].UnixNano() != c.seek) {
+		i--
+	}
+	if i < 0 || i >= len(c.cache) || !c.inRange(c.cache[i].UnixNano()) {
+		return -1
+	}
+	return i
+}
+
+// inRange reports whether t is before the end of the cursor.
+func (c *integerArrayPointCursor) inRange(t int64) bool {
+	if c.ascending {
+		return t < c.end
+	}
+	return t > c.end
+}
+
+// before reports whether t is strictly before u in the direction of the cursor.
+func (c *integerArrayPointCursor) before(t, u int64) bool {
+	if c.ascending {
+		return t < u
+	}
+	return t > u
+}
+
 type unsignedArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -692,6 +1012,7 @@ func (c *unsignedArrayAscendingCursor) Stats() cursors.CursorStats { return c.st
 // Next returns the next key/value for the cursor.
 func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -766,6 +1087,7 @@ func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += c.cache.pos - cpos
 	c.stats.ScannedValues += len(c.res.Values)
 
 	c.stats.ScannedBytes += len(c.res.Values) * 8
@@ -782,6 +1104,9 @@ func (c *unsignedArrayAscendingCursor) nextTSM() *tsdb.UnsignedArray {
 
 func (c *unsignedArrayAscendingCursor) readArrayBlock() *tsdb.UnsignedArray {
 	values, _ := c.tsm.keyCursor.ReadUnsignedArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 	return values
 }
 
@@ -858,6 +1183,7 @@ func (c *unsignedArrayDescendingCursor) Stats() cursors.CursorStats { return c.s
 
 func (c *unsignedArrayDescendingCursor) Next() *tsdb.UnsignedArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -925,6 +1251,8 @@ func (c *unsignedArrayDescendingCursor) Next() *tsdb.UnsignedArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += cpos - c.cache.pos
+
 	return c.res
 }
 
@@ -937,6 +1265,9 @@ func (c *unsignedArrayDescendingCursor) nextTSM() *tsdb.UnsignedArray {
 
 func (c *unsignedArrayDescendingCursor) readArrayBlock() *tsdb.UnsignedArray {
 	values, _ := c.tsm.keyCursor.ReadUnsignedArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 
 	c.stats.ScannedValues += len(values.Values)
 
@@ -945,6 +1276,155 @@ func (c *unsignedArrayDescendingCursor) readArrayBlock() *tsdb.UnsignedArray {
 	return values
 }
 
+// unsignedArrayPointCursor reads only the first point of a series in the direction
+// of the cursor, which is the point of a first or a last aggregate.
+//
+// The index entries of the blocks of the series bound the times of their
+// points, so the point is read from the cache without decoding any block
+// when it is before all of the blocks, and otherwise only the blocks which
+// hold the point are decoded.
+type unsignedArrayPointCursor struct {
+	cache     Values
+	keyCursor *KeyCursor
+	buf       *tsdb.UnsignedArray
+
+	seek, end int64
+	ascending bool
+	done      bool
+
+	res   *tsdb.UnsignedArray
+	stats cursors.CursorStats
+}
+
+func newUnsignedArrayPointCursor() *unsignedArrayPointCursor {
+	return &unsignedArrayPointCursor{
+		buf: tsdb.NewUnsignedArrayLen(MaxPointsPerBlock),
+		res: tsdb.NewUnsignedArrayLen(1),
+	}
+}
+
+func (c *unsignedArrayPointCursor) reset(seek, end int64, ascending bool, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.cache = cacheValues
+	c.keyCursor = tsmKeyCursor
+	c.seek, c.end = seek, end
+	c.ascending = ascending
+	c.done = false
+}
+
+func (c *unsignedArrayPointCursor) Err() error { return nil }
+
+func (c *unsignedArrayPointCursor) Close() {
+	if c.keyCursor != nil {
+		c.keyCursor.Close()
+		c.keyCursor = nil
+	}
+	c.cache = nil
+}
+
+func (c *unsignedArrayPointCursor) Stats() cursors.CursorStats { return c.stats }
+
+func (c *unsignedArrayPointCursor) Next() *tsdb.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+	if c.done {
+		return c.res
+	}
+	c.done = true
+
+	// The point of the cache, which is replaced by a point of the
+	// blocks only when the point of the blocks is before it.
+	ci := c.cachePoint()
+	if ci >= 0 {
+		c.res.Timestamps = append(c.res.Timestamps, c.cache[ci].UnixNano())
+		c.res.Values = append(c.res.Values, c.cache[ci].(UnsignedValue).RawValue())
+	}
+
+	bound, ok := c.keyCursor.peekTime(c.seek)
+	if !ok || !c.inRange(bound) || (ci >= 0 && !c.before(bound, c.res.Timestamps[0])) {
+		// The blocks have no point in the range or before the point of the cache.
+		return c.cacheResult()
+	}
+
+	for {
+		values, _ := c.keyCursor.ReadUnsignedArrayBlock(c.buf)
+		if values.Len() == 0 {
+			break
+		}
+		c.stats.DecodedBlocks++
+		c.stats.ScannedValues += len(values.Values)
+
+		c.stats.ScannedBytes += len(values.Values) * 8
+
+		// The values of a block are in ascending order.
+		var j int
+		if c.ascending {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] >= c.seek
+			})
+		} else {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] > c.seek
+			}) - 1
+		}
+		if j < 0 || j >= values.Len() {
+			// The points of the block are all before the seek time.
+			c.keyCursor.Next()
+			continue
+		}
+
+		if t := values.Timestamps[j]; c.inRange(t) && (ci < 0 || c.before(t, c.res.Timestamps[0])) {
+			c.res.Timestamps = append(c.res.Timestamps[:0], t)
+			c.res.Values = append(c.res.Values[:0], values.Values[j])
+			return c.res
+		}
+		break
+	}
+	return c.cacheResult()
+}
+
+// cacheResult returns the point of the cache, if any, as the result.
+func (c *unsignedArrayPointCursor) cacheResult() *tsdb.UnsignedArray {
+	if c.res.Len() > 0 {
+		c.stats.CacheValues++
+		c.stats.ScannedValues++
+
+		c.stats.ScannedBytes += 8
+
+	}
+	return c.res
+}
+
+// cachePoint returns the index of the first point of the cache from the seek
+// time in the direction of the cursor, or -1 if the cache has no point in the range.
+func (c *unsignedArrayPointCursor) cachePoint() int {
+	i := sort.Search(len(c.cache), func(i int) bool {
+		return c.cache[i].UnixNano() >= c.seek
+	})
+	if !c.ascending && (i == len(c.cache) || c.cache[i].UnixNano() != c.seek) {
+		i--
+	}
+	if i < 0 || i >= len(c.cache) || !c.inRange(c.cache[i].UnixNano()) {
+		return -1
+	}
+	return i
+}
+
+// inRange reports whether t is before the end of the cursor.
+func (c *unsignedArrayPointCursor) inRange(t int64) bool {
+	if c.ascending {
+		return t < c.end
+	}
+	return t > c.end
+}
+
+// before reports whether t is strictly before u in the direction of the cursor.
+func (c *unsignedArrayPointCursor) before(t, u int64) bool {
+	if c.ascending {
+		return t < u
+	}
+	return t > u
+}
+
 type stringArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -1002,6 +1482,7 @@ func (c *stringArrayAscendingCursor) Stats() cursors.CursorStats { return c.stat
 // Next returns the next key/value for the cursor.
 func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -1076,6 +1557,7 @@ func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += c.cache.pos - cpos
 	c.stats.ScannedValues += len(c.res.Values)
 
 	for _, v := range c.res.Values {
@@ -1094,6 +1576,9 @@ func (c *stringArrayAscendingCursor) nextTSM() *tsdb.StringArray {
 
 func (c *stringArrayAscendingCursor) readArrayBlock() *tsdb.StringArray {
 	values, _ := c.tsm.keyCursor.ReadStringArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 	return values
 }
 
@@ -1170,6 +1655,7 @@ func (c *stringArrayDescendingCursor) Stats() cursors.CursorStats { return c.sta
 
 func (c *stringArrayDescendingCursor) Next() *tsdb.StringArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -1237,6 +1723,8 @@ func (c *stringArrayDescendingCursor) Next() *tsdb.StringArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += cpos - c.cache.pos
+
 	return c.res
 }
 
@@ -1249,6 +1737,9 @@ func (c *stringArrayDescendingCursor) nextTSM() *tsdb.StringArray {
 
 func (c *stringArrayDescendingCursor) readArrayBlock() *tsdb.StringArray {
 	values, _ := c.tsm.keyCursor.ReadStringArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 
 	c.stats.ScannedValues += len(values.Values)
 
@@ -1259,6 +1750,157 @@ func (c *stringArrayDescendingCursor) readArrayBlock() *tsdb.StringArray {
 	return values
 }
 
+// stringArrayPointCursor reads only the first point of a series in the direction
+// of the cursor, which is the point of a first or a last aggregate.
+//
+// The index entries of the blocks of the series bound the times of their
+// points, so the point is read from the cache without decoding any block
+// when it is before all of the blocks, and otherwise only the blocks which
+// hold the point are decoded.
+type stringArrayPointCursor struct {
+	cache     Values
+	keyCursor *KeyCursor
+	buf       *tsdb.StringArray
+
+	seek, end int64
+	ascending bool
+	done      bool
+
+	res   *tsdb.StringArray
+	stats cursors.CursorStats
+}
+
+func newStringArrayPointCursor() *stringArrayPointCursor {
+	return &stringArrayPointCursor{
+		buf: tsdb.NewStringArrayLen(MaxPointsPerBlock),
+		res: tsdb.NewStringArrayLen(1),
+	}
+}
+
+func (c *stringArrayPointCursor) reset(seek, end int64, ascending bool, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.cache = cacheValues
+	c.keyCursor = tsmKeyCursor
+	c.seek, c.end = seek, end
+	c.ascending = ascending
+	c.done = false
+}
+
+func (c *stringArrayPointCursor) Err() error { return nil }
+
+func (c *stringArrayPointCursor) Close() {
+	if c.keyCursor != nil {
+		c.keyCursor.Close()
+		c.keyCursor = nil
+	}
+	c.cache = nil
+}
+
+func (c *stringArrayPointCursor) Stats() cursors.CursorStats { return c.stats }
+
+func (c *stringArrayPointCursor) Next() *tsdb.StringArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+	if c.done {
+		return c.res
+	}
+	c.done = true
+
+	// The point of the cache, which is replaced by a point of the
+	// blocks only when the point of the blocks is before it.
+	ci := c.cachePoint()
+	if ci >= 0 {
+		c.res.Timestamps = append(c.res.Timestamps, c.cache[ci].UnixNano())
+		c.res.Values = append(c.res.Values, c.cache[ci].(StringValue).RawValue())
+	}
+
+	bound, ok := c.keyCursor.peekTime(c.seek)
+	if !ok || !c.inRange(bound) || (ci >= 0 && !c.before(bound, c.res.Timestamps[0])) {
+		// The blocks have no point in the range or before the point of the cache.
+		return c.cacheResult()
+	}
+
+	for {
+		values, _ := c.keyCursor.ReadStringArrayBlock(c.buf)
+		if values.Len() == 0 {
+			break
+		}
+		c.stats.DecodedBlocks++
+		c.stats.ScannedValues += len(values.Values)
+
+		for _, v := range values.Values {
+			c.stats.ScannedBytes += len(v)
+		}
+
+		// The values of a block are in ascending order.
+		var j int
+		if c.ascending {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] >= c.seek
+			})
+		} else {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] > c.seek
+			}) - 1
+		}
+		if j < 0 || j >= values.Len() {
+			// The points of the block are all before the seek time.
+			c.keyCursor.Next()
+			continue
+		}
+
+		if t := values.Timestamps[j]; c.inRange(t) && (ci < 0 || c.before(t, c.res.Timestamps[0])) {
+			c.res.Timestamps = append(c.res.Timestamps[:0], t)
+			c.res.Values = append(c.res.Values[:0], values.Values[j])
+			return c.res
+		}
+		break
+	}
+	return c.cacheResult()
+}
+
+// cacheResult returns the point of the cache, if any, as the result.
+func (c *stringArrayPointCursor) cacheResult() *tsdb.StringArray {
+	if c.res.Len() > 0 {
+		c.stats.CacheValues++
+		c.stats.ScannedValues++
+
+		c.stats.ScannedBytes += len(c.res.Values[0])
+
+	}
+	return c.res
+}
+
+// cachePoint returns the index of the first point of the cache from the seek
+// time in the direction of the cursor, or -1 if the cache has no point in the range.
+func (c *stringArrayPointCursor) cachePoint() int {
+	i := sort.Search(len(c.cache), func(i int) bool {
+		return c.cache[i].UnixNano() >= c.seek
+	})
+	if !c.ascending && (i == len(c.cache) || c.cache[i].UnixNano() != c.seek) {
+		i--
+	}
+	if i < 0 || i >= len(c.cache) || !c.inRange(c.cache[i].UnixNano()) {
+		return -1
+	}
+	return i
+}
+
+// inRange reports whether t is before the end of the cursor.
+func (c *stringArrayPointCursor) inRange(t int64) bool {
+	if c.ascending {
+		return t < c.end
+	}
+	return t > c.end
+}
+
+// before reports whether t is strictly before u in the direction of the cursor.
+func (c *stringArrayPointCursor) before(t, u int64) bool {
+	if c.ascending {
+		return t < u
+	}
+	return t > u
+}
+
 type booleanArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -1316,6 +1958,7 @@ func (c *booleanArrayAscendingCursor) Stats() cursors.CursorStats { return c.sta
 // Next returns the next key/value for the cursor.
 func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -1390,6 +2033,7 @@ func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += c.cache.pos - cpos
 	c.stats.ScannedValues += len(c.res.Values)
 
 	c.stats.ScannedBytes += len(c.res.Values) * 1
@@ -1406,6 +2050,9 @@ func (c *booleanArrayAscendingCursor) nextTSM() *tsdb.BooleanArray {
 
 func (c *booleanArrayAscendingCursor) readArrayBlock() *tsdb.BooleanArray {
 	values, _ := c.tsm.keyCursor.ReadBooleanArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 	return values
 }
 
@@ -1482,6 +2129,7 @@ func (c *booleanArrayDescendingCursor) Stats() cursors.CursorStats { return c.st
 
 func (c *booleanArrayDescendingCursor) Next() *tsdb.BooleanArray {
 	pos := 0
+	cpos := c.cache.pos
 	cvals := c.cache.values
 	tvals := c.tsm.values
 
@@ -1549,6 +2197,8 @@ func (c *booleanArrayDescendingCursor) Next() *tsdb.BooleanArray {
 	c.res.Timestamps = c.res.Timestamps[:pos]
 	c.res.Values = c.res.Values[:pos]
 
+	c.stats.CacheValues += cpos - c.cache.pos
+
 	return c.res
 }
 
@@ -1561,6 +2211,9 @@ func (c *booleanArrayDescendingCursor) nextTSM() *tsdb.BooleanArray {
 
 func (c *booleanArrayDescendingCursor) readArrayBlock() *tsdb.BooleanArray {
 	values, _ := c.tsm.keyCursor.ReadBooleanArrayBlock(c.tsm.buf)
+	if values.Len() > 0 {
+		c.stats.DecodedBlocks++
+	}
 
 	c.stats.ScannedValues += len(values.Values)
 
@@ -1568,3 +2221,152 @@ func (c *booleanArrayDescendingCursor) readArrayBlock() *tsdb.BooleanArray {
 
 	return values
 }
+
+// booleanArrayPointCursor reads only the first point of a series in the direction
+// of the cursor, which is the point of a first or a last aggregate.
+//
+// The index entries of the blocks of the series bound the times of their
+// points, so the point is read from the cache without decoding any block
+// when it is before all of the blocks, and otherwise only the blocks which
+// hold the point are decoded.
+type booleanArrayPointCursor struct {
+	cache     Values
+	keyCursor *KeyCursor
+	buf       *tsdb.BooleanArray
+
+	seek, end int64
+	ascending bool
+	done      bool
+
+	res   *tsdb.BooleanArray
+	stats cursors.CursorStats
+}
+
+func newBooleanArrayPointCursor() *booleanArrayPointCursor {
+	return &booleanArrayPointCursor{
+		buf: tsdb.NewBooleanArrayLen(MaxPointsPerBlock),
+		res: tsdb.NewBooleanArrayLen(1),
+	}
+}
+
+func (c *booleanArrayPointCursor) reset(seek, end int64, ascending bool, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.cache = cacheValues
+	c.keyCursor = tsmKeyCursor
+	c.seek, c.end = seek, end
+	c.ascending = ascending
+	c.done = false
+}
+
+func (c *booleanArrayPointCursor) Err() error { return nil }
+
+func (c *booleanArrayPointCursor) Close() {
+	if c.keyCursor != nil {
+		c.keyCursor.Close()
+		c.keyCursor = nil
+	}
+	c.cache = nil
+}
+
+func (c *booleanArrayPointCursor) Stats() cursors.CursorStats { return c.stats }
+
+func (c *booleanArrayPointCursor) Next() *tsdb.BooleanArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+	if c.done {
+		return c.res
+	}
+	c.done = true
+
+	// The point of the cache, which is replaced by a point of the
+	// blocks only when the point of the blocks is before it.
+	ci := c.cachePoint()
+	if ci >= 0 {
+		c.res.Timestamps = append(c.res.Timestamps, c.cache[ci].UnixNano())
+		c.res.Values = append(c.res.Values, c.cache[ci].(BooleanValue).RawValue())
+	}
+
+	bound, ok := c.keyCursor.peekTime(c.seek)
+	if !ok || !c.inRange(bound) || (ci >= 0 && !c.before(bound, c.res.Timestamps[0])) {
+		// The blocks have no point in the range or before the point of the cache.
+		return c.cacheResult()
+	}
+
+	for {
+		values, _ := c.keyCursor.ReadBooleanArrayBlock(c.buf)
+		if values.Len() == 0 {
+			break
+		}
+		c.stats.DecodedBlocks++
+		c.stats.ScannedValues += len(values.Values)
+
+		c.stats.ScannedBytes += len(values.Values) * 1
+
+		// The values of a block are in ascending order.
+		var j int
+		if c.ascending {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] >= c.seek
+			})
+		} else {
+			j = sort.Search(values.Len(), func(i int) bool {
+				return values.Timestamps[i] > c.seek
+			}) - 1
+		}
+		if j < 0 || j >= values.Len() {
+			// The points of the block are all before the seek time.
+			c.keyCursor.Next()
+			continue
+		}
+
+		if t := values.Timestamps[j]; c.inRange(t) && (ci < 0 || c.before(t, c.res.Timestamps[0])) {
+			c.res.Timestamps = append(c.res.Timestamps[:0], t)
+			c.res.Values = append(c.res.Values[:0], values.Values[j])
+			return c.res
+		}
+		break
+	}
+	return c.cacheResult()
+}
+
+// cacheResult returns the point of the cache, if any, as the result.
+func (c *booleanArrayPointCursor) cacheResult() *tsdb.BooleanArray {
+	if c.res.Len() > 0 {
+		c.stats.CacheValues++
+		c.stats.ScannedValues++
+
+		c.stats.ScannedBytes += 1
+
+	}
+	return c.res
+}
+
+// cachePoint returns the index of the first point of the cache from the seek
+// time in the direction of the cursor, or -1 if the cache has no point in the range.
+func (c *booleanArrayPointCursor) cachePoint() int {
+	i := sort.Search(len(c.cache), func(i int) bool {
+		return c.cache[i].UnixNano() >= c.seek
+	})
+	if !c.ascending && (i == len(c.cache) || c.cache[i].UnixNano() != c.seek) {
+		i--
+	}
+	if i < 0 || i >= len(c.cache) || !c.inRange(c.cache[i].UnixNano()) {
+		return -1
+	}
+	return i
+}
+
+// inRange reports whether t is before the end of the cursor.
+func (c *booleanArrayPointCursor) inRange(t int64) bool {
+	if c.ascending {
+		return t < c.end
+	}
+	return t > c.end
+}
+
+// before reports whether t is strictly before u in the direction of the cursor.
+func (c *booleanArrayPointCursor) before(t, u int64) bool {
+	if c.ascending {
+		return t < u
+	}
+	return t > u
+}
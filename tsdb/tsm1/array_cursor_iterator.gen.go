@@ -22,6 +22,14 @@ func (q *arrayCursorIterator) buildFloatArrayCursor(ctx context.Context, name []
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
+	if opt.Limit == 1 {
+		if q.point.Float == nil {
+			q.point.Float = newFloatArrayPointCursor()
+		}
+		q.point.Float.reset(opt.SeekTime(), opt.StopTime(), opt.Ascending, cacheValues, keyCursor)
+		return q.point.Float
+	}
+
 	if opt.Ascending {
 		if q.asc.Float == nil {
 			q.asc.Float = newFloatArrayAscendingCursor()
@@ -45,6 +53,14 @@ func (q *arrayCursorIterator) buildIntegerArrayCursor(ctx context.Context, name
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
+	if opt.Limit == 1 {
+		if q.point.Integer == nil {
+			q.point.Integer = newIntegerArrayPointCursor()
+		}
+		q.point.Integer.reset(opt.SeekTime(), opt.StopTime(), opt.Ascending, cacheValues, keyCursor)
+		return q.point.Integer
+	}
+
 	if opt.Ascending {
 		if q.asc.Integer == nil {
 			q.asc.Integer = newIntegerArrayAscendingCursor()
@@ -68,6 +84,14 @@ func (q *arrayCursorIterator) buildUnsignedArrayCursor(ctx context.Context, name
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
+	if opt.Limit == 1 {
+		if q.point.Unsigned == nil {
+			q.point.Unsigned = newUnsignedArrayPointCursor()
+		}
+		q.point.Unsigned.reset(opt.SeekTime(), opt.StopTime(), opt.Ascending, cacheValues, keyCursor)
+		return q.point.Unsigned
+	}
+
 	if opt.Ascending {
 		if q.asc.Unsigned == nil {
 			q.asc.Unsigned = newUnsignedArrayAscendingCursor()
@@ -91,6 +115,14 @@ func (q *arrayCursorIterator) buildStringArrayCursor(ctx context.Context, name [
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
+	if opt.Limit == 1 {
+		if q.point.String == nil {
+			q.point.String = newStringArrayPointCursor()
+		}
+		q.point.String.reset(opt.SeekTime(), opt.StopTime(), opt.Ascending, cacheValues, keyCursor)
+		return q.point.String
+	}
+
 	if opt.Ascending {
 		if q.asc.String == nil {
 			q.asc.String = newStringArrayAscendingCursor()
@@ -114,6 +146,14 @@ func (q *arrayCursorIterator) buildBooleanArrayCursor(ctx context.Context, name
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
+	if opt.Limit == 1 {
+		if q.point.Boolean == nil {
+			q.point.Boolean = newBooleanArrayPointCursor()
+		}
+		q.point.Boolean.reset(opt.SeekTime(), opt.StopTime(), opt.Ascending, cacheValues, keyCursor)
+		return q.point.Boolean
+	}
+
 	if opt.Ascending {
 		if q.asc.Boolean == nil {
 			q.asc.Boolean = newBooleanArrayAscendingCursor()
@@ -30,6 +30,15 @@ type arrayCursorIterator struct {
 		Boolean  *booleanArrayDescendingCursor
 		String   *stringArrayDescendingCursor
 	}
+
+	// point holds the cursors of the requests limited to one point.
+	point struct {
+		Float    *floatArrayPointCursor
+		Integer  *integerArrayPointCursor
+		Unsigned *unsignedArrayPointCursor
+		Boolean  *booleanArrayPointCursor
+		String   *stringArrayPointCursor
+	}
 }
 
 func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (tsdb.Cursor, error) {
@@ -49,6 +58,7 @@ func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (
 	opt.Ascending = r.Ascending
 	opt.StartTime = r.StartTime
 	opt.EndTime = r.EndTime
+	opt.Limit = int(r.Limit)
 
 	// Return appropriate cursor based on type.
 	switch typ := id.Type(); typ {
@@ -107,5 +117,20 @@ func (q *arrayCursorIterator) Stats() cursors.CursorStats {
 	if cur := q.desc.String; cur != nil {
 		stats.Add(cur.Stats())
 	}
+	if cur := q.point.Float; cur != nil {
+		stats.Add(cur.Stats())
+	}
+	if cur := q.point.Integer; cur != nil {
+		stats.Add(cur.Stats())
+	}
+	if cur := q.point.Unsigned; cur != nil {
+		stats.Add(cur.Stats())
+	}
+	if cur := q.point.Boolean; cur != nil {
+		stats.Add(cur.Stats())
+	}
+	if cur := q.point.String; cur != nil {
+		stats.Add(cur.Stats())
+	}
 	return stats
 }
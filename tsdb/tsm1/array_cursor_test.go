@@ -350,3 +350,81 @@ func TestFileStore_MergeBlocksLargerThat1000_MultipleBlocksInEachFile(t *testing
 		assert.Equal(t, got.Values, exp.Values)
 	})
 }
+
+func TestFileStore_PointCursor(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+
+	makeVals := func(ts ...int64) []Value {
+		vals := make([]Value, len(ts))
+		for i, t := range ts {
+			vals[i] = NewFloatValue(t, float64(t))
+		}
+		return vals
+	}
+
+	// Setup 3 files with a block each
+	data := []keyValues{
+		{"m,_field=v#!~#v", makeVals(10, 20)},
+		{"m,_field=v#!~#v", makeVals(30, 40)},
+		{"m,_field=v#!~#v", makeVals(50, 60)},
+	}
+
+	files, err := newFiles(dir, data...)
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+
+	_ = fs.Replace(nil, files)
+
+	type point struct {
+		T int64
+		V float64
+	}
+
+	tests := []struct {
+		name      string
+		seek, end int64
+		ascending bool
+		cache     []Value
+		exp       []point
+		decoded   int
+	}{
+		{name: "first", seek: 0, end: 100, ascending: true, exp: []point{{10, 10}}, decoded: 1},
+		{name: "first from seek", seek: 25, end: 100, ascending: true, exp: []point{{30, 30}}, decoded: 1},
+		{name: "first before end", seek: 0, end: 10, ascending: true},
+		{name: "first after blocks", seek: 61, end: 100, ascending: true},
+		{name: "first in cache", seek: 0, end: 100, ascending: true, cache: []Value{NewFloatValue(5, -1)}, exp: []point{{5, -1}}},
+		{name: "first cache overwrites", seek: 0, end: 100, ascending: true, cache: []Value{NewFloatValue(10, -1)}, exp: []point{{10, -1}}},
+		{name: "first in block", seek: 0, end: 100, ascending: true, cache: []Value{NewFloatValue(15, -1)}, exp: []point{{10, 10}}, decoded: 1},
+		{name: "last", seek: 100, end: 0, exp: []point{{60, 60}}, decoded: 1},
+		{name: "last from seek", seek: 45, end: 0, exp: []point{{40, 40}}, decoded: 1},
+		{name: "last in cache", seek: 100, end: 0, cache: []Value{NewFloatValue(70, -1)}, exp: []point{{70, -1}}},
+		{name: "last in block", seek: 100, end: 0, cache: []Value{NewFloatValue(55, -1)}, exp: []point{{60, 60}}, decoded: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := fs.KeyCursor(context.Background(), []byte("m,_field=v#!~#v"), tt.seek, tt.ascending)
+			cur := newFloatArrayPointCursor()
+			cur.reset(tt.seek, tt.end, tt.ascending, tt.cache, kc)
+			defer cur.Close()
+
+			var got []point
+			ar := cur.Next()
+			for ar.Len() > 0 {
+				for i := range ar.Timestamps {
+					got = append(got, point{T: ar.Timestamps[i], V: ar.Values[i]})
+				}
+				ar = cur.Next()
+			}
+
+			if !cmp.Equal(got, tt.exp) {
+				t.Errorf("unexpected points; -got/+exp\n%s", cmp.Diff(got, tt.exp))
+			}
+			if got := cur.Stats().DecodedBlocks; got != tt.decoded {
+				t.Errorf("unexpected decoded blocks: got %d, exp %d", got, tt.decoded)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxql"
@@ -127,7 +128,11 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 	store := c.store
 	c.mu.RUnlock()
 
-	var bytesWrittenErr uint64
+	var (
+		bytesWrittenErr uint64
+		failed          int
+		conflicts       [][]byte
+	)
 
 	// We'll optimistically set size here, and then decrement it for write errors.
 	for k, v := range values {
@@ -137,6 +142,12 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 			werr = err
 			addedSize -= uint64(Values(v).Size())
 			bytesWrittenErr += uint64(Values(v).Size())
+			failed++
+
+			if err == tsdb.ErrFieldTypeConflict {
+				seriesKey, _ := SeriesAndFieldFromCompositeKey([]byte(k))
+				conflicts = append(conflicts, seriesKey)
+			}
 		}
 
 		if newKey {
@@ -152,6 +163,11 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 		c.tracker.AddWrittenBytesErr(bytesWrittenErr)
 	}
 
+	// Report which series were dropped when the only failures were type conflicts.
+	if werr != nil && len(conflicts) == failed {
+		werr = &tsdb.FieldTypeConflictError{SeriesKeys: bytesutil.SortDedup(conflicts)}
+	}
+
 	// Update the memory size stat
 	c.tracker.IncCacheSize(addedSize)
 	c.tracker.AddMemBytes(addedSize)
@@ -111,7 +111,7 @@ func TestEngine_CursorIterator_Stats(t *testing.T) {
 	}
 
 	// iterator should report integer array stats
-	if got, exp := cursorIterator.Stats(), (cursors.CursorStats{ScannedValues: 3, ScannedBytes: 24}); exp != got {
+	if got, exp := cursorIterator.Stats(), (cursors.CursorStats{ScannedValues: 3, ScannedBytes: 24, DecodedBlocks: 2}); exp != got {
 		t.Fatalf("expected %v, got %v", exp, got)
 	}
 }
@@ -1465,6 +1465,39 @@ func (c *KeyCursor) seekDescending(t int64) {
 	}
 }
 
+// peekTime returns a bound of the times of the points of the blocks of the
+// cursor from their index entries: the earliest time from the seek time t when
+// ascending, and the latest time until t when descending. Points of the blocks
+// may have been deleted, so no point may be at the bound. It returns false if
+// the cursor has no blocks.
+func (c *KeyCursor) peekTime(t int64) (int64, bool) {
+	if len(c.current) == 0 {
+		return 0, false
+	}
+
+	var bound int64
+	for i, l := range c.current {
+		if c.ascending {
+			min := l.entry.MinTime
+			if min < t {
+				min = t
+			}
+			if i == 0 || min < bound {
+				bound = min
+			}
+		} else {
+			max := l.entry.MaxTime
+			if max > t {
+				max = t
+			}
+			if i == 0 || max > bound {
+				bound = max
+			}
+		}
+	}
+	return bound, true
+}
+
 // seekN returns the number of seek locations.
 func (c *KeyCursor) seekN() int {
 	return len(c.seeks)
@@ -0,0 +1,166 @@
+package write
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	csvTimeColumn        = "_time"
+	csvStartColumn       = "_start"
+	csvStopColumn        = "_stop"
+	csvMeasurementColumn = "_measurement"
+	csvFieldColumn       = "_field"
+	csvValueColumn       = "_value"
+)
+
+// DecodeCSV decodes annotated CSV, the format returned by Flux queries, into
+// points named name. Every table requires the #datatype, #group and #default
+// annotations, a _time column and a _measurement column.
+//
+// String columns that are part of the group key become tags. When the table
+// has _field and _value columns each row writes _value to the field named by
+// _field. Every other column outside of the group key, except _start and _stop,
+// is written as a field of the same name and type, which allows wide tables.
+// Null values are not written.
+func DecodeCSV(r io.Reader, name string) ([]models.Point, error) {
+	results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(ioutil.NopCloser(r))
+	if err != nil {
+		return nil, err
+	}
+	defer results.Release()
+
+	var points []models.Point
+	for results.More() {
+		err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			var err error
+			points, err = appendTablePoints(points, name, tbl)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func appendTablePoints(points []models.Point, name string, tbl flux.Table) ([]models.Point, error) {
+	cols := tbl.Cols()
+	timeIdx := execute.ColIdx(csvTimeColumn, cols)
+	if timeIdx < 0 || cols[timeIdx].Type != flux.TTime {
+		return nil, fmt.Errorf("table requires a %s column of type dateTime", csvTimeColumn)
+	}
+	measurementIdx := execute.ColIdx(csvMeasurementColumn, cols)
+	if measurementIdx < 0 || cols[measurementIdx].Type != flux.TString {
+		return nil, fmt.Errorf("table requires a %s column of type string", csvMeasurementColumn)
+	}
+	fieldIdx := execute.ColIdx(csvFieldColumn, cols)
+	valueIdx := execute.ColIdx(csvValueColumn, cols)
+	if (fieldIdx < 0) != (valueIdx < 0) {
+		return nil, fmt.Errorf("table requires both a %s and a %s column or neither", csvFieldColumn, csvValueColumn)
+	}
+	if fieldIdx >= 0 && cols[fieldIdx].Type != flux.TString {
+		return nil, fmt.Errorf("column %s must be of type string", csvFieldColumn)
+	}
+	if valueIdx >= 0 && cols[valueIdx].Type == flux.TTime {
+		return nil, fmt.Errorf("column %s of type dateTime cannot be written as a field", csvValueColumn)
+	}
+
+	key := tbl.Key()
+	isTag := make([]bool, len(cols))
+	isField := make([]bool, len(cols))
+	for j, col := range cols {
+		switch col.Label {
+		case csvTimeColumn, csvStartColumn, csvStopColumn, csvMeasurementColumn, csvFieldColumn, csvValueColumn:
+			continue
+		}
+		if key.HasCol(col.Label) {
+			if col.Type != flux.TString {
+				return nil, fmt.Errorf("group key column %s must be of type string to be written as a tag", col.Label)
+			}
+			if isReservedTagKey(col.Label) {
+				return nil, fmt.Errorf("cannot use reserved tag key %q", col.Label)
+			}
+			isTag[j] = true
+			continue
+		}
+		if col.Type == flux.TTime {
+			return nil, fmt.Errorf("column %s of type dateTime cannot be written as a field", col.Label)
+		}
+		isField[j] = true
+	}
+
+	err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			t := execute.ValueForRow(cr, i, timeIdx)
+			if t.IsNull() {
+				return errors.New("missing value in the _time column")
+			}
+			m := execute.ValueForRow(cr, i, measurementIdx)
+			if m.IsNull() || m.Str() == "" {
+				return errors.New("missing value in the _measurement column")
+			}
+
+			tags := make(map[string]string)
+			fields := make(models.Fields)
+			for j, col := range cr.Cols() {
+				v := execute.ValueForRow(cr, i, j)
+				if v.IsNull() {
+					continue
+				}
+				switch {
+				case isTag[j]:
+					if s := v.Str(); s != "" {
+						tags[col.Label] = s
+					}
+				case isField[j]:
+					fields[col.Label] = fieldValue(col.Type, v)
+				}
+			}
+			if fieldIdx >= 0 {
+				f, v := execute.ValueForRow(cr, i, fieldIdx), execute.ValueForRow(cr, i, valueIdx)
+				if !f.IsNull() && !v.IsNull() {
+					fields[f.Str()] = fieldValue(cols[valueIdx].Type, v)
+				}
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			var err error
+			points, err = appendPoints(points, name, m.Str(), tags, fields, t.Time().Time())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return points, err
+}
+
+// fieldValue returns the Go value of a non-null column value for a field.
+func fieldValue(typ flux.ColType, v values.Value) interface{} {
+	switch typ {
+	case flux.TFloat:
+		return v.Float()
+	case flux.TInt:
+		return v.Int()
+	case flux.TUInt:
+		return v.UInt()
+	case flux.TBool:
+		return v.Bool()
+	default:
+		return v.Str()
+	}
+}
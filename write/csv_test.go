@@ -0,0 +1,93 @@
+package write
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []testPoint
+		wantErr bool
+	}{
+		{
+			name: "flux query result",
+			input: `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,string,string,string,double
+#group,false,false,true,true,false,true,true,true,false
+#default,_result,,,,,,,,
+,result,table,_start,_stop,_time,_measurement,_field,host,_value
+,,0,2019-12-31T00:00:00Z,2020-01-02T00:00:00Z,2020-01-01T00:00:00Z,cpu,usage,a,0.5
+,,0,2019-12-31T00:00:00Z,2020-01-02T00:00:00Z,2020-01-01T00:00:10Z,cpu,usage,a,0.75
+`,
+			want: []testPoint{
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "usage", Value: 0.5, Time: ts},
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "usage", Value: 0.75, Time: ts.Add(10 * time.Second)},
+			},
+		},
+		{
+			name: "wide table with defaults",
+			input: `#datatype,string,long,dateTime:RFC3339,string,string,long,boolean,string
+#group,false,false,false,true,true,false,false,false
+#default,,,,mem,b,,,
+,result,table,_time,_measurement,host,used,ok,state
+,,0,2020-01-01T00:00:00Z,,,10,true,running
+`,
+			want: []testPoint{
+				{Measurement: "mem", Tags: map[string]string{"host": "b"}, Field: "ok", Value: true, Time: ts},
+				{Measurement: "mem", Tags: map[string]string{"host": "b"}, Field: "state", Value: "running", Time: ts},
+				{Measurement: "mem", Tags: map[string]string{"host": "b"}, Field: "used", Value: int64(10), Time: ts},
+			},
+		},
+		{
+			name: "null values are not written",
+			input: `#datatype,string,long,dateTime:RFC3339,string,unsignedLong,unsignedLong
+#group,false,false,false,true,false,false
+#default,,,,,,
+,result,table,_time,_measurement,a,b
+,,0,2020-01-01T00:00:00Z,disk,,3
+`,
+			want: []testPoint{
+				{Measurement: "disk", Tags: map[string]string{}, Field: "b", Value: uint64(3), Time: ts},
+			},
+		},
+		{
+			name: "missing measurement column",
+			input: `#datatype,string,long,dateTime:RFC3339,double
+#group,false,false,false,false
+#default,,,,
+,result,table,_time,_value
+,,0,2020-01-01T00:00:00Z,1
+`,
+			wantErr: true,
+		},
+		{
+			name: "missing annotations",
+			input: `,result,table,_time,_measurement,_field,_value
+,,0,2020-01-01T00:00:00Z,cpu,usage,1
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := DecodeCSV(strings.NewReader(tt.input), "bucket")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, toTestPoints(t, points)); diff != "" {
+				t.Errorf("unexpected points -want/+got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package write
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Format is the encoding of the points in a write request.
+type Format string
+
+const (
+	// FormatLineProtocol is the line protocol format. It is the default
+	// format of a write request.
+	FormatLineProtocol Format = "lp"
+	// FormatCSV is the annotated CSV format returned by Flux queries.
+	FormatCSV Format = "csv"
+	// FormatJSON is the JSON points format. See DecodeJSON for its layout.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatLineProtocol, FormatCSV, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q; supported formats are lp, csv and json", s)
+}
+
+// ContentType returns the media type of a request body in format f.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// FormatFromContentType returns the format of a request body with the given
+// Content-Type. Any content type other than CSV or JSON is line protocol,
+// which keeps existing clients that send arbitrary content types working.
+func FormatFromContentType(contentType string) Format {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return FormatLineProtocol
+	}
+
+	switch mt {
+	case "text/csv", "application/csv":
+		return FormatCSV
+	case "application/json":
+		return FormatJSON
+	default:
+		return FormatLineProtocol
+	}
+}
+
+// appendPoints appends a point per field to points, tagged with the measurement
+// and field keys in the same way the line protocol parser explodes points for
+// the storage engine. The name is the encoded organization and bucket ID.
+func appendPoints(points []models.Point, name, measurement string, tags map[string]string, fields models.Fields, t time.Time) ([]models.Point, error) {
+	kv := make([][]byte, 0, 2*len(tags)+4)
+	kv = append(kv, models.MeasurementTagKeyBytes, []byte(measurement))
+	for k, v := range tags {
+		kv = append(kv, []byte(k), []byte(v))
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		pointTags, err := models.NewTagsKeyValues(nil, append(kv, models.FieldKeyTagKeyBytes, []byte(k))...)
+		if err != nil {
+			return nil, err
+		}
+
+		pt, err := models.NewPoint(name, pointTags, models.Fields{k: fields[k]}, t)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
@@ -0,0 +1,63 @@
+package write
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// testPoint is an exploded point in a form that is easy to compare.
+type testPoint struct {
+	Measurement string
+	Tags        map[string]string
+	Field       string
+	Value       interface{}
+	Time        time.Time
+}
+
+func toTestPoints(t *testing.T, points []models.Point) []testPoint {
+	t.Helper()
+
+	out := make([]testPoint, 0, len(points))
+	for _, p := range points {
+		tp := testPoint{Tags: make(map[string]string), Time: p.Time().UTC()}
+		for _, tag := range p.Tags() {
+			switch string(tag.Key) {
+			case models.MeasurementTagKey:
+				tp.Measurement = string(tag.Value)
+			case models.FieldKeyTagKey:
+				tp.Field = string(tag.Value)
+			default:
+				tp.Tags[string(tag.Key)] = string(tag.Value)
+			}
+		}
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tp.Value = fields[tp.Field]
+		out = append(out, tp)
+	}
+	return out
+}
+
+func TestFormatFromContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Format
+	}{
+		{contentType: "", want: FormatLineProtocol},
+		{contentType: "text/plain; charset=utf-8", want: FormatLineProtocol},
+		{contentType: "application/octet-stream", want: FormatLineProtocol},
+		{contentType: "text/csv", want: FormatCSV},
+		{contentType: "application/csv; charset=utf-8", want: FormatCSV},
+		{contentType: "application/json", want: FormatJSON},
+		{contentType: FormatJSON.ContentType(), want: FormatJSON},
+	}
+	for _, tt := range tests {
+		if got := FormatFromContentType(tt.contentType); got != tt.want {
+			t.Errorf("FormatFromContentType(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
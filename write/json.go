@@ -0,0 +1,177 @@
+package write
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// jsonPoint is a single point of the JSON points format.
+type jsonPoint struct {
+	Measurement string                     `json:"measurement"`
+	Tags        map[string]string          `json:"tags"`
+	Fields      map[string]json.RawMessage `json:"fields"`
+	Time        json.RawMessage            `json:"time"`
+}
+
+// jsonTypedValue is a field value with an explicit data type.
+type jsonTypedValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// DecodeJSON decodes the JSON points format into points named name. The body
+// is an array of points:
+//
+//	[
+//	  {
+//	    "measurement": "cpu",
+//	    "tags": {"host": "server01"},
+//	    "fields": {"usage": 0.64, "cores": {"type": "integer", "value": 4}},
+//	    "time": 1590000000000000000
+//	  }
+//	]
+//
+// A time is either an integer in the given precision or an RFC3339 string.
+// Points without a time are written at the current server time. As with line
+// protocol, numbers are floats, strings are strings and booleans are booleans.
+// A field written as an object declares its type explicitly, one of float,
+// integer, unsigned, string or boolean.
+func DecodeJSON(r io.Reader, name, precision string) ([]models.Point, error) {
+	var jps []jsonPoint
+	if err := json.NewDecoder(r).Decode(&jps); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON points: %v", err)
+	}
+
+	now := time.Now().UTC()
+	var points []models.Point
+	for i, jp := range jps {
+		if jp.Measurement == "" {
+			return nil, fmt.Errorf("point %d: missing measurement", i)
+		}
+		for k := range jp.Tags {
+			if isReservedTagKey(k) {
+				return nil, fmt.Errorf("point %d: cannot use reserved tag key %q", i, k)
+			}
+		}
+		if len(jp.Fields) == 0 {
+			return nil, fmt.Errorf("point %d: %v", i, models.ErrPointMustHaveAField)
+		}
+
+		fields := make(models.Fields, len(jp.Fields))
+		for k, raw := range jp.Fields {
+			v, err := decodeJSONFieldValue(raw)
+			if err != nil {
+				return nil, fmt.Errorf("point %d: field %q: %v", i, k, err)
+			}
+			fields[k] = v
+		}
+
+		t, err := decodeJSONTime(jp.Time, precision, now)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %v", i, err)
+		}
+
+		points, err = appendPoints(points, name, jp.Measurement, jp.Tags, fields, t)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %v", i, err)
+		}
+	}
+	return points, nil
+}
+
+func decodeJSONFieldValue(raw json.RawMessage) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '{' {
+		var tv jsonTypedValue
+		if err := json.Unmarshal(raw, &tv); err != nil {
+			return nil, err
+		}
+		return decodeJSONTypedValue(tv)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	switch v.(type) {
+	case float64, string, bool:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %s", raw)
+	}
+}
+
+func decodeJSONTypedValue(tv jsonTypedValue) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+	switch tv.Type {
+	case "float":
+		var f float64
+		err = json.Unmarshal(tv.Value, &f)
+		v = f
+	case "integer":
+		var i int64
+		err = json.Unmarshal(tv.Value, &i)
+		v = i
+	case "unsigned":
+		var u uint64
+		err = json.Unmarshal(tv.Value, &u)
+		v = u
+	case "string":
+		var s string
+		err = json.Unmarshal(tv.Value, &s)
+		v = s
+	case "boolean":
+		var b bool
+		err = json.Unmarshal(tv.Value, &b)
+		v = b
+	default:
+		return nil, fmt.Errorf("unsupported type %q", tv.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %v", tv.Type, err)
+	}
+	return v, nil
+}
+
+func decodeJSONTime(raw json.RawMessage, precision string, now time.Time) (time.Time, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return now, nil
+	}
+
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return time.Time{}, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time: %v", err)
+		}
+		return t, nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %v", err)
+	}
+	return time.Unix(0, n*models.GetPrecisionMultiplier(precision)).UTC(), nil
+}
+
+// isReservedTagKey reports whether key may not be used as a tag key since it
+// is used by the storage engine or by Flux for the measurement, field or time.
+func isReservedTagKey(key string) bool {
+	switch key {
+	case models.MeasurementTagKey, models.FieldKeyTagKey, csvMeasurementColumn, csvFieldColumn, "time":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package write
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		input     string
+		precision string
+		want      []testPoint
+		wantErr   bool
+	}{
+		{
+			name:      "typed fields",
+			precision: "s",
+			input: `[{
+				"measurement": "cpu",
+				"tags": {"host": "a"},
+				"fields": {
+					"usage": 0.5,
+					"cores": {"type": "integer", "value": 4},
+					"ticks": {"type": "unsigned", "value": 7},
+					"ok": true,
+					"state": "running"
+				},
+				"time": 1577836800
+			}]`,
+			want: []testPoint{
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "cores", Value: int64(4), Time: ts},
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "ok", Value: true, Time: ts},
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "state", Value: "running", Time: ts},
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "ticks", Value: uint64(7), Time: ts},
+				{Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "usage", Value: 0.5, Time: ts},
+			},
+		},
+		{
+			name:  "RFC3339 time",
+			input: `[{"measurement": "mem", "fields": {"used": 1}, "time": "2020-01-01T00:00:00Z"}]`,
+			want: []testPoint{
+				{Measurement: "mem", Tags: map[string]string{}, Field: "used", Value: float64(1), Time: ts},
+			},
+		},
+		{
+			name:    "missing fields",
+			input:   `[{"measurement": "mem"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "reserved tag key",
+			input:   `[{"measurement": "mem", "tags": {"_field": "x"}, "fields": {"used": 1}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported field type",
+			input:   `[{"measurement": "mem", "fields": {"used": {"type": "duration", "value": 1}}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "not an array",
+			input:   `{"measurement": "mem", "fields": {"used": 1}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := DecodeJSON(strings.NewReader(tt.input), "bucket", tt.precision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, toTestPoints(t, points)); diff != "" {
+				t.Errorf("unexpected points -want/+got\n%s", diff)
+			}
+		})
+	}
+}